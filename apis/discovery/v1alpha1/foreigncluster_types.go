@@ -46,6 +46,9 @@ const (
 	PeeringConditionStatusError PeeringConditionStatusType = "Error"
 	// PeeringConditionStatusSuccess indicates that the condition is successful.
 	PeeringConditionStatusSuccess PeeringConditionStatusType = "Success"
+	// PeeringConditionStatusRevoked indicates that the identity issued to the remote cluster has been revoked.
+	// This is only used by the AuthenticationCondition Type.
+	PeeringConditionStatusRevoked PeeringConditionStatusType = "Revoked"
 )
 
 // PeeringEnabledType indicates the desired state for the peering with this remote cluster.
@@ -110,6 +113,10 @@ type ForeignClusterSpec struct {
 	// +kubebuilder:default=true
 	// +kubebuilder:validation:Optional
 	InsecureSkipTLSVerify *bool `json:"insecureSkipTLSVerify"`
+	// Revoke the identity issued to the remote cluster, removing its permissions and rejecting
+	// its identity requests, until this field is set back to false.
+	// +kubebuilder:validation:Optional
+	IdentityRevoked bool `json:"identityRevoked,omitempty"`
 	// If discoveryType is LAN or WAN and this indicates the number of seconds after that
 	// this ForeignCluster will be removed if no updates have been received.
 	// +kubebuilder:validation:Minimum=0
@@ -166,7 +173,7 @@ type PeeringCondition struct {
 	// +kubebuilder:validation:Enum="OutgoingPeering";"IncomingPeering";"NetworkStatus";"AuthenticationStatus";"ProcessForeignClusterStatus"
 	Type PeeringConditionType `json:"type"`
 	// Status of the condition.
-	// +kubebuilder:validation:Enum="None";"Pending";"Established";"Disconnecting";"Denied";"EmptyDenied";"Error";"Success"
+	// +kubebuilder:validation:Enum="None";"Pending";"Established";"Disconnecting";"Denied";"EmptyDenied";"Error";"Success";"Revoked"
 	// +kubebuilder:default="None"
 	Status PeeringConditionStatusType `json:"status"`
 	// LastTransitionTime -> timestamp for when the condition last transitioned from one status to another.
@@ -77,6 +77,15 @@ type IpamSpec struct {
 	PodCIDR string `json:"podCIDR"`
 	// ServiceCIDR
 	ServiceCIDR string `json:"serviceCIDR"`
+	// Cluster IPv6 PodCIDR, in case of dual-stack clusters.
+	PodCIDRv6 string `json:"podCIDRv6,omitempty"`
+	// IPv6 ServiceCIDR, in case of dual-stack clusters.
+	ServiceCIDRv6 string `json:"serviceCIDRv6,omitempty"`
+	// Cluster IPv6 ExternalCIDR, in case of dual-stack clusters.
+	ExternalCIDRv6 string `json:"externalCIDRv6,omitempty"`
+	// Map used to keep track of the IPv6 networks assigned to dual-stack clusters. Key is the remote cluster ID,
+	// value is a the set of IPv6 networks used by the remote cluster.
+	ClusterSubnetsV6 map[string]Subnets `json:"clusterSubnetsV6,omitempty"`
 }
 
 // +kubebuilder:object:root=true
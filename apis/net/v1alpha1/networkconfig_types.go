@@ -37,6 +37,10 @@ type NetworkConfigSpec struct {
 	PodCIDR string `json:"podCIDR"`
 	// Network used for local service endpoints.
 	ExternalCIDR string `json:"externalCIDR"`
+	// IPv6 network used in the local cluster for the pod IPs, in case of dual-stack clusters.
+	PodCIDRv6 string `json:"podCIDRv6,omitempty"`
+	// IPv6 network used for local service endpoints, in case of dual-stack clusters.
+	ExternalCIDRv6 string `json:"externalCIDRv6,omitempty"`
 	// Public IP of the node where the VPN tunnel is created.
 	EndpointIP string `json:"endpointIP"`
 	// Vpn technology used to interconnect two clusters.
@@ -59,6 +63,10 @@ type NetworkConfigStatus struct {
 	// The new subnet used to NAT the externalCIDR of the remote cluster. The original ExternalCIDR may have been mapped
 	// to this network by the remote cluster.
 	ExternalCIDRNAT string `json:"externalCIDRNAT,omitempty"`
+	// The new subnet used to NAT the IPv6 podCidr of the remote cluster, in case of dual-stack clusters.
+	PodCIDRNATv6 string `json:"podCIDRNATv6,omitempty"`
+	// The new subnet used to NAT the IPv6 externalCIDR of the remote cluster, in case of dual-stack clusters.
+	ExternalCIDRNATv6 string `json:"externalCIDRNATv6,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -52,6 +52,26 @@ type TunnelEndpointSpec struct {
 	// +kubebuilder:validation:Optional
 	RemoteNATExternalCIDR string `json:"remoteNATExternalCIDR"`
 
+	// IPv6 counterparts of the above networks, set only in case both clusters are dual-stack.
+	// The NAT fields follow the same semantic of the IPv4 ones, with "None" meaning no remapping.
+
+	// IPv6 PodCIDR of local cluster.
+	LocalPodCIDRv6 string `json:"localPodCIDRv6,omitempty"`
+	// IPv6 network used in the remote cluster to map the local PodCIDR.
+	LocalNATPodCIDRv6 string `json:"localNATPodCIDRv6,omitempty"`
+	// IPv6 ExternalCIDR of local cluster.
+	LocalExternalCIDRv6 string `json:"localExternalCIDRv6,omitempty"`
+	// IPv6 network used in the remote cluster to map the local ExternalCIDR.
+	LocalNATExternalCIDRv6 string `json:"localNATExternalCIDRv6,omitempty"`
+	// IPv6 PodCIDR of remote cluster.
+	RemotePodCIDRv6 string `json:"remotePodCIDRv6,omitempty"`
+	// IPv6 network used in the local cluster to map the remote cluster PodCIDR.
+	RemoteNATPodCIDRv6 string `json:"remoteNATPodCIDRv6,omitempty"`
+	// IPv6 ExternalCIDR of remote cluster.
+	RemoteExternalCIDRv6 string `json:"remoteExternalCIDRv6,omitempty"`
+	// IPv6 network used in the local cluster to map the remote cluster ExternalCIDR.
+	RemoteNATExternalCIDRv6 string `json:"remoteNATExternalCIDRv6,omitempty"`
+
 	// Public IP of the node where the VPN tunnel is created.
 	EndpointIP string `json:"endpointIP"`
 	// Vpn technology used to interconnect two clusters.
@@ -69,6 +89,25 @@ type TunnelEndpointStatus struct {
 	VethIP           string     `json:"vethIP,omitempty"`
 	GatewayIP        string     `json:"gatewayIP,omitempty"`
 	Connection       Connection `json:"connection,omitempty"`
+	// Health reports the outcome of the periodic probes performed across the tunnel towards the remote gateway.
+	Health *TunnelHealth `json:"health,omitempty"`
+}
+
+// TunnelHealth holds the data-plane statistics measured across a vpn tunnel connecting to a remote cluster.
+type TunnelHealth struct {
+	// RTT is the average round-trip time of the probes answered by the remote gateway.
+	RTT metav1.Duration `json:"rtt,omitempty"`
+	// Jitter is the average variation of the round-trip time between consecutive probes.
+	Jitter metav1.Duration `json:"jitter,omitempty"`
+	// PacketLoss is the percentage of probes not answered by the remote gateway.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	PacketLoss int `json:"packetLoss"`
+	// LastHandshakeAge is the time elapsed since the last handshake with the remote peer.
+	// It is not set if the tunnel backend does not perform handshakes.
+	LastHandshakeAge *metav1.Duration `json:"lastHandshakeAge,omitempty"`
+	// LastUpdateTime is the time the statistics have been last updated.
+	LastUpdateTime metav1.Time `json:"lastUpdateTime,omitempty"`
 }
 
 // Connection holds the configuration and status of a vpn tunnel connecting to remote cluster.
@@ -92,6 +131,8 @@ const (
 	ConnectingMessage string = "Waiting VPN connection to be established"
 	// ConnectionError used to se the status in case of errors.
 	ConnectionError ConnectionStatus = "Error"
+	// ConnectionDegraded used when the vpn tunnel is established, but the health probes exceed the configured thresholds.
+	ConnectionDegraded ConnectionStatus = "Degraded"
 )
 
 // +kubebuilder:object:root=true
@@ -103,6 +144,8 @@ const (
 // +kubebuilder:printcolumn:name="Endpoint IP",type=string,JSONPath=`.spec.endpointIP`,priority=1
 // +kubebuilder:printcolumn:name="Backend type",type=string,JSONPath=`.spec.backendType`
 // +kubebuilder:printcolumn:name="Connection status",type=string,JSONPath=`.status.connection.status`
+// +kubebuilder:printcolumn:name="RTT",type=string,JSONPath=`.status.health.rtt`,priority=1
+// +kubebuilder:printcolumn:name="Packet loss",type=integer,JSONPath=`.status.health.packetLoss`,priority=1
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 type TunnelEndpoint struct {
 	metav1.TypeMeta   `json:",inline"`
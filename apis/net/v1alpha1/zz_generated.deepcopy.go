@@ -20,6 +20,7 @@
 package v1alpha1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -146,6 +147,13 @@ func (in *IpamSpec) DeepCopyInto(out *IpamSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.ClusterSubnetsV6 != nil {
+		in, out := &in.ClusterSubnetsV6, &out.ClusterSubnetsV6
+		*out = make(map[string]Subnets, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IpamSpec.
@@ -530,6 +538,11 @@ func (in *TunnelEndpointSpec) DeepCopy() *TunnelEndpointSpec {
 func (in *TunnelEndpointStatus) DeepCopyInto(out *TunnelEndpointStatus) {
 	*out = *in
 	in.Connection.DeepCopyInto(&out.Connection)
+	if in.Health != nil {
+		in, out := &in.Health, &out.Health
+		*out = new(TunnelHealth)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TunnelEndpointStatus.
@@ -541,3 +554,26 @@ func (in *TunnelEndpointStatus) DeepCopy() *TunnelEndpointStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TunnelHealth) DeepCopyInto(out *TunnelHealth) {
+	*out = *in
+	out.RTT = in.RTT
+	out.Jitter = in.Jitter
+	if in.LastHandshakeAge != nil {
+		in, out := &in.LastHandshakeAge, &out.LastHandshakeAge
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TunnelHealth.
+func (in *TunnelHealth) DeepCopy() *TunnelHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(TunnelHealth)
+	in.DeepCopyInto(out)
+	return out
+}
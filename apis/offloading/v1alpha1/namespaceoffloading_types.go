@@ -115,6 +115,20 @@ type NamespaceOffloadingSpec struct {
 	// pod offloading by means of the standard Kubernetes NodeSelector approach
 	// (https://kubernetes.io/docs/concepts/scheduling-eviction/assign-pod-node/#node-affinity).
 	ClusterSelector corev1.NodeSelector `json:"clusterSelector,omitempty"`
+
+	// PreferredClusterSelector allows users to express weighted preferences among the remote clusters selected
+	// through the ClusterSelector field, by means of the standard Kubernetes PreferredSchedulingTerm approach.
+	// The preferences are injected in the offloaded pods as preferred node affinity, and they are used to rank
+	// the eligible clusters when the MaxClusters field is set.
+	// +kubebuilder:validation:Optional
+	PreferredClusterSelector []corev1.PreferredSchedulingTerm `json:"preferredClusterSelector,omitempty"`
+
+	// MaxClusters caps the number of remote clusters the namespace is offloaded to. If set, the eligible
+	// clusters are ranked according to the PreferredClusterSelector field, and only the best ones are selected.
+	// Clusters already hosting the remote namespace are preferred, and they are not deselected if the cap is lowered.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Optional
+	MaxClusters *int32 `json:"maxClusters,omitempty"`
 }
 
 // NamespaceOffloadingStatus defines the observed state of NamespaceOffloading.
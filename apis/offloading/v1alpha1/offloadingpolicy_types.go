@@ -0,0 +1,68 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// OffloadingPolicySpec defines the desired state of OffloadingPolicy.
+type OffloadingPolicySpec struct {
+	// PodSelector selects the pods, in the same namespace of the policy, the policy applies to.
+	PodSelector metav1.LabelSelector `json:"podSelector"`
+	// Priority determines the policy applied when multiple ones select the same pod (the highest one wins).
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+	// PodOffloadingStrategy overrides the PodOffloadingStrategy of the NamespaceOffloading for the selected pods.
+	// It cannot be less restrictive than the one of the NamespaceOffloading (i.e., "Local" and "Remote" are allowed
+	// only if the namespace is offloaded with either the same or the "LocalAndRemote" strategy).
+	// If not set, the PodOffloadingStrategy of the NamespaceOffloading applies.
+	// +kubebuilder:validation:Enum="Local";"Remote";"LocalAndRemote"
+	// +optional
+	PodOffloadingStrategy PodOffloadingStrategyType `json:"podOffloadingStrategy,omitempty"`
+	// ClusterSelector further restricts the remote clusters the selected pods can be offloaded to,
+	// in addition to the ClusterSelector of the NamespaceOffloading.
+	// +optional
+	ClusterSelector *corev1.NodeSelector `json:"clusterSelector,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:shortName="ofp",categories=liqo
+// +kubebuilder:printcolumn:name="PodOffloadingStrategy",type=string,JSONPath=`.spec.podOffloadingStrategy`
+// +kubebuilder:printcolumn:name="Priority",type=integer,JSONPath=`.spec.priority`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// OffloadingPolicy is the Schema for the offloadingpolicies API.
+// It overrides the offloading configuration of the namespace for the selected pods.
+type OffloadingPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec OffloadingPolicySpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// OffloadingPolicyList contains a list of OffloadingPolicy.
+type OffloadingPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OffloadingPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&OffloadingPolicy{}, &OffloadingPolicyList{})
+}
@@ -16,6 +16,7 @@ package v1alpha1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -27,6 +28,39 @@ type StorageType struct {
 	Default bool `json:"default,omitempty"`
 }
 
+// NodePool describes a group of nodes of the cluster sending the ResourceOffer, to be exposed as a dedicated virtual node.
+type NodePool struct {
+	// Name is the name of the node pool, unique within the ResourceOffer.
+	Name string `json:"name"`
+	// Labels contains the labels to be added to the virtual node corresponding to the node pool.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Taints contains the taints to be added to the virtual node corresponding to the node pool.
+	Taints []corev1.Taint `json:"taints,omitempty"`
+	// Resources contains the quantity of resources made available by the node pool.
+	Resources corev1.ResourceList `json:"resources,omitempty"`
+	// MaxAllocatablePerNode contains the maximum quantity of each resource available on a single node of the pool,
+	// as a hint concerning the largest pod which can be hosted.
+	MaxAllocatablePerNode corev1.ResourceList `json:"maxAllocatablePerNode,omitempty"`
+	// Nodes is the number of ready nodes belonging to the node pool.
+	Nodes int32 `json:"nodes,omitempty"`
+}
+
+// FragmentationBucket reports the number of nodes having at least a given quantity of a resource available.
+type FragmentationBucket struct {
+	// Available is the minimum quantity of the resource available on each node accounted in the bucket.
+	Available resource.Quantity `json:"available"`
+	// Nodes is the number of ready nodes having at least the given quantity of the resource available.
+	Nodes int32 `json:"nodes"`
+}
+
+// ResourceFragmentation describes how the available quantity of a resource is distributed across the nodes.
+type ResourceFragmentation struct {
+	// Name is the name of the resource.
+	Name corev1.ResourceName `json:"name"`
+	// Buckets is the cumulative histogram of the nodes by available quantity, sorted by increasing quantity.
+	Buckets []FragmentationBucket `json:"buckets,omitempty"`
+}
+
 // ResourceOfferSpec defines the desired state of ResourceOffer.
 type ResourceOfferSpec struct {
 	// ClusterID is the identifier of the cluster that is sending this ResourceOffer.
@@ -44,6 +78,15 @@ type ResourceOfferSpec struct {
 	WithdrawalTimestamp *metav1.Time `json:"withdrawalTimestamp,omitempty"`
 	// StorageClasses contains the list of the storage classes offered by the cluster.
 	StorageClasses []StorageType `json:"storageClasses,omitempty"`
+	// NodePools contains the breakdown of the offered resources by node pool. If set, a dedicated virtual node
+	// is created for each node pool, instead of a single one aggregating all resources.
+	NodePools []NodePool `json:"nodePools,omitempty"`
+	// LargestAllocatable contains the largest quantity of each resource available on a single node, as an upper bound
+	// for the resources requested by a single pod which can be hosted (the sum of the offered resources may be
+	// fragmented across multiple nodes).
+	LargestAllocatable corev1.ResourceList `json:"largestAllocatable,omitempty"`
+	// Fragmentation describes how the offered resources are distributed across the nodes of the cluster.
+	Fragmentation []ResourceFragmentation `json:"fragmentation,omitempty"`
 }
 
 // OfferPhase describes the phase of the ResourceOffer.
@@ -80,10 +123,19 @@ type ResourceOfferStatus struct {
 	// +kubebuilder:validation:Enum="Pending";"ManualActionRequired";"Accepted";"Refused"
 	// +kubebuilder:default="Pending"
 	Phase OfferPhase `json:"phase"`
+	// Reason is a brief CamelCase string that describes why the offer is in the current phase.
+	Reason string `json:"reason,omitempty"`
+	// Message is a human-readable message indicating details about why the offer is in the current phase.
+	Message string `json:"message,omitempty"`
+	// ObservedGeneration is the generation of the offer last evaluated by the operator, to detect the changes of its spec.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 	// VirtualKubeletStatus indicates if the virtual-kubelet for this ResourceOffer has been created or not.
 	// +kubebuilder:validation:Enum="None";"Created";"Deleting"
 	// +kubebuilder:default="None"
 	VirtualKubeletStatus VirtualKubeletStatus `json:"virtualKubeletStatus,omitempty"`
+	// Used is the quantity of the offered resources currently consumed by the ShadowPods of the cluster the
+	// ResourceOffer is addressed to, as observed by the cluster sending this ResourceOffer.
+	Used corev1.ResourceList `json:"used,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,128 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ResourceSharingPolicy defines how a given resource is shared with the selected clusters.
+type ResourceSharingPolicy struct {
+	// Name is the name of the resource (e.g., cpu, memory).
+	Name corev1.ResourceName `json:"name"`
+	// Percentage is the percentage of the available quantity of the resource shared with each selected cluster.
+	// If not set, the default sharing percentage applies.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Percentage *int32 `json:"percentage,omitempty"`
+	// Limit is the maximum quantity of the resource shared with each selected cluster.
+	// +optional
+	Limit *resource.Quantity `json:"limit,omitempty"`
+	// Guaranteed is the quantity of the resource reserved to each selected cluster,
+	// which is no longer offered to the other clusters.
+	// +optional
+	Guaranteed *resource.Quantity `json:"guaranteed,omitempty"`
+}
+
+// PodSecurityAction defines how the offloaded pods violating the pod security constraints are handled.
+// +kubebuilder:validation:Enum="Deny";"Rewrite"
+type PodSecurityAction string
+
+const (
+	// PodSecurityActionDeny rejects the offloaded pods violating any constraint.
+	PodSecurityActionDeny PodSecurityAction = "Deny"
+	// PodSecurityActionRewrite rewrites the offloaded pods violating any constraint, removing or overriding the offending fields.
+	PodSecurityActionRewrite PodSecurityAction = "Rewrite"
+)
+
+// PodSecurityConstraints defines the security constraints enforced on the pods offloaded by the selected clusters.
+// Unless explicitly allowed, privileged containers, host namespaces, hostPath volumes, additional capabilities and
+// containers possibly running as root are forbidden.
+type PodSecurityConstraints struct {
+	// Action defines how the pods violating any constraint are handled.
+	// +kubebuilder:default="Deny"
+	// +optional
+	Action PodSecurityAction `json:"action,omitempty"`
+	// AllowPrivileged allows the offloaded containers to run in privileged mode.
+	// +optional
+	AllowPrivileged bool `json:"allowPrivileged,omitempty"`
+	// AllowHostNamespaces allows the offloaded pods to share the host network, PID and IPC namespaces.
+	// +optional
+	AllowHostNamespaces bool `json:"allowHostNamespaces,omitempty"`
+	// AllowHostPath allows the offloaded pods to mount hostPath volumes.
+	// +optional
+	AllowHostPath bool `json:"allowHostPath,omitempty"`
+	// AllowedCapabilities is the list of capabilities the offloaded containers are allowed to add.
+	// +optional
+	AllowedCapabilities []corev1.Capability `json:"allowedCapabilities,omitempty"`
+	// AllowRunAsRoot allows the offloaded containers to run as root.
+	// Otherwise, each container shall either set runAsNonRoot or a non-zero runAsUser.
+	// +optional
+	AllowRunAsRoot bool `json:"allowRunAsRoot,omitempty"`
+}
+
+// SharingPolicySpec defines the desired state of SharingPolicy.
+type SharingPolicySpec struct {
+	// ClusterIDs is the list of the identifiers of the clusters the policy applies to.
+	// +optional
+	ClusterIDs []string `json:"clusterIDs,omitempty"`
+	// ClusterSelector selects the clusters the policy applies to, according to the labels of the corresponding ForeignClusters.
+	// +optional
+	ClusterSelector *metav1.LabelSelector `json:"clusterSelector,omitempty"`
+	// Priority determines the policy applied when multiple ones select the same cluster (the highest one wins).
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+	// Resources defines how each resource is shared with the selected clusters.
+	// The resources not listed here are shared according to the default sharing percentage.
+	// +optional
+	Resources []ResourceSharingPolicy `json:"resources,omitempty"`
+	// ExcludedNodeSelector selects the nodes (e.g., node pools) whose resources are not shared with the selected clusters.
+	// +optional
+	ExcludedNodeSelector *metav1.LabelSelector `json:"excludedNodeSelector,omitempty"`
+	// PodSecurity defines the security constraints enforced on the pods offloaded by the selected clusters.
+	// If not set, no constraint is enforced.
+	// +optional
+	PodSecurity *PodSecurityConstraints `json:"podSecurity,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,shortName="sp",categories=liqo
+
+// SharingPolicy is the Schema for the sharingpolicies API.
+// It defines how the local resources are shared with the selected remote clusters.
+// +kubebuilder:printcolumn:name="Priority",type=integer,JSONPath=`.spec.priority`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type SharingPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec SharingPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SharingPolicyList contains a list of SharingPolicy.
+type SharingPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SharingPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SharingPolicy{}, &SharingPolicyList{})
+}
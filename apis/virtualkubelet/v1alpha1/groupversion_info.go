@@ -41,6 +41,15 @@ var (
 	// ShadowPodGroupVersionResource is groupResourceVersion used to register these objects.
 	ShadowPodGroupVersionResource = SchemeGroupVersion.WithResource(ShadowPodResource)
 
+	// ReflectionPolicyResource is the resource name used to register the ReflectionPolicy CRD.
+	ReflectionPolicyResource = "reflectionpolicies"
+
+	// ReflectionPolicyGroupResource is group resource used to register these objects.
+	ReflectionPolicyGroupResource = schema.GroupResource{Group: SchemeGroupVersion.Group, Resource: ReflectionPolicyResource}
+
+	// ReflectionPolicyGroupVersionResource is groupResourceVersion used to register these objects.
+	ReflectionPolicyGroupVersionResource = SchemeGroupVersion.WithResource(ReflectionPolicyResource)
+
 	// SchemeBuilder is used to add go types to the GroupVersionKind scheme.
 	SchemeBuilder = &scheme.Builder{GroupVersion: SchemeGroupVersion}
 
@@ -0,0 +1,107 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ReflectionAction defines whether the objects matching a given rule shall be reflected.
+// +kubebuilder:validation:Enum="Reflect";"Skip"
+type ReflectionAction string
+
+const (
+	// ReflectionActionReflect indicates that the matching objects shall be reflected.
+	ReflectionActionReflect ReflectionAction = "Reflect"
+	// ReflectionActionSkip indicates that the matching objects shall not be reflected.
+	ReflectionActionSkip ReflectionAction = "Skip"
+)
+
+// ReflectedResource identifies a kind of resource subject to reflection policies.
+// +kubebuilder:validation:Enum="ConfigMap";"Secret";"Service"
+type ReflectedResource string
+
+const (
+	// ReflectedResourceConfigMap identifies the ConfigMap resources.
+	ReflectedResourceConfigMap ReflectedResource = "ConfigMap"
+	// ReflectedResourceSecret identifies the Secret resources.
+	ReflectedResourceSecret ReflectedResource = "Secret"
+	// ReflectedResourceService identifies the Service resources.
+	ReflectedResourceService ReflectedResource = "Service"
+)
+
+// ReflectionRule selects a set of objects, and defines whether and how they shall be reflected.
+type ReflectionRule struct {
+	// Resources is the list of resource kinds the rule applies to (all, if empty).
+	Resources []ReflectedResource `json:"resources,omitempty"`
+	// Namespaces is the list of local namespaces the rule applies to (all, if empty).
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Selector selects the objects the rule applies to, based on their labels (all, if not specified).
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// SecretTypes is the list of secret types the rule applies to (all, if empty). It is ignored for the other resources.
+	SecretTypes []corev1.SecretType `json:"secretTypes,omitempty"`
+
+	// Action defines whether the matching objects shall be reflected or not.
+	// +kubebuilder:default="Reflect"
+	Action ReflectionAction `json:"action,omitempty"`
+
+	// StripLabels is the list of label keys removed from the reflected objects.
+	StripLabels []string `json:"stripLabels,omitempty"`
+	// StripAnnotations is the list of annotation keys removed from the reflected objects.
+	StripAnnotations []string `json:"stripAnnotations,omitempty"`
+	// StripDataKeys is the list of data keys removed from the reflected ConfigMaps and Secrets.
+	StripDataKeys []string `json:"stripDataKeys,omitempty"`
+	// SetLabels is the set of labels added to (or overwritten in) the reflected objects.
+	SetLabels map[string]string `json:"setLabels,omitempty"`
+	// SetAnnotations is the set of annotations added to (or overwritten in) the reflected objects.
+	SetAnnotations map[string]string `json:"setAnnotations,omitempty"`
+}
+
+// ReflectionPolicySpec defines the desired state of ReflectionPolicy.
+type ReflectionPolicySpec struct {
+	// ClusterIDs is the list of remote clusters the policy applies to (all, if empty).
+	ClusterIDs []string `json:"clusterIDs,omitempty"`
+	// Rules is the ordered list of reflection rules. The first rule matching a given object determines its outcome,
+	// while objects matching no rule are reflected unmodified.
+	Rules []ReflectionRule `json:"rules"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster,categories=liqo
+// +genclient
+// +genclient:nonNamespaced
+
+// ReflectionPolicy is the Schema for the reflectionpolicies API.
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+type ReflectionPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ReflectionPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ReflectionPolicyList contains a list of ReflectionPolicy.
+type ReflectionPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ReflectionPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ReflectionPolicy{}, &ReflectionPolicyList{})
+}
@@ -24,7 +24,21 @@ type ShadowPodSpec struct {
 	Pod corev1.PodSpec `json:"pod,omitempty"`
 }
 
+// ShadowPodStatus defines the observed state of ShadowPod.
+type ShadowPodStatus struct {
+	// Phase mirrors the phase of the pod managed by the ShadowPod.
+	Phase corev1.PodPhase `json:"phase,omitempty"`
+	// Conditions mirrors the conditions of the pod managed by the ShadowPod.
+	Conditions []corev1.PodCondition `json:"conditions,omitempty"`
+	// NodeName is the name of the node the pod managed by the ShadowPod has been scheduled on.
+	NodeName string `json:"nodeName,omitempty"`
+}
+
 // +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Node",type=string,JSONPath=`.status.nodeName`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 // +genclient
 
 // ShadowPod is the Schema for the Shadowpods API.
@@ -32,7 +46,8 @@ type ShadowPod struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec ShadowPodSpec `json:"spec,omitempty"`
+	Spec   ShadowPodSpec   `json:"spec,omitempty"`
+	Status ShadowPodStatus `json:"status,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -33,6 +33,7 @@ func main() {
 	klog.Info("Starting")
 
 	var awsConfig identitymanager.AwsConfig
+	var oidcConfig identitymanager.OIDCConfig
 
 	namespace := flag.String("namespace", "default", "Namespace where your configs are stored.")
 	resync := flag.Duration("resync-period", 30*time.Second, "The resync period for the informers")
@@ -51,6 +52,10 @@ func main() {
 	flag.StringVar(&awsConfig.AwsRegion, "aws-region", "", "AWS region where the local cluster is running")
 	flag.StringVar(&awsConfig.AwsClusterName, "aws-cluster-name", "", "Name of the local EKS cluster")
 
+	flag.StringVar(&oidcConfig.Audience, "oidc-audience", "",
+		"The audience of the service account tokens issued as identities, accepted by the OIDC authenticator of the local API server")
+	flag.DurationVar(&oidcConfig.TokenExpiration, "oidc-token-expiration", time.Hour, "The validity of the issued service account tokens")
+
 	// Configure the flags concerning the exposed API server connection parameters.
 	apiserver.InitFlags(nil)
 
@@ -64,7 +69,7 @@ func main() {
 
 	clusterIdentity := clusterFlags.ReadOrDie()
 	authService, err := authservice.NewAuthServiceCtrl(
-		config, *namespace, awsConfig, *resync, apiserver.GetConfig(), *enableAuth, *useTLS, clusterIdentity)
+		config, *namespace, awsConfig, oidcConfig, *resync, apiserver.GetConfig(), *enableAuth, *useTLS, clusterIdentity)
 	if err != nil {
 		klog.Error(err)
 		os.Exit(1)
@@ -45,6 +45,7 @@ import (
 	"github.com/liqotech/liqo/pkg/consts"
 	identitymanager "github.com/liqotech/liqo/pkg/identityManager"
 	foreignclusteroperator "github.com/liqotech/liqo/pkg/liqo-controller-manager/foreign-cluster-operator"
+	identityrenewalctrl "github.com/liqotech/liqo/pkg/liqo-controller-manager/identity-renewal-controller"
 	namectrl "github.com/liqotech/liqo/pkg/liqo-controller-manager/namespace-controller"
 	nsoffctrl "github.com/liqotech/liqo/pkg/liqo-controller-manager/namespaceOffloading-controller"
 	mapsctrl "github.com/liqotech/liqo/pkg/liqo-controller-manager/namespacemap-controller"
@@ -123,6 +124,9 @@ func main() {
 	authServicePortOverride := flag.String(consts.AuthServicePortOverrideParameter, "",
 		"The port the authentication service is reachable from foreign clusters (automatically retrieved if not set")
 	autoJoin := flag.Bool("auto-join-discovered-clusters", true, "Whether to automatically peer with discovered clusters")
+	identityRenewalThreshold := argsutils.Percentage{Val: 70}
+	flag.Var(&identityRenewalThreshold, "identity-renewal-threshold-percentage",
+		"The percentage of the certificate lifetime after which the identities used to interact with remote clusters are renewed")
 
 	// Resource sharing parameters
 	externalResourceMonitorAddress := flag.String(consts.ExternalResourceMonitorParameter, "",
@@ -131,10 +135,24 @@ func main() {
 		"The set of labels which characterizes the local cluster when exposed remotely as a virtual node")
 	resourceSharingPercentage := argsutils.Percentage{Val: 50}
 	flag.Var(&resourceSharingPercentage, "resource-sharing-percentage",
-		"The amount (in percentage) of cluster resources possibly shared with foreign clusters (ignored when using an external resource monitor)")
+		"The amount (in percentage) of cluster resources possibly shared with foreign clusters not selected by any SharingPolicy "+
+			"(ignored when using an external resource monitor)")
+	nodePoolLabel := flag.String("node-pool-label", "",
+		"The key of the label grouping the nodes into node pools, each one offered as a dedicated virtual node (default: no node pools)")
 	enableIncomingPeering := flag.Bool("enable-incoming-peering", true,
 		"Enable remote clusters to establish an incoming peering with the local cluster (can be overwritten on a per foreign cluster basis)")
+	var offerPrices argsutils.ResourceMap
+	flag.Var(&offerPrices, "offer-prices",
+		"The price of each resource published in the ResourceOffers sent to remote clusters (e.g., cpu=1,memory=500m for the price per CPU and per GiB)")
 	offerDisableAutoAccept := flag.Bool("offer-disable-auto-accept", false, "Disable the automatic acceptance of resource offers")
+	var offerMaxPrices, offerMinQuota argsutils.ResourceMap
+	var offerRequiredLabels argsutils.StringMap
+	flag.Var(&offerMaxPrices, "offer-max-prices",
+		"The maximum price of each resource for a ResourceOffer to be automatically accepted (e.g., cpu=2,memory=1)")
+	flag.Var(&offerRequiredLabels, "offer-required-labels",
+		"The set of labels a ResourceOffer shall include to be automatically accepted (e.g., liqo.io/provider=kubeadm)")
+	flag.Var(&offerMinQuota, "offer-min-quota",
+		"The minimum quantity of each resource a ResourceOffer shall include to be automatically accepted (e.g., cpu=4,memory=8Gi)")
 	offerUpdateThreshold := argsutils.Percentage{Val: 5}
 	flag.Var(&offerUpdateThreshold, "offer-update-threshold-percentage",
 		"The threshold (in percentage) of resources quantity variation which triggers a ResourceOffer update")
@@ -263,6 +281,20 @@ func main() {
 		klog.Fatal(err)
 	}
 
+	identityRenewalReconciler := &identityrenewalctrl.IdentityRenewalReconciler{
+		Client:           mgr.GetClient(),
+		Scheme:           mgr.GetScheme(),
+		LiqoNamespace:    *liqoNamespace,
+		IdentityManager:  idManager,
+		RenewalThreshold: identityRenewalThreshold.Val,
+
+		SecureTransport:   secureTransport,
+		InsecureTransport: insecureTransport,
+	}
+	if err = identityRenewalReconciler.SetupWithManager(mgr); err != nil {
+		klog.Fatal(err)
+	}
+
 	var resourceRequestReconciler *resourceRequestOperator.ResourceRequestReconciler
 	var monitor resourcemonitors.ResourceReader
 	if *externalResourceMonitorAddress != "" {
@@ -272,14 +304,15 @@ func main() {
 		}
 		monitor = externalMonitor
 	} else {
-		localMonitor := resourcemonitors.NewLocalMonitor(ctx, clientset, *resyncPeriod)
-		monitor = &resourcemonitors.ResourceScaler{
-			Provider: localMonitor,
-			Factor:   float32(resourceSharingPercentage.Val) / 100.,
+		localMonitor := resourcemonitors.NewLocalMonitor(ctx, clientset, *resyncPeriod, *nodePoolLabel)
+		policyReader := resourcemonitors.NewSharingPolicyReader(mgr.GetClient(), localMonitor, float32(resourceSharingPercentage.Val)/100.)
+		if err = policyReader.SetupPolicyWatch(ctx, mgr.GetCache()); err != nil {
+			klog.Fatal(err)
 		}
+		monitor = policyReader
 	}
 	offerUpdater := resourceRequestOperator.NewOfferUpdater(ctx, mgr.GetClient(), clusterIdentity,
-		clusterLabels.StringMap, monitor, uint(offerUpdateThreshold.Val), *realStorageClassName, *enableStorage)
+		clusterLabels.StringMap, offerPrices.ResourceList, monitor, uint(offerUpdateThreshold.Val), *realStorageClassName, *enableStorage)
 	resourceRequestReconciler = &resourceRequestOperator.ResourceRequestReconciler{
 		Client:                mgr.GetClient(),
 		Scheme:                mgr.GetScheme(),
@@ -307,8 +340,13 @@ func main() {
 		LimitsRAM:             kubeletRAMLimits.Quantity,
 	}
 
+	acceptancePolicy := &resourceoffercontroller.AcceptancePolicy{
+		MaxPrices:      offerMaxPrices.ResourceList,
+		RequiredLabels: offerRequiredLabels.StringMap,
+		MinQuota:       offerMinQuota.ResourceList,
+	}
 	resourceOfferReconciler := resourceoffercontroller.NewResourceOfferController(
-		mgr, clusterIdentity, *resyncPeriod, *liqoNamespace, virtualKubeletOpts, *offerDisableAutoAccept)
+		mgr, clusterIdentity, *resyncPeriod, *liqoNamespace, virtualKubeletOpts, *offerDisableAutoAccept, acceptancePolicy)
 	if err = resourceOfferReconciler.SetupWithManager(mgr); err != nil {
 		klog.Fatal(err)
 	}
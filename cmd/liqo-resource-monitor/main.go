@@ -0,0 +1,111 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package main is the reference implementation of an external resource monitor, which offers to the remote clusters
+// either the resources configured in a YAML file, or a percentage of the ones available in a given Kubernetes cluster.
+package main
+
+import (
+	"context"
+	"flag"
+	"os"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	resourcemonitors "github.com/liqotech/liqo/pkg/liqo-controller-manager/resource-request-controller/resource-monitors"
+	"github.com/liqotech/liqo/pkg/liqo-controller-manager/resource-request-controller/resource-monitors/server"
+	argsutils "github.com/liqotech/liqo/pkg/utils/args"
+	"github.com/liqotech/liqo/pkg/utils/restcfg"
+)
+
+func main() {
+	address := flag.String("address", ":7000", "The address the gRPC server listens on")
+	configPath := flag.String("config", "", "The path of the YAML file specifying the resources offered to the remote clusters")
+	reloadPeriod := flag.Duration("reload-period", 10*time.Second, "The period the configuration file is checked for changes")
+	kubeconfig := flag.String("cluster-kubeconfig", "",
+		"The kubeconfig of the cluster whose resources are offered, as an alternative to the configuration file")
+	resyncPeriod := flag.Duration("resync-period", 10*time.Hour, "The resync period for the informers")
+	sharingPercentage := argsutils.Percentage{Val: 50}
+	flag.Var(&sharingPercentage, "resource-sharing-percentage",
+		"The percentage of the available resources of the cluster offered to each remote cluster (with --cluster-kubeconfig)")
+
+	klog.InitFlags(nil)
+	restcfg.InitFlags(nil)
+	flag.Parse()
+
+	if (*configPath == "") == (*kubeconfig == "") {
+		klog.Error("Exactly one between --config and --cluster-kubeconfig shall be specified")
+		os.Exit(1)
+	}
+
+	ctx := ctrl.SetupSignalHandler()
+
+	var srv *server.Server
+	if *configPath != "" {
+		config, err := server.LoadStaticPolicyConfig(*configPath)
+		if err != nil {
+			klog.Fatal(err)
+		}
+
+		policy := server.NewStaticPolicy(config)
+		srv = server.New(policy)
+		go reloadConfig(ctx, *configPath, *reloadPeriod, policy, srv)
+	} else {
+		config, err := clientcmd.BuildConfigFromFlags("", *kubeconfig)
+		if err != nil {
+			klog.Fatalf("Failed to load the kubeconfig %q: %v", *kubeconfig, err)
+		}
+		clientset := kubernetes.NewForConfigOrDie(restcfg.SetRateLimiter(config))
+
+		scaler := &resourcemonitors.ResourceScaler{
+			Provider: resourcemonitors.NewLocalMonitor(ctx, clientset, *resyncPeriod, ""),
+			Factor:   float32(sharingPercentage.Val) / 100.,
+		}
+		srv = server.New(server.FromResourceReader(scaler))
+		scaler.Register(ctx, srv)
+	}
+
+	if err := srv.Serve(ctx, *address); err != nil {
+		klog.Fatal(err)
+	}
+}
+
+// reloadConfig periodically reloads the configuration file, notifying the subscribers in case of changes.
+func reloadConfig(ctx context.Context, path string, period time.Duration,
+	policy *server.StaticPolicy, notifier resourcemonitors.ResourceUpdateNotifier) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			config, err := server.LoadStaticPolicyConfig(path)
+			if err != nil {
+				klog.Errorf("Failed to reload the configuration: %v", err)
+				continue
+			}
+
+			if policy.Update(config) {
+				klog.Info("Configuration changed, notifying the subscribers")
+				notifier.NotifyChange()
+			}
+		}
+	}
+}
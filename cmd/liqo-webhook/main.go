@@ -29,10 +29,13 @@ import (
 const gracefulPeriod = 5 * time.Second
 
 func main() {
+	config := &mutate.MutationConfig{}
+
 	klog.InitFlags(nil)
+	flag.DurationVar(&config.ShadowPodQuotaResyncPeriod, "shadow-pod-quota-resync-period", 10*time.Minute,
+		"The period after which the resources used by each remote cluster through ShadowPods are resynchronized")
 	flag.Parse()
 
-	config := &mutate.MutationConfig{}
 	setOptions(config)
 
 	klog.Info("Starting server ...")
@@ -28,18 +28,27 @@ import (
 func newGenerateAddCommand(ctx context.Context) *cobra.Command {
 	var liqoNamespace string
 	var onlyCommand bool
+	var tokenOptions generate.TokenOptions
 	var addCmd = &cobra.Command{
 		Use:          generate.LiqoctlGenerateAddCommand,
 		Short:        generate.LiqoctlGenerateShortHelp,
 		Long:         generate.LiqoctlGenerateLongHelp,
 		SilenceUsage: true,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return generate.HandleGenerateAddCommand(ctx, liqoNamespace, onlyCommand, os.Args[0])
+			return generate.HandleGenerateAddCommand(ctx, liqoNamespace, onlyCommand, os.Args[0], &tokenOptions)
 		},
 	}
 	addCmd.Flags().StringVar(&liqoNamespace, "namespace", add.ClusterLiqoNamespace,
 		"the name of the namespace where Liqo is installed")
 	addCmd.Flags().BoolVar(&onlyCommand, "only-command", false, "print only the add command (useful in scripts)")
+	addCmd.Flags().StringVar(&tokenOptions.Name, "token-name", "",
+		"generate a dedicated token with the given name, instead of using the cluster-wide one")
+	addCmd.Flags().BoolVar(&tokenOptions.OneShot, "one-shot", false,
+		"generate a dedicated token, which can be used to establish a single peering")
+	addCmd.Flags().DurationVar(&tokenOptions.TTL, "token-ttl", 0,
+		"generate a dedicated token, which expires after the given duration")
+	addCmd.Flags().StringVar(&tokenOptions.AllowedClusterID, "token-allowed-cluster-id", "",
+		"generate a dedicated token, which can be used only by the cluster with the given ClusterID")
 
 	return addCmd
 }
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -69,6 +70,11 @@ func newInstallCommand(ctx context.Context) *cobra.Command {
 	// environments (e.g., cloud providers). This guarantees improved compatibility at the cost of possible limited performance drops.
 	installCmd.PersistentFlags().Int("mtu", 1340, "mtu is the maximum transmission unit for interfaces managed by Liqo")
 	installCmd.PersistentFlags().Int("vpn-listening-port", liqoconst.GatewayListeningPort, "vpn-listening-port is the port used by the vpn tunnel")
+	installCmd.PersistentFlags().String("oidc-audience", "", "Issue service account tokens with the given audience as identities "+
+		"for the remote clusters, instead of certificates. The API server shall accept them through an OIDC authenticator "+
+		"trusting the service account issuer, with the same audience as client ID")
+	installCmd.PersistentFlags().Duration("oidc-token-expiration", time.Hour,
+		"The validity of the service account tokens issued as identities, when --oidc-audience is set")
 
 	provider.GenerateFlags(installCmd)
 
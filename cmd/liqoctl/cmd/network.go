@@ -0,0 +1,59 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/liqotech/liqo/pkg/liqoctl/network"
+)
+
+func newNetworkCommand(ctx context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   network.UseCommand,
+		Short: network.ShortHelp,
+	}
+
+	cmd.AddCommand(newNetworkDiagnoseCommand(ctx))
+	return cmd
+}
+
+func newNetworkDiagnoseCommand(ctx context.Context) *cobra.Command {
+	var params = network.DiagnoseArgs{}
+
+	cmd := &cobra.Command{
+		Use:           network.DiagnoseUseCommand,
+		Short:         network.DiagnoseShortHelp,
+		Long:          network.DiagnoseLongHelp,
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return params.Handler(ctx)
+		},
+	}
+	cmd.Flags().StringVarP(&params.Cluster1Namespace, "namespace1", "", "liqo", "Namespace Liqo is running in cluster 1")
+	cmd.Flags().StringVarP(&params.Cluster2Namespace, "namespace2", "", "liqo", "Namespace Liqo is running in cluster 2")
+	cmd.Flags().StringVarP(&params.Cluster1Kubeconfig, "config1", "", "", "Kubeconfig of cluster 1")
+	cmd.Flags().StringVarP(&params.Cluster2Kubeconfig, "config2", "", "", "Kubeconfig of cluster 2")
+	cmd.Flags().BoolVar(&params.SkipDataplane, "skip-dataplane", false,
+		"Skip the inspection of the iptables chains and routing tables configured by the gateway and route pods")
+	cmd.Flags().BoolVar(&params.SkipProbes, "skip-probes", false, "Skip the reachability tests performed through ephemeral probe pods")
+	cmd.Flags().StringVar(&params.ProbeImage, "probe-image", network.DefaultProbeImage, "The image used for the probe pods")
+	cmd.Flags().DurationVar(&params.ProbeTimeout, "probe-timeout", network.DefaultProbeTimeout, "The maximum time waited for each probe to complete")
+
+	return cmd
+}
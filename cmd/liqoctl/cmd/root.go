@@ -78,5 +78,6 @@ func NewRootCommand(ctx context.Context) *cobra.Command {
 	rootCmd.AddCommand(newConnectCommand(ctx))
 	rootCmd.AddCommand(newDisconnectCommand(ctx))
 	rootCmd.AddCommand(newMoveCommand(ctx))
+	rootCmd.AddCommand(newNetworkCommand(ctx))
 	return rootCmd
 }
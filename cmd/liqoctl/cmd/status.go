@@ -16,6 +16,7 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/spf13/cobra"
 
@@ -30,10 +31,33 @@ func newStatusCommand(ctx context.Context) *cobra.Command {
 		Short:         status.ShortHelp,
 		Long:          status.LongHelp,
 		SilenceErrors: true,
+		SilenceUsage:  true,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return params.Handler(ctx)
 		},
 	}
-	cmd.Flags().StringVarP(&params.Namespace, status.Namespace, "n", "liqo", "Namespace Liqo is running in")
+	cmd.PersistentFlags().StringVarP(&params.Namespace, status.Namespace, "n", "liqo", "Namespace Liqo is running in")
+	cmd.PersistentFlags().StringVarP(&params.OutputFormat, status.Output, "o", "",
+		fmt.Sprintf("Output format of the status, either %s or %s (default: human-readable)", status.OutputFormatJSON, status.OutputFormatYAML))
+
+	cmd.AddCommand(newStatusPeerCommand(ctx, &params))
 	return cmd
 }
+
+func newStatusPeerCommand(ctx context.Context, params *status.Args) *cobra.Command {
+	return &cobra.Command{
+		Use:           status.PeerUseCommand,
+		Short:         status.PeerShortHelp,
+		Long:          status.PeerLongHelp,
+		Args:          cobra.MaximumNArgs(1),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var cluster string
+			if len(args) == 1 {
+				cluster = args[0]
+			}
+			return params.PeerHandler(ctx, cluster)
+		},
+	}
+}
@@ -16,6 +16,7 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	"os"
 	"sync"
 	"time"
@@ -38,16 +39,27 @@ import (
 
 type gatewayOperatorFlags struct {
 	enableLeaderElection bool
+	activeActive         bool
+	dualStack            bool
 	leaseDuration        time.Duration
 	renewDeadline        time.Duration
 	retryPeriod          time.Duration
 	tunnelMTU            uint
 	tunnelListeningPort  uint
+	tunnelBackend        string
+	probeInterval        time.Duration
+	probeRTTThreshold    time.Duration
+	probeLossThreshold   int
+	handshakeThreshold   time.Duration
 }
 
 func addGatewayOperatorFlags(liqonet *gatewayOperatorFlags) {
 	flag.BoolVar(&liqonet.enableLeaderElection, "gateway.leader-elect", false,
 		"leader-elect enables leader election for controller manager.")
+	flag.BoolVar(&liqonet.activeActive, "gateway.active-active", false,
+		"active-active enables all the replicas to carry tunnels, each one handling a subset of the remote clusters (overrides leader-elect)")
+	flag.BoolVar(&liqonet.dualStack, "gateway.dual-stack", false,
+		"dual-stack enables the configuration of the IPv6 networks of the remote clusters, in addition to the IPv4 ones")
 	flag.DurationVar(&liqonet.leaseDuration, "gateway.lease-duration", 7*time.Second,
 		"lease-duration is the duration that non-leader candidates will wait to force acquire leadership")
 	flag.DurationVar(&liqonet.renewDeadline, "gateway.renew-deadline", 5*time.Second,
@@ -58,11 +70,23 @@ func addGatewayOperatorFlags(liqonet *gatewayOperatorFlags) {
 		"mtu is the maximum transmission unit for interfaces managed by the gateway operator")
 	flag.UintVar(&liqonet.tunnelListeningPort, "gateway.listening-port", liqoconst.GatewayListeningPort,
 		"listening-port is the port used by the vpn tunnel")
+	flag.StringVar(&liqonet.tunnelBackend, "gateway.tunnel-backend", liqoconst.DriverName,
+		fmt.Sprintf("tunnel-backend is the vpn technology used to interconnect clusters (accepted values: %q, %q)",
+			liqoconst.DriverName, liqoconst.IPsecDriverName))
+	flag.DurationVar(&liqonet.probeInterval, "gateway.probe-interval", 5*time.Second,
+		"probe-interval is the interval between the health probes sent across the tunnels (0 to disable probing)")
+	flag.DurationVar(&liqonet.probeRTTThreshold, "gateway.probe-rtt-threshold", 500*time.Millisecond,
+		"probe-rtt-threshold is the average round-trip time above which a tunnel is considered degraded (0 to disable the check)")
+	flag.IntVar(&liqonet.probeLossThreshold, "gateway.probe-loss-threshold", 50,
+		"probe-loss-threshold is the percentage of lost probes above which a tunnel is considered degraded (0 to disable the check)")
+	flag.DurationVar(&liqonet.handshakeThreshold, "gateway.handshake-threshold", 5*time.Minute,
+		"handshake-threshold is the time since the last handshake above which a tunnel is considered degraded (0 to disable the check)")
 }
 
 func runGatewayOperator(commonFlags *liqonetCommonFlags, gatewayFlags *gatewayOperatorFlags) {
 	metricsAddr := commonFlags.metricsAddr
-	enableLeaderElection := gatewayFlags.enableLeaderElection
+	// In active/active mode, all the replicas are active, and leader election is not needed.
+	enableLeaderElection := gatewayFlags.enableLeaderElection && !gatewayFlags.activeActive
 	leaseDuration := gatewayFlags.leaseDuration
 	renewDeadLine := gatewayFlags.renewDeadline
 	retryPeriod := gatewayFlags.retryPeriod
@@ -72,9 +96,25 @@ func runGatewayOperator(commonFlags *liqonetCommonFlags, gatewayFlags *gatewayOp
 		klog.Errorf("port %d should be greater than %d and minor than %d", gatewayFlags.tunnelListeningPort, liqoconst.UDPMinPort, liqoconst.UDPMaxPort)
 		os.Exit(1)
 	}
+	if gatewayFlags.probeLossThreshold < 0 || gatewayFlags.probeLossThreshold > 100 {
+		klog.Errorf("probe loss threshold %d should be a percentage between 0 and 100", gatewayFlags.probeLossThreshold)
+		os.Exit(1)
+	}
 	port := gatewayFlags.tunnelListeningPort
 	MTU := gatewayFlags.tunnelMTU
 
+	// Retrieve the name of the tunnel device associated with the selected backend.
+	var tunnelDeviceName string
+	switch gatewayFlags.tunnelBackend {
+	case liqoconst.DriverName:
+		tunnelDeviceName = liqoconst.DeviceName
+	case liqoconst.IPsecDriverName:
+		tunnelDeviceName = liqoconst.IPsecDeviceName
+	default:
+		klog.Errorf("unsupported tunnel backend %q", gatewayFlags.tunnelBackend)
+		os.Exit(1)
+	}
+
 	// Get the pod ip and parse to net.IP.
 	podIP, err := utils.GetPodIP()
 	if err != nil {
@@ -130,12 +170,19 @@ func runGatewayOperator(commonFlags *liqonetCommonFlags, gatewayFlags *gatewayOp
 	klog.Infof("created custom network namespace {%s}", liqoconst.GatewayNetnsName)
 
 	labelController := tunneloperator.NewLabelerController(podIP.String(), main.GetClient())
+	labelController.ActiveActive = gatewayFlags.activeActive
 	if err = labelController.SetupWithManager(main); err != nil {
 		klog.Errorf("unable to setup labeler controller: %s", err)
 		os.Exit(1)
 	}
 	tunnelController, err := tunneloperator.NewTunnelController(podIP.String(), podNamespace, eventRecorder,
-		clientset, main.GetClient(), &readyClustersMutex, readyClusters, gatewayNetns, hostNetns, int(MTU), int(port))
+		clientset, main.GetClient(), &readyClustersMutex, readyClusters, gatewayNetns, hostNetns, int(MTU), int(port), gatewayFlags.tunnelBackend,
+		tunneloperator.HealthConfig{
+			ProbeInterval:       gatewayFlags.probeInterval,
+			RTTThreshold:        gatewayFlags.probeRTTThreshold,
+			PacketLossThreshold: gatewayFlags.probeLossThreshold,
+			HandshakeThreshold:  gatewayFlags.handshakeThreshold,
+		}, gatewayFlags.activeActive, gatewayFlags.dualStack)
 	// If something goes wrong while creating and configuring the tunnel controller
 	// then make sure that we remove all the resources created during the create process.
 	if err != nil {
@@ -144,9 +191,9 @@ func runGatewayOperator(commonFlags *liqonetCommonFlags, gatewayFlags *gatewayOp
 		if err := liqonetns.DeleteNetns(liqoconst.GatewayNetnsName); err != nil {
 			klog.Errorf("an error occurred while deleting netns {%s}: %v", liqoconst.GatewayNetnsName, err)
 		}
-		klog.Info("cleaning up wireguard tunnel interface")
-		if err := links.DeleteIFaceByName(liqoconst.DeviceName); err != nil {
-			klog.Errorf("an error occurred while deleting iface {%s}: %v", liqoconst.DriverName, err)
+		klog.Infof("cleaning up %s tunnel interface", gatewayFlags.tunnelBackend)
+		if err := links.DeleteIFaceByName(tunnelDeviceName); err != nil {
+			klog.Errorf("an error occurred while deleting iface {%s}: %v", tunnelDeviceName, err)
 		}
 		os.Exit(1)
 	}
@@ -155,7 +202,7 @@ func runGatewayOperator(commonFlags *liqonetCommonFlags, gatewayFlags *gatewayOp
 		os.Exit(1)
 	}
 	natMappingController, err := tunneloperator.NewNatMappingController(main.GetClient(), &readyClustersMutex,
-		readyClusters, gatewayNetns)
+		readyClusters, gatewayNetns, gatewayFlags.dualStack)
 	if err != nil {
 		klog.Errorf("an error occurred while creating the natmapping controller: %v", err)
 		os.Exit(1)
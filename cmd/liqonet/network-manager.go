@@ -15,8 +15,11 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"fmt"
 	"os"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/fields"
@@ -24,6 +27,7 @@ import (
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"github.com/liqotech/liqo/internal/liqonet/network-manager/netcfgcreator"
 	"github.com/liqotech/liqo/internal/liqonet/network-manager/tunnelendpointcreator"
@@ -39,20 +43,51 @@ type networkManagerFlags struct {
 	podCIDR     args.CIDR
 	serviceCIDR args.CIDR
 
+	podCIDRv6     args.CIDR
+	serviceCIDRv6 args.CIDR
+
 	additionalPools args.CIDRList
 	reservedPools   args.CIDRList
+
+	tunnelBackend string
+	activeActive  bool
+
+	leakDetectionPeriod time.Duration
+	leakCollection      bool
 }
 
 func addNetworkManagerFlags(managerFlags *networkManagerFlags) {
 	flag.Var(&managerFlags.podCIDR, "manager.pod-cidr", "The subnet used by the cluster for the pods, in CIDR notation")
 	flag.Var(&managerFlags.serviceCIDR, "manager.service-cidr", "The subnet used by the cluster for the pods, in services notation")
+	flag.Var(&managerFlags.podCIDRv6, "manager.pod-cidr-v6",
+		"The IPv6 subnet used by the cluster for the pods, in CIDR notation (dual-stack clusters only)")
+	flag.Var(&managerFlags.serviceCIDRv6, "manager.service-cidr-v6",
+		"The IPv6 subnet used by the cluster for the services, in CIDR notation (dual-stack clusters only)")
 	flag.Var(&managerFlags.reservedPools, "manager.reserved-pools",
 		"Private CIDRs slices used by the Kubernetes infrastructure, in addition to the pod and service CIDR (e.g., the node subnet).")
 	flag.Var(&managerFlags.additionalPools, "manager.additional-pools",
 		"Network pools used to map a cluster network into another one in order to prevent conflicts, in addition to standard private CIDRs.")
+	flag.StringVar(&managerFlags.tunnelBackend, "manager.tunnel-backend", liqoconst.DriverName,
+		fmt.Sprintf("The vpn technology used to interconnect clusters, advertised to the remote ones (accepted values: %q, %q)",
+			liqoconst.DriverName, liqoconst.IPsecDriverName))
+	flag.BoolVar(&managerFlags.activeActive, "manager.gateway-active-active", false,
+		"Whether the gateway is configured in active/active mode, hence each remote cluster is advertised the endpoint of a specific replica")
+	flag.DurationVar(&managerFlags.leakDetectionPeriod, "manager.leak-detection-period", 10*time.Minute,
+		"The period of the detection of the IPAM entries referencing pods, endpoints or clusters which no longer exist (0 to disable)")
+	flag.BoolVar(&managerFlags.leakCollection, "manager.leak-garbage-collection", false,
+		"Whether the IPAM entries detected as leaked are also garbage collected, in addition to being reported")
 }
 
 func runNetworkManager(commonFlags *liqonetCommonFlags, managerFlags *networkManagerFlags) {
+	if managerFlags.tunnelBackend != liqoconst.DriverName && managerFlags.tunnelBackend != liqoconst.IPsecDriverName {
+		klog.Errorf("unsupported tunnel backend %q", managerFlags.tunnelBackend)
+		os.Exit(1)
+	}
+	if managerFlags.podCIDRv6.IsSet() != managerFlags.serviceCIDRv6.IsSet() {
+		klog.Error("both the IPv6 pod and service CIDRs shall be specified in case of dual-stack clusters")
+		os.Exit(1)
+	}
+
 	podNamespace, err := utils.GetPodNamespace()
 	if err != nil {
 		klog.Errorf("unable to get pod namespace: %v", err)
@@ -67,6 +102,7 @@ func runNetworkManager(commonFlags *liqonetCommonFlags, managerFlags *networkMan
 			SelectorsByObject: cache.SelectorsByObject{
 				&corev1.Secret{}:  {Field: fields.OneTermEqualSelector("metadata.namespace", podNamespace)},
 				&corev1.Service{}: {Field: fields.OneTermEqualSelector("metadata.namespace", podNamespace)},
+				&corev1.Pod{}:     {Field: fields.OneTermEqualSelector("metadata.namespace", podNamespace)},
 			},
 		}),
 	})
@@ -88,6 +124,16 @@ func runNetworkManager(commonFlags *liqonetCommonFlags, managerFlags *networkMan
 		os.Exit(1)
 	}
 
+	var podCIDRv6, externalCIDRv6 string
+	if managerFlags.podCIDRv6.IsSet() {
+		podCIDRv6 = managerFlags.podCIDRv6.String()
+		externalCIDRv6, err = ipam.GetExternalCIDRV6(utils.GetMask(podCIDRv6))
+		if err != nil {
+			klog.Errorf("Failed to initialize the IPv6 external CIDR: %s", err)
+			os.Exit(1)
+		}
+	}
+
 	tec := &tunnelendpointcreator.TunnelEndpointCreator{
 		Client:    mgr.GetClient(),
 		Scheme:    mgr.GetScheme(),
@@ -98,8 +144,12 @@ func runNetworkManager(commonFlags *liqonetCommonFlags, managerFlags *networkMan
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
 
-		PodCIDR:      managerFlags.podCIDR.String(),
-		ExternalCIDR: externalCIDR,
+		PodCIDR:        managerFlags.podCIDR.String(),
+		ExternalCIDR:   externalCIDR,
+		PodCIDRv6:      podCIDRv6,
+		ExternalCIDRv6: externalCIDRv6,
+		BackendType:    managerFlags.tunnelBackend,
+		ActiveActive:   managerFlags.activeActive,
 	}
 
 	if err = tec.SetupWithManager(mgr); err != nil {
@@ -112,6 +162,16 @@ func runNetworkManager(commonFlags *liqonetCommonFlags, managerFlags *networkMan
 		os.Exit(1)
 	}
 
+	if managerFlags.leakDetectionPeriod > 0 {
+		if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+			ipam.RunLeakDetection(ctx, managerFlags.leakDetectionPeriod, managerFlags.leakCollection)
+			return nil
+		})); err != nil {
+			klog.Errorf("unable to add the IPAM leak detection to the manager: %s", err)
+			os.Exit(1)
+		}
+	}
+
 	klog.Info("starting manager as liqo-network-manager")
 	if err := mgr.Start(tec.SetupSignalHandlerForTunEndCreator()); err != nil {
 		klog.Errorf("an error occurred while starting manager: %s", err)
@@ -122,7 +182,11 @@ func runNetworkManager(commonFlags *liqonetCommonFlags, managerFlags *networkMan
 func initializeIPAM(client dynamic.Interface, managerFlags *networkManagerFlags) (*liqonetIpam.IPAM, error) {
 	ipam := liqonetIpam.NewIPAM()
 
-	if err := ipam.Init(liqonetIpam.Pools, client, liqoconst.NetworkManagerIpamPort); err != nil {
+	pools := liqonetIpam.Pools
+	if managerFlags.podCIDRv6.IsSet() {
+		pools = append(append([]string{}, liqonetIpam.Pools...), liqonetIpam.PoolsV6...)
+	}
+	if err := ipam.Init(pools, client, liqoconst.NetworkManagerIpamPort); err != nil {
 		return nil, err
 	}
 
@@ -133,6 +197,15 @@ func initializeIPAM(client dynamic.Interface, managerFlags *networkManagerFlags)
 		return nil, err
 	}
 
+	if managerFlags.podCIDRv6.IsSet() {
+		if err := ipam.SetPodCIDRV6(managerFlags.podCIDRv6.String()); err != nil {
+			return nil, err
+		}
+		if err := ipam.SetServiceCIDRV6(managerFlags.serviceCIDRv6.String()); err != nil {
+			return nil, err
+		}
+	}
+
 	for _, pool := range managerFlags.additionalPools.StringList.StringList {
 		if err := ipam.AddNetworkPool(pool); err != nil {
 			return nil, err
@@ -49,6 +49,11 @@ func InstallFlags(flags *pflag.FlagSet, o *Opts) {
 	flags.UintVar(&o.SecretWorkers, "secret-reflection-workers", o.SecretWorkers, "The number of secret reflection workers")
 	flags.UintVar(&o.PersistenVolumeClaimWorkers, "persistentvolumeclaim-reflection-workers", o.PersistenVolumeClaimWorkers,
 		"The number of persistentvolumeclaim reflection workers")
+	flags.UintVar(&o.CustomResourceWorkers, "custom-resource-reflection-workers", o.CustomResourceWorkers,
+		"The number of reflection workers for each custom resource")
+	flags.Var(&o.CustomResourceReflections, "custom-resource-reflections",
+		"The custom resources to be reflected, in the form resource.version.group[=direction] "+
+			"(direction: LocalToRemote (default), RemoteToLocal or StatusBackPropagation)")
 
 	flags.DurationVar(&o.NodeLeaseDuration, "node-lease-duration", o.NodeLeaseDuration, "The duration of the node leases")
 	flags.DurationVar(&o.NodePingInterval, "node-ping-interval", o.NodePingInterval,
@@ -59,6 +64,11 @@ func InstallFlags(flags *pflag.FlagSet, o *Opts) {
 	flags.Var(&o.NodeExtraAnnotations, "node-extra-annotations", "Extra annotations to add to the Virtual Node")
 	flags.Var(&o.NodeExtraLabels, "node-extra-labels", "Extra labels to add to the Virtual Node")
 
+	flags.StringVar(&o.NodePool, "node-pool", o.NodePool, "The node pool of the ResourceOffer represented by the Virtual Node")
+	flags.Var(&o.SiblingNodes, "sibling-nodes", "The names of the other Virtual Nodes associated with the same remote cluster")
+	flags.BoolVar(&o.PodReflectionOnly, "pod-reflection-only", o.PodReflectionOnly,
+		"Reflect only the pods scheduled on the Virtual Node, delegating the other resources to a sibling virtual kubelet")
+
 	flags.BoolVar(&o.EnableStorage, "enable-storage", false, "Enable the Liqo storage reflection")
 	flags.StringVar(&o.VirtualStorageClassName, "virtual-storage-class-name", "liqo", "Name of the virtual storage class")
 	flags.StringVar(&o.RemoteRealStorageClassName, "remote-real-storage-class-name", "", "Name of the real storage class to use for the actual volumes")
@@ -40,6 +40,7 @@ const (
 	DefaultIngressWorkers              = 3
 	DefaultConfigMapWorkers            = 3
 	DefaultSecretWorkers               = 3
+	DefaultCustomResourceWorkers       = 3
 	DefaultPersistenVolumeClaimWorkers = 3
 
 	DefaultNodePingTimeout = 1 * time.Second
@@ -72,6 +73,10 @@ type Opts struct {
 	ConfigMapWorkers            uint
 	SecretWorkers               uint
 	PersistenVolumeClaimWorkers uint
+	CustomResourceWorkers       uint
+
+	// The list of custom resources to be reflected, in the form resource.version.group[=direction]
+	CustomResourceReflections argsutils.StringList
 
 	NodeLeaseDuration time.Duration
 	NodePingInterval  time.Duration
@@ -80,6 +85,12 @@ type Opts struct {
 	NodeExtraAnnotations argsutils.StringMap
 	NodeExtraLabels      argsutils.StringMap
 
+	// The node pool of the ResourceOffer represented by this virtual node, if any
+	NodePool string
+	// The names of the other virtual nodes associated with the same remote cluster
+	SiblingNodes      argsutils.StringList
+	PodReflectionOnly bool
+
 	EnableStorage              bool
 	VirtualStorageClassName    string
 	RemoteRealStorageClassName string
@@ -106,6 +117,7 @@ func NewOpts() *Opts {
 		ConfigMapWorkers:            DefaultConfigMapWorkers,
 		SecretWorkers:               DefaultSecretWorkers,
 		PersistenVolumeClaimWorkers: DefaultPersistenVolumeClaimWorkers,
+		CustomResourceWorkers:       DefaultCustomResourceWorkers,
 
 		NodeLeaseDuration: node.DefaultLeaseDuration * time.Second,
 		NodePingInterval:  node.DefaultPingInterval,
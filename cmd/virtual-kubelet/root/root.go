@@ -37,6 +37,7 @@ import (
 	"github.com/liqotech/liqo/pkg/utils/restcfg"
 	nodeprovider "github.com/liqotech/liqo/pkg/virtualKubelet/liqoNodeProvider"
 	podprovider "github.com/liqotech/liqo/pkg/virtualKubelet/provider"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/customresource"
 )
 
 const defaultVersion = "v1.22.1" // This should follow the version of k8s.io/kubernetes we are importing
@@ -64,10 +65,15 @@ func runRootCommand(ctx context.Context, c *Opts) error {
 	}
 
 	if c.PodWorkers == 0 || c.ServiceWorkers == 0 || c.IngressWorkers == 0 ||
-		c.EndpointSliceWorkers == 0 || c.ConfigMapWorkers == 0 || c.SecretWorkers == 0 {
+		c.EndpointSliceWorkers == 0 || c.ConfigMapWorkers == 0 || c.SecretWorkers == 0 || c.CustomResourceWorkers == 0 {
 		return errors.New("reflection workers must be greater than 0")
 	}
 
+	customResourceReflections, err := customresource.ParseResourceReflections(c.CustomResourceReflections.StringList)
+	if err != nil {
+		return err
+	}
+
 	localConfig, err := utils.GetRestConfig(c.HomeKubeconfig)
 	if err != nil {
 		return err
@@ -108,6 +114,12 @@ func runRootCommand(ctx context.Context, c *Opts) error {
 		ConfigMapWorkers:            c.ConfigMapWorkers,
 		SecretWorkers:               c.SecretWorkers,
 		PersistenVolumeClaimWorkers: c.PersistenVolumeClaimWorkers,
+		CustomResourceWorkers:       c.CustomResourceWorkers,
+
+		CustomResourceReflections: customResourceReflections,
+
+		SiblingNodeNames:  c.SiblingNodes.StringList,
+		PodReflectionOnly: c.PodReflectionOnly,
 
 		EnableStorage:              c.EnableStorage,
 		VirtualStorageClassName:    c.VirtualStorageClassName,
@@ -129,6 +141,7 @@ func runRootCommand(ctx context.Context, c *Opts) error {
 		Namespace:       c.TenantNamespace,
 
 		NodeName:         c.NodeName,
+		NodePool:         c.NodePool,
 		InternalIP:       os.Getenv("VKUBELET_POD_IP"),
 		DaemonPort:       c.ListenPort,
 		Version:          getVersion(localConfig),
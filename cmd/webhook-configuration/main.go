@@ -22,6 +22,7 @@ import (
 	"path"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
@@ -78,68 +79,104 @@ func main() {
 	}
 	klog.Infof("mutating webhook %s found", webhookName)
 
-	// iterate over all the webhooks in the mutatingWebhookConfiguration
-	for i, wh := range mutatingWebhook.Webhooks {
-		// generate tls secrets and CA
-		secrets, err := webhookConfiguration.NewSecrets(webhookConfiguration.ServiceNames{
-			CommonName: wh.Name,
-			DNSNames:   webhookConfiguration.GetDNSNames(wh.Name),
-		})
-		if err != nil {
-			klog.Fatal(err)
-		}
-		klog.Infof("secrets for %s generated", wh.Name)
-
-		// write tls secrets in a kubernetes secret
-		secret := &corev1.Secret{
-			ObjectMeta: metav1.ObjectMeta{
-				GenerateName: fmt.Sprintf("%s-", wh.ClientConfig.Service.Name),
-				Namespace:    wh.ClientConfig.Service.Namespace,
-				Labels: map[string]string{
-					LiqoMutatingWebhookServiceName: wh.ClientConfig.Service.Name,
-				},
-				OwnerReferences: []metav1.OwnerReference{
-					{
-						APIVersion: "v1",
-						Kind:       "Pod",
-						Name:       podName,
-						UID:        types.UID(podUID),
-					},
-				},
+	// get the validatingWebhookConfiguration, if any
+	validatingWebhook, err := k8sClient.AdmissionregistrationV1().
+		ValidatingWebhookConfigurations().
+		Get(context.TODO(), webhookName, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		klog.Infof("validating webhook %s not found", webhookName)
+		validatingWebhook = nil
+	case err != nil:
+		klog.Fatal(err)
+	default:
+		klog.Infof("validating webhook %s found", webhookName)
+	}
+
+	if len(mutatingWebhook.Webhooks) == 0 {
+		klog.Fatalf("mutating webhook %s does not contain any webhook", webhookName)
+	}
+
+	// all the webhooks are served by the same backend, hence a single certificate is generated for all of them
+	wh := mutatingWebhook.Webhooks[0]
+
+	// generate tls secrets and CA
+	secrets, err := webhookConfiguration.NewSecrets(webhookConfiguration.ServiceNames{
+		CommonName: wh.Name,
+		DNSNames:   webhookConfiguration.GetDNSNames(wh.Name),
+	})
+	if err != nil {
+		klog.Fatal(err)
+	}
+	klog.Infof("secrets for %s generated", wh.Name)
+
+	// write tls secrets in a kubernetes secret
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-", wh.ClientConfig.Service.Name),
+			Namespace:    wh.ClientConfig.Service.Namespace,
+			Labels: map[string]string{
+				LiqoMutatingWebhookServiceName: wh.ClientConfig.Service.Name,
 			},
-			Type: "kubernetes.io/tls",
-			Data: map[string][]byte{
-				"tls.crt": secrets.ServerCertPEM(),
-				"tls.key": secrets.ServerKeyPEM(),
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: "v1",
+					Kind:       "Pod",
+					Name:       podName,
+					UID:        types.UID(podUID),
+				},
 			},
-		}
+		},
+		Type: "kubernetes.io/tls",
+		Data: map[string][]byte{
+			"tls.crt": secrets.ServerCertPEM(),
+			"tls.key": secrets.ServerKeyPEM(),
+		},
+	}
 
-		// dump the secrets on disk for allowing the container to read them
-		if err = secrets.WriteFiles(path.Join(certDir, "tls.crt"), path.Join(certDir, "tls.key")); err != nil {
-			klog.Fatal(err)
-		}
-		klog.Infof("secrets for %s written on disk", wh.Name)
+	// dump the secrets on disk for allowing the container to read them
+	if err = secrets.WriteFiles(path.Join(certDir, "tls.crt"), path.Join(certDir, "tls.key")); err != nil {
+		klog.Fatal(err)
+	}
+	klog.Infof("secrets for %s written on disk", wh.Name)
 
-		// create k8s secret
-		if _, err := k8sClient.CoreV1().Secrets(secret.Namespace).Create(context.TODO(), secret, metav1.CreateOptions{}); err != nil {
+	// create k8s secret
+	if _, err := k8sClient.CoreV1().Secrets(secret.Namespace).Create(context.TODO(), secret, metav1.CreateOptions{}); err != nil {
+		klog.Fatal(err)
+	}
+	klog.Infof("secret for %s created", wh.Name)
+
+	// patch all the webhooks with the newly generated CaBundle
+	for i := range mutatingWebhook.Webhooks {
+		_, err = k8sClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Patch(context.TODO(),
+			mutatingWebhook.Name,
+			types.JSONPatchType,
+			caBundlePatch(i, secrets.CAPEM()),
+			metav1.PatchOptions{})
+		if err != nil {
 			klog.Fatal(err)
 		}
-		klog.Infof("secret for %s created", wh.Name)
+		klog.Infof("webhook %s CaBundle patched", mutatingWebhook.Webhooks[i].Name)
+	}
 
-		// patch the mutatingWebhook with the newly generated CaBundle
-		caBundlePatch := []byte(fmt.Sprintf(
-			`[{"op":"replace","path":"/webhooks/%d/clientConfig/caBundle","value":%q}]`,
-			i, secrets.CAPEM()))
+	if validatingWebhook == nil {
+		return
+	}
 
-		klog.Info()
-		_, err = k8sClient.AdmissionregistrationV1().MutatingWebhookConfigurations().Patch(context.TODO(),
-			mutatingWebhook.Name,
+	for i := range validatingWebhook.Webhooks {
+		_, err = k8sClient.AdmissionregistrationV1().ValidatingWebhookConfigurations().Patch(context.TODO(),
+			validatingWebhook.Name,
 			types.JSONPatchType,
-			caBundlePatch,
+			caBundlePatch(i, secrets.CAPEM()),
 			metav1.PatchOptions{})
 		if err != nil {
 			klog.Fatal(err)
 		}
-		klog.Infof("webhook %s CaBundle patched", wh.Name)
+		klog.Infof("webhook %s CaBundle patched", validatingWebhook.Webhooks[i].Name)
 	}
 }
+
+// caBundlePatch returns the JSON patch replacing the CaBundle of the i-th webhook of a webhook configuration.
+func caBundlePatch(i int, caPEM string) []byte {
+	return []byte(fmt.Sprintf(`[{"op":"replace","path":"/webhooks/%d/clientConfig/caBundle","value":%q}]`, i, caPEM))
+}
@@ -170,6 +170,45 @@ Concatenates a values list into a string in the form "--commandName=val1,val2"
 - {{ trimSuffix "," $res }}
 {{- end -}}
 
+{{/*
+Generates the RBAC rules granting access to the custom resources reflected by the virtual kubelet.
+The "side" parameter (local or remote) selects the cluster the rules refer to, since the required verbs depend on the reflection direction.
+The remote rules are granted by the provider to its consumers, hence the directions are interpreted from their point of view,
+and the consumers can reflect only the resources also configured in the provider cluster.
+*/}}
+{{- define "liqo.customResourceReflectionRules" -}}
+{{- $side := .side -}}
+{{- range .reflections }}
+{{- $parts := splitn "=" 2 . }}
+{{- $direction := default "LocalToRemote" $parts._1 }}
+{{- $gvr := splitn "." 3 $parts._0 }}
+- apiGroups:
+  - {{ default "" $gvr._2 | quote }}
+  resources:
+  - {{ $gvr._0 }}
+  verbs:
+  - get
+  - list
+  - watch
+  {{- if or (and (eq $side "local") (eq $direction "RemoteToLocal")) (and (eq $side "remote") (ne $direction "RemoteToLocal")) }}
+  - create
+  - delete
+  - patch
+  - update
+  {{- end }}
+{{- if and (eq $side "local") (eq $direction "StatusBackPropagation") }}
+- apiGroups:
+  - {{ default "" $gvr._2 | quote }}
+  resources:
+  - {{ $gvr._0 }}/status
+  verbs:
+  - get
+  - patch
+  - update
+{{- end }}
+{{- end }}
+{{- end -}}
+
 {{/*
 Get the liqo clusterID ConfigMap name
 */}}
@@ -16,6 +16,7 @@ package authservice
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -73,9 +74,13 @@ type Controller struct {
 
 // NewAuthServiceCtrl creates a new Auth Controller.
 func NewAuthServiceCtrl(config *rest.Config, namespace string,
-	awsConfig identitymanager.AwsConfig, resyncTime time.Duration,
+	awsConfig identitymanager.AwsConfig, oidcConfig identitymanager.OIDCConfig, resyncTime time.Duration,
 	apiServerConfig apiserver.Config, authEnabled, useTLS bool,
 	localCluster discoveryv1alpha1.ClusterIdentity) (*Controller, error) {
+	if !awsConfig.IsEmpty() && !oidcConfig.IsEmpty() {
+		return nil, fmt.Errorf("the AWS and the OIDC identity providers cannot be enabled at the same time")
+	}
+
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
 		return nil, err
@@ -97,12 +102,16 @@ func NewAuthServiceCtrl(config *rest.Config, namespace string,
 	namespaceManager := tenantnamespace.NewTenantNamespaceManager(clientset)
 
 	var idProvider identitymanager.IdentityProvider
-	if awsConfig.IsEmpty() {
-		idProvider = identitymanager.NewCertificateIdentityProvider(
-			context.Background(), clientset, localCluster, namespaceManager)
-	} else {
+	switch {
+	case !awsConfig.IsEmpty():
 		idProvider = identitymanager.NewIAMIdentityProvider(
 			clientset, localCluster, &awsConfig, namespaceManager)
+	case !oidcConfig.IsEmpty():
+		idProvider = identitymanager.NewOIDCIdentityProvider(
+			clientset, localCluster, &oidcConfig, namespaceManager)
+	default:
+		idProvider = identitymanager.NewCertificateIdentityProvider(
+			context.Background(), clientset, localCluster, namespaceManager)
 	}
 
 	return &Controller{
@@ -138,6 +147,7 @@ func (authService *Controller) Start(address string, useTLS bool, certPath, keyP
 	router := httprouter.New()
 
 	router.POST(auth.CertIdentityURI, authService.identity)
+	router.POST(auth.CertRenewalURI, authService.renewIdentity)
 	router.GET(auth.IdsURI, authService.ids)
 
 	if useTLS {
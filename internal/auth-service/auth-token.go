@@ -16,11 +16,16 @@ package authservice
 
 import (
 	"context"
+	"strconv"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 
 	"github.com/liqotech/liqo/pkg/auth"
@@ -29,6 +34,8 @@ import (
 type tokenManager interface {
 	getToken() (string, error)
 	createToken() error
+	consumeNamedToken(token, clusterID string) (bool, error)
+	releaseNamedToken(token, clusterID string) error
 }
 
 func (authService *Controller) getToken() (string, error) {
@@ -85,3 +92,86 @@ func (authService *Controller) createToken() error {
 	}
 	return nil
 }
+
+// consumeNamedToken checks whether the given token matches a valid named token for the given remote cluster,
+// and in that case increases its usage count. The update is performed starting from the cached version of the secret,
+// hence concurrent usages of the same token conflict, and only one of them succeeds.
+func (authService *Controller) consumeNamedToken(token, clusterID string) (bool, error) {
+	secret, namedToken, err := authService.getNamedToken(token)
+	if err != nil || namedToken == nil {
+		return false, err
+	}
+
+	if err = namedToken.CheckValidity(clusterID, time.Now()); err != nil {
+		klog.Warningf("[%s] rejecting named token: %v", clusterID, err)
+		return false, nil
+	}
+
+	namedToken.Usages++
+	namedToken.SetUsages(secret)
+	if _, err = authService.clientset.CoreV1().Secrets(secret.Namespace).Update(context.TODO(), secret, metav1.UpdateOptions{}); err != nil {
+		klog.Error(err)
+		return false, err
+	}
+	klog.Infof("[%s] named token %q used (%d usages)", clusterID, namedToken.Name, namedToken.Usages)
+	return true, nil
+}
+
+// releaseNamedToken decreases the usage count of the named token matching the given one (if any), to roll back
+// a previous consumption in case the identity could not be eventually issued to the given remote cluster.
+func (authService *Controller) releaseNamedToken(token, clusterID string) error {
+	cached, namedToken, err := authService.getNamedToken(token)
+	if err != nil || namedToken == nil {
+		return err
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		// The secret is retrieved from the API server, as the cached version does not yet reflect the consumption.
+		secret, err := authService.clientset.CoreV1().Secrets(cached.Namespace).Get(context.TODO(), cached.Name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if namedToken, err = auth.NamedTokenFromSecret(secret); err != nil || namedToken.Usages == 0 {
+			return err
+		}
+
+		namedToken.Usages--
+		namedToken.SetUsages(secret)
+		_, err = authService.clientset.CoreV1().Secrets(secret.Namespace).Update(context.TODO(), secret, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+	klog.Infof("[%s] named token %q released (%d usages)", clusterID, namedToken.Name, namedToken.Usages)
+	return nil
+}
+
+// getNamedToken returns the cached secret and the named token matching the given token, if any.
+func (authService *Controller) getNamedToken(token string) (*v1.Secret, *auth.NamedToken, error) {
+	selector := labels.SelectorFromSet(labels.Set{auth.NamedTokenLabel: strconv.FormatBool(true)})
+
+	var secret *v1.Secret
+	var namedToken *auth.NamedToken
+	err := cache.ListAll(authService.secretInformer.GetStore(), selector, func(obj interface{}) {
+		candidate, ok := obj.(*v1.Secret)
+		if !ok || secret != nil {
+			return
+		}
+
+		parsed, err := auth.NamedTokenFromSecret(candidate)
+		if err != nil {
+			klog.Warning(err)
+			return
+		}
+		if parsed.Value == token {
+			secret, namedToken = candidate.DeepCopy(), parsed
+		}
+	})
+	if err != nil {
+		klog.Error(err)
+		return nil, nil, err
+	}
+	return secret, namedToken, nil
+}
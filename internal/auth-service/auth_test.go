@@ -65,6 +65,14 @@ func (man *tokenManagerMock) createToken() error {
 	return nil
 }
 
+func (man *tokenManagerMock) consumeNamedToken(token, clusterID string) (bool, error) {
+	return false, nil
+}
+
+func (man *tokenManagerMock) releaseNamedToken(token, clusterID string) error {
+	return nil
+}
+
 var _ = Describe("Auth", func() {
 
 	var (
@@ -162,6 +170,61 @@ var _ = Describe("Auth", func() {
 
 	})
 
+	Context("Named Token", func() {
+
+		createNamedToken := func(token *auth.NamedToken) {
+			_, err := cluster.GetClient().CoreV1().Secrets(authService.namespace).Create(
+				context.TODO(), token.ToSecret(authService.namespace), metav1.CreateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			// wait for the secret to be cached by the informer
+			Eventually(func() bool {
+				_, exists, _ := authService.secretInformer.GetStore().GetByKey(
+					authService.namespace + "/" + auth.NamedTokenSecretName(token.Name))
+				return exists
+			}).Should(BeTrue())
+		}
+
+		It("One-shot token", func() {
+			createNamedToken(&auth.NamedToken{Name: "one-shot", Value: "one-shot-token", MaxUsages: 1})
+
+			Expect(authService.consumeNamedToken("one-shot-token", "cluster")).To(BeTrue())
+			Eventually(func() (bool, error) {
+				return authService.consumeNamedToken("one-shot-token", "cluster")
+			}).Should(BeFalse())
+		})
+
+		It("Released token", func() {
+			createNamedToken(&auth.NamedToken{Name: "released", Value: "released-token", MaxUsages: 1})
+
+			Expect(authService.consumeNamedToken("released-token", "cluster")).To(BeTrue())
+			Eventually(func() (bool, error) {
+				return authService.consumeNamedToken("released-token", "cluster")
+			}).Should(BeFalse())
+
+			// once released, the token can be used again
+			Expect(authService.releaseNamedToken("released-token", "cluster")).To(Succeed())
+			Eventually(func() (bool, error) {
+				return authService.consumeNamedToken("released-token", "cluster")
+			}).Should(BeTrue())
+		})
+
+		It("Expired token", func() {
+			createNamedToken(&auth.NamedToken{Name: "expired", Value: "expired-token", Expiration: time.Now().Add(-time.Minute)})
+			Expect(authService.consumeNamedToken("expired-token", "cluster")).To(BeFalse())
+		})
+
+		It("Token restricted to a cluster", func() {
+			createNamedToken(&auth.NamedToken{Name: "restricted", Value: "restricted-token", AllowedClusterID: "allowed"})
+			Expect(authService.consumeNamedToken("restricted-token", "other")).To(BeFalse())
+			Expect(authService.consumeNamedToken("restricted-token", "allowed")).To(BeTrue())
+		})
+
+		It("Unknown token", func() {
+			Expect(authService.consumeNamedToken("unknown-token", "cluster")).To(BeFalse())
+		})
+
+	})
+
 	Context("Credential Validator", func() {
 
 		type credentialValidatorTestcase struct {
@@ -92,6 +92,13 @@ func (authService *Controller) handleIdentity(
 		klog.Error(err)
 		return nil, err
 	}
+	// named tokens are consumed only if the identity is successfully issued, hence the usage is rolled back otherwise.
+	defer func() {
+		if err != nil {
+			authService.credentialsValidator.releaseCredentials(
+				&identityRequest, authService.getTokenManager(), authService.authenticationEnabled)
+		}
+	}()
 	tracer.Step("Credentials checked")
 
 	remoteClusterIdentity := identityRequest.ClusterIdentity
@@ -0,0 +1,112 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package authservice
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/trace"
+
+	"github.com/liqotech/liqo/pkg/auth"
+	autherrors "github.com/liqotech/liqo/pkg/auth/errors"
+	traceutils "github.com/liqotech/liqo/pkg/utils/trace"
+)
+
+// renewIdentity handles the certificate renewal http request.
+func (authService *Controller) renewIdentity(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	tracer := trace.New("Identity renewal handler")
+	ctx := trace.ContextWithTrace(r.Context(), tracer)
+	defer tracer.LogIfLong(traceutils.LongThreshold())
+
+	bytes, err := io.ReadAll(r.Body)
+	if err != nil {
+		klog.Error(err)
+		authService.handleError(w, err)
+		return
+	}
+
+	renewalRequest := auth.CertificateRenewalRequest{}
+	err = json.Unmarshal(bytes, &renewalRequest)
+	if err != nil {
+		klog.Error(err)
+		err = &autherrors.ClientError{
+			Reason: err.Error(),
+		}
+		authService.handleError(w, err)
+		return
+	}
+
+	response, err := authService.handleIdentityRenewal(ctx, renewalRequest)
+	if err != nil {
+		klog.Error(err)
+		authService.handleError(w, err)
+		return
+	}
+	klog.V(8).Infof("Sending response: %v", response)
+
+	respBytes, err := json.Marshal(response)
+	if err != nil {
+		klog.Error(err)
+		authService.handleError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	if _, err = w.Write(respBytes); err != nil {
+		klog.Error(err)
+		return
+	}
+}
+
+// handleIdentityRenewal issues a new certificate and a CertificateIdentityResponse, given a CertificateRenewalRequest.
+// Differently from the initial identity request, the renewal is authenticated by the identity currently owned by
+// the remote cluster, hence it is only allowed if the corresponding tenant namespace and certificate already exist.
+// Both the current certificate and the new CSR are required to refer to the cluster ID specified in the request.
+func (authService *Controller) handleIdentityRenewal(
+	ctx context.Context, renewalRequest auth.CertificateRenewalRequest) (*auth.CertificateIdentityResponse, error) {
+	tracer := trace.FromContext(ctx).Nest("Identity renewal handling")
+	defer tracer.LogIfLong(traceutils.LongThreshold())
+
+	remoteClusterIdentity := renewalRequest.ClusterIdentity
+	namespace, err := authService.namespaceManager.GetNamespace(remoteClusterIdentity)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+	tracer.Step("Tenant namespace retrieved")
+
+	identityResponse, err := authService.identityProvider.RenewSigningRequest(remoteClusterIdentity,
+		namespace.Name, renewalRequest.CertificateSigningRequest, renewalRequest.Signature)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+	tracer.Step("Certificate signing request renewed")
+
+	response, err := auth.NewCertificateIdentityResponse(namespace.Name, identityResponse, authService.apiServerConfig)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+	tracer.Step("Identity response prepared")
+
+	klog.Infof("Identity Renewal successfully performed for cluster %s", remoteClusterIdentity)
+	return response, nil
+}
@@ -25,7 +25,8 @@ import (
 
 type credentialsValidator interface {
 	checkCredentials(roleRequest auth.IdentityRequest, tokenManager tokenManager, authenticationEnabled bool) error
-	validToken(tokenManager tokenManager, token string) (bool, error)
+	validToken(tokenManager tokenManager, token, clusterID string) (bool, error)
+	releaseCredentials(roleRequest auth.IdentityRequest, tokenManager tokenManager, authenticationEnabled bool)
 }
 
 type tokenValidator struct{}
@@ -42,7 +43,7 @@ func (tokenValidator *tokenValidator) checkCredentials(
 		return nil
 	}
 
-	valid, err := tokenValidator.validToken(tokenManager, roleRequest.GetToken())
+	valid, err := tokenValidator.validToken(tokenManager, roleRequest.GetToken(), roleRequest.GetClusterIdentity().ClusterID)
 	if err != nil {
 		klog.Error(err)
 		return err
@@ -57,13 +58,33 @@ func (tokenValidator *tokenValidator) checkCredentials(
 	return nil
 }
 
-// validToken checks if the token provided is valid.
-func (tokenValidator *tokenValidator) validToken(tokenManager tokenManager, token string) (bool, error) {
+// validToken checks if the token provided is valid, either as the cluster-wide token
+// or as a named token allowed for the given remote cluster.
+func (tokenValidator *tokenValidator) validToken(tokenManager tokenManager, token, clusterID string) (bool, error) {
 	correctToken, err := tokenManager.getToken()
 	if err != nil {
 		klog.Error(err)
 		return false, err
 	}
+	if token == correctToken {
+		return true, nil
+	}
+
+	if token == "" {
+		return false, nil
+	}
+	return tokenManager.consumeNamedToken(token, clusterID)
+}
 
-	return token == correctToken, nil
+// releaseCredentials rolls back the effects of a previous successful checkCredentials invocation, in case the identity
+// could not be eventually issued. Specifically, it restores the usage count of the named token consumed (if any).
+func (tokenValidator *tokenValidator) releaseCredentials(
+	roleRequest auth.IdentityRequest, tokenManager tokenManager, authenticationEnabled bool) {
+	if !authenticationEnabled || roleRequest.GetToken() == "" {
+		return
+	}
+
+	if err := tokenManager.releaseNamedToken(roleRequest.GetToken(), roleRequest.GetClusterIdentity().ClusterID); err != nil {
+		klog.Errorf("[%s] failed to release the named token: %v", roleRequest.GetClusterIdentity(), err)
+	}
 }
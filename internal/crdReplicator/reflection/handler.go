@@ -206,46 +206,74 @@ func (r *Reflector) updateRemoteObjectSpec(ctx context.Context, gvr schema.Group
 
 // updateObjectStatus updates the status of a local or remote object, depending on the resource ownership.
 func (r *Reflector) updateObjectStatus(ctx context.Context, resource *reflectedResource, local, remote *unstructured.Unstructured) error {
+	// Retrieve the status of the local and remote objects
+	statusLocal, err := r.getNestedMap(local, statusKey, resource.gvr)
+	utilruntime.Must(err)
+
+	statusRemote, err := r.getNestedMap(remote, statusKey, resource.gvr)
+	utilruntime.Must(err)
+
 	switch resource.ownership {
 	case consts.OwnershipLocal:
-		return r.updateObjectStatusInner(ctx, r.remoteClient, r.remoteNamespace, resource.gvr, local, remote)
+		return r.updateObjectStatusInner(ctx, r.remoteClient, r.remoteNamespace, resource.gvr, statusLocal, remote)
 	case consts.OwnershipShared:
-		return r.updateObjectStatusInner(ctx, r.manager.client, r.localNamespace, resource.gvr, remote, local)
+		// The status is owned by the remote cluster, except for the fields explicitly owned by the local one (if any).
+		status := mergeStatusFields(statusRemote, statusLocal, resource.localStatusFields)
+		if err := r.updateObjectStatusInner(ctx, r.manager.client, r.localNamespace, resource.gvr, status, local); err != nil {
+			return err
+		}
+		if len(resource.localStatusFields) > 0 {
+			return r.updateObjectStatusInner(ctx, r.remoteClient, r.remoteNamespace, resource.gvr, status, remote)
+		}
 	default:
 		klog.Fatalf("Unknown ownership %v", resource.ownership)
 	}
 	return nil
 }
 
-// updateObjectStatusInner performs the actual status update.
+// updateObjectStatusInner performs the actual status update, if the status of the destination object differs from the desired one.
 func (r *Reflector) updateObjectStatusInner(ctx context.Context, cl dynamic.Interface, namespace string,
-	gvr schema.GroupVersionResource, source, destination *unstructured.Unstructured) error {
-	// Retrieve the status of the source and destination objects
-	statusSource, err := r.getNestedMap(source, statusKey, gvr)
-	utilruntime.Must(err)
-
+	gvr schema.GroupVersionResource, status map[string]interface{}, destination *unstructured.Unstructured) error {
+	// Retrieve the status of the destination object
 	statusDestination, err := r.getNestedMap(destination, statusKey, gvr)
 	utilruntime.Must(err)
 
 	// The statuses are already the same, nothing to do
-	if reflect.DeepEqual(statusSource, statusDestination) {
+	if reflect.DeepEqual(status, statusDestination) {
 		return nil
 	}
 
-	// Update the local status field
-	err = unstructured.SetNestedMap(destination.Object, statusSource, statusKey)
+	// Update the destination status field
+	err = unstructured.SetNestedMap(destination.Object, status, statusKey)
 	utilruntime.Must(err)
 
 	// Update the resource in the destination cluster
 	if _, err = cl.Resource(gvr).Namespace(namespace).UpdateStatus(ctx, destination, metav1.UpdateOptions{}); err != nil {
-		klog.Errorf("[%v] Failed to update the status of %v with name %v: %v", r.remoteClusterID, gvr, source.GetName(), err)
+		klog.Errorf("[%v] Failed to update the status of %v with name %v: %v", r.remoteClusterID, gvr, destination.GetName(), err)
 		return err
 	}
 
-	klog.Infof("[%v] Status of %v with name %v successfully updated", r.remoteClusterID, gvr, source.GetName())
+	klog.Infof("[%v] Status of %v with name %v successfully updated", r.remoteClusterID, gvr, destination.GetName())
 	return nil
 }
 
+// mergeStatusFields returns a copy of the base status, with the given fields replaced by the ones of the override status.
+func mergeStatusFields(base, override map[string]interface{}, fields []string) map[string]interface{} {
+	if len(fields) == 0 {
+		return base
+	}
+
+	merged := runtime.DeepCopyJSON(base)
+	for _, field := range fields {
+		if value, found := override[field]; found {
+			merged[field] = runtime.DeepCopyJSONValue(value)
+		} else {
+			delete(merged, field)
+		}
+	}
+	return merged
+}
+
 // deleteRemoteObject deletes a given object from the remote cluster.
 func (r *Reflector) deleteRemoteObject(ctx context.Context, resource *reflectedResource, key item) (vanished bool, err error) {
 	if _, err := resource.remote.Get(key.name); err != nil {
@@ -51,8 +51,9 @@ var _ = Describe("Handler tests", func() {
 		localCluster  discoveryv1alpha1.ClusterIdentity
 		remoteCluster discoveryv1alpha1.ClusterIdentity
 
-		gvr       schema.GroupVersionResource
-		ownership consts.OwnershipType
+		gvr               schema.GroupVersionResource
+		ownership         consts.OwnershipType
+		localStatusFields []string
 
 		reflector                 Reflector
 		local, remote             dynamic.Interface
@@ -75,6 +76,7 @@ var _ = Describe("Handler tests", func() {
 		ctx, cancel = context.WithCancel(context.Background())
 		gvr = netv1alpha1.NetworkConfigGroupVersionResource
 		ownership = consts.OwnershipLocal
+		localStatusFields = nil
 
 		// Fill with fake data, to avoid issues if not overwritten later with real parameters
 		localBefore = netv1alpha1.NetworkConfig{
@@ -117,10 +119,11 @@ var _ = Describe("Handler tests", func() {
 
 			resources: map[schema.GroupVersionResource]*reflectedResource{
 				gvr: {
-					gvr:       gvr,
-					ownership: ownership,
-					local:     Lister(ctx, local, localNamespace, gvr),
-					remote:    Lister(ctx, remote, remoteNamespace, gvr),
+					gvr:               gvr,
+					ownership:         ownership,
+					localStatusFields: localStatusFields,
+					local:             Lister(ctx, local, localNamespace, gvr),
+					remote:            Lister(ctx, remote, remoteNamespace, gvr),
 				},
 			},
 		}
@@ -250,6 +253,19 @@ var _ = Describe("Handler tests", func() {
 						Expect(localAfter.Status).To(Equal(remoteBefore.Status))
 					})
 				})
+				Context("Shared ownership with locally owned status fields", func() {
+					BeforeEach(func() {
+						ownership = consts.OwnershipShared
+						localStatusFields = []string{"externalCIDRNAT"}
+						localBefore.Status.ExternalCIDRNAT = "30.30.0.0/16"
+					})
+					It("the status should have been merged in both the local and the remote object", func() {
+						expected := remoteBefore.Status
+						expected.ExternalCIDRNAT = localBefore.Status.ExternalCIDRNAT
+						Expect(remoteAfter.Status).To(Equal(expected))
+						Expect(localAfter.Status).To(Equal(expected))
+					})
+				})
 			}
 		}
 
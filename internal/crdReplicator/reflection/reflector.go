@@ -60,8 +60,9 @@ type Reflector struct {
 
 // reflectedResource wraps the listers associated with a reflected resource.
 type reflectedResource struct {
-	gvr       schema.GroupVersionResource
-	ownership consts.OwnershipType
+	gvr               schema.GroupVersionResource
+	ownership         consts.OwnershipType
+	localStatusFields []string
 
 	local  cache.GenericNamespaceLister
 	remote cache.GenericNamespaceLister
@@ -128,8 +129,9 @@ func (r *Reflector) StartForResource(ctx context.Context, resource *resources.Re
 
 	ctx, cancel := context.WithCancel(ctx)
 	r.resources[gvr] = &reflectedResource{
-		gvr:       gvr,
-		ownership: resource.Ownership,
+		gvr:               gvr,
+		ownership:         resource.Ownership,
+		localStatusFields: resource.LocalStatusFields,
 
 		local:  r.manager.listers[gvr].ByNamespace(r.localNamespace),
 		remote: informer.Lister().ByNamespace(r.remoteNamespace),
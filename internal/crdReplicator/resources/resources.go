@@ -33,6 +33,9 @@ type Resource struct {
 	PeeringPhase consts.PeeringPhase
 	// Ownership indicates the ownership over this resource.
 	Ownership consts.OwnershipType
+	// LocalStatusFields contains the status fields owned by the local cluster (i.e., the one originating the resource),
+	// which are replicated towards the remote cluster also in case of shared ownership.
+	LocalStatusFields []string
 }
 
 // GetResourcesToReplicate returns the list of resources to be replicated through the CRD replicator.
@@ -47,6 +50,7 @@ func GetResourcesToReplicate() []Resource {
 			GroupVersionResource: sharingv1alpha1.ResourceOfferGroupVersionResource,
 			PeeringPhase:         consts.PeeringPhaseIncoming,
 			Ownership:            consts.OwnershipShared,
+			LocalStatusFields:    []string{"used"},
 		},
 		{
 			GroupVersionResource: netv1alpha1.NetworkConfigGroupVersionResource,
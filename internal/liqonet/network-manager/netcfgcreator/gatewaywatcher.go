@@ -0,0 +1,173 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package netcfgcreator
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	liqoconst "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqonet/sharding"
+	liqolabels "github.com/liqotech/liqo/pkg/utils/labels"
+)
+
+// GatewayWatcher reconciles the gateway Pod objects to retrieve the tunnel endpoint of each replica,
+// when the gateway is configured in active/active mode.
+type GatewayWatcher struct {
+	sync.RWMutex
+	pods  map[string]*corev1.Pod
+	ports map[string]string
+	ring  *sharding.Ring
+
+	configured bool
+	wait       chan struct{}
+
+	enqueuefn func(workqueue.RateLimitingInterface)
+}
+
+// NewGatewayWatcher returns a new initialized GatewayWatcher instance.
+func NewGatewayWatcher(enqueuefn func(workqueue.RateLimitingInterface)) *GatewayWatcher {
+	return &GatewayWatcher{
+		pods:  map[string]*corev1.Pod{},
+		ports: map[string]string{},
+		ring:  sharding.NewRing(nil),
+
+		configured: false,
+		wait:       make(chan struct{}),
+
+		enqueuefn: enqueuefn,
+	}
+}
+
+// Endpoint returns the endpoint information (IP/port) of the gateway replica in charge of the given remote cluster.
+func (gw *GatewayWatcher) Endpoint(clusterID string) (ip, port string, found bool) {
+	gw.RLock()
+	defer gw.RUnlock()
+
+	ip = gw.ring.Owner(clusterID)
+	if ip == "" {
+		return "", "", false
+	}
+	return ip, gw.ports[ip], true
+}
+
+// WaitForConfigured waits until at least one gateway replica is ready for the first time.
+func (gw *GatewayWatcher) WaitForConfigured(ctx context.Context) bool {
+	gw.RLock()
+
+	if !gw.configured {
+		gw.RUnlock()
+		klog.Info("Waiting for the configuration of the gateway watcher")
+
+		select {
+		case <-gw.wait:
+			klog.Info("Gateway watcher correctly configured")
+			return true
+		case <-ctx.Done():
+			klog.Warning("Context expired before configuring the gateway watcher")
+			return false
+		}
+	}
+
+	gw.RUnlock()
+	return true
+}
+
+// Handlers returns the set of handlers used for the Watch configuration.
+func (gw *GatewayWatcher) Handlers() handler.EventHandler {
+	return handler.Funcs{
+		CreateFunc: func(ce event.CreateEvent, rli workqueue.RateLimitingInterface) {
+			pod := ce.Object.(*corev1.Pod)
+			gw.handle(pod.GetName(), pod, rli)
+		},
+		UpdateFunc: func(ue event.UpdateEvent, rli workqueue.RateLimitingInterface) {
+			pod := ue.ObjectNew.(*corev1.Pod)
+			gw.handle(pod.GetName(), pod, rli)
+		},
+		DeleteFunc: func(de event.DeleteEvent, rli workqueue.RateLimitingInterface) {
+			gw.handle(de.Object.GetName(), nil, rli)
+		},
+	}
+}
+
+// Predicates returns the set of predicates used for the Watch configuration.
+func (gw *GatewayWatcher) Predicates() predicate.Predicate {
+	podsPredicate, err := predicate.LabelSelectorPredicate(liqolabels.GatewayPodLabelSelector)
+	utilruntime.Must(err)
+
+	return podsPredicate
+}
+
+// handle processes creation, update and deletion events of a gateway Pod object (nil in case of deletion).
+func (gw *GatewayWatcher) handle(name string, pod *corev1.Pod, rli workqueue.RateLimitingInterface) {
+	klog.V(4).Infof("Handling gateway Pod %q", name)
+
+	gw.Lock()
+	defer gw.Unlock()
+
+	if pod == nil {
+		delete(gw.pods, name)
+	} else {
+		gw.pods[name] = pod.DeepCopy()
+	}
+
+	pods := make([]corev1.Pod, 0, len(gw.pods))
+	ports := make(map[string]string, len(gw.pods))
+	for _, p := range gw.pods {
+		pods = append(pods, *p)
+		if sharding.IsMember(p) {
+			ports[p.Status.PodIP] = gatewayListeningPort(p)
+		}
+	}
+	members := sharding.Members(pods)
+
+	// The set of replicas did not change, nothing to do
+	if reflect.DeepEqual(members, gw.ring.Members()) && reflect.DeepEqual(ports, gw.ports) {
+		return
+	}
+
+	klog.Infof("Gateway replicas eligible to carry tunnels: %v", members)
+	gw.ring = sharding.NewRing(members)
+	gw.ports = ports
+	if !gw.configured && len(members) > 0 {
+		close(gw.wait)
+		gw.configured = true
+	}
+
+	// Enqueue all foreign clusters for update (which in turn update the respective network configs)
+	gw.enqueuefn(rli)
+}
+
+// gatewayListeningPort returns the port the given gateway pod is listening on for incoming tunnel connections.
+func gatewayListeningPort(pod *corev1.Pod) string {
+	for i := range pod.Spec.Containers {
+		for _, port := range pod.Spec.Containers[i].Ports {
+			if port.Name == liqoconst.DriverName {
+				return strconv.Itoa(int(port.ContainerPort))
+			}
+		}
+	}
+	return strconv.Itoa(liqoconst.GatewayListeningPort)
+}
@@ -0,0 +1,123 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package netcfgcreator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+)
+
+var _ = Describe("Gateway Watcher functions", func() {
+	var (
+		handled int
+
+		gw *GatewayWatcher
+	)
+
+	forgePod := func(name, ip string, ready bool) *corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "liqo"},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Ports: []corev1.ContainerPort{{Name: "wireguard", ContainerPort: 9999}},
+			}}},
+			Status: corev1.PodStatus{PodIP: ip, Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}}},
+		}
+	}
+
+	BeforeEach(func() {
+		handled = 0
+		gw = NewGatewayWatcher(func(rli workqueue.RateLimitingInterface) { handled++ })
+	})
+
+	Describe("The handle function", func() {
+		When("no gateway replica is ready", func() {
+			BeforeEach(func() { gw.handle("foo", forgePod("foo", "10.0.0.1", false), nil) })
+
+			It("should not execute the handle function", func() { Expect(handled).To(BeZero()) })
+			It("should not be initialized", func() { Expect(gw.configured).To(BeFalse()) })
+			It("should not return any endpoint", func() {
+				_, _, found := gw.Endpoint("cluster")
+				Expect(found).To(BeFalse())
+			})
+		})
+
+		When("a gateway replica becomes ready", func() {
+			BeforeEach(func() { gw.handle("foo", forgePod("foo", "10.0.0.1", true), nil) })
+
+			It("should execute the handle function", func() { Expect(handled).To(Equal(1)) })
+			It("should be initialized", func() { Expect(gw.configured).To(BeTrue()) })
+			It("should return the endpoint of the replica", func() {
+				ip, port, found := gw.Endpoint("cluster")
+				Expect(found).To(BeTrue())
+				Expect(ip).To(BeIdenticalTo("10.0.0.1"))
+				Expect(port).To(BeIdenticalTo("9999"))
+			})
+
+			When("the same replica is updated without membership changes", func() {
+				BeforeEach(func() { gw.handle("foo", forgePod("foo", "10.0.0.1", true), nil) })
+				It("should not execute again the handle function", func() { Expect(handled).To(Equal(1)) })
+			})
+
+			When("the replica is deleted", func() {
+				BeforeEach(func() { gw.handle("foo", nil, nil) })
+				It("should execute again the handle function", func() { Expect(handled).To(Equal(2)) })
+				It("should not return any endpoint", func() {
+					_, _, found := gw.Endpoint("cluster")
+					Expect(found).To(BeFalse())
+				})
+			})
+		})
+
+		When("multiple gateway replicas are ready", func() {
+			BeforeEach(func() {
+				gw.handle("foo", forgePod("foo", "10.0.0.1", true), nil)
+				gw.handle("bar", forgePod("bar", "10.0.0.2", true), nil)
+			})
+
+			It("should distribute the remote clusters among them", func() {
+				owners := map[string]struct{}{}
+				for i := 0; i < 100; i++ {
+					ip, _, found := gw.Endpoint(fmt.Sprintf("cluster-%d", i))
+					Expect(found).To(BeTrue())
+					owners[ip] = struct{}{}
+				}
+				Expect(owners).To(HaveLen(2))
+			})
+		})
+	})
+
+	Describe("The WaitForConfigured function", func() {
+		It("should return true once a replica becomes ready", func() {
+			go func() {
+				time.Sleep(10 * time.Millisecond)
+				gw.handle("foo", forgePod("foo", "10.0.0.1", true), nil)
+			}()
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			Expect(gw.WaitForConfigured(ctx)).To(BeTrue())
+		})
+	})
+})
@@ -49,9 +49,17 @@ type NetworkConfigCreator struct {
 	foreignClusters *syncset.SyncSet
 	secretWatcher   *SecretWatcher
 	serviceWatcher  *ServiceWatcher
+	gatewayWatcher  *GatewayWatcher
 
 	PodCIDR      string
 	ExternalCIDR string
+	// PodCIDRv6 and ExternalCIDRv6 are set only in case of dual-stack clusters.
+	PodCIDRv6      string
+	ExternalCIDRv6 string
+	BackendType    string
+	// ActiveActive denotes whether the gateway is configured in active/active mode, hence each remote cluster
+	// shall be advertised the endpoint of the replica in charge of the corresponding tunnel.
+	ActiveActive bool
 }
 
 // cluster-roles
@@ -60,11 +68,12 @@ type NetworkConfigCreator struct {
 // roles
 // +kubebuilder:rbac:groups=core,namespace="do-not-care",resources=secrets,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,namespace="do-not-care",resources=services,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,namespace="do-not-care",resources=pods,verbs=get;list;watch
 
 // Reconcile reconciles the state of ForeignCluster resources to enforce the respective NetworkConfigs.
 func (ncc *NetworkConfigCreator) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	// Wait, in case the configuration has not completed yet.
-	if !ncc.secretWatcher.WaitForConfigured(ctx) || !ncc.serviceWatcher.WaitForConfigured(ctx) {
+	if !ncc.secretWatcher.WaitForConfigured(ctx) || !ncc.endpointWatcherConfigured(ctx) {
 		return ctrl.Result{}, errors.New("context expired before initialization completed")
 	}
 
@@ -109,6 +118,16 @@ func (ncc *NetworkConfigCreator) Reconcile(ctx context.Context, req ctrl.Request
 	return ctrl.Result{}, ncc.EnforceNetworkConfigAbsence(ctx, &fc)
 }
 
+// endpointWatcherConfigured waits until the watcher providing the tunnel endpoint information is configured.
+// In active/active mode, the endpoint is the one of the gateway replica in charge of the remote cluster,
+// hence the gateway service (which is not annotated with a specific node IP) is not considered.
+func (ncc *NetworkConfigCreator) endpointWatcherConfigured(ctx context.Context) bool {
+	if ncc.ActiveActive {
+		return ncc.gatewayWatcher.WaitForConfigured(ctx)
+	}
+	return ncc.serviceWatcher.WaitForConfigured(ctx)
+}
+
 // SetupWithManager registers a new controller for ForeignCluster resources.
 func (ncc *NetworkConfigCreator) SetupWithManager(mgr ctrl.Manager) error {
 	enqueuefn := func(rli workqueue.RateLimitingInterface) {
@@ -116,17 +135,24 @@ func (ncc *NetworkConfigCreator) SetupWithManager(mgr ctrl.Manager) error {
 	}
 
 	ncc.foreignClusters = syncset.New()
-	ncc.secretWatcher = NewSecretWatcher(enqueuefn)
+	ncc.secretWatcher = NewSecretWatcher(ncc.backendType(), enqueuefn)
 	ncc.serviceWatcher = NewServiceWatcher(enqueuefn)
+	ncc.gatewayWatcher = NewGatewayWatcher(enqueuefn)
 
 	localNetcfg, err := predicate.LabelSelectorPredicate(reflection.LocalResourcesLabelSelector())
 	utilruntime.Must(err)
 
-	return ctrl.NewControllerManagedBy(mgr).
+	ctrlBuilder := ctrl.NewControllerManagedBy(mgr).
 		For(&discoveryv1alpha1.ForeignCluster{}).
 		Owns(&netv1alpha1.NetworkConfig{}, builder.WithPredicates(
 			predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}), localNetcfg)).
 		Watches(&source.Kind{Type: &corev1.Secret{}}, ncc.secretWatcher.Handlers(), builder.WithPredicates(ncc.secretWatcher.Predicates())).
-		Watches(&source.Kind{Type: &corev1.Service{}}, ncc.serviceWatcher.Handlers(), builder.WithPredicates(ncc.serviceWatcher.Predicates())).
-		Complete(ncc)
+		Watches(&source.Kind{Type: &corev1.Service{}}, ncc.serviceWatcher.Handlers(), builder.WithPredicates(ncc.serviceWatcher.Predicates()))
+
+	if ncc.ActiveActive {
+		ctrlBuilder = ctrlBuilder.Watches(&source.Kind{Type: &corev1.Pod{}}, ncc.gatewayWatcher.Handlers(),
+			builder.WithPredicates(ncc.gatewayWatcher.Predicates()))
+	}
+
+	return ctrlBuilder.Complete(ncc)
 }
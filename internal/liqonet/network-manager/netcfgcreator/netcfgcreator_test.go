@@ -74,8 +74,8 @@ var _ = Describe("NetworkConfigCreator Controller", func() {
 
 			foreignClusters: syncset.New(),
 			secretWatcher: &SecretWatcher{
-				wiregardPublicKey: "public-key",
-				configured:        true,
+				publicKey:  "public-key",
+				configured: true,
 			},
 			serviceWatcher: &ServiceWatcher{
 				endpointIP:   "1.1.1.1",
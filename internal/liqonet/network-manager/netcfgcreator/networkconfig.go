@@ -23,7 +23,6 @@ import (
 
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -142,7 +141,10 @@ func (ncc *NetworkConfigCreator) createNetworkConfig(ctx context.Context, fc *di
 			Namespace: fc.Status.TenantNamespace.Local,
 		},
 	}
-	utilruntime.Must(ncc.populateNetworkConfig(&netcfg, fc))
+	if err := ncc.populateNetworkConfig(&netcfg, fc); err != nil {
+		klog.Errorf("An error occurred while creating NetworkConfig %q: %v", klog.KObj(&netcfg), err)
+		return err
+	}
 
 	if err := ncc.Create(ctx, &netcfg); err != nil {
 		klog.Errorf("An error occurred while creating NetworkConfig: %v", err)
@@ -186,19 +188,33 @@ func (ncc *NetworkConfigCreator) populateNetworkConfig(netcfg *netv1alpha1.Netwo
 	netcfg.Labels[consts.LocalResourceOwnership] = componentName
 	netcfg.Labels[consts.ReplicationDestinationLabel] = clusterIdentity.ClusterID
 
-	wgEndpointIP, wgEndpointPort := ncc.serviceWatcher.WiregardEndpoint()
+	var wgEndpointIP, wgEndpointPort string
+	if ncc.ActiveActive {
+		// Advertise the endpoint of the gateway replica in charge of the tunnel towards the given remote cluster.
+		var found bool
+		if wgEndpointIP, wgEndpointPort, found = ncc.gatewayWatcher.Endpoint(clusterIdentity.ClusterID); !found {
+			return fmt.Errorf("no gateway replica available for remote cluster %q", clusterIdentity.ClusterID)
+		}
+	} else {
+		wgEndpointIP, wgEndpointPort = ncc.serviceWatcher.WiregardEndpoint()
+	}
 
 	netcfg.Spec.RemoteCluster = fc.Spec.ClusterIdentity
 	netcfg.Spec.PodCIDR = ncc.PodCIDR
 	netcfg.Spec.ExternalCIDR = ncc.ExternalCIDR
+	netcfg.Spec.PodCIDRv6 = ncc.PodCIDRv6
+	netcfg.Spec.ExternalCIDRv6 = ncc.ExternalCIDRv6
 	netcfg.Spec.EndpointIP = wgEndpointIP
-	netcfg.Spec.BackendType = consts.DriverName
+	netcfg.Spec.BackendType = ncc.backendType()
 
 	if netcfg.Spec.BackendConfig == nil {
 		netcfg.Spec.BackendConfig = map[string]string{}
 	}
-	netcfg.Spec.BackendConfig[consts.PublicKey] = ncc.secretWatcher.WiregardPublicKey()
+	netcfg.Spec.BackendConfig[consts.PublicKey] = ncc.secretWatcher.PublicKey()
 	netcfg.Spec.BackendConfig[consts.ListeningPort] = wgEndpointPort
+	if nonce := ncc.secretWatcher.Nonce(); nonce != "" {
+		netcfg.Spec.BackendConfig[consts.IPsecNonce] = nonce
+	}
 
 	return controllerutil.SetControllerReference(fc, netcfg, ncc.Scheme)
 }
@@ -232,3 +248,11 @@ func (ncc *NetworkConfigCreator) EnforceNetworkConfigAbsence(ctx context.Context
 	klog.Errorf("Correctly ensured no NetworkConfigs associated with cluster %s are present", clusterIdentity)
 	return nil
 }
+
+// backendType returns the configured tunnel backend, defaulting to wireguard if unset.
+func (ncc *NetworkConfigCreator) backendType() string {
+	if ncc.BackendType == "" {
+		return consts.DriverName
+	}
+	return ncc.BackendType
+}
@@ -62,7 +62,7 @@ var _ = Describe("Network config functions", func() {
 			PodCIDR:      "192.168.0.0/24",
 			ExternalCIDR: "192.168.1.0/24",
 
-			secretWatcher:  &SecretWatcher{wiregardPublicKey: "public-key"},
+			secretWatcher:  &SecretWatcher{publicKey: "public-key"},
 			serviceWatcher: &ServiceWatcher{endpointIP: "1.1.1.1", endpointPort: "9999"},
 		}
 	})
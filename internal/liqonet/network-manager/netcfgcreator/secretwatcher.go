@@ -16,6 +16,7 @@ package netcfgcreator
 
 import (
 	"context"
+	"fmt"
 	"sync"
 
 	corev1 "k8s.io/api/core/v1"
@@ -27,14 +28,17 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqonet/tunnel/ipsec"
 	"github.com/liqotech/liqo/pkg/utils/getters"
 	liqolabels "github.com/liqotech/liqo/pkg/utils/labels"
 )
 
-// SecretWatcher reconciles Secret objects to retrieve the Wireguard public key.
+// SecretWatcher reconciles Secret objects to retrieve the public key (and the nonce, if any) of the configured tunnel backend.
 type SecretWatcher struct {
 	sync.RWMutex
-	wiregardPublicKey string
+	backendType string
+	publicKey   string
+	nonce       string
 
 	configured bool
 	wait       chan struct{}
@@ -42,22 +46,31 @@ type SecretWatcher struct {
 	enqueuefn func(workqueue.RateLimitingInterface)
 }
 
-// NewSecretWatcher returns a new initialized SecretWatcher instance.
-func NewSecretWatcher(enqueuefn func(workqueue.RateLimitingInterface)) *SecretWatcher {
+// NewSecretWatcher returns a new initialized SecretWatcher instance, for the given tunnel backend.
+func NewSecretWatcher(backendType string, enqueuefn func(workqueue.RateLimitingInterface)) *SecretWatcher {
 	return &SecretWatcher{
-		configured: false,
-		wait:       make(chan struct{}),
+		backendType: backendType,
+		configured:  false,
+		wait:        make(chan struct{}),
 
 		enqueuefn: enqueuefn,
 	}
 }
 
-// WiregardPublicKey returns the retrieved Wireguard public key.
-func (sw *SecretWatcher) WiregardPublicKey() string {
+// PublicKey returns the retrieved public key of the tunnel backend.
+func (sw *SecretWatcher) PublicKey() string {
 	sw.RLock()
 	defer sw.RUnlock()
 
-	return sw.wiregardPublicKey
+	return sw.publicKey
+}
+
+// Nonce returns the retrieved nonce of the tunnel backend (if any).
+func (sw *SecretWatcher) Nonce() string {
+	sw.RLock()
+	defer sw.RUnlock()
+
+	return sw.nonce
 }
 
 // WaitForConfigured waits until a valid key is retrieved for the first time.
@@ -98,7 +111,7 @@ func (sw *SecretWatcher) Handlers() handler.EventHandler {
 
 // Predicates returns the set of predicates used for the Watch configuration.
 func (sw *SecretWatcher) Predicates() predicate.Predicate {
-	secretsPredicate, err := predicate.LabelSelectorPredicate(liqolabels.WireGuardSecretLabelSelector)
+	secretsPredicate, err := predicate.LabelSelectorPredicate(liqolabels.TunnelSecretLabelSelector(sw.backendType))
 	utilruntime.Must(err)
 
 	return secretsPredicate
@@ -111,20 +124,26 @@ func (sw *SecretWatcher) handle(secret *corev1.Secret, rli workqueue.RateLimitin
 	sw.Lock()
 	defer sw.Unlock()
 
-	pubKey, err := getters.RetrieveWGPubKeyFromSecret(secret, consts.PublicKey)
+	pubKey, err := sw.retrievePublicKey(secret)
+	if err != nil {
+		klog.Error(err)
+		return
+	}
+	nonce, err := sw.retrieveNonce(secret)
 	if err != nil {
 		klog.Error(err)
 		return
 	}
 
-	// The key did not change, nothing to do
-	if pubKey.String() == sw.wiregardPublicKey {
+	// Neither the key nor the nonce changed, nothing to do
+	if pubKey == sw.publicKey && nonce == sw.nonce {
 		return
 	}
 
 	// Configure the new key, and set as configured if not yet done
-	klog.Infof("Wiregard public key correctly retrieved")
-	sw.wiregardPublicKey = pubKey.String()
+	klog.Infof("%s public key correctly retrieved", sw.backendType)
+	sw.publicKey = pubKey
+	sw.nonce = nonce
 	if !sw.configured {
 		close(sw.wait)
 		sw.configured = true
@@ -133,3 +152,39 @@ func (sw *SecretWatcher) handle(secret *corev1.Secret, rli workqueue.RateLimitin
 	// Enqueue all foreign clusters for update (which in turn update the respective network configs)
 	sw.enqueuefn(rli)
 }
+
+// retrievePublicKey retrieves and validates the public key of the tunnel backend from the given secret.
+func (sw *SecretWatcher) retrievePublicKey(secret *corev1.Secret) (string, error) {
+	if sw.backendType == consts.IPsecDriverName {
+		pubKey, found := secret.Data[consts.PublicKey]
+		if !found {
+			return "", fmt.Errorf("no data with key %s found in secret %q", consts.PublicKey, klog.KObj(secret))
+		}
+		if _, err := ipsec.ParsePublicKey(string(pubKey)); err != nil {
+			return "", fmt.Errorf("secret %q: invalid public key: %w", klog.KObj(secret), err)
+		}
+		return string(pubKey), nil
+	}
+
+	pubKey, err := getters.RetrieveWGPubKeyFromSecret(secret, consts.PublicKey)
+	if err != nil {
+		return "", err
+	}
+	return pubKey.String(), nil
+}
+
+// retrieveNonce retrieves and validates the nonce of the tunnel backend (if required) from the given secret.
+func (sw *SecretWatcher) retrieveNonce(secret *corev1.Secret) (string, error) {
+	if sw.backendType != consts.IPsecDriverName {
+		return "", nil
+	}
+
+	nonce, found := secret.Data[consts.IPsecNonce]
+	if !found {
+		return "", fmt.Errorf("no data with key %s found in secret %q", consts.IPsecNonce, klog.KObj(secret))
+	}
+	if _, err := ipsec.ParseNonce(string(nonce)); err != nil {
+		return "", fmt.Errorf("secret %q: invalid nonce: %w", klog.KObj(secret), err)
+	}
+	return string(nonce), nil
+}
@@ -37,7 +37,7 @@ var _ = Describe("Secret Watcher functions", func() {
 
 	BeforeEach(func() {
 		handled = make(chan struct{})
-		sw = NewSecretWatcher(func(rli workqueue.RateLimitingInterface) { close(handled) })
+		sw = NewSecretWatcher(consts.DriverName, func(rli workqueue.RateLimitingInterface) { close(handled) })
 		secret = corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "bar"}}
 	})
 
@@ -52,29 +52,64 @@ var _ = Describe("Secret Watcher functions", func() {
 			})
 
 			When("not yet initialized", func() {
-				It("should retrieve the correct public key", func() { Expect(sw.WiregardPublicKey()).To(BeIdenticalTo(key)) })
+				It("should retrieve the correct public key", func() { Expect(sw.PublicKey()).To(BeIdenticalTo(key)) })
 				It("should execute the handle function", func() { Expect(handled).To(BeClosed()) })
 				It("should be initialized", func() { Expect(sw.configured).To(BeTrue()) })
 			})
 
 			When("already initialized", func() {
 				BeforeEach(func() {
-					sw.wiregardPublicKey = "previous-public-key"
+					sw.publicKey = "previous-public-key"
 					sw.configured = true
 				})
 
-				It("should retrieve the correct public key", func() { Expect(sw.WiregardPublicKey()).To(BeIdenticalTo(key)) })
+				It("should retrieve the correct public key", func() { Expect(sw.PublicKey()).To(BeIdenticalTo(key)) })
 				It("should execute the handle function", func() { Expect(handled).To(BeClosed()) })
 				It("should be initialized", func() { Expect(sw.configured).To(BeTrue()) })
 			})
 		})
 
+		When("the IPsec backend is configured", func() {
+			const (
+				ipsecKey = "BGsX0fLhLEJH+Lzm5WOkQPJ3A32BLeszoPShOUXYmMKWT+NC4v4af5uO5+tKfA+eFivOM1drMV7Oy7ZAaDe/UfU="
+				nonce    = "AAECAwQFBgcICQoLDA0ODw=="
+			)
+
+			BeforeEach(func() {
+				sw = NewSecretWatcher(consts.IPsecDriverName, func(rli workqueue.RateLimitingInterface) { close(handled) })
+				secret.Data = map[string][]byte{consts.PublicKey: []byte(ipsecKey), consts.IPsecNonce: []byte(nonce)}
+			})
+
+			When("the secret is valid", func() {
+				It("should retrieve the correct public key", func() { Expect(sw.PublicKey()).To(BeIdenticalTo(ipsecKey)) })
+				It("should retrieve the correct nonce", func() { Expect(sw.Nonce()).To(BeIdenticalTo(nonce)) })
+				It("should execute the handle function", func() { Expect(handled).To(BeClosed()) })
+			})
+
+			When("only the nonce changed", func() {
+				BeforeEach(func() {
+					sw.publicKey = ipsecKey
+					sw.nonce = "previous-nonce"
+					sw.configured = true
+				})
+
+				It("should retrieve the correct nonce", func() { Expect(sw.Nonce()).To(BeIdenticalTo(nonce)) })
+				It("should execute the handle function", func() { Expect(handled).To(BeClosed()) })
+			})
+
+			When("the nonce is missing", func() {
+				BeforeEach(func() { delete(secret.Data, consts.IPsecNonce) })
+				It("should leave the public key unmodified", func() { Expect(sw.PublicKey()).To(BeIdenticalTo("")) })
+				It("should not execute the handle function", func() { Expect(handled).ToNot(BeClosed()) })
+			})
+		})
+
 		When("given an invalid secret", func() {
 			BeforeEach(func() {
 				secret.Data = map[string][]byte{"incorrect-key": []byte(key)}
 			})
 
-			It("should leave the public key unmodified", func() { Expect(sw.WiregardPublicKey()).To(BeIdenticalTo("")) })
+			It("should leave the public key unmodified", func() { Expect(sw.PublicKey()).To(BeIdenticalTo("")) })
 			It("should not execute the handle function", func() { Expect(handled).ToNot(BeClosed()) })
 			It("should not be initialized", func() { Expect(sw.configured).To(BeFalse()) })
 		})
@@ -59,6 +59,16 @@ type networkParam struct {
 	localNatExternalCIDR  string
 	backendType           string
 	backendConfig         map[string]string
+
+	// IPv6 networks, set only if both clusters are dual-stack.
+	remotePodCIDRv6         string
+	remoteNatPodCIDRv6      string
+	remoteExternalCIDRv6    string
+	remoteNatExternalCIDRv6 string
+	localNatPodCIDRv6       string
+	localPodCIDRv6          string
+	localExternalCIDRv6     string
+	localNatExternalCIDRv6  string
 }
 
 // TunnelEndpointCreator manages the most of liqo networking.
@@ -78,6 +88,8 @@ type TunnelEndpointCreator struct {
 // +kubebuilder:rbac:groups=discovery.liqo.io,resources=foreignclusters,verbs=get;list;watch
 // +kubebuilder:rbac:groups=discovery.liqo.io,resources=foreignclusters/status;foreignclusters/finalizers,verbs=get;update;patch
 // +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=list
+// +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=list
 
 // Reconcile reconciles the state of NetworkConfig resources.
 func (tec *TunnelEndpointCreator) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -242,6 +254,12 @@ func (tec *TunnelEndpointCreator) processNetworkConfig(ctx context.Context, clus
 		klog.Errorf("Failed to add local subnets to IPAM for cluster %s: %v", local.Spec.RemoteCluster, err)
 		return err
 	}
+	if local.Status.PodCIDRNATv6 != "" && local.Status.ExternalCIDRNATv6 != "" {
+		if err := tec.IPManager.AddLocalSubnetsPerClusterV6(local.Status.PodCIDRNATv6, local.Status.ExternalCIDRNATv6, clusterID); err != nil {
+			klog.Errorf("Failed to add local IPv6 subnets to IPAM for cluster %s: %v", local.Spec.RemoteCluster, err)
+			return err
+		}
+	}
 	tracer.Step("IPAM configuration")
 
 	// If we reached this point, then it is possible to enforce the TunnelEndpoint resource
@@ -296,11 +314,29 @@ func (tec *TunnelEndpointCreator) enforceRemoteNetConfigStatus(ctx context.Conte
 		externalCIDR = liqoconst.DefaultCIDRValue
 	}
 
+	// Get the IPv6 CIDR remappings, in case the remote cluster is dual-stack
+	var podCIDRv6, externalCIDRv6 string
+	if netcfg.Spec.PodCIDRv6 != "" && netcfg.Spec.ExternalCIDRv6 != "" {
+		podCIDRv6, externalCIDRv6, err = tec.IPManager.GetSubnetsPerClusterV6(netcfg.Spec.PodCIDRv6, netcfg.Spec.ExternalCIDRv6, clusterID)
+		if err != nil {
+			klog.Errorf("An error occurred while getting a new IPv6 subnet for resource %q: %v", klog.KObj(netcfg), err)
+			return err
+		}
+		if podCIDRv6 == netcfg.Spec.PodCIDRv6 {
+			podCIDRv6 = liqoconst.DefaultCIDRValue
+		}
+		if externalCIDRv6 == netcfg.Spec.ExternalCIDRv6 {
+			externalCIDRv6 = liqoconst.DefaultCIDRValue
+		}
+	}
+
 	// Update the status fields
 	original := netcfg.Status.DeepCopy()
 	netcfg.Status.Processed = true
 	netcfg.Status.PodCIDRNAT = podCIDR
 	netcfg.Status.ExternalCIDRNAT = externalCIDR
+	netcfg.Status.PodCIDRNATv6 = podCIDRv6
+	netcfg.Status.ExternalCIDRNATv6 = externalCIDRv6
 
 	// Avoid performing updates in case it is not necessary
 	if !reflect.DeepEqual(original, netcfg.Status) {
@@ -333,6 +369,18 @@ func (tec *TunnelEndpointCreator) enforceTunnelEndpoint(ctx context.Context, loc
 		backendConfig:         remote.Spec.BackendConfig,
 	}
 
+	// The IPv6 networks are configured only in case both clusters are dual-stack
+	if local.Status.PodCIDRNATv6 != "" && remote.Status.PodCIDRNATv6 != "" {
+		param.remotePodCIDRv6 = remote.Spec.PodCIDRv6
+		param.remoteNatPodCIDRv6 = remote.Status.PodCIDRNATv6
+		param.remoteExternalCIDRv6 = remote.Spec.ExternalCIDRv6
+		param.remoteNatExternalCIDRv6 = remote.Status.ExternalCIDRNATv6
+		param.localNatPodCIDRv6 = local.Status.PodCIDRNATv6
+		param.localPodCIDRv6 = local.Spec.PodCIDRv6
+		param.localExternalCIDRv6 = local.Spec.ExternalCIDRv6
+		param.localNatExternalCIDRv6 = local.Status.ExternalCIDRNATv6
+	}
+
 	// Try to get the tunnelEndpoint, which may not exist
 	_, found, err := tec.GetTunnelEndpoint(ctx, param.remoteCluster.ClusterID, local.GetNamespace())
 	tracer.Step("TunnelEndpoint retrieval")
@@ -432,6 +480,14 @@ func (tec *TunnelEndpointCreator) fillTunnelEndpointSpec(tep *netv1alpha1.Tunnel
 	tep.Spec.EndpointIP = param.remoteEndpointIP
 	tep.Spec.BackendType = param.backendType
 	tep.Spec.BackendConfig = param.backendConfig
+	tep.Spec.LocalPodCIDRv6 = param.localPodCIDRv6
+	tep.Spec.LocalExternalCIDRv6 = param.localExternalCIDRv6
+	tep.Spec.LocalNATPodCIDRv6 = param.localNatPodCIDRv6
+	tep.Spec.LocalNATExternalCIDRv6 = param.localNatExternalCIDRv6
+	tep.Spec.RemotePodCIDRv6 = param.remotePodCIDRv6
+	tep.Spec.RemoteNATPodCIDRv6 = param.remoteNatPodCIDRv6
+	tep.Spec.RemoteExternalCIDRv6 = param.remoteExternalCIDRv6
+	tep.Spec.RemoteNATExternalCIDRv6 = param.remoteNatExternalCIDRv6
 }
 
 // GetTunnelEndpoint retrieves the tunnelEndpoint resource related to a cluster.
@@ -0,0 +1,295 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tunneloperator
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/vishvananda/netlink"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
+	liqoconst "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqonet/tunnel"
+	"github.com/liqotech/liqo/pkg/liqonet/tunnel/probe"
+	"github.com/liqotech/liqo/pkg/liqonet/utils"
+)
+
+// HealthConfig contains the parameters of the health probes performed across the vpn tunnels.
+type HealthConfig struct {
+	// ProbeInterval is the interval between consecutive probes. Probing is disabled if zero.
+	ProbeInterval time.Duration
+	// RTTThreshold is the average round-trip time above which the connection is considered degraded.
+	RTTThreshold time.Duration
+	// PacketLossThreshold is the percentage of lost probes above which the connection is considered degraded.
+	PacketLossThreshold int
+	// HandshakeThreshold is the time elapsed since the last handshake above which the connection is considered degraded.
+	HandshakeThreshold time.Duration
+}
+
+// healthEventsBuffer is the size of the buffer of the channel triggering the reconciliation of the tunnel endpoints
+// following the probe results. Events are dropped if the buffer is full, as they are regenerated at the next probe.
+const healthEventsBuffer = 128
+
+// healthReport tracks the last health information reported for a given tunnel endpoint.
+type healthReport struct {
+	timestamp time.Time
+	// degraded is the overall outcome of the health evaluation.
+	degraded bool
+	// probeDegraded is the outcome of the health evaluation, considering only the probe statistics.
+	probeDegraded bool
+}
+
+// evaluateHealth checks whether the given health information exceeds any of the configured thresholds,
+// returning a human-readable message describing the reason in that case.
+func evaluateHealth(health *netv1alpha1.TunnelHealth, config *HealthConfig) (degraded bool, reason string) {
+	if config.PacketLossThreshold > 0 && health.PacketLoss > config.PacketLossThreshold {
+		return true, fmt.Sprintf("VPN connection degraded: packet loss %d%% exceeds the threshold of %d%%",
+			health.PacketLoss, config.PacketLossThreshold)
+	}
+	// The RTT is not meaningful if all the probes have been lost.
+	if config.RTTThreshold > 0 && health.PacketLoss < 100 && health.RTT.Duration > config.RTTThreshold {
+		return true, fmt.Sprintf("VPN connection degraded: round-trip time %v exceeds the threshold of %v",
+			health.RTT.Duration, config.RTTThreshold)
+	}
+	if config.HandshakeThreshold > 0 && health.LastHandshakeAge != nil && health.LastHandshakeAge.Duration > config.HandshakeThreshold {
+		return true, fmt.Sprintf("VPN connection degraded: last handshake %v ago exceeds the threshold of %v",
+			health.LastHandshakeAge.Duration, config.HandshakeThreshold)
+	}
+	return false, ""
+}
+
+// forgeTunnelHealth forges the health information starting from the statistics of the probes.
+func forgeTunnelHealth(stats *probe.Stats, lastHandshake time.Time, now time.Time) *netv1alpha1.TunnelHealth {
+	health := &netv1alpha1.TunnelHealth{
+		// Rounded to avoid excessive precision in the status of the resource.
+		RTT:            metav1.Duration{Duration: stats.RTT.Round(10 * time.Microsecond)},
+		Jitter:         metav1.Duration{Duration: stats.Jitter.Round(10 * time.Microsecond)},
+		PacketLoss:     stats.PacketLoss,
+		LastUpdateTime: metav1.NewTime(now),
+	}
+	if !lastHandshake.IsZero() {
+		health.LastHandshakeAge = &metav1.Duration{Duration: now.Sub(lastHandshake).Round(time.Second)}
+	}
+	return health
+}
+
+// SetUpProber creates the socket used to exchange the health probes in the gateway network namespace,
+// and initializes the prober, if enabled.
+func (tc *TunnelController) SetUpProber() error {
+	if tc.healthConfig.ProbeInterval == 0 {
+		klog.Info("Tunnel health probing disabled")
+		return nil
+	}
+
+	var conn *net.UDPConn
+	err := tc.gatewayNetns.Do(func(netNamespace ns.NetNS) (err error) {
+		conn, err = net.ListenUDP("udp4", &net.UDPAddr{Port: liqoconst.GatewayProbePort})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to open the health probe socket in gateway netns: %w", err)
+	}
+
+	tc.healthEvents = make(chan event.GenericEvent, healthEventsBuffer)
+	tc.healthReports = make(map[types.NamespacedName]healthReport)
+	tc.prober = probe.NewProber(conn, tc.healthConfig.ProbeInterval, probe.DefaultWindow, tc.onProbeResults)
+	return nil
+}
+
+// startProbing starts probing the remote gateway associated with the given tunnel endpoint.
+// It must be executed in the gateway network namespace.
+func (tc *TunnelController) startProbing(tep *netv1alpha1.TunnelEndpoint) error {
+	if tc.prober == nil {
+		return nil
+	}
+
+	localIP, remoteIP, err := probeAddresses(tep)
+	if err != nil {
+		return err
+	}
+	link, err := netlink.LinkByName(tc.drivers[tc.backendType].GetLink().Attrs().Name)
+	if err != nil {
+		return err
+	}
+	addr, err := netlink.ParseAddr(localIP + "/32")
+	if err != nil {
+		return err
+	}
+	if err := netlink.AddrReplace(link, addr); err != nil {
+		return fmt.Errorf("failed to configure the tunnel probe address: %w", err)
+	}
+
+	tc.prober.Add(healthKey(tep).String(), &net.UDPAddr{IP: net.ParseIP(remoteIP), Port: liqoconst.GatewayProbePort})
+	return nil
+}
+
+// probeAddresses returns the local and remote addresses used to exchange the health probes with the remote gateway.
+// They correspond to the network address of the respective external CIDRs (as seen by the remote cluster, in case of
+// remapping), which is always reserved by the IPAM and never assigned to pods or endpoints. Hence, it can be dedicated
+// to the tunnel interface without hijacking other traffic, while being routed through the tunnel as the external CIDRs.
+func probeAddresses(tep *netv1alpha1.TunnelEndpoint) (localIP, remoteIP string, err error) {
+	localExternalCIDR, remoteExternalCIDR := utils.GetExternalCIDRS(tep)
+	if localIP, err = utils.GetFirstIP(localExternalCIDR); err != nil {
+		return "", "", fmt.Errorf("failed to retrieve the tunnel probe address: %w", err)
+	}
+	if remoteIP, err = utils.GetFirstIP(remoteExternalCIDR); err != nil {
+		return "", "", fmt.Errorf("failed to retrieve the remote tunnel probe address: %w", err)
+	}
+	return localIP, remoteIP, nil
+}
+
+// stopProbing stops probing the remote gateway associated with the given tunnel endpoint.
+func (tc *TunnelController) stopProbing(tep *netv1alpha1.TunnelEndpoint) {
+	if tc.prober == nil {
+		return
+	}
+
+	key := healthKey(tep)
+	tc.prober.Remove(key.String())
+
+	tc.healthMutex.Lock()
+	defer tc.healthMutex.Unlock()
+	delete(tc.healthReports, key)
+}
+
+// enforceHealth returns the health information of the given tunnel endpoint, and marks the connection as
+// degraded in case any of the thresholds is exceeded. The connection is returned unmodified if not connected.
+// To prevent excessive updates, the health information is refreshed at most once per report period, unless
+// the degraded state changes.
+func (tc *TunnelController) enforceHealth(tep *netv1alpha1.TunnelEndpoint, con *netv1alpha1.Connection) (*netv1alpha1.TunnelHealth,
+	*netv1alpha1.Connection) {
+	if tc.prober == nil || con.Status != netv1alpha1.Connected {
+		return nil, con
+	}
+
+	key := healthKey(tep)
+	stats, found := tc.prober.Stats(key.String())
+	if !found || stats.Samples == 0 {
+		// Preserve the previous information, until the first probes complete.
+		return tep.Status.Health, tc.degradeIfNeeded(con, tep.Status.Health)
+	}
+
+	var lastHandshake time.Time
+	if reporter, ok := tc.drivers[tep.Spec.BackendType].(tunnel.HandshakeReporter); ok {
+		var err error
+		if lastHandshake, err = reporter.LastHandshake(tep); err != nil {
+			klog.Warningf("%s -> failed to retrieve the last handshake time: %v", tep.Spec.ClusterID, err)
+		}
+	}
+
+	now := time.Now()
+	health := forgeTunnelHealth(&stats, lastHandshake, now)
+	degraded, reason := evaluateHealth(health, &tc.healthConfig)
+	probeDegraded, _ := evaluateHealth(forgeTunnelHealth(&stats, time.Time{}, now), &tc.healthConfig)
+
+	tc.healthMutex.Lock()
+	previous, reported := tc.healthReports[key]
+	if reported && tep.Status.Health != nil && previous.degraded == degraded && now.Sub(previous.timestamp) < tc.healthReportPeriod() {
+		tc.healthMutex.Unlock()
+		return tep.Status.Health, tc.degradeIfNeeded(con, tep.Status.Health)
+	}
+	tc.healthReports[key] = healthReport{timestamp: now, degraded: degraded, probeDegraded: probeDegraded}
+	tc.healthMutex.Unlock()
+
+	if degraded && (!reported || !previous.degraded) {
+		klog.Warningf("%s -> %s", tep.Spec.ClusterID, reason)
+		tc.Event(tep, "Warning", "Degraded", reason)
+	} else if !degraded && reported && previous.degraded {
+		klog.Infof("%s -> vpn connection no longer degraded", tep.Spec.ClusterID)
+		tc.Event(tep, "Normal", "Recovered", "vpn connection no longer degraded")
+	}
+
+	return health, tc.degradeIfNeeded(con, health)
+}
+
+// degradeIfNeeded returns a copy of the given connection marked as degraded, if the health information exceeds
+// any of the thresholds. Otherwise, the connection is returned unmodified.
+func (tc *TunnelController) degradeIfNeeded(con *netv1alpha1.Connection, health *netv1alpha1.TunnelHealth) *netv1alpha1.Connection {
+	if health == nil {
+		return con
+	}
+
+	degraded, reason := evaluateHealth(health, &tc.healthConfig)
+	if !degraded {
+		return con
+	}
+
+	// The connection returned by the driver is copied, since it is tracked internally.
+	degradedCon := con.DeepCopy()
+	degradedCon.Status = netv1alpha1.ConnectionDegraded
+	degradedCon.StatusMessage = reason
+	return degradedCon
+}
+
+// onProbeResults is invoked by the prober every time the statistics of the given peer are updated,
+// and triggers the reconciliation of the corresponding tunnel endpoint if the health information
+// needs to be refreshed, either because it is outdated or the degraded state changed.
+func (tc *TunnelController) onProbeResults(key string) {
+	stats, found := tc.prober.Stats(key)
+	if !found || stats.Samples == 0 {
+		return
+	}
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		klog.Errorf("invalid health probe key %q: %v", key, err)
+		return
+	}
+
+	// Handshake information is not considered here, as retrieved during the reconciliation.
+	degraded, _ := evaluateHealth(forgeTunnelHealth(&stats, time.Time{}, time.Now()), &tc.healthConfig)
+
+	tc.healthMutex.Lock()
+	previous, reported := tc.healthReports[types.NamespacedName{Namespace: namespace, Name: name}]
+	tc.healthMutex.Unlock()
+
+	if reported && previous.probeDegraded == degraded && time.Since(previous.timestamp) < tc.healthReportPeriod() {
+		return
+	}
+
+	tc.notifyHealthEvent(namespace, name)
+}
+
+// notifyHealthEvent triggers the reconciliation of the given tunnel endpoint, without blocking the prober.
+// The event is dropped if the buffer is full, since it is generated again when the next probe results are available.
+func (tc *TunnelController) notifyHealthEvent(namespace, name string) {
+	tep := &netv1alpha1.TunnelEndpoint{}
+	tep.SetNamespace(namespace)
+	tep.SetName(name)
+
+	select {
+	case tc.healthEvents <- event.GenericEvent{Object: tep}:
+	default:
+		klog.V(4).Infof("Health events buffer full, skipping the notification for tunnel endpoint %s/%s", namespace, name)
+	}
+}
+
+// healthReportPeriod returns the maximum period between consecutive updates of the health information.
+func (tc *TunnelController) healthReportPeriod() time.Duration {
+	return tc.healthConfig.ProbeInterval * probe.DefaultWindow
+}
+
+// healthKey returns the key identifying the given tunnel endpoint in the prober.
+func healthKey(tep *netv1alpha1.TunnelEndpoint) types.NamespacedName {
+	return types.NamespacedName{Namespace: tep.GetNamespace(), Name: tep.GetName()}
+}
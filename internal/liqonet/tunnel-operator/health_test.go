@@ -0,0 +1,138 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tunneloperator
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqonet/tunnel/probe"
+)
+
+var _ = Describe("Tunnel health", func() {
+	config := HealthConfig{
+		ProbeInterval:       time.Second,
+		RTTThreshold:        100 * time.Millisecond,
+		PacketLossThreshold: 20,
+		HandshakeThreshold:  time.Minute,
+	}
+
+	duration := func(d time.Duration) *metav1.Duration { return &metav1.Duration{Duration: d} }
+
+	DescribeTable("the evaluateHealth function",
+		func(health netv1alpha1.TunnelHealth, expected bool) {
+			degraded, reason := evaluateHealth(&health, &config)
+			Expect(degraded).To(Equal(expected))
+			if expected {
+				Expect(reason).ToNot(BeEmpty())
+			} else {
+				Expect(reason).To(BeEmpty())
+			}
+		},
+		Entry("all values below the thresholds", netv1alpha1.TunnelHealth{
+			RTT: metav1.Duration{Duration: 10 * time.Millisecond}, PacketLoss: 10, LastHandshakeAge: duration(10 * time.Second),
+		}, false),
+		Entry("no handshake information", netv1alpha1.TunnelHealth{RTT: metav1.Duration{Duration: 10 * time.Millisecond}}, false),
+		Entry("packet loss above the threshold", netv1alpha1.TunnelHealth{
+			RTT: metav1.Duration{Duration: 10 * time.Millisecond}, PacketLoss: 30,
+		}, true),
+		Entry("round-trip time above the threshold", netv1alpha1.TunnelHealth{RTT: metav1.Duration{Duration: time.Second}}, true),
+		Entry("last handshake older than the threshold", netv1alpha1.TunnelHealth{
+			RTT: metav1.Duration{Duration: 10 * time.Millisecond}, LastHandshakeAge: duration(time.Hour),
+		}, true),
+	)
+
+	Describe("the forgeTunnelHealth function", func() {
+		var (
+			now   time.Time
+			stats probe.Stats
+		)
+
+		BeforeEach(func() {
+			now = time.Now()
+			stats = probe.Stats{RTT: 1234567 * time.Nanosecond, Jitter: 7654321 * time.Nanosecond, PacketLoss: 10, Samples: 10}
+		})
+
+		It("should correctly forge the health information", func() {
+			health := forgeTunnelHealth(&stats, now.Add(-time.Minute), now)
+			Expect(health.RTT.Duration).To(Equal(1230 * time.Microsecond))
+			Expect(health.Jitter.Duration).To(Equal(7650 * time.Microsecond))
+			Expect(health.PacketLoss).To(Equal(10))
+			Expect(health.LastHandshakeAge).ToNot(BeNil())
+			Expect(health.LastHandshakeAge.Duration).To(Equal(time.Minute))
+			Expect(health.LastUpdateTime.Time).To(Equal(now))
+		})
+
+		It("should not set the handshake age, if no handshake information is available", func() {
+			health := forgeTunnelHealth(&stats, time.Time{}, now)
+			Expect(health.LastHandshakeAge).To(BeNil())
+		})
+	})
+
+	Describe("the probeAddresses function", func() {
+		var tep *netv1alpha1.TunnelEndpoint
+
+		BeforeEach(func() {
+			tep = &netv1alpha1.TunnelEndpoint{Spec: netv1alpha1.TunnelEndpointSpec{
+				LocalPodCIDR: "10.0.0.0/16", LocalExternalCIDR: "10.1.0.0/16", LocalNATExternalCIDR: consts.DefaultCIDRValue,
+				RemoteExternalCIDR: "10.2.0.0/16", RemoteNATExternalCIDR: consts.DefaultCIDRValue,
+			}}
+		})
+
+		It("should return the network addresses of the external CIDRs", func() {
+			localIP, remoteIP, err := probeAddresses(tep)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(localIP).To(Equal("10.1.0.0"))
+			Expect(remoteIP).To(Equal("10.2.0.0"))
+		})
+
+		When("the external CIDRs have been remapped", func() {
+			BeforeEach(func() {
+				tep.Spec.LocalNATExternalCIDR = "10.3.0.0/16"
+				tep.Spec.RemoteNATExternalCIDR = "10.4.0.0/16"
+			})
+
+			It("should return the network addresses of the remapped CIDRs", func() {
+				localIP, remoteIP, err := probeAddresses(tep)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(localIP).To(Equal("10.3.0.0"))
+				Expect(remoteIP).To(Equal("10.4.0.0"))
+			})
+		})
+	})
+
+	Describe("the notifyHealthEvent function", func() {
+		It("should not block if the buffer is full", func() {
+			tc := &TunnelController{healthEvents: make(chan event.GenericEvent, 1)}
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				tc.notifyHealthEvent("foo", "bar")
+				tc.notifyHealthEvent("foo", "baz")
+			}()
+
+			Eventually(done).Should(BeClosed())
+			Expect(tc.healthEvents).To(HaveLen(1))
+			Expect((<-tc.healthEvents).Object.GetName()).To(Equal("bar"))
+		})
+	})
+})
@@ -61,6 +61,9 @@ var (
 type LabelerController struct {
 	client.Client
 	PodIP string
+	// ActiveActive is set when all the gateway replicas are active, each one carrying a subset of the tunnels.
+	// In this case, each replica labels only itself as active, and the gateway service is not annotated.
+	ActiveActive bool
 }
 
 // NewLabelerController  returns a new controller ready to be setup and started with the controller manager.
@@ -93,12 +96,19 @@ func (lbc *LabelerController) Reconcile(ctx context.Context, req ctrl.Request) (
 			klog.Infof("successfully updated label {%s: %s} for pod {%s}",
 				gatewayLabelKey, gatewayStatusActive, req.String())
 		}
+		if lbc.ActiveActive {
+			return ctrl.Result{}, nil
+		}
 		if err := lbc.annotateGatewayService(ctx); err != nil {
 			// Do not log here, already done in annotateGatewayService.
 			return ctrl.Result{}, err
 		}
 		return ctrl.Result{}, nil
 	}
+	// In active/active mode, the other replicas are in charge of labeling themselves.
+	if lbc.ActiveActive {
+		return ctrl.Result{}, nil
+	}
 	// Make sure that the other replicas has the label set to "standby".
 	if val := liqoutils.GetLabelValueFromObj(pod, gatewayLabelKey); val == gatewayStatusActive {
 		if liqoutils.AddLabelToObj(pod, gatewayLabelKey, gatewayStatusStandby) {
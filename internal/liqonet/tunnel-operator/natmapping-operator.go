@@ -81,8 +81,12 @@ func (npc *NatMappingController) SetupWithManager(mgr ctrl.Manager) error {
 
 // NewNatMappingController returns a NAT mapping controller istance.
 func NewNatMappingController(cl client.Client, readyClustersMutex *sync.Mutex,
-	readyClusters map[string]struct{}, gatewayNetns ns.NetNS) (*NatMappingController, error) {
-	iptablesHandler, err := iptables.NewIPTHandler()
+	readyClusters map[string]struct{}, gatewayNetns ns.NetNS, dualStack bool) (*NatMappingController, error) {
+	newIPTHandler := iptables.NewIPTHandler
+	if dualStack {
+		newIPTHandler = iptables.NewDualStackIPTHandler
+	}
+	iptablesHandler, err := newIPTHandler()
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,104 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tunneloperator
+
+import (
+	"context"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
+	"github.com/liqotech/liqo/pkg/liqonet/sharding"
+)
+
+// isOwner returns whether the current gateway replica is in charge of the tunnel towards the given remote cluster.
+// In active/passive mode, the leader replica (i.e., the only one running the controller) owns all the tunnels.
+// In active/active mode, each tunnel is assigned to one of the ready replicas through consistent hashing on the cluster ID.
+func (tc *TunnelController) isOwner(ctx context.Context, clusterID string) (bool, error) {
+	if !tc.activeActive {
+		return true, nil
+	}
+
+	var pods corev1.PodList
+	if err := tc.List(ctx, &pods, client.InNamespace(tc.namespace), client.MatchingLabels{
+		podComponentLabelKey: podComponentLabelValue,
+		podNameLabelKey:      podNameLabelValue,
+	}); err != nil {
+		return false, err
+	}
+
+	return sharding.NewRing(sharding.Members(pods.Items)).Owner(clusterID) == tc.podIP, nil
+}
+
+// releaseTunnel tears down the local configuration of a tunnel which has been assigned to a different gateway replica, if any.
+func (tc *TunnelController) releaseTunnel(tep *netv1alpha1.TunnelEndpoint, unconfigure func(ns.NetNS) error) error {
+	key := types.NamespacedName{Namespace: tep.GetNamespace(), Name: tep.GetName()}
+	if _, found := tc.ownedTunnels[key]; !found {
+		klog.V(4).Infof("%s -> tunnel handled by a different gateway replica", tep.Spec.ClusterID)
+		return nil
+	}
+
+	klog.Infof("%s -> tunnel assigned to a different gateway replica, tearing down the local configuration", tep.Spec.ClusterID)
+	if err := tc.gatewayNetns.Do(unconfigure); err != nil {
+		return err
+	}
+
+	tc.readyClustersMutex.Lock()
+	delete(tc.readyClusters, tep.Spec.ClusterID)
+	tc.readyClustersMutex.Unlock()
+
+	delete(tc.ownedTunnels, key)
+	tc.Event(tep, "Normal", "Processing", "tunnel released to a different gateway replica")
+	return nil
+}
+
+// enqueueTunnelEndpoints returns the reconciliation requests for all the tunnel endpoints,
+// to rebalance them when the set of gateway replicas changes.
+func (tc *TunnelController) enqueueTunnelEndpoints(_ client.Object) []reconcile.Request {
+	var teps netv1alpha1.TunnelEndpointList
+	if err := tc.List(context.Background(), &teps); err != nil {
+		klog.Errorf("unable to list tunnel endpoints: %v", err)
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(teps.Items))
+	for i := range teps.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+			Namespace: teps.Items[i].GetNamespace(), Name: teps.Items[i].GetName()}})
+	}
+	return requests
+}
+
+// gatewayMembershipPredicate filters the events concerning the gateway pods which may change
+// the set of replicas eligible to carry tunnels.
+func gatewayMembershipPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldPod, okOld := e.ObjectOld.(*corev1.Pod)
+			newPod, okNew := e.ObjectNew.(*corev1.Pod)
+			if !okOld || !okNew {
+				return false
+			}
+			return sharding.IsMember(oldPod) != sharding.IsMember(newPod) || oldPod.Status.PodIP != newPod.Status.PodIP
+		},
+	}
+}
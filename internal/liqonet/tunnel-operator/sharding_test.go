@@ -0,0 +1,101 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tunneloperator
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/liqotech/liqo/pkg/liqonet/sharding"
+)
+
+var _ = Describe("Tunnel sharding", func() {
+	const namespace = "liqo"
+
+	forgeGatewayPod := func(name, ip string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{
+				podComponentLabelKey: podComponentLabelValue,
+				podNameLabelKey:      podNameLabelValue,
+			}},
+			Status: corev1.PodStatus{PodIP: ip, Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}},
+		}
+	}
+
+	Describe("The isOwner function", func() {
+		var (
+			ctrl  *TunnelController
+			owner bool
+			err   error
+		)
+
+		BeforeEach(func() {
+			ctrl = &TunnelController{
+				Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(
+					forgeGatewayPod("foo", "10.0.0.1"), forgeGatewayPod("bar", "10.0.0.2")).Build(),
+				namespace: namespace,
+				podIP:     "10.0.0.1",
+			}
+		})
+
+		JustBeforeEach(func() { owner, err = ctrl.isOwner(context.Background(), clusterID1) })
+
+		When("the gateway is in active/passive mode", func() {
+			It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+			It("should own all the tunnels", func() { Expect(owner).To(BeTrue()) })
+		})
+
+		When("the gateway is in active/active mode", func() {
+			BeforeEach(func() { ctrl.activeActive = true })
+
+			It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+			It("should own the tunnel only if assigned to the current replica", func() {
+				expected := sharding.NewRing([]string{"10.0.0.1", "10.0.0.2"}).Owner(clusterID1) == ctrl.podIP
+				Expect(owner).To(Equal(expected))
+			})
+		})
+	})
+
+	Describe("The gatewayMembershipPredicate function", func() {
+		var oldPod, newPod *corev1.Pod
+
+		BeforeEach(func() {
+			oldPod = forgeGatewayPod("foo", "10.0.0.1")
+			newPod = oldPod.DeepCopy()
+		})
+
+		It("should ignore updates not changing the membership", func() {
+			newPod.Labels["foo"] = "bar"
+			Expect(gatewayMembershipPredicate().Update(event.UpdateEvent{ObjectOld: oldPod, ObjectNew: newPod})).To(BeFalse())
+		})
+
+		It("should accept updates changing the readiness", func() {
+			newPod.Status.Conditions[0].Status = corev1.ConditionFalse
+			Expect(gatewayMembershipPredicate().Update(event.UpdateEvent{ObjectOld: oldPod, ObjectNew: newPod})).To(BeTrue())
+		})
+
+		It("should accept updates changing the IP address", func() {
+			newPod.Status.PodIP = "10.0.0.2"
+			Expect(gatewayMembershipPredicate().Update(event.UpdateEvent{ObjectOld: oldPod, ObjectNew: newPod})).To(BeTrue())
+		})
+	})
+})
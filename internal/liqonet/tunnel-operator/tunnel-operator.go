@@ -27,15 +27,21 @@ import (
 	"github.com/containernetworking/plugins/pkg/ns"
 	"github.com/vishvananda/netlink"
 	"golang.org/x/sys/unix"
+	corev1 "k8s.io/api/core/v1"
 	k8sApiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
 	k8s "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlbuilder "sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
 	liqoconst "github.com/liqotech/liqo/pkg/consts"
@@ -43,6 +49,9 @@ import (
 	liqonetns "github.com/liqotech/liqo/pkg/liqonet/netns"
 	liqorouting "github.com/liqotech/liqo/pkg/liqonet/routing"
 	"github.com/liqotech/liqo/pkg/liqonet/tunnel"
+	// Registering the IPsec tunnel driver.
+	_ "github.com/liqotech/liqo/pkg/liqonet/tunnel/ipsec"
+	"github.com/liqotech/liqo/pkg/liqonet/tunnel/probe"
 	tunnelwg "github.com/liqotech/liqo/pkg/liqonet/tunnel/wireguard"
 	"github.com/liqotech/liqo/pkg/liqonet/utils"
 )
@@ -60,6 +69,7 @@ type TunnelController struct {
 	iptables.IPTHandler
 	k8sClient          k8s.Interface
 	drivers            map[string]tunnel.Driver
+	backendType        string
 	namespace          string
 	podIP              string
 	finalizer          string
@@ -69,6 +79,14 @@ type TunnelController struct {
 	gatewayVeth        net.Interface
 	readyClustersMutex *sync.Mutex
 	readyClusters      map[string]struct{}
+	healthConfig       HealthConfig
+	prober             *probe.Prober
+	healthEvents       chan event.GenericEvent
+	healthMutex        sync.Mutex
+	healthReports      map[types.NamespacedName]healthReport
+	activeActive       bool
+	dualStack          bool
+	ownedTunnels       map[types.NamespacedName]struct{}
 }
 
 // cluster-role
@@ -81,59 +99,66 @@ type TunnelController struct {
 
 // NewTunnelController instantiates and initializes the tunnel controller.
 func NewTunnelController(podIP, namespace string, er record.EventRecorder, k8sClient k8s.Interface, cl client.Client,
-	readyClustersMutex *sync.Mutex, readyClusters map[string]struct{}, gatewayNetns, hostNetns ns.NetNS, mtu, port int) (*TunnelController, error) {
+	readyClustersMutex *sync.Mutex, readyClusters map[string]struct{}, gatewayNetns, hostNetns ns.NetNS, mtu, port int,
+	backendType string, health HealthConfig, activeActive, dualStack bool) (*TunnelController, error) {
 	tunnelEndpointFinalizer := liqoconst.LiqoGatewayOperatorName + "." + liqoconst.FinalizersSuffix
 	tc := &TunnelController{
 		Client:             cl,
 		EventRecorder:      er,
 		k8sClient:          k8sClient,
 		podIP:              podIP,
+		backendType:        backendType,
 		namespace:          namespace,
 		finalizer:          tunnelEndpointFinalizer,
 		readyClustersMutex: readyClustersMutex,
 		readyClusters:      readyClusters,
 		gatewayNetns:       gatewayNetns,
 		hostNetns:          hostNetns,
+		healthConfig:       health,
+		activeActive:       activeActive,
+		dualStack:          dualStack,
+		ownedTunnels:       make(map[types.NamespacedName]struct{}),
 	}
 
-	err := tc.SetUpTunnelDrivers(tunnel.Config{
+	err := tc.SetUpTunnelDriver(tunnel.Config{
 		MTU:           mtu,
 		ListeningPort: port,
 	})
 	if err != nil {
 		return nil, err
 	}
-	link, err := netlink.LinkByName(liqoconst.DeviceName)
+	deviceName := tc.drivers[backendType].GetLink().Attrs().Name
+	link, err := netlink.LinkByName(deviceName)
 	if err != nil {
 		return nil, err
 	}
 	if err = tc.setUpGWNetns(liqoconst.HostVethName, liqoconst.GatewayVethName, mtu); err != nil {
 		return nil, err
 	}
-	// Move wireguard interface in the gateway network namespace.
+	// Move tunnel interface in the gateway network namespace.
 	if err = netlink.LinkSetNsFd(link, int(tc.gatewayNetns.Fd())); err != nil {
-		return nil, fmt.Errorf("failed to move wireguard interfacte to gateway netns: %w", err)
+		return nil, fmt.Errorf("failed to move tunnel interface to gateway netns: %w", err)
 	}
-	// After the wireguard device has been moved to the new netns we need to:
+	// After the tunnel device has been moved to the new netns we need to:
 	// 1) set it up;
-	// 2) replace the wgctl.Client with a new client spawned in the new netns.
-	var configureWg = func(netnsNamespace ns.NetNS) error {
-		link, err = netlink.LinkByName(liqoconst.DeviceName)
+	// 2) in case of wireguard, replace the wgctl.Client with a new client spawned in the new netns.
+	var configureTunnel = func(netnsNamespace ns.NetNS) error {
+		link, err = netlink.LinkByName(deviceName)
 		if err != nil {
 			return err
 		}
 		err = netlink.LinkSetUp(link)
 		if err != nil {
-			return fmt.Errorf("failed to set wireguard iface up in gateway netns: %w", err)
+			return fmt.Errorf("failed to set tunnel iface up in gateway netns: %w", err)
 		}
-		w := tc.drivers[liqoconst.DriverName]
-		wg := w.(*tunnelwg.Wireguard)
-		if err := wg.SetNewClient(); err != nil {
-			return fmt.Errorf("an error occurred while setting new client in tunnel driver")
+		if wg, ok := tc.drivers[backendType].(*tunnelwg.Wireguard); ok {
+			if err := wg.SetNewClient(); err != nil {
+				return fmt.Errorf("an error occurred while setting new client in tunnel driver")
+			}
 		}
 		return nil
 	}
-	if err := tc.gatewayNetns.Do(configureWg); err != nil {
+	if err := tc.gatewayNetns.Do(configureTunnel); err != nil {
 		return nil, err
 	}
 	err = tc.SetUpIPTablesHandler()
@@ -143,6 +168,9 @@ func NewTunnelController(podIP, namespace string, er record.EventRecorder, k8sCl
 	if err := tc.SetUpRouteManager(); err != nil {
 		return nil, err
 	}
+	if err := tc.SetUpProber(); err != nil {
+		return nil, err
+	}
 
 	return tc, nil
 }
@@ -176,6 +204,14 @@ func (tc *TunnelController) Reconcile(ctx context.Context, req ctrl.Request) (ct
 			tc.Event(tep, "Normal", "Processing", "route configured")
 			klog.Infof("%s -> route for destination {%s} correctly configured", clusterID, remotePodCIDR)
 		}
+		// Probes are started once connected, to prevent accounting as lost the ones sent in the meanwhile.
+		if con.Status == netv1alpha1.Connected {
+			if err := tc.startProbing(tep); err != nil {
+				klog.Errorf("%s -> unable to start the tunnel health probes: %v", clusterID, err)
+				tc.Eventf(tep, "Warning", "Processing", "unable to start the tunnel health probes: %v", err)
+				return err
+			}
+		}
 		return nil
 	}
 	var unconfigGWNetns = func(netNamespace ns.NetNS) error {
@@ -184,6 +220,7 @@ func (tc *TunnelController) Reconcile(ctx context.Context, req ctrl.Request) (ct
 				tep.Spec.ClusterID, err.Error())
 			return err
 		}
+		tc.stopProbing(tep)
 		if err := tc.disconnectFromPeer(tep); err != nil {
 			return err
 		}
@@ -211,6 +248,17 @@ func (tc *TunnelController) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	clusterID = tep.Spec.ClusterID
 
 	_, remotePodCIDR = utils.GetPodCIDRS(tep)
+
+	// In active/active mode, each tunnel is handled only by the gateway replica it is assigned to.
+	owner, err := tc.isOwner(ctx, clusterID)
+	if err != nil {
+		klog.Errorf("%s -> unable to determine the gateway replica in charge of the tunnel: %v", clusterID, err)
+		return result, err
+	}
+	if !owner {
+		return result, tc.releaseTunnel(tep, unconfigGWNetns)
+	}
+
 	// Examine DeletionTimestamp to determine if object is under deletion.
 	if tep.ObjectMeta.DeletionTimestamp.IsZero() {
 		if !controllerutil.ContainsFinalizer(tep, tc.finalizer) {
@@ -233,6 +281,7 @@ func (tc *TunnelController) Reconcile(ctx context.Context, req ctrl.Request) (ct
 			if err = tc.gatewayNetns.Do(unconfigGWNetns); err != nil {
 				return result, err
 			}
+			delete(tc.ownedTunnels, req.NamespacedName)
 
 			// Remove the finalizer from the list and update it.
 			controllerutil.RemoveFinalizer(tep, tc.finalizer)
@@ -248,9 +297,12 @@ func (tc *TunnelController) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		// If object is being deleted and does not have a finalizer we just return.
 		return result, nil
 	}
+	// The tunnel is tracked before being configured, to guarantee the teardown in case of partial configurations.
+	tc.ownedTunnels[req.NamespacedName] = struct{}{}
 	if err := tc.gatewayNetns.Do(configGWNetns); err != nil {
 		return result, err
 	}
+	health, con := tc.enforceHealth(tep, con)
 	// When the status of VPN tunnel is "Connecting" than we requeue the tunnelendpoint resource in order to
 	// reprocess it and check the VPN tunnel state.
 	if con.Status == netv1alpha1.Connecting {
@@ -259,7 +311,7 @@ func (tc *TunnelController) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		}
 	}
 
-	return result, tc.updateStatus(con, tep)
+	return result, tc.updateStatus(con, health, tep)
 }
 
 func (tc *TunnelController) connectToPeer(ep *netv1alpha1.TunnelEndpoint) (*netv1alpha1.Connection, error) {
@@ -276,7 +328,12 @@ func (tc *TunnelController) connectToPeer(ep *netv1alpha1.TunnelEndpoint) (*netv
 		klog.Errorf("%s -> an error occurred while establishing vpn connection: %v", clusterID, err)
 		return nil, err
 	}
-	if reflect.DeepEqual(*con, ep.Status.Connection) {
+	current := ep.Status.Connection
+	if current.Status == netv1alpha1.ConnectionDegraded {
+		// The degraded state is set on top of the one reported by the driver, according to the health probes.
+		current.Status, current.StatusMessage = netv1alpha1.Connected, netv1alpha1.ConnectedMessage
+	}
+	if reflect.DeepEqual(*con, current) {
 		return con, nil
 	}
 	tc.Event(ep, "Normal", "Processing", "connection established")
@@ -370,34 +427,61 @@ func (tc *TunnelController) SetupWithManager(mgr ctrl.Manager) error {
 			return false
 		},
 	}
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&netv1alpha1.TunnelEndpoint{}).WithEventFilter(resourceToBeProccesedPredicate).
-		Complete(tc)
-}
+	builder := ctrl.NewControllerManagedBy(mgr).
+		For(&netv1alpha1.TunnelEndpoint{}, ctrlbuilder.WithPredicates(resourceToBeProccesedPredicate))
 
-// SetUpTunnelDrivers for each registered tunnel implementation it creates and initializes the driver.
-func (tc *TunnelController) SetUpTunnelDrivers(config tunnel.Config) error {
-	tc.drivers = make(map[string]tunnel.Driver)
-	for tunnelType, createDriverFunc := range tunnel.Drivers {
-		klog.V(3).Infof("Creating driver for tunnel of type %s", tunnelType)
-		d, err := createDriverFunc(tc.k8sClient, tc.namespace, config)
-		if err != nil {
-			return err
-		}
-		klog.V(3).Infof("Initializing driver for %s tunnel", tunnelType)
-		err = d.Init()
-		if err != nil {
+	if tc.activeActive {
+		// Rebalance the tunnels when the set of gateway replicas eligible to carry them changes.
+		builder = builder.Watches(&source.Kind{Type: &corev1.Pod{}}, handler.EnqueueRequestsFromMapFunc(tc.enqueueTunnelEndpoints),
+			ctrlbuilder.WithPredicates(gatewayMembershipPredicate()))
+	}
+
+	if tc.prober != nil {
+		// The prober is started only by the leader, which is in charge of the tunnels.
+		if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+			tc.prober.Start(ctx)
+			<-ctx.Done()
+			return nil
+		})); err != nil {
 			return err
 		}
-		klog.V(3).Infof("Driver for %s tunnel created and initialized", tunnelType)
-		tc.drivers[tunnelType] = d
+		// Trigger the reconciliation when the health information needs to be refreshed.
+		builder = builder.Watches(&source.Channel{Source: tc.healthEvents}, &handler.EnqueueRequestForObject{})
 	}
+
+	return builder.Complete(tc)
+}
+
+// SetUpTunnelDriver creates and initializes the driver of the configured tunnel implementation.
+func (tc *TunnelController) SetUpTunnelDriver(config tunnel.Config) error {
+	createDriverFunc, found := tunnel.Drivers[tc.backendType]
+	if !found {
+		return fmt.Errorf("no registered driver of type %s found", tc.backendType)
+	}
+
+	tc.drivers = make(map[string]tunnel.Driver)
+	klog.V(3).Infof("Creating driver for tunnel of type %s", tc.backendType)
+	d, err := createDriverFunc(tc.k8sClient, tc.namespace, config)
+	if err != nil {
+		return err
+	}
+	klog.V(3).Infof("Initializing driver for %s tunnel", tc.backendType)
+	err = d.Init()
+	if err != nil {
+		return err
+	}
+	klog.V(3).Infof("Driver for %s tunnel created and initialized", tc.backendType)
+	tc.drivers[tc.backendType] = d
 	return nil
 }
 
 // SetUpIPTablesHandler initializes the IPTables handler of TunnelController.
 func (tc *TunnelController) SetUpIPTablesHandler() error {
-	iptHandler, err := iptables.NewIPTHandler()
+	newIPTHandler := iptables.NewIPTHandler
+	if tc.dualStack {
+		newIPTHandler = iptables.NewDualStackIPTHandler
+	}
+	iptHandler, err := newIPTHandler()
 	if err != nil {
 		return err
 	}
@@ -418,8 +502,7 @@ func (tc *TunnelController) SetUpIPTablesHandler() error {
 // SetUpRouteManager initializes the Route manager of TunnelController.
 func (tc *TunnelController) SetUpRouteManager() error {
 	// Todo make the gateway routing manager to support more than one vpn technology at the same time.
-	// Todo it should use the right tunnel based on the backend type set inside the tep.
-	grm, err := liqorouting.NewGatewayRoutingManager(unix.RT_TABLE_MAIN, tc.drivers[liqoconst.DriverName].GetLink())
+	grm, err := liqorouting.NewGatewayRoutingManager(unix.RT_TABLE_MAIN, tc.drivers[tc.backendType].GetLink())
 	if err != nil {
 		return err
 	}
@@ -449,16 +532,33 @@ func (tc *TunnelController) setUpGWNetns(hostVethName, gatewayVethName string, v
 	}
 
 	// Configure gatewayveth.
-	return liqonetns.ConfigureVeth(&gatewayVeth, liqoconst.HostVethIPAddr, hostVeth.HardwareAddr, tc.gatewayNetns)
+	if err = liqonetns.ConfigureVeth(&gatewayVeth, liqoconst.HostVethIPAddr, hostVeth.HardwareAddr, tc.gatewayNetns); err != nil {
+		return err
+	}
+
+	if !tc.dualStack {
+		return nil
+	}
+	// Enable ipv6 forwarding in the gateway namespace, to route the traffic towards the IPv6 networks of the remote clusters.
+	return tc.gatewayNetns.Do(func(netNamespace ns.NetNS) error {
+		if err := liqorouting.EnableIPv6Forwarding(); err != nil {
+			return fmt.Errorf("unable to enable ipv6 forwarding in namespace {%s}: %w", netNamespace.Path(), err)
+		}
+		klog.V(5).Infof("ipv6 forwarding in namespace {%s} with path {%s} correctly enabled",
+			liqoconst.GatewayNetnsName, netNamespace.Path())
+		return nil
+	})
 }
 
-func (tc *TunnelController) updateStatus(con *netv1alpha1.Connection, tep *netv1alpha1.TunnelEndpoint) error {
-	if reflect.DeepEqual(*con, tep.Status.Connection) && tep.Status.GatewayIP == tc.podIP &&
+func (tc *TunnelController) updateStatus(con *netv1alpha1.Connection, health *netv1alpha1.TunnelHealth,
+	tep *netv1alpha1.TunnelEndpoint) error {
+	if reflect.DeepEqual(*con, tep.Status.Connection) && reflect.DeepEqual(health, tep.Status.Health) && tep.Status.GatewayIP == tc.podIP &&
 		tep.Status.VethIFaceIndex == tc.hostVeth.Index && tep.Status.VethIP == liqoconst.GatewayVethIPAddr {
 		return nil
 	}
 
 	tep.Status.Connection = *con
+	tep.Status.Health = health
 	tep.Status.GatewayIP = tc.podIP
 	tep.Status.VethIFaceIndex = tc.hostVeth.Index
 	tep.Status.VethIFaceName = tc.hostVeth.Name
@@ -121,7 +121,7 @@ var _ = BeforeSuite(func() {
 		MetricsBindAddress: "0",
 	})
 
-	controller, err = NewNatMappingController(mgr.GetClient(), &readyClustersMutex, readyClusters, iptNetns)
+	controller, err = NewNatMappingController(mgr.GetClient(), &readyClustersMutex, readyClusters, iptNetns, false)
 	Expect(err).To(BeNil())
 	go func() {
 		if err = mgr.Start(context.Background()); err != nil {
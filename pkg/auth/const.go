@@ -23,4 +23,7 @@ const (
 	// CertIdentityURI is the path where to contact the Authentication Service
 	// to have a Certificate Identity.
 	CertIdentityURI = "/identity/certificate"
+	// CertRenewalURI is the path where to contact the Authentication Service
+	// to renew a Certificate Identity before it expires.
+	CertRenewalURI = "/identity/certificate/renew"
 )
@@ -43,6 +43,15 @@ type CertificateIdentityRequest struct {
 	CertificateSigningRequest string `json:"certificateSigningRequest"`
 }
 
+// CertificateRenewalRequest is the request for the renewal of a certificate identity, before it expires.
+// It is authenticated through the Signature of the new CertificateSigningRequest, which is performed with
+// the private key associated with the certificate currently owned by the requesting cluster.
+type CertificateRenewalRequest struct {
+	ClusterIdentity           discoveryv1alpha1.ClusterIdentity `json:"cluster"`
+	CertificateSigningRequest string                            `json:"certificateSigningRequest"`
+	Signature                 string                            `json:"signature"`
+}
+
 // NewCertificateIdentityRequest creates and returns a new CertificateIdentityRequest.
 func NewCertificateIdentityRequest(cluster discoveryv1alpha1.ClusterIdentity, originClusterToken, token string,
 	certificateSigningRequest []byte) *CertificateIdentityRequest {
@@ -54,6 +63,16 @@ func NewCertificateIdentityRequest(cluster discoveryv1alpha1.ClusterIdentity, or
 	}
 }
 
+// NewCertificateRenewalRequest creates and returns a new CertificateRenewalRequest.
+func NewCertificateRenewalRequest(cluster discoveryv1alpha1.ClusterIdentity,
+	certificateSigningRequest, signature []byte) *CertificateRenewalRequest {
+	return &CertificateRenewalRequest{
+		ClusterIdentity:           cluster,
+		CertificateSigningRequest: base64.StdEncoding.EncodeToString(certificateSigningRequest),
+		Signature:                 base64.StdEncoding.EncodeToString(signature),
+	}
+}
+
 // GetClusterIdentity returns the ClusterIdentity.
 func (saIdentityRequest *ServiceAccountIdentityRequest) GetClusterIdentity() discoveryv1alpha1.ClusterIdentity {
 	return saIdentityRequest.ClusterIdentity
@@ -83,3 +102,18 @@ func (certIdentityRequest *CertificateIdentityRequest) GetToken() string {
 func (certIdentityRequest *CertificateIdentityRequest) GetPath() string {
 	return CertIdentityURI
 }
+
+// GetClusterIdentity returns the ClusterIdentity.
+func (certRenewalRequest *CertificateRenewalRequest) GetClusterIdentity() discoveryv1alpha1.ClusterIdentity {
+	return certRenewalRequest.ClusterIdentity
+}
+
+// GetToken returns the token. The renewal requests are not authenticated through tokens, hence it is always empty.
+func (certRenewalRequest *CertificateRenewalRequest) GetToken() string {
+	return ""
+}
+
+// GetPath returns the absolute path of the endpoint to contact to send a new CertificateRenewalRequest.
+func (certRenewalRequest *CertificateRenewalRequest) GetPath() string {
+	return CertRenewalURI
+}
@@ -17,6 +17,7 @@ package auth
 import (
 	"encoding/base64"
 	"fmt"
+	"time"
 
 	"k8s.io/klog/v2"
 
@@ -33,6 +34,16 @@ type AWSIdentityInfo struct {
 	IAMUserArn      string `json:"iamUserArn"`
 }
 
+// OIDCIdentityInfo contains the information required by a cluster to get a valid token-based identity,
+// federated through an OIDC issuer.
+type OIDCIdentityInfo struct {
+	Token              string `json:"token"`
+	Audience           string `json:"audience"`
+	ServiceAccountName string `json:"serviceAccountName"`
+	ExpirationSeconds  int64  `json:"expirationSeconds"`
+	Expiration         string `json:"expiration"`
+}
+
 // CertificateIdentityResponse is the response on a certificate identity request.
 type CertificateIdentityResponse struct {
 	Namespace    string `json:"namespace"`
@@ -40,7 +51,8 @@ type CertificateIdentityResponse struct {
 	APIServerURL string `json:"apiServerUrl"`
 	APIServerCA  string `json:"apiServerCA,omitempty"`
 
-	AWSIdentityInfo AWSIdentityInfo `json:"aws,omitempty"`
+	AWSIdentityInfo  AWSIdentityInfo  `json:"aws,omitempty"`
+	OIDCIdentityInfo OIDCIdentityInfo `json:"oidc,omitempty"`
 }
 
 // HasAWSValues checks if the response has all the required AWS fields set.
@@ -52,6 +64,11 @@ func (resp *CertificateIdentityResponse) HasAWSValues() bool {
 	return credentials && region && cluster && userArn
 }
 
+// HasOIDCValues checks if the response has all the required OIDC fields set.
+func (resp *CertificateIdentityResponse) HasOIDCValues() bool {
+	return resp.OIDCIdentityInfo.Token != "" && resp.OIDCIdentityInfo.Audience != "" && resp.OIDCIdentityInfo.ServiceAccountName != ""
+}
+
 // NewCertificateIdentityResponse makes a new CertificateIdentityResponse.
 func NewCertificateIdentityResponse(
 	namespace string, identityResponse *responsetypes.SigningRequestResponse,
@@ -81,6 +98,20 @@ func NewCertificateIdentityResponse(
 			},
 		}, nil
 
+	case responsetypes.SigningRequestResponseOIDC:
+		return &CertificateIdentityResponse{
+			Namespace:    namespace,
+			APIServerURL: apiServerConfig.Address,
+			APIServerCA:  apiServerConfig.CA,
+			OIDCIdentityInfo: OIDCIdentityInfo{
+				Token:              identityResponse.OIDCIdentityResponse.Token,
+				Audience:           identityResponse.OIDCIdentityResponse.Audience,
+				ServiceAccountName: identityResponse.OIDCIdentityResponse.ServiceAccountName,
+				ExpirationSeconds:  identityResponse.OIDCIdentityResponse.ExpirationSeconds,
+				Expiration:         identityResponse.OIDCIdentityResponse.Expiration.UTC().Format(time.RFC3339),
+			},
+		}, nil
+
 	default:
 		err := fmt.Errorf("unknown response type %v", responseType)
 		klog.Error(err)
@@ -0,0 +1,175 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// NamedTokenLabel is the label set on the secrets containing the additional named authentication tokens.
+	NamedTokenLabel = "auth.liqo.io/named-token"
+
+	namedTokenSecretPrefix = TokenSecretName + "-"
+
+	tokenExpirationAnnotation       = "auth.liqo.io/token-expiration"
+	tokenMaxUsagesAnnotation        = "auth.liqo.io/token-max-usages"
+	tokenUsagesAnnotation           = "auth.liqo.io/token-usages"
+	tokenAllowedClusterIDAnnotation = "auth.liqo.io/token-allowed-cluster-id"
+)
+
+// NamedToken is an authentication token which, differently from the cluster-wide one,
+// can be restricted in time, in the number of usages and to a specific remote cluster.
+// Revoking a NamedToken (i.e., deleting the corresponding secret) does not affect the
+// peerings already established, since the token is only checked when an identity is issued.
+type NamedToken struct {
+	// Name is the name of the token, which identifies the corresponding secret.
+	Name string
+	// Value is the actual token to be provided by the remote clusters.
+	Value string
+	// Expiration is the time after which the token is no longer valid (no expiration if zero).
+	Expiration time.Time
+	// MaxUsages is the number of identities which can be issued with this token (unlimited if zero).
+	MaxUsages int
+	// Usages is the number of identities already issued with this token.
+	Usages int
+	// AllowedClusterID is the only ClusterID allowed to use the token (any cluster if empty).
+	AllowedClusterID string
+}
+
+// NamedTokenSecretName returns the name of the secret containing the named token with the given name.
+func NamedTokenSecretName(name string) string {
+	return namedTokenSecretPrefix + name
+}
+
+// NamedTokenFromSecret retrieves the named token given its secret.
+func NamedTokenFromSecret(secret *v1.Secret) (*NamedToken, error) {
+	value, err := GetTokenFromSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	token := &NamedToken{
+		Name:             strings.TrimPrefix(secret.GetName(), namedTokenSecretPrefix),
+		Value:            value,
+		AllowedClusterID: secret.GetAnnotations()[tokenAllowedClusterIDAnnotation],
+	}
+
+	if expiration, ok := secret.GetAnnotations()[tokenExpirationAnnotation]; ok {
+		if token.Expiration, err = time.Parse(time.RFC3339, expiration); err != nil {
+			return nil, fmt.Errorf("invalid secret %v/%v: invalid expiration: %w", secret.GetNamespace(), secret.GetName(), err)
+		}
+	}
+	if token.MaxUsages, err = parseUsages(secret, tokenMaxUsagesAnnotation); err != nil {
+		return nil, err
+	}
+	if token.Usages, err = parseUsages(secret, tokenUsagesAnnotation); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// ToSecret returns the secret storing the named token in the given namespace.
+func (token *NamedToken) ToSecret(namespace string) *v1.Secret {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      NamedTokenSecretName(token.Name),
+			Namespace: namespace,
+			Labels:    map[string]string{NamedTokenLabel: strconv.FormatBool(true)},
+		},
+		Data: map[string][]byte{"token": []byte(token.Value)},
+	}
+	token.SetUsages(secret)
+
+	if !token.Expiration.IsZero() {
+		secret.Annotations[tokenExpirationAnnotation] = token.Expiration.UTC().Format(time.RFC3339)
+	}
+	if token.MaxUsages > 0 {
+		secret.Annotations[tokenMaxUsagesAnnotation] = strconv.Itoa(token.MaxUsages)
+	}
+	if token.AllowedClusterID != "" {
+		secret.Annotations[tokenAllowedClusterIDAnnotation] = token.AllowedClusterID
+	}
+	return secret
+}
+
+// SetUsages updates the number of usages stored in the given secret.
+func (token *NamedToken) SetUsages(secret *v1.Secret) {
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[tokenUsagesAnnotation] = strconv.Itoa(token.Usages)
+}
+
+// CheckValidity returns an error in case the named token cannot be used by the given remote cluster at the given time.
+func (token *NamedToken) CheckValidity(clusterID string, now time.Time) error {
+	switch {
+	case !token.Expiration.IsZero() && now.After(token.Expiration):
+		return fmt.Errorf("token %q expired at %v", token.Name, token.Expiration.Format(time.RFC3339))
+	case token.MaxUsages > 0 && token.Usages >= token.MaxUsages:
+		return fmt.Errorf("token %q already used %d times", token.Name, token.Usages)
+	case token.AllowedClusterID != "" && token.AllowedClusterID != clusterID:
+		return fmt.Errorf("token %q not allowed for cluster %q", token.Name, clusterID)
+	default:
+		return nil
+	}
+}
+
+// CreateNamedToken generates a new named token, with the given restrictions, and stores it in the given namespace.
+func CreateNamedToken(ctx context.Context, c client.Client, namespace, name string,
+	ttl time.Duration, maxUsages int, allowedClusterID string) (*NamedToken, error) {
+	value, err := GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	token := &NamedToken{
+		Name:             name,
+		Value:            value,
+		MaxUsages:        maxUsages,
+		AllowedClusterID: allowedClusterID,
+	}
+	if ttl > 0 {
+		token.Expiration = time.Now().Add(ttl)
+	}
+
+	if err = c.Create(ctx, token.ToSecret(namespace)); err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+	return token, nil
+}
+
+func parseUsages(secret *v1.Secret, annotation string) (int, error) {
+	value, ok := secret.GetAnnotations()[annotation]
+	if !ok {
+		return 0, nil
+	}
+
+	usages, err := strconv.Atoi(value)
+	if err != nil || usages < 0 {
+		return 0, fmt.Errorf("invalid secret %v/%v: invalid %v annotation %q", secret.GetNamespace(), secret.GetName(), annotation, value)
+	}
+	return usages, nil
+}
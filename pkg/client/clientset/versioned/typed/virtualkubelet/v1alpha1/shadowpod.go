@@ -39,6 +39,7 @@ type ShadowPodsGetter interface {
 type ShadowPodInterface interface {
 	Create(ctx context.Context, shadowPod *v1alpha1.ShadowPod, opts v1.CreateOptions) (*v1alpha1.ShadowPod, error)
 	Update(ctx context.Context, shadowPod *v1alpha1.ShadowPod, opts v1.UpdateOptions) (*v1alpha1.ShadowPod, error)
+	UpdateStatus(ctx context.Context, shadowPod *v1alpha1.ShadowPod, opts v1.UpdateOptions) (*v1alpha1.ShadowPod, error)
 	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
 	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
 	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.ShadowPod, error)
@@ -134,6 +135,22 @@ func (c *shadowPods) Update(ctx context.Context, shadowPod *v1alpha1.ShadowPod,
 	return
 }
 
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *shadowPods) UpdateStatus(ctx context.Context, shadowPod *v1alpha1.ShadowPod, opts v1.UpdateOptions) (result *v1alpha1.ShadowPod, err error) {
+	result = &v1alpha1.ShadowPod{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("shadowpods").
+		Name(shadowPod.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(shadowPod).
+		Do(ctx).
+		Into(result)
+	return
+}
+
 // Delete takes name of the shadowPod and deletes it. Returns an error if one occurs.
 func (c *shadowPods) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
 	return c.client.Delete().
@@ -26,6 +26,7 @@ import (
 type VirtualkubeletV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	NamespaceMapsGetter
+	ReflectionPoliciesGetter
 	ShadowPodsGetter
 }
 
@@ -38,6 +39,10 @@ func (c *VirtualkubeletV1alpha1Client) NamespaceMaps(namespace string) Namespace
 	return newNamespaceMaps(c, namespace)
 }
 
+func (c *VirtualkubeletV1alpha1Client) ReflectionPolicies() ReflectionPolicyInterface {
+	return newReflectionPolicies(c)
+}
+
 func (c *VirtualkubeletV1alpha1Client) ShadowPods(namespace string) ShadowPodInterface {
 	return newShadowPods(c, namespace)
 }
@@ -24,6 +24,10 @@ type NamespaceMapListerExpansion interface{}
 // NamespaceMapNamespaceLister.
 type NamespaceMapNamespaceListerExpansion interface{}
 
+// ReflectionPolicyListerExpansion allows custom methods to be added to
+// ReflectionPolicyLister.
+type ReflectionPolicyListerExpansion interface{}
+
 // ShadowPodListerExpansion allows custom methods to be added to
 // ShadowPodLister.
 type ShadowPodListerExpansion interface{}
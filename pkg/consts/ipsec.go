@@ -0,0 +1,27 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package consts
+
+const (
+	// IPsecDriverName name of the IPsec driver, which is also used as the type of the backend in the tunnelendpoint CRD.
+	IPsecDriverName = "ipsec"
+	// IPsecDeviceName name of the XFRM interface created on the custom network namespace.
+	IPsecDeviceName = "liqo.ipsec"
+	// IPsecInterfaceID identifier of the XFRM interface, which binds the IPsec states and policies to the interface.
+	IPsecInterfaceID = 0x11c0
+	// IPsecNonce is the key of the nonce of the IPsec driver, both in the secret storing the keys and in the backend
+	// configuration. The nonce is regenerated at every start of the driver, and mixed in the derivation of the keys.
+	IPsecNonce = "nonce"
+)
@@ -28,10 +28,18 @@ const (
 	GatewayServiceLabelKey = "net.liqo.io/gateway"
 	// GatewayServiceLabelValue value of the label used to get the service.
 	GatewayServiceLabelValue = "true"
+	// GatewayActiveLabelValue value of the GatewayServiceLabelKey label denoting the active gateway pod.
+	GatewayActiveLabelValue = "active"
 
 	// AuthAppName label value that denotes the name of the liqo-auth deployment.
 	AuthAppName = "auth"
 
+	// GatewayAppName label value that denotes the name of the liqo-gateway deployment.
+	GatewayAppName = "gateway"
+
+	// RouteAppName label value that denotes the name of the liqo-route daemonset.
+	RouteAppName = "route"
+
 	// NetworkManagerAppName label value that denotes the name of the liqo-network-manager deployment.
 	NetworkManagerAppName = "network-manager"
 
@@ -93,6 +93,9 @@ const (
 	DefaultMTU = 1440
 	// GatewayListeningPort port used by the vpn tunnel.
 	GatewayListeningPort = 5871
+	// GatewayProbePort port used, in the custom network namespace, to exchange the health probes
+	// with the remote gateways across the vpn tunnels.
+	GatewayProbePort = 5872
 
 	// **** Liqo Gateway Service ****.
 
@@ -37,6 +37,12 @@ const (
 	SchedulingLiqoLabel = "liqo.io/scheduling-enabled"
 	// SchedulingLiqoLabelValue unique value allowed for SchedulingLiqoLabel.
 	SchedulingLiqoLabelValue = "true"
+	// PodOffloadingStrategyAnnotationKey is the annotation that allows a pod to override the PodOffloadingStrategy
+	// of the NamespaceOffloading (within its bounds).
+	PodOffloadingStrategyAnnotationKey = "liqo.io/pod-offloading-strategy"
+	// ClusterSelectorAnnotationKey is the annotation that allows a pod to further restrict the clusters it can be
+	// offloaded to, through the label selector syntax (e.g., "region=eu,provider!=aws").
+	ClusterSelectorAnnotationKey = "liqo.io/cluster-selector"
 
 	// RemoteNamespaceManagedByAnnotationKey is the annotation that identifies the NamespaceMap managing a given remote namespace.
 	RemoteNamespaceManagedByAnnotationKey = "liqo.io/managed-by-namespace-map"
@@ -44,6 +44,12 @@ const (
 	ManagedByLabelKey = "liqo.io/managed-by"
 	// ManagedByShadowPodValue it the label value used to indicate that a given resource is managed by a ShadowPod.
 	ManagedByShadowPodValue = "shadowpod"
+	// ShadowPodManagedLabelsAnnotationKey is the annotation key set on the pods managed by a ShadowPod to track the labels
+	// propagated from the ShadowPod (comma separated), so that they can be removed once removed from the ShadowPod.
+	ShadowPodManagedLabelsAnnotationKey = "liqo.io/shadowpod-managed-labels"
+	// ShadowPodManagedAnnotationsAnnotationKey is the annotation key set on the pods managed by a ShadowPod to track the annotations
+	// propagated from the ShadowPod (comma separated), so that they can be removed once removed from the ShadowPod.
+	ShadowPodManagedAnnotationsAnnotationKey = "liqo.io/shadowpod-managed-annotations"
 
 	// LocalResourceOwnership label key added to a resource when it is owned by a local component.
 	// Ex. Local networkconfigs are owned by the component that creates them. If the resource is replicated in
@@ -53,4 +59,9 @@ const (
 	// ForceRemoteNodePortAnnotationKey is the annotation key used to indicate that a service should be forced to
 	// use the same node port on both clusters.
 	ForceRemoteNodePortAnnotationKey = "liqo.io/force-remote-node-port"
+
+	// SkipReflectionAnnotationKey is the annotation key used to indicate whether a given object shall be reflected
+	// to the remote clusters. If set to "true", the object is not reflected, while any other value does not
+	// override the ReflectionPolicies possibly matching it (i.e., the annotation can only restrict the reflection).
+	SkipReflectionAnnotationKey = "liqo.io/skip-reflection"
 )
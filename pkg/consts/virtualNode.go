@@ -21,3 +21,24 @@ const NodeFinalizer = "liqo.io/node"
 // VirtualKubeletFinalizer is the finalizer added on a ResourceOffer when the related VirtualKubelet is up.
 // (managed by the ResourceOffer Operator).
 const VirtualKubeletFinalizer = "liqo.io/virtualkubelet"
+
+// NodePoolLabel is the label set on the virtual nodes (and the related VirtualKubelet Deployments)
+// associated with a given node pool of the remote cluster.
+const NodePoolLabel = "liqo.io/node-pool"
+
+// PrimaryNodePoolAnnotation is the annotation set on a ForeignCluster to pin the node pool whose virtual kubelet is in charge
+// of reflecting all resources (while the other ones reflect only the pods scheduled on their node).
+const PrimaryNodePoolAnnotation = "liqo.io/primary-node-pool"
+
+// LargestAllocatableAnnotation is the annotation set on the virtual nodes to advertise the largest quantity of each resource
+// available on a single node of the remote cluster, in the form "cpu=2,memory=4Gi" (i.e., an upper bound for the resources
+// requested by a single pod which can be offloaded).
+const LargestAllocatableAnnotation = "liqo.io/largest-allocatable"
+
+// FragmentationAnnotation is the annotation set on the virtual nodes to advertise (in JSON format) how the available
+// resources are distributed across the nodes of the remote cluster.
+const FragmentationAnnotation = "liqo.io/resource-fragmentation"
+
+// ResourceUsageAnnotation is the annotation set on the virtual nodes to advertise the quantity of the offered resources
+// currently used in the remote cluster by the pods offloaded from the local cluster, in the form "cpu=2,memory=4Gi".
+const ResourceUsageAnnotation = "liqo.io/resource-usage"
@@ -85,7 +85,7 @@ func (certManager *identityManager) StoreCertificate(remoteCluster discoveryv1al
 	if secret.Labels == nil {
 		secret.Labels = map[string]string{}
 	}
-	secret.Labels[certificateAvailableLabel] = "true"
+	secret.Labels[CertificateAvailableLabel] = "true"
 
 	if identityResponse.HasAWSValues() || certManager.isAwsIdentity(secret) {
 		secret.Data[awsAccessKeyIDSecretKey] = []byte(identityResponse.AWSIdentityInfo.AccessKeyID)
@@ -93,6 +93,12 @@ func (certManager *identityManager) StoreCertificate(remoteCluster discoveryv1al
 		secret.Data[awsRegionSecretKey] = []byte(identityResponse.AWSIdentityInfo.Region)
 		secret.Data[awsEKSClusterIDSecretKey] = []byte(identityResponse.AWSIdentityInfo.EKSClusterID)
 		secret.Data[awsIAMUserArnSecretKey] = []byte(identityResponse.AWSIdentityInfo.IAMUserArn)
+	} else if identityResponse.HasOIDCValues() {
+		secret.Data[oidcTokenSecretKey] = []byte(identityResponse.OIDCIdentityInfo.Token)
+		secret.Data[oidcAudienceSecretKey] = []byte(identityResponse.OIDCIdentityInfo.Audience)
+		secret.Data[oidcServiceAccountSecretKey] = []byte(identityResponse.OIDCIdentityInfo.ServiceAccountName)
+		secret.Data[oidcExpirationSecondsSecretKey] = []byte(strconv.FormatInt(identityResponse.OIDCIdentityInfo.ExpirationSeconds, 10))
+		secret.Data[oidcExpirationSecretKey] = []byte(identityResponse.OIDCIdentityInfo.Expiration)
 	} else {
 		certificate, err := base64.StdEncoding.DecodeString(identityResponse.Certificate)
 		if err != nil {
@@ -101,6 +107,7 @@ func (certManager *identityManager) StoreCertificate(remoteCluster discoveryv1al
 		}
 
 		secret.Data[certificateSecretKey] = certificate
+		setCertificateExpireTime(secret, certificate)
 	}
 
 	// ApiServerCA may be empty if the remote cluster exposes the ApiServer with a certificate issued by "public" CAs
@@ -143,7 +150,7 @@ func (certManager *identityManager) getSecretInNamespace(remoteCluster discovery
 	namespace string) (*v1.Secret, error) {
 	labelSelector := metav1.LabelSelector{
 		MatchLabels: map[string]string{
-			localIdentitySecretLabel: "true",
+			LocalIdentitySecretLabel: "true",
 			discovery.ClusterIDLabel: remoteCluster.ClusterID,
 		},
 	}
@@ -236,7 +243,7 @@ func (certManager *identityManager) createIdentityInNamespace(remoteClusterID, n
 			GenerateName: identitySecretRoot + "-",
 			Namespace:    namespace,
 			Labels: map[string]string{
-				localIdentitySecretLabel: "true",
+				LocalIdentitySecretLabel: "true",
 				discovery.ClusterIDLabel: remoteClusterID,
 			},
 			Annotations: map[string]string{
@@ -285,3 +292,15 @@ func (certManager *identityManager) isAwsIdentity(secret *v1.Secret) bool {
 	}
 	return true
 }
+
+// isOIDCIdentity returns whether the given secret contains a token-based identity, issued through the OIDC identity provider.
+func isOIDCIdentity(secret *v1.Secret) bool {
+	data := secret.Data
+	keys := []string{oidcTokenSecretKey, oidcAudienceSecretKey, oidcServiceAccountSecretKey}
+	for i := range keys {
+		if _, ok := data[keys[i]]; !ok {
+			return false
+		}
+	}
+	return true
+}
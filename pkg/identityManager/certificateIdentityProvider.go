@@ -16,7 +16,10 @@ package identitymanager
 
 import (
 	"context"
+	"crypto/x509"
 	"encoding/base64"
+	"encoding/pem"
+	"errors"
 	"fmt"
 	"math/rand"
 	"strconv"
@@ -62,6 +65,12 @@ func (identityProvider *certificateIdentityProvider) GetRemoteCertificate(cluste
 		return response, err
 	}
 
+	if isIdentityRevoked(secret) {
+		err = newIdentityRevokedError(cluster)
+		klog.Error(err)
+		return response, err
+	}
+
 	signingRequestSecret, ok := secret.Data[csrSecretKey]
 	if !ok {
 		klog.Errorf("no %v key in secret %v/%v", csrSecretKey, secret.Namespace, secret.Name)
@@ -106,6 +115,67 @@ func (identityProvider *certificateIdentityProvider) ApproveSigningRequest(clust
 		return response, err
 	}
 
+	return identityProvider.issueCertificate(cluster, signingBytes)
+}
+
+// RenewSigningRequest issues a new certificate for a remote cluster which already owns a valid identity.
+// The request is authenticated verifying that the new CSR has been signed with the private key associated
+// with the certificate previously issued to the same cluster, which must not be expired yet.
+func (identityProvider *certificateIdentityProvider) RenewSigningRequest(cluster discoveryv1alpha1.ClusterIdentity,
+	namespace, signingRequest, signature string) (response *responsetypes.SigningRequestResponse, err error) {
+	secret, err := identityProvider.client.CoreV1().Secrets(namespace).Get(context.TODO(), remoteCertificateSecret, metav1.GetOptions{})
+	if err != nil {
+		klog.Error(err)
+		return response, err
+	}
+
+	if isIdentityRevoked(secret) {
+		err = newIdentityRevokedError(cluster)
+		klog.Error(err)
+		return response, err
+	}
+
+	certificate, ok := secret.Data[certificateSecretKey]
+	if !ok {
+		klog.Errorf("no %v key in secret %v/%v", certificateSecretKey, secret.Namespace, secret.Name)
+		err = kerrors.NewNotFound(schema.GroupResource{
+			Group:    "v1",
+			Resource: "secrets",
+		}, remoteCertificateSecret)
+		return response, err
+	}
+
+	signingBytes, err := base64.StdEncoding.DecodeString(signingRequest)
+	if err != nil {
+		klog.Error(err)
+		return response, kerrors.NewBadRequest(fmt.Sprintf("invalid CSR for cluster %s: %v", cluster.ClusterName, err))
+	}
+	signatureBytes, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		klog.Error(err)
+		return response, kerrors.NewBadRequest(fmt.Sprintf("invalid signature for cluster %s: %v", cluster.ClusterName, err))
+	}
+
+	if err = verifyRenewalSignature(certificate, signingBytes, signatureBytes, cluster.ClusterID); err != nil {
+		err = kerrors.NewUnauthorized(fmt.Sprintf("unable to authenticate the renewal request for cluster %s: %v", cluster.ClusterName, err))
+		klog.Error(err)
+		return response, err
+	}
+
+	return identityProvider.issueCertificate(cluster, signingBytes)
+}
+
+// issueCertificate creates a CertificateSigningRequest CR to be issued by the local cluster, and approves it.
+// The resulting certificate is stored in a Secret in the TenantNamespace, replacing the previous one (if any).
+func (identityProvider *certificateIdentityProvider) issueCertificate(cluster discoveryv1alpha1.ClusterIdentity,
+	signingBytes []byte) (response *responsetypes.SigningRequestResponse, err error) {
+	if err = validateSigningRequest(signingBytes, cluster.ClusterID); err != nil {
+		err = kerrors.NewForbidden(schema.GroupResource{Group: certv1.GroupName, Resource: "certificatesigningrequests"},
+			cluster.ClusterName, err)
+		klog.Error(err)
+		return response, err
+	}
+
 	cert := &certv1.CertificateSigningRequest{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: identitySecretRoot + "-",
@@ -158,7 +228,34 @@ func (identityProvider *certificateIdentityProvider) ApproveSigningRequest(clust
 	return response, nil
 }
 
-// storeRemoteCertificate stores the issued certificate in a Secret in the TenantNamespace.
+// validateSigningRequest checks that the given PEM encoded CSR is correctly signed, and that its subject matches
+// the one of the identities generated by Liqo for the given cluster. Since the subject of the certificates issued by
+// the Kubernetes client signer is mapped to the authenticated user (CN) and groups (O), this prevents a remote cluster
+// from obtaining a certificate impersonating another cluster, or belonging to additional groups.
+func validateSigningRequest(signingRequest []byte, clusterID string) error {
+	block, _ := pem.Decode(signingRequest)
+	if block == nil {
+		return errors.New("failed to decode the PEM certificate signing request")
+	}
+
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("failed to parse the certificate signing request: %w", err)
+	}
+	if err = csr.CheckSignature(); err != nil {
+		return fmt.Errorf("invalid certificate signing request signature: %w", err)
+	}
+
+	if csr.Subject.CommonName != clusterID {
+		return fmt.Errorf("the common name %q does not match the cluster ID %q", csr.Subject.CommonName, clusterID)
+	}
+	if len(csr.Subject.Organization) != 1 || csr.Subject.Organization[0] != defaultOrganization {
+		return fmt.Errorf("the organization %v does not match the expected one (%v)", csr.Subject.Organization, defaultOrganization)
+	}
+	return nil
+}
+
+// storeRemoteCertificate stores the issued certificate in a Secret in the TenantNamespace, or updates the existing one.
 func (identityProvider *certificateIdentityProvider) storeRemoteCertificate(cluster discoveryv1alpha1.ClusterIdentity,
 	signingRequest, certificate []byte) (*v1.Secret, error) {
 	namespace, err := identityProvider.namespaceManager.GetNamespace(cluster)
@@ -181,10 +278,26 @@ func (identityProvider *certificateIdentityProvider) storeRemoteCertificate(clus
 		},
 	}
 
-	if secret, err = identityProvider.client.CoreV1().
-		Secrets(namespace.Name).Create(context.TODO(), secret, metav1.CreateOptions{}); err != nil {
+	created, err := identityProvider.client.CoreV1().Secrets(namespace.Name).Create(context.TODO(), secret, metav1.CreateOptions{})
+	if err == nil {
+		return created, nil
+	}
+	if !kerrors.IsAlreadyExists(err) {
+		klog.Error(err)
+		return nil, err
+	}
+
+	// the secret already exists, since the identity of the remote cluster is being renewed
+	existing, err := identityProvider.client.CoreV1().Secrets(namespace.Name).Get(context.TODO(), remoteCertificateSecret, metav1.GetOptions{})
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+	existing.Data = secret.Data
+	if existing, err = identityProvider.client.CoreV1().
+		Secrets(namespace.Name).Update(context.TODO(), existing, metav1.UpdateOptions{}); err != nil {
 		klog.Error(err)
 		return nil, err
 	}
-	return secret, nil
+	return existing, nil
 }
@@ -48,6 +48,10 @@ func (certManager *identityManager) GetConfig(remoteCluster discoveryv1alpha1.Cl
 		return certManager.getIAMConfig(secret, remoteCluster)
 	}
 
+	if isOIDCIdentity(secret) {
+		return certManager.oidcTokenManager.getConfig(secret, remoteCluster)
+	}
+
 	return buildConfigFromSecret(secret, remoteCluster)
 }
 
@@ -124,18 +128,9 @@ func buildConfigFromSecret(secret *v1.Secret, remoteCluster discoveryv1alpha1.Cl
 		return nil, err
 	}
 
-	var proxyURL *url.URL
-	var proxyFunc func(*http.Request) (*url.URL, error)
-	proxyConfig, ok := secret.Data[apiProxyURLSecretKey]
-	if ok {
-		proxyURL, err = url.Parse(string(proxyConfig))
-		if err != nil {
-			klog.Errorf("an error occurred while parsing proxy url %s from secret %v/%v: %s", proxyConfig, secret.Namespace, secret.Name, err)
-			return nil, err
-		}
-		proxyFunc = func(request *http.Request) (*url.URL, error) {
-			return proxyURL, nil
-		}
+	proxyFunc, err := getProxyFunc(secret)
+	if err != nil {
+		return nil, err
 	}
 
 	// create the rest config that can be used to create a client
@@ -150,3 +145,20 @@ func buildConfigFromSecret(secret *v1.Secret, remoteCluster discoveryv1alpha1.Cl
 		Proxy: proxyFunc,
 	}, nil
 }
+
+// getProxyFunc returns the proxy function to reach the remote API server, if a proxy is configured in the given secret.
+func getProxyFunc(secret *v1.Secret) (func(*http.Request) (*url.URL, error), error) {
+	proxyConfig, ok := secret.Data[apiProxyURLSecretKey]
+	if !ok {
+		return nil, nil
+	}
+
+	proxyURL, err := url.Parse(string(proxyConfig))
+	if err != nil {
+		klog.Errorf("an error occurred while parsing proxy url %s from secret %v/%v: %s", proxyConfig, secret.Namespace, secret.Name, err)
+		return nil, err
+	}
+	return func(request *http.Request) (*url.URL, error) {
+		return proxyURL, nil
+	}, nil
+}
@@ -14,16 +14,26 @@
 
 package identitymanager
 
-const defaultOrganization = "liqo.io"
+import tenantnamespace "github.com/liqotech/liqo/pkg/tenantNamespace"
+
+// defaultOrganization is the organization of the certificates issued to the remote clusters,
+// which corresponds to the group they belong to once authenticated.
+const defaultOrganization = tenantnamespace.RemoteClusterGroup
 
 const (
-	localIdentitySecretLabel  = "discovery.liqo.io/local-identity"
-	remoteTenantCSRLabel      = "discovery.liqo.io/remote-tenant-csr"
-	certificateAvailableLabel = "discovery.liqo.io/certificate-available"
+	// LocalIdentitySecretLabel is the label set on the secrets storing the identities used to interact with remote clusters.
+	LocalIdentitySecretLabel = "discovery.liqo.io/local-identity"
+	remoteTenantCSRLabel     = "discovery.liqo.io/remote-tenant-csr"
+	// CertificateAvailableLabel is the label set on the identity secrets once the certificate has been issued.
+	CertificateAvailableLabel = "discovery.liqo.io/certificate-available"
+	identityRevokedLabel      = "discovery.liqo.io/identity-revoked"
 )
 
 const (
 	certificateExpireTimeAnnotation = "discovery.liqo.io/certificate-expire-time"
+	// IdentityPendingRestartAnnotation is the annotation set on the identity secrets once renewed, until the components
+	// consuming the identity have been restarted. Its value is the time of the renewal.
+	IdentityPendingRestartAnnotation = "discovery.liqo.io/identity-pending-restart"
 )
 
 const (
@@ -44,4 +54,18 @@ const (
 	awsRegionSecretKey          = "awsRegion"
 	awsEKSClusterIDSecretKey    = "awsEksClusterID" // nolint:gosec // not a credential
 	awsIAMUserArnSecretKey      = "awsIamUserArn"   // nolint:gosec // not a credential
+
+	oidcTokenSecretKey             = "oidcToken"
+	oidcAudienceSecretKey          = "oidcAudience"
+	oidcServiceAccountSecretKey    = "oidcServiceAccount"
+	oidcExpirationSecondsSecretKey = "oidcExpirationSeconds"
+	oidcExpirationSecretKey        = "oidcExpiration"
+)
+
+const (
+	// remoteIdentityServiceAccountName is the name of the ServiceAccount representing the remote cluster in its tenant namespace,
+	// whose tokens are issued as identities by the OIDC identity provider.
+	remoteIdentityServiceAccountName = tenantnamespace.RemoteIdentityServiceAccountName
+	// remoteIdentityRoleRoot is the name of the role allowing the ServiceAccount representing the remote cluster to refresh its tokens.
+	remoteIdentityRoleRoot = "liqo-remote-identity"
 )
@@ -54,13 +54,26 @@ type mapUser struct {
 
 func (identityProvider *iamIdentityProvider) GetRemoteCertificate(cluster discoveryv1alpha1.ClusterIdentity,
 	namespace, signingRequest string) (response *responsetypes.SigningRequestResponse, err error) {
-	// this method has no meaning for this identity provider
+	if err = ensureIdentityNotRevoked(identityProvider.client, cluster, namespace); err != nil {
+		return response, err
+	}
+
+	// this method has no meaning for this identity provider, apart from checking whether the identity has been revoked
 	return response, kerrors.NewNotFound(schema.GroupResource{
 		Group:    "v1",
 		Resource: "secrets",
 	}, remoteCertificateSecret)
 }
 
+func (identityProvider *iamIdentityProvider) RenewSigningRequest(cluster discoveryv1alpha1.ClusterIdentity,
+	namespace, signingRequest, signature string) (response *responsetypes.SigningRequestResponse, err error) {
+	// the IAM credentials do not expire, hence there is nothing to renew
+	return response, kerrors.NewMethodNotSupported(schema.GroupResource{
+		Group:    "v1",
+		Resource: "secrets",
+	}, "renew")
+}
+
 func (identityProvider *iamIdentityProvider) ApproveSigningRequest(cluster discoveryv1alpha1.ClusterIdentity,
 	signingRequest string) (response *responsetypes.SigningRequestResponse, err error) {
 	sess, err := session.NewSession(&aws.Config{
@@ -35,7 +35,8 @@ type identityManager struct {
 	localCluster     discoveryv1alpha1.ClusterIdentity
 	namespaceManager tenantnamespace.Manager
 
-	iamTokenManager tokenManager
+	iamTokenManager  tokenManager
+	oidcTokenManager tokenManager
 }
 
 // NewCertificateIdentityReader gets a new certificate identity reader.
@@ -104,6 +105,19 @@ func NewIAMIdentityProvider(client kubernetes.Interface,
 	return newIdentityManager(client, localCluster, namespaceManager, idProvider)
 }
 
+// NewOIDCIdentityProvider gets a new identity approver issuing service account tokens, federated through an OIDC issuer.
+func NewOIDCIdentityProvider(client kubernetes.Interface,
+	localCluster discoveryv1alpha1.ClusterIdentity, oidcConfig *OIDCConfig,
+	namespaceManager tenantnamespace.Manager) IdentityProvider {
+	idProvider := &oidcIdentityProvider{
+		oidcConfig:       oidcConfig,
+		client:           client,
+		namespaceManager: namespaceManager,
+	}
+
+	return newIdentityManager(client, localCluster, namespaceManager, idProvider)
+}
+
 func newIdentityManager(client kubernetes.Interface,
 	localCluster discoveryv1alpha1.ClusterIdentity,
 	namespaceManager tenantnamespace.Manager,
@@ -115,6 +129,14 @@ func newIdentityManager(client kubernetes.Interface,
 	}
 	iamTokenManager.start(context.TODO())
 
+	oidcTokenManager := &oidcTokenManager{
+		client:                    client,
+		localCluster:              localCluster,
+		availableClusterIDSecrets: map[string]types.NamespacedName{},
+		tokenFiles:                map[string]string{},
+	}
+	oidcTokenManager.start(context.TODO())
+
 	return &identityManager{
 		client:           client,
 		localCluster:     localCluster,
@@ -122,6 +144,7 @@ func newIdentityManager(client kubernetes.Interface,
 
 		IdentityProvider: idProvider,
 
-		iamTokenManager: iamTokenManager,
+		iamTokenManager:  iamTokenManager,
+		oidcTokenManager: oidcTokenManager,
 	}
 }
@@ -17,9 +17,13 @@ package identitymanager
 import (
 	"context"
 	"crypto/ed25519"
+	"crypto/rand"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/base64"
 	"encoding/pem"
+	"errors"
+	"math/big"
 	"os"
 	"path/filepath"
 	"testing"
@@ -31,7 +35,9 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
@@ -199,7 +205,7 @@ var _ = Describe("IdentityManager", func() {
 			Expect(secret.Namespace).To(Equal(namespace.Name))
 
 			Expect(secret.Labels).NotTo(BeNil())
-			_, ok := secret.Labels[localIdentitySecretLabel]
+			_, ok := secret.Labels[LocalIdentitySecretLabel]
 			Expect(ok).To(BeTrue())
 			v, ok := secret.Labels[discovery.ClusterIDLabel]
 			Expect(ok).To(BeTrue())
@@ -255,8 +261,13 @@ var _ = Describe("IdentityManager", func() {
 		var stopChan chan struct{}
 
 		BeforeEach(func() {
-			csrBytes, err = identityMan.GetSigningRequest(remoteCluster)
-			Expect(err).To(BeNil())
+			// the CSR is generated by the identity manager of the remote cluster, hence it refers to its cluster ID
+			if csrBytes == nil {
+				remoteIdMan, ok := NewCertificateIdentityManager(client, remoteCluster, namespaceManager).(*identityManager)
+				Expect(ok).To(BeTrue())
+				_, csrBytes, err = remoteIdMan.createCSR()
+				Expect(err).To(BeNil())
+			}
 
 			stopChan = make(chan struct{})
 			idManTest.StartTestApprover(client, stopChan)
@@ -273,6 +284,13 @@ var _ = Describe("IdentityManager", func() {
 			Expect(certificate.Certificate).To(Equal([]byte(idManTest.FakeCRT)))
 		})
 
+		It("Approve Signing Request referring to another cluster", func() {
+			otherCSR, err := identityMan.GetSigningRequest(remoteCluster)
+			Expect(err).To(BeNil())
+			_, err = identityProvider.ApproveSigningRequest(remoteCluster, base64.StdEncoding.EncodeToString(otherCSR))
+			Expect(kerrors.IsForbidden(err)).To(BeTrue())
+		})
+
 		It("Retrieve Remote Certificate", func() {
 			certificate, err := identityProvider.GetRemoteCertificate(remoteCluster, namespace.Name, base64.StdEncoding.EncodeToString(csrBytes))
 			Expect(err).To(BeNil())
@@ -417,6 +435,244 @@ var _ = Describe("IdentityManager", func() {
 
 	})
 
+	Context("Renewal", func() {
+
+		var (
+			renewalCluster   discoveryv1alpha1.ClusterIdentity
+			renewalNamespace *v1.Namespace
+			currentKey       ed25519.PrivateKey
+			stopChan         chan struct{}
+		)
+
+		generateCertificate := func(commonName string, key ed25519.PrivateKey, notBefore, notAfter time.Time) []byte {
+			template := x509.Certificate{
+				SerialNumber: big.NewInt(1),
+				Subject:      pkix.Name{CommonName: commonName, Organization: []string{defaultOrganization}},
+				NotBefore:    notBefore,
+				NotAfter:     notAfter,
+			}
+			certificate, err := x509.CreateCertificate(rand.Reader, &template, &template, key.Public(), key)
+			Expect(err).ToNot(HaveOccurred())
+			return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate})
+		}
+
+		BeforeEach(func() {
+			renewalCluster = discoveryv1alpha1.ClusterIdentity{
+				ClusterID:   "renewal-cluster-id",
+				ClusterName: "renewal-cluster-name",
+			}
+
+			var err error
+			renewalNamespace, err = namespaceManager.CreateNamespace(renewalCluster)
+			Expect(err).ToNot(HaveOccurred())
+			Eventually(func() (*v1.Namespace, error) { return namespaceManager.GetNamespace(renewalCluster) }).Should(Equal(renewalNamespace))
+
+			_, currentKey, err = ed25519.GenerateKey(rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+
+			stopChan = make(chan struct{})
+			idManTest.StartTestApprover(client, stopChan)
+		})
+
+		AfterEach(func() {
+			close(stopChan)
+		})
+
+		It("Verify Renewal Signature", func() {
+			id := renewalCluster.ClusterID
+			certificate := generateCertificate(id, currentKey, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+			csrBytes := []byte("csr")
+
+			Expect(verifyRenewalSignature(certificate, csrBytes, ed25519.Sign(currentKey, csrBytes), id)).To(Succeed())
+			Expect(verifyRenewalSignature(certificate, []byte("other"), ed25519.Sign(currentKey, csrBytes), id)).ToNot(Succeed())
+			Expect(verifyRenewalSignature(certificate, csrBytes, ed25519.Sign(currentKey, csrBytes), "other-cluster-id")).ToNot(Succeed())
+
+			_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(verifyRenewalSignature(certificate, csrBytes, ed25519.Sign(otherKey, csrBytes), id)).ToNot(Succeed())
+
+			expired := generateCertificate(id, currentKey, time.Now().Add(-2*time.Hour), time.Now().Add(-time.Hour))
+			Expect(verifyRenewalSignature(expired, csrBytes, ed25519.Sign(currentKey, csrBytes), id)).ToNot(Succeed())
+		})
+
+		It("Validate Signing Request", func() {
+			generateCSR := func(subject pkix.Name) []byte {
+				_, key, err := ed25519.GenerateKey(rand.Reader)
+				Expect(err).ToNot(HaveOccurred())
+				csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: subject}, key)
+				Expect(err).ToNot(HaveOccurred())
+				return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr})
+			}
+
+			id := renewalCluster.ClusterID
+			Expect(validateSigningRequest(generateCSR(pkix.Name{CommonName: id, Organization: []string{defaultOrganization}}), id)).To(Succeed())
+			Expect(validateSigningRequest(generateCSR(pkix.Name{CommonName: "other", Organization: []string{defaultOrganization}}), id)).ToNot(Succeed())
+			Expect(validateSigningRequest(generateCSR(pkix.Name{CommonName: id, Organization: []string{"system:masters"}}), id)).ToNot(Succeed())
+			Expect(validateSigningRequest(generateCSR(pkix.Name{CommonName: id,
+				Organization: []string{defaultOrganization, "system:masters"}}), id)).ToNot(Succeed())
+			Expect(validateSigningRequest([]byte("invalid"), id)).ToNot(Succeed())
+		})
+
+		It("Renew Signing Request", func() {
+			certificate := generateCertificate(renewalCluster.ClusterID, currentKey, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+			_, err := client.CoreV1().Secrets(renewalNamespace.Name).Create(ctx, &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: remoteCertificateSecret, Namespace: renewalNamespace.Name},
+				Data:       map[string][]byte{csrSecretKey: []byte("old-csr"), certificateSecretKey: certificate},
+			}, metav1.CreateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			idMan, ok := NewCertificateIdentityManager(client, renewalCluster, namespaceManager).(*identityManager)
+			Expect(ok).To(BeTrue())
+			_, csrBytes, err := idMan.createCSR()
+			Expect(err).ToNot(HaveOccurred())
+			signingRequest := base64.StdEncoding.EncodeToString(csrBytes)
+
+			_, err = identityProvider.RenewSigningRequest(renewalCluster, renewalNamespace.Name, signingRequest,
+				base64.StdEncoding.EncodeToString([]byte("invalid")))
+			Expect(kerrors.IsUnauthorized(err)).To(BeTrue())
+
+			response, err := identityProvider.RenewSigningRequest(renewalCluster, renewalNamespace.Name, signingRequest,
+				base64.StdEncoding.EncodeToString(ed25519.Sign(currentKey, csrBytes)))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Certificate).To(Equal([]byte(idManTest.FakeCRT)))
+
+			// the stored certificate has been replaced by the renewed one
+			renewed, err := identityProvider.GetRemoteCertificate(renewalCluster, renewalNamespace.Name, signingRequest)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(renewed.Certificate).To(Equal([]byte(idManTest.FakeCRT)))
+		})
+
+		It("Renew Identity", func() {
+			secret, err := identityMan.CreateIdentity(renewalCluster)
+			Expect(err).ToNot(HaveOccurred())
+
+			// replace the generated key with a known one, and store the corresponding certificate
+			keyBytes, err := x509.MarshalPKCS8PrivateKey(currentKey)
+			Expect(err).ToNot(HaveOccurred())
+			secret.Data[privateKeySecretKey] = pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyBytes})
+			secret.Data[certificateSecretKey] = generateCertificate(localCluster.ClusterID, currentKey, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+			_, err = client.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+
+			var newCertificate []byte
+			notAfter := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+			renewer := func(ctx context.Context, request *auth.CertificateRenewalRequest) (*auth.CertificateIdentityResponse, error) {
+				csrBytes, err := base64.StdEncoding.DecodeString(request.CertificateSigningRequest)
+				Expect(err).ToNot(HaveOccurred())
+				signature, err := base64.StdEncoding.DecodeString(request.Signature)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(ed25519.Verify(currentKey.Public().(ed25519.PublicKey), csrBytes, signature)).To(BeTrue())
+
+				_, newKey, err := ed25519.GenerateKey(rand.Reader)
+				Expect(err).ToNot(HaveOccurred())
+				newCertificate = generateCertificate(localCluster.ClusterID, newKey, time.Now(), notAfter)
+				return &auth.CertificateIdentityResponse{Certificate: base64.StdEncoding.EncodeToString(newCertificate)}, nil
+			}
+			Expect(identityMan.RenewIdentity(ctx, renewalCluster, renewer)).To(Succeed())
+
+			renewed, err := client.CoreV1().Secrets(secret.Namespace).Get(ctx, secret.Name, metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(renewed.Data[certificateSecretKey]).To(Equal(newCertificate))
+			Expect(renewed.Data[privateKeySecretKey]).ToNot(Equal(secret.Data[privateKeySecretKey]))
+			Expect(renewed.Data[csrSecretKey]).ToNot(Equal(secret.Data[csrSecretKey]))
+			Expect(getExpireTime(renewed)).To(Equal(notAfter.Unix()))
+			Expect(renewed.Annotations).To(HaveKey(IdentityPendingRestartAnnotation))
+
+			_, expiration, err := CertificateValidity(renewed)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(expiration.Unix()).To(Equal(notAfter.Unix()))
+		})
+
+		It("Renew Identity failure", func() {
+			secret, err := identityMan.CreateIdentity(renewalCluster)
+			Expect(err).ToNot(HaveOccurred())
+
+			renewer := func(ctx context.Context, request *auth.CertificateRenewalRequest) (*auth.CertificateIdentityResponse, error) {
+				return nil, errors.New("renewal refused")
+			}
+			Expect(identityMan.RenewIdentity(ctx, renewalCluster, renewer)).ToNot(Succeed())
+
+			// the identity is left untouched
+			current, err := client.CoreV1().Secrets(secret.Namespace).Get(ctx, secret.Name, metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(current.Data).To(Equal(secret.Data))
+			Expect(current.Annotations).ToNot(HaveKey(IdentityPendingRestartAnnotation))
+		})
+
+	})
+
+	Context("Revocation", func() {
+
+		var (
+			revocationCluster   discoveryv1alpha1.ClusterIdentity
+			revocationNamespace *v1.Namespace
+		)
+
+		BeforeEach(func() {
+			revocationCluster = discoveryv1alpha1.ClusterIdentity{
+				ClusterID:   "revocation-cluster-id",
+				ClusterName: "revocation-cluster-name",
+			}
+
+			var err error
+			revocationNamespace, err = namespaceManager.CreateNamespace(revocationCluster)
+			Expect(err).ToNot(HaveOccurred())
+			Eventually(func() (*v1.Namespace, error) {
+				return namespaceManager.GetNamespace(revocationCluster)
+			}).Should(Equal(revocationNamespace))
+		})
+
+		AfterEach(func() {
+			err := client.CoreV1().Secrets(revocationNamespace.Name).Delete(ctx, remoteCertificateSecret, metav1.DeleteOptions{})
+			Expect(err == nil || kerrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("Revoke an issued identity", func() {
+			signingRequest := []byte("csr")
+			_, err := client.CoreV1().Secrets(revocationNamespace.Name).Create(ctx, &v1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: remoteCertificateSecret, Namespace: revocationNamespace.Name},
+				Data:       map[string][]byte{csrSecretKey: signingRequest, certificateSecretKey: []byte(idManTest.FakeCRT)},
+			}, metav1.CreateOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			encodedRequest := base64.StdEncoding.EncodeToString(signingRequest)
+
+			Expect(identityMan.IsIdentityRevoked(revocationCluster)).To(BeFalse())
+			_, err = identityProvider.GetRemoteCertificate(revocationCluster, revocationNamespace.Name, encodedRequest)
+			Expect(err).ToNot(HaveOccurred())
+
+			By("Revoking the identity")
+			Expect(identityMan.RevokeIdentity(revocationCluster)).To(Succeed())
+			Expect(identityMan.IsIdentityRevoked(revocationCluster)).To(BeTrue())
+
+			_, err = identityProvider.GetRemoteCertificate(revocationCluster, revocationNamespace.Name, encodedRequest)
+			Expect(kerrors.IsForbidden(err)).To(BeTrue())
+			_, err = identityProvider.RenewSigningRequest(revocationCluster, revocationNamespace.Name, encodedRequest, encodedRequest)
+			Expect(kerrors.IsForbidden(err)).To(BeTrue())
+
+			By("Re-approving the identity")
+			Expect(identityMan.ReapproveIdentity(revocationCluster)).To(Succeed())
+			Expect(identityMan.IsIdentityRevoked(revocationCluster)).To(BeFalse())
+
+			certificate, err := identityProvider.GetRemoteCertificate(revocationCluster, revocationNamespace.Name, encodedRequest)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(certificate.Certificate).To(Equal([]byte(idManTest.FakeCRT)))
+		})
+
+		It("Revoke an identity not yet issued", func() {
+			Expect(identityMan.RevokeIdentity(revocationCluster)).To(Succeed())
+			Expect(identityMan.IsIdentityRevoked(revocationCluster)).To(BeTrue())
+
+			// the identity requests are rejected, rather than being processed as new ones
+			_, err := identityProvider.GetRemoteCertificate(revocationCluster, revocationNamespace.Name, "csr")
+			Expect(kerrors.IsForbidden(err)).To(BeTrue())
+
+			Expect(identityMan.ReapproveIdentity(revocationCluster)).To(Succeed())
+			_, err = identityProvider.GetRemoteCertificate(revocationCluster, revocationNamespace.Name, "csr")
+			Expect(kerrors.IsNotFound(err)).To(BeTrue())
+		})
+
+	})
+
 	Context("buildConfigFromSecret", func() {
 
 		var (
@@ -573,4 +829,135 @@ var _ = Describe("IdentityManager", func() {
 
 	})
 
+	Context("OIDC", func() {
+
+		var (
+			oidcCluster   discoveryv1alpha1.ClusterIdentity
+			oidcNamespace *v1.Namespace
+			oidcConfig    *OIDCConfig
+		)
+
+		BeforeEach(func() {
+			oidcCluster = discoveryv1alpha1.ClusterIdentity{
+				ClusterID:   "oidc-cluster-id",
+				ClusterName: "oidc-cluster-name",
+			}
+			oidcConfig = &OIDCConfig{Audience: "liqo-test", TokenExpiration: time.Hour}
+
+			var err error
+			oidcNamespace, err = namespaceManager.CreateNamespace(oidcCluster)
+			Expect(err).ToNot(HaveOccurred())
+			Eventually(func() (*v1.Namespace, error) {
+				return namespaceManager.GetNamespace(oidcCluster)
+			}).Should(Equal(oidcNamespace))
+		})
+
+		It("OIDC Identity Provider", func() {
+			idProvider := NewOIDCIdentityProvider(client, localCluster, oidcConfig, namespaceManager)
+
+			oidcIDManager, ok := idProvider.(*identityManager)
+			Expect(ok).To(BeTrue())
+
+			_, ok = oidcIDManager.IdentityProvider.(*oidcIdentityProvider)
+			Expect(ok).To(BeTrue())
+		})
+
+		It("Issue and store a service account token", func() {
+			idProvider := NewOIDCIdentityProvider(client, localCluster, oidcConfig, namespaceManager)
+
+			_, err := idProvider.GetRemoteCertificate(oidcCluster, oidcNamespace.Name, "")
+			Expect(kerrors.IsNotFound(err)).To(BeTrue())
+
+			response, err := idProvider.ApproveSigningRequest(oidcCluster, "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.ResponseType).To(Equal(responsetypes.SigningRequestResponseOIDC))
+			Expect(response.OIDCIdentityResponse.Token).ToNot(BeEmpty())
+			Expect(response.OIDCIdentityResponse.Audience).To(Equal(oidcConfig.Audience))
+			Expect(response.OIDCIdentityResponse.ServiceAccountName).To(Equal(remoteIdentityServiceAccountName))
+			Expect(response.OIDCIdentityResponse.ExpirationSeconds).To(BeNumerically("==", time.Hour.Seconds()))
+
+			By("Checking the permissions granted to the service account")
+			_, err = client.CoreV1().ServiceAccounts(oidcNamespace.Name).Get(ctx, remoteIdentityServiceAccountName, metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			_, err = client.RbacV1().RoleBindings(oidcNamespace.Name).Get(ctx, remoteIdentityRoleRoot, metav1.GetOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			// no cluster-wide permission is granted, as the service account is directly bound in the tenant namespace
+			clusterRoleBindings, err := client.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(clusterRoleBindings.Items).ToNot(ContainElement(HaveField("Subjects", ContainElement(serviceAccountSubject(oidcNamespace.Name)))))
+
+			// issuing a new identity for the same cluster shall succeed as well
+			_, err = idProvider.ApproveSigningRequest(oidcCluster, "")
+			Expect(err).ToNot(HaveOccurred())
+
+			By("Storing the identity on the requesting cluster")
+			identityResponse, err := auth.NewCertificateIdentityResponse("remoteNamespace", response, apiServerConfig)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(identityResponse.HasOIDCValues()).To(BeTrue())
+
+			_, err = identityMan.CreateIdentity(oidcCluster)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(identityMan.StoreCertificate(oidcCluster, apiProxyURL, identityResponse)).To(Succeed())
+
+			idMan, ok := identityMan.(*identityManager)
+			Expect(ok).To(BeTrue())
+			secret, err := idMan.getSecret(oidcCluster)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(isOIDCIdentity(secret)).To(BeTrue())
+			Expect(idMan.isAwsIdentity(secret)).To(BeFalse())
+			Expect(secret.Data[oidcTokenSecretKey]).To(Equal([]byte(response.OIDCIdentityResponse.Token)))
+			Expect(oidcTokenNeedsRefresh(secret, time.Now())).To(BeFalse())
+			Expect(oidcTokenNeedsRefresh(secret, time.Now().Add(45*time.Minute))).To(BeTrue())
+
+			By("Retrieving the rest config")
+			cnf, err := identityMan.GetConfig(oidcCluster, "")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cnf.Host).To(Equal(identityResponse.APIServerURL))
+			Expect(cnf.Proxy).NotTo(BeNil())
+			Expect(cnf.Impersonate.UserName).To(BeEmpty())
+			defer os.Remove(cnf.BearerTokenFile)
+
+			token, err := os.ReadFile(cnf.BearerTokenFile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(token).To(Equal([]byte(response.OIDCIdentityResponse.Token)))
+
+			Expect(identityMan.RenewIdentity(ctx, oidcCluster, nil)).ToNot(Succeed())
+		})
+
+		It("Reject the identity requests once revoked", func() {
+			idProvider := NewOIDCIdentityProvider(client, localCluster, oidcConfig, namespaceManager)
+
+			Expect(identityMan.RevokeIdentity(oidcCluster)).To(Succeed())
+			defer func() { Expect(identityMan.ReapproveIdentity(oidcCluster)).To(Succeed()) }()
+
+			_, err := idProvider.GetRemoteCertificate(oidcCluster, oidcNamespace.Name, "")
+			Expect(kerrors.IsForbidden(err)).To(BeTrue())
+			_, err = idProvider.ApproveSigningRequest(oidcCluster, "")
+			Expect(kerrors.IsForbidden(err)).To(BeTrue())
+		})
+
+		It("Remove the token refresh permission once revoked", func() {
+			idProvider := NewOIDCIdentityProvider(client, localCluster, oidcConfig, namespaceManager)
+			_, err := idProvider.ApproveSigningRequest(oidcCluster, "")
+			Expect(err).ToNot(HaveOccurred())
+
+			roleBindings := func() []rbacv1.RoleBinding {
+				list, err := client.RbacV1().RoleBindings(oidcNamespace.Name).List(ctx, metav1.ListOptions{})
+				Expect(err).ToNot(HaveOccurred())
+				return list.Items
+			}
+			bound := HaveField("Subjects", ContainElement(serviceAccountSubject(oidcNamespace.Name)))
+			Expect(roleBindings()).To(ContainElement(bound))
+
+			By("Revoking the identity")
+			Expect(identityMan.RevokeIdentity(oidcCluster)).To(Succeed())
+			Expect(roleBindings()).ToNot(ContainElement(bound))
+
+			By("Re-approving the identity")
+			Expect(identityMan.ReapproveIdentity(oidcCluster)).To(Succeed())
+			Expect(roleBindings()).To(ContainElement(bound))
+		})
+
+	})
+
 })
@@ -15,6 +15,8 @@
 package identitymanager
 
 import (
+	"context"
+
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/rest"
 
@@ -36,12 +38,22 @@ type IdentityManager interface {
 	CreateIdentity(remoteCluster discoveryv1alpha1.ClusterIdentity) (*v1.Secret, error)
 	GetSigningRequest(remoteCluster discoveryv1alpha1.ClusterIdentity) ([]byte, error)
 	StoreCertificate(remoteCluster discoveryv1alpha1.ClusterIdentity, remoteProxyURL string, identityResponse *auth.CertificateIdentityResponse) error
+	RenewIdentity(ctx context.Context, remoteCluster discoveryv1alpha1.ClusterIdentity, renewer CertificateRenewer) error
+
+	RevokeIdentity(remoteCluster discoveryv1alpha1.ClusterIdentity) error
+	ReapproveIdentity(remoteCluster discoveryv1alpha1.ClusterIdentity) error
+	IsIdentityRevoked(remoteCluster discoveryv1alpha1.ClusterIdentity) (bool, error)
 }
 
+// CertificateRenewer sends a CertificateRenewalRequest to the remote cluster, and returns the obtained identity.
+type CertificateRenewer func(ctx context.Context, request *auth.CertificateRenewalRequest) (*auth.CertificateIdentityResponse, error)
+
 // IdentityProvider provides the interface to retrieve and approve remote cluster identities.
 type IdentityProvider interface {
 	GetRemoteCertificate(cluster discoveryv1alpha1.ClusterIdentity,
 		namespace, signingRequest string) (response *responsetypes.SigningRequestResponse, err error)
 	ApproveSigningRequest(cluster discoveryv1alpha1.ClusterIdentity,
 		signingRequest string) (response *responsetypes.SigningRequestResponse, err error)
+	RenewSigningRequest(cluster discoveryv1alpha1.ClusterIdentity,
+		namespace, signingRequest, signature string) (response *responsetypes.SigningRequestResponse, err error)
 }
@@ -0,0 +1,31 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package identitymanager
+
+import "time"
+
+// OIDCConfig contains the configuration of the identity provider issuing service account tokens,
+// which are federated through an OIDC issuer trusted by the local API server.
+type OIDCConfig struct {
+	// Audience is the audience of the issued tokens, which must match the one expected by the OIDC authenticator.
+	Audience string
+	// TokenExpiration is the requested validity of the issued tokens.
+	TokenExpiration time.Duration
+}
+
+// IsEmpty indicates that some of the required values is not set.
+func (oc *OIDCConfig) IsEmpty() bool {
+	return oc == nil || oc.Audience == ""
+}
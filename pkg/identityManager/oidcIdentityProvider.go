@@ -0,0 +1,197 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package identitymanager
+
+import (
+	"context"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	responsetypes "github.com/liqotech/liqo/pkg/identityManager/responseTypes"
+	tenantnamespace "github.com/liqotech/liqo/pkg/tenantNamespace"
+)
+
+// oidcIdentityProvider issues to the remote clusters the tokens of a ServiceAccount created in their tenant namespace,
+// with the configured audience. The local API server is expected to authenticate them through an OIDC authenticator
+// trusting the ServiceAccount issuer. The ServiceAccount is bound, along with the user associated with the remote cluster,
+// to the permissions granted to it (hence, obtaining the same permissions granted to certificate-based identities),
+// and it is allowed to request new tokens for itself, to refresh the identity before its expiration.
+type oidcIdentityProvider struct {
+	oidcConfig       *OIDCConfig
+	client           kubernetes.Interface
+	namespaceManager tenantnamespace.Manager
+}
+
+func (identityProvider *oidcIdentityProvider) GetRemoteCertificate(cluster discoveryv1alpha1.ClusterIdentity,
+	namespace, signingRequest string) (response *responsetypes.SigningRequestResponse, err error) {
+	if err = ensureIdentityNotRevoked(identityProvider.client, cluster, namespace); err != nil {
+		return response, err
+	}
+
+	// this method has no meaning for this identity provider, apart from checking whether the identity has been revoked
+	return response, kerrors.NewNotFound(schema.GroupResource{
+		Group:    "v1",
+		Resource: "secrets",
+	}, remoteCertificateSecret)
+}
+
+func (identityProvider *oidcIdentityProvider) RenewSigningRequest(cluster discoveryv1alpha1.ClusterIdentity,
+	namespace, signingRequest, signature string) (response *responsetypes.SigningRequestResponse, err error) {
+	// the tokens are refreshed directly by the remote cluster, requesting them to the API server
+	return response, kerrors.NewMethodNotSupported(schema.GroupResource{
+		Group:    "v1",
+		Resource: "secrets",
+	}, "renew")
+}
+
+func (identityProvider *oidcIdentityProvider) ApproveSigningRequest(cluster discoveryv1alpha1.ClusterIdentity,
+	signingRequest string) (response *responsetypes.SigningRequestResponse, err error) {
+	ctx := context.TODO()
+
+	namespace, err := identityProvider.namespaceManager.GetNamespace(cluster)
+	if err != nil {
+		klog.Error(err)
+		return response, err
+	}
+
+	// the identity requests are normally rejected earlier, but the permissions must not be granted in any case
+	if err = ensureIdentityNotRevoked(identityProvider.client, cluster, namespace.Name); err != nil {
+		klog.Error(err)
+		return response, err
+	}
+
+	if err = identityProvider.ensureServiceAccount(ctx, namespace.Name); err != nil {
+		klog.Error(err)
+		return response, err
+	}
+
+	if err = identityProvider.ensureTokenRefreshPermission(ctx, namespace.Name); err != nil {
+		klog.Error(err)
+		return response, err
+	}
+
+	tokenRequest := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			Audiences: []string{identityProvider.oidcConfig.Audience},
+		},
+	}
+	if identityProvider.oidcConfig.TokenExpiration > 0 {
+		expirationSeconds := int64(identityProvider.oidcConfig.TokenExpiration.Seconds())
+		tokenRequest.Spec.ExpirationSeconds = &expirationSeconds
+	}
+
+	tokenRequest, err = identityProvider.client.CoreV1().ServiceAccounts(namespace.Name).CreateToken(
+		ctx, remoteIdentityServiceAccountName, tokenRequest, metav1.CreateOptions{})
+	if err != nil {
+		klog.Error(err)
+		return response, err
+	}
+
+	var expirationSeconds int64
+	if tokenRequest.Spec.ExpirationSeconds != nil {
+		expirationSeconds = *tokenRequest.Spec.ExpirationSeconds
+	}
+
+	return &responsetypes.SigningRequestResponse{
+		ResponseType: responsetypes.SigningRequestResponseOIDC,
+		OIDCIdentityResponse: responsetypes.OIDCIdentityResponse{
+			Token:              tokenRequest.Status.Token,
+			Audience:           identityProvider.oidcConfig.Audience,
+			ServiceAccountName: remoteIdentityServiceAccountName,
+			ExpirationSeconds:  expirationSeconds,
+			Expiration:         tokenRequest.Status.ExpirationTimestamp.Time,
+		},
+	}, nil
+}
+
+// ensureServiceAccount creates the ServiceAccount representing the remote cluster in its tenant namespace.
+func (identityProvider *oidcIdentityProvider) ensureServiceAccount(ctx context.Context, namespace string) error {
+	serviceAccount := &v1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      remoteIdentityServiceAccountName,
+			Namespace: namespace,
+		},
+	}
+
+	_, err := identityProvider.client.CoreV1().ServiceAccounts(namespace).Create(ctx, serviceAccount, metav1.CreateOptions{})
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// ensureTokenRefreshPermission allows the ServiceAccount representing the remote cluster to request new tokens for itself.
+func (identityProvider *oidcIdentityProvider) ensureTokenRefreshPermission(ctx context.Context, namespace string) error {
+	role := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: remoteIdentityRoleRoot, Namespace: namespace},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups:     []string{""},
+				Resources:     []string{"serviceaccounts/token"},
+				Verbs:         []string{"create"},
+				ResourceNames: []string{remoteIdentityServiceAccountName},
+			},
+		},
+	}
+	_, err := identityProvider.client.RbacV1().Roles(namespace).Create(ctx, role, metav1.CreateOptions{})
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	return ensureTokenRefreshBinding(ctx, identityProvider.client, namespace)
+}
+
+// ensureTokenRefreshBinding binds the ServiceAccount representing the remote cluster to the token refresh Role.
+func ensureTokenRefreshBinding(ctx context.Context, client kubernetes.Interface, namespace string) error {
+	roleBinding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: remoteIdentityRoleRoot, Namespace: namespace},
+		Subjects:   []rbacv1.Subject{serviceAccountSubject(namespace)},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     remoteIdentityRoleRoot,
+		},
+	}
+	_, err := client.RbacV1().RoleBindings(namespace).Create(ctx, roleBinding, metav1.CreateOptions{})
+	if err != nil && !kerrors.IsAlreadyExists(err) {
+		return err
+	}
+	return nil
+}
+
+// deleteTokenRefreshBinding prevents the ServiceAccount representing the remote cluster from requesting new tokens.
+func deleteTokenRefreshBinding(ctx context.Context, client kubernetes.Interface, namespace string) error {
+	err := client.RbacV1().RoleBindings(namespace).Delete(ctx, remoteIdentityRoleRoot, metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func serviceAccountSubject(namespace string) rbacv1.Subject {
+	return rbacv1.Subject{
+		Kind:      rbacv1.ServiceAccountKind,
+		Name:      remoteIdentityServiceAccountName,
+		Namespace: namespace,
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package identitymanager
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+)
+
+// oidcTokenRefreshPeriod is the period after which the OIDC tokens are checked, and possibly refreshed.
+const oidcTokenRefreshPeriod = time.Minute
+
+// oidcTokenManager manages the service account tokens issued by remote clusters through the OIDC identity provider.
+// Each token is refreshed, requesting a new one to the remote API server, once more than half of its lifetime elapsed.
+type oidcTokenManager struct {
+	client                    kubernetes.Interface
+	localCluster              discoveryv1alpha1.ClusterIdentity
+	availableClusterIDSecrets map[string]types.NamespacedName
+	availableTokenMutex       sync.Mutex
+
+	tokenFiles map[string]string
+}
+
+func (tokMan *oidcTokenManager) start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(oidcTokenRefreshPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				klog.V(4).Info("Refreshing OIDC tokens...")
+				for remoteClusterID, namespacedName := range tokMan.getClusterIDs() {
+					if err := tokMan.refreshToken(ctx, discoveryv1alpha1.ClusterIdentity{
+						ClusterID:   remoteClusterID,
+						ClusterName: remoteClusterID,
+					}, namespacedName); err != nil {
+						klog.Error(err)
+						continue
+					}
+				}
+				klog.V(4).Info("OIDC tokens refresh completed")
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (tokMan *oidcTokenManager) refreshToken(ctx context.Context, remoteCluster discoveryv1alpha1.ClusterIdentity,
+	namespacedName types.NamespacedName) error {
+	secret, err := tokMan.client.CoreV1().Secrets(namespacedName.Namespace).Get(ctx, namespacedName.Name, metav1.GetOptions{})
+	if err != nil {
+		klog.Errorf("[%v] %v", remoteCluster.ClusterName, err)
+		return err
+	}
+
+	if !oidcTokenNeedsRefresh(secret, time.Now()) {
+		return nil
+	}
+
+	// the token is requested as the service account itself, without impersonating the user associated with the local cluster.
+	config, err := tokMan.buildConfig(secret, remoteCluster)
+	if err != nil {
+		klog.Errorf("[%v] %v", remoteCluster.ClusterName, err)
+		return err
+	}
+
+	remoteNamespace, err := getValue(secret, namespaceSecretKey, remoteCluster)
+	if err != nil {
+		klog.Errorf("[%v] %v", remoteCluster.ClusterName, err)
+		return err
+	}
+
+	remoteClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		klog.Errorf("[%v] %v", remoteCluster.ClusterName, err)
+		return err
+	}
+
+	tokenRequest := &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			Audiences: []string{string(secret.Data[oidcAudienceSecretKey])},
+		},
+	}
+	if expirationSeconds, err := strconv.ParseInt(string(secret.Data[oidcExpirationSecondsSecretKey]), 10, 64); err == nil {
+		tokenRequest.Spec.ExpirationSeconds = &expirationSeconds
+	}
+
+	tokenRequest, err = remoteClient.CoreV1().ServiceAccounts(string(remoteNamespace)).CreateToken(ctx,
+		string(secret.Data[oidcServiceAccountSecretKey]), tokenRequest, metav1.CreateOptions{})
+	if err != nil {
+		klog.Errorf("[%v] failed to refresh the OIDC token: %v", remoteCluster.ClusterName, err)
+		return err
+	}
+
+	if _, err = tokMan.storeToken(remoteCluster, tokenRequest.Status.Token); err != nil {
+		klog.Errorf("[%v] %v", remoteCluster.ClusterName, err)
+		return err
+	}
+
+	secret.Data[oidcTokenSecretKey] = []byte(tokenRequest.Status.Token)
+	secret.Data[oidcExpirationSecretKey] = []byte(tokenRequest.Status.ExpirationTimestamp.UTC().Format(time.RFC3339))
+	if _, err = tokMan.client.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		// the token file has already been updated, hence the new token will be stored at the next refresh.
+		klog.Warningf("[%v] failed to store the refreshed OIDC token: %v", remoteCluster.ClusterName, err)
+	}
+
+	klog.V(4).Infof("[%v] OIDC token refreshed, expiring at %v", remoteCluster.ClusterName, tokenRequest.Status.ExpirationTimestamp)
+	return nil
+}
+
+func (tokMan *oidcTokenManager) getConfig(secret *v1.Secret, remoteCluster discoveryv1alpha1.ClusterIdentity) (*rest.Config, error) {
+	config, err := tokMan.buildConfig(secret, remoteCluster)
+	if err != nil {
+		klog.Error(err)
+		return nil, err
+	}
+
+	// the service account is directly bound to the permissions granted by the remote cluster, hence no impersonation is required.
+	tokMan.addClusterID(remoteCluster, types.NamespacedName{
+		Namespace: secret.Namespace,
+		Name:      secret.Name,
+	})
+	return config, nil
+}
+
+// buildConfig returns the rest config authenticating as the remote service account, through the token stored in the secret.
+func (tokMan *oidcTokenManager) buildConfig(secret *v1.Secret, remoteCluster discoveryv1alpha1.ClusterIdentity) (*rest.Config, error) {
+	tok, err := getValue(secret, oidcTokenSecretKey, remoteCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	clusterEndpoint, err := getValue(secret, APIServerURLSecretKey, remoteCluster)
+	if err != nil {
+		return nil, err
+	}
+
+	// CAData may be nil if the remote cluster exposes the API Server with a trusted certificate
+	ca := secret.Data[apiServerCaSecretKey]
+
+	filename, err := tokMan.storeToken(remoteCluster, string(tok))
+	if err != nil {
+		return nil, err
+	}
+
+	proxyFunc, err := getProxyFunc(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rest.Config{
+		Host:            string(clusterEndpoint),
+		BearerTokenFile: filename,
+		TLSClientConfig: rest.TLSClientConfig{
+			CAData: ca,
+		},
+		Proxy: proxyFunc,
+	}, nil
+}
+
+func (tokMan *oidcTokenManager) addClusterID(remoteCluster discoveryv1alpha1.ClusterIdentity, secret types.NamespacedName) {
+	tokMan.availableTokenMutex.Lock()
+	defer tokMan.availableTokenMutex.Unlock()
+	tokMan.availableClusterIDSecrets[remoteCluster.ClusterID] = secret
+}
+
+func (tokMan *oidcTokenManager) getClusterIDs() map[string]types.NamespacedName {
+	tokMan.availableTokenMutex.Lock()
+	defer tokMan.availableTokenMutex.Unlock()
+	clusterIDs := make(map[string]types.NamespacedName, len(tokMan.availableClusterIDSecrets))
+	for clusterID, secret := range tokMan.availableClusterIDSecrets {
+		clusterIDs[clusterID] = secret
+	}
+	return clusterIDs
+}
+
+func (tokMan *oidcTokenManager) storeToken(remoteCluster discoveryv1alpha1.ClusterIdentity, tok string) (string, error) {
+	tokMan.availableTokenMutex.Lock()
+	defer tokMan.availableTokenMutex.Unlock()
+	return storeTokenFile(tokMan.tokenFiles, remoteCluster, tok)
+}
+
+// oidcTokenNeedsRefresh returns whether more than half of the lifetime of the token stored in the secret already elapsed.
+// Tokens whose expiration is unknown are always refreshed.
+func oidcTokenNeedsRefresh(secret *v1.Secret, now time.Time) bool {
+	expiration, err := time.Parse(time.RFC3339, string(secret.Data[oidcExpirationSecretKey]))
+	if err != nil {
+		return true
+	}
+
+	expirationSeconds, err := strconv.ParseInt(string(secret.Data[oidcExpirationSecondsSecretKey]), 10, 64)
+	if err != nil || expirationSeconds <= 0 {
+		return true
+	}
+
+	lifetime := time.Duration(expirationSeconds) * time.Second
+	return now.After(expiration.Add(-lifetime / 2))
+}
@@ -0,0 +1,185 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package identitymanager
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	"github.com/liqotech/liqo/pkg/auth"
+)
+
+// RenewIdentity renews the identity used to interact with the given remote cluster, before it expires.
+// A new key and a new CSR are generated, and the CSR is signed with the current private key to authenticate
+// the request towards the remote cluster. Once the new certificate is obtained through the given renewer,
+// the key, the CSR and the certificate are swapped at once, with a single update of the identity secret, which
+// is also marked with the IdentityPendingRestartAnnotation, until the consumers of the identity are restarted.
+func (certManager *identityManager) RenewIdentity(ctx context.Context,
+	remoteCluster discoveryv1alpha1.ClusterIdentity, renewer CertificateRenewer) error {
+	secret, err := certManager.getSecret(remoteCluster)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	if certManager.isAwsIdentity(secret) {
+		return fmt.Errorf("the identity for cluster %v is an AWS IAM identity, which cannot be renewed", remoteCluster.ClusterName)
+	}
+
+	if isOIDCIdentity(secret) {
+		return fmt.Errorf("the identity for cluster %v is an OIDC token, which is refreshed automatically", remoteCluster.ClusterName)
+	}
+
+	currentKey, err := parsePrivateKey(secret.Data[privateKeySecretKey])
+	if err != nil {
+		return fmt.Errorf("failed to parse the private key for cluster %v: %w", remoteCluster.ClusterName, err)
+	}
+
+	key, csrBytes, err := certManager.createCSR()
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	request := auth.NewCertificateRenewalRequest(certManager.localCluster, csrBytes, ed25519.Sign(currentKey, csrBytes))
+	response, err := renewer(ctx, request)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	certificate, err := base64.StdEncoding.DecodeString(response.Certificate)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	// ApiServerCA may be empty if the remote cluster exposes the ApiServer with a certificate issued by "public" CAs
+	if response.APIServerCA != "" {
+		apiServerCa, err := base64.StdEncoding.DecodeString(response.APIServerCA)
+		if err != nil {
+			klog.Error(err)
+			return err
+		}
+		secret.Data[apiServerCaSecretKey] = apiServerCa
+	}
+
+	secret.Data[privateKeySecretKey] = key
+	secret.Data[csrSecretKey] = csrBytes
+	secret.Data[certificateSecretKey] = certificate
+	setCertificateExpireTime(secret, certificate)
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[IdentityPendingRestartAnnotation] = time.Now().Format(time.RFC3339)
+
+	if _, err = certManager.client.CoreV1().Secrets(secret.Namespace).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	klog.Infof("Identity for cluster %v successfully renewed", remoteCluster.ClusterName)
+	return nil
+}
+
+// CertificateValidity returns the validity period of the certificate stored in the given identity secret.
+func CertificateValidity(secret *v1.Secret) (notBefore, notAfter time.Time, err error) {
+	certificate, err := parseCertificate(secret.Data[certificateSecretKey])
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return certificate.NotBefore, certificate.NotAfter, nil
+}
+
+// setCertificateExpireTime sets the expire time annotation of the secret to the expiration of the given certificate.
+// The annotation is left unmodified in case the certificate cannot be parsed.
+func setCertificateExpireTime(secret *v1.Secret, certificate []byte) {
+	parsed, err := parseCertificate(certificate)
+	if err != nil {
+		klog.V(4).Infof("unable to parse the certificate in secret %v/%v: %v", secret.Namespace, secret.Name, err)
+		return
+	}
+
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[certificateExpireTimeAnnotation] = fmt.Sprintf("%v", parsed.NotAfter.Unix())
+}
+
+// verifyRenewalSignature checks that the signature of the given CSR has been generated with the private key
+// corresponding to the given certificate, and that the certificate is not expired and has been issued to the given cluster.
+func verifyRenewalSignature(certificate, signingRequest, signature []byte, clusterID string) error {
+	parsed, err := parseCertificate(certificate)
+	if err != nil {
+		return err
+	}
+
+	if parsed.Subject.CommonName != clusterID {
+		return fmt.Errorf("the current certificate has been issued to %q, rather than %q", parsed.Subject.CommonName, clusterID)
+	}
+
+	if now := time.Now(); now.After(parsed.NotAfter) || now.Before(parsed.NotBefore) {
+		return errors.New("the current certificate is not valid")
+	}
+
+	publicKey, ok := parsed.PublicKey.(ed25519.PublicKey)
+	if !ok {
+		return fmt.Errorf("unsupported public key type %T", parsed.PublicKey)
+	}
+
+	if !ed25519.Verify(publicKey, signingRequest, signature) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+// parseCertificate parses a PEM encoded certificate.
+func parseCertificate(certificate []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certificate)
+	if block == nil {
+		return nil, errors.New("failed to decode the PEM certificate")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// parsePrivateKey parses a PEM encoded ed25519 private key.
+func parsePrivateKey(key []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(key)
+	if block == nil {
+		return nil, errors.New("failed to decode the PEM private key")
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", parsed)
+	}
+	return privateKey, nil
+}
@@ -15,6 +15,8 @@
 package responsetypes
 
 import (
+	"time"
+
 	"github.com/aws/aws-sdk-go/service/eks"
 	"github.com/aws/aws-sdk-go/service/iam"
 )
@@ -28,6 +30,8 @@ const (
 	SigningRequestResponseCertificate SigningRequestResponseType = "Certificate"
 	// SigningRequestResponseIAM indicates that the identity has been validated by the Amazon IAM service.
 	SigningRequestResponseIAM SigningRequestResponseType = "IAM"
+	// SigningRequestResponseOIDC indicates that the identity is a service account token, federated through an OIDC issuer.
+	SigningRequestResponseOIDC SigningRequestResponseType = "OIDC"
 )
 
 // AwsIdentityResponse contains the information about the created IAM user and the EKS cluster.
@@ -38,6 +42,15 @@ type AwsIdentityResponse struct {
 	Region     string
 }
 
+// OIDCIdentityResponse contains the information about the service account token issued to the remote cluster.
+type OIDCIdentityResponse struct {
+	Token              string
+	Audience           string
+	ServiceAccountName string
+	ExpirationSeconds  int64
+	Expiration         time.Time
+}
+
 // SigningRequestResponse contains the response from an Indentity Provider.
 type SigningRequestResponse struct {
 	ResponseType SigningRequestResponseType
@@ -45,4 +58,6 @@ type SigningRequestResponse struct {
 	Certificate []byte
 
 	AwsIdentityResponse AwsIdentityResponse
+
+	OIDCIdentityResponse OIDCIdentityResponse
 }
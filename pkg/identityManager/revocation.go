@@ -0,0 +1,168 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package identitymanager
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	"github.com/liqotech/liqo/pkg/discovery"
+)
+
+// RevokeIdentity marks the identity issued to the given remote cluster as revoked.
+// From now on, the identity requests coming from that cluster are rejected, until the identity is re-approved,
+// and the tokens possibly issued through the OIDC identity provider can no longer be refreshed.
+// The secret storing the issued certificate is labeled accordingly, and created if it does not exist yet.
+func (certManager *identityManager) RevokeIdentity(remoteCluster discoveryv1alpha1.ClusterIdentity) error {
+	namespace, err := certManager.namespaceManager.GetNamespace(remoteCluster)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	secret, err := certManager.client.CoreV1().Secrets(namespace.Name).Get(context.TODO(), remoteCertificateSecret, metav1.GetOptions{})
+	switch {
+	case kerrors.IsNotFound(err):
+		secret = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      remoteCertificateSecret,
+				Namespace: namespace.Name,
+				Labels: map[string]string{
+					discovery.ClusterIDLabel: remoteCluster.ClusterID,
+					identityRevokedLabel:     strconv.FormatBool(true),
+				},
+			},
+		}
+		if _, err = certManager.client.CoreV1().Secrets(namespace.Name).Create(context.TODO(), secret, metav1.CreateOptions{}); err != nil {
+			klog.Error(err)
+			return err
+		}
+	case err != nil:
+		klog.Error(err)
+		return err
+	case isIdentityRevoked(secret):
+		return nil
+	default:
+		if secret.Labels == nil {
+			secret.Labels = map[string]string{}
+		}
+		secret.Labels[identityRevokedLabel] = strconv.FormatBool(true)
+		if _, err = certManager.client.CoreV1().Secrets(namespace.Name).Update(context.TODO(), secret, metav1.UpdateOptions{}); err != nil {
+			klog.Error(err)
+			return err
+		}
+	}
+
+	// the tokens issued through the OIDC identity provider shall not be refreshed anymore
+	if err = deleteTokenRefreshBinding(context.TODO(), certManager.client, namespace.Name); err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	klog.Infof("Identity issued to remote cluster %v revoked", remoteCluster.ClusterName)
+	return nil
+}
+
+// ReapproveIdentity removes the revocation mark from the identity issued to the given remote cluster,
+// hence accepting again the identity requests coming from that cluster. It is a no-op if the identity is not revoked.
+func (certManager *identityManager) ReapproveIdentity(remoteCluster discoveryv1alpha1.ClusterIdentity) error {
+	namespace, err := certManager.namespaceManager.GetNamespace(remoteCluster)
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	secret, err := certManager.client.CoreV1().Secrets(namespace.Name).Get(context.TODO(), remoteCertificateSecret, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) || (err == nil && !isIdentityRevoked(secret)) {
+		return nil
+	}
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	delete(secret.Labels, identityRevokedLabel)
+	if _, err = certManager.client.CoreV1().Secrets(namespace.Name).Update(context.TODO(), secret, metav1.UpdateOptions{}); err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	// restore the token refresh permission, in case a token has been issued through the OIDC identity provider
+	_, err = certManager.client.CoreV1().ServiceAccounts(namespace.Name).Get(context.TODO(), remoteIdentityServiceAccountName, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		if err = ensureTokenRefreshBinding(context.TODO(), certManager.client, namespace.Name); err != nil {
+			klog.Error(err)
+			return err
+		}
+	case !kerrors.IsNotFound(err):
+		klog.Error(err)
+		return err
+	}
+
+	klog.Infof("Identity issued to remote cluster %v re-approved", remoteCluster.ClusterName)
+	return nil
+}
+
+// IsIdentityRevoked returns whether the identity issued to the given remote cluster has been revoked.
+func (certManager *identityManager) IsIdentityRevoked(remoteCluster discoveryv1alpha1.ClusterIdentity) (bool, error) {
+	namespace, err := certManager.namespaceManager.GetNamespace(remoteCluster)
+	if err != nil {
+		klog.Error(err)
+		return false, err
+	}
+
+	err = ensureIdentityNotRevoked(certManager.client, remoteCluster, namespace.Name)
+	if kerrors.IsForbidden(err) {
+		return true, nil
+	}
+	return false, err
+}
+
+// ensureIdentityNotRevoked returns a Forbidden error in case the identity issued to the given remote cluster has been revoked.
+func ensureIdentityNotRevoked(client kubernetes.Interface, cluster discoveryv1alpha1.ClusterIdentity, namespace string) error {
+	secret, err := client.CoreV1().Secrets(namespace).Get(context.TODO(), remoteCertificateSecret, metav1.GetOptions{})
+	if kerrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	if isIdentityRevoked(secret) {
+		return newIdentityRevokedError(cluster)
+	}
+	return nil
+}
+
+// newIdentityRevokedError returns the Forbidden error returned to the remote clusters whose identity has been revoked.
+func newIdentityRevokedError(cluster discoveryv1alpha1.ClusterIdentity) error {
+	return kerrors.NewForbidden(v1.Resource("secrets"), remoteCertificateSecret,
+		fmt.Errorf("the identity of cluster %v has been revoked", cluster.ClusterName))
+}
+
+func isIdentityRevoked(secret *v1.Secret) bool {
+	revoked, ok := secret.Labels[identityRevokedLabel]
+	return ok && revoked == strconv.FormatBool(true)
+}
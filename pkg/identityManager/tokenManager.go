@@ -16,8 +16,6 @@ package identitymanager
 
 import (
 	"context"
-	"net/http"
-	"net/url"
 	"os"
 	"sync"
 	"time"
@@ -125,18 +123,9 @@ func (tokMan *iamTokenManager) getConfig(secret *v1.Secret, remoteCluster discov
 		Name:      secret.Name,
 	})
 
-	var proxyURL *url.URL
-	var proxyFunc func(*http.Request) (*url.URL, error)
-	proxyConfig, ok := secret.Data[apiProxyURLSecretKey]
-	if ok {
-		proxyURL, err = url.Parse(string(proxyConfig))
-		if err != nil {
-			klog.Errorf("an error occurred while parsing proxy url %s from secret %v/%v: %s", proxyConfig, secret.Namespace, secret.Name, err)
-			return nil, err
-		}
-		proxyFunc = func(request *http.Request) (*url.URL, error) {
-			return proxyURL, nil
-		}
+	proxyFunc, err := getProxyFunc(secret)
+	if err != nil {
+		return nil, err
 	}
 
 	// create the rest config
@@ -157,8 +146,14 @@ func (tokMan *iamTokenManager) addClusterID(remoteCluster discoveryv1alpha1.Clus
 }
 
 func (tokMan *iamTokenManager) storeToken(remoteCluster discoveryv1alpha1.ClusterIdentity, tok *token.Token) (string, error) {
+	return storeTokenFile(tokMan.tokenFiles, remoteCluster, tok.Token)
+}
+
+// storeTokenFile writes the given token to the file associated with the remote cluster,
+// creating it if not already present, and returns the name of the file.
+func storeTokenFile(tokenFiles map[string]string, remoteCluster discoveryv1alpha1.ClusterIdentity, tok string) (string, error) {
 	var err error
-	filename, found := tokMan.tokenFiles[remoteCluster.ClusterID]
+	filename, found := tokenFiles[remoteCluster.ClusterID]
 	if found {
 		_, err = os.Stat(filename)
 	}
@@ -176,10 +171,10 @@ func (tokMan *iamTokenManager) storeToken(remoteCluster discoveryv1alpha1.Cluste
 		}
 
 		filename = file.Name()
-		tokMan.tokenFiles[remoteCluster.ClusterID] = filename
+		tokenFiles[remoteCluster.ClusterID] = filename
 	}
 
-	err = os.WriteFile(filename, []byte(tok.Token), 0o600)
+	err = os.WriteFile(filename, []byte(tok), 0o600)
 	if err != nil {
 		klog.Errorf("Error writing the authentication token tmp file: %v", err)
 		return "", err
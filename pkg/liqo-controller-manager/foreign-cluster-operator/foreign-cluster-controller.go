@@ -76,6 +76,8 @@ const (
 	tunnelEndpointConnectingMessage = "The TunnelEndpoint has been successfully found in the Tenant Namespace %v, but it is not connected yet"
 
 	tunnelEndpointErrorReason = "TunnelEndpointError"
+
+	tunnelEndpointDegradedReason = "TunnelEndpointDegraded"
 )
 
 // ForeignClusterReconciler reconciles a ForeignCluster object.
@@ -221,6 +223,14 @@ func (r *ForeignClusterReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	}
 	tracer.Step("Ensured the existence of the remote identity")
 
+	// enforce the revocation status of the identity issued to the remote cluster
+	// (after the remote identity, as the revocation overrides the authentication status)
+	if err = r.ensureIdentityRevocation(&foreignCluster); err != nil {
+		klog.Error(err)
+		return ctrl.Result{}, err
+	}
+	tracer.Step("Ensured the revocation status of the issued identity")
+
 	// fetch the remote tenant namespace name
 	if err = r.fetchRemoteTenantNamespace(ctx, &foreignCluster); err != nil {
 		klog.Error(err)
@@ -527,6 +537,10 @@ func (r *ForeignClusterReconciler) checkTEP(ctx context.Context,
 			peeringconditionsutils.EnsureStatus(foreignCluster,
 				discoveryv1alpha1.NetworkStatusCondition, discoveryv1alpha1.PeeringConditionStatusPending,
 				tunnelEndpointConnectingReason, fmt.Sprintf(tunnelEndpointConnectingMessage, foreignCluster.Status.TenantNamespace.Local))
+		case netv1alpha1.ConnectionDegraded:
+			peeringconditionsutils.EnsureStatus(foreignCluster,
+				discoveryv1alpha1.NetworkStatusCondition, discoveryv1alpha1.PeeringConditionStatusError,
+				tunnelEndpointDegradedReason, tep.Status.Connection.StatusMessage)
 		case netv1alpha1.ConnectionError:
 			peeringconditionsutils.EnsureStatus(foreignCluster,
 				discoveryv1alpha1.NetworkStatusCondition, discoveryv1alpha1.PeeringConditionStatusNone,
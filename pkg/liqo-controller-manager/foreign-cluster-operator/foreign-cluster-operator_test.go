@@ -1068,6 +1068,47 @@ var _ = Describe("ForeignClusterOperator", func() {
 				expectedIncoming:            ContainElement(incomingBinding),
 				expectedOutgoingClusterWide: Not(HaveOccurred()),
 			}),
+
+			Entry("bidirectional peering with revoked identity", permissionTestcase{
+				fc: discoveryv1alpha1.ForeignCluster{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "foreign-cluster-name",
+						Labels: map[string]string{
+							discovery.DiscoveryTypeLabel: string(discovery.ManualDiscovery),
+							discovery.ClusterIDLabel:     "foreign-cluster-id",
+						},
+					},
+					Spec: discoveryv1alpha1.ForeignClusterSpec{
+						ClusterIdentity: discoveryv1alpha1.ClusterIdentity{
+							ClusterID:   "foreign-cluster-id",
+							ClusterName: "foreign-cluster-name",
+						},
+						OutgoingPeeringEnabled: discoveryv1alpha1.PeeringEnabledAuto,
+						IncomingPeeringEnabled: discoveryv1alpha1.PeeringEnabledAuto,
+						ForeignAuthURL:         "https://example.com",
+						InsecureSkipTLSVerify:  pointer.BoolPtr(true),
+						IdentityRevoked:        true,
+					},
+					Status: discoveryv1alpha1.ForeignClusterStatus{
+						TenantNamespace: discoveryv1alpha1.TenantNamespaceType{},
+						PeeringConditions: []discoveryv1alpha1.PeeringCondition{
+							{
+								Type:               discoveryv1alpha1.IncomingPeeringCondition,
+								Status:             discoveryv1alpha1.PeeringConditionStatusEstablished,
+								LastTransitionTime: metav1.Now(),
+							},
+							{
+								Type:               discoveryv1alpha1.OutgoingPeeringCondition,
+								Status:             discoveryv1alpha1.PeeringConditionStatusEstablished,
+								LastTransitionTime: metav1.Now(),
+							},
+						},
+					},
+				},
+				expectedOutgoing:            Not(ContainElement(outgoingBinding)),
+				expectedIncoming:            Not(ContainElement(incomingBinding)),
+				expectedOutgoingClusterWide: HaveOccurred(),
+			}),
 		)
 
 	})
@@ -30,6 +30,18 @@ func (r *ForeignClusterReconciler) ensurePermission(ctx context.Context, foreign
 	remoteCluster := foreignCluster.Spec.ClusterIdentity
 	peeringPhase := foreignclusterutils.GetPeeringPhase(foreignCluster)
 
+	// the remote cluster is granted no permission at all, while its identity is revoked
+	if foreignCluster.Spec.IdentityRevoked {
+		for _, clusterRoles := range [][]*rbacv1.ClusterRole{r.PeeringPermission.Basic,
+			r.PeeringPermission.Outgoing, r.PeeringPermission.Incoming} {
+			if err = r.NamespaceManager.UnbindClusterRoles(remoteCluster, clusterRolesToNames(clusterRoles)...); err != nil {
+				klog.Error(err)
+				return err
+			}
+		}
+		return nil
+	}
+
 	if _, err = r.NamespaceManager.BindClusterRoles(remoteCluster, r.PeeringPermission.Basic...); err != nil {
 		klog.Error(err)
 		return err
@@ -0,0 +1,57 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package foreignclusteroperator
+
+import (
+	"k8s.io/klog/v2"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	peeringconditionsutils "github.com/liqotech/liqo/pkg/utils/peeringConditions"
+)
+
+const (
+	identityRevokedReason  = "IdentityRevoked"
+	identityRevokedMessage = "The identity issued to the remote cluster has been revoked"
+)
+
+// ensureIdentityRevocation enforces the revocation status of the identity issued to the remote cluster,
+// as specified in the ForeignCluster spec.
+func (r *ForeignClusterReconciler) ensureIdentityRevocation(foreignCluster *discoveryv1alpha1.ForeignCluster) error {
+	remoteCluster := foreignCluster.Spec.ClusterIdentity
+
+	if !foreignCluster.Spec.IdentityRevoked {
+		// the identity requests are accepted again, in case they have been previously rejected.
+		if err := r.IdentityManager.ReapproveIdentity(remoteCluster); err != nil {
+			klog.Error(err)
+			return err
+		}
+		return nil
+	}
+
+	if err := r.IdentityManager.RevokeIdentity(remoteCluster); err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	// the permissions granted to the remote cluster are removed by ensurePermission, as well as by the
+	// controllers managing the ResourceRequests and the NamespaceMaps originating from it.
+	peeringconditionsutils.EnsureStatus(foreignCluster,
+		discoveryv1alpha1.AuthenticationStatusCondition,
+		discoveryv1alpha1.PeeringConditionStatusRevoked,
+		identityRevokedReason,
+		identityRevokedMessage)
+	klog.V(4).Infof("[%v] The identity issued to the remote cluster is revoked", remoteCluster.ClusterID)
+	return nil
+}
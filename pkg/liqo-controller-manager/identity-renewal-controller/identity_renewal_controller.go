@@ -0,0 +1,188 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package identityrenewalctrl
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/liqotech/liqo/pkg/discovery"
+	identitymanager "github.com/liqotech/liqo/pkg/identityManager"
+	foreignclusterutils "github.com/liqotech/liqo/pkg/utils/foreignCluster"
+	"github.com/liqotech/liqo/pkg/vkMachinery"
+)
+
+const (
+	// crdReplicatorDeploymentName is the name of the crd-replicator deployment, which consumes the identities.
+	crdReplicatorDeploymentName = "liqo-crd-replicator"
+	// restartedAtAnnotation is the annotation set on the pod templates to trigger the restart of the consumers.
+	restartedAtAnnotation = "liqo.io/identity-renewed-at"
+)
+
+// IdentityRenewalReconciler renews the identities used to interact with remote clusters, before they expire.
+type IdentityRenewalReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	LiqoNamespace   string
+	IdentityManager identitymanager.IdentityManager
+
+	// RenewalThreshold is the percentage of the certificate lifetime after which the identity is renewed.
+	RenewalThreshold uint64
+
+	SecureTransport   *http.Transport
+	InsecureTransport *http.Transport
+}
+
+// cluster-role
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch;update
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;patch
+// +kubebuilder:rbac:groups=discovery.liqo.io,resources=foreignclusters,verbs=get;list;watch
+
+// Reconcile checks whether the certificate stored in the given identity secret is approaching its expiration,
+// and in that case it renews the identity and restarts the components consuming it. Otherwise, the secret is
+// requeued to be processed again once the renewal threshold is reached.
+func (r *IdentityRenewalReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var secret corev1.Secret
+	if err := r.Get(ctx, req.NamespacedName, &secret); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// The identity has already been renewed, but the consumers have not yet been restarted (e.g., because of a failure).
+	if renewedAt, pending := secret.Annotations[identitymanager.IdentityPendingRestartAnnotation]; pending {
+		return ctrl.Result{}, r.completeRestart(ctx, &secret, renewedAt)
+	}
+
+	notBefore, notAfter, err := identitymanager.CertificateValidity(&secret)
+	if err != nil {
+		// The identity is not backed by a certificate (e.g., AWS IAM identities), hence there is nothing to renew.
+		klog.V(4).Infof("Unable to retrieve the certificate validity from secret %q: %v", klog.KObj(&secret), err)
+		return ctrl.Result{}, nil
+	}
+
+	renewal := renewalTime(notBefore, notAfter, r.RenewalThreshold)
+	if now := time.Now(); now.Before(renewal) {
+		klog.V(4).Infof("Identity in secret %q will be renewed at %v", klog.KObj(&secret), renewal)
+		return ctrl.Result{RequeueAfter: renewal.Sub(now)}, nil
+	}
+
+	clusterID := secret.Labels[discovery.ClusterIDLabel]
+	fc, err := foreignclusterutils.GetForeignClusterByID(ctx, r.Client, clusterID)
+	if err != nil {
+		klog.Errorf("Failed to retrieve the ForeignCluster for cluster %q: %v", clusterID, err)
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	klog.Infof("Renewing the identity for cluster %q, expiring at %v", fc.Spec.ClusterIdentity.ClusterName, notAfter)
+	if err = r.IdentityManager.RenewIdentity(ctx, fc.Spec.ClusterIdentity, r.renewer(fc)); err != nil {
+		klog.Errorf("Failed to renew the identity for cluster %q: %v", fc.Spec.ClusterIdentity.ClusterName, err)
+		return ctrl.Result{}, err
+	}
+
+	// The consumers are restarted once the renewed identity (marked as pending restart) triggers a new reconciliation.
+	return ctrl.Result{}, nil
+}
+
+// completeRestart restarts the consumers of a renewed identity, and then removes the pending restart annotation.
+// The consumers read the identity only at startup, hence they need to be restarted to use the new certificate.
+// The peering is not affected, as the previous certificate is still valid while the new instances are rolled out.
+func (r *IdentityRenewalReconciler) completeRestart(ctx context.Context, secret *corev1.Secret, renewedAt string) error {
+	clusterID := secret.Labels[discovery.ClusterIDLabel]
+	if err := r.restartConsumers(ctx, secret.Namespace, clusterID, renewedAt); err != nil {
+		klog.Errorf("Failed to restart the consumers of the identity for cluster %q: %v", clusterID, err)
+		return err
+	}
+
+	delete(secret.Annotations, identitymanager.IdentityPendingRestartAnnotation)
+	if err := r.Update(ctx, secret); err != nil {
+		klog.Errorf("Failed to clear the pending restart of the identity for cluster %q: %v", clusterID, err)
+		return err
+	}
+	return nil
+}
+
+// SetupWithManager registers a new controller for identity secrets.
+func (r *IdentityRenewalReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	identityPredicate, err := predicate.LabelSelectorPredicate(metav1.LabelSelector{
+		MatchLabels: map[string]string{
+			identitymanager.LocalIdentitySecretLabel:  "true",
+			identitymanager.CertificateAvailableLabel: "true",
+		},
+	})
+	utilruntime.Must(err)
+
+	return ctrl.NewControllerManagedBy(mgr).Named("identity-renewal").
+		For(&corev1.Secret{}, builder.WithPredicates(identityPredicate)).
+		Complete(r)
+}
+
+// restartConsumers triggers the rolling restart of the virtual kubelets associated with the given remote cluster,
+// as well as of the crd-replicator, to make them use the renewed identity. The restart is marked with the time of
+// the renewal, so that retrying it does not restart again the deployments already processed.
+func (r *IdentityRenewalReconciler) restartConsumers(ctx context.Context, tenantNamespace, clusterID, restartedAt string) error {
+	var deployments appsv1.DeploymentList
+	if err := r.List(ctx, &deployments, client.InNamespace(tenantNamespace),
+		client.MatchingLabels(labels.Merge(vkMachinery.KubeletBaseLabels, labels.Set{discovery.ClusterIDLabel: clusterID}))); err != nil {
+		return err
+	}
+
+	var crdReplicator appsv1.Deployment
+	key := types.NamespacedName{Namespace: r.LiqoNamespace, Name: crdReplicatorDeploymentName}
+	if err := r.Get(ctx, key, &crdReplicator); client.IgnoreNotFound(err) != nil {
+		return err
+	} else if err == nil {
+		deployments.Items = append(deployments.Items, crdReplicator)
+	}
+
+	for i := range deployments.Items {
+		deployment := &deployments.Items[i]
+		if deployment.Spec.Template.Annotations[restartedAtAnnotation] == restartedAt {
+			continue
+		}
+
+		original := deployment.DeepCopy()
+		if deployment.Spec.Template.Annotations == nil {
+			deployment.Spec.Template.Annotations = map[string]string{}
+		}
+		deployment.Spec.Template.Annotations[restartedAtAnnotation] = restartedAt
+		if err := r.Patch(ctx, deployment, client.MergeFrom(original)); err != nil {
+			return err
+		}
+		klog.Infof("Restarted deployment %q to use the renewed identity", klog.KObj(deployment))
+	}
+	return nil
+}
+
+// renewalTime returns the time the identity has to be renewed at, given the certificate
+// validity period and the percentage of the lifetime after which the renewal occurs.
+func renewalTime(notBefore, notAfter time.Time, threshold uint64) time.Time {
+	lifetime := notAfter.Sub(notBefore)
+	// The lifetime is divided first, not to overflow in case of long-lived certificates.
+	return notBefore.Add(lifetime / 100 * time.Duration(threshold))
+}
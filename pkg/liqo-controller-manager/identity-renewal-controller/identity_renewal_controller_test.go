@@ -0,0 +1,248 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package identityrenewalctrl
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	"github.com/liqotech/liqo/pkg/discovery"
+	identitymanager "github.com/liqotech/liqo/pkg/identityManager"
+	"github.com/liqotech/liqo/pkg/vkMachinery"
+)
+
+type fakeIdentityManager struct {
+	identitymanager.IdentityManager
+	cl      client.Client
+	secret  types.NamespacedName
+	renewed []string
+	err     error
+}
+
+func (f *fakeIdentityManager) RenewIdentity(ctx context.Context, remoteCluster discoveryv1alpha1.ClusterIdentity,
+	_ identitymanager.CertificateRenewer) error {
+	f.renewed = append(f.renewed, remoteCluster.ClusterID)
+	if f.err != nil {
+		return f.err
+	}
+
+	// Mimic the update of the identity secret, which is marked as pending restart.
+	var secret corev1.Secret
+	Expect(f.cl.Get(ctx, f.secret, &secret)).To(Succeed())
+	secret.Annotations = map[string]string{identitymanager.IdentityPendingRestartAnnotation: "renewed-at"}
+	return f.cl.Update(ctx, &secret)
+}
+
+func generateCertificate(notBefore, notAfter time.Time) []byte {
+	public, key, err := ed25519.GenerateKey(rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+	template := x509.Certificate{SerialNumber: big.NewInt(1), NotBefore: notBefore, NotAfter: notAfter}
+	certificate, err := x509.CreateCertificate(rand.Reader, &template, &template, public, key)
+	Expect(err).ToNot(HaveOccurred())
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate})
+}
+
+func newDeployment(namespace, name string, lbls map[string]string) *appsv1.Deployment {
+	return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: lbls}}
+}
+
+var _ = Describe("IdentityRenewalReconciler", func() {
+	const (
+		liqoNamespace   = "liqo"
+		tenantNamespace = "liqo-tenant-remote"
+		clusterID       = "remote-cluster-id"
+	)
+
+	var (
+		ctx        context.Context
+		cl         client.Client
+		idManager  *fakeIdentityManager
+		reconciler *IdentityRenewalReconciler
+		secret     *corev1.Secret
+		result     ctrl.Result
+		err        error
+	)
+
+	getRestartAnnotation := func(namespace, name string) string {
+		var deployment appsv1.Deployment
+		Expect(cl.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &deployment)).To(Succeed())
+		return deployment.Spec.Template.Annotations[restartedAtAnnotation]
+	}
+
+	getSecretAnnotations := func() map[string]string {
+		var current corev1.Secret
+		Expect(cl.Get(ctx, client.ObjectKeyFromObject(secret), &current)).To(Succeed())
+		return current.Annotations
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		idManager = &fakeIdentityManager{}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "liqo-identity-abcde", Namespace: tenantNamespace,
+				Labels: map[string]string{discovery.ClusterIDLabel: clusterID},
+			},
+			Data: map[string][]byte{},
+		}
+	})
+
+	JustBeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(discoveryv1alpha1.AddToScheme(scheme)).To(Succeed())
+
+		fc := &discoveryv1alpha1.ForeignCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "remote", Labels: map[string]string{discovery.ClusterIDLabel: clusterID}},
+			Spec: discoveryv1alpha1.ForeignClusterSpec{
+				ClusterIdentity: discoveryv1alpha1.ClusterIdentity{ClusterID: clusterID, ClusterName: "remote"},
+			},
+		}
+		vkLabels := labels.Merge(vkMachinery.KubeletBaseLabels, labels.Set{discovery.ClusterIDLabel: clusterID})
+		otherVKLabels := labels.Merge(vkMachinery.KubeletBaseLabels, labels.Set{discovery.ClusterIDLabel: "other"})
+
+		cl = fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret, fc,
+			newDeployment(tenantNamespace, "virtual-kubelet", vkLabels),
+			newDeployment(tenantNamespace, "other-virtual-kubelet", otherVKLabels),
+			newDeployment(liqoNamespace, crdReplicatorDeploymentName, nil),
+		).Build()
+
+		idManager.cl, idManager.secret = cl, client.ObjectKeyFromObject(secret)
+		reconciler = &IdentityRenewalReconciler{
+			Client:           cl,
+			Scheme:           scheme,
+			LiqoNamespace:    liqoNamespace,
+			IdentityManager:  idManager,
+			RenewalThreshold: 70,
+		}
+		result, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(secret)})
+	})
+
+	When("the certificate is far from the expiration", func() {
+		BeforeEach(func() {
+			secret.Data["certificate"] = generateCertificate(time.Now().Add(-time.Hour), time.Now().Add(99*time.Hour))
+		})
+
+		It("should not renew the identity", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(idManager.renewed).To(BeEmpty())
+		})
+		It("should requeue the secret at the renewal time", func() {
+			Expect(result.RequeueAfter).To(BeNumerically("~", 69*time.Hour, time.Minute))
+		})
+	})
+
+	When("the certificate is approaching the expiration", func() {
+		BeforeEach(func() {
+			secret.Data["certificate"] = generateCertificate(time.Now().Add(-9*time.Hour), time.Now().Add(time.Hour))
+		})
+
+		It("should renew the identity", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(idManager.renewed).To(ConsistOf(clusterID))
+		})
+		It("should mark the identity as pending restart", func() {
+			Expect(getSecretAnnotations()).To(HaveKeyWithValue(identitymanager.IdentityPendingRestartAnnotation, "renewed-at"))
+		})
+
+		When("the renewed identity is reconciled", func() {
+			JustBeforeEach(func() {
+				result, err = reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(secret)})
+			})
+
+			It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+			It("should restart the consumers of the identity", func() {
+				Expect(getRestartAnnotation(tenantNamespace, "virtual-kubelet")).To(Equal("renewed-at"))
+				Expect(getRestartAnnotation(liqoNamespace, crdReplicatorDeploymentName)).To(Equal("renewed-at"))
+				Expect(getRestartAnnotation(tenantNamespace, "other-virtual-kubelet")).To(BeEmpty())
+			})
+			It("should clear the pending restart", func() {
+				Expect(getSecretAnnotations()).ToNot(HaveKey(identitymanager.IdentityPendingRestartAnnotation))
+			})
+		})
+
+		When("the renewal fails", func() {
+			BeforeEach(func() { idManager.err = errors.New("renewal refused") })
+
+			It("should return an error", func() { Expect(err).To(HaveOccurred()) })
+			It("should not mark the identity as pending restart", func() {
+				Expect(getSecretAnnotations()).ToNot(HaveKey(identitymanager.IdentityPendingRestartAnnotation))
+			})
+		})
+	})
+
+	When("the restart of the consumers is pending", func() {
+		BeforeEach(func() {
+			secret.Data["certificate"] = generateCertificate(time.Now().Add(-time.Hour), time.Now().Add(99*time.Hour))
+			secret.Annotations = map[string]string{identitymanager.IdentityPendingRestartAnnotation: "previously-renewed-at"}
+		})
+
+		It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+		It("should not renew the identity again", func() { Expect(idManager.renewed).To(BeEmpty()) })
+		It("should restart the consumers of the identity", func() {
+			Expect(getRestartAnnotation(tenantNamespace, "virtual-kubelet")).To(Equal("previously-renewed-at"))
+			Expect(getRestartAnnotation(liqoNamespace, crdReplicatorDeploymentName)).To(Equal("previously-renewed-at"))
+		})
+		It("should clear the pending restart", func() {
+			Expect(getSecretAnnotations()).ToNot(HaveKey(identitymanager.IdentityPendingRestartAnnotation))
+		})
+	})
+
+	When("the identity is not backed by a certificate", func() {
+		It("should do nothing", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal(ctrl.Result{}))
+			Expect(idManager.renewed).To(BeEmpty())
+		})
+	})
+
+	DescribeTable("renewalTime",
+		func(threshold uint64, expected time.Duration) {
+			notBefore := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+			Expect(renewalTime(notBefore, notBefore.Add(100*time.Hour), threshold)).To(Equal(notBefore.Add(expected)))
+		},
+		Entry("immediately", uint64(0), time.Duration(0)),
+		Entry("after 70% of the lifetime", uint64(70), 70*time.Hour),
+		Entry("at the expiration", uint64(100), 100*time.Hour),
+	)
+
+	It("renewalTime should not overflow with long-lived certificates", func() {
+		notBefore := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+		renewal := renewalTime(notBefore, notBefore.AddDate(10, 0, 0), 70)
+		Expect(renewal).To(BeTemporally(">", notBefore.AddDate(6, 0, 0)))
+		Expect(renewal).To(BeTemporally("<", notBefore.AddDate(8, 0, 0)))
+	})
+})
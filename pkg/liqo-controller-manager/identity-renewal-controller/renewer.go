@@ -0,0 +1,79 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package identityrenewalctrl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	"github.com/liqotech/liqo/pkg/auth"
+	"github.com/liqotech/liqo/pkg/discoverymanager/utils"
+	identitymanager "github.com/liqotech/liqo/pkg/identityManager"
+	foreignclusterutils "github.com/liqotech/liqo/pkg/utils/foreignCluster"
+)
+
+// renewer returns the function sending the renewal requests to the authentication service of the given cluster.
+func (r *IdentityRenewalReconciler) renewer(fc *discoveryv1alpha1.ForeignCluster) identitymanager.CertificateRenewer {
+	return func(ctx context.Context, request *auth.CertificateRenewalRequest) (*auth.CertificateIdentityResponse, error) {
+		payload, err := json.Marshal(request)
+		if err != nil {
+			return nil, err
+		}
+
+		httpClient := &http.Client{
+			Transport: r.transport(foreignclusterutils.InsecureSkipTLSVerify(fc)),
+			Timeout:   utils.HTTPRequestTimeout,
+		}
+		url := fmt.Sprintf("%s%s", fc.Spec.ForeignAuthURL, request.GetPath())
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(payload))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "text/plain")
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusAccepted {
+			return nil, fmt.Errorf("renewal refused by the remote cluster (status code %d): %s", resp.StatusCode, string(body))
+		}
+
+		response := auth.CertificateIdentityResponse{}
+		if err = json.Unmarshal(body, &response); err != nil {
+			return nil, err
+		}
+		return &response, nil
+	}
+}
+
+// transport returns the correct transport to be used for a given request.
+func (r *IdentityRenewalReconciler) transport(insecureSkipTLSVerify bool) *http.Transport {
+	if insecureSkipTLSVerify {
+		return r.InsecureTransport
+	}
+	return r.SecureTransport
+}
@@ -17,11 +17,13 @@ package namespaceoffloadingctrl
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	k8shelper "k8s.io/component-helpers/scheduling/corev1"
+	"k8s.io/component-helpers/scheduling/corev1/nodeaffinity"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -41,42 +43,55 @@ func (r *NamespaceOffloadingReconciler) enforceClusterSelector(ctx context.Conte
 	}
 
 	// If here there are no virtual nodes is an error because it means that in the cluster there are NamespaceMap
-	// but not their associated virtual nodes
-	if len(virtualNodes.Items) != len(clusterIDMap) {
+	// but not their associated virtual nodes. Multiple virtual nodes may be associated with the same cluster (i.e., node pools).
+	clusterIDs := map[string]struct{}{}
+	for i := range virtualNodes.Items {
+		clusterIDs[virtualNodes.Items[i].Labels[liqoconst.RemoteClusterID]] = struct{}{}
+	}
+	if len(clusterIDs) != len(clusterIDMap) {
 		err := fmt.Errorf(" No VirtualNodes at the moment in the cluster")
 		klog.Error(err)
 		return err
 	}
 
-	errorCondition := false
+	preferences, err := nodeaffinity.NewPreferredSchedulingTerms(noff.Spec.PreferredClusterSelector)
+	if err != nil {
+		klog.Infof("%s -> Unable to offload the namespace '%s', there is an error in PreferredClusterSelectorField",
+			err, noff.Namespace)
+		return r.addInvalidSelectorAnnotation(ctx, noff, fmt.Sprintf("Invalid Preferred Cluster Selector : %s", err))
+	}
+
+	// Each eligible cluster is scored according to the best of its virtual nodes.
+	scores := map[string]int64{}
 	for i := range virtualNodes.Items {
+		clusterID := virtualNodes.Items[i].Labels[liqoconst.RemoteClusterID]
+		if _, found := clusterIDMap[clusterID]; !found {
+			continue
+		}
+
 		match, err := k8shelper.MatchNodeSelectorTerms(&virtualNodes.Items[i], &noff.Spec.ClusterSelector)
 		if err != nil {
 			klog.Infof("%s -> Unable to offload the namespace '%s', there is an error in ClusterSelectorField",
 				err, noff.Namespace)
-			patch := noff.DeepCopy()
-			if noff.Annotations == nil {
-				noff.Annotations = map[string]string{}
-			}
-			noff.Annotations[liqoconst.SchedulingLiqoLabel] = fmt.Sprintf("Invalid Cluster Selector : %s", err)
-			if err = r.Patch(ctx, noff, client.MergeFrom(patch)); err != nil {
-				klog.Errorf("%s -> unable to add the liqo scheduling annotation to the NamespaceOffloading in the namespace '%s'",
-					err, noff.Namespace)
-				return err
-			}
-			klog.Infof("The liqo scheduling annotation is correctly added to the NamespaceOffloading in the namespace '%s'",
-				noff.Namespace)
-			break
+			return r.addInvalidSelectorAnnotation(ctx, noff, fmt.Sprintf("Invalid Cluster Selector : %s", err))
 		}
-		if match {
-			if err = addDesiredMapping(ctx, r.Client, noff.Namespace, noff.Status.RemoteNamespaceName,
-				clusterIDMap[virtualNodes.Items[i].Labels[liqoconst.RemoteClusterID]]); err != nil {
-				errorCondition = true
-				continue
-			}
-			delete(clusterIDMap, virtualNodes.Items[i].Labels[liqoconst.RemoteClusterID])
+		if !match {
+			continue
+		}
+		score := preferences.Score(&virtualNodes.Items[i])
+		if current, found := scores[clusterID]; !found || score > current {
+			scores[clusterID] = score
 		}
 	}
+
+	errorCondition := false
+	for _, clusterID := range selectClusters(noff, scores, clusterIDMap) {
+		if err = addDesiredMapping(ctx, r.Client, noff.Namespace, noff.Status.RemoteNamespaceName, clusterIDMap[clusterID]); err != nil {
+			errorCondition = true
+			continue
+		}
+		delete(clusterIDMap, clusterID)
+	}
 	if errorCondition {
 		err := fmt.Errorf("some desiredMappings has not been added")
 		klog.Error(err)
@@ -85,6 +100,57 @@ func (r *NamespaceOffloadingReconciler) enforceClusterSelector(ctx context.Conte
 	return nil
 }
 
+// selectClusters returns the eligible clusters the namespace has to be offloaded to. In case the MaxClusters field is
+// set, the clusters already hosting the remote namespace are selected first, followed by the remaining ones ordered
+// by decreasing preference score (ties are broken by cluster ID, to guarantee a stable choice).
+func selectClusters(noff *offv1alpha1.NamespaceOffloading, scores map[string]int64,
+	clusterIDMap map[string]*mapsv1alpha1.NamespaceMap) []string {
+	clusterIDs := make([]string, 0, len(scores))
+	for clusterID := range scores {
+		clusterIDs = append(clusterIDs, clusterID)
+	}
+
+	if noff.Spec.MaxClusters == nil || int(*noff.Spec.MaxClusters) >= len(clusterIDs) {
+		return clusterIDs
+	}
+
+	offloaded := func(clusterID string) bool {
+		_, found := clusterIDMap[clusterID].Spec.DesiredMapping[noff.Namespace]
+		return found
+	}
+	sort.Slice(clusterIDs, func(i, j int) bool {
+		if offloadedI, offloadedJ := offloaded(clusterIDs[i]), offloaded(clusterIDs[j]); offloadedI != offloadedJ {
+			return offloadedI
+		}
+		if scores[clusterIDs[i]] != scores[clusterIDs[j]] {
+			return scores[clusterIDs[i]] > scores[clusterIDs[j]]
+		}
+		return clusterIDs[i] < clusterIDs[j]
+	})
+
+	klog.V(4).Infof("Offloading the namespace '%s' to at most %d clusters: %v", noff.Namespace, *noff.Spec.MaxClusters, clusterIDs)
+	return clusterIDs[:*noff.Spec.MaxClusters]
+}
+
+// addInvalidSelectorAnnotation informs the user that the cluster selectors specified in the NamespaceOffloading
+// resource are not valid, and that the namespace cannot be offloaded.
+func (r *NamespaceOffloadingReconciler) addInvalidSelectorAnnotation(ctx context.Context,
+	noff *offv1alpha1.NamespaceOffloading, message string) error {
+	patch := noff.DeepCopy()
+	if noff.Annotations == nil {
+		noff.Annotations = map[string]string{}
+	}
+	noff.Annotations[liqoconst.SchedulingLiqoLabel] = message
+	if err := r.Patch(ctx, noff, client.MergeFrom(patch)); err != nil {
+		klog.Errorf("%s -> unable to add the liqo scheduling annotation to the NamespaceOffloading in the namespace '%s'",
+			err, noff.Namespace)
+		return err
+	}
+	klog.Infof("The liqo scheduling annotation is correctly added to the NamespaceOffloading in the namespace '%s'",
+		noff.Namespace)
+	return nil
+}
+
 func (r *NamespaceOffloadingReconciler) getClusterIDMap(ctx context.Context) (map[string]*mapsv1alpha1.NamespaceMap, error) {
 	// Build the selector to consider only local NamespaceMaps.
 	metals := reflection.LocalResourcesLabelSelector()
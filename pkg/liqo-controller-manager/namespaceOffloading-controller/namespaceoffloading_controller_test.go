@@ -25,6 +25,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	ctrlutils "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -693,6 +694,100 @@ var _ = Describe("Namespace controller", func() {
 
 		})
 
+		It(" TEST 6: Create a NamespaceOffloading resource with preferences and a cap on the number of clusters", func() {
+
+			namespace8Name := "namespace8"
+			namespace8 := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: namespace8Name,
+				},
+			}
+
+			namespaceOffloading8 := &offv1alpha1.NamespaceOffloading{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      liqoconst.DefaultNamespaceOffloadingName,
+					Namespace: namespace8Name,
+				},
+				Spec: offv1alpha1.NamespaceOffloadingSpec{
+					NamespaceMappingStrategy: offv1alpha1.EnforceSameNameMappingStrategyType,
+					PodOffloadingStrategy:    offv1alpha1.LocalAndRemotePodOffloadingStrategyType,
+					ClusterSelector: corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+						MatchExpressions: []corev1.NodeSelectorRequirement{{
+							Key:      regionLabel,
+							Operator: corev1.NodeSelectorOpIn,
+							Values:   []string{regionA},
+						}},
+					}}},
+					PreferredClusterSelector: []corev1.PreferredSchedulingTerm{{
+						Weight: 50,
+						Preference: corev1.NodeSelectorTerm{
+							MatchExpressions: []corev1.NodeSelectorRequirement{{
+								Key:      providerLabel,
+								Operator: corev1.NodeSelectorOpIn,
+								Values:   []string{providerGKE},
+							}},
+						},
+					}},
+					MaxClusters: pointer.Int32(1),
+				},
+			}
+
+			By(fmt.Sprintf(" 1 - Create NamespaceOffloading resource in Namespace '%s'", namespace8Name))
+			Expect(homeClient.Create(context.TODO(), namespace8)).To(Succeed())
+			Eventually(func() bool {
+				err := homeClient.Create(context.TODO(), namespaceOffloading8)
+				return err == nil
+			}, timeout, interval).Should(BeTrue())
+
+			By(" 2 - Check NamespaceMap of virtual node 3, the preferred one")
+			Eventually(func() bool {
+				Expect(homeClient.List(context.TODO(), nms, client.MatchingLabels{liqoconst.RemoteClusterID: remoteCluster3.ClusterID})).To(Succeed())
+				Expect(len(nms.Items) == 1).To(BeTrue())
+				return nms.Items[0].Spec.DesiredMapping[namespace8Name] == namespace8Name
+			}, timeout, interval).Should(BeTrue())
+
+			By(" 3 - Check that the NamespaceMaps of virtual nodes 1 and 2 are not modified")
+			Consistently(func() bool {
+				Expect(homeClient.List(context.TODO(), nms)).To(Succeed())
+				Expect(len(nms.Items) == mapNumber).To(BeTrue())
+				for i := range nms.Items {
+					if nms.Items[i].Labels[liqoconst.RemoteClusterID] == remoteCluster3.ClusterID {
+						continue
+					}
+					if _, ok := nms.Items[i].Spec.DesiredMapping[namespace8Name]; ok {
+						return false
+					}
+				}
+				return true
+			}, time.Second*3, interval).Should(BeTrue())
+
+			By(" 4 - Delete NamespaceOffloading resource")
+			Eventually(func() bool {
+				if err := homeClient.Get(context.TODO(), types.NamespacedName{
+					Name:      liqoconst.DefaultNamespaceOffloadingName,
+					Namespace: namespace8Name}, namespaceOffloading8); err != nil {
+					return false
+				}
+				err := homeClient.Delete(context.TODO(), namespaceOffloading8)
+				return err == nil
+			}, timeout, interval).Should(BeTrue())
+
+			By(" 5 - Check if there are no DesiredMapping")
+			Eventually(func() bool {
+				if err := homeClient.List(context.TODO(), nms); err != nil {
+					return false
+				}
+				Expect(len(nms.Items) == mapNumber).To(BeTrue())
+				for i := range nms.Items {
+					if nms.Items[i].Spec.DesiredMapping != nil {
+						return false
+					}
+				}
+				return true
+			}, timeout, interval).Should(BeTrue())
+
+		})
+
 	})
 
 })
@@ -32,14 +32,17 @@ import (
 
 	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
 	liqoconst "github.com/liqotech/liqo/pkg/consts"
+	tenantnamespace "github.com/liqotech/liqo/pkg/tenantNamespace"
 	"github.com/liqotech/liqo/pkg/utils"
 	liqoerrors "github.com/liqotech/liqo/pkg/utils/errors"
+	foreignclusterutils "github.com/liqotech/liqo/pkg/utils/foreignCluster"
 )
 
 // createNamespace creates a new namespace associated with a NamespaceMap. It returns whether a possible error
 // could be ignored if previously successful or it refers to an hard failure.
+// No permission is granted to the origin cluster in the namespace while its identity is revoked.
 func (r *NamespaceMapReconciler) createNamespace(ctx context.Context, name, originName string,
-	nm *vkv1alpha1.NamespaceMap) (ignorable bool, err error) {
+	nm *vkv1alpha1.NamespaceMap, revoked bool) (ignorable bool, err error) {
 	// The label is guaranteed to exist, since it is part of the filter predicate.
 	origin := nm.Labels[liqoconst.ReplicationOriginLabel]
 	nmID, err := cache.MetaNamespaceKeyFunc(nm)
@@ -83,12 +86,22 @@ func (r *NamespaceMapReconciler) createNamespace(ctx context.Context, name, orig
 	// The rolebinding is named after the tenant namespace name, since that is guaranteed to be unique.
 	// This will simplify the support for remote namespaces associated with multiple origins.
 	binding := rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Namespace: name, Name: nm.GetNamespace()}}
+	if revoked {
+		if err = r.Delete(ctx, &binding); client.IgnoreNotFound(err) != nil {
+			return true, fmt.Errorf("failed to remove role binding %q: %w", klog.KObj(&binding), err)
+		}
+
+		klog.V(4).Infof("RoleBinding %q absence ensured, since the identity of cluster %q is revoked", klog.KObj(&binding), origin)
+		return true, nil
+	}
+
 	result, err := controllerutil.CreateOrUpdate(ctx, r.Client, &binding, func() error {
 		binding.Annotations = labels.Merge(binding.GetAnnotations(), map[string]string{
 			liqoconst.RemoteNamespaceManagedByAnnotationKey: nmID})
 
+		// The NamespaceMap is stored in the tenant namespace associated with the origin cluster.
+		binding.Subjects = tenantnamespace.RemoteClusterSubjects(origin, nm.GetNamespace())
 		if binding.CreationTimestamp.IsZero() {
-			binding.Subjects = []rbacv1.Subject{{APIGroup: rbacv1.GroupName, Kind: rbacv1.UserKind, Name: origin}}
 			binding.RoleRef = rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: liqoconst.RemoteNamespaceClusterRoleName}
 		}
 
@@ -105,11 +118,14 @@ func (r *NamespaceMapReconciler) createNamespace(ctx context.Context, name, orig
 // For every entry of DesiredMapping create remote Namespace if it has not already being created.
 // ensureNamespacesExistence tries to create all the remote namespaces requested in DesiredMapping (NamespaceMap->Spec->DesiredMapping).
 func (r *NamespaceMapReconciler) ensureNamespacesExistence(ctx context.Context, nm *vkv1alpha1.NamespaceMap) error {
-	var err error
+	revoked, err := r.isOriginRevoked(ctx, nm)
+	if err != nil {
+		return err
+	}
 
 	for originName, destinationName := range nm.Spec.DesiredMapping {
 		phase := vkv1alpha1.MappingAccepted
-		if ignorable, creationError := r.createNamespace(ctx, destinationName, originName, nm); creationError != nil {
+		if ignorable, creationError := r.createNamespace(ctx, destinationName, originName, nm, revoked); creationError != nil {
 			// Do not overwrite the phase in case the mapping was already present, ant this is marked as a temporary error.
 			previous, found := nm.Status.CurrentMapping[originName]
 			if !ignorable || !found || previous.Phase != vkv1alpha1.MappingAccepted {
@@ -126,6 +142,19 @@ func (r *NamespaceMapReconciler) ensureNamespacesExistence(ctx context.Context,
 	return err
 }
 
+// isOriginRevoked returns whether the identity issued to the cluster the NamespaceMap originates from has been revoked.
+func (r *NamespaceMapReconciler) isOriginRevoked(ctx context.Context, nm *vkv1alpha1.NamespaceMap) (bool, error) {
+	origin := nm.Labels[liqoconst.ReplicationOriginLabel]
+	fc, err := foreignclusterutils.GetForeignClusterByID(ctx, r.Client, origin)
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to retrieve the ForeignCluster associated with cluster %q: %w", origin, err)
+	}
+	return fc.Spec.IdentityRevoked, nil
+}
+
 // deleteNamespace removes an existing namespace associated with a NamespaceMap, and returns whether it still exists or not.
 func (r *NamespaceMapReconciler) deleteNamespace(ctx context.Context, namespaceName, nmID string) (existing bool, err error) {
 	var namespace corev1.Namespace
@@ -16,6 +16,7 @@ package namespacemapctrl
 
 import (
 	"context"
+	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -27,11 +28,13 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
 	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
 	"github.com/liqotech/liqo/internal/crdReplicator/reflection"
 	liqoconst "github.com/liqotech/liqo/pkg/consts"
@@ -122,5 +125,34 @@ func (r *NamespaceMapReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		// https://kubernetes.io/docs/concepts/overview/working-with-objects/owners-dependents/.
 		Watches(&source.Kind{Type: &corev1.Namespace{}}, handler.EnqueueRequestsFromMapFunc(enqueuer)).
 		Watches(&source.Kind{Type: &rbacv1.RoleBinding{}}, handler.EnqueueRequestsFromMapFunc(enqueuer)).
+		// The permissions granted to the origin cluster depend on whether its identity has been revoked.
+		Watches(&source.Kind{Type: &discoveryv1alpha1.ForeignCluster{}}, handler.EnqueueRequestsFromMapFunc(r.foreignClusterEnqueuer),
+			builder.WithPredicates(predicate.Funcs{UpdateFunc: func(ue event.UpdateEvent) bool {
+				return ue.ObjectOld.(*discoveryv1alpha1.ForeignCluster).Spec.IdentityRevoked !=
+					ue.ObjectNew.(*discoveryv1alpha1.ForeignCluster).Spec.IdentityRevoked
+			}})).
 		Complete(r)
 }
+
+// foreignClusterEnqueuer returns the requests for the NamespaceMaps originating from the given ForeignCluster.
+func (r *NamespaceMapReconciler) foreignClusterEnqueuer(obj client.Object) []reconcile.Request {
+	fc, ok := obj.(*discoveryv1alpha1.ForeignCluster)
+	if !ok {
+		return nil
+	}
+
+	var namespaceMaps vkv1alpha1.NamespaceMapList
+	if err := r.List(context.Background(), &namespaceMaps, client.MatchingLabels{
+		liqoconst.ReplicationOriginLabel: fc.Spec.ClusterIdentity.ClusterID,
+		liqoconst.ReplicationStatusLabel: strconv.FormatBool(true),
+	}); err != nil {
+		klog.Errorf("Failed to retrieve the NamespaceMaps originating from cluster %q: %v", fc.Spec.ClusterIdentity.ClusterID, err)
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(namespaceMaps.Items))
+	for i := range namespaceMaps.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&namespaceMaps.Items[i])})
+	}
+	return requests
+}
@@ -29,9 +29,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	ctrlutils "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
 	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
 	liqoconst "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/discovery"
 	namespacemapctrl "github.com/liqotech/liqo/pkg/liqo-controller-manager/namespacemap-controller"
+	tenantnamespace "github.com/liqotech/liqo/pkg/tenantNamespace"
 	. "github.com/liqotech/liqo/pkg/utils/testutil"
 )
 
@@ -86,7 +89,7 @@ var _ = Describe("Enforcement logic", func() {
 				It("should correctly ensure the rolebinding is present", func() {
 					var binding rbacv1.RoleBinding
 					Expect(reconciler.Get(ctx, types.NamespacedName{Namespace: "namespace-remote", Name: "tenant-namespace"}, &binding)).To(Succeed())
-					Expect(binding.Subjects).To(ConsistOf(rbacv1.Subject{APIGroup: rbacv1.GroupName, Kind: rbacv1.UserKind, Name: "origin"}))
+					Expect(binding.Subjects).To(ConsistOf(tenantnamespace.RemoteClusterSubjects("origin", "tenant-namespace")))
 					Expect(binding.RoleRef).To(Equal(
 						rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: liqoconst.RemoteNamespaceClusterRoleName}))
 					Expect(binding.GetAnnotations()).To(HaveKeyWithValue(liqoconst.RemoteNamespaceManagedByAnnotationKey, "tenant-namespace/name"))
@@ -136,6 +139,39 @@ var _ = Describe("Enforcement logic", func() {
 			})
 		})
 
+		Context("creation with the identity of the origin cluster revoked", func() {
+			BeforeEach(func() {
+				nm.Spec.DesiredMapping = map[string]string{"namespace": "namespace-remote"}
+
+				fc := discoveryv1alpha1.ForeignCluster{
+					ObjectMeta: metav1.ObjectMeta{Name: "origin", Labels: map[string]string{discovery.ClusterIDLabel: "origin"}},
+					Spec: discoveryv1alpha1.ForeignClusterSpec{
+						ClusterIdentity: discoveryv1alpha1.ClusterIdentity{ClusterID: "origin"},
+						IdentityRevoked: true,
+					},
+				}
+				binding := rbacv1.RoleBinding{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "namespace-remote", Name: "tenant-namespace"},
+					Subjects:   tenantnamespace.RemoteClusterSubjects("origin", "tenant-namespace"),
+					RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: liqoconst.RemoteNamespaceClusterRoleName},
+				}
+				clientBuilder.WithObjects(&fc, &binding)
+			})
+
+			It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+			It("should correctly ensure the namespace is present", func() {
+				var namespace corev1.Namespace
+				Expect(reconciler.Get(ctx, types.NamespacedName{Name: "namespace-remote"}, &namespace)).To(Succeed())
+			})
+			It("should not leave any rolebinding granting permissions to the origin cluster", func() {
+				var bindings rbacv1.RoleBindingList
+				Expect(reconciler.List(ctx, &bindings)).To(Succeed())
+				for _, subject := range tenantnamespace.RemoteClusterSubjects("origin", "tenant-namespace") {
+					Expect(bindings.Items).ToNot(ContainElement(HaveField("Subjects", ContainElement(subject))))
+				}
+			})
+		})
+
 		Context("multiple creations", func() {
 			BeforeEach(func() {
 				nm.Spec.DesiredMapping = map[string]string{
@@ -26,6 +26,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
 )
 
 // getForeignClusterEventHandler returns an event handler that reacts on ForeignClusters updates.
@@ -50,7 +51,8 @@ func getForeignClusterEventHandler(c client.Client) handler.EventHandler {
 			}
 
 			remoteCluster := newForeignCluster.Spec.ClusterIdentity
-			if oldForeignCluster.Spec.IncomingPeeringEnabled != newForeignCluster.Spec.IncomingPeeringEnabled {
+			if oldForeignCluster.Spec.IncomingPeeringEnabled != newForeignCluster.Spec.IncomingPeeringEnabled ||
+				oldForeignCluster.Spec.IdentityRevoked != newForeignCluster.Spec.IdentityRevoked {
 				resourceRequest, err := GetResourceRequest(ctx, c, remoteCluster.ClusterID)
 				if err != nil {
 					klog.Errorf("[%s] failed to list resource requests: %s\n", remoteCluster.ClusterName, err)
@@ -73,3 +75,29 @@ func getForeignClusterEventHandler(c client.Client) handler.EventHandler {
 		GenericFunc: func(ge event.GenericEvent, rli workqueue.RateLimitingInterface) {},
 	}
 }
+
+// getShadowPodEventHandler returns an event handler that reacts on ShadowPods changes, triggering the
+// reconciliation of the ResourceRequest of the originating cluster, to update the resources used by the related ResourceOffer.
+func getShadowPodEventHandler(c client.Client) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(obj client.Object) []reconcile.Request {
+		clusterID, found := obj.GetLabels()[forge.LiqoOriginClusterIDKey]
+		if !found {
+			return nil
+		}
+
+		resourceRequest, err := GetResourceRequest(context.Background(), c, clusterID)
+		if err != nil {
+			klog.Errorf("[%s] failed to list resource requests: %s", clusterID, err)
+			return nil
+		}
+		if resourceRequest == nil {
+			klog.V(3).Infof("[%s] no ResourceRequest found", clusterID)
+			return nil
+		}
+
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{
+			Name:      resourceRequest.GetName(),
+			Namespace: resourceRequest.GetNamespace(),
+		}}}
+	})
+}
@@ -25,14 +25,17 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/klog/v2"
+	resourcehelper "k8s.io/kubectl/pkg/util/resource"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
 	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
+	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
 	"github.com/liqotech/liqo/pkg/consts"
 	"github.com/liqotech/liqo/pkg/discovery"
 	resourcemonitors "github.com/liqotech/liqo/pkg/liqo-controller-manager/resource-request-controller/resource-monitors"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
 )
 
 // OfferUpdater is a component that responds to ResourceRequests with the cluster's resources read from ResourceReader.
@@ -43,6 +46,7 @@ type OfferUpdater struct {
 	client                    client.Client
 	homeCluster               discoveryv1alpha1.ClusterIdentity
 	clusterLabels             map[string]string
+	prices                    corev1.ResourceList
 	scheme                    *runtime.Scheme
 	localRealStorageClassName string
 	enableStorage             bool
@@ -57,13 +61,14 @@ type OfferUpdater struct {
 
 // NewOfferUpdater constructs a new OfferUpdater.
 func NewOfferUpdater(ctx context.Context, k8sClient client.Client, homeCluster discoveryv1alpha1.ClusterIdentity,
-	clusterLabels map[string]string, reader resourcemonitors.ResourceReader, updateThresholdPercentage uint,
+	clusterLabels map[string]string, prices corev1.ResourceList, reader resourcemonitors.ResourceReader, updateThresholdPercentage uint,
 	localRealStorageClassName string, enableStorage bool) *OfferUpdater {
 	updater := &OfferUpdater{
 		ResourceReader:            reader,
 		client:                    k8sClient,
 		homeCluster:               homeCluster,
 		clusterLabels:             clusterLabels,
+		prices:                    prices,
 		scheme:                    k8sClient.Scheme(),
 		localRealStorageClassName: localRealStorageClassName,
 		enableStorage:             enableStorage,
@@ -124,6 +129,9 @@ func (u *OfferUpdater) CreateOrUpdateOffer(cluster discoveryv1alpha1.ClusterIden
 		offer.Spec.ClusterID = u.homeCluster.ClusterID
 		offer.Spec.ResourceQuota.Hard = resources.DeepCopy()
 		offer.Spec.Labels = u.clusterLabels
+		offer.Spec.Prices = u.prices.DeepCopy()
+		offer.Spec.NodePools = u.readNodePools(ctx, cluster.ClusterID)
+		offer.Spec.LargestAllocatable, offer.Spec.Fragmentation = u.readFragmentation(ctx, cluster.ClusterID)
 
 		offer.Spec.StorageClasses, err = u.getStorageClasses(ctx)
 		if err != nil {
@@ -138,9 +146,53 @@ func (u *OfferUpdater) CreateOrUpdateOffer(cluster discoveryv1alpha1.ClusterIden
 		return true, err
 	}
 	klog.Infof("%s -> %s Offer: %s/%s", u.homeCluster.ClusterName, op, offer.Namespace, offer.Name)
+
+	if err = u.updateUsage(ctx, offer, cluster.ClusterID); err != nil {
+		klog.Errorf("%s -> Failed to update the resources used by Offer %s/%s: %v", u.homeCluster.ClusterName, offer.Namespace, offer.Name, err)
+		return true, err
+	}
 	return false, nil
 }
 
+// updateUsage updates the status of the given ResourceOffer with the resources currently requested
+// by the ShadowPods originated from the given cluster.
+func (u *OfferUpdater) updateUsage(ctx context.Context, offer *sharingv1alpha1.ResourceOffer, clusterID string) error {
+	used, err := u.readUsage(ctx, clusterID)
+	if err != nil {
+		return err
+	}
+
+	if resourcesEqual(offer.Status.Used, used) {
+		return nil
+	}
+
+	original := offer.DeepCopy()
+	offer.Status.Used = used
+	return u.client.Status().Patch(ctx, offer, client.MergeFrom(original))
+}
+
+// readUsage returns the resources requested by the ShadowPods originated from the given cluster, and not being deleted.
+func (u *OfferUpdater) readUsage(ctx context.Context, clusterID string) (corev1.ResourceList, error) {
+	var shadowPods vkv1alpha1.ShadowPodList
+	if err := u.client.List(ctx, &shadowPods, client.MatchingLabels{forge.LiqoOriginClusterIDKey: clusterID}); err != nil {
+		return nil, fmt.Errorf("failed to list the ShadowPods of cluster %s: %w", clusterID, err)
+	}
+
+	used := corev1.ResourceList{}
+	for i := range shadowPods.Items {
+		if !shadowPods.Items[i].DeletionTimestamp.IsZero() {
+			continue
+		}
+		requests, _ := resourcehelper.PodRequestsAndLimits(&corev1.Pod{Spec: shadowPods.Items[i].Spec.Pod})
+		for name, quantity := range requests {
+			total := used[name]
+			total.Add(quantity)
+			used[name] = total
+		}
+	}
+	return used, nil
+}
+
 // NotifyChange is used by the ResourceReader to notify that resources were added or removed.
 // It checks if any resources have changed by at least a set percentage since we last updated a ResourceOffer, and if so
 // it triggers a new update.
@@ -159,6 +211,25 @@ func (u *OfferUpdater) RemoveClusterID(clusterID string) {
 	u.OfferQueue.RemoveClusterID(clusterID)
 }
 
+// readNodePools returns the resources to be offered to the given cluster grouped by node pool,
+// if supported by the ResourceReader.
+func (u *OfferUpdater) readNodePools(ctx context.Context, clusterID string) []sharingv1alpha1.NodePool {
+	if reader, ok := u.ResourceReader.(resourcemonitors.NodePoolReader); ok {
+		return reader.ReadNodePools(ctx, clusterID)
+	}
+	return nil
+}
+
+// readFragmentation returns the largest quantity of each resource to be offered to the given cluster on a single node,
+// along with the fragmentation histograms, if supported by the ResourceReader.
+func (u *OfferUpdater) readFragmentation(ctx context.Context,
+	clusterID string) (corev1.ResourceList, []sharingv1alpha1.ResourceFragmentation) {
+	if reader, ok := u.ResourceReader.(resourcemonitors.FragmentationReader); ok {
+		return reader.ReadFragmentation(ctx, clusterID)
+	}
+	return nil, nil
+}
+
 func (u *OfferUpdater) getStorageClasses(ctx context.Context) ([]sharingv1alpha1.StorageType, error) {
 	if !u.enableStorage {
 		return []sharingv1alpha1.StorageType{}, nil
@@ -235,6 +306,21 @@ func resourceIsEmpty(list corev1.ResourceList) bool {
 	return true
 }
 
+// resourcesEqual checks whether the two ResourceLists contain the same quantities, ignoring the zero ones.
+func resourcesEqual(first, second corev1.ResourceList) bool {
+	for name, quantity := range first {
+		if other := second[name]; quantity.Cmp(other) != 0 {
+			return false
+		}
+	}
+	for name, quantity := range second {
+		if _, found := first[name]; !found && !quantity.IsZero() {
+			return false
+		}
+	}
+	return true
+}
+
 // GetResourceRequest returns ResourceRequest for the given cluster.
 func GetResourceRequest(ctx context.Context, k8sClient client.Client, clusterID string) (
 	*discoveryv1alpha1.ResourceRequest, error) {
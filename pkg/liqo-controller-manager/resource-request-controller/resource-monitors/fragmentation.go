@@ -0,0 +1,100 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcemonitors
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
+)
+
+// fragmentationThresholds are the quantities used as boundaries for the buckets of the fragmentation histograms.
+var fragmentationThresholds = map[corev1.ResourceName][]resource.Quantity{
+	corev1.ResourceCPU: parseQuantities("250m", "500m", "1", "2", "4", "8", "16", "32", "64"),
+	corev1.ResourceMemory: parseQuantities("256Mi", "512Mi", "1Gi", "2Gi", "4Gi", "8Gi",
+		"16Gi", "32Gi", "64Gi", "128Gi", "256Gi"),
+}
+
+// largestAllocatable returns the largest quantity of each resource available on a single node.
+func largestAllocatable(free []corev1.ResourceList) corev1.ResourceList {
+	largest := corev1.ResourceList{}
+	for i := range free {
+		maxResources(largest, free[i])
+	}
+	return largest
+}
+
+// fragmentationHistograms returns, for each resource characterized by a set of thresholds, the cumulative histogram
+// of the nodes by available quantity. Buckets not including any node are omitted.
+func fragmentationHistograms(free []corev1.ResourceList) []sharingv1alpha1.ResourceFragmentation {
+	histograms := make([]sharingv1alpha1.ResourceFragmentation, 0, len(fragmentationThresholds))
+	for resourceName, thresholds := range fragmentationThresholds {
+		histogram := sharingv1alpha1.ResourceFragmentation{Name: resourceName}
+		for i := range thresholds {
+			var nodes int32
+			for j := range free {
+				if available, found := free[j][resourceName]; found && available.Cmp(thresholds[i]) >= 0 {
+					nodes++
+				}
+			}
+
+			// The histogram is cumulative, hence no node is accounted in the following buckets either.
+			if nodes == 0 {
+				break
+			}
+			histogram.Buckets = append(histogram.Buckets, sharingv1alpha1.FragmentationBucket{
+				Available: thresholds[i].DeepCopy(), Nodes: nodes})
+		}
+		histograms = append(histograms, histogram)
+	}
+
+	sort.Slice(histograms, func(i, j int) bool { return histograms[i].Name < histograms[j].Name })
+	return histograms
+}
+
+// capFragmentation caps the fragmentation information to the resources shared with a given cluster,
+// since no pod requesting more than the shared resources can be hosted.
+func capFragmentation(largest corev1.ResourceList, histograms []sharingv1alpha1.ResourceFragmentation,
+	shared corev1.ResourceList) (corev1.ResourceList, []sharingv1alpha1.ResourceFragmentation) {
+	capped := corev1.ResourceList{}
+	for resourceName, quantity := range largest {
+		value := shared[resourceName]
+		capped[resourceName] = minQuantity(quantity, value)
+	}
+
+	cappedHistograms := make([]sharingv1alpha1.ResourceFragmentation, len(histograms))
+	for i := range histograms {
+		cappedHistograms[i] = sharingv1alpha1.ResourceFragmentation{Name: histograms[i].Name}
+		value := shared[histograms[i].Name]
+		for j := range histograms[i].Buckets {
+			if histograms[i].Buckets[j].Available.Cmp(value) > 0 {
+				break
+			}
+			cappedHistograms[i].Buckets = append(cappedHistograms[i].Buckets, *histograms[i].Buckets[j].DeepCopy())
+		}
+	}
+	return capped, cappedHistograms
+}
+
+func parseQuantities(values ...string) []resource.Quantity {
+	quantities := make([]resource.Quantity, len(values))
+	for i := range values {
+		quantities[i] = resource.MustParse(values[i])
+	}
+	return quantities
+}
@@ -0,0 +1,73 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcemonitors
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
+)
+
+var _ = Describe("Fragmentation", func() {
+	newResources := func(cpu, memory string) corev1.ResourceList {
+		return corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse(memory)}
+	}
+
+	var free []corev1.ResourceList
+
+	BeforeEach(func() {
+		free = []corev1.ResourceList{newResources("500m", "1Gi"), newResources("500m", "3Gi"), newResources("2500m", "512Mi")}
+	})
+
+	It("largestAllocatable should return the largest quantity of each resource available on a single node", func() {
+		largest := largestAllocatable(free)
+		Expect(largest.Cpu().Equal(resource.MustParse("2500m"))).To(BeTrue())
+		Expect(largest.Memory().Equal(resource.MustParse("3Gi"))).To(BeTrue())
+	})
+
+	It("fragmentationHistograms should return the cumulative histograms of the nodes by available quantity", func() {
+		histograms := fragmentationHistograms(free)
+		Expect(histograms).To(HaveLen(2))
+
+		Expect(histograms[0].Name).To(Equal(corev1.ResourceCPU))
+		Expect(histograms[0].Buckets).To(Equal([]sharingv1alpha1.FragmentationBucket{
+			{Available: resource.MustParse("250m"), Nodes: 3},
+			{Available: resource.MustParse("500m"), Nodes: 3},
+			{Available: resource.MustParse("1"), Nodes: 1},
+			{Available: resource.MustParse("2"), Nodes: 1},
+		}))
+
+		Expect(histograms[1].Name).To(Equal(corev1.ResourceMemory))
+		Expect(histograms[1].Buckets).To(Equal([]sharingv1alpha1.FragmentationBucket{
+			{Available: resource.MustParse("256Mi"), Nodes: 3},
+			{Available: resource.MustParse("512Mi"), Nodes: 3},
+			{Available: resource.MustParse("1Gi"), Nodes: 2},
+			{Available: resource.MustParse("2Gi"), Nodes: 1},
+		}))
+	})
+
+	It("capFragmentation should cap the fragmentation information to the shared resources", func() {
+		largest, histograms := capFragmentation(largestAllocatable(free), fragmentationHistograms(free), newResources("1", "8Gi"))
+		Expect(largest.Cpu().Equal(resource.MustParse("1"))).To(BeTrue())
+		Expect(largest.Memory().Equal(resource.MustParse("3Gi"))).To(BeTrue())
+
+		Expect(histograms).To(HaveLen(2))
+		Expect(histograms[0].Buckets).To(HaveLen(3))
+		Expect(histograms[1].Buckets).To(HaveLen(4))
+	})
+})
@@ -18,6 +18,9 @@ import (
 	"context"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
 )
 
 // ResourceUpdateNotifier represents an interface for OfferUpdater to receive resource updates.
@@ -35,3 +38,32 @@ type ResourceReader interface {
 	// RemoveClusterID removes the given clusterID from all internal structures.
 	RemoveClusterID(ctx context.Context, clusterID string)
 }
+
+// NodeAwareResourceReader represents a ResourceReader which can additionally exclude the resources of a subset of nodes.
+type NodeAwareResourceReader interface {
+	ResourceReader
+	// ReadResourcesExcludingNodes returns the resources available for usage by the given cluster,
+	// without considering the ones of the nodes matching the given selector.
+	ReadResourcesExcludingNodes(ctx context.Context, clusterID string, selector labels.Selector) corev1.ResourceList
+	// ReadNodePoolsExcludingNodes returns the resources available for usage by the given cluster, grouped by node pool,
+	// without considering the ones of the nodes matching the given selector.
+	ReadNodePoolsExcludingNodes(ctx context.Context, clusterID string, selector labels.Selector) []sharingv1alpha1.NodePool
+	// ReadFragmentationExcludingNodes returns the largest quantity of each resource available for usage by the given
+	// cluster on a single node, along with the fragmentation histograms, without considering the nodes matching the selector.
+	ReadFragmentationExcludingNodes(ctx context.Context, clusterID string,
+		selector labels.Selector) (corev1.ResourceList, []sharingv1alpha1.ResourceFragmentation)
+}
+
+// NodePoolReader represents an interface to read the available resources in this cluster, grouped by node pool.
+type NodePoolReader interface {
+	// ReadNodePools returns the resources available for usage by the given cluster, grouped by node pool.
+	// It returns nil if the resources are not grouped into node pools.
+	ReadNodePools(ctx context.Context, clusterID string) []sharingv1alpha1.NodePool
+}
+
+// FragmentationReader represents an interface to read how the available resources are distributed across the nodes of this cluster.
+type FragmentationReader interface {
+	// ReadFragmentation returns the largest quantity of each resource available for usage by the given cluster
+	// on a single node, along with the histograms describing how the available resources are distributed across the nodes.
+	ReadFragmentation(ctx context.Context, clusterID string) (corev1.ResourceList, []sharingv1alpha1.ResourceFragmentation)
+}
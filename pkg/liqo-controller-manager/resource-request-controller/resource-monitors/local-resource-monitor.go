@@ -17,6 +17,7 @@ package resourcemonitors
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -33,6 +34,7 @@ import (
 	"k8s.io/klog/v2"
 	resourcehelper "k8s.io/kubectl/pkg/util/resource"
 
+	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
 	"github.com/liqotech/liqo/pkg/consts"
 	"github.com/liqotech/liqo/pkg/utils"
 	liqoerrors "github.com/liqotech/liqo/pkg/utils/errors"
@@ -46,8 +48,19 @@ type LocalResourceMonitor struct {
 	nodeMutex      sync.RWMutex
 	podMutex       sync.RWMutex
 	notifier       ResourceUpdateNotifier
+
+	nodeStore cache.Store
+	podIndex  cache.Indexer
+	// nodePoolLabel is the key of the label grouping the nodes into node pools (if any).
+	nodePoolLabel string
 }
 
+// DefaultNodePool is the name of the node pool grouping the nodes without the node pool label.
+const DefaultNodePool = "default"
+
+// podNodeNameIndex is the name of the index to retrieve the pods running on a given node.
+const podNodeNameIndex = "nodeName"
+
 // PodTransition represents a podReady condition possible transitions.
 type PodTransition uint8
 
@@ -62,9 +75,10 @@ const (
 	PendingToPending
 )
 
-// NewLocalMonitor creates a new LocalResourceMonitor.
+// NewLocalMonitor creates a new LocalResourceMonitor. If nodePoolLabel is not empty, the available resources
+// are additionally grouped into node pools, according to the value of the corresponding label of each node.
 func NewLocalMonitor(ctx context.Context, clientset kubernetes.Interface,
-	resyncPeriod time.Duration) *LocalResourceMonitor {
+	resyncPeriod time.Duration, nodePoolLabel string) *LocalResourceMonitor {
 	nodeFactory := informers.NewSharedInformerFactoryWithOptions(
 		clientset, resyncPeriod, informers.WithTweakListOptions(noVirtualNodesFilter),
 	)
@@ -73,10 +87,14 @@ func NewLocalMonitor(ctx context.Context, clientset kubernetes.Interface,
 		clientset, resyncPeriod, informers.WithTweakListOptions(noShadowPodsFilter),
 	)
 	podInformer := podFactory.Core().V1().Pods().Informer()
+	utilruntime.Must(podInformer.AddIndexers(cache.Indexers{podNodeNameIndex: podNodeNameIndexer}))
 
 	lrm := LocalResourceMonitor{
 		allocatable:    corev1.ResourceList{},
 		resourcePodMap: map[string]corev1.ResourceList{},
+		nodeStore:      nodeInformer.GetStore(),
+		podIndex:       podInformer.GetIndexer(),
+		nodePoolLabel:  nodePoolLabel,
 	}
 
 	nodeInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -230,6 +248,134 @@ func (m *LocalResourceMonitor) ReadResources(_ context.Context, clusterID string
 	return toRead
 }
 
+// ReadResourcesExcludingNodes returns the resources available for the given cluster, without considering the ones of the nodes
+// matching the given selector (i.e., their allocatable resources not already requested by the pods of other clusters).
+func (m *LocalResourceMonitor) ReadResourcesExcludingNodes(ctx context.Context, clusterID string, selector labels.Selector) corev1.ResourceList {
+	toRead := m.ReadResources(ctx, clusterID)
+	for _, node := range m.readyNodes(selector) {
+		free := m.readNodeResources(node, clusterID)
+		klog.V(5).Infof("Excluding the resources of node %q (%v) for cluster %q", node.Name, free, clusterID)
+		subResources(toRead, free)
+	}
+
+	// The resources of the excluded nodes might exceed the available ones, due to transient inconsistencies.
+	clampToZero(toRead)
+	return toRead
+}
+
+// ReadNodePools returns the resources available for the given cluster, grouped by node pool.
+func (m *LocalResourceMonitor) ReadNodePools(ctx context.Context, clusterID string) []sharingv1alpha1.NodePool {
+	return m.ReadNodePoolsExcludingNodes(ctx, clusterID, labels.Nothing())
+}
+
+// ReadNodePoolsExcludingNodes returns the resources available for the given cluster, grouped by node pool,
+// without considering the nodes matching the given selector. It returns nil if no node pool label is configured.
+func (m *LocalResourceMonitor) ReadNodePoolsExcludingNodes(_ context.Context, clusterID string,
+	selector labels.Selector) []sharingv1alpha1.NodePool {
+	if m.nodePoolLabel == "" {
+		return nil
+	}
+
+	pools := map[string]*sharingv1alpha1.NodePool{}
+	for _, node := range m.readyNodes(labels.Everything()) {
+		if selector.Matches(labels.Set(node.Labels)) {
+			continue
+		}
+
+		name, found := node.Labels[m.nodePoolLabel]
+		if !found || name == "" {
+			name = DefaultNodePool
+		}
+
+		free := m.readNodeResources(node, clusterID)
+		clampToZero(free)
+
+		pool, found := pools[name]
+		if !found {
+			pool = &sharingv1alpha1.NodePool{
+				Name:                  name,
+				Resources:             corev1.ResourceList{},
+				MaxAllocatablePerNode: corev1.ResourceList{},
+				Taints:                schedulingTaints(node.Spec.Taints),
+			}
+			if name != DefaultNodePool {
+				pool.Labels = map[string]string{m.nodePoolLabel: name}
+			}
+			pools[name] = pool
+		} else {
+			// Only the taints shared by all nodes of the pool are propagated.
+			pool.Taints = commonTaints(pool.Taints, node.Spec.Taints)
+		}
+
+		pool.Nodes++
+		addResources(pool.Resources, free)
+		maxResources(pool.MaxAllocatablePerNode, free)
+	}
+
+	result := make([]sharingv1alpha1.NodePool, 0, len(pools))
+	for _, pool := range pools {
+		result = append(result, *pool)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// ReadFragmentation returns the largest quantity of each resource available for the given cluster on a single node,
+// along with the histograms describing how the available resources are distributed across the nodes.
+func (m *LocalResourceMonitor) ReadFragmentation(ctx context.Context,
+	clusterID string) (corev1.ResourceList, []sharingv1alpha1.ResourceFragmentation) {
+	return m.ReadFragmentationExcludingNodes(ctx, clusterID, labels.Nothing())
+}
+
+// ReadFragmentationExcludingNodes returns the largest quantity of each resource available for the given cluster
+// on a single node, along with the fragmentation histograms, without considering the nodes matching the given selector.
+func (m *LocalResourceMonitor) ReadFragmentationExcludingNodes(_ context.Context, clusterID string,
+	selector labels.Selector) (corev1.ResourceList, []sharingv1alpha1.ResourceFragmentation) {
+	var free []corev1.ResourceList
+	for _, node := range m.readyNodes(labels.Everything()) {
+		if selector.Matches(labels.Set(node.Labels)) {
+			continue
+		}
+
+		resources := m.readNodeResources(node, clusterID)
+		clampToZero(resources)
+		free = append(free, resources)
+	}
+	return largestAllocatable(free), fragmentationHistograms(free)
+}
+
+// readyNodes returns the ready nodes matching the given selector.
+func (m *LocalResourceMonitor) readyNodes(selector labels.Selector) []*corev1.Node {
+	var nodes []*corev1.Node
+	for _, obj := range m.nodeStore.List() {
+		node := obj.(*corev1.Node)
+		if utils.IsNodeReady(node) && selector.Matches(labels.Set(node.Labels)) {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// readNodeResources returns the resources of the given node available for the given cluster,
+// that is its allocatable resources not already requested by the pods of other clusters.
+func (m *LocalResourceMonitor) readNodeResources(node *corev1.Node, clusterID string) corev1.ResourceList {
+	free := node.Status.Allocatable.DeepCopy()
+	pods, err := m.podIndex.ByIndex(podNodeNameIndex, node.Name)
+	if err != nil {
+		klog.Errorf("Failed to retrieve the pods running on node %q: %v", node.Name, err)
+		return free
+	}
+
+	for _, obj := range pods {
+		pod := obj.(*corev1.Pod)
+		// The resources requested by the pods of the given cluster are accounted as available.
+		if clusterID == "" || pod.Labels[forge.LiqoOriginClusterIDKey] != clusterID {
+			subResources(free, extractPodResources(pod))
+		}
+	}
+	return free
+}
+
 // RemoveClusterID removes a clusterID from all broadcaster internal structures
 // it is useful when a particular foreign cluster has no more peering and its ResourceRequest has been deleted.
 func (m *LocalResourceMonitor) RemoveClusterID(_ context.Context, clusterID string) {
@@ -264,6 +410,50 @@ func setZero(resources *corev1.ResourceList) {
 	}
 }
 
+// clampToZero is a utility function to set to zero the negative resources.
+func clampToZero(resources corev1.ResourceList) {
+	for resourceName, value := range resources {
+		if value.Sign() == -1 {
+			value.Set(0)
+			resources[resourceName] = value
+		}
+	}
+}
+
+// maxResources is a utility function to set the current resources to the maximum between them and the given ones.
+func maxResources(currentResources, other corev1.ResourceList) {
+	for resourceName, quantity := range other {
+		if value, exists := currentResources[resourceName]; !exists || value.Cmp(quantity) < 0 {
+			currentResources[resourceName] = quantity.DeepCopy()
+		}
+	}
+}
+
+// schedulingTaints returns the taints affecting the scheduling of pods (i.e., NoSchedule and NoExecute).
+func schedulingTaints(taints []corev1.Taint) []corev1.Taint {
+	var filtered []corev1.Taint
+	for i := range taints {
+		if taints[i].Effect == corev1.TaintEffectNoSchedule || taints[i].Effect == corev1.TaintEffectNoExecute {
+			filtered = append(filtered, corev1.Taint{Key: taints[i].Key, Value: taints[i].Value, Effect: taints[i].Effect})
+		}
+	}
+	return filtered
+}
+
+// commonTaints returns the taints of the first list which are also present in the second one.
+func commonTaints(taints, others []corev1.Taint) []corev1.Taint {
+	var common []corev1.Taint
+	for i := range taints {
+		for j := range others {
+			if taints[i].MatchTaint(&others[j]) && taints[i].Value == others[j].Value {
+				common = append(common, taints[i])
+				break
+			}
+		}
+	}
+	return common
+}
+
 // addResources is a utility function to add resources.
 func addResources(currentResources, toAdd corev1.ResourceList) {
 	for resourceName, quantity := range toAdd {
@@ -322,6 +512,15 @@ func noVirtualNodesFilter(options *metav1.ListOptions) {
 	options.LabelSelector = labels.NewSelector().Add(*req).String()
 }
 
+// podNodeNameIndexer indexes the pods by the name of the node they are running on.
+func podNodeNameIndexer(obj interface{}) ([]string, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok || pod.Spec.NodeName == "" {
+		return []string{}, nil
+	}
+	return []string{pod.Spec.NodeName}, nil
+}
+
 // this function is used to filter and ignore shadow pods at informer level.
 func noShadowPodsFilter(options *metav1.ListOptions) {
 	req, err := labels.NewRequirement(consts.LocalPodLabelKey, selection.NotEquals, []string{consts.LocalPodLabelValue})
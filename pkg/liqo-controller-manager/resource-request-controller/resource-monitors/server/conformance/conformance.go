@@ -0,0 +1,159 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package conformance
+
+import (
+	"context"
+	"time"
+
+	"github.com/onsi/ginkgo"
+	"github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	resourcemonitors "github.com/liqotech/liqo/pkg/liqo-controller-manager/resource-request-controller/resource-monitors"
+)
+
+// DefaultTimeout is the default timeout for the operations performed against the resource monitor under test.
+const DefaultTimeout = 5 * time.Second
+
+// Options configures the conformance test suite.
+type Options struct {
+	// Address is the address the resource monitor under test is listening on.
+	Address string
+	// ClusterIDs are the cluster IDs the resources are requested for (a set of fake ones is used if not specified).
+	ClusterIDs []string
+	// TriggerChange, if set, causes the resource monitor under test to notify a possible change in resources.
+	// The notification tests are skipped otherwise.
+	TriggerChange func()
+	// Timeout is the timeout for each operation (DefaultTimeout if not specified).
+	Timeout time.Duration
+}
+
+// Describe declares the ginkgo specs verifying the conformance of a resource monitor to the resource_reader protocol.
+// The options are retrieved lazily (i.e., when the specs are run), so that they can be configured in a BeforeSuite node.
+func Describe(options func() Options) bool {
+	return ginkgo.Describe("Resource monitor conformance", func() {
+		var (
+			ctx    context.Context
+			cancel context.CancelFunc
+			opts   Options
+			conn   *grpc.ClientConn
+			client resourcemonitors.ResourceReaderClient
+		)
+
+		ginkgo.BeforeEach(func() {
+			opts = options()
+			if opts.Timeout == 0 {
+				opts.Timeout = DefaultTimeout
+			}
+			if len(opts.ClusterIDs) == 0 {
+				opts.ClusterIDs = []string{"conformance-cluster-1", "conformance-cluster-2"}
+			}
+
+			ctx, cancel = context.WithCancel(context.Background())
+
+			dialctx, dialcancel := context.WithTimeout(ctx, opts.Timeout)
+			defer dialcancel()
+
+			var err error
+			conn, err = grpc.DialContext(dialctx, opts.Address, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+			gomega.Expect(err).ToNot(gomega.HaveOccurred(), "failed to connect to %q", opts.Address)
+			client = resourcemonitors.NewResourceReaderClient(conn)
+		})
+
+		ginkgo.AfterEach(func() {
+			cancel()
+			gomega.Expect(conn.Close()).To(gomega.Succeed())
+		})
+
+		ginkgo.It("should return valid resources for every cluster", func() {
+			for _, clusterID := range opts.ClusterIDs {
+				rctx, rcancel := context.WithTimeout(ctx, opts.Timeout)
+				response, err := client.ReadResources(rctx, &resourcemonitors.ReadRequest{Originator: clusterID})
+				rcancel()
+				gomega.Expect(err).ToNot(gomega.HaveOccurred(), "failed to read the resources for %q", clusterID)
+
+				for name, value := range response.GetResources() {
+					gomega.Expect(validation.IsQualifiedName(name)).To(gomega.BeEmpty(), "invalid resource name %q", name)
+					quantity, err := resource.ParseQuantity(value)
+					gomega.Expect(err).ToNot(gomega.HaveOccurred(), "invalid quantity %q for resource %q", value, name)
+					gomega.Expect(quantity.Sign()).To(gomega.BeNumerically(">=", 0), "negative quantity for resource %q", name)
+				}
+			}
+		})
+
+		ginkgo.It("should accept the removal of unknown clusters", func() {
+			rctx, rcancel := context.WithTimeout(ctx, opts.Timeout)
+			defer rcancel()
+
+			_, err := client.RemoveCluster(rctx, &resourcemonitors.RemoveRequest{Cluster: "conformance-unknown-cluster"})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("should keep serving the resources after the removal of a cluster", func() {
+			clusterID := opts.ClusterIDs[0]
+			rctx, rcancel := context.WithTimeout(ctx, opts.Timeout)
+			defer rcancel()
+
+			_, err := client.RemoveCluster(rctx, &resourcemonitors.RemoveRequest{Cluster: clusterID})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+			_, err = client.ReadResources(rctx, &resourcemonitors.ReadRequest{Originator: clusterID})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("should notify the subscribers of possible changes", func() {
+			if opts.TriggerChange == nil {
+				ginkgo.Skip("no trigger function configured")
+			}
+
+			stream, err := client.Subscribe(ctx, &resourcemonitors.SubscribeRequest{})
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+			notifications := make(chan error, 1)
+			go func() {
+				_, err := stream.Recv()
+				notifications <- err
+			}()
+
+			// The subscription is established asynchronously, hence keep triggering changes until a notification is received.
+			gomega.Eventually(func() bool {
+				opts.TriggerChange()
+				select {
+				case err := <-notifications:
+					gomega.Expect(err).ToNot(gomega.HaveOccurred())
+					return true
+				case <-time.After(opts.Timeout / 10):
+					return false
+				}
+			}, opts.Timeout).Should(gomega.BeTrue())
+		})
+
+		ginkgo.It("should interoperate with the ExternalResourceMonitor", func() {
+			monitor, err := resourcemonitors.NewExternalMonitor(ctx, opts.Address)
+			gomega.Expect(err).ToNot(gomega.HaveOccurred())
+
+			for _, clusterID := range opts.ClusterIDs {
+				rctx, rcancel := context.WithTimeout(ctx, opts.Timeout)
+				response, err := client.ReadResources(rctx, &resourcemonitors.ReadRequest{Originator: clusterID})
+				rcancel()
+				gomega.Expect(err).ToNot(gomega.HaveOccurred())
+				gomega.Expect(monitor.ReadResources(ctx, clusterID)).To(gomega.HaveLen(len(response.GetResources())))
+			}
+		})
+	})
+}
@@ -0,0 +1,198 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+
+	resourcemonitors "github.com/liqotech/liqo/pkg/liqo-controller-manager/resource-request-controller/resource-monitors"
+)
+
+// Policy computes the resources to be offered to the remote clusters.
+type Policy interface {
+	// ReadResources returns the resources to be offered to the given cluster.
+	ReadResources(ctx context.Context, clusterID string) (corev1.ResourceList, error)
+	// RemoveCluster notifies that the given cluster is no longer peered, hence the associated data can be released.
+	RemoveCluster(ctx context.Context, clusterID string) error
+}
+
+// PolicyFunc is an adapter to allow the use of ordinary functions as policies, when no per-cluster data is retained.
+type PolicyFunc func(ctx context.Context, clusterID string) (corev1.ResourceList, error)
+
+// ReadResources calls f(ctx, clusterID).
+func (f PolicyFunc) ReadResources(ctx context.Context, clusterID string) (corev1.ResourceList, error) {
+	return f(ctx, clusterID)
+}
+
+// RemoveCluster is a no-op, as no per-cluster data is retained.
+func (f PolicyFunc) RemoveCluster(context.Context, string) error {
+	return nil
+}
+
+// readerPolicy adapts a ResourceReader to the Policy interface.
+type readerPolicy struct {
+	reader resourcemonitors.ResourceReader
+}
+
+// FromResourceReader returns a policy offering the resources returned by the given ResourceReader
+// (e.g., a LocalResourceMonitor observing a different cluster).
+func FromResourceReader(reader resourcemonitors.ResourceReader) Policy {
+	return &readerPolicy{reader: reader}
+}
+
+// ReadResources returns the resources returned by the underlying ResourceReader.
+func (p *readerPolicy) ReadResources(ctx context.Context, clusterID string) (corev1.ResourceList, error) {
+	return p.reader.ReadResources(ctx, clusterID), nil
+}
+
+// RemoveCluster removes the given cluster from the underlying ResourceReader.
+func (p *readerPolicy) RemoveCluster(ctx context.Context, clusterID string) error {
+	p.reader.RemoveClusterID(ctx, clusterID)
+	return nil
+}
+
+// Server implements the resource_reader gRPC protocol on top of a Policy.
+// It additionally implements the ResourceUpdateNotifier interface, to notify the subscribers of possible changes.
+type Server struct {
+	resourcemonitors.UnimplementedResourceReaderServer
+
+	policy Policy
+
+	subscribersMutex sync.Mutex
+	subscribers      map[chan struct{}]struct{}
+
+	// stopping is closed when the server is being stopped, to terminate the subscription streams.
+	stopping chan struct{}
+	stopOnce sync.Once
+}
+
+var _ resourcemonitors.ResourceReaderServer = &Server{}
+var _ resourcemonitors.ResourceUpdateNotifier = &Server{}
+
+// New returns a new Server, offering the resources computed by the given policy.
+func New(policy Policy) *Server {
+	return &Server{
+		policy:      policy,
+		subscribers: map[chan struct{}]struct{}{},
+		stopping:    make(chan struct{}),
+	}
+}
+
+// ReadResources returns the resources to be offered to the originator cluster, as computed by the policy.
+func (s *Server) ReadResources(ctx context.Context, req *resourcemonitors.ReadRequest) (*resourcemonitors.ReadResponse, error) {
+	resources, err := s.policy.ReadResources(ctx, req.GetOriginator())
+	if err != nil {
+		klog.Errorf("Failed to read the resources for cluster %q: %v", req.GetOriginator(), err)
+		return nil, err
+	}
+
+	response := &resourcemonitors.ReadResponse{Resources: make(map[string]string, len(resources))}
+	for name, quantity := range resources {
+		response.Resources[name.String()] = quantity.String()
+	}
+	return response, nil
+}
+
+// RemoveCluster notifies the policy that the given cluster is no longer peered.
+func (s *Server) RemoveCluster(ctx context.Context, req *resourcemonitors.RemoveRequest) (*resourcemonitors.RemoveResponse, error) {
+	if err := s.policy.RemoveCluster(ctx, req.GetCluster()); err != nil {
+		klog.Errorf("Failed to remove cluster %q: %v", req.GetCluster(), err)
+		return nil, err
+	}
+	return &resourcemonitors.RemoveResponse{}, nil
+}
+
+// Subscribe streams a notification to the subscriber each time NotifyChange is invoked, until the stream is closed.
+func (s *Server) Subscribe(_ *resourcemonitors.SubscribeRequest, stream resourcemonitors.ResourceReader_SubscribeServer) error {
+	// The channel is buffered, so that notifications are coalesced in case the subscriber is slow.
+	notifications := make(chan struct{}, 1)
+
+	s.subscribersMutex.Lock()
+	s.subscribers[notifications] = struct{}{}
+	s.subscribersMutex.Unlock()
+
+	defer func() {
+		s.subscribersMutex.Lock()
+		delete(s.subscribers, notifications)
+		s.subscribersMutex.Unlock()
+	}()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-s.stopping:
+			return nil
+		case <-notifications:
+			if err := stream.Send(&resourcemonitors.UpdateNotification{}); err != nil {
+				klog.Errorf("Failed to notify subscriber: %v", err)
+				return err
+			}
+		}
+	}
+}
+
+// NotifyChange notifies all subscribers that the offered resources may have changed.
+func (s *Server) NotifyChange() {
+	s.subscribersMutex.Lock()
+	defer s.subscribersMutex.Unlock()
+
+	for subscriber := range s.subscribers {
+		select {
+		case subscriber <- struct{}{}:
+		default:
+			// A notification is already pending for this subscriber.
+		}
+	}
+}
+
+// Subscribers returns the number of currently active subscribers.
+func (s *Server) Subscribers() int {
+	s.subscribersMutex.Lock()
+	defer s.subscribersMutex.Unlock()
+	return len(s.subscribers)
+}
+
+// Serve starts serving the resource_reader protocol on the given address, until the context is canceled.
+func (s *Server) Serve(ctx context.Context, address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %q: %w", address, err)
+	}
+	return s.ServeListener(ctx, listener)
+}
+
+// ServeListener starts serving the resource_reader protocol on the given listener, until the context is canceled.
+func (s *Server) ServeListener(ctx context.Context, listener net.Listener) error {
+	server := grpc.NewServer()
+	resourcemonitors.RegisterResourceReaderServer(server, s)
+
+	go func() {
+		<-ctx.Done()
+		klog.Info("Stopping the resource monitor server")
+		s.stopOnce.Do(func() { close(s.stopping) })
+		server.GracefulStop()
+	}()
+
+	klog.Infof("Resource monitor server listening on %q", listener.Addr())
+	return server.Serve(listener)
+}
@@ -0,0 +1,90 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	resourcemonitors "github.com/liqotech/liqo/pkg/liqo-controller-manager/resource-request-controller/resource-monitors"
+)
+
+var _ = Describe("Server", func() {
+	It("should return the resources computed by the policy", func() {
+		response, err := srv.ReadResources(ctx, &resourcemonitors.ReadRequest{Originator: "conformance-cluster-1"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.Resources).To(Equal(map[string]string{"cpu": "1"}))
+
+		response, err = srv.ReadResources(ctx, &resourcemonitors.ReadRequest{Originator: "other-cluster"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.Resources).To(Equal(map[string]string{"cpu": "2", "memory": "4Gi"}))
+	})
+
+	It("should not block when notifying changes multiple times", func() {
+		done := make(chan struct{})
+		go func() {
+			srv.NotifyChange()
+			srv.NotifyChange()
+			close(done)
+		}()
+		Eventually(done).Should(BeClosed())
+	})
+})
+
+var _ = Describe("StaticPolicy", func() {
+	var dir, path string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "static-policy-")
+		Expect(err).ToNot(HaveOccurred())
+		path = filepath.Join(dir, "config.yaml")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("should load the configuration from file", func() {
+		Expect(os.WriteFile(path, []byte("default:\n  cpu: 4\nclusters:\n  foo:\n    memory: 1Gi\n"), 0o600)).To(Succeed())
+
+		config, err := LoadStaticPolicyConfig(path)
+		Expect(err).ToNot(HaveOccurred())
+
+		policy := NewStaticPolicy(&StaticPolicyConfig{})
+		Expect(policy.Update(config)).To(BeTrue())
+		Expect(policy.Update(config)).To(BeFalse())
+
+		resources, err := policy.ReadResources(ctx, "foo")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resources.Memory().Equal(resource.MustParse("1Gi"))).To(BeTrue())
+		Expect(resources).ToNot(HaveKey(corev1.ResourceCPU))
+
+		resources, err = policy.ReadResources(ctx, "bar")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(resources.Cpu().Equal(resource.MustParse("4"))).To(BeTrue())
+	})
+
+	It("should reject invalid configurations", func() {
+		Expect(os.WriteFile(path, []byte("unknown: field\n"), 0o600)).To(Succeed())
+		_, err := LoadStaticPolicyConfig(path)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,89 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// StaticPolicyConfig is the configuration of a StaticPolicy.
+type StaticPolicyConfig struct {
+	// Default are the resources offered to the clusters not explicitly listed.
+	Default corev1.ResourceList `json:"default,omitempty"`
+	// Clusters are the resources offered to specific clusters, indexed by cluster ID.
+	Clusters map[string]corev1.ResourceList `json:"clusters,omitempty"`
+}
+
+// StaticPolicy is a policy offering a fixed amount of resources to each cluster.
+type StaticPolicy struct {
+	mutex  sync.RWMutex
+	config StaticPolicyConfig
+}
+
+var _ Policy = &StaticPolicy{}
+
+// NewStaticPolicy returns a new StaticPolicy with the given configuration.
+func NewStaticPolicy(config *StaticPolicyConfig) *StaticPolicy {
+	return &StaticPolicy{config: *config}
+}
+
+// LoadStaticPolicyConfig reads the configuration of a StaticPolicy from the given YAML file.
+func LoadStaticPolicyConfig(path string) (*StaticPolicyConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %q: %w", path, err)
+	}
+
+	var config StaticPolicyConfig
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to parse %q: %w", path, err)
+	}
+	return &config, nil
+}
+
+// Update replaces the configuration of the policy, and returns whether it changed.
+func (p *StaticPolicy) Update(config *StaticPolicyConfig) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if reflect.DeepEqual(p.config, *config) {
+		return false
+	}
+	p.config = *config
+	return true
+}
+
+// ReadResources returns the resources configured for the given cluster, or the default ones if not explicitly configured.
+func (p *StaticPolicy) ReadResources(_ context.Context, clusterID string) (corev1.ResourceList, error) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+
+	if resources, found := p.config.Clusters[clusterID]; found {
+		return resources.DeepCopy(), nil
+	}
+	return p.config.Default.DeepCopy(), nil
+}
+
+// RemoveCluster is a no-op, as no per-cluster data is retained.
+func (p *StaticPolicy) RemoveCluster(context.Context, string) error {
+	return nil
+}
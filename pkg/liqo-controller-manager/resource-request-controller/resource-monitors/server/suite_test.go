@@ -0,0 +1,71 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/liqotech/liqo/pkg/liqo-controller-manager/resource-request-controller/resource-monitors/server/conformance"
+)
+
+func TestServer(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Resource Monitor Server Suite")
+}
+
+var (
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	address string
+	policy  *StaticPolicy
+	srv     *Server
+)
+
+var _ = BeforeSuite(func() {
+	ctx, cancel = context.WithCancel(context.Background())
+
+	policy = NewStaticPolicy(&StaticPolicyConfig{
+		Default: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("4Gi")},
+		Clusters: map[string]corev1.ResourceList{
+			"conformance-cluster-1": {corev1.ResourceCPU: resource.MustParse("1")},
+		},
+	})
+	srv = New(policy)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).ToNot(HaveOccurred())
+	address = listener.Addr().String()
+
+	go func() {
+		defer GinkgoRecover()
+		Expect(srv.ServeListener(ctx, listener)).To(Succeed())
+	}()
+})
+
+var _ = AfterSuite(func() {
+	cancel()
+})
+
+var _ = conformance.Describe(func() conformance.Options {
+	return conformance.Options{Address: address, TriggerChange: srv.NotifyChange}
+})
@@ -0,0 +1,334 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcemonitors
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	ctrlcache "sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
+	"github.com/liqotech/liqo/pkg/consts"
+	sharingpolicyutils "github.com/liqotech/liqo/pkg/utils/sharingpolicy"
+)
+
+// SharingPolicyReader customizes the resources of a ResourceReader shared with each cluster,
+// according to the SharingPolicy selecting it (or scaling them by DefaultFactor, if none does).
+type SharingPolicyReader struct {
+	Provider      ResourceReader
+	DefaultFactor float32
+
+	client   client.Client
+	notifier ResourceUpdateNotifier
+
+	// clusters is the set of clusters resources are currently shared with, to account for their guaranteed resources.
+	// It is seeded from the existing ResourceRequests the first time it is needed, so that the resources guaranteed
+	// to a cluster are reserved even before the offer for that cluster is (re)computed, e.g., after a restart.
+	clusters       map[string]struct{}
+	clustersSeeded bool
+	clustersMutex  sync.RWMutex
+}
+
+// NewSharingPolicyReader creates a new SharingPolicyReader.
+func NewSharingPolicyReader(cl client.Client, provider ResourceReader, defaultFactor float32) *SharingPolicyReader {
+	return &SharingPolicyReader{
+		Provider:      provider,
+		DefaultFactor: defaultFactor,
+		client:        cl,
+		clusters:      map[string]struct{}{},
+	}
+}
+
+// SetupPolicyWatch configures the reader to notify a change whenever a SharingPolicy is created, updated or deleted.
+func (r *SharingPolicyReader) SetupPolicyWatch(ctx context.Context, informers ctrlcache.Informers) error {
+	informer, err := informers.GetInformer(ctx, &sharingv1alpha1.SharingPolicy{})
+	if err != nil {
+		klog.Errorf("Failed to retrieve the SharingPolicy informer: %v", err)
+		return err
+	}
+
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { r.notifyChange() },
+		UpdateFunc: func(interface{}, interface{}) { r.notifyChange() },
+		DeleteFunc: func(interface{}) { r.notifyChange() },
+	})
+	return nil
+}
+
+// Register sets an update notifier.
+func (r *SharingPolicyReader) Register(ctx context.Context, notifier ResourceUpdateNotifier) {
+	r.notifier = notifier
+	r.Provider.Register(ctx, notifier)
+}
+
+// ReadResources returns the resources shared with the given cluster, according to the SharingPolicy selecting it.
+func (r *SharingPolicyReader) ReadResources(ctx context.Context, clusterID string) corev1.ResourceList {
+	r.clustersMutex.Lock()
+	r.clusters[clusterID] = struct{}{}
+	r.clustersMutex.Unlock()
+
+	// In case of errors, fallback to the default sharing percentage (the error is already logged).
+	policies, _ := r.listPolicies(ctx)
+
+	policy := r.policyFor(ctx, clusterID, policies)
+	available := r.readAvailable(ctx, clusterID, policy)
+	return applySharingPolicy(available, policy, r.reservedFor(ctx, clusterID, policies), r.DefaultFactor)
+}
+
+// ReadNodePools returns the resources shared with the given cluster, grouped by node pool. The resources of each pool
+// are scaled proportionally, so that their sum corresponds to the ones returned by ReadResources.
+func (r *SharingPolicyReader) ReadNodePools(ctx context.Context, clusterID string) []sharingv1alpha1.NodePool {
+	reader, ok := r.Provider.(NodeAwareResourceReader)
+	if !ok {
+		return nil
+	}
+
+	pools := reader.ReadNodePoolsExcludingNodes(ctx, clusterID, r.excludedNodes(ctx, clusterID))
+	if len(pools) == 0 {
+		return pools
+	}
+	return scaleNodePools(pools, r.ReadResources(ctx, clusterID))
+}
+
+// ReadFragmentation returns the largest quantity of each resource shared with the given cluster on a single node,
+// along with the fragmentation histograms, capped to the resources returned by ReadResources.
+func (r *SharingPolicyReader) ReadFragmentation(ctx context.Context,
+	clusterID string) (corev1.ResourceList, []sharingv1alpha1.ResourceFragmentation) {
+	reader, ok := r.Provider.(NodeAwareResourceReader)
+	if !ok {
+		return nil, nil
+	}
+
+	largest, histograms := reader.ReadFragmentationExcludingNodes(ctx, clusterID, r.excludedNodes(ctx, clusterID))
+	return capFragmentation(largest, histograms, r.ReadResources(ctx, clusterID))
+}
+
+// excludedNodes returns the selector matching the nodes whose resources shall not be shared with the given cluster.
+func (r *SharingPolicyReader) excludedNodes(ctx context.Context, clusterID string) labels.Selector {
+	// In case of errors, fallback to the default sharing percentage (the error is already logged).
+	policies, _ := r.listPolicies(ctx)
+	policy := r.policyFor(ctx, clusterID, policies)
+	if policy == nil || policy.Spec.ExcludedNodeSelector == nil {
+		return labels.Nothing()
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(policy.Spec.ExcludedNodeSelector)
+	if err != nil {
+		klog.Errorf("SharingPolicy %q specifies an invalid excluded node selector: %v", policy.Name, err)
+		return labels.Nothing()
+	}
+	return selector
+}
+
+// RemoveClusterID removes the given clusterID from all internal structures and from the provider.
+func (r *SharingPolicyReader) RemoveClusterID(ctx context.Context, clusterID string) {
+	r.clustersMutex.Lock()
+	delete(r.clusters, clusterID)
+	r.clustersMutex.Unlock()
+	r.Provider.RemoveClusterID(ctx, clusterID)
+}
+
+// seedClusters adds to the set of clusters the ones which requested resources to the local cluster, as tracked
+// by the ResourceRequests replicated from them. It does nothing once the set has already been successfully seeded.
+func (r *SharingPolicyReader) seedClusters(ctx context.Context) {
+	r.clustersMutex.RLock()
+	seeded := r.clustersSeeded
+	r.clustersMutex.RUnlock()
+	if seeded {
+		return
+	}
+
+	var requests discoveryv1alpha1.ResourceRequestList
+	if err := r.client.List(ctx, &requests, client.HasLabels{consts.ReplicationOriginLabel},
+		client.MatchingLabels{consts.ReplicationStatusLabel: strconv.FormatBool(true)}); err != nil {
+		// The seeding is retried the next time the set is needed.
+		klog.Errorf("Failed to list the ResourceRequests to retrieve the clusters resources are shared with: %v", err)
+		return
+	}
+
+	r.clustersMutex.Lock()
+	defer r.clustersMutex.Unlock()
+	if r.clustersSeeded {
+		return
+	}
+	for i := range requests.Items {
+		request := &requests.Items[i]
+		// Skip the ResourceRequests being withdrawn, as the corresponding resources are going to be released.
+		if !request.DeletionTimestamp.IsZero() || request.Spec.WithdrawalTimestamp != nil {
+			continue
+		}
+		r.clusters[request.Spec.ClusterIdentity.ClusterID] = struct{}{}
+	}
+	r.clustersSeeded = true
+}
+
+func (r *SharingPolicyReader) notifyChange() {
+	if r.notifier != nil {
+		r.notifier.NotifyChange()
+	}
+}
+
+// readAvailable returns the resources available for the given cluster, excluding the nodes selected by the policy (if any).
+func (r *SharingPolicyReader) readAvailable(ctx context.Context, clusterID string,
+	policy *sharingv1alpha1.SharingPolicy) corev1.ResourceList {
+	if policy == nil || policy.Spec.ExcludedNodeSelector == nil {
+		return r.Provider.ReadResources(ctx, clusterID)
+	}
+
+	reader, ok := r.Provider.(NodeAwareResourceReader)
+	if !ok {
+		klog.Warningf("SharingPolicy %q excludes a set of nodes, but the resource provider does not support it", policy.Name)
+		return r.Provider.ReadResources(ctx, clusterID)
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(policy.Spec.ExcludedNodeSelector)
+	if err != nil {
+		klog.Errorf("SharingPolicy %q specifies an invalid excluded node selector: %v", policy.Name, err)
+		return r.Provider.ReadResources(ctx, clusterID)
+	}
+	return reader.ReadResourcesExcludingNodes(ctx, clusterID, selector)
+}
+
+// reservedFor returns the resources guaranteed to the clusters other than the given one, which cannot be shared with it.
+func (r *SharingPolicyReader) reservedFor(ctx context.Context, clusterID string,
+	policies []sharingv1alpha1.SharingPolicy) corev1.ResourceList {
+	r.seedClusters(ctx)
+
+	r.clustersMutex.RLock()
+	others := make([]string, 0, len(r.clusters))
+	for other := range r.clusters {
+		if other != clusterID {
+			others = append(others, other)
+		}
+	}
+	r.clustersMutex.RUnlock()
+
+	reserved := corev1.ResourceList{}
+	for _, other := range others {
+		policy := r.policyFor(ctx, other, policies)
+		if policy == nil {
+			continue
+		}
+		for i := range policy.Spec.Resources {
+			if guaranteed := policy.Spec.Resources[i].Guaranteed; guaranteed != nil {
+				addResources(reserved, corev1.ResourceList{policy.Spec.Resources[i].Name: guaranteed.DeepCopy()})
+			}
+		}
+	}
+	return reserved
+}
+
+// listPolicies returns the existing SharingPolicies, sorted by decreasing priority (and then by name).
+func (r *SharingPolicyReader) listPolicies(ctx context.Context) ([]sharingv1alpha1.SharingPolicy, error) {
+	return sharingpolicyutils.List(ctx, r.client)
+}
+
+// policyFor returns the policy with the highest priority selecting the given cluster, or nil if none does.
+func (r *SharingPolicyReader) policyFor(ctx context.Context, clusterID string,
+	policies []sharingv1alpha1.SharingPolicy) *sharingv1alpha1.SharingPolicy {
+	return sharingpolicyutils.ForCluster(ctx, r.client, clusterID, policies)
+}
+
+// applySharingPolicy computes the resources shared with a cluster, given the available ones, the policy selecting the cluster
+// (possibly nil), the resources reserved to the other clusters and the default scaling factor.
+func applySharingPolicy(available corev1.ResourceList, policy *sharingv1alpha1.SharingPolicy,
+	reserved corev1.ResourceList, defaultFactor float32) corev1.ResourceList {
+	rules := map[corev1.ResourceName]*sharingv1alpha1.ResourceSharingPolicy{}
+	if policy != nil {
+		for i := range policy.Spec.Resources {
+			rules[policy.Spec.Resources[i].Name] = &policy.Spec.Resources[i]
+		}
+	}
+
+	shared := corev1.ResourceList{}
+	for resourceName, quantity := range available {
+		value := quantity.DeepCopy()
+		if toReserve, found := reserved[resourceName]; found {
+			value.Sub(toReserve)
+			if value.Sign() == -1 {
+				value.Set(0)
+			}
+		}
+
+		factor := defaultFactor
+		rule := rules[resourceName]
+		if rule != nil && rule.Percentage != nil {
+			factor = float32(*rule.Percentage) / 100.
+		}
+		ScaleResources(resourceName, &value, factor)
+
+		if rule != nil && rule.Limit != nil && value.Cmp(*rule.Limit) > 0 {
+			value = rule.Limit.DeepCopy()
+		}
+		// The guaranteed resources are always shared, as long as they are available.
+		if rule != nil && rule.Guaranteed != nil && value.Cmp(*rule.Guaranteed) < 0 {
+			value = minQuantity(*rule.Guaranteed, quantity)
+		}
+		shared[resourceName] = value
+	}
+	return shared
+}
+
+// minQuantity returns the smaller of two quantities.
+func minQuantity(a, b resource.Quantity) resource.Quantity {
+	if a.Cmp(b) <= 0 {
+		return a.DeepCopy()
+	}
+	return b.DeepCopy()
+}
+
+// scaleNodePools scales the resources of each node pool proportionally, so that their sum corresponds to the shared ones.
+// The maximum quantities allocatable per node are capped accordingly.
+func scaleNodePools(pools []sharingv1alpha1.NodePool, shared corev1.ResourceList) []sharingv1alpha1.NodePool {
+	totals := corev1.ResourceList{}
+	for i := range pools {
+		addResources(totals, pools[i].Resources.DeepCopy())
+	}
+
+	scaled := make([]sharingv1alpha1.NodePool, len(pools))
+	for i := range pools {
+		pool := pools[i].DeepCopy()
+		for resourceName, quantity := range pool.Resources {
+			value, found := shared[resourceName]
+			total := totals[resourceName]
+			if !found || total.IsZero() {
+				pool.Resources[resourceName] = *resource.NewQuantity(0, quantity.Format)
+				continue
+			}
+
+			value = value.DeepCopy()
+			ScaleResources(resourceName, &value, float32(float64(quantity.MilliValue())/float64(total.MilliValue())))
+			pool.Resources[resourceName] = value
+		}
+
+		for resourceName, quantity := range pool.MaxAllocatablePerNode {
+			if available := pool.Resources[resourceName]; available.Cmp(quantity) < 0 {
+				pool.MaxAllocatablePerNode[resourceName] = available.DeepCopy()
+			}
+		}
+		scaled[i] = *pool
+	}
+	return scaled
+}
@@ -0,0 +1,214 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourcemonitors
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/discovery"
+)
+
+var _ = Describe("SharingPolicyReader", func() {
+	const (
+		clusterID      = "cluster-id"
+		otherClusterID = "other-cluster-id"
+	)
+
+	var (
+		ctx      context.Context
+		objects  []client.Object
+		reader   *SharingPolicyReader
+		provider FakeResourceReader
+	)
+
+	quantity := func(value string) *resource.Quantity {
+		q := resource.MustParse(value)
+		return &q
+	}
+
+	newPolicy := func(name string, priority int32, clusterIDs []string, selector *metav1.LabelSelector,
+		resources ...sharingv1alpha1.ResourceSharingPolicy) *sharingv1alpha1.SharingPolicy {
+		return &sharingv1alpha1.SharingPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: sharingv1alpha1.SharingPolicySpec{
+				ClusterIDs: clusterIDs, ClusterSelector: selector, Priority: priority, Resources: resources,
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		provider = FakeResourceReader{corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("10"),
+			corev1.ResourceMemory: resource.MustParse("10G"),
+		}}
+		objects = []client.Object{&discoveryv1alpha1.ForeignCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "remote", Labels: map[string]string{
+				discovery.ClusterIDLabel: clusterID, "tier": "gold"}},
+			Spec: discoveryv1alpha1.ForeignClusterSpec{
+				ClusterIdentity: discoveryv1alpha1.ClusterIdentity{ClusterID: clusterID, ClusterName: "remote"},
+			},
+		}}
+	})
+
+	JustBeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(discoveryv1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(sharingv1alpha1.AddToScheme(scheme)).To(Succeed())
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+		reader = NewSharingPolicyReader(cl, provider, .5)
+	})
+
+	When("no policy selects the cluster", func() {
+		BeforeEach(func() {
+			objects = append(objects, newPolicy("other", 0, []string{otherClusterID}, nil))
+		})
+
+		It("should apply the default sharing percentage", func() {
+			shared := reader.ReadResources(ctx, clusterID)
+			Expect(shared.Cpu().Equal(resource.MustParse("5"))).To(BeTrue())
+			Expect(shared.Memory().Equal(resource.MustParse("5G"))).To(BeTrue())
+		})
+	})
+
+	When("a policy selects the cluster by identifier", func() {
+		BeforeEach(func() {
+			objects = append(objects, newPolicy("by-id", 0, []string{clusterID}, nil,
+				sharingv1alpha1.ResourceSharingPolicy{Name: corev1.ResourceCPU, Percentage: pointer.Int32(80), Limit: quantity("6")},
+				sharingv1alpha1.ResourceSharingPolicy{Name: corev1.ResourceMemory, Percentage: pointer.Int32(20)},
+			))
+		})
+
+		It("should apply the corresponding percentages and limits", func() {
+			shared := reader.ReadResources(ctx, clusterID)
+			Expect(shared.Cpu().Equal(resource.MustParse("6"))).To(BeTrue())
+			Expect(shared.Memory().Equal(resource.MustParse("2G"))).To(BeTrue())
+		})
+	})
+
+	When("multiple policies select the cluster", func() {
+		BeforeEach(func() {
+			objects = append(objects,
+				newPolicy("low", 1, []string{clusterID}, nil,
+					sharingv1alpha1.ResourceSharingPolicy{Name: corev1.ResourceCPU, Percentage: pointer.Int32(10)}),
+				newPolicy("high", 10, nil, &metav1.LabelSelector{MatchLabels: map[string]string{"tier": "gold"}},
+					sharingv1alpha1.ResourceSharingPolicy{Name: corev1.ResourceCPU, Percentage: pointer.Int32(100)}),
+			)
+		})
+
+		It("should apply the one with the highest priority", func() {
+			shared := reader.ReadResources(ctx, clusterID)
+			Expect(shared.Cpu().Equal(resource.MustParse("10"))).To(BeTrue())
+			Expect(shared.Memory().Equal(resource.MustParse("5G"))).To(BeTrue())
+		})
+	})
+
+	When("resources are guaranteed to another cluster", func() {
+		BeforeEach(func() {
+			objects = append(objects, newPolicy("guaranteed", 0, []string{otherClusterID}, nil,
+				sharingv1alpha1.ResourceSharingPolicy{Name: corev1.ResourceCPU, Guaranteed: quantity("4")}))
+		})
+
+		It("should not share them with the other clusters", func() {
+			shared := reader.ReadResources(ctx, otherClusterID)
+			Expect(shared.Cpu().Equal(resource.MustParse("5"))).To(BeTrue())
+			shared = reader.ReadResources(ctx, clusterID)
+			Expect(shared.Cpu().Equal(resource.MustParse("3"))).To(BeTrue())
+
+			By("removing the cluster the resources are guaranteed to")
+			reader.RemoveClusterID(ctx, otherClusterID)
+			shared = reader.ReadResources(ctx, clusterID)
+			Expect(shared.Cpu().Equal(resource.MustParse("5"))).To(BeTrue())
+		})
+
+		When("the other cluster already requested resources", func() {
+			BeforeEach(func() {
+				objects = append(objects, &discoveryv1alpha1.ResourceRequest{
+					ObjectMeta: metav1.ObjectMeta{Name: "request", Namespace: "tenant", Labels: map[string]string{
+						consts.ReplicationOriginLabel: otherClusterID,
+						consts.ReplicationStatusLabel: "true",
+					}},
+					Spec: discoveryv1alpha1.ResourceRequestSpec{
+						ClusterIdentity: discoveryv1alpha1.ClusterIdentity{ClusterID: otherClusterID},
+					},
+				})
+			})
+
+			It("should not share them even if the resources for the other cluster have not been read yet", func() {
+				shared := reader.ReadResources(ctx, clusterID)
+				Expect(shared.Cpu().Equal(resource.MustParse("3"))).To(BeTrue())
+			})
+		})
+	})
+
+	DescribeTable("applySharingPolicy",
+		func(rule sharingv1alpha1.ResourceSharingPolicy, available, reserved, expected string) {
+			policy := newPolicy("policy", 0, nil, nil, rule)
+			shared := applySharingPolicy(corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(available)},
+				policy, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(reserved)}, .5)
+			Expect(shared.Cpu().Equal(resource.MustParse(expected))).To(BeTrue(), "got %v", shared.Cpu())
+		},
+		Entry("default percentage", sharingv1alpha1.ResourceSharingPolicy{Name: corev1.ResourceCPU}, "8", "0", "4"),
+		Entry("custom percentage", sharingv1alpha1.ResourceSharingPolicy{Name: corev1.ResourceCPU, Percentage: pointer.Int32(25)},
+			"8", "0", "2"),
+		Entry("limit", sharingv1alpha1.ResourceSharingPolicy{Name: corev1.ResourceCPU, Limit: quantity("1")}, "8", "0", "1"),
+		Entry("reservation", sharingv1alpha1.ResourceSharingPolicy{Name: corev1.ResourceCPU}, "8", "4", "2"),
+		Entry("reservation exceeding the available resources", sharingv1alpha1.ResourceSharingPolicy{Name: corev1.ResourceCPU},
+			"8", "10", "0"),
+		Entry("guaranteed", sharingv1alpha1.ResourceSharingPolicy{Name: corev1.ResourceCPU, Guaranteed: quantity("6")},
+			"8", "4", "6"),
+		Entry("guaranteed exceeding the available resources",
+			sharingv1alpha1.ResourceSharingPolicy{Name: corev1.ResourceCPU, Guaranteed: quantity("10")}, "8", "0", "8"),
+	)
+
+	It("scaleNodePools should scale the node pools proportionally to the shared resources", func() {
+		pools := []sharingv1alpha1.NodePool{
+			{
+				Name:                  "small",
+				Resources:             corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+				MaxAllocatablePerNode: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			},
+			{
+				Name:                  "large",
+				Resources:             corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("6")},
+				MaxAllocatablePerNode: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("6")},
+			},
+		}
+
+		scaled := scaleNodePools(pools, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")})
+		Expect(scaled).To(HaveLen(2))
+		Expect(scaled[0].Resources.Cpu().Equal(resource.MustParse("1"))).To(BeTrue(), "got %v", scaled[0].Resources.Cpu())
+		Expect(scaled[0].MaxAllocatablePerNode.Cpu().Equal(resource.MustParse("1"))).To(BeTrue())
+		Expect(scaled[1].Resources.Cpu().Equal(resource.MustParse("3"))).To(BeTrue(), "got %v", scaled[1].Resources.Cpu())
+		Expect(scaled[1].MaxAllocatablePerNode.Cpu().Equal(resource.MustParse("3"))).To(BeTrue())
+
+		// The original node pools shall not be mutated.
+		Expect(pools[1].Resources.Cpu().Equal(resource.MustParse("6"))).To(BeTrue())
+	})
+})
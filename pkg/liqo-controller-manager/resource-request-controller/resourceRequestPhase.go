@@ -30,7 +30,7 @@ const (
 // getResourceRequestPhase returns the phase associated with a resource request. It is:
 // * "Deleting" if the deletion timestamp is set or the related offer has been withdrawn.
 // * "Allow" if the incoming peering is enabled in the ForeignCluster or through the command line parameter.
-// * "Deny" in the other cases (no ForeignCluster, incoming peering disabled, ...)
+// * "Deny" in the other cases (no ForeignCluster, incoming peering disabled, identity revoked, ...)
 func (r *ResourceRequestReconciler) getResourceRequestPhase(
 	foreignCluster *discoveryv1alpha1.ForeignCluster,
 	resourceRequest *discoveryv1alpha1.ResourceRequest) (resourceRequestPhase, error) {
@@ -38,6 +38,10 @@ func (r *ResourceRequestReconciler) getResourceRequestPhase(
 		return deletingResourceRequestPhase, nil
 	}
 
+	if foreignCluster.Spec.IdentityRevoked {
+		return denyResourceRequestPhase, nil
+	}
+
 	if foreignclusterutils.AllowIncomingPeering(foreignCluster, r.EnableIncomingPeering) {
 		return allowResourceRequestPhase, nil
 	}
@@ -42,6 +42,7 @@ var _ = Describe("Resource Phase", func() {
 
 		type getResourceRequestPhaseTestcase struct {
 			incomingPeeringEnabled discoveryv1alpha1.PeeringEnabledType
+			identityRevoked        bool
 			resourceRequest        *discoveryv1alpha1.ResourceRequest
 			expectedResult         OmegaMatcher
 		}
@@ -60,6 +61,7 @@ var _ = Describe("Resource Phase", func() {
 						OutgoingPeeringEnabled: discoveryv1alpha1.PeeringEnabledAuto,
 						IncomingPeeringEnabled: c.incomingPeeringEnabled,
 						InsecureSkipTLSVerify:  pointer.BoolPtr(true),
+						IdentityRevoked:        c.identityRevoked,
 					},
 				}
 
@@ -119,6 +121,19 @@ var _ = Describe("Resource Phase", func() {
 				},
 				expectedResult: Equal(denyResourceRequestPhase),
 			}),
+
+			Entry("resource request from a cluster with revoked identity", getResourceRequestPhaseTestcase{
+				incomingPeeringEnabled: discoveryv1alpha1.PeeringEnabledYes,
+				identityRevoked:        true,
+				resourceRequest: &discoveryv1alpha1.ResourceRequest{
+					Spec: discoveryv1alpha1.ResourceRequestSpec{
+						ClusterIdentity: discoveryv1alpha1.ClusterIdentity{
+							ClusterID: clusterID,
+						},
+					},
+				},
+				expectedResult: Equal(denyResourceRequestPhase),
+			}),
 		)
 	})
 
@@ -27,6 +27,7 @@ import (
 
 	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
 	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
+	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
 	"github.com/liqotech/liqo/internal/crdReplicator/reflection"
 )
 
@@ -41,12 +42,15 @@ type ResourceRequestReconciler struct {
 
 // +kubebuilder:rbac:groups=sharing.liqo.io,resources=resourceoffers,verbs=get;list;watch;create;update;patch;
 // +kubebuilder:rbac:groups=sharing.liqo.io,resources=resourceoffers/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=sharing.liqo.io,resources=sharingpolicies,verbs=get;list;watch
 // +kubebuilder:rbac:groups=discovery.liqo.io,resources=resourcerequests,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=discovery.liqo.io,resources=resourcerequests/status;resourcerequests/finalizers,verbs=get;update;patch
 // +kubebuilder:rbac:groups=discovery.liqo.io,resources=foreignclusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=discovery.liqo.io,resources=foreignclusters/status;foreignclusters/finalizers,verbs=get;update;patch
 // +kubebuilder:rbac:groups=metrics.liqo.io,resources=scrape;scrape/metrics,verbs=get
 
+// +kubebuilder:rbac:groups=virtualkubelet.liqo.io,resources=shadowpods,verbs=get;list;watch
+
 // +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
 
 // Reconcile is the main function of the controller which reconciles ResourceRequest resources.
@@ -101,7 +105,7 @@ func (r *ResourceRequestReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 			klog.Errorf("%s -> Error creating ClusterRole: %s", remoteCluster.ClusterName, err)
 			return ctrl.Result{}, err
 		}
-		if err = r.ensureClusterRoleBinding(ctx, remoteCluster); err != nil {
+		if err = r.ensureClusterRoleBinding(ctx, remoteCluster, resourceRequest.Namespace); err != nil {
 			klog.Errorf("%s -> Error creating ClusterRoleBinding: %s", remoteCluster.ClusterName, err)
 			return ctrl.Result{}, err
 		}
@@ -171,5 +175,6 @@ func (r *ResourceRequestReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Watches(&source.Kind{Type: &discoveryv1alpha1.ForeignCluster{}}, getForeignClusterEventHandler(
 			r.Client,
 		)).
+		Watches(&source.Kind{Type: &vkv1alpha1.ShadowPod{}}, getShadowPodEventHandler(r.Client)).
 		Complete(r)
 }
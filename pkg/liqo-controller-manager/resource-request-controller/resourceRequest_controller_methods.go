@@ -28,6 +28,7 @@ import (
 
 	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
 	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
+	tenantnamespace "github.com/liqotech/liqo/pkg/tenantNamespace"
 )
 
 func (r *ResourceRequestReconciler) ensureClusterRole(ctx context.Context,
@@ -53,7 +54,7 @@ func (r *ResourceRequestReconciler) ensureClusterRole(ctx context.Context,
 }
 
 func (r *ResourceRequestReconciler) ensureClusterRoleBinding(ctx context.Context,
-	remoteClusterIdentity discoveryv1alpha1.ClusterIdentity) error {
+	remoteClusterIdentity discoveryv1alpha1.ClusterIdentity, tenantNamespace string) error {
 	clusterRoleBinding := &rbacv1.ClusterRoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: fmt.Sprintf("liqo-tenant-remote-%s", GetTenantName(remoteClusterIdentity)),
@@ -66,12 +67,7 @@ func (r *ResourceRequestReconciler) ensureClusterRoleBinding(ctx context.Context
 			Kind:     "ClusterRole",
 			Name:     fmt.Sprintf("liqo-tenant-remote-%s", GetTenantName(remoteClusterIdentity)),
 		}
-		clusterRoleBinding.Subjects = []rbacv1.Subject{
-			{
-				Kind: rbacv1.UserKind,
-				Name: remoteClusterIdentity.ClusterID,
-			},
-		}
+		clusterRoleBinding.Subjects = tenantnamespace.RemoteClusterSubjects(remoteClusterIdentity.ClusterID, tenantNamespace)
 		return nil
 	})
 	return err
@@ -34,11 +34,14 @@ import (
 
 	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
 	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
+	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
 	"github.com/liqotech/liqo/pkg/consts"
 	"github.com/liqotech/liqo/pkg/discovery"
 	resourcemonitors "github.com/liqotech/liqo/pkg/liqo-controller-manager/resource-request-controller/resource-monitors"
+	tenantnamespace "github.com/liqotech/liqo/pkg/tenantNamespace"
 	"github.com/liqotech/liqo/pkg/utils"
 	foreignclusterutils "github.com/liqotech/liqo/pkg/utils/foreignCluster"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
 )
 
 const (
@@ -224,12 +227,18 @@ var _ = Describe("ResourceRequest Operator", func() {
 				}, &clusterRoleBinding)
 			}, timeout, interval).ShouldNot(HaveOccurred())
 
-			Expect(clusterRoleBinding.Subjects).To(HaveLen(1))
-			Expect(clusterRoleBinding.Subjects[0]).To(Equal(rbacv1.Subject{
-				APIGroup: "rbac.authorization.k8s.io",
-				Kind:     rbacv1.UserKind,
-				Name:     cluster1.ClusterID,
-			}))
+			Expect(clusterRoleBinding.Subjects).To(ConsistOf(
+				rbacv1.Subject{
+					APIGroup: "rbac.authorization.k8s.io",
+					Kind:     rbacv1.UserKind,
+					Name:     cluster1.ClusterID,
+				},
+				rbacv1.Subject{
+					Kind:      rbacv1.ServiceAccountKind,
+					Name:      tenantnamespace.RemoteIdentityServiceAccountName,
+					Namespace: ResourcesNamespace,
+				},
+			))
 			Expect(clusterRoleBinding.RoleRef).To(Equal(rbacv1.RoleRef{
 				APIGroup: "rbac.authorization.k8s.io",
 				Kind:     "ClusterRole",
@@ -255,6 +264,10 @@ var _ = Describe("ResourceRequest Operator", func() {
 			Expect(createdResourceOffer.Labels[discovery.ClusterIDLabel]).Should(Equal(createdResourceRequest.Spec.ClusterIdentity.ClusterID))
 			Expect(createdResourceOffer.Labels[consts.ReplicationRequestedLabel]).Should(Equal("true"))
 			Expect(createdResourceOffer.Labels[consts.ReplicationDestinationLabel]).Should(Equal(createdResourceRequest.Spec.ClusterIdentity.ClusterID))
+			Expect(createdResourceOffer.Spec.Prices).Should(HaveLen(len(offerPrices)))
+			for name, price := range offerPrices {
+				Expect(createdResourceOffer.Spec.Prices[name].Equal(price)).Should(BeTrue())
+			}
 			By("Checking OwnerReference for Garbage Collector")
 			Expect(createdResourceOffer.GetOwnerReferences()).ShouldNot(HaveLen(0))
 			Expect(createdResourceOffer.GetOwnerReferences()).Should(ContainElement(MatchFields(IgnoreExtras, Fields{
@@ -348,6 +361,46 @@ var _ = Describe("ResourceRequest Operator", func() {
 					&rr))
 			}, timeout, interval).Should(BeTrue())
 		})
+
+		It("Should report the resources used by the ShadowPods", func() {
+			By("Checking Offer creation")
+			var offers sharingv1alpha1.ResourceOfferList
+			Eventually(func() []sharingv1alpha1.ResourceOffer {
+				Expect(k8sClient.List(ctx, &offers, client.InNamespace(ResourcesNamespace))).To(Succeed())
+				return offers.Items
+			}, timeout, interval).Should(HaveLen(1))
+			offerName = types.NamespacedName{Name: offers.Items[0].Name, Namespace: ResourcesNamespace}
+
+			By("Creating a ShadowPod originated from the remote cluster")
+			shadowPod := &vkv1alpha1.ShadowPod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "shadow-pod", Namespace: ResourcesNamespace,
+					Labels: map[string]string{forge.LiqoOriginClusterIDKey: cluster1.ClusterID},
+				},
+				Spec: vkv1alpha1.ShadowPodSpec{Pod: corev1.PodSpec{Containers: []corev1.Container{{
+					Name: "container", Image: "nginx",
+					Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse("500m"),
+						corev1.ResourceMemory: resource.MustParse("1Gi"),
+					}},
+				}}}},
+			}
+			Expect(k8sClient.Create(ctx, shadowPod)).To(Succeed())
+
+			var resourceOffer sharingv1alpha1.ResourceOffer
+			Eventually(func() bool {
+				Expect(k8sClient.Get(ctx, offerName, &resourceOffer)).To(Succeed())
+				return resourceOffer.Status.Used.Cpu().Equal(resource.MustParse("500m")) &&
+					resourceOffer.Status.Used.Memory().Equal(resource.MustParse("1Gi"))
+			}, timeout, interval).Should(BeTrue())
+
+			By("Deleting the ShadowPod")
+			Expect(k8sClient.Delete(ctx, shadowPod)).To(Succeed())
+			Eventually(func() corev1.ResourceList {
+				Expect(k8sClient.Get(ctx, offerName, &resourceOffer)).To(Succeed())
+				return resourceOffer.Status.Used
+			}, timeout, interval).Should(BeEmpty())
+		})
 	})
 
 	When("Creating a new ResourceRequest", func() {
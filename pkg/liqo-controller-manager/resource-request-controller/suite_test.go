@@ -24,6 +24,7 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -34,6 +35,7 @@ import (
 
 	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
 	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
+	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
 	resourcemonitors "github.com/liqotech/liqo/pkg/liqo-controller-manager/resource-request-controller/resource-monitors"
 	liqoerrors "github.com/liqotech/liqo/pkg/utils/errors"
 )
@@ -47,6 +49,7 @@ var (
 	monitor       *resourcemonitors.LocalResourceMonitor
 	scaledMonitor *resourcemonitors.ResourceScaler
 	updater       *OfferUpdater
+	offerPrices   corev1.ResourceList
 	ctx           context.Context
 	cancel        context.CancelFunc
 	group         sync.WaitGroup
@@ -76,6 +79,8 @@ func createCluster() {
 	Expect(err).NotTo(HaveOccurred())
 	err = sharingv1alpha1.AddToScheme(scheme.Scheme)
 	Expect(err).NotTo(HaveOccurred())
+	err = vkv1alpha1.AddToScheme(scheme.Scheme)
+	Expect(err).NotTo(HaveOccurred())
 
 	By("Starting a new manager")
 	k8sManager, err := ctrl.NewManager(cfg, ctrl.Options{
@@ -97,9 +102,10 @@ func createCluster() {
 	// Initializing a new notifier and adding it to the manager.
 	localStorageClassName := ""
 	enableStorage := true
-	monitor = resourcemonitors.NewLocalMonitor(ctx, clientset, 5*time.Second)
+	monitor = resourcemonitors.NewLocalMonitor(ctx, clientset, 5*time.Second, "")
 	scaledMonitor = &resourcemonitors.ResourceScaler{Provider: monitor, Factor: DefaultScaleFactor}
-	updater = NewOfferUpdater(ctx, k8sClient, homeCluster, nil, scaledMonitor, 5, localStorageClassName, enableStorage)
+	offerPrices = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("500m")}
+	updater = NewOfferUpdater(ctx, k8sClient, homeCluster, nil, offerPrices, scaledMonitor, 5, localStorageClassName, enableStorage)
 
 	Expect(k8sManager.Add(updater)).To(Succeed())
 
@@ -0,0 +1,134 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resourceoffercontroller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
+)
+
+const (
+	// reasonAutoAcceptDisabled is the reason set when the automatic acceptance of offers is disabled.
+	reasonAutoAcceptDisabled = "AutoAcceptDisabled"
+	// reasonPolicySatisfied is the reason set when the offer satisfies the acceptance policy.
+	reasonPolicySatisfied = "AcceptancePolicySatisfied"
+	// reasonPriceTooHigh is the reason set when the price of some resource exceeds the maximum accepted one.
+	reasonPriceTooHigh = "PriceTooHigh"
+	// reasonPriceNotAvailable is the reason set when the offer does not specify the price of some resource
+	// constrained by the acceptance policy.
+	reasonPriceNotAvailable = "PriceNotAvailable"
+	// reasonMissingLabels is the reason set when the offer does not include some of the required labels.
+	reasonMissingLabels = "MissingLabels"
+	// reasonInsufficientQuota is the reason set when the offer does not include the minimum required resources.
+	reasonInsufficientQuota = "InsufficientQuota"
+)
+
+// isPolicyRefusalReason returns whether the given reason corresponds to a refusal decided by the acceptance policy.
+func isPolicyRefusalReason(reason string) bool {
+	switch reason {
+	case reasonMissingLabels, reasonInsufficientQuota, reasonPriceTooHigh:
+		return true
+	default:
+		return false
+	}
+}
+
+// AcceptancePolicy defines the criteria a ResourceOffer shall satisfy to be automatically accepted.
+// Offers violating any of the criteria are refused, while offers missing the price of a constrained
+// resource require a manual action, as their cost cannot be evaluated.
+type AcceptancePolicy struct {
+	// MaxPrices is the maximum accepted price for each resource (e.g., per CPU and per GiB of memory),
+	// according to the same units used by the providers to publish their prices.
+	MaxPrices corev1.ResourceList
+	// RequiredLabels is the set of labels the offer shall include (with the given values).
+	RequiredLabels map[string]string
+	// MinQuota is the minimum quantity of each resource the offer shall include.
+	MinQuota corev1.ResourceList
+}
+
+// evaluate checks whether the given ResourceOffer satisfies the acceptance policy,
+// returning the resulting phase, together with the corresponding reason and message.
+func (p *AcceptancePolicy) evaluate(resourceOffer *sharingv1alpha1.ResourceOffer) (phase sharingv1alpha1.OfferPhase, reason, message string) {
+	if p == nil {
+		return sharingv1alpha1.ResourceOfferAccepted, reasonPolicySatisfied, "The offer has been automatically accepted"
+	}
+
+	if missing := p.missingLabels(resourceOffer.Spec.Labels); len(missing) > 0 {
+		return sharingv1alpha1.ResourceOfferRefused, reasonMissingLabels,
+			fmt.Sprintf("The offer does not include the required labels: %v", strings.Join(missing, ", "))
+	}
+
+	if insufficient := p.insufficientResources(resourceOffer.Spec.ResourceQuota.Hard); len(insufficient) > 0 {
+		return sharingv1alpha1.ResourceOfferRefused, reasonInsufficientQuota,
+			fmt.Sprintf("The offer does not include the minimum quantity of resources: %v", strings.Join(insufficient, ", "))
+	}
+
+	tooHigh, unavailable := p.checkPrices(resourceOffer.Spec.Prices)
+	if len(tooHigh) > 0 {
+		return sharingv1alpha1.ResourceOfferRefused, reasonPriceTooHigh,
+			fmt.Sprintf("The price of some resources exceeds the maximum accepted one: %v", strings.Join(tooHigh, ", "))
+	}
+	if len(unavailable) > 0 {
+		return sharingv1alpha1.ResourceOfferManualActionRequired, reasonPriceNotAvailable,
+			fmt.Sprintf("The offer does not specify the price of some resources: %v", strings.Join(unavailable, ", "))
+	}
+
+	return sharingv1alpha1.ResourceOfferAccepted, reasonPolicySatisfied, "The offer satisfies the acceptance policy"
+}
+
+// missingLabels returns the sorted list of required labels not matched by the given ones.
+func (p *AcceptancePolicy) missingLabels(labels map[string]string) []string {
+	var missing []string
+	for key, value := range p.RequiredLabels {
+		if current, found := labels[key]; !found || current != value {
+			missing = append(missing, fmt.Sprintf("%v=%v", key, value))
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// insufficientResources returns the sorted list of resources whose offered quantity is below the minimum one.
+func (p *AcceptancePolicy) insufficientResources(offered corev1.ResourceList) []string {
+	var insufficient []string
+	for name, minimum := range p.MinQuota {
+		if quantity, found := offered[name]; !found || quantity.Cmp(minimum) < 0 {
+			insufficient = append(insufficient, fmt.Sprintf("%v (minimum %v)", name, minimum.String()))
+		}
+	}
+	sort.Strings(insufficient)
+	return insufficient
+}
+
+// checkPrices returns the sorted lists of resources whose price exceeds the maximum one, and of those whose price is not specified.
+func (p *AcceptancePolicy) checkPrices(prices corev1.ResourceList) (tooHigh, unavailable []string) {
+	for name, maximum := range p.MaxPrices {
+		price, found := prices[name]
+		switch {
+		case !found:
+			unavailable = append(unavailable, name.String())
+		case price.Cmp(maximum) > 0:
+			tooHigh = append(tooHigh, fmt.Sprintf("%v (%v, maximum %v)", name, price.String(), maximum.String()))
+		}
+	}
+	sort.Strings(tooHigh)
+	sort.Strings(unavailable)
+	return tooHigh, unavailable
+}
@@ -28,7 +28,7 @@ func NewResourceOfferController(
 	mgr manager.Manager, cluster discoveryv1alpha1.ClusterIdentity,
 	resyncPeriod time.Duration, liqoNamespace string,
 	virtualKubeletOpts *forge.VirtualKubeletOpts,
-	disableAutoAccept bool) *ResourceOfferReconciler {
+	disableAutoAccept bool, acceptancePolicy *AcceptancePolicy) *ResourceOfferReconciler {
 	return &ResourceOfferReconciler{
 		Client: mgr.GetClient(),
 		Scheme: mgr.GetScheme(),
@@ -40,6 +40,7 @@ func NewResourceOfferController(
 
 		virtualKubeletOpts: virtualKubeletOpts,
 		disableAutoAccept:  disableAutoAccept,
+		acceptancePolicy:   acceptancePolicy,
 
 		resyncPeriod: resyncPeriod,
 	}
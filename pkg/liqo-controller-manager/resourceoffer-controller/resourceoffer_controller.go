@@ -58,6 +58,7 @@ type ResourceOfferReconciler struct {
 
 	virtualKubeletOpts *forge.VirtualKubeletOpts
 	disableAutoAccept  bool
+	acceptancePolicy   *AcceptancePolicy
 
 	resyncPeriod time.Duration
 }
@@ -66,6 +67,7 @@ type ResourceOfferReconciler struct {
 //+kubebuilder:rbac:groups=sharing.liqo.io,resources=resourceoffers/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=sharing.liqo.io,resources=resourceoffers/finalizers,verbs=get;update;patch
 //+kubebuilder:rbac:groups=discovery.liqo.io,resources=resourcerequests/finalizers,verbs=get;update;patch
+//+kubebuilder:rbac:groups=discovery.liqo.io,resources=foreignclusters,verbs=get;list;watch;update;patch
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=clusterrolebindings,verbs=get;list;watch;create;update;patch;delete;deletecollection
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;create;update
@@ -19,14 +19,17 @@ import (
 	"fmt"
 
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
 	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
 	"github.com/liqotech/liqo/pkg/consts"
 	foreigncluster "github.com/liqotech/liqo/pkg/utils/foreignCluster"
+	"github.com/liqotech/liqo/pkg/virtualKubelet"
 	"github.com/liqotech/liqo/pkg/vkMachinery/forge"
 )
 
@@ -49,31 +52,78 @@ func (r *ResourceOfferReconciler) setControllerReference(
 	return nil
 }
 
-// setResourceOfferPhase checks if the resource request can be accepted and set its phase accordingly.
+// setResourceOfferPhase checks if the resource offer can be accepted, according to the acceptance policy,
+// and sets its phase accordingly, recording the reason of the decision. Offers already decided are evaluated
+// again in case their spec changed (see reevaluateResourceOfferPhase).
 func (r *ResourceOfferReconciler) setResourceOfferPhase(resourceOffer *sharingv1alpha1.ResourceOffer) {
-	// we want only to care about resource offers with a pending status
+	// the generation is zero for the offers evaluated before it was tracked, which are not considered as changed.
+	specChanged := resourceOffer.Status.ObservedGeneration != 0 && resourceOffer.Status.ObservedGeneration != resourceOffer.Generation
+	resourceOffer.Status.ObservedGeneration = resourceOffer.Generation
+
 	if resourceOffer.Status.Phase != "" && resourceOffer.Status.Phase != sharingv1alpha1.ResourceOfferPending {
+		if specChanged && !r.disableAutoAccept {
+			r.reevaluateResourceOfferPhase(resourceOffer)
+		}
 		return
 	}
 
 	if r.disableAutoAccept {
 		resourceOffer.Status.Phase = sharingv1alpha1.ResourceOfferManualActionRequired
+		resourceOffer.Status.Reason = reasonAutoAcceptDisabled
+		resourceOffer.Status.Message = "The automatic acceptance of offers is disabled"
 	} else {
-		resourceOffer.Status.Phase = sharingv1alpha1.ResourceOfferAccepted
+		resourceOffer.Status.Phase, resourceOffer.Status.Reason, resourceOffer.Status.Message =
+			r.acceptancePolicy.evaluate(resourceOffer)
+	}
+
+	klog.Infof("[%v] ResourceOffer %v set to %v: %v", resourceOffer.Spec.ClusterID,
+		klog.KObj(resourceOffer), resourceOffer.Status.Phase, resourceOffer.Status.Message)
+	eventType := corev1.EventTypeNormal
+	if resourceOffer.Status.Phase != sharingv1alpha1.ResourceOfferAccepted {
+		eventType = corev1.EventTypeWarning
 	}
+	r.eventsRecorder.Event(resourceOffer, eventType, resourceOffer.Status.Reason, resourceOffer.Status.Message)
 }
 
-// checkVirtualKubeletDeployment checks the existence of the VirtualKubelet Deployment
-// and sets its status in the ResourceOffer accordingly.
+// reevaluateResourceOfferPhase evaluates again an offer already decided, following the modification of its spec.
+// Offers no longer satisfying the acceptance policy are refused, while offers previously refused by the acceptance policy
+// are decided again (e.g., accepted once the offered resources reach the minimum quota). Otherwise, the current phase is
+// preserved, not to override the decisions possibly taken manually (e.g., the acceptance of an offer not specifying some prices).
+func (r *ResourceOfferReconciler) reevaluateResourceOfferPhase(resourceOffer *sharingv1alpha1.ResourceOffer) {
+	phase, reason, message := r.acceptancePolicy.evaluate(resourceOffer)
+
+	current := &resourceOffer.Status
+	switch {
+	case phase == sharingv1alpha1.ResourceOfferRefused && current.Phase != sharingv1alpha1.ResourceOfferRefused:
+		// the offer no longer satisfies the acceptance policy.
+	case current.Phase == sharingv1alpha1.ResourceOfferRefused && isPolicyRefusalReason(current.Reason) &&
+		(phase != current.Phase || reason != current.Reason):
+		// the offer had been refused by the acceptance policy, whose outcome changed.
+	default:
+		return
+	}
+
+	current.Phase, current.Reason, current.Message = phase, reason, message
+	klog.Infof("[%v] ResourceOffer %v evaluated again following a spec change, set to %v: %v", resourceOffer.Spec.ClusterID,
+		klog.KObj(resourceOffer), current.Phase, current.Message)
+	eventType := corev1.EventTypeNormal
+	if resourceOffer.Status.Phase != sharingv1alpha1.ResourceOfferAccepted {
+		eventType = corev1.EventTypeWarning
+	}
+	r.eventsRecorder.Event(resourceOffer, eventType, resourceOffer.Status.Reason, resourceOffer.Status.Message)
+}
+
+// checkVirtualKubeletDeployment checks the existence of the VirtualKubelet Deployments
+// and sets their status in the ResourceOffer accordingly.
 func (r *ResourceOfferReconciler) checkVirtualKubeletDeployment(
 	ctx context.Context, resourceOffer *sharingv1alpha1.ResourceOffer) error {
-	virtualKubeletDeployment, err := r.getVirtualKubeletDeployment(ctx, resourceOffer)
+	virtualKubeletDeployments, err := r.getVirtualKubeletDeployments(ctx, resourceOffer)
 	if err != nil {
 		klog.Error(err)
 		return err
 	}
 
-	if virtualKubeletDeployment == nil {
+	if len(virtualKubeletDeployments) == 0 {
 		resourceOffer.Status.VirtualKubeletStatus = sharingv1alpha1.VirtualKubeletStatusNone
 	} else if resourceOffer.Status.VirtualKubeletStatus != sharingv1alpha1.VirtualKubeletStatusDeleting {
 		// there is a virtual kubelet deployment and the phase is not deleting
@@ -82,7 +132,8 @@ func (r *ResourceOfferReconciler) checkVirtualKubeletDeployment(
 	return nil
 }
 
-// createVirtualKubeletDeployment creates the VirtualKubelet Deployment.
+// createVirtualKubeletDeployment creates the VirtualKubelet Deployments (one for each node pool, if any),
+// and deletes the ones associated with node pools no longer offered, once the corresponding node has been drained.
 func (r *ResourceOfferReconciler) createVirtualKubeletDeployment(
 	ctx context.Context, resourceOffer *sharingv1alpha1.ResourceOffer) error {
 	namespace := resourceOffer.Namespace
@@ -116,35 +167,50 @@ func (r *ResourceOfferReconciler) createVirtualKubeletDeployment(
 	klog.V(5).Infof("[%v] ClusterRoleBinding %s reconciled: %s",
 		remoteClusterIdentity.ClusterName, vkClusterRoleBinding.Name, op)
 
-	// forge the virtual Kubelet
-	vkDeployment, err := forge.VirtualKubeletDeployment(
-		r.cluster, remoteClusterIdentity, namespace, r.liqoNamespace,
-		r.virtualKubeletOpts, resourceOffer)
+	primaryPool, err := r.pinPrimaryNodePool(ctx, remoteCluster, resourceOffer)
 	if err != nil {
 		klog.Error(err)
 		return err
 	}
 
-	op, err = controllerutil.CreateOrUpdate(ctx, r.Client, vkDeployment, func() error {
-		// set the "owner" object name in the annotation to be able to reconcile deployment changes
-		if vkDeployment.Annotations == nil {
-			vkDeployment.Annotations = map[string]string{}
-		}
-		vkDeployment.Annotations[resourceOfferAnnotation] = resourceOffer.GetName()
-		return nil
-	})
+	// forge the virtual Kubelets
+	vkDeployments, err := forge.VirtualKubeletDeployments(
+		r.cluster, remoteClusterIdentity, namespace, r.liqoNamespace,
+		r.virtualKubeletOpts, resourceOffer, primaryPool)
 	if err != nil {
 		klog.Error(err)
 		return err
 	}
-	klog.V(5).Infof("[%v] Deployment %s/%s reconciled: %s",
-		remoteClusterIdentity.ClusterName, vkDeployment.Namespace, vkDeployment.Name, op)
 
-	if op == controllerutil.OperationResultCreated {
-		msg := fmt.Sprintf("[%v] Launching virtual-kubelet in namespace %v",
-			remoteClusterIdentity.ClusterName, namespace)
-		klog.Info(msg)
-		r.eventsRecorder.Event(resourceOffer, "Normal", "VkCreated", msg)
+	desired := make(map[string]struct{}, len(vkDeployments))
+	for _, vkDeployment := range vkDeployments {
+		desired[vkDeployment.Name] = struct{}{}
+		op, err = controllerutil.CreateOrUpdate(ctx, r.Client, vkDeployment, func() error {
+			// set the "owner" object name in the annotation to be able to reconcile deployment changes
+			if vkDeployment.Annotations == nil {
+				vkDeployment.Annotations = map[string]string{}
+			}
+			vkDeployment.Annotations[resourceOfferAnnotation] = resourceOffer.GetName()
+			return nil
+		})
+		if err != nil {
+			klog.Error(err)
+			return err
+		}
+		klog.V(5).Infof("[%v] Deployment %s/%s reconciled: %s",
+			remoteClusterIdentity.ClusterName, vkDeployment.Namespace, vkDeployment.Name, op)
+
+		if op == controllerutil.OperationResultCreated {
+			msg := fmt.Sprintf("[%v] Launching virtual-kubelet %v in namespace %v",
+				remoteClusterIdentity.ClusterName, vkDeployment.Name, namespace)
+			klog.Info(msg)
+			r.eventsRecorder.Event(resourceOffer, "Normal", "VkCreated", msg)
+		}
+	}
+
+	if err = r.deleteStaleVirtualKubeletDeployments(ctx, resourceOffer, desired); err != nil {
+		klog.Error(err)
+		return err
 	}
 
 	controllerutil.AddFinalizer(resourceOffer, consts.VirtualKubeletFinalizer)
@@ -152,27 +218,90 @@ func (r *ResourceOfferReconciler) createVirtualKubeletDeployment(
 	return nil
 }
 
-// deleteVirtualKubeletDeployment deletes the VirtualKubelet Deployment.
-func (r *ResourceOfferReconciler) deleteVirtualKubeletDeployment(
-	ctx context.Context, resourceOffer *sharingv1alpha1.ResourceOffer) error {
-	virtualKubeletDeployment, err := r.getVirtualKubeletDeployment(ctx, resourceOffer)
+// pinPrimaryNodePool returns the node pool whose virtual kubelet is in charge of reflecting all resources, and pins it
+// through an annotation on the ForeignCluster. This way, it does not change as long as the pool is offered, regardless
+// of the other pools appearing or disappearing from the ResourceOffer.
+func (r *ResourceOfferReconciler) pinPrimaryNodePool(ctx context.Context, foreignCluster *discoveryv1alpha1.ForeignCluster,
+	resourceOffer *sharingv1alpha1.ResourceOffer) (string, error) {
+	pinned := foreignCluster.GetAnnotations()[consts.PrimaryNodePoolAnnotation]
+	primary := forge.PrimaryNodePool(resourceOffer, pinned)
+	if primary == "" || primary == pinned {
+		return primary, nil
+	}
+
+	original := foreignCluster.DeepCopy()
+	if foreignCluster.Annotations == nil {
+		foreignCluster.Annotations = map[string]string{}
+	}
+	foreignCluster.Annotations[consts.PrimaryNodePoolAnnotation] = primary
+	if err := r.Client.Patch(ctx, foreignCluster, client.MergeFrom(original)); err != nil {
+		return "", err
+	}
+
+	klog.Infof("[%v] Node pool %q pinned as the primary one (previously %q)", resourceOffer.Spec.ClusterID, primary, pinned)
+	return primary, nil
+}
+
+// deleteStaleVirtualKubeletDeployments deletes the VirtualKubelet Deployments not included in the desired ones
+// (i.e., associated with node pools no longer offered), once the corresponding node has been drained.
+func (r *ResourceOfferReconciler) deleteStaleVirtualKubeletDeployments(ctx context.Context,
+	resourceOffer *sharingv1alpha1.ResourceOffer, desired map[string]struct{}) error {
+	virtualKubeletDeployments, err := r.getVirtualKubeletDeployments(ctx, resourceOffer)
 	if err != nil {
 		klog.Error(err)
 		return err
 	}
-	if virtualKubeletDeployment == nil || !virtualKubeletDeployment.DeletionTimestamp.IsZero() {
-		return nil
+
+	for i := range virtualKubeletDeployments {
+		deployment := &virtualKubeletDeployments[i]
+		if _, found := desired[deployment.Name]; found || !deployment.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		// Wait for the virtual kubelet to drain and delete the corresponding node.
+		if controllerutil.ContainsFinalizer(resourceOffer, nodeFinalizerForDeployment(deployment)) {
+			klog.V(4).Infof("[%v] Waiting for the node associated with Deployment %q to be drained",
+				resourceOffer.Spec.ClusterID, klog.KObj(deployment))
+			continue
+		}
+
+		if err := r.Client.Delete(ctx, deployment); client.IgnoreNotFound(err) != nil {
+			klog.Error(err)
+			return err
+		}
+		msg := fmt.Sprintf("[%v] Deleting virtual-kubelet %v in namespace %v", resourceOffer.Spec.ClusterID,
+			deployment.Name, resourceOffer.Namespace)
+		klog.Info(msg)
+		r.eventsRecorder.Event(resourceOffer, "Normal", "VkDeleted", msg)
 	}
+	return nil
+}
 
-	if err := r.Client.Delete(ctx, virtualKubeletDeployment); err != nil {
+// deleteVirtualKubeletDeployment deletes the VirtualKubelet Deployments.
+func (r *ResourceOfferReconciler) deleteVirtualKubeletDeployment(
+	ctx context.Context, resourceOffer *sharingv1alpha1.ResourceOffer) error {
+	virtualKubeletDeployments, err := r.getVirtualKubeletDeployments(ctx, resourceOffer)
+	if err != nil {
 		klog.Error(err)
 		return err
 	}
 
-	controllerutil.RemoveFinalizer(resourceOffer, consts.VirtualKubeletFinalizer)
-	msg := fmt.Sprintf("[%v] Deleting virtual-kubelet in namespace %v", resourceOffer.Spec.ClusterID, resourceOffer.Namespace)
-	klog.Info(msg)
-	r.eventsRecorder.Event(resourceOffer, "Normal", "VkDeleted", msg)
+	for i := range virtualKubeletDeployments {
+		if !virtualKubeletDeployments[i].DeletionTimestamp.IsZero() {
+			continue
+		}
+		if err := r.Client.Delete(ctx, &virtualKubeletDeployments[i]); client.IgnoreNotFound(err) != nil {
+			klog.Error(err)
+			return err
+		}
+	}
+
+	if controllerutil.ContainsFinalizer(resourceOffer, consts.VirtualKubeletFinalizer) {
+		controllerutil.RemoveFinalizer(resourceOffer, consts.VirtualKubeletFinalizer)
+		msg := fmt.Sprintf("[%v] Deleting virtual-kubelet in namespace %v", resourceOffer.Spec.ClusterID, resourceOffer.Namespace)
+		klog.Info(msg)
+		r.eventsRecorder.Event(resourceOffer, "Normal", "VkDeleted", msg)
+	}
 	return nil
 }
 
@@ -188,26 +317,38 @@ func (r *ResourceOfferReconciler) deleteClusterRoleBinding(
 	return nil
 }
 
-// getVirtualKubeletDeployment returns the VirtualKubelet Deployment given a ResourceOffer.
-func (r *ResourceOfferReconciler) getVirtualKubeletDeployment(
-	ctx context.Context, resourceOffer *sharingv1alpha1.ResourceOffer) (*appsv1.Deployment, error) {
+// getVirtualKubeletDeployments returns the VirtualKubelet Deployments given a ResourceOffer.
+func (r *ResourceOfferReconciler) getVirtualKubeletDeployments(
+	ctx context.Context, resourceOffer *sharingv1alpha1.ResourceOffer) ([]appsv1.Deployment, error) {
 	var deployList appsv1.DeploymentList
 	labels := forge.VirtualKubeletLabels(resourceOffer.Spec.ClusterID, r.virtualKubeletOpts)
-	if err := r.Client.List(ctx, &deployList, client.MatchingLabels(labels)); err != nil {
+	if err := r.Client.List(ctx, &deployList, client.InNamespace(resourceOffer.Namespace), client.MatchingLabels(labels)); err != nil {
 		klog.Error(err)
 		return nil, err
 	}
 
 	if len(deployList.Items) == 0 {
 		klog.V(4).Infof("[%v] no VirtualKubelet deployment found", resourceOffer.Spec.ClusterID)
-		return nil, nil
-	} else if len(deployList.Items) > 1 {
-		err := fmt.Errorf("[%v] more than one VirtualKubelet deployment found", resourceOffer.Spec.ClusterID)
-		klog.Error(err)
-		return nil, err
 	}
+	return deployList.Items, nil
+}
 
-	return &deployList.Items[0], nil
+// nodeFinalizerForDeployment returns the finalizer set on the ResourceOffer by the given VirtualKubelet Deployment.
+func nodeFinalizerForDeployment(deployment *appsv1.Deployment) string {
+	if pool, found := deployment.Labels[consts.NodePoolLabel]; found {
+		return virtualKubelet.NodePoolFinalizer(pool)
+	}
+	return consts.NodeFinalizer
+}
+
+// hasNodeFinalizers returns whether the ResourceOffer has any finalizer set by a virtual kubelet.
+func hasNodeFinalizers(resourceOffer *sharingv1alpha1.ResourceOffer) bool {
+	for _, finalizer := range resourceOffer.GetFinalizers() {
+		if virtualKubelet.IsNodeFinalizer(finalizer) {
+			return true
+		}
+	}
+	return false
 }
 
 type kubeletDeletePhase string
@@ -224,14 +365,14 @@ func getDeleteVirtualKubeletPhase(resourceOffer *sharingv1alpha1.ResourceOffer)
 	notAccepted := !isAccepted(resourceOffer)
 	deleting := !resourceOffer.DeletionTimestamp.IsZero()
 	desiredDelete := !resourceOffer.Spec.WithdrawalTimestamp.IsZero()
-	nodeDrained := !controllerutil.ContainsFinalizer(resourceOffer, consts.NodeFinalizer)
+	nodeDrained := !hasNodeFinalizers(resourceOffer)
 
 	// if the ResourceRequest has not been accepted by the local cluster,
 	// or it has a DeletionTimestamp not equal to zero (the resource has been deleted),
 	// or it has a WithdrawalTimestamp not equal to zero (the remote cluster asked for its graceful deletion),
 	// the VirtualKubelet is in a terminating phase, otherwise return the None phase.
 	if notAccepted || deleting || desiredDelete {
-		// if the liqo.io/node finalizer is not set, the remote cluster has been drained and the node has been deleted,
+		// if no liqo.io/node finalizer is set, the remote cluster has been drained and the nodes have been deleted,
 		// we can then proceed with the VirtualKubelet deletion.
 		if nodeDrained {
 			return kubeletDeletePhaseNodeDeleted
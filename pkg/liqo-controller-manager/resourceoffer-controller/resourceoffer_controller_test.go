@@ -26,9 +26,12 @@ import (
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -38,7 +41,9 @@ import (
 	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
 	"github.com/liqotech/liqo/pkg/consts"
 	"github.com/liqotech/liqo/pkg/discovery"
+	foreignclusterutils "github.com/liqotech/liqo/pkg/utils/foreignCluster"
 	"github.com/liqotech/liqo/pkg/utils/testutil"
+	"github.com/liqotech/liqo/pkg/virtualKubelet"
 	"github.com/liqotech/liqo/pkg/vkMachinery/forge"
 )
 
@@ -110,7 +115,7 @@ var _ = Describe("ResourceOffer Controller", func() {
 		}
 
 		controller = NewResourceOfferController(mgr, remoteClusterIdentity,
-			10*time.Second, testNamespace, kubeletOpts, true)
+			10*time.Second, testNamespace, kubeletOpts, true, nil)
 		if err := controller.SetupWithManager(mgr); err != nil {
 			By(err.Error())
 			os.Exit(1)
@@ -244,20 +249,20 @@ var _ = Describe("ResourceOffer Controller", func() {
 					return false
 				}
 
-				vkDeploy, err := controller.getVirtualKubeletDeployment(ctx, resourceOffer)
-				if err != nil || vkDeploy == nil {
+				vkDeploys, err := controller.getVirtualKubeletDeployments(ctx, resourceOffer)
+				if err != nil || len(vkDeploys) != 1 {
 					return false
 				}
-				return reflect.DeepEqual(deploymentList.Items[0], *vkDeploy)
+				return reflect.DeepEqual(deploymentList.Items[0], vkDeploys[0])
 			}, timeout, interval).Should(BeTrue())
 
 			// check that the deployment has the controller reference annotation
 			Eventually(func() string {
-				vkDeploy, err := controller.getVirtualKubeletDeployment(ctx, resourceOffer)
-				if err != nil || vkDeploy == nil {
+				vkDeploys, err := controller.getVirtualKubeletDeployments(ctx, resourceOffer)
+				if err != nil || len(vkDeploys) != 1 {
 					return ""
 				}
-				return vkDeploy.Annotations[resourceOfferAnnotation]
+				return vkDeploys[0].Annotations[resourceOfferAnnotation]
 			}, timeout, interval).Should(Equal(resourceOffer.Name))
 
 			// check the existence of the ClusterRoleBinding
@@ -272,18 +277,20 @@ var _ = Describe("ResourceOffer Controller", func() {
 			}, timeout, interval).Should(BeNumerically("==", 1))
 
 			// get the vk deployment and delete it
-			vkDeploy, err := controller.getVirtualKubeletDeployment(ctx, resourceOffer)
+			vkDeploys, err := controller.getVirtualKubeletDeployments(ctx, resourceOffer)
 			Expect(err).To(BeNil())
+			Expect(vkDeploys).To(HaveLen(1))
+			vkDeploy := &vkDeploys[0]
 			err = controller.Client.Delete(ctx, vkDeploy)
 			Expect(err).To(BeNil())
 
 			// check the deployment recreation
 			Eventually(func() types.UID {
-				newVkDeploy, err := controller.getVirtualKubeletDeployment(ctx, resourceOffer)
-				if err != nil || newVkDeploy == nil {
+				newVkDeploys, err := controller.getVirtualKubeletDeployments(ctx, resourceOffer)
+				if err != nil || len(newVkDeploys) != 1 {
 					return vkDeploy.UID // this will cause the eventually statement to not terminate
 				}
-				return newVkDeploy.UID
+				return newVkDeploys[0].UID
 			}, timeout, interval).ShouldNot(Equal(vkDeploy.UID))
 
 			err = controller.Client.Get(ctx, client.ObjectKeyFromObject(resourceOffer), resourceOffer)
@@ -327,12 +334,264 @@ var _ = Describe("ResourceOffer Controller", func() {
 			Expect(err).To(BeNil())
 		})
 
+		It("test virtual kubelet creation with node pools", func() {
+			resourceOffer := &sharingv1alpha1.ResourceOffer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "resource-offer",
+					Namespace: testNamespace,
+					Labels: map[string]string{
+						consts.ReplicationOriginLabel: "origin-cluster-id",
+						consts.ReplicationStatusLabel: "true",
+					},
+				},
+				Spec: sharingv1alpha1.ResourceOfferSpec{
+					ClusterID: remoteClusterIdentity.ClusterID,
+					NodePools: []sharingv1alpha1.NodePool{{Name: "gpu"}, {Name: "cpu"}},
+				},
+			}
+			Expect(controller.Client.Create(ctx, resourceOffer)).To(Succeed())
+
+			Eventually(func() error {
+				if err := controller.Client.Get(ctx, client.ObjectKeyFromObject(resourceOffer), resourceOffer); err != nil {
+					return err
+				}
+				resourceOffer.Status.Phase = sharingv1alpha1.ResourceOfferAccepted
+				return controller.Status().Update(ctx, resourceOffer)
+			}, timeout, interval).Should(Succeed())
+
+			// check the creation of one deployment for each node pool
+			var vkDeploys []v1.Deployment
+			Eventually(func() []v1.Deployment {
+				vkDeploys, _ = controller.getVirtualKubeletDeployments(ctx, resourceOffer)
+				return vkDeploys
+			}, timeout, interval).Should(HaveLen(2))
+
+			args := map[string][]string{}
+			for i := range vkDeploys {
+				args[vkDeploys[i].Labels[consts.NodePoolLabel]] = vkDeploys[i].Spec.Template.Spec.Containers[0].Args
+			}
+
+			// the first node pool (in alphabetical order) reflects all resources
+			Expect(args).To(HaveKey("cpu"))
+			Expect(args["cpu"]).To(ContainElements(
+				"--nodename="+virtualKubelet.VirtualNodePoolName(remoteClusterIdentity, "cpu"), "--node-pool=cpu",
+				"--sibling-nodes="+virtualKubelet.VirtualNodePoolName(remoteClusterIdentity, "gpu")))
+			Expect(args["cpu"]).ToNot(ContainElement("--pod-reflection-only"))
+
+			Expect(args).To(HaveKey("gpu"))
+			Expect(args["gpu"]).To(ContainElements(
+				"--nodename="+virtualKubelet.VirtualNodePoolName(remoteClusterIdentity, "gpu"), "--node-pool=gpu", "--pod-reflection-only",
+				"--sibling-nodes="+virtualKubelet.VirtualNodePoolName(remoteClusterIdentity, "cpu")))
+
+			// the primary node pool is pinned on the ForeignCluster
+			foreignCluster, err := foreignclusterutils.GetForeignClusterByID(ctx, controller.Client, remoteClusterIdentity.ClusterID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(foreignCluster.Annotations).To(HaveKeyWithValue(consts.PrimaryNodePoolAnnotation, "cpu"))
+
+			// a new node pool preceding the primary one (in alphabetical order) does not change the primary node pool
+			Eventually(func() error {
+				if err := controller.Client.Get(ctx, client.ObjectKeyFromObject(resourceOffer), resourceOffer); err != nil {
+					return err
+				}
+				resourceOffer.Spec.NodePools = []sharingv1alpha1.NodePool{{Name: "gpu"}, {Name: "cpu"}, {Name: "arm"}}
+				return controller.Client.Update(ctx, resourceOffer)
+			}, timeout, interval).Should(Succeed())
+
+			Eventually(func() []v1.Deployment {
+				vkDeploys, _ = controller.getVirtualKubeletDeployments(ctx, resourceOffer)
+				return vkDeploys
+			}, timeout, interval).Should(HaveLen(3))
+
+			args = map[string][]string{}
+			for i := range vkDeploys {
+				args[vkDeploys[i].Labels[consts.NodePoolLabel]] = vkDeploys[i].Spec.Template.Spec.Containers[0].Args
+			}
+			Expect(args["arm"]).To(ContainElement("--pod-reflection-only"))
+			Expect(args["cpu"]).ToNot(ContainElement("--pod-reflection-only"))
+
+			Expect(controller.Client.Delete(ctx, resourceOffer)).To(Succeed())
+		})
+
 	})
 
 })
 
 var _ = Describe("ResourceOffer Operator util functions", func() {
 
+	Context("AcceptancePolicy", func() {
+
+		type acceptancePolicyTestcase struct {
+			policy         *AcceptancePolicy
+			spec           sharingv1alpha1.ResourceOfferSpec
+			expectedPhase  sharingv1alpha1.OfferPhase
+			expectedReason string
+		}
+
+		policy := &AcceptancePolicy{
+			MaxPrices:      corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("500m")},
+			RequiredLabels: map[string]string{"liqo.io/provider": "kubeadm"},
+			MinQuota:       corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("4")},
+		}
+
+		compliantSpec := func() sharingv1alpha1.ResourceOfferSpec {
+			return sharingv1alpha1.ResourceOfferSpec{
+				ClusterID:     remoteClusterIdentity.ClusterID,
+				Labels:        map[string]string{"liqo.io/provider": "kubeadm", "liqo.io/other": "value"},
+				ResourceQuota: corev1.ResourceQuotaSpec{Hard: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("8")}},
+				Prices:        corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("500m")},
+			}
+		}
+
+		DescribeTable("AcceptancePolicy table",
+			func(c acceptancePolicyTestcase) {
+				phase, reason, message := c.policy.evaluate(&sharingv1alpha1.ResourceOffer{Spec: c.spec})
+				Expect(phase).To(Equal(c.expectedPhase))
+				Expect(reason).To(Equal(c.expectedReason))
+				Expect(message).ToNot(BeEmpty())
+			},
+
+			Entry("no acceptance policy", acceptancePolicyTestcase{
+				spec:           sharingv1alpha1.ResourceOfferSpec{},
+				expectedPhase:  sharingv1alpha1.ResourceOfferAccepted,
+				expectedReason: reasonPolicySatisfied,
+			}),
+
+			Entry("compliant offer", acceptancePolicyTestcase{
+				policy:         policy,
+				spec:           compliantSpec(),
+				expectedPhase:  sharingv1alpha1.ResourceOfferAccepted,
+				expectedReason: reasonPolicySatisfied,
+			}),
+
+			Entry("offer missing a required label", acceptancePolicyTestcase{
+				policy: policy,
+				spec: func() sharingv1alpha1.ResourceOfferSpec {
+					spec := compliantSpec()
+					spec.Labels["liqo.io/provider"] = "kind"
+					return spec
+				}(),
+				expectedPhase:  sharingv1alpha1.ResourceOfferRefused,
+				expectedReason: reasonMissingLabels,
+			}),
+
+			Entry("offer with insufficient resources", acceptancePolicyTestcase{
+				policy: policy,
+				spec: func() sharingv1alpha1.ResourceOfferSpec {
+					spec := compliantSpec()
+					spec.ResourceQuota.Hard[corev1.ResourceCPU] = resource.MustParse("2")
+					return spec
+				}(),
+				expectedPhase:  sharingv1alpha1.ResourceOfferRefused,
+				expectedReason: reasonInsufficientQuota,
+			}),
+
+			Entry("offer with a price too high", acceptancePolicyTestcase{
+				policy: policy,
+				spec: func() sharingv1alpha1.ResourceOfferSpec {
+					spec := compliantSpec()
+					spec.Prices[corev1.ResourceMemory] = resource.MustParse("1")
+					return spec
+				}(),
+				expectedPhase:  sharingv1alpha1.ResourceOfferRefused,
+				expectedReason: reasonPriceTooHigh,
+			}),
+
+			Entry("offer without the price of a constrained resource", acceptancePolicyTestcase{
+				policy: policy,
+				spec: func() sharingv1alpha1.ResourceOfferSpec {
+					spec := compliantSpec()
+					delete(spec.Prices, corev1.ResourceCPU)
+					return spec
+				}(),
+				expectedPhase:  sharingv1alpha1.ResourceOfferManualActionRequired,
+				expectedReason: reasonPriceNotAvailable,
+			}),
+		)
+
+		type reevaluationTestcase struct {
+			phase         sharingv1alpha1.OfferPhase
+			reason        string
+			generation    int64
+			spec          sharingv1alpha1.ResourceOfferSpec
+			expectedPhase sharingv1alpha1.OfferPhase
+		}
+
+		DescribeTable("re-evaluation of the offers already decided",
+			func(c reevaluationTestcase) {
+				r := &ResourceOfferReconciler{acceptancePolicy: policy, eventsRecorder: record.NewFakeRecorder(10)}
+				offer := &sharingv1alpha1.ResourceOffer{
+					ObjectMeta: metav1.ObjectMeta{Generation: c.generation},
+					Spec:       c.spec,
+					Status:     sharingv1alpha1.ResourceOfferStatus{Phase: c.phase, Reason: c.reason, ObservedGeneration: 1},
+				}
+
+				r.setResourceOfferPhase(offer)
+				Expect(offer.Status.Phase).To(Equal(c.expectedPhase))
+				Expect(offer.Status.ObservedGeneration).To(Equal(c.generation))
+			},
+
+			Entry("accepted offer whose spec did not change", reevaluationTestcase{
+				phase:         sharingv1alpha1.ResourceOfferAccepted,
+				generation:    1,
+				spec:          sharingv1alpha1.ResourceOfferSpec{},
+				expectedPhase: sharingv1alpha1.ResourceOfferAccepted,
+			}),
+			Entry("accepted offer whose spec changed, still satisfying the policy", reevaluationTestcase{
+				phase:         sharingv1alpha1.ResourceOfferAccepted,
+				generation:    2,
+				spec:          compliantSpec(),
+				expectedPhase: sharingv1alpha1.ResourceOfferAccepted,
+			}),
+			Entry("accepted offer whose spec changed, no longer satisfying the policy", reevaluationTestcase{
+				phase:         sharingv1alpha1.ResourceOfferAccepted,
+				generation:    2,
+				spec:          sharingv1alpha1.ResourceOfferSpec{},
+				expectedPhase: sharingv1alpha1.ResourceOfferRefused,
+			}),
+			Entry("manually accepted offer whose spec changed, still missing some prices", reevaluationTestcase{
+				phase:      sharingv1alpha1.ResourceOfferAccepted,
+				generation: 2,
+				spec: func() sharingv1alpha1.ResourceOfferSpec {
+					spec := compliantSpec()
+					delete(spec.Prices, corev1.ResourceCPU)
+					return spec
+				}(),
+				expectedPhase: sharingv1alpha1.ResourceOfferAccepted,
+			}),
+			Entry("offer refused for insufficient quota whose spec changed, now satisfying the policy", reevaluationTestcase{
+				phase:         sharingv1alpha1.ResourceOfferRefused,
+				reason:        reasonInsufficientQuota,
+				generation:    2,
+				spec:          compliantSpec(),
+				expectedPhase: sharingv1alpha1.ResourceOfferAccepted,
+			}),
+			Entry("offer refused for insufficient quota whose spec changed, still not satisfying the policy", reevaluationTestcase{
+				phase:         sharingv1alpha1.ResourceOfferRefused,
+				reason:        reasonInsufficientQuota,
+				generation:    2,
+				spec:          sharingv1alpha1.ResourceOfferSpec{},
+				expectedPhase: sharingv1alpha1.ResourceOfferRefused,
+			}),
+			Entry("offer refused for a too high price whose spec changed, now missing some prices", reevaluationTestcase{
+				phase:      sharingv1alpha1.ResourceOfferRefused,
+				reason:     reasonPriceTooHigh,
+				generation: 2,
+				spec: func() sharingv1alpha1.ResourceOfferSpec {
+					spec := compliantSpec()
+					delete(spec.Prices, corev1.ResourceCPU)
+					return spec
+				}(),
+				expectedPhase: sharingv1alpha1.ResourceOfferManualActionRequired,
+			}),
+			Entry("manually refused offer whose spec changed, now satisfying the policy", reevaluationTestcase{
+				phase:         sharingv1alpha1.ResourceOfferRefused,
+				generation:    2,
+				spec:          compliantSpec(),
+				expectedPhase: sharingv1alpha1.ResourceOfferRefused,
+			}),
+		)
+	})
+
 	Context("getDeleteVirtualKubeletPhase", func() {
 
 		type getDeleteVirtualKubeletPhaseTestcase struct {
@@ -440,6 +699,23 @@ var _ = Describe("ResourceOffer Operator util functions", func() {
 				expected: Equal(kubeletDeletePhaseDrainingNode),
 			}),
 
+			Entry("desired deletion of ResourceOffer with node pool finalizer", getDeleteVirtualKubeletPhaseTestcase{
+				resourceOffer: &sharingv1alpha1.ResourceOffer{
+					ObjectMeta: metav1.ObjectMeta{
+						Finalizers: []string{
+							virtualKubelet.NodePoolFinalizer("pool"),
+						},
+					},
+					Spec: sharingv1alpha1.ResourceOfferSpec{
+						WithdrawalTimestamp: &now,
+					},
+					Status: sharingv1alpha1.ResourceOfferStatus{
+						Phase: sharingv1alpha1.ResourceOfferAccepted,
+					},
+				},
+				expected: Equal(kubeletDeletePhaseDrainingNode),
+			}),
+
 			Entry("desired deletion of ResourceOffer without finalizer", getDeleteVirtualKubeletPhaseTestcase{
 				resourceOffer: &sharingv1alpha1.ResourceOffer{
 					ObjectMeta: metav1.ObjectMeta{
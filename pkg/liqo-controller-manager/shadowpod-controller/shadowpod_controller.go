@@ -16,19 +16,22 @@ package shadowpodctrl
 
 import (
 	"context"
+	"sort"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
-	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
@@ -43,6 +46,7 @@ type Reconciler struct {
 
 // +kubebuilder:rbac:groups=virtualkubelet.liqo.io,resources=shadowpods,verbs=get;list;watch;update;patch;delete
 // +kubebuilder:rbac:groups=virtualkubelet.liqo.io,resources=shadowpods/finalizers,verbs=get;update;patch
+// +kubebuilder:rbac:groups=virtualkubelet.liqo.io,resources=shadowpods/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile ShadowPods objects.
@@ -59,50 +63,214 @@ func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, err
 	}
 
+	if !shadowPod.DeletionTimestamp.IsZero() {
+		klog.V(4).Infof("skip: shadowpod %s is terminating", nsName)
+		return ctrl.Result{}, nil
+	}
+
+	pod := corev1.Pod{}
+	if err := r.Get(ctx, nsName, &pod); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, r.createPod(ctx, &shadowPod)
+		}
+
+		klog.Errorf("unable to retrieve pod for shadowpod %q: %v", klog.KObj(&shadowPod), err)
+		return ctrl.Result{}, err
+	}
+
+	if !metav1.IsControlledBy(&pod, &shadowPod) {
+		// The pod has been created out of band (e.g., it has been deleted and recreated by a third party),
+		// hence it is deleted, so that it can be replaced by one matching the ShadowPod.
+		klog.Warningf("pod %q is not controlled by shadowpod %q, deleting it", klog.KObj(&pod), klog.KObj(&shadowPod))
+		if err := r.Delete(ctx, &pod, client.Preconditions{UID: &pod.UID}); client.IgnoreNotFound(err) != nil {
+			klog.Errorf("unable to delete pod %q: %v", klog.KObj(&pod), err)
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// The pod is recreated once the deletion completes, through the corresponding event.
+	if pod.DeletionTimestamp.IsZero() {
+		if err := r.enforcePodMutableFields(ctx, &shadowPod, &pod); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, r.updateStatus(ctx, &shadowPod, &pod)
+}
+
+// createPod creates the pod corresponding to the given ShadowPod.
+func (r *Reconciler) createPod(ctx context.Context, shadowPod *vkv1alpha1.ShadowPod) error {
 	pod := corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        nsName.Name,
-			Namespace:   nsName.Namespace,
-			Labels:      labels.Merge(shadowPod.Labels, labels.Set{consts.ManagedByLabelKey: consts.ManagedByShadowPodValue}),
-			Annotations: shadowPod.Annotations,
+			Name:        shadowPod.Name,
+			Namespace:   shadowPod.Namespace,
+			Labels:      podLabels(shadowPod),
+			Annotations: podAnnotations(shadowPod),
 		},
 		Spec: shadowPod.Spec.Pod,
 	}
 
-	utilruntime.Must(ctrl.SetControllerReference(&shadowPod, &pod, r.Scheme))
-
-	if err := r.Get(ctx, nsName, &pod); err == nil {
-		klog.V(4).Infof("skip: pod %q already running", klog.KObj(&pod))
-		return ctrl.Result{}, nil
-	}
+	utilruntime.Must(ctrl.SetControllerReference(shadowPod, &pod, r.Scheme))
 
 	if err := r.Create(ctx, &pod); err != nil {
 		if errors.IsAlreadyExists(err) {
 			klog.V(4).Infof("pod %q already exists", klog.KObj(&pod))
-			return ctrl.Result{}, nil
+			return nil
 		}
 
-		klog.Errorf("unable to create pod for shadowpod %q: %v", klog.KObj(&shadowPod), err)
-		return ctrl.Result{}, err
+		klog.Errorf("unable to create pod for shadowpod %q: %v", klog.KObj(shadowPod), err)
+		return err
+	}
+
+	klog.Infof("created pod %q for shadowpod %q", klog.KObj(&pod), klog.KObj(shadowPod))
+	return nil
+}
+
+// enforcePodMutableFields propagates to the pod the changes to the ShadowPod fields which can be mutated in-place,
+// that is labels, annotations, container images, active deadline and (additional) tolerations.
+// Changes to any other field of the pod specification are not propagated, as not permitted by the API server.
+// The labels and annotations added to the pod by third parties (e.g., admission plugins) are preserved.
+func (r *Reconciler) enforcePodMutableFields(ctx context.Context, shadowPod *vkv1alpha1.ShadowPod, pod *corev1.Pod) error {
+	original := pod.DeepCopy()
+
+	pod.SetLabels(mergeManagedEntries(pod.GetLabels(), podLabels(shadowPod),
+		managedKeys(original.GetAnnotations(), consts.ShadowPodManagedLabelsAnnotationKey)))
+	pod.SetAnnotations(mergeManagedEntries(pod.GetAnnotations(), podAnnotations(shadowPod),
+		managedKeys(original.GetAnnotations(), consts.ShadowPodManagedAnnotationsAnnotationKey)))
+	enforceContainerImages(pod.Spec.Containers, shadowPod.Spec.Pod.Containers)
+	enforceContainerImages(pod.Spec.InitContainers, shadowPod.Spec.Pod.InitContainers)
+
+	// The active deadline cannot be removed once set.
+	if shadowPod.Spec.Pod.ActiveDeadlineSeconds != nil {
+		pod.Spec.ActiveDeadlineSeconds = shadowPod.Spec.Pod.ActiveDeadlineSeconds
+	}
+
+	// Tolerations can only be added, and the pod may include additional ones configured by admission plugins.
+	for i := range shadowPod.Spec.Pod.Tolerations {
+		if !hasToleration(pod.Spec.Tolerations, &shadowPod.Spec.Pod.Tolerations[i]) {
+			pod.Spec.Tolerations = append(pod.Spec.Tolerations, shadowPod.Spec.Pod.Tolerations[i])
+		}
 	}
 
-	klog.Infof("created pod %q for shadowpod %q", klog.KObj(&pod), klog.KObj(&shadowPod))
+	if equality.Semantic.DeepEqual(original, pod) {
+		klog.V(4).Infof("skip: pod %q already in sync with shadowpod %q", klog.KObj(pod), klog.KObj(shadowPod))
+		return nil
+	}
+
+	if err := r.Patch(ctx, pod, client.MergeFrom(original)); err != nil {
+		klog.Errorf("unable to update pod %q for shadowpod %q: %v", klog.KObj(pod), klog.KObj(shadowPod), err)
+		return err
+	}
 
-	return ctrl.Result{}, nil
+	klog.Infof("updated pod %q for shadowpod %q", klog.KObj(pod), klog.KObj(shadowPod))
+	return nil
 }
 
-// SetupWithManager monitors only updates on ShadowPods.
-func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, workers int) error {
-	// Trigger a reconciliation only for DeleteEvent.
-	deletedPredicate := predicate.Funcs{
-		DeleteFunc:  func(e event.DeleteEvent) bool { return true },
-		CreateFunc:  func(e event.CreateEvent) bool { return false },
-		UpdateFunc:  func(e event.UpdateEvent) bool { return false },
-		GenericFunc: func(e event.GenericEvent) bool { return false },
+// updateStatus mirrors the status of the pod into the one of the corresponding ShadowPod.
+func (r *Reconciler) updateStatus(ctx context.Context, shadowPod *vkv1alpha1.ShadowPod, pod *corev1.Pod) error {
+	status := vkv1alpha1.ShadowPodStatus{
+		Phase:      pod.Status.Phase,
+		Conditions: pod.Status.Conditions,
+		NodeName:   pod.Spec.NodeName,
 	}
+
+	if equality.Semantic.DeepEqual(shadowPod.Status, status) {
+		return nil
+	}
+
+	original := shadowPod.DeepCopy()
+	shadowPod.Status = status
+	if err := r.Status().Patch(ctx, shadowPod, client.MergeFrom(original)); err != nil {
+		klog.Errorf("unable to update status of shadowpod %q: %v", klog.KObj(shadowPod), err)
+		return err
+	}
+
+	klog.V(4).Infof("updated status of shadowpod %q (phase: %s)", klog.KObj(shadowPod), status.Phase)
+	return nil
+}
+
+// podLabels returns the labels to be assigned to the pod corresponding to the given ShadowPod.
+func podLabels(shadowPod *vkv1alpha1.ShadowPod) labels.Set {
+	return labels.Merge(shadowPod.Labels, labels.Set{consts.ManagedByLabelKey: consts.ManagedByShadowPodValue})
+}
+
+// podAnnotations returns the annotations to be assigned to the pod corresponding to the given ShadowPod, including the ones
+// tracking the labels and annotations propagated from the ShadowPod, to remove them once removed from the ShadowPod.
+func podAnnotations(shadowPod *vkv1alpha1.ShadowPod) labels.Set {
+	annotations := labels.Set{}
+	for key, value := range shadowPod.Annotations {
+		if key != consts.ShadowPodManagedLabelsAnnotationKey && key != consts.ShadowPodManagedAnnotationsAnnotationKey {
+			annotations[key] = value
+		}
+	}
+
+	return labels.Merge(annotations, labels.Set{
+		consts.ShadowPodManagedLabelsAnnotationKey:      joinKeys(podLabels(shadowPod)),
+		consts.ShadowPodManagedAnnotationsAnnotationKey: joinKeys(annotations),
+	})
+}
+
+// mergeManagedEntries returns the current entries (i.e., labels or annotations) updated with the desired ones,
+// and excluding the previously managed ones which are no longer desired. The other entries are preserved.
+func mergeManagedEntries(current, desired labels.Set, previous []string) labels.Set {
+	merged := labels.Merge(current, nil)
+	for _, key := range previous {
+		if _, found := desired[key]; !found {
+			delete(merged, key)
+		}
+	}
+	return labels.Merge(merged, desired)
+}
+
+// managedKeys returns the keys tracked by the given annotation, as set by podAnnotations.
+func managedKeys(annotations map[string]string, annotation string) []string {
+	if value := annotations[annotation]; value != "" {
+		return strings.Split(value, ",")
+	}
+	return nil
+}
+
+// joinKeys returns the sorted keys of the given set, separated by commas.
+func joinKeys(set labels.Set) string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+// enforceContainerImages sets the image of each container to the one of the desired container with the same name.
+func enforceContainerImages(containers, desired []corev1.Container) {
+	for i := range containers {
+		for j := range desired {
+			if containers[i].Name == desired[j].Name {
+				containers[i].Image = desired[j].Image
+				break
+			}
+		}
+	}
+}
+
+// hasToleration returns whether the given toleration is included in the list.
+func hasToleration(tolerations []corev1.Toleration, toleration *corev1.Toleration) bool {
+	for i := range tolerations {
+		if tolerations[i].MatchToleration(toleration) && pointer.Int64Equal(tolerations[i].TolerationSeconds, toleration.TolerationSeconds) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetupWithManager monitors ShadowPods and the corresponding pods.
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager, workers int) error {
+	// Status updates do not trigger a reconciliation, as they do not modify the generation.
+	shadowPodPredicate := predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{},
+		predicate.AnnotationChangedPredicate{})
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&vkv1alpha1.ShadowPod{}).
-		Owns(&corev1.Pod{}, builder.WithPredicates(deletedPredicate)).
+		For(&vkv1alpha1.ShadowPod{}, builder.WithPredicates(shadowPodPredicate)).
+		Owns(&corev1.Pod{}).
 		WithOptions(controller.Options{MaxConcurrentReconciles: workers}).
 		Complete(r)
 }
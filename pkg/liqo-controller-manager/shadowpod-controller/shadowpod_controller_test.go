@@ -27,12 +27,14 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 	"k8s.io/kubectl/pkg/scheme"
+	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
 	"github.com/liqotech/liqo/pkg/consts"
 	shadowpodctrl "github.com/liqotech/liqo/pkg/liqo-controller-manager/shadowpod-controller"
+	"github.com/liqotech/liqo/pkg/utils/testutil"
 )
 
 var _ = Describe("Reconcile", func() {
@@ -116,16 +118,103 @@ var _ = Describe("Reconcile", func() {
 		})
 	})
 
+	When("pod has been created out of band", func() {
+		BeforeEach(func() {
+			Expect(k8sClient.Create(ctx, &testShadowPod)).To(Succeed())
+			Expect(k8sClient.Create(ctx, &testPod)).To(Succeed())
+		})
+
+		It("should delete it and requeue the shadowpod", func() {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.Requeue).To(BeTrue())
+			Expect(buffer.String()).To(ContainSubstring("pod \"default/test-shadow-pod\" is not controlled by shadowpod \"default/test-shadow-pod\""))
+			Expect(k8sClient.Get(ctx, req.NamespacedName, &corev1.Pod{})).To(testutil.BeNotFound())
+		})
+	})
+
 	When("pod has been already created", func() {
 		BeforeEach(func() {
 			Expect(k8sClient.Create(ctx, &testShadowPod)).To(Succeed())
+
+			// The pod is also characterized by a label and an annotation added by a third party.
+			testPod.SetLabels(map[string]string{consts.ManagedByLabelKey: consts.ManagedByShadowPodValue, "third-party": "value"})
+			for key, value := range testShadowPod.GetLabels() {
+				testPod.Labels[key] = value
+			}
+			testPod.SetAnnotations(map[string]string{
+				consts.ShadowPodManagedLabelsAnnotationKey:      "label1-key,label2-key," + consts.ManagedByLabelKey,
+				consts.ShadowPodManagedAnnotationsAnnotationKey: "annotation1-key,annotation2-key",
+				"third-party": "value",
+			})
+			for key, value := range testShadowPod.GetAnnotations() {
+				testPod.Annotations[key] = value
+			}
+			testPod.Spec.NodeName = "worker"
+			Expect(ctrl.SetControllerReference(&testShadowPod, &testPod, scheme.Scheme)).To(Succeed())
 			Expect(k8sClient.Create(ctx, &testPod)).To(Succeed())
+
+			testPod.Status = corev1.PodStatus{
+				Phase:      corev1.PodRunning,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+			}
+			Expect(k8sClient.Status().Update(ctx, &testPod)).To(Succeed())
 		})
 
-		It("should ignore it", func() {
+		It("should not modify the pod, if already in sync", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(res).To(BeZero())
-			Expect(buffer.String()).To(ContainSubstring("skip: pod \"default/test-shadow-pod\" already running"))
+			Expect(buffer.String()).To(ContainSubstring("skip: pod \"default/test-shadow-pod\" already in sync with shadowpod \"default/test-shadow-pod\""))
+		})
+
+		It("should mirror the pod status into the shadowpod status", func() {
+			shadowPod := vkv1alpha1.ShadowPod{}
+			Expect(k8sClient.Get(ctx, req.NamespacedName, &shadowPod)).To(Succeed())
+			Expect(shadowPod.Status.Phase).To(Equal(corev1.PodRunning))
+			Expect(shadowPod.Status.NodeName).To(Equal("worker"))
+			Expect(shadowPod.Status.Conditions).To(ConsistOf(MatchFields(IgnoreExtras, Fields{
+				"Type":   Equal(corev1.PodReady),
+				"Status": Equal(corev1.ConditionTrue),
+			})))
+		})
+
+		When("the shadowpod has been modified", func() {
+			BeforeEach(func() {
+				original := testShadowPod.DeepCopy()
+				testShadowPod.Labels["label1-key"] = "label1-modified"
+				delete(testShadowPod.Labels, "label2-key")
+				delete(testShadowPod.Annotations, "annotation2-key")
+				testShadowPod.Annotations["annotation3-key"] = "annotation3-value"
+				testShadowPod.Spec.Pod.Containers[0].Image = "nginx:stable"
+				testShadowPod.Spec.Pod.ActiveDeadlineSeconds = pointer.Int64(3600)
+				Expect(k8sClient.Patch(ctx, &testShadowPod, client.MergeFrom(original))).To(Succeed())
+			})
+
+			It("should propagate the changes to the pod", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(res).To(BeZero())
+
+				pod := corev1.Pod{}
+				Expect(k8sClient.Get(ctx, req.NamespacedName, &pod)).To(Succeed())
+				Expect(pod.GetLabels()).To(HaveKeyWithValue("label1-key", "label1-modified"))
+				Expect(pod.GetLabels()).ToNot(HaveKey("label2-key"))
+				Expect(pod.GetLabels()).To(HaveKeyWithValue(consts.ManagedByLabelKey, consts.ManagedByShadowPodValue))
+				Expect(pod.GetAnnotations()).To(HaveKeyWithValue("annotation1-key", "annotation1-value"))
+				Expect(pod.GetAnnotations()).ToNot(HaveKey("annotation2-key"))
+				Expect(pod.GetAnnotations()).To(HaveKeyWithValue("annotation3-key", "annotation3-value"))
+				Expect(pod.GetAnnotations()).To(HaveKeyWithValue(consts.ShadowPodManagedLabelsAnnotationKey,
+					"label1-key,"+consts.ManagedByLabelKey))
+				Expect(pod.GetAnnotations()).To(HaveKeyWithValue(consts.ShadowPodManagedAnnotationsAnnotationKey,
+					"annotation1-key,annotation3-key"))
+				Expect(pod.Spec.Containers[0].Image).To(Equal("nginx:stable"))
+				Expect(pod.Spec.ActiveDeadlineSeconds).To(PointTo(BeNumerically("==", 3600)))
+			})
+
+			It("should preserve the labels and annotations added by third parties", func() {
+				pod := corev1.Pod{}
+				Expect(k8sClient.Get(ctx, req.NamespacedName, &pod)).To(Succeed())
+				Expect(pod.GetLabels()).To(HaveKeyWithValue("third-party", "value"))
+				Expect(pod.GetAnnotations()).To(HaveKeyWithValue("third-party", "value"))
+			})
 		})
 	})
 
@@ -157,7 +246,13 @@ var _ = Describe("Reconcile", func() {
 			pod := corev1.Pod{}
 			Expect(k8sClient.Get(ctx, req.NamespacedName, &pod)).To(Succeed())
 			Expect(pod.GetName()).To(Equal(testShadowPod.GetName()))
-			Expect(pod.GetAnnotations()).To(Equal(testShadowPod.GetAnnotations()))
+			for key, value := range testShadowPod.GetAnnotations() {
+				Expect(pod.GetAnnotations()).To(HaveKeyWithValue(key, value))
+			}
+			Expect(pod.GetAnnotations()).To(HaveKeyWithValue(consts.ShadowPodManagedLabelsAnnotationKey,
+				"label1-key,label2-key,"+consts.ManagedByLabelKey))
+			Expect(pod.GetAnnotations()).To(HaveKeyWithValue(consts.ShadowPodManagedAnnotationsAnnotationKey,
+				"annotation1-key,annotation2-key"))
 
 			for key, value := range testShadowPod.GetLabels() {
 				Expect(pod.GetLabels()).To(HaveKeyWithValue(key, value))
@@ -179,5 +274,6 @@ var _ = Describe("Reconcile", func() {
 
 func deleteAllShadowPodsAndPods(ctx context.Context, ns string) {
 	Expect(k8sClient.DeleteAllOf(ctx, &vkv1alpha1.ShadowPod{}, client.InNamespace(ns))).Should(Succeed())
-	Expect(k8sClient.DeleteAllOf(ctx, &corev1.Pod{}, client.InNamespace(ns))).Should(Succeed())
+	// Pods bound to a node are deleted immediately, as no kubelet would complete the graceful termination.
+	Expect(k8sClient.DeleteAllOf(ctx, &corev1.Pod{}, client.InNamespace(ns), client.GracePeriodSeconds(0))).Should(Succeed())
 }
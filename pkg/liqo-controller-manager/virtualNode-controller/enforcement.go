@@ -45,6 +45,11 @@ func (r *VirtualNodeReconciler) ensureNamespaceMapPresence(ctx context.Context,
 			liqoconst.ReplicationDestinationLabel: fc.Spec.ClusterIdentity.ClusterID,
 		})
 
+		// Multiple virtual nodes may be associated with the same cluster (i.e., node pools): in this case,
+		// the NamespaceMap is controlled by the first one, while the other ones are set as additional owners.
+		if owner := metav1.GetControllerOf(&nm); owner != nil && owner.UID != n.GetUID() {
+			return ctrlutils.SetOwnerReference(n, &nm, r.Scheme)
+		}
 		return ctrlutils.SetControllerReference(n, &nm, r.Scheme)
 	})
 
@@ -73,6 +78,20 @@ func (r *VirtualNodeReconciler) ensureNamespaceMapAbsence(ctx context.Context, f
 		return r.removeVirtualNodeFinalizer(ctx, n)
 	}
 
+	// The NamespaceMaps are still required if other virtual nodes are associated with the same cluster.
+	siblings, err := r.hasSiblingVirtualNodes(ctx, n)
+	if err != nil {
+		return err
+	}
+	if siblings {
+		for i := range namespaceMapList.Items {
+			if err := r.removeOwnerReference(ctx, &namespaceMapList.Items[i], n); err != nil {
+				return err
+			}
+		}
+		return r.removeVirtualNodeFinalizer(ctx, n)
+	}
+
 	for i := range namespaceMapList.Items {
 		if namespaceMapList.Items[i].GetDeletionTimestamp().IsZero() {
 			if err := r.Delete(ctx, &namespaceMapList.Items[i]); err != nil {
@@ -81,7 +100,48 @@ func (r *VirtualNodeReconciler) ensureNamespaceMapAbsence(ctx context.Context, f
 		}
 	}
 
-	err := fmt.Errorf("waiting for deletion of NamespaceMaps associated with virtual node %q", n.Name)
+	err = fmt.Errorf("waiting for deletion of NamespaceMaps associated with virtual node %q", n.Name)
 	klog.Info(err)
 	return err
 }
+
+// hasSiblingVirtualNodes returns whether other (not terminating) virtual nodes are associated with the same cluster of the given one.
+func (r *VirtualNodeReconciler) hasSiblingVirtualNodes(ctx context.Context, n *corev1.Node) (bool, error) {
+	virtualNodes := &corev1.NodeList{}
+	if err := r.List(ctx, virtualNodes, client.MatchingLabels{
+		liqoconst.TypeLabel:       liqoconst.TypeNode,
+		liqoconst.RemoteClusterID: n.Labels[liqoconst.RemoteClusterID],
+	}); err != nil {
+		klog.Errorf("%s -> Unable to List the virtual nodes associated with the same cluster of %q", err, n.GetName())
+		return false, err
+	}
+
+	for i := range virtualNodes.Items {
+		if virtualNodes.Items[i].GetUID() != n.GetUID() && virtualNodes.Items[i].GetDeletionTimestamp().IsZero() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// removeOwnerReference removes the owner reference pointing to the given virtual node from the NamespaceMap.
+func (r *VirtualNodeReconciler) removeOwnerReference(ctx context.Context, nm *mapsv1alpha1.NamespaceMap, n *corev1.Node) error {
+	original := nm.DeepCopy()
+	references := []metav1.OwnerReference{}
+	for _, reference := range nm.GetOwnerReferences() {
+		if reference.UID != n.GetUID() {
+			references = append(references, reference)
+		}
+	}
+
+	if len(references) == len(nm.GetOwnerReferences()) {
+		return nil
+	}
+
+	nm.SetOwnerReferences(references)
+	if err := r.Patch(ctx, nm, client.MergeFrom(original)); err != nil {
+		klog.Errorf("%s -> unable to remove the owner reference of virtual node %q from NamespaceMap %q", err, n.GetName(), nm.GetName())
+		return err
+	}
+	return nil
+}
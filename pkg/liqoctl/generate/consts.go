@@ -28,3 +28,6 @@ const LiqoctlGenerateAddCommand = "generate-add-command"
 // liqoctlGenerateRemindInstall embeds a message to remind Liqo as requirement to use liqoctl generate-add command.
 const liqoctlGenerateRemindInstall = "Notice: 'liqoctl generate-add-command' requires Liqo to be installed on both clusters " +
 	"involved in the peering process.\n"
+
+// tokenNameLength is the length of the names randomly generated for the named tokens.
+const tokenNameLength = 8
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
@@ -139,7 +140,7 @@ var _ = Describe("Test the generate command works as expected", func() {
 	DescribeTable("A generate command is performed",
 		func(deployArgs []string, expected string) {
 			k8sClient = setUpEnvironment(liqoNamespace, localClusterID, clusterName, token, deployArgs)
-			Expect(processGenerateCommand(ctx, k8sClient, liqoNamespace, "liqoctl")).To(BeIdenticalTo(expected))
+			Expect(processGenerateCommand(ctx, k8sClient, liqoNamespace, "liqoctl", &TokenOptions{})).To(BeIdenticalTo(expected))
 		},
 		Entry("Default authentication service endpoint",
 			[]string{fmt.Sprintf("--%v=%v", consts.ClusterNameParameter, clusterName)},
@@ -154,4 +155,22 @@ var _ = Describe("Test the generate command works as expected", func() {
 			commandName+" add cluster "+clusterName+" --auth-url https://foo.bar.com:8443 --id "+localClusterID+" --token "+token,
 		),
 	)
+
+	It("A generate command with a one-shot token is performed", func() {
+		k8sClient = setUpEnvironment(liqoNamespace, localClusterID, clusterName, token,
+			[]string{fmt.Sprintf("--%v=%v", consts.ClusterNameParameter, clusterName)})
+		options := &TokenOptions{Name: "one-shot", OneShot: true, TTL: time.Hour}
+
+		command, err := processGenerateCommand(ctx, k8sClient, liqoNamespace, "liqoctl", options)
+		Expect(err).ToNot(HaveOccurred())
+
+		var secret corev1.Secret
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Name: auth.NamedTokenSecretName("one-shot"), Namespace: liqoNamespace}, &secret)).To(Succeed())
+		generated, err := auth.NamedTokenFromSecret(&secret)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(generated.MaxUsages).To(Equal(1))
+		Expect(generated.Expiration).To(BeTemporally("~", time.Now().Add(time.Hour), time.Minute))
+		Expect(command).To(Equal(commandName + " add cluster " + clusterName + " --auth-url https://" + authEndpoint +
+			" --id " + localClusterID + " --token " + generated.Value))
+	})
 })
@@ -19,8 +19,10 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/liqotech/liqo/pkg/auth"
@@ -32,8 +34,27 @@ import (
 	foreigncluster "github.com/liqotech/liqo/pkg/utils/foreignCluster"
 )
 
+// TokenOptions contains the restrictions of the named token to be generated for the add command.
+// If no restriction is set, the cluster-wide token is used instead.
+type TokenOptions struct {
+	// Name is the name of the generated token (randomly generated if empty).
+	Name string
+	// OneShot restricts the token to a single usage.
+	OneShot bool
+	// TTL is the validity of the token (no expiration if zero).
+	TTL time.Duration
+	// AllowedClusterID restricts the token to the given remote cluster.
+	AllowedClusterID string
+}
+
+// isSet returns whether a named token has to be generated.
+func (opts *TokenOptions) isSet() bool {
+	return opts.Name != "" || opts.OneShot || opts.TTL > 0 || opts.AllowedClusterID != ""
+}
+
 // HandleGenerateAddCommand outputs the liqoctl add command to use to add the target cluster.
-func HandleGenerateAddCommand(ctx context.Context, liqoNamespace string, printOnlyCommand bool, commandName string) error {
+func HandleGenerateAddCommand(ctx context.Context, liqoNamespace string, printOnlyCommand bool, commandName string,
+	tokenOptions *TokenOptions) error {
 	restConfig, err := common.GetLiqoctlRestConf()
 	if err != nil {
 		print(liqoctlGenerateRemindInstall)
@@ -46,7 +67,7 @@ func HandleGenerateAddCommand(ctx context.Context, liqoNamespace string, printOn
 		return err
 	}
 
-	commandString, err := processGenerateCommand(ctx, clientSet, liqoNamespace, commandName)
+	commandString, err := processGenerateCommand(ctx, clientSet, liqoNamespace, commandName, tokenOptions)
 	if err != nil {
 		print(liqoctlGenerateRemindInstall)
 		return err
@@ -57,12 +78,17 @@ func HandleGenerateAddCommand(ctx context.Context, liqoNamespace string, printOn
 	} else {
 		fmt.Printf("\nUse this command on a DIFFERENT cluster to enable an outgoing peering WITH THE CURRENT cluster 🛠:\n\n")
 		fmt.Printf("%s\n\n", commandString)
+		if tokenOptions.isSet() {
+			fmt.Printf("The command embeds a dedicated token, which can be revoked (without affecting the established peerings) with:\n\n")
+			fmt.Printf("kubectl delete secret --namespace %s %s\n\n", liqoNamespace, auth.NamedTokenSecretName(tokenOptions.Name))
+		}
 	}
 	return nil
 }
 
-func processGenerateCommand(ctx context.Context, clientSet client.Client, liqoNamespace, commandName string) (string, error) {
-	localToken, err := auth.GetToken(ctx, clientSet, liqoNamespace)
+func processGenerateCommand(ctx context.Context, clientSet client.Client, liqoNamespace, commandName string,
+	tokenOptions *TokenOptions) (string, error) {
+	localToken, err := retrieveToken(ctx, clientSet, liqoNamespace, tokenOptions)
 	if err != nil {
 		return "", err
 	}
@@ -92,6 +118,28 @@ func processGenerateCommand(ctx context.Context, clientSet client.Client, liqoNa
 	return generateCommandString(commandName, authEP, clusterID, localToken, clusterName), nil
 }
 
+// retrieveToken returns the token to be embedded in the add command, generating a new named token if requested.
+func retrieveToken(ctx context.Context, clientSet client.Client, liqoNamespace string, tokenOptions *TokenOptions) (string, error) {
+	if !tokenOptions.isSet() {
+		return auth.GetToken(ctx, clientSet, liqoNamespace)
+	}
+
+	if tokenOptions.Name == "" {
+		tokenOptions.Name = rand.String(tokenNameLength)
+	}
+	maxUsages := 0
+	if tokenOptions.OneShot {
+		maxUsages = 1
+	}
+
+	token, err := auth.CreateNamedToken(ctx, clientSet, liqoNamespace, tokenOptions.Name,
+		tokenOptions.TTL, maxUsages, tokenOptions.AllowedClusterID)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate the token: %w", err)
+	}
+	return token.Value, nil
+}
+
 func generateCommandString(commandName, authEP, clusterID, localToken, clusterName string) string {
 	// If the local cluster has not clusterName, we print the local clusterID to not leave this field empty.
 	// This can be changed bt the user when pasting this value in a remote cluster.
@@ -121,7 +121,7 @@ func HandleInstallCommand(ctx context.Context, cmd *cobra.Command, baseCommand,
 	switch {
 	case !commonArgs.DumpValues && !commonArgs.DryRun:
 		s.Success("All Set! You can use Liqo now!")
-		return generate.HandleGenerateAddCommand(ctx, installutils.LiqoNamespace, false, baseCommand)
+		return generate.HandleGenerateAddCommand(ctx, installutils.LiqoNamespace, false, baseCommand, &generate.TokenOptions{})
 	case commonArgs.DumpValues:
 		s.Success(fmt.Sprintf("All Set! Chart values written in file %s", commonArgs.DumpValuesPath))
 	case commonArgs.DryRun:
@@ -108,9 +108,17 @@ func ValidateCommonArguments(providerName string, flags *flag.FlagSet, s *pterm.
 	if err != nil {
 		return nil, err
 	}
+	oidcAudience, err := flags.GetString("oidc-audience")
+	if err != nil {
+		return nil, err
+	}
+	oidcTokenExpiration, err := flags.GetDuration("oidc-token-expiration")
+	if err != nil {
+		return nil, err
+	}
 	commonValues, tmpDir, err := parseCommonValues(providerName, &chartPath, repoURL, version,
-		resourceSharingPercentage, downloadChart, lanDiscovery, enableHa,
-		ifaceMTU, listeningPort, s)
+		resourceSharingPercentage, oidcAudience, downloadChart, lanDiscovery, enableHa,
+		ifaceMTU, listeningPort, oidcTokenExpiration, s)
 	if err != nil {
 		return nil, err
 	}
@@ -130,9 +138,9 @@ func ValidateCommonArguments(providerName string, flags *flag.FlagSet, s *pterm.
 	}, nil
 }
 
-func parseCommonValues(providerName string, chartPath *string, repoURL, version, resourceSharingPercentage string,
+func parseCommonValues(providerName string, chartPath *string, repoURL, version, resourceSharingPercentage, oidcAudience string,
 	downloadChart, lanDiscovery, enableHa bool,
-	mtu, port int, s *pterm.SpinnerPrinter) (values map[string]interface{}, tmpDir string, err error) {
+	mtu, port int, oidcTokenExpiration time.Duration, s *pterm.SpinnerPrinter) (values map[string]interface{}, tmpDir string, err error) {
 	if chartPath == nil {
 		chartPath = pointer.String(installutils.LiqoChartFullName)
 	}
@@ -188,6 +196,10 @@ func parseCommonValues(providerName string, chartPath *string, repoURL, version,
 			// The value is converted to float64 to match the type returned by the helm client.
 			"mtu": float64(mtu),
 		},
+		"oidcConfig": map[string]interface{}{
+			"audience":        oidcAudience,
+			"tokenExpiration": oidcTokenExpiration.String(),
+		},
 	}, tmpDir, nil
 }
 
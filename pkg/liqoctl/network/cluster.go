@@ -0,0 +1,150 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
+	liqoconsts "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqoctl/common"
+	liqogetters "github.com/liqotech/liqo/pkg/utils/getters"
+	liqolabels "github.com/liqotech/liqo/pkg/utils/labels"
+)
+
+// clusterView holds the networking resources of one of the two diagnosed clusters, concerning the other one.
+type clusterView struct {
+	name       string
+	namespace  string
+	restConfig *rest.Config
+	client     client.Client
+	identity   *discoveryv1alpha1.ClusterIdentity
+
+	// localNetworkConfig is the NetworkConfig created by the local cluster and replicated to the remote one.
+	localNetworkConfig *netv1alpha1.NetworkConfig
+	// remoteNetworkConfig is the NetworkConfig replicated by the remote cluster.
+	remoteNetworkConfig *netv1alpha1.NetworkConfig
+	tunnelEndpoint      *netv1alpha1.TunnelEndpoint
+	natMapping          *netv1alpha1.NatMapping
+}
+
+// newClusterView returns a new clusterView for the cluster identified by the given kubeconfig,
+// retrieving the identity of the cluster itself.
+func newClusterView(ctx context.Context, kubeconfig, namespace, name string) (*clusterView, error) {
+	restConfig, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create rest config from kubeconfig {%s}: %w", kubeconfig, err)
+	}
+	cl, err := client.New(restConfig, client.Options{Scheme: common.Scheme})
+	if err != nil {
+		return nil, fmt.Errorf("unable to create controller runtime client from kubeconfig {%s}: %w", kubeconfig, err)
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&liqolabels.ClusterIDConfigMapLabelSelector)
+	if err != nil {
+		return nil, err
+	}
+	cm, err := liqogetters.GetConfigMapByLabel(ctx, cl, namespace, selector)
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred while retrieving the identity of %s: %w", name, err)
+	}
+	identity, err := liqogetters.RetrieveClusterIDFromConfigMap(cm)
+	if err != nil {
+		return nil, fmt.Errorf("an error occurred while retrieving the identity of %s: %w", name, err)
+	}
+
+	return &clusterView{
+		name:       name,
+		namespace:  namespace,
+		restConfig: restConfig,
+		client:     cl,
+		identity:   identity,
+	}, nil
+}
+
+// collect retrieves the networking resources concerning the given remote cluster.
+// The resources which do not exist are left unset, while any other error is returned.
+func (cv *clusterView) collect(ctx context.Context, remoteClusterID string) error {
+	var err error
+
+	if cv.localNetworkConfig, err = cv.getNetworkConfig(ctx, liqoconsts.ReplicationDestinationLabel, remoteClusterID); err != nil {
+		return fmt.Errorf("an error occurred while retrieving the local NetworkConfig: %w", err)
+	}
+	if cv.remoteNetworkConfig, err = cv.getNetworkConfig(ctx, liqoconsts.ReplicationOriginLabel, remoteClusterID); err != nil {
+		return fmt.Errorf("an error occurred while retrieving the remote NetworkConfig: %w", err)
+	}
+
+	teps := &netv1alpha1.TunnelEndpointList{}
+	if err = cv.client.List(ctx, teps, client.MatchingLabels{liqoconsts.ClusterIDLabelName: remoteClusterID}); err != nil {
+		return fmt.Errorf("an error occurred while retrieving the TunnelEndpoint: %w", err)
+	}
+	switch len(teps.Items) {
+	case 0:
+		cv.tunnelEndpoint = nil
+	case 1:
+		cv.tunnelEndpoint = &teps.Items[0]
+	default:
+		return fmt.Errorf("multiple TunnelEndpoints found for remote cluster %s", remoteClusterID)
+	}
+
+	natMappings := &netv1alpha1.NatMappingList{}
+	if err = cv.client.List(ctx, natMappings, client.MatchingLabels{
+		liqoconsts.NatMappingResourceLabelKey: liqoconsts.NatMappingResourceLabelValue,
+		liqoconsts.ClusterIDLabelName:         remoteClusterID,
+	}); err != nil {
+		return fmt.Errorf("an error occurred while retrieving the NatMapping: %w", err)
+	}
+	switch len(natMappings.Items) {
+	case 0:
+		cv.natMapping = nil
+	case 1:
+		cv.natMapping = &natMappings.Items[0]
+	default:
+		return fmt.Errorf("multiple NatMappings found for remote cluster %s", remoteClusterID)
+	}
+
+	return nil
+}
+
+// getNetworkConfig returns the NetworkConfig characterized by the given label key and remote cluster, or nil if not found.
+func (cv *clusterView) getNetworkConfig(ctx context.Context, labelKey, remoteClusterID string) (*netv1alpha1.NetworkConfig, error) {
+	selector := labels.SelectorFromSet(labels.Set{labelKey: remoteClusterID})
+	netcfg, err := liqogetters.GetNetworkConfigByLabel(ctx, cv.client, corev1.NamespaceAll, selector)
+	if kerrors.IsNotFound(err) {
+		return nil, nil
+	}
+	return netcfg, err
+}
+
+// direction is one of the two directions of a peering, expressed as a (local, remote) pair of clusters.
+type direction struct {
+	local  *clusterView
+	remote *clusterView
+}
+
+// directions returns the two directions of the peering between the given clusters.
+func directions(cluster1, cluster2 *clusterView) []direction {
+	return []direction{{local: cluster1, remote: cluster2}, {local: cluster2, remote: cluster1}}
+}
@@ -0,0 +1,125 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"context"
+	"net"
+
+	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
+	liqonetutils "github.com/liqotech/liqo/pkg/liqonet/utils"
+)
+
+const consistencyCheckerName = "network-consistency"
+
+// consistencyChecker implements the Checker interface.
+// It verifies that the CIDRs configured in the two clusters are symmetric, and that
+// the NatMappings are coherent with the corresponding TunnelEndpoints.
+type consistencyChecker struct {
+	report
+	cluster1 *clusterView
+	cluster2 *clusterView
+}
+
+func newConsistencyChecker(cluster1, cluster2 *clusterView) *consistencyChecker {
+	return &consistencyChecker{
+		report:   newReport(consistencyCheckerName),
+		cluster1: cluster1,
+		cluster2: cluster2,
+	}
+}
+
+// Collect implements the Collect method of the Checker interface.
+func (cc *consistencyChecker) Collect(_ context.Context) error {
+	for _, d := range directions(cc.cluster1, cc.cluster2) {
+		local, remote := d.local.tunnelEndpoint, d.remote.tunnelEndpoint
+		if local == nil || remote == nil || d.local.natMapping == nil {
+			continue
+		}
+
+		// The networks of the local cluster, as well as the ones remapped by the remote cluster,
+		// shall match the ones the remote cluster has been configured with for the local one.
+		cc.checkSymmetry(d, "PodCIDR", local.Spec.LocalPodCIDR, remote.Spec.RemotePodCIDR)
+		cc.checkSymmetry(d, "PodCIDR remapping", local.Spec.LocalNATPodCIDR, remote.Spec.RemoteNATPodCIDR)
+		cc.checkSymmetry(d, "ExternalCIDR", local.Spec.LocalExternalCIDR, remote.Spec.RemoteExternalCIDR)
+		cc.checkSymmetry(d, "ExternalCIDR remapping", local.Spec.LocalNATExternalCIDR, remote.Spec.RemoteNATExternalCIDR)
+		_, localDualStack := liqonetutils.GetIPv6View(local)
+		_, remoteDualStack := liqonetutils.GetIPv6View(remote)
+		if localDualStack || remoteDualStack {
+			cc.checkSymmetry(d, "PodCIDR (IPv6)", local.Spec.LocalPodCIDRv6, remote.Spec.RemotePodCIDRv6)
+			cc.checkSymmetry(d, "PodCIDR remapping (IPv6)", local.Spec.LocalNATPodCIDRv6, remote.Spec.RemoteNATPodCIDRv6)
+			cc.checkSymmetry(d, "ExternalCIDR (IPv6)", local.Spec.LocalExternalCIDRv6, remote.Spec.RemoteExternalCIDRv6)
+			cc.checkSymmetry(d, "ExternalCIDR remapping (IPv6)", local.Spec.LocalNATExternalCIDRv6, remote.Spec.RemoteNATExternalCIDRv6)
+		}
+
+		cc.checkNatMapping(d.local.name, d.local.natMapping, local)
+	}
+
+	return nil
+}
+
+// checkSymmetry verifies that the value configured in the local cluster matches the one configured in the remote cluster.
+func (cc *consistencyChecker) checkSymmetry(d direction, hop, localValue, remoteValue string) {
+	if localValue != remoteValue {
+		cc.addFailure(d.local.name, hop, "%s is configured with %s, while %s with %s", d.local.name, localValue, d.remote.name, remoteValue)
+		return
+	}
+	cc.addSuccess(d.local.name, hop, "%s (symmetric with %s)", localValue, d.remote.name)
+}
+
+// checkNatMapping verifies that the networks and the mappings of the NatMapping are coherent with the given TunnelEndpoint.
+func (cc *consistencyChecker) checkNatMapping(cluster string, natMapping *netv1alpha1.NatMapping, tep *netv1alpha1.TunnelEndpoint) {
+	_, remotePodCIDR := liqonetutils.GetPodCIDRS(tep)
+	localExternalCIDR, _ := liqonetutils.GetExternalCIDRS(tep)
+	cc.checkNatMappingNetwork(cluster, "NatMapping PodCIDR", natMapping.Spec.PodCIDR, remotePodCIDR)
+	cc.checkNatMappingNetwork(cluster, "NatMapping ExternalCIDR", natMapping.Spec.ExternalCIDR, localExternalCIDR)
+	externalCIDRs := []string{localExternalCIDR}
+
+	if tepv6, ok := liqonetutils.GetIPv6View(tep); ok {
+		_, remotePodCIDRv6 := liqonetutils.GetPodCIDRS(tepv6)
+		localExternalCIDRv6, _ := liqonetutils.GetExternalCIDRS(tepv6)
+		cc.checkNatMappingNetwork(cluster, "NatMapping PodCIDR (IPv6)", natMapping.Spec.PodCIDRv6, remotePodCIDRv6)
+		cc.checkNatMappingNetwork(cluster, "NatMapping ExternalCIDR (IPv6)", natMapping.Spec.ExternalCIDRv6, localExternalCIDRv6)
+		externalCIDRs = append(externalCIDRs, localExternalCIDRv6)
+	}
+
+	for oldIP, newIP := range natMapping.Spec.ClusterMappings {
+		if !containedInAny(newIP, externalCIDRs) {
+			cc.addFailure(cluster, "NatMapping mappings", "%s is mapped to %s, which does not belong to the ExternalCIDR", oldIP, newIP)
+			return
+		}
+	}
+	cc.addSuccess(cluster, "NatMapping mappings", "%d mappings belonging to the ExternalCIDR", len(natMapping.Spec.ClusterMappings))
+}
+
+// checkNatMappingNetwork verifies that the network configured in the NatMapping matches the expected one.
+func (cc *consistencyChecker) checkNatMappingNetwork(cluster, hop, configured, expected string) {
+	if configured != expected {
+		cc.addFailure(cluster, hop, "%s configured, while %s expected according to the TunnelEndpoint", configured, expected)
+		return
+	}
+	cc.addSuccess(cluster, hop, "%s", configured)
+}
+
+// containedInAny returns whether the given IP address belongs to any of the given networks.
+func containedInAny(ip string, networks []string) bool {
+	parsed := net.ParseIP(ip)
+	for _, network := range networks {
+		if _, cidr, err := net.ParseCIDR(network); err == nil && parsed != nil && cidr.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
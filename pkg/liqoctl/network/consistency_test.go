@@ -0,0 +1,132 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
+	liqoconsts "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqoctl/common"
+)
+
+var _ = Describe("Consistency", func() {
+	var (
+		cluster1, cluster2 *clusterView
+		checker            *consistencyChecker
+	)
+
+	BeforeEach(func() {
+		cluster1 = &clusterView{
+			name: common.Cluster1Name,
+			tunnelEndpoint: &netv1alpha1.TunnelEndpoint{Spec: netv1alpha1.TunnelEndpointSpec{
+				LocalPodCIDR: "10.0.0.0/16", LocalNATPodCIDR: "10.1.0.0/16",
+				LocalExternalCIDR: "10.2.0.0/16", LocalNATExternalCIDR: liqoconsts.DefaultCIDRValue,
+				RemotePodCIDR: "10.0.0.0/16", RemoteNATPodCIDR: "10.3.0.0/16",
+				RemoteExternalCIDR: "10.4.0.0/16", RemoteNATExternalCIDR: liqoconsts.DefaultCIDRValue,
+			}},
+			natMapping: &netv1alpha1.NatMapping{Spec: netv1alpha1.NatMappingSpec{
+				PodCIDR: "10.3.0.0/16", ExternalCIDR: "10.2.0.0/16",
+				ClusterMappings: netv1alpha1.Mappings{"10.0.0.8": "10.2.0.1"},
+			}},
+		}
+		cluster2 = &clusterView{
+			name: common.Cluster2Name,
+			tunnelEndpoint: &netv1alpha1.TunnelEndpoint{Spec: netv1alpha1.TunnelEndpointSpec{
+				LocalPodCIDR: "10.0.0.0/16", LocalNATPodCIDR: "10.3.0.0/16",
+				LocalExternalCIDR: "10.4.0.0/16", LocalNATExternalCIDR: liqoconsts.DefaultCIDRValue,
+				RemotePodCIDR: "10.0.0.0/16", RemoteNATPodCIDR: "10.1.0.0/16",
+				RemoteExternalCIDR: "10.2.0.0/16", RemoteNATExternalCIDR: liqoconsts.DefaultCIDRValue,
+			}},
+			natMapping: &netv1alpha1.NatMapping{Spec: netv1alpha1.NatMappingSpec{
+				PodCIDR: "10.1.0.0/16", ExternalCIDR: "10.4.0.0/16",
+				ClusterMappings: netv1alpha1.Mappings{},
+			}},
+		}
+	})
+
+	JustBeforeEach(func() {
+		checker = newConsistencyChecker(cluster1, cluster2)
+		Expect(checker.Collect(context.Background())).To(Succeed())
+	})
+
+	failures := func() []hopResult {
+		var out []hopResult
+		for _, result := range checker.results {
+			if !result.succeeded {
+				out = append(out, result)
+			}
+		}
+		return out
+	}
+
+	When("the configuration is symmetric", func() {
+		It("should succeed", func() {
+			Expect(checker.HasSucceeded()).To(BeTrue())
+			Expect(checker.results).ToNot(BeEmpty())
+		})
+	})
+
+	When("the remapping of the PodCIDR is not symmetric", func() {
+		BeforeEach(func() { cluster2.tunnelEndpoint.Spec.RemoteNATPodCIDR = "10.5.0.0/16" })
+
+		It("should report the failures", func() {
+			Expect(failures()).To(HaveLen(2))
+			Expect(failures()[0].cluster).To(Equal(common.Cluster1Name))
+			Expect(failures()[0].hop).To(Equal("PodCIDR remapping"))
+			// The NatMapping of the second cluster is no longer coherent with its TunnelEndpoint as well.
+			Expect(failures()[1].cluster).To(Equal(common.Cluster2Name))
+			Expect(failures()[1].hop).To(Equal("NatMapping PodCIDR"))
+		})
+	})
+
+	When("the NatMapping does not match the TunnelEndpoint", func() {
+		BeforeEach(func() { cluster1.natMapping.Spec.PodCIDR = "10.0.0.0/16" })
+
+		It("should report the failure", func() {
+			Expect(failures()).To(HaveLen(1))
+			Expect(failures()[0].hop).To(Equal("NatMapping PodCIDR"))
+		})
+	})
+
+	When("a mapping does not belong to the ExternalCIDR", func() {
+		BeforeEach(func() { cluster1.natMapping.Spec.ClusterMappings["10.0.0.9"] = "10.9.0.1" })
+
+		It("should report the failure", func() {
+			Expect(failures()).To(HaveLen(1))
+			Expect(failures()[0].hop).To(Equal("NatMapping mappings"))
+		})
+	})
+
+	When("the TunnelEndpoint of one cluster is missing", func() {
+		BeforeEach(func() { cluster2.tunnelEndpoint = nil })
+
+		It("should skip the checks", func() { Expect(checker.results).To(BeEmpty()) })
+	})
+
+	DescribeTable("containedInAny",
+		func(ip string, networks []string, expected bool) {
+			Expect(containedInAny(ip, networks)).To(Equal(expected))
+		},
+		Entry("IPv4 address contained", "10.0.0.1", []string{"10.0.0.0/24"}, true),
+		Entry("IPv4 address not contained", "10.0.1.1", []string{"10.0.0.0/24"}, false),
+		Entry("IPv6 address contained in the second network", "fd00::1", []string{"10.0.0.0/24", "fd00::/64"}, true),
+		Entry("invalid address", "invalid", []string{"10.0.0.0/24"}, false),
+	)
+})
@@ -0,0 +1,51 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import "time"
+
+const (
+	// UseCommand contains the name of the network command.
+	UseCommand = "network"
+	// ShortHelp contains the short help string for liqoctl network command.
+	ShortHelp = "Troubleshoot the network fabric established between peered clusters"
+
+	// DiagnoseUseCommand contains the name of the network diagnose command.
+	DiagnoseUseCommand = "diagnose"
+	// DiagnoseShortHelp contains the short help string for liqoctl network diagnose command.
+	DiagnoseShortHelp = "Diagnose the network connectivity between two peered clusters"
+	// DiagnoseLongHelp contains the Long help string for liqoctl network diagnose command.
+	DiagnoseLongHelp = `
+Diagnose the network connectivity between two peered clusters.
+
+The command collects the networking resources (i.e., NetworkConfigs, TunnelEndpoints
+and NatMappings) concerning the peering from both clusters, and verifies that the
+CIDRs and the NAT mappings configured on the two sides are symmetric. Then, it checks
+the status of the tunnel, inspects the iptables chains and the routing tables configured
+by the gateway and route pods, and launches ephemeral probe pods to test the pod-to-pod
+and the pod-to-external-CIDR reachability in both directions.
+
+$ liqoctl network diagnose --config1 kubeconfig-cluster1 --config2 kubeconfig-cluster2
+`
+
+	// DefaultProbeImage is the default image used for the probe pods.
+	DefaultProbeImage = "busybox:1.35"
+	// DefaultProbeTimeout is the default maximum time waited for each probe to complete.
+	DefaultProbeTimeout = 2 * time.Minute
+
+	redCross  = "\u274c"
+	checkMark = "\u2714"
+	skipMark  = "\u2796"
+)
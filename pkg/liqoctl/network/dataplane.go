@@ -0,0 +1,244 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	liqoconsts "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqonet/iptables"
+	liqonetutils "github.com/liqotech/liqo/pkg/liqonet/utils"
+	liqolabels "github.com/liqotech/liqo/pkg/utils/labels"
+)
+
+const (
+	dataplaneCheckerName = "network-dataplane"
+
+	// gatewayNetnsPath is the path of the custom network namespace created by the gateway, in its own mount namespace.
+	gatewayNetnsPath = "/var/run/netns/" + liqoconsts.GatewayNetnsName
+)
+
+// podExecutor executes the given command in the given pod, and returns its standard output.
+type podExecutor func(ctx context.Context, cv *clusterView, pod *corev1.Pod, command ...string) (string, error)
+
+// dataplaneChecker implements the Checker interface.
+// It inspects the iptables chains and the routing tables configured by the gateway and the route pods of both clusters,
+// to verify that the entries concerning the peered cluster are present.
+type dataplaneChecker struct {
+	report
+	cluster1 *clusterView
+	cluster2 *clusterView
+	exec     podExecutor
+}
+
+func newDataplaneChecker(cluster1, cluster2 *clusterView) *dataplaneChecker {
+	return &dataplaneChecker{
+		report:   newReport(dataplaneCheckerName),
+		cluster1: cluster1,
+		cluster2: cluster2,
+		exec:     execInPod,
+	}
+}
+
+// Collect implements the Collect method of the Checker interface.
+func (dc *dataplaneChecker) Collect(ctx context.Context) error {
+	for _, d := range directions(dc.cluster1, dc.cluster2) {
+		if d.local.tunnelEndpoint == nil {
+			continue
+		}
+
+		// The remote CIDRs, as seen by the local cluster, are the destinations of the routes towards the remote cluster.
+		_, remotePodCIDR := liqonetutils.GetPodCIDRS(d.local.tunnelEndpoint)
+		_, remoteExternalCIDR := liqonetutils.GetExternalCIDRS(d.local.tunnelEndpoint)
+		destinations := []string{remotePodCIDR, remoteExternalCIDR}
+
+		dc.checkGateway(ctx, d, destinations)
+		dc.checkNodes(ctx, d, destinations)
+	}
+	return nil
+}
+
+// checkGateway verifies the iptables chains and the routes configured in the network namespace of the active gateway.
+func (dc *dataplaneChecker) checkGateway(ctx context.Context, d direction, destinations []string) {
+	pods, err := listPods(ctx, d.local, &liqolabels.ActiveGatewayPodLabelSelector)
+	switch {
+	case err != nil:
+		dc.addFailure(d.local.name, "gateway", "failed to retrieve the active gateway pod: %v", err)
+		return
+	case len(pods) == 0:
+		dc.addFailure(d.local.name, "gateway", "no active gateway pod found")
+		return
+	}
+
+	gateway := &pods[0]
+	hop := fmt.Sprintf("gateway %s (iptables)", gateway.Name)
+	rules, err := dc.exec(ctx, d.local, gateway, "nsenter", "--net="+gatewayNetnsPath, "iptables-save")
+	if err != nil {
+		dc.addFailure(d.local.name, hop, "failed to retrieve the iptables rules: %v", err)
+	} else if missing := missingChains(rules, iptables.ChainsPerCluster(d.remote.identity.ClusterID)); len(missing) > 0 {
+		dc.addFailure(d.local.name, hop, "missing chains %s", strings.Join(missing, ", "))
+	} else {
+		dc.addSuccess(d.local.name, hop, "chains concerning %s present", d.remote.name)
+	}
+
+	routes, err := dc.exec(ctx, d.local, gateway, append([]string{"nsenter", "--net=" + gatewayNetnsPath}, showRoutesCommand()...)...)
+	dc.verifyRoutes(d.local.name, fmt.Sprintf("gateway %s (routes)", gateway.Name), routes, err, destinations)
+}
+
+// checkNodes verifies the routes configured by the route pods in the host network namespace of each node.
+func (dc *dataplaneChecker) checkNodes(ctx context.Context, d direction, destinations []string) {
+	pods, err := listPods(ctx, d.local, &liqolabels.RoutePodLabelSelector)
+	switch {
+	case err != nil:
+		dc.addFailure(d.local.name, "nodes", "failed to retrieve the route pods: %v", err)
+		return
+	case len(pods) == 0:
+		dc.addFailure(d.local.name, "nodes", "no route pod found")
+		return
+	}
+
+	for i := range pods {
+		routes, err := dc.exec(ctx, d.local, &pods[i], showRoutesCommand()...)
+		dc.verifyRoutes(d.local.name, fmt.Sprintf("node %s (routes)", pods[i].Spec.NodeName), routes, err, destinations)
+	}
+}
+
+// verifyRoutes records the outcome of the verification of the routes retrieved for the given hop.
+func (dc *dataplaneChecker) verifyRoutes(cluster, hop, routes string, err error, destinations []string) {
+	if err != nil {
+		dc.addFailure(cluster, hop, "failed to retrieve the routes: %v", err)
+		return
+	}
+	if missing := missingRoutes(routes, destinations); len(missing) > 0 {
+		dc.addFailure(cluster, hop, "missing routes towards %s in table %d", strings.Join(missing, ", "), liqoconsts.RoutingTableID)
+		return
+	}
+	dc.addSuccess(cluster, hop, "routes towards %s present in table %d", strings.Join(destinations, ", "), liqoconsts.RoutingTableID)
+}
+
+// showRoutesCommand returns the command to retrieve the routes configured by Liqo.
+func showRoutesCommand() []string {
+	return []string{"ip", "route", "show", "table", strconv.Itoa(liqoconsts.RoutingTableID)}
+}
+
+// missingChains returns the names of the given chains (mapped to the corresponding tables) which are not
+// present in the given iptables-save output, sorted alphabetically.
+func missingChains(rules string, chains map[string]string) []string {
+	present := make(map[string]struct{})
+	table := ""
+	for _, line := range strings.Split(rules, "\n") {
+		switch {
+		case strings.HasPrefix(line, "*"):
+			table = strings.TrimPrefix(line, "*")
+		case strings.HasPrefix(line, ":"):
+			present[table+"/"+strings.Fields(strings.TrimPrefix(line, ":"))[0]] = struct{}{}
+		}
+	}
+
+	var missing []string
+	for chain, table := range chains {
+		if _, found := present[table+"/"+chain]; !found {
+			missing = append(missing, chain)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// missingRoutes returns the given destinations for which no route is present in the given "ip route" output.
+func missingRoutes(routes string, destinations []string) []string {
+	present := make(map[string]struct{})
+	for _, line := range strings.Split(routes, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if _, network, err := net.ParseCIDR(fields[0]); err == nil {
+			present[network.String()] = struct{}{}
+		}
+	}
+
+	var missing []string
+	for _, destination := range destinations {
+		if _, network, err := net.ParseCIDR(destination); err != nil {
+			missing = append(missing, destination)
+		} else if _, found := present[network.String()]; !found {
+			missing = append(missing, destination)
+		}
+	}
+	return missing
+}
+
+// listPods returns the pods in the Liqo namespace of the given cluster matching the given selector.
+func listPods(ctx context.Context, cv *clusterView, selector *metav1.LabelSelector) ([]corev1.Pod, error) {
+	sel, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	var pods corev1.PodList
+	if err := cv.client.List(ctx, &pods, client.InNamespace(cv.namespace), client.MatchingLabelsSelector{Selector: sel}); err != nil {
+		return nil, err
+	}
+	return pods.Items, nil
+}
+
+// execInPod executes the given command in the first container of the given pod, and returns its standard output.
+func execInPod(_ context.Context, cv *clusterView, pod *corev1.Pod, command ...string) (string, error) {
+	clientset, err := kubernetes.NewForConfig(cv.restConfig)
+	if err != nil {
+		return "", err
+	}
+
+	request := clientset.CoreV1().RESTClient().Post().
+		Resource(corev1.ResourcePods.String()).
+		Namespace(pod.Namespace).
+		Name(pod.Name).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: pod.Spec.Containers[0].Name,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	exec, err := remotecommand.NewSPDYExecutor(cv.restConfig, http.MethodPost, request.URL())
+	if err != nil {
+		return "", err
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := exec.Stream(remotecommand.StreamOptions{Stdout: &stdout, Stderr: &stderr}); err != nil {
+		if stderr.Len() > 0 {
+			return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		return "", err
+	}
+	return stdout.String(), nil
+}
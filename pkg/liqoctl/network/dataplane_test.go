@@ -0,0 +1,190 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
+	liqoconsts "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqoctl/common"
+	"github.com/liqotech/liqo/pkg/liqonet/iptables"
+)
+
+var _ = Describe("Dataplane", func() {
+	const (
+		namespace       = "liqo"
+		remoteClusterID = "remote-cluster-id"
+	)
+
+	var (
+		objects  []client.Object
+		rules    string
+		routes   map[string]string
+		execErr  error
+		cluster1 *clusterView
+		cluster2 *clusterView
+		checker  *dataplaneChecker
+	)
+
+	Pod := func(name, app, node string, labels map[string]string) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{liqoconsts.K8sAppNameKey: app}},
+			Spec:       corev1.PodSpec{NodeName: node, Containers: []corev1.Container{{Name: app}}},
+		}
+		for key, value := range labels {
+			pod.Labels[key] = value
+		}
+		return pod
+	}
+
+	IPTablesSave := func(chains map[string]string) string {
+		var builder strings.Builder
+		for _, table := range []string{"nat", "filter"} {
+			fmt.Fprintf(&builder, "*%s\n:PREROUTING ACCEPT [0:0]\n", table)
+			for chain, chainTable := range chains {
+				if chainTable == table {
+					fmt.Fprintf(&builder, ":%s - [0:0]\n", chain)
+				}
+			}
+			fmt.Fprintf(&builder, "COMMIT\n")
+		}
+		return builder.String()
+	}
+
+	BeforeEach(func() {
+		objects = []client.Object{
+			Pod("liqo-gateway-active", liqoconsts.GatewayAppName, "node-1",
+				map[string]string{liqoconsts.GatewayServiceLabelKey: liqoconsts.GatewayActiveLabelValue}),
+			Pod("liqo-gateway-standby", liqoconsts.GatewayAppName, "node-2", map[string]string{liqoconsts.GatewayServiceLabelKey: "standby"}),
+			Pod("liqo-route-1", liqoconsts.RouteAppName, "node-1", nil),
+			Pod("liqo-route-2", liqoconsts.RouteAppName, "node-2", nil),
+		}
+
+		rules = IPTablesSave(iptables.ChainsPerCluster(remoteClusterID))
+		routes = map[string]string{
+			"liqo-gateway-active": "10.200.0.0/16 dev liqo.wg scope link\n10.201.0.0/16 dev liqo.wg scope link\n",
+			"liqo-route-1":        "10.200.0.0/16 via 169.254.100.1 dev liqo.host\n10.201.0.0/16 via 169.254.100.1 dev liqo.host\n",
+			"liqo-route-2":        "10.200.0.0/16 via 240.1.0.1 dev liqo.vxlan onlink\n10.201.0.0/16 via 240.1.0.1 dev liqo.vxlan onlink\n",
+		}
+		execErr = nil
+	})
+
+	JustBeforeEach(func() {
+		cluster1 = &clusterView{
+			name:      common.Cluster1Name,
+			namespace: namespace,
+			client:    fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(objects...).Build(),
+			tunnelEndpoint: &netv1alpha1.TunnelEndpoint{Spec: netv1alpha1.TunnelEndpointSpec{
+				RemotePodCIDR: "10.200.0.0/16", RemoteNATPodCIDR: liqoconsts.DefaultCIDRValue,
+				RemoteExternalCIDR: "10.211.0.0/16", RemoteNATExternalCIDR: "10.201.0.0/16",
+			}},
+		}
+		cluster2 = &clusterView{
+			name:     common.Cluster2Name,
+			identity: &discoveryv1alpha1.ClusterIdentity{ClusterID: remoteClusterID},
+		}
+
+		checker = newDataplaneChecker(cluster1, cluster2)
+		checker.exec = func(_ context.Context, _ *clusterView, pod *corev1.Pod, command ...string) (string, error) {
+			if execErr != nil {
+				return "", execErr
+			}
+			if command[len(command)-1] == "iptables-save" {
+				return rules, nil
+			}
+			return routes[pod.Name], nil
+		}
+		Expect(checker.Collect(context.Background())).To(Succeed())
+	})
+
+	When("all the chains and routes are present", func() {
+		It("should succeed", func() {
+			Expect(checker.HasSucceeded()).To(BeTrue())
+			// The chains and the routes of the active gateway, and the routes of the two nodes.
+			Expect(checker.results).To(HaveLen(4))
+		})
+	})
+
+	When("a chain is missing", func() {
+		BeforeEach(func() {
+			chains := iptables.ChainsPerCluster(remoteClusterID)
+			for chain := range chains {
+				if strings.HasPrefix(chain, "LIQO-PSTRT-CLS-") {
+					delete(chains, chain)
+				}
+			}
+			rules = IPTablesSave(chains)
+		})
+
+		It("should report the failure", func() {
+			Expect(checker.HasSucceeded()).To(BeFalse())
+			Expect(checker.results[0].succeeded).To(BeFalse())
+			Expect(checker.results[0].details).To(ContainSubstring("LIQO-PSTRT-CLS-remote"))
+		})
+	})
+
+	When("a route is missing on a node", func() {
+		BeforeEach(func() { routes["liqo-route-2"] = "10.200.0.0/16 via 240.1.0.1 dev liqo.vxlan onlink\n" })
+
+		It("should report the failure", func() {
+			Expect(checker.HasSucceeded()).To(BeFalse())
+			Expect(checker.results[3].succeeded).To(BeFalse())
+			Expect(checker.results[3].hop).To(ContainSubstring("node-2"))
+			Expect(checker.results[3].details).To(ContainSubstring("10.201.0.0/16"))
+		})
+	})
+
+	When("no gateway is active", func() {
+		BeforeEach(func() { objects = objects[1:] })
+
+		It("should report the failure", func() {
+			Expect(checker.HasSucceeded()).To(BeFalse())
+			Expect(checker.results[0].details).To(ContainSubstring("no active gateway pod found"))
+		})
+	})
+
+	When("the commands cannot be executed", func() {
+		BeforeEach(func() { execErr = fmt.Errorf("forbidden") })
+
+		It("should report the failures", func() {
+			Expect(checker.HasSucceeded()).To(BeFalse())
+			for i := range checker.results {
+				Expect(checker.results[i].succeeded).To(BeFalse())
+			}
+		})
+	})
+
+	Describe("the report", func() {
+		It("should not consider the skipped verifications as failures", func() {
+			report := newReport("test")
+			report.addSuccess(common.Cluster1Name, "hop", "succeeded")
+			report.addSkipped(common.Cluster1Name, "hop", "skipped")
+			Expect(report.HasSucceeded()).To(BeTrue())
+			Expect(report.Report().Hops[1].Skipped).To(BeTrue())
+			Expect(report.Report().Hops[1].Succeeded).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,102 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/liqotech/liqo/pkg/liqoctl/common"
+	"github.com/liqotech/liqo/pkg/liqoctl/status"
+)
+
+// DiagnoseArgs flags of the network diagnose command.
+type DiagnoseArgs struct {
+	Cluster1Namespace  string
+	Cluster2Namespace  string
+	Cluster1Kubeconfig string
+	Cluster2Kubeconfig string
+
+	SkipDataplane bool
+	SkipProbes    bool
+	ProbeImage    string
+	ProbeTimeout  time.Duration
+}
+
+// Handler implements the logic of the network diagnose command.
+func (a *DiagnoseArgs) Handler(ctx context.Context) error {
+	// Check that the kubeconfigs are different.
+	if a.Cluster1Kubeconfig == a.Cluster2Kubeconfig {
+		common.ErrorPrinter.Printf("kubeconfig1 and kubeconfig2 has to be different, current value: %s", a.Cluster2Kubeconfig)
+		return fmt.Errorf("kubeconfig1 and kubeconfig2 has to be different, current value: %s", a.Cluster2Kubeconfig)
+	}
+
+	cluster1, err := newClusterView(ctx, a.Cluster1Kubeconfig, a.Cluster1Namespace, common.Cluster1Name)
+	if err != nil {
+		common.ErrorPrinter.Printf("%v", err)
+		return err
+	}
+	cluster2, err := newClusterView(ctx, a.Cluster2Kubeconfig, a.Cluster2Namespace, common.Cluster2Name)
+	if err != nil {
+		common.ErrorPrinter.Printf("%v", err)
+		return err
+	}
+
+	// The presence of the networking resources is a prerequisite for all the other checks,
+	// hence the diagnosis is aborted in case any of them is missing.
+	checkers := []status.Checker{
+		newResourceChecker(cluster1, cluster2),
+		newConsistencyChecker(cluster1, cluster2),
+		newTunnelChecker(cluster1, cluster2),
+	}
+	if !a.SkipDataplane {
+		checkers = append(checkers, newDataplaneChecker(cluster1, cluster2))
+	}
+	if !a.SkipProbes {
+		checkers = append(checkers, newProbeChecker(cluster1, cluster2, a.ProbeImage, a.ProbeTimeout))
+	}
+
+	return runCheckers(ctx, checkers)
+}
+
+// runCheckers collects and outputs the report of each checker. The execution is interrupted if the first
+// checker fails, while all the remaining ones are always executed to provide a complete picture.
+func runCheckers(ctx context.Context, checkers []status.Checker) error {
+	failed := false
+	for i, checker := range checkers {
+		if err := checker.Collect(ctx); err != nil {
+			return err
+		}
+		msg, err := checker.Format()
+		if err != nil {
+			return err
+		}
+		fmt.Print(msg)
+
+		if !checker.HasSucceeded() {
+			failed = true
+			if i == 0 {
+				break
+			}
+		}
+	}
+
+	if failed {
+		return errors.New("the network diagnosis detected one or more failures")
+	}
+	return nil
+}
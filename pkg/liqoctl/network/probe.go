@@ -0,0 +1,249 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	liqoconsts "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqoctl/common"
+	"github.com/liqotech/liqo/pkg/liqonet/ipam"
+	liqonetutils "github.com/liqotech/liqo/pkg/liqonet/utils"
+	liqolabels "github.com/liqotech/liqo/pkg/utils/labels"
+)
+
+const (
+	probeCheckerName = "network-probes"
+
+	probePodPrefix    = "liqo-network-probe-"
+	probeAppName      = "liqo-network-probe"
+	probeContainer    = "probe"
+	probePingCount    = 3
+	probePollInterval = time.Second
+)
+
+// probeChecker implements the Checker interface.
+// It launches ephemeral probe pods in both clusters, to verify the pod-to-pod and the pod-to-external-CIDR reachability.
+type probeChecker struct {
+	report
+	cluster1 *clusterView
+	cluster2 *clusterView
+	image    string
+	timeout  time.Duration
+}
+
+func newProbeChecker(cluster1, cluster2 *clusterView, image string, timeout time.Duration) *probeChecker {
+	return &probeChecker{
+		report:   newReport(probeCheckerName),
+		cluster1: cluster1,
+		cluster2: cluster2,
+		image:    image,
+		timeout:  timeout,
+	}
+}
+
+// Collect implements the Collect method of the Checker interface.
+func (pc *probeChecker) Collect(ctx context.Context) error {
+	for _, d := range directions(pc.cluster1, pc.cluster2) {
+		if d.local.tunnelEndpoint == nil {
+			continue
+		}
+		pc.probe(ctx, d)
+	}
+	return nil
+}
+
+// probe verifies the reachability of a target pod started in the remote cluster, from a pod started in the local one.
+// Additionally, it verifies the reachability of an endpoint already mapped by the remote IPAM into its ExternalCIDR.
+func (pc *probeChecker) probe(ctx context.Context, d direction) {
+	hop := fmt.Sprintf("%s -> %s", d.local.name, d.remote.name)
+
+	target, err := pc.startTarget(ctx, d.remote)
+	if target != nil {
+		defer pc.deletePod(d.remote, target)
+	}
+	if err != nil {
+		pc.addFailure(d.local.name, hop+" (pod-to-pod)", "failed to start the target pod: %v", err)
+	} else {
+		// The target pod IP is translated into the remote PodCIDR as seen by the local cluster.
+		_, remotePodCIDR := liqonetutils.GetPodCIDRS(d.local.tunnelEndpoint)
+		podIP, err := liqonetutils.MapIPToNetwork(remotePodCIDR, target.Status.PodIP)
+		if err != nil {
+			pc.addFailure(d.local.name, hop+" (pod-to-pod)", "failed to translate IP %s: %v", target.Status.PodIP, err)
+		} else {
+			pc.ping(ctx, d.local, hop+" (pod-to-pod)", podIP)
+		}
+	}
+
+	pc.probeExternalCIDR(ctx, d, hop+" (pod-to-external-CIDR)")
+}
+
+// probeExternalCIDR verifies the reachability of the remote ExternalCIDR, from a pod started in the local cluster.
+// The remote IPAM is only inspected, and not modified: the target is one of the endpoints already mapped into the
+// remote ExternalCIDR towards the local cluster, and the check is skipped if none is currently present.
+func (pc *probeChecker) probeExternalCIDR(ctx context.Context, d direction, hop string) {
+	ipamClient, cleanup, err := newIPAMClient(ctx, d.remote)
+	if err != nil {
+		pc.addFailure(d.local.name, hop, "failed to contact the IPAM of %s: %v", d.remote.name, err)
+		return
+	}
+	defer cleanup()
+
+	mappings, err := ipamClient.ListEndpointMappings(ctx, &ipam.ListEndpointMappingsRequest{ClusterID: d.local.identity.ClusterID})
+	if err != nil {
+		pc.addFailure(d.local.name, hop, "failed to retrieve the endpoint mappings of %s: %v", d.remote.name, err)
+		return
+	}
+
+	_, remoteExternalCIDR := liqonetutils.GetExternalCIDRS(d.local.tunnelEndpoint)
+	ip, found, err := externalCIDRTarget(mappings.GetMappings(), remoteExternalCIDR)
+	switch {
+	case err != nil:
+		pc.addFailure(d.local.name, hop, "failed to translate the endpoint mapping: %v", err)
+	case !found:
+		pc.addSkipped(d.local.name, hop, "skipped, as no endpoint of %s is currently mapped towards %s", d.remote.name, d.local.name)
+	default:
+		pc.ping(ctx, d.local, hop, ip)
+	}
+}
+
+// externalCIDRTarget returns the address, translated into the remote ExternalCIDR as seen by the local cluster,
+// of the first of the given endpoint mappings, and whether one is present.
+func externalCIDRTarget(mappings []*ipam.EndpointMapping, remoteExternalCIDR string) (ip string, found bool, err error) {
+	if len(mappings) == 0 {
+		return "", false, nil
+	}
+	ip, err = liqonetutils.MapIPToNetwork(remoteExternalCIDR, mappings[0].GetExternalCIDRIP())
+	if err != nil {
+		return "", false, err
+	}
+	return ip, true, nil
+}
+
+// startTarget starts the target pod in the given cluster, and waits for it to be running.
+func (pc *probeChecker) startTarget(ctx context.Context, cv *clusterView) (*corev1.Pod, error) {
+	pod := pc.probePod(cv.namespace, "sleep", fmt.Sprintf("%d", int(pc.timeout.Seconds())))
+	if err := cv.client.Create(ctx, pod); err != nil {
+		return nil, err
+	}
+
+	err := wait.PollImmediateWithContext(ctx, probePollInterval, pc.timeout, func(ctx context.Context) (bool, error) {
+		if err := cv.client.Get(ctx, client.ObjectKeyFromObject(pod), pod); err != nil {
+			return false, err
+		}
+		return pod.Status.Phase == corev1.PodRunning && pod.Status.PodIP != "", nil
+	})
+	return pod, err
+}
+
+// ping starts a pod in the given cluster to ping the given IP address, and records the outcome for the given hop.
+func (pc *probeChecker) ping(ctx context.Context, cv *clusterView, hop, ip string) {
+	pod := pc.probePod(cv.namespace, "ping", "-c", fmt.Sprintf("%d", probePingCount), "-W", "2", ip)
+	if err := cv.client.Create(ctx, pod); err != nil {
+		pc.addFailure(cv.name, hop, "failed to start the source pod: %v", err)
+		return
+	}
+	defer pc.deletePod(cv, pod)
+
+	err := wait.PollImmediateWithContext(ctx, probePollInterval, pc.timeout, func(ctx context.Context) (bool, error) {
+		if err := cv.client.Get(ctx, client.ObjectKeyFromObject(pod), pod); err != nil {
+			return false, err
+		}
+		return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed, nil
+	})
+	if err != nil {
+		pc.addFailure(cv.name, hop, "failed to wait for the source pod to complete: %v", err)
+		return
+	}
+
+	if pod.Status.Phase != corev1.PodSucceeded {
+		pc.addFailure(cv.name, hop, "%s unreachable", ip)
+		return
+	}
+	pc.addSuccess(cv.name, hop, "%s reachable", ip)
+}
+
+// deletePod deletes the given probe pod, ignoring the errors other than logging them.
+func (pc *probeChecker) deletePod(cv *clusterView, pod *corev1.Pod) {
+	// A new context is used, to ensure the pod is deleted also in case the parent one has been canceled.
+	if err := cv.client.Delete(context.Background(), pod); client.IgnoreNotFound(err) != nil {
+		common.WarningPrinter.Printf("failed to delete probe pod %s/%s in %s: %v", pod.Namespace, pod.Name, cv.name, err)
+	}
+}
+
+// probePod returns the definition of a probe pod executing the given command.
+func (pc *probeChecker) probePod(namespace string, command ...string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: probePodPrefix,
+			Namespace:    namespace,
+			Labels:       map[string]string{liqoconsts.K8sAppNameKey: probeAppName},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:    probeContainer,
+				Image:   pc.image,
+				Command: command,
+			}},
+			RestartPolicy:                 corev1.RestartPolicyNever,
+			TerminationGracePeriodSeconds: pointer.Int64(0),
+		},
+	}
+}
+
+// newIPAMClient port-forwards the IPAM service of the given cluster, and returns a client to interact with it,
+// along with a function to be invoked to release the corresponding resources.
+func newIPAMClient(ctx context.Context, cv *clusterView) (ipam.IpamClient, func(), error) {
+	pfo := &common.PortForwardOptions{
+		Namespace:     cv.namespace,
+		Selector:      &liqolabels.NetworkManagerPodLabelSelector,
+		Config:        cv.restConfig,
+		Client:        cv.client,
+		PortForwarder: &common.DefaultPortForwarder{IOStreams: genericclioptions.NewTestIOStreamsDiscard()},
+		RemotePort:    liqoconsts.NetworkManagerIpamPort,
+		StopChannel:   make(chan struct{}),
+		ReadyChannel:  make(chan struct{}),
+	}
+	if err := pfo.RunPortForward(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	dialctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	connection, err := grpc.DialContext(dialctx, fmt.Sprintf("localhost:%d", pfo.LocalPort),
+		grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	if err != nil {
+		pfo.StopPortForward()
+		return nil, nil, err
+	}
+
+	return ipam.NewIpamClient(connection), func() {
+		if err := connection.Close(); err != nil {
+			common.WarningPrinter.Printf("failed to close the connection to the IPAM of %s: %v", cv.name, err)
+		}
+		pfo.StopPortForward()
+	}, nil
+}
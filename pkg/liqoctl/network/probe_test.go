@@ -0,0 +1,61 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/liqotech/liqo/pkg/liqonet/ipam"
+)
+
+var _ = Describe("Probe", func() {
+	Describe("the externalCIDRTarget function", func() {
+		var (
+			mappings []*ipam.EndpointMapping
+			ip       string
+			found    bool
+			err      error
+		)
+
+		JustBeforeEach(func() {
+			ip, found, err = externalCIDRTarget(mappings, "10.200.0.0/16")
+		})
+
+		When("no endpoint is mapped", func() {
+			BeforeEach(func() { mappings = nil })
+
+			It("should not find any target", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(found).To(BeFalse())
+			})
+		})
+
+		When("some endpoints are mapped", func() {
+			BeforeEach(func() {
+				mappings = []*ipam.EndpointMapping{
+					{EndpointIP: "10.0.0.5", ExternalCIDRIP: "10.100.0.1"},
+					{EndpointIP: "10.0.0.6", ExternalCIDRIP: "10.100.0.2"},
+				}
+			})
+
+			It("should return the first one, translated into the given network", func() {
+				Expect(err).ToNot(HaveOccurred())
+				Expect(found).To(BeTrue())
+				Expect(ip).To(Equal("10.200.0.1"))
+			})
+		})
+	})
+})
@@ -0,0 +1,114 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/liqotech/liqo/pkg/liqoctl/status"
+)
+
+// hopResult is the outcome of the verification of a single hop.
+type hopResult struct {
+	cluster   string
+	hop       string
+	succeeded bool
+	skipped   bool
+	details   string
+}
+
+// report collects the outcome of the verifications performed by a checker,
+// and implements the Format, HasSucceeded and Report methods of the Checker interface.
+type report struct {
+	name    string
+	results []hopResult
+}
+
+func newReport(name string) report {
+	return report{name: name}
+}
+
+// addSuccess records a successful verification for the given cluster and hop.
+func (r *report) addSuccess(cluster, hop, format string, args ...interface{}) {
+	r.results = append(r.results, hopResult{cluster: cluster, hop: hop, succeeded: true, details: fmt.Sprintf(format, args...)})
+}
+
+// addSkipped records a verification which has not been performed for the given cluster and hop,
+// hence considered neither a success nor a failure.
+func (r *report) addSkipped(cluster, hop, format string, args ...interface{}) {
+	r.results = append(r.results, hopResult{cluster: cluster, hop: hop, skipped: true, details: fmt.Sprintf(format, args...)})
+}
+
+// addFailure records a failed verification for the given cluster and hop.
+func (r *report) addFailure(cluster, hop, format string, args ...interface{}) {
+	r.results = append(r.results, hopResult{cluster: cluster, hop: hop, succeeded: false, details: fmt.Sprintf(format, args...)})
+}
+
+// HasSucceeded implements the HasSucceeded method of the Checker interface.
+func (r *report) HasSucceeded() bool {
+	for i := range r.results {
+		if !r.results[i].succeeded && !r.results[i].skipped {
+			return false
+		}
+	}
+	return true
+}
+
+// Report implements the Report method of the Checker interface.
+func (r *report) Report() status.CheckerReport {
+	report := status.CheckerReport{Name: r.name, Succeeded: r.HasSucceeded()}
+	for i := range r.results {
+		result := &r.results[i]
+		report.Hops = append(report.Hops, status.HopReport{
+			Cluster:   result.cluster,
+			Hop:       result.hop,
+			Succeeded: result.succeeded,
+			Skipped:   result.skipped,
+			Details:   result.details,
+		})
+	}
+	return report
+}
+
+// Format implements the Format method of the Checker interface.
+// It outputs one line per verified hop, along with the corresponding outcome.
+func (r *report) Format() (string, error) {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 4, ' ', 0)
+
+	fmt.Fprintf(w, "%s\n", r.name)
+	fmt.Fprintf(w, "%s\n", strings.Repeat("-", len(r.name)))
+	for i := range r.results {
+		result := &r.results[i]
+		mark := checkMark
+		switch {
+		case result.skipped:
+			mark = skipMark
+		case !result.succeeded:
+			mark = redCross
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", mark, result.cluster, result.hop, result.details)
+	}
+
+	// Add a new line ad the end of the message.
+	fmt.Fprintf(w, "\n")
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"context"
+	"fmt"
+)
+
+const resourceCheckerName = "network-resources"
+
+// resourceChecker implements the Checker interface.
+// It retrieves the networking resources concerning the peering from both clusters,
+// and checks that they exist and that the NetworkConfigs have been processed.
+type resourceChecker struct {
+	report
+	cluster1 *clusterView
+	cluster2 *clusterView
+}
+
+func newResourceChecker(cluster1, cluster2 *clusterView) *resourceChecker {
+	return &resourceChecker{
+		report:   newReport(resourceCheckerName),
+		cluster1: cluster1,
+		cluster2: cluster2,
+	}
+}
+
+// Collect implements the Collect method of the Checker interface.
+func (rc *resourceChecker) Collect(ctx context.Context) error {
+	for _, d := range directions(rc.cluster1, rc.cluster2) {
+		if err := d.local.collect(ctx, d.remote.identity.ClusterID); err != nil {
+			return fmt.Errorf("%s: %w", d.local.name, err)
+		}
+
+		remoteName := d.remote.identity.ClusterName
+		switch {
+		case d.local.localNetworkConfig == nil:
+			rc.addFailure(d.local.name, "NetworkConfig (local)", "not found for remote cluster %s", remoteName)
+		case !d.local.localNetworkConfig.Status.Processed:
+			rc.addFailure(d.local.name, "NetworkConfig (local)", "not yet processed by remote cluster %s", remoteName)
+		default:
+			rc.addSuccess(d.local.name, "NetworkConfig (local)", "%s processed by remote cluster %s", d.local.localNetworkConfig.Name, remoteName)
+		}
+
+		if d.local.remoteNetworkConfig == nil {
+			rc.addFailure(d.local.name, "NetworkConfig (remote)", "not received from remote cluster %s", remoteName)
+		} else {
+			rc.addSuccess(d.local.name, "NetworkConfig (remote)", "%s received from remote cluster %s", d.local.remoteNetworkConfig.Name, remoteName)
+		}
+
+		if d.local.tunnelEndpoint == nil {
+			rc.addFailure(d.local.name, "TunnelEndpoint", "not found for remote cluster %s", remoteName)
+		} else {
+			rc.addSuccess(d.local.name, "TunnelEndpoint", "%s/%s", d.local.tunnelEndpoint.Namespace, d.local.tunnelEndpoint.Name)
+		}
+
+		if d.local.natMapping == nil {
+			rc.addFailure(d.local.name, "NatMapping", "not found for remote cluster %s", remoteName)
+		} else {
+			rc.addSuccess(d.local.name, "NatMapping", "%s", d.local.natMapping.Name)
+		}
+	}
+
+	return nil
+}
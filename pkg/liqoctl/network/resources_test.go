@@ -0,0 +1,129 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
+	liqoconsts "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqoctl/common"
+)
+
+var _ = Describe("Resources", func() {
+	const (
+		localClusterID  = "local-cluster-id"
+		remoteClusterID = "remote-cluster-id"
+		tenantNamespace = "liqo-tenant-remote"
+	)
+
+	var (
+		ctx     context.Context
+		objects []client.Object
+		local   *clusterView
+		remote  *clusterView
+		checker *resourceChecker
+		err     error
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		objects = []client.Object{
+			&netv1alpha1.NetworkConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "local", Namespace: tenantNamespace,
+					Labels: map[string]string{liqoconsts.ReplicationDestinationLabel: remoteClusterID}},
+				Status: netv1alpha1.NetworkConfigStatus{Processed: true},
+			},
+			&netv1alpha1.NetworkConfig{
+				ObjectMeta: metav1.ObjectMeta{Name: "remote", Namespace: tenantNamespace,
+					Labels: map[string]string{liqoconsts.ReplicationOriginLabel: remoteClusterID}},
+			},
+			&netv1alpha1.TunnelEndpoint{
+				ObjectMeta: metav1.ObjectMeta{Name: "tep", Namespace: tenantNamespace,
+					Labels: map[string]string{liqoconsts.ClusterIDLabelName: remoteClusterID}},
+			},
+			&netv1alpha1.NatMapping{
+				ObjectMeta: metav1.ObjectMeta{Name: "natmapping", Labels: map[string]string{
+					liqoconsts.NatMappingResourceLabelKey: liqoconsts.NatMappingResourceLabelValue,
+					liqoconsts.ClusterIDLabelName:         remoteClusterID,
+				}},
+			},
+		}
+	})
+
+	JustBeforeEach(func() {
+		local = &clusterView{
+			name:     common.Cluster1Name,
+			client:   fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(objects...).Build(),
+			identity: &discoveryv1alpha1.ClusterIdentity{ClusterID: localClusterID, ClusterName: "local"},
+		}
+		remote = &clusterView{
+			name:     common.Cluster2Name,
+			client:   fake.NewClientBuilder().WithScheme(common.Scheme).Build(),
+			identity: &discoveryv1alpha1.ClusterIdentity{ClusterID: remoteClusterID, ClusterName: "remote"},
+		}
+		checker = newResourceChecker(local, remote)
+		err = checker.Collect(ctx)
+	})
+
+	When("all the resources exist", func() {
+		It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+		It("should populate the view of the local cluster", func() {
+			Expect(local.localNetworkConfig).ToNot(BeNil())
+			Expect(local.localNetworkConfig.Name).To(Equal("local"))
+			Expect(local.remoteNetworkConfig).ToNot(BeNil())
+			Expect(local.remoteNetworkConfig.Name).To(Equal("remote"))
+			Expect(local.tunnelEndpoint).ToNot(BeNil())
+			Expect(local.natMapping).ToNot(BeNil())
+		})
+		It("should report the failures of the remote cluster only", func() {
+			Expect(checker.HasSucceeded()).To(BeFalse())
+			for _, result := range checker.results {
+				Expect(result.succeeded).To(Equal(result.cluster == common.Cluster1Name))
+			}
+		})
+	})
+
+	When("the local NetworkConfig has not been processed", func() {
+		BeforeEach(func() {
+			objects[0].(*netv1alpha1.NetworkConfig).Status.Processed = false
+		})
+
+		It("should report the failure", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(checker.results[0].cluster).To(Equal(common.Cluster1Name))
+			Expect(checker.results[0].succeeded).To(BeFalse())
+			Expect(checker.results[0].details).To(ContainSubstring("not yet processed"))
+		})
+	})
+
+	When("multiple TunnelEndpoints exist", func() {
+		BeforeEach(func() {
+			objects = append(objects, &netv1alpha1.TunnelEndpoint{
+				ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: tenantNamespace,
+					Labels: map[string]string{liqoconsts.ClusterIDLabelName: remoteClusterID}},
+			})
+		})
+
+		It("should return an error", func() { Expect(err).To(HaveOccurred()) })
+	})
+})
@@ -0,0 +1,66 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"context"
+
+	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
+)
+
+const tunnelCheckerName = "network-tunnel"
+
+// tunnelChecker implements the Checker interface.
+// It verifies the status of the tunnel reported by the TunnelEndpoints of both clusters.
+type tunnelChecker struct {
+	report
+	cluster1 *clusterView
+	cluster2 *clusterView
+}
+
+func newTunnelChecker(cluster1, cluster2 *clusterView) *tunnelChecker {
+	return &tunnelChecker{
+		report:   newReport(tunnelCheckerName),
+		cluster1: cluster1,
+		cluster2: cluster2,
+	}
+}
+
+// Collect implements the Collect method of the Checker interface.
+func (tc *tunnelChecker) Collect(_ context.Context) error {
+	for _, d := range directions(tc.cluster1, tc.cluster2) {
+		tep := d.local.tunnelEndpoint
+		if tep == nil {
+			continue
+		}
+
+		connection := &tep.Status.Connection
+		switch connection.Status {
+		case netv1alpha1.Connected:
+			if health := tep.Status.Health; health != nil {
+				tc.addSuccess(d.local.name, "tunnel", "%s (%s endpoint %s, RTT %s, packet loss %d%%)",
+					connection.Status, tep.Spec.BackendType, tep.Spec.EndpointIP, health.RTT.Duration, health.PacketLoss)
+			} else {
+				tc.addSuccess(d.local.name, "tunnel", "%s (%s endpoint %s)", connection.Status, tep.Spec.BackendType, tep.Spec.EndpointIP)
+			}
+		case "":
+			tc.addFailure(d.local.name, "tunnel", "status not yet reported")
+		default:
+			tc.addFailure(d.local.name, "tunnel", "%s: %s", connection.Status, connection.StatusMessage)
+		}
+	}
+
+	return nil
+}
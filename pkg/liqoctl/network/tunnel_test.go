@@ -0,0 +1,83 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package network
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
+	"github.com/liqotech/liqo/pkg/liqoctl/common"
+)
+
+var _ = Describe("Tunnel", func() {
+	var (
+		cluster1, cluster2 *clusterView
+		checker            *tunnelChecker
+	)
+
+	BeforeEach(func() {
+		cluster1 = &clusterView{name: common.Cluster1Name, tunnelEndpoint: &netv1alpha1.TunnelEndpoint{
+			Status: netv1alpha1.TunnelEndpointStatus{
+				Connection: netv1alpha1.Connection{Status: netv1alpha1.Connected},
+				Health:     &netv1alpha1.TunnelHealth{RTT: metav1.Duration{Duration: 5 * time.Millisecond}},
+			},
+		}}
+		cluster2 = &clusterView{name: common.Cluster2Name, tunnelEndpoint: &netv1alpha1.TunnelEndpoint{
+			Status: netv1alpha1.TunnelEndpointStatus{
+				Connection: netv1alpha1.Connection{Status: netv1alpha1.Connected},
+			},
+		}}
+	})
+
+	JustBeforeEach(func() {
+		checker = newTunnelChecker(cluster1, cluster2)
+		Expect(checker.Collect(context.Background())).To(Succeed())
+	})
+
+	When("the tunnel is connected on both sides", func() {
+		It("should succeed", func() {
+			Expect(checker.HasSucceeded()).To(BeTrue())
+			Expect(checker.results).To(HaveLen(2))
+			Expect(checker.results[0].details).To(ContainSubstring("RTT 5ms"))
+		})
+	})
+
+	When("the tunnel is degraded on one side", func() {
+		BeforeEach(func() {
+			cluster2.tunnelEndpoint.Status.Connection = netv1alpha1.Connection{
+				Status: netv1alpha1.ConnectionDegraded, StatusMessage: "packet loss exceeds the threshold"}
+		})
+
+		It("should report the failure", func() {
+			Expect(checker.HasSucceeded()).To(BeFalse())
+			Expect(checker.results[1].succeeded).To(BeFalse())
+			Expect(checker.results[1].details).To(ContainSubstring("packet loss exceeds the threshold"))
+		})
+	})
+
+	When("the status has not been reported yet", func() {
+		BeforeEach(func() { cluster1.tunnelEndpoint.Status = netv1alpha1.TunnelEndpointStatus{} })
+
+		It("should report the failure", func() {
+			Expect(checker.HasSucceeded()).To(BeFalse())
+			Expect(checker.results[0].succeeded).To(BeFalse())
+		})
+	})
+})
@@ -22,4 +22,6 @@ type Checker interface {
 	Collect(ctx context.Context) error
 	Format() (string, error)
 	HasSucceeded() bool
+	// Report returns the machine-readable representation of the collected status.
+	Report() CheckerReport
 }
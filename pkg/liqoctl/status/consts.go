@@ -23,15 +23,39 @@ const (
 Show overall status of Liqo.
 
 The command shows the status of the Liqo control plane. The command checks that
-every component is up and running.
+every component is up and running, and that no peering with the remote clusters is
+in a failed state. The command terminates with a non-zero exit code otherwise.
 
 $ liqoctl status --namespace ns-where-Liqo-is-running
+
+The status can also be serialized in a machine-readable format (either json or yaml):
+
+$ liqoctl status --namespace ns-where-Liqo-is-running --output json
 `
 	// UseCommand contains the name of the command.
 	UseCommand = "status"
 
+	// PeerShortHelp contains the short help string for liqoctl status peer command.
+	PeerShortHelp = "Show the detailed status of the peerings with remote clusters"
+	// PeerLongHelp contains the Long help string for liqoctl status peer command.
+	PeerLongHelp = `
+Show the detailed status of the peerings with remote clusters.
+
+For each ForeignCluster, the command shows the peering conditions together with
+the status of the associated ResourceRequests, ResourceOffers, TunnelEndpoint and
+virtual nodes, including the reasons and the last transition times. The output can be
+restricted to a single cluster, specifying either its name or its ID.
+
+$ liqoctl status peer
+$ liqoctl status peer remote-cluster-name --output yaml
+`
+	// PeerUseCommand contains the name of the peer sub-command.
+	PeerUseCommand = "peer [cluster]"
+
 	// Namespace contains the name of namespace flag.
 	Namespace = "namespace"
+	// Output contains the name of output flag.
+	Output = "output"
 
 	redCross  = "\u274c"
 	checkMark = "\u2714"
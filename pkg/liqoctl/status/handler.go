@@ -16,19 +16,27 @@ package status
 
 import (
 	"context"
+	"os"
 
 	k8s "k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/liqotech/liqo/pkg/liqoctl/common"
 )
 
 // Args flags of the status command.
 type Args struct {
-	Namespace string
+	Namespace    string
+	OutputFormat string
 }
 
 // Handler implements the logic of the status command.
 func (a *Args) Handler(ctx context.Context) error {
+	if err := ValidateOutputFormat(a.OutputFormat); err != nil {
+		common.ErrorPrinter.Printf("%v", err)
+		return err
+	}
+
 	restConfig, err := common.GetLiqoctlRestConf()
 	if err != nil {
 		return err
@@ -39,7 +47,35 @@ func (a *Args) Handler(ctx context.Context) error {
 		return err
 	}
 
-	collector := newK8sStatusCollector(clientSet, *a)
+	crClient, err := client.New(restConfig, client.Options{Scheme: common.Scheme})
+	if err != nil {
+		return err
+	}
+
+	collector := newK8sStatusCollector(clientSet, crClient, *a, os.Stdout)
+
+	return collector.collectStatus(ctx)
+}
+
+// PeerHandler implements the logic of the status peer command.
+// If cluster is not empty, only the peering with the given cluster (either name or ID) is shown.
+func (a *Args) PeerHandler(ctx context.Context, cluster string) error {
+	if err := ValidateOutputFormat(a.OutputFormat); err != nil {
+		common.ErrorPrinter.Printf("%v", err)
+		return err
+	}
+
+	restConfig, err := common.GetLiqoctlRestConf()
+	if err != nil {
+		return err
+	}
+
+	crClient, err := client.New(restConfig, client.Options{Scheme: common.Scheme})
+	if err != nil {
+		return err
+	}
+
+	collector := newPeerStatusCollector(crClient, *a, cluster, os.Stdout)
 
 	return collector.collectStatus(ctx)
 }
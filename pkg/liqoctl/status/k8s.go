@@ -16,44 +16,81 @@ package status
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 
 	k8s "k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// errUnhealthy is returned when at least one of the checkers did not succeed.
+var errUnhealthy = errors.New("liqo is not healthy")
+
 // k8sStatusCollector knows how to interact with k8s cluster.
 type k8sStatusCollector struct {
 	client   k8s.Interface
 	params   Args
 	checkers []Checker
+	out      io.Writer
 }
 
 // newK8sStatusCollector returns a new k8sStatusCollector.
-func newK8sStatusCollector(client k8s.Interface, params Args) *k8sStatusCollector {
+func newK8sStatusCollector(client k8s.Interface, crClient client.Client, params Args, out io.Writer) *k8sStatusCollector {
 	return &k8sStatusCollector{
 		client: client,
 		params: params,
 		checkers: []Checker{
 			newNamespaceChecker(params.Namespace, client),
 			newPodChecker(params.Namespace, liqoDeployments, liqoDaemonSets, client),
+			newPeeringChecker(crClient),
 		},
+		out: out,
+	}
+}
+
+// newPeerStatusCollector returns a new k8sStatusCollector, which reports the detailed status
+// of the peerings with the remote clusters (or only with the given one, if not empty).
+func newPeerStatusCollector(crClient client.Client, params Args, cluster string, out io.Writer) *k8sStatusCollector {
+	return &k8sStatusCollector{
+		params:   params,
+		checkers: []Checker{newPeerChecker(crClient, cluster)},
+		out:      out,
 	}
 }
 
 // collectStatus collects the status of each Checker that belongs to the collector.
+// The status is either printed in human-oriented form as soon as each checker completes,
+// or serialized at the end according to the selected output format. An error is returned
+// in case any of the checkers did not succeed, so that the exit code reflects the overall health.
 func (k *k8sStatusCollector) collectStatus(ctx context.Context) error {
+	report := Report{Succeeded: true}
 	for _, checker := range k.checkers {
 		if err := checker.Collect(ctx); err != nil {
 			return err
 		}
-		msg, err := checker.Format()
-		if err != nil {
-			return err
+		if k.params.OutputFormat == "" {
+			msg, err := checker.Format()
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(k.out, msg)
 		}
-		fmt.Print(msg)
+		report.Checkers = append(report.Checkers, checker.Report())
 		if !checker.HasSucceeded() {
+			report.Succeeded = false
 			break
 		}
 	}
+
+	if k.params.OutputFormat != "" {
+		if err := printReport(k.out, &report, k.params.OutputFormat); err != nil {
+			return err
+		}
+	}
+
+	if !report.Succeeded {
+		return errUnhealthy
+	}
 	return nil
 }
@@ -78,3 +78,15 @@ func (nc *namespaceChecker) Format() (string, error) {
 func (nc *namespaceChecker) HasSucceeded() bool {
 	return nc.succeeded
 }
+
+func (nc *namespaceChecker) Report() CheckerReport {
+	report := CheckerReport{
+		Name:      nc.name,
+		Succeeded: nc.succeeded,
+		Namespace: &NamespaceReport{Name: nc.namespace, Exists: nc.succeeded},
+	}
+	if nc.failureReason != nil {
+		report.Namespace.Reason = nc.failureReason.Error()
+	}
+	return report
+}
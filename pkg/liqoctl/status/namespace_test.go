@@ -104,6 +104,28 @@ var _ = Describe("Namespace", func() {
 			})
 		})
 
+		Describe("Report() function", func() {
+			When("the collection has failed", func() {
+				It("should report the reason of the failure", func() {
+					nsChecker.succeeded = false
+					nsChecker.failureReason = fmt.Errorf("unable to find namespace foo")
+					report := nsChecker.Report()
+					Expect(report.Name).To(Equal(nsCheckerName))
+					Expect(report.Succeeded).To(BeFalse())
+					Expect(report.Namespace).To(Equal(&NamespaceReport{Name: namespaceName, Exists: false, Reason: "unable to find namespace foo"}))
+				})
+			})
+
+			When("succeeds to get the namespace", func() {
+				It("should report the namespace exists", func() {
+					nsChecker.succeeded = true
+					report := nsChecker.Report()
+					Expect(report.Succeeded).To(BeTrue())
+					Expect(report.Namespace).To(Equal(&NamespaceReport{Name: namespaceName, Exists: true}))
+				})
+			})
+		})
+
 		Describe("HasSucceeded() function", func() {
 			When("check succeeds", func() {
 				It("should return true", func() {
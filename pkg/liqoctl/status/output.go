@@ -0,0 +1,184 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// OutputFormatJSON is the output format serializing the status in JSON.
+	OutputFormatJSON = "json"
+	// OutputFormatYAML is the output format serializing the status in YAML.
+	OutputFormatYAML = "yaml"
+)
+
+// Report is the machine-readable representation of the status of Liqo.
+type Report struct {
+	// Succeeded is true if all the checkers succeeded.
+	Succeeded bool `json:"succeeded"`
+	// Checkers contains the outcome of each executed checker.
+	Checkers []CheckerReport `json:"checkers"`
+}
+
+// CheckerReport is the machine-readable representation of the outcome of a checker.
+// Only the fields concerning the given checker are populated.
+type CheckerReport struct {
+	Name      string `json:"name"`
+	Succeeded bool   `json:"succeeded"`
+
+	Namespace  *NamespaceReport  `json:"namespace,omitempty"`
+	Components []ComponentReport `json:"components,omitempty"`
+	Peerings   []PeeringReport   `json:"peerings,omitempty"`
+	Hops       []HopReport       `json:"hops,omitempty"`
+}
+
+// NamespaceReport is the machine-readable representation of the status of the Liqo namespace.
+type NamespaceReport struct {
+	Name   string `json:"name"`
+	Exists bool   `json:"exists"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// ComponentReport is the machine-readable representation of the status of a Liqo component.
+type ComponentReport struct {
+	Name        string           `json:"name"`
+	Kind        string           `json:"kind"`
+	Succeeded   bool             `json:"succeeded"`
+	Desired     int              `json:"desired"`
+	Ready       int              `json:"ready"`
+	Available   int              `json:"available"`
+	Unavailable int              `json:"unavailable"`
+	Images      []string         `json:"images,omitempty"`
+	PodErrors   []PodErrorReport `json:"podErrors,omitempty"`
+	// Errors contains the errors occurred while collecting the status of the component.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// PodErrorReport is the machine-readable representation of the errors concerning a pod of a Liqo component.
+type PodErrorReport struct {
+	Pod    string   `json:"pod"`
+	Errors []string `json:"errors"`
+}
+
+// PeeringReport is the machine-readable representation of the status of the peering with a remote cluster.
+// The fields concerning the resources associated with the peering are populated by the peer view only.
+type PeeringReport struct {
+	ClusterID   string                   `json:"clusterID"`
+	ClusterName string                   `json:"clusterName"`
+	Succeeded   bool                     `json:"succeeded"`
+	Conditions  []PeeringConditionReport `json:"conditions,omitempty"`
+
+	TenantNamespace         string                 `json:"tenantNamespace,omitempty"`
+	OutgoingResourceRequest *ResourceRequestReport `json:"outgoingResourceRequest,omitempty"`
+	OutgoingResourceOffer   *ResourceOfferReport   `json:"outgoingResourceOffer,omitempty"`
+	IncomingResourceRequest *ResourceRequestReport `json:"incomingResourceRequest,omitempty"`
+	IncomingResourceOffer   *ResourceOfferReport   `json:"incomingResourceOffer,omitempty"`
+	Tunnel                  *TunnelReport          `json:"tunnel,omitempty"`
+	VirtualNodes            []VirtualNodeReport    `json:"virtualNodes,omitempty"`
+}
+
+// PeeringConditionReport is the machine-readable representation of a peering condition.
+type PeeringConditionReport struct {
+	Type               string       `json:"type"`
+	Status             string       `json:"status"`
+	Reason             string       `json:"reason,omitempty"`
+	Message            string       `json:"message,omitempty"`
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// ResourceRequestReport is the machine-readable representation of the status of a ResourceRequest.
+type ResourceRequestReport struct {
+	Name       string `json:"name"`
+	OfferState string `json:"offerState"`
+	Withdrawn  bool   `json:"withdrawn"`
+}
+
+// ResourceOfferReport is the machine-readable representation of the status of a ResourceOffer.
+type ResourceOfferReport struct {
+	Name                 string `json:"name"`
+	Phase                string `json:"phase"`
+	VirtualKubeletStatus string `json:"virtualKubeletStatus,omitempty"`
+	Withdrawn            bool   `json:"withdrawn"`
+}
+
+// TunnelReport is the machine-readable representation of the status of the tunnel towards a remote cluster.
+type TunnelReport struct {
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
+
+// VirtualNodeReport is the machine-readable representation of the status of a virtual node.
+type VirtualNodeReport struct {
+	Name       string                `json:"name"`
+	Ready      bool                  `json:"ready"`
+	Conditions []NodeConditionReport `json:"conditions,omitempty"`
+}
+
+// NodeConditionReport is the machine-readable representation of a node condition.
+type NodeConditionReport struct {
+	Type               string       `json:"type"`
+	Status             string       `json:"status"`
+	Reason             string       `json:"reason,omitempty"`
+	Message            string       `json:"message,omitempty"`
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// HopReport is the machine-readable representation of the outcome of the verification of a network hop.
+type HopReport struct {
+	Cluster   string `json:"cluster"`
+	Hop       string `json:"hop"`
+	Succeeded bool   `json:"succeeded"`
+	Skipped   bool   `json:"skipped,omitempty"`
+	Details   string `json:"details,omitempty"`
+}
+
+// ValidateOutputFormat returns an error if the given output format is not supported.
+// The empty string corresponds to the human-oriented output.
+func ValidateOutputFormat(format string) error {
+	switch format {
+	case "", OutputFormatJSON, OutputFormatYAML:
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format %q (supported: %s, %s)", format, OutputFormatJSON, OutputFormatYAML)
+	}
+}
+
+// printReport serializes the given report according to the output format, and writes it to the given writer.
+func printReport(w io.Writer, report *Report, format string) error {
+	var out []byte
+	var err error
+
+	switch format {
+	case OutputFormatJSON:
+		out, err = json.MarshalIndent(report, "", "  ")
+		out = append(out, '\n')
+	case OutputFormatYAML:
+		out, err = yaml.Marshal(report)
+	default:
+		err = ValidateOutputFormat(format)
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(out)
+	return err
+}
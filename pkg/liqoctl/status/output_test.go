@@ -0,0 +1,125 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/liqotech/liqo/pkg/liqoctl/common"
+)
+
+var _ = Describe("Output", func() {
+	Describe("ValidateOutputFormat() function", func() {
+		It("should accept the supported formats", func() {
+			Expect(ValidateOutputFormat("")).To(Succeed())
+			Expect(ValidateOutputFormat(OutputFormatJSON)).To(Succeed())
+			Expect(ValidateOutputFormat(OutputFormatYAML)).To(Succeed())
+		})
+		It("should reject the unsupported formats", func() {
+			Expect(ValidateOutputFormat("xml")).NotTo(Succeed())
+		})
+	})
+
+	Describe("collectStatus() function", func() {
+		var (
+			buffer    *bytes.Buffer
+			collector *k8sStatusCollector
+			objects   []*corev1.Namespace
+			format    string
+			err       error
+		)
+
+		BeforeEach(func() {
+			buffer = &bytes.Buffer{}
+			objects = []*corev1.Namespace{{ObjectMeta: metav1.ObjectMeta{Name: "liqo"}}}
+			format = OutputFormatJSON
+		})
+
+		JustBeforeEach(func() {
+			clientset := k8sfake.NewSimpleClientset()
+			for _, ns := range objects {
+				Expect(clientset.Tracker().Add(ns)).To(Succeed())
+			}
+			collector = &k8sStatusCollector{
+				client: clientset,
+				params: Args{Namespace: "liqo", OutputFormat: format},
+				checkers: []Checker{
+					newNamespaceChecker("liqo", clientset),
+					newPeeringChecker(fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(newForeignCluster("remote")).Build()),
+				},
+				out: buffer,
+			}
+			err = collector.collectStatus(context.Background())
+		})
+
+		When("all the checkers succeed", func() {
+			It("should serialize the report of each checker in JSON", func() {
+				Expect(err).NotTo(HaveOccurred())
+				var report Report
+				Expect(json.Unmarshal(buffer.Bytes(), &report)).To(Succeed())
+				Expect(report.Succeeded).To(BeTrue())
+				Expect(report.Checkers).To(HaveLen(2))
+				Expect(report.Checkers[0].Namespace).To(Equal(&NamespaceReport{Name: "liqo", Exists: true}))
+				Expect(report.Checkers[1].Peerings).To(HaveLen(1))
+				Expect(report.Checkers[1].Peerings[0].ClusterName).To(Equal("remote"))
+			})
+		})
+
+		When("the YAML format is selected", func() {
+			BeforeEach(func() { format = OutputFormatYAML })
+
+			It("should serialize the report in YAML", func() {
+				Expect(err).NotTo(HaveOccurred())
+				var report Report
+				Expect(yaml.Unmarshal(buffer.Bytes(), &report)).To(Succeed())
+				Expect(report.Succeeded).To(BeTrue())
+				Expect(report.Checkers).To(HaveLen(2))
+			})
+		})
+
+		When("the human-readable format is selected", func() {
+			BeforeEach(func() { format = "" })
+
+			It("should output the formatted status", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(buffer.String()).To(ContainSubstring(nsCheckerName))
+				Expect(buffer.String()).To(ContainSubstring(peeringCheckerName))
+			})
+		})
+
+		When("a checker fails", func() {
+			BeforeEach(func() { objects = nil })
+
+			It("should stop at the failed checker and return an error", func() {
+				Expect(err).To(MatchError(errUnhealthy))
+				var report Report
+				Expect(json.Unmarshal(buffer.Bytes(), &report)).To(Succeed())
+				Expect(report.Succeeded).To(BeFalse())
+				Expect(report.Checkers).To(HaveLen(1))
+				Expect(report.Checkers[0].Succeeded).To(BeFalse())
+			})
+		})
+	})
+})
@@ -0,0 +1,393 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
+	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
+	liqoconsts "github.com/liqotech/liqo/pkg/consts"
+)
+
+const peerCheckerName = "peers"
+
+// peerInfo holds the resources associated with the peering with a given remote cluster.
+type peerInfo struct {
+	foreignCluster *discoveryv1alpha1.ForeignCluster
+
+	// outgoingRequest and outgoingOffer concern the resources offered by the remote cluster to the local one.
+	outgoingRequest *discoveryv1alpha1.ResourceRequest
+	outgoingOffer   *sharingv1alpha1.ResourceOffer
+	// incomingRequest and incomingOffer concern the resources offered by the local cluster to the remote one.
+	incomingRequest *discoveryv1alpha1.ResourceRequest
+	incomingOffer   *sharingv1alpha1.ResourceOffer
+
+	tunnelEndpoint *netv1alpha1.TunnelEndpoint
+	virtualNodes   []corev1.Node
+}
+
+// peerChecker implements the Checker interface.
+// collects, for each ForeignCluster, the peering conditions joined with the status of the
+// associated ResourceRequests, ResourceOffers, TunnelEndpoint and virtual nodes.
+type peerChecker struct {
+	client  client.Client
+	name    string
+	cluster string
+	peers   []peerInfo
+	errors  bool
+}
+
+// newPeerChecker returns a new peer checker. If cluster is not empty, only the
+// ForeignCluster with the given name (or ID) is considered.
+func newPeerChecker(cl client.Client, cluster string) *peerChecker {
+	return &peerChecker{
+		client:  cl,
+		name:    peerCheckerName,
+		cluster: cluster,
+	}
+}
+
+// Collect implements the collect method of the Checker interface.
+func (pc *peerChecker) Collect(ctx context.Context) error {
+	foreignClusters, err := listForeignClusters(ctx, pc.client)
+	if err != nil {
+		return err
+	}
+
+	pc.peers = nil
+	pc.errors = false
+	for i := range foreignClusters {
+		fc := &foreignClusters[i]
+		if pc.cluster != "" && pc.cluster != fc.Spec.ClusterIdentity.ClusterName && pc.cluster != fc.Spec.ClusterIdentity.ClusterID {
+			continue
+		}
+
+		peer, err := pc.collectPeer(ctx, fc)
+		if err != nil {
+			return fmt.Errorf("unable to collect the status of the peering with %s: %w", fc.Spec.ClusterIdentity.ClusterName, err)
+		}
+		if !peer.isHealthy() {
+			pc.errors = true
+		}
+		pc.peers = append(pc.peers, *peer)
+	}
+
+	if pc.cluster != "" && len(pc.peers) == 0 {
+		return fmt.Errorf("no ForeignCluster found for cluster %q", pc.cluster)
+	}
+	return nil
+}
+
+// collectPeer retrieves the resources associated with the peering with the given ForeignCluster.
+func (pc *peerChecker) collectPeer(ctx context.Context, fc *discoveryv1alpha1.ForeignCluster) (*peerInfo, error) {
+	peer := &peerInfo{foreignCluster: fc}
+	clusterID := fc.Spec.ClusterIdentity.ClusterID
+
+	var nodes corev1.NodeList
+	if err := pc.client.List(ctx, &nodes, client.MatchingLabels{
+		liqoconsts.TypeLabel:       liqoconsts.TypeNode,
+		liqoconsts.RemoteClusterID: clusterID,
+	}); err != nil {
+		return nil, err
+	}
+	peer.virtualNodes = nodes.Items
+
+	// The other resources are hosted by the tenant namespace, which is not present if the peering was never established.
+	namespace := fc.Status.TenantNamespace.Local
+	if namespace == "" {
+		return peer, nil
+	}
+
+	outgoing := client.MatchingLabels{liqoconsts.ReplicationDestinationLabel: clusterID}
+	incoming := client.MatchingLabels{liqoconsts.ReplicationOriginLabel: clusterID}
+
+	var requests discoveryv1alpha1.ResourceRequestList
+	if err := pc.client.List(ctx, &requests, client.InNamespace(namespace), outgoing); err != nil {
+		return nil, err
+	}
+	if len(requests.Items) > 0 {
+		peer.outgoingRequest = &requests.Items[0]
+	}
+	if err := pc.client.List(ctx, &requests, client.InNamespace(namespace), incoming); err != nil {
+		return nil, err
+	}
+	if len(requests.Items) > 0 {
+		peer.incomingRequest = &requests.Items[0]
+	}
+
+	var offers sharingv1alpha1.ResourceOfferList
+	if err := pc.client.List(ctx, &offers, client.InNamespace(namespace), incoming); err != nil {
+		return nil, err
+	}
+	if len(offers.Items) > 0 {
+		peer.outgoingOffer = &offers.Items[0]
+	}
+	if err := pc.client.List(ctx, &offers, client.InNamespace(namespace), outgoing); err != nil {
+		return nil, err
+	}
+	if len(offers.Items) > 0 {
+		peer.incomingOffer = &offers.Items[0]
+	}
+
+	var teps netv1alpha1.TunnelEndpointList
+	if err := pc.client.List(ctx, &teps, client.InNamespace(namespace),
+		client.MatchingLabels{liqoconsts.ClusterIDLabelName: clusterID}); err != nil {
+		return nil, err
+	}
+	if len(teps.Items) > 0 {
+		peer.tunnelEndpoint = &teps.Items[0]
+	}
+
+	return peer, nil
+}
+
+// Format implements the format method of the Checker interface.
+// it outputs a summary of each peering, along with the reasons and the last transition times.
+func (pc *peerChecker) Format() (string, error) {
+	w, buf := newTabWriter(pc.name)
+
+	if len(pc.peers) == 0 {
+		fmt.Fprintf(w, "%s no ForeignCluster found\n", checkMark)
+	}
+	for i := range pc.peers {
+		pc.peers[i].format(w)
+	}
+
+	// Add a new line ad the end of the message.
+	fmt.Fprintf(w, "\n")
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func (pc *peerChecker) HasSucceeded() bool {
+	return !pc.errors
+}
+
+// Report implements the report method of the Checker interface.
+func (pc *peerChecker) Report() CheckerReport {
+	report := CheckerReport{Name: pc.name, Succeeded: pc.HasSucceeded()}
+	for i := range pc.peers {
+		report.Peerings = append(report.Peerings, pc.peers[i].report())
+	}
+	return report
+}
+
+// isHealthy returns whether no peering condition is failed, no ResourceOffer has been refused,
+// the tunnel (if any) is connected and all the virtual nodes are ready.
+func (p *peerInfo) isHealthy() bool {
+	if !isPeeringHealthy(p.foreignCluster) {
+		return false
+	}
+	for _, offer := range []*sharingv1alpha1.ResourceOffer{p.outgoingOffer, p.incomingOffer} {
+		if offer != nil && offer.Status.Phase == sharingv1alpha1.ResourceOfferRefused {
+			return false
+		}
+	}
+	if p.tunnelEndpoint != nil && p.tunnelEndpoint.Status.Connection.Status != netv1alpha1.Connected {
+		return false
+	}
+	for i := range p.virtualNodes {
+		if !isNodeReady(&p.virtualNodes[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// format writes the summary of the peering to the given writer.
+func (p *peerInfo) format(w io.Writer) {
+	identity := &p.foreignCluster.Spec.ClusterIdentity
+	mark := checkMark
+	if !p.isHealthy() {
+		mark = redCross
+	}
+	fmt.Fprintf(w, "%s %s (%s)\n", mark, identity.ClusterName, identity.ClusterID)
+
+	for i := range p.foreignCluster.Status.PeeringConditions {
+		condition := &p.foreignCluster.Status.PeeringConditions[i]
+		fmt.Fprintf(w, "\t%s\t%s\t%s\t%s\n", condition.Type, formatConditionStatus(condition.Status),
+			formatReason(condition.Reason, condition.Message), formatTime(condition.LastTransitionTime))
+	}
+
+	formatRequest(w, "ResourceRequest (outgoing)", p.outgoingRequest)
+	formatOffer(w, "ResourceOffer (outgoing)", p.outgoingOffer)
+	formatRequest(w, "ResourceRequest (incoming)", p.incomingRequest)
+	formatOffer(w, "ResourceOffer (incoming)", p.incomingOffer)
+
+	if p.tunnelEndpoint != nil {
+		connection := &p.tunnelEndpoint.Status.Connection
+		color := green
+		if connection.Status != netv1alpha1.Connected {
+			color = red
+		}
+		fmt.Fprintf(w, "\tTunnelEndpoint\t%s%s%s\t%s\t-\n", color, connection.Status, reset, formatReason("", connection.StatusMessage))
+	}
+
+	for i := range p.virtualNodes {
+		node := &p.virtualNodes[i]
+		ready := getNodeReadyCondition(node)
+		if ready == nil {
+			fmt.Fprintf(w, "\tVirtualNode %s\t%sNotReady%s\t-\t-\n", node.Name, red, reset)
+			continue
+		}
+		status, color := "Ready", green
+		if ready.Status != corev1.ConditionTrue {
+			status, color = "NotReady", red
+		}
+		fmt.Fprintf(w, "\tVirtualNode %s\t%s%s%s\t%s\t%s\n", node.Name, color, status, reset,
+			formatReason(ready.Reason, ready.Message), formatTime(ready.LastTransitionTime))
+	}
+}
+
+// report returns the machine-readable representation of the peering.
+func (p *peerInfo) report() PeeringReport {
+	report := newPeeringReport(p.foreignCluster)
+	report.Succeeded = p.isHealthy()
+	report.TenantNamespace = p.foreignCluster.Status.TenantNamespace.Local
+	report.OutgoingResourceRequest = newResourceRequestReport(p.outgoingRequest)
+	report.OutgoingResourceOffer = newResourceOfferReport(p.outgoingOffer)
+	report.IncomingResourceRequest = newResourceRequestReport(p.incomingRequest)
+	report.IncomingResourceOffer = newResourceOfferReport(p.incomingOffer)
+
+	if p.tunnelEndpoint != nil {
+		report.Tunnel = &TunnelReport{
+			Status:  string(p.tunnelEndpoint.Status.Connection.Status),
+			Message: p.tunnelEndpoint.Status.Connection.StatusMessage,
+		}
+	}
+
+	for i := range p.virtualNodes {
+		node := &p.virtualNodes[i]
+		vn := VirtualNodeReport{Name: node.Name, Ready: isNodeReady(node)}
+		for j := range node.Status.Conditions {
+			condition := &node.Status.Conditions[j]
+			vn.Conditions = append(vn.Conditions, NodeConditionReport{
+				Type:               string(condition.Type),
+				Status:             string(condition.Status),
+				Reason:             condition.Reason,
+				Message:            condition.Message,
+				LastTransitionTime: timeOrNil(condition.LastTransitionTime),
+			})
+		}
+		report.VirtualNodes = append(report.VirtualNodes, vn)
+	}
+	return report
+}
+
+func newResourceRequestReport(request *discoveryv1alpha1.ResourceRequest) *ResourceRequestReport {
+	if request == nil {
+		return nil
+	}
+	return &ResourceRequestReport{
+		Name:       request.Name,
+		OfferState: string(request.Status.OfferState),
+		Withdrawn:  request.Spec.WithdrawalTimestamp != nil,
+	}
+}
+
+func newResourceOfferReport(offer *sharingv1alpha1.ResourceOffer) *ResourceOfferReport {
+	if offer == nil {
+		return nil
+	}
+	return &ResourceOfferReport{
+		Name:                 offer.Name,
+		Phase:                string(offer.Status.Phase),
+		VirtualKubeletStatus: string(offer.Status.VirtualKubeletStatus),
+		Withdrawn:            offer.Spec.WithdrawalTimestamp != nil,
+	}
+}
+
+// formatRequest writes the summary of the given ResourceRequest to the given writer.
+func formatRequest(w io.Writer, kind string, request *discoveryv1alpha1.ResourceRequest) {
+	if request == nil {
+		fmt.Fprintf(w, "\t%s\t%sNotFound%s\t-\t-\n", kind, yellow, reset)
+		return
+	}
+	details := fmt.Sprintf("OfferState: %s", request.Status.OfferState)
+	if request.Spec.WithdrawalTimestamp != nil {
+		details += ", withdrawal requested"
+	}
+	fmt.Fprintf(w, "\t%s\t%s%s%s\t%s\t%s\n", kind, green, "Found", reset, details, formatTime(request.CreationTimestamp))
+}
+
+// formatOffer writes the summary of the given ResourceOffer to the given writer.
+func formatOffer(w io.Writer, kind string, offer *sharingv1alpha1.ResourceOffer) {
+	if offer == nil {
+		fmt.Fprintf(w, "\t%s\t%sNotFound%s\t-\t-\n", kind, yellow, reset)
+		return
+	}
+	color := yellow
+	switch offer.Status.Phase {
+	case sharingv1alpha1.ResourceOfferAccepted:
+		color = green
+	case sharingv1alpha1.ResourceOfferRefused:
+		color = red
+	}
+	details := fmt.Sprintf("VirtualKubelet: %s", offer.Status.VirtualKubeletStatus)
+	if offer.Spec.WithdrawalTimestamp != nil {
+		details += ", withdrawal requested"
+	}
+	fmt.Fprintf(w, "\t%s\t%s%s%s\t%s\t%s\n", kind, color, offer.Status.Phase, reset, details, formatTime(offer.CreationTimestamp))
+}
+
+// getNodeReadyCondition returns the Ready condition of the given node, or nil if not present.
+func getNodeReadyCondition(node *corev1.Node) *corev1.NodeCondition {
+	for i := range node.Status.Conditions {
+		if node.Status.Conditions[i].Type == corev1.NodeReady {
+			return &node.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// isNodeReady returns whether the given node is ready.
+func isNodeReady(node *corev1.Node) bool {
+	ready := getNodeReadyCondition(node)
+	return ready != nil && ready.Status == corev1.ConditionTrue
+}
+
+// formatReason returns the given reason and message, joined in a human-readable form.
+func formatReason(reason, message string) string {
+	switch {
+	case reason != "" && message != "":
+		return fmt.Sprintf("%s: %s", reason, message)
+	case reason != "":
+		return reason
+	case message != "":
+		return message
+	default:
+		return "-"
+	}
+}
+
+// formatTime returns the time elapsed since the given time, in a human-readable form.
+func formatTime(t metav1.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return duration.HumanDuration(time.Since(t.Time)) + " ago"
+}
@@ -0,0 +1,180 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
+	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
+	liqoconsts "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqoctl/common"
+)
+
+var _ = Describe("Peer", func() {
+	Describe("peerChecker", func() {
+		const tenantNamespace = "liqo-tenant-remote"
+
+		var (
+			ctx         context.Context
+			objects     []client.Object
+			fc          *discoveryv1alpha1.ForeignCluster
+			tep         *netv1alpha1.TunnelEndpoint
+			virtualNode *corev1.Node
+			cluster     string
+			pc          *peerChecker
+			err         error
+		)
+
+		labelled := func(meta metav1.ObjectMeta, key, value string) metav1.ObjectMeta {
+			meta.Labels = map[string]string{key: value}
+			return meta
+		}
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			cluster = ""
+
+			fc = newForeignCluster("remote", discoveryv1alpha1.PeeringCondition{
+				Type: discoveryv1alpha1.OutgoingPeeringCondition, Status: discoveryv1alpha1.PeeringConditionStatusEstablished,
+				Reason: "Established", LastTransitionTime: metav1.Now()})
+			fc.Status.TenantNamespace.Local = tenantNamespace
+
+			tep = &netv1alpha1.TunnelEndpoint{
+				ObjectMeta: labelled(metav1.ObjectMeta{Name: "tep", Namespace: tenantNamespace}, liqoconsts.ClusterIDLabelName, "remote-id"),
+				Status: netv1alpha1.TunnelEndpointStatus{Connection: netv1alpha1.Connection{
+					Status: netv1alpha1.Connected, StatusMessage: "the tunnel is up"}},
+			}
+
+			virtualNode = &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "liqo-remote", Labels: map[string]string{
+					liqoconsts.TypeLabel: liqoconsts.TypeNode, liqoconsts.RemoteClusterID: "remote-id"}},
+				Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{{
+					Type: corev1.NodeReady, Status: corev1.ConditionTrue, Reason: "KubeletReady"}}},
+			}
+
+			objects = []client.Object{
+				fc, tep, virtualNode,
+				&discoveryv1alpha1.ResourceRequest{
+					ObjectMeta: labelled(metav1.ObjectMeta{Name: "outgoing", Namespace: tenantNamespace},
+						liqoconsts.ReplicationDestinationLabel, "remote-id"),
+					Status: discoveryv1alpha1.ResourceRequestStatus{OfferState: discoveryv1alpha1.OfferStateCreated},
+				},
+				&sharingv1alpha1.ResourceOffer{
+					ObjectMeta: labelled(metav1.ObjectMeta{Name: "outgoing", Namespace: tenantNamespace},
+						liqoconsts.ReplicationOriginLabel, "remote-id"),
+					Status: sharingv1alpha1.ResourceOfferStatus{
+						Phase: sharingv1alpha1.ResourceOfferAccepted, VirtualKubeletStatus: sharingv1alpha1.VirtualKubeletStatusCreated},
+				},
+				newForeignCluster("other"),
+			}
+		})
+
+		JustBeforeEach(func() {
+			pc = newPeerChecker(fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(objects...).Build(), cluster)
+			err = pc.Collect(ctx)
+		})
+
+		When("the peering is healthy", func() {
+			It("should succeed", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pc.HasSucceeded()).To(BeTrue())
+			})
+
+			It("should join each ForeignCluster with the associated resources", func() {
+				Expect(pc.peers).To(HaveLen(2))
+				peer := pc.peers[1]
+				Expect(peer.foreignCluster.Name).To(Equal("remote"))
+				Expect(peer.outgoingRequest).NotTo(BeNil())
+				Expect(peer.outgoingOffer).NotTo(BeNil())
+				Expect(peer.incomingRequest).To(BeNil())
+				Expect(peer.incomingOffer).To(BeNil())
+				Expect(peer.tunnelEndpoint).NotTo(BeNil())
+				Expect(peer.virtualNodes).To(HaveLen(1))
+			})
+
+			It("should format the summary of each peering", func() {
+				msg, err := pc.Format()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(msg).To(ContainSubstring("%s remote (remote-id)", checkMark))
+				Expect(msg).To(ContainSubstring("Established"))
+				Expect(msg).To(ContainSubstring("ago"))
+				Expect(msg).To(ContainSubstring("%sConnected%s", green, reset))
+				Expect(msg).To(ContainSubstring("the tunnel is up"))
+				Expect(msg).To(ContainSubstring("VirtualNode liqo-remote"))
+				Expect(msg).To(ContainSubstring("KubeletReady"))
+			})
+
+			It("should report the joined resources", func() {
+				report := pc.Report()
+				Expect(report.Name).To(Equal(peerCheckerName))
+				Expect(report.Peerings).To(HaveLen(2))
+				peering := report.Peerings[1]
+				Expect(peering.TenantNamespace).To(Equal(tenantNamespace))
+				Expect(peering.Conditions[0].LastTransitionTime).NotTo(BeNil())
+				Expect(peering.OutgoingResourceRequest).To(Equal(&ResourceRequestReport{Name: "outgoing", OfferState: "Created"}))
+				Expect(peering.OutgoingResourceOffer).To(Equal(&ResourceOfferReport{
+					Name: "outgoing", Phase: "Accepted", VirtualKubeletStatus: "Created"}))
+				Expect(peering.IncomingResourceRequest).To(BeNil())
+				Expect(peering.Tunnel).To(Equal(&TunnelReport{Status: "Connected", Message: "the tunnel is up"}))
+				Expect(peering.VirtualNodes).To(HaveLen(1))
+				Expect(peering.VirtualNodes[0].Ready).To(BeTrue())
+			})
+		})
+
+		When("the tunnel is not connected", func() {
+			BeforeEach(func() { tep.Status.Connection.Status = netv1alpha1.ConnectionError })
+
+			It("should fail", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pc.HasSucceeded()).To(BeFalse())
+				Expect(pc.Report().Peerings[1].Succeeded).To(BeFalse())
+			})
+		})
+
+		When("the virtual node is not ready", func() {
+			BeforeEach(func() { virtualNode.Status.Conditions[0].Status = corev1.ConditionFalse })
+
+			It("should fail", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pc.HasSucceeded()).To(BeFalse())
+			})
+		})
+
+		When("a cluster is specified", func() {
+			BeforeEach(func() { cluster = "remote-id" })
+
+			It("should only consider the matching ForeignCluster", func() {
+				Expect(err).NotTo(HaveOccurred())
+				Expect(pc.peers).To(HaveLen(1))
+				Expect(pc.peers[0].foreignCluster.Name).To(Equal("remote"))
+			})
+		})
+
+		When("the specified cluster does not exist", func() {
+			BeforeEach(func() { cluster = "missing" })
+
+			It("should return an error", func() { Expect(err).To(HaveOccurred()) })
+		})
+	})
+})
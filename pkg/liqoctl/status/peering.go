@@ -0,0 +1,183 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+)
+
+const peeringCheckerName = "foreign-clusters"
+
+// peeringChecker implements the Checker interface.
+// collects the peering conditions of each ForeignCluster, and checks that none of them is failed.
+type peeringChecker struct {
+	client          client.Client
+	name            string
+	foreignClusters []discoveryv1alpha1.ForeignCluster
+	errors          bool
+}
+
+func newPeeringChecker(cl client.Client) *peeringChecker {
+	return &peeringChecker{
+		client: cl,
+		name:   peeringCheckerName,
+	}
+}
+
+// Collect implements the collect method of the Checker interface.
+// it retrieves the ForeignClusters, sorted by cluster name.
+func (pc *peeringChecker) Collect(ctx context.Context) error {
+	var err error
+	if pc.foreignClusters, err = listForeignClusters(ctx, pc.client); err != nil {
+		return err
+	}
+
+	pc.errors = false
+	for i := range pc.foreignClusters {
+		if !isPeeringHealthy(&pc.foreignClusters[i]) {
+			pc.errors = true
+		}
+	}
+	return nil
+}
+
+// Format implements the format method of the Checker interface.
+// it outputs the peering conditions of each ForeignCluster.
+func (pc *peeringChecker) Format() (string, error) {
+	w, buf := newTabWriter(pc.name)
+
+	if len(pc.foreignClusters) == 0 {
+		fmt.Fprintf(w, "%s no ForeignCluster found\n", checkMark)
+	}
+	for i := range pc.foreignClusters {
+		fc := &pc.foreignClusters[i]
+		mark := checkMark
+		if !isPeeringHealthy(fc) {
+			mark = redCross
+		}
+
+		var conditions []string
+		for j := range fc.Status.PeeringConditions {
+			condition := &fc.Status.PeeringConditions[j]
+			conditions = append(conditions, fmt.Sprintf("%s: %s", condition.Type, formatConditionStatus(condition.Status)))
+		}
+		fmt.Fprintf(w, "%s %s\t%s\t%s\n", mark, fc.Spec.ClusterIdentity.ClusterName, fc.Spec.ClusterIdentity.ClusterID, strings.Join(conditions, ", "))
+	}
+
+	// Add a new line ad the end of the message.
+	fmt.Fprintf(w, "\n")
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func (pc *peeringChecker) HasSucceeded() bool {
+	return !pc.errors
+}
+
+// Report implements the report method of the Checker interface.
+// it returns the peering conditions of each ForeignCluster.
+func (pc *peeringChecker) Report() CheckerReport {
+	report := CheckerReport{Name: pc.name, Succeeded: pc.HasSucceeded()}
+	for i := range pc.foreignClusters {
+		report.Peerings = append(report.Peerings, newPeeringReport(&pc.foreignClusters[i]))
+	}
+	return report
+}
+
+// newPeeringReport returns the report of the peering conditions of the given ForeignCluster.
+func newPeeringReport(fc *discoveryv1alpha1.ForeignCluster) PeeringReport {
+	peering := PeeringReport{
+		ClusterID:   fc.Spec.ClusterIdentity.ClusterID,
+		ClusterName: fc.Spec.ClusterIdentity.ClusterName,
+		Succeeded:   isPeeringHealthy(fc),
+	}
+	for i := range fc.Status.PeeringConditions {
+		condition := &fc.Status.PeeringConditions[i]
+		peering.Conditions = append(peering.Conditions, PeeringConditionReport{
+			Type:               string(condition.Type),
+			Status:             string(condition.Status),
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+			LastTransitionTime: timeOrNil(condition.LastTransitionTime),
+		})
+	}
+	return peering
+}
+
+// timeOrNil returns a pointer to the given time, or nil if it is not set.
+func timeOrNil(t metav1.Time) *metav1.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// listForeignClusters returns the ForeignClusters, sorted by cluster name.
+func listForeignClusters(ctx context.Context, cl client.Client) ([]discoveryv1alpha1.ForeignCluster, error) {
+	var foreignClusters discoveryv1alpha1.ForeignClusterList
+	if err := cl.List(ctx, &foreignClusters); err != nil {
+		return nil, fmt.Errorf("unable to list ForeignClusters: %w", err)
+	}
+
+	sort.Slice(foreignClusters.Items, func(i, j int) bool {
+		return foreignClusters.Items[i].Spec.ClusterIdentity.ClusterName < foreignClusters.Items[j].Spec.ClusterIdentity.ClusterName
+	})
+	return foreignClusters.Items, nil
+}
+
+// isPeeringHealthy returns whether none of the peering conditions of the given ForeignCluster is failed.
+func isPeeringHealthy(fc *discoveryv1alpha1.ForeignCluster) bool {
+	for i := range fc.Status.PeeringConditions {
+		if isConditionFailed(fc.Status.PeeringConditions[i].Status) {
+			return false
+		}
+	}
+	return true
+}
+
+// isConditionFailed returns whether the given peering condition status denotes a failure.
+func isConditionFailed(status discoveryv1alpha1.PeeringConditionStatusType) bool {
+	switch status {
+	case discoveryv1alpha1.PeeringConditionStatusError,
+		discoveryv1alpha1.PeeringConditionStatusDenied,
+		discoveryv1alpha1.PeeringConditionStatusEmptyDenied,
+		discoveryv1alpha1.PeeringConditionStatusRevoked:
+		return true
+	default:
+		return false
+	}
+}
+
+// formatConditionStatus returns the given peering condition status, colored according to its meaning.
+func formatConditionStatus(status discoveryv1alpha1.PeeringConditionStatusType) string {
+	switch {
+	case isConditionFailed(status):
+		return red + string(status) + reset
+	case status == discoveryv1alpha1.PeeringConditionStatusEstablished, status == discoveryv1alpha1.PeeringConditionStatusSuccess:
+		return green + string(status) + reset
+	default:
+		return yellow + string(status) + reset
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package status
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	"github.com/liqotech/liqo/pkg/liqoctl/common"
+)
+
+func newForeignCluster(name string, conditions ...discoveryv1alpha1.PeeringCondition) *discoveryv1alpha1.ForeignCluster {
+	return &discoveryv1alpha1.ForeignCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: discoveryv1alpha1.ForeignClusterSpec{
+			ClusterIdentity: discoveryv1alpha1.ClusterIdentity{ClusterID: name + "-id", ClusterName: name},
+		},
+		Status: discoveryv1alpha1.ForeignClusterStatus{PeeringConditions: conditions},
+	}
+}
+
+var _ = Describe("Peering", func() {
+	Describe("peeringChecker", func() {
+		var (
+			ctx             context.Context
+			foreignClusters []client.Object
+			pc              *peeringChecker
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			foreignClusters = []client.Object{
+				newForeignCluster("zeta", discoveryv1alpha1.PeeringCondition{
+					Type: discoveryv1alpha1.OutgoingPeeringCondition, Status: discoveryv1alpha1.PeeringConditionStatusEstablished}),
+				newForeignCluster("alpha", discoveryv1alpha1.PeeringCondition{
+					Type: discoveryv1alpha1.NetworkStatusCondition, Status: discoveryv1alpha1.PeeringConditionStatusPending}),
+			}
+		})
+
+		JustBeforeEach(func() {
+			pc = newPeeringChecker(fake.NewClientBuilder().WithScheme(common.Scheme).WithObjects(foreignClusters...).Build())
+			Expect(pc.Collect(ctx)).To(Succeed())
+		})
+
+		When("no peering is failed", func() {
+			It("should succeed", func() { Expect(pc.HasSucceeded()).To(BeTrue()) })
+			It("should sort the ForeignClusters by name", func() {
+				Expect(pc.foreignClusters).To(HaveLen(2))
+				Expect(pc.foreignClusters[0].Name).To(Equal("alpha"))
+				Expect(pc.foreignClusters[1].Name).To(Equal("zeta"))
+			})
+			It("should format the peering conditions", func() {
+				msg, err := pc.Format()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(msg).To(ContainSubstring("%s alpha", checkMark))
+				Expect(msg).To(ContainSubstring("NetworkStatus: %sPending%s", yellow, reset))
+				Expect(msg).To(ContainSubstring("OutgoingPeering: %sEstablished%s", green, reset))
+			})
+		})
+
+		When("a peering is failed", func() {
+			BeforeEach(func() {
+				foreignClusters = append(foreignClusters, newForeignCluster("beta", discoveryv1alpha1.PeeringCondition{
+					Type: discoveryv1alpha1.AuthenticationStatusCondition, Status: discoveryv1alpha1.PeeringConditionStatusDenied,
+					Reason: "Denied", Message: "the remote cluster denied the authentication"}))
+			})
+
+			It("should fail", func() { Expect(pc.HasSucceeded()).To(BeFalse()) })
+			It("should report the failed peering", func() {
+				report := pc.Report()
+				Expect(report.Name).To(Equal(peeringCheckerName))
+				Expect(report.Succeeded).To(BeFalse())
+				Expect(report.Peerings).To(HaveLen(3))
+				Expect(report.Peerings[1]).To(Equal(PeeringReport{
+					ClusterID: "beta-id", ClusterName: "beta", Succeeded: false,
+					Conditions: []PeeringConditionReport{{
+						Type: "AuthenticationStatus", Status: "Denied",
+						Reason: "Denied", Message: "the remote cluster denied the authentication",
+					}},
+				}))
+				Expect(report.Peerings[0].Succeeded).To(BeTrue())
+			})
+		})
+
+		When("no ForeignCluster exists", func() {
+			BeforeEach(func() { foreignClusters = nil })
+
+			It("should succeed", func() {
+				Expect(pc.HasSucceeded()).To(BeTrue())
+				msg, err := pc.Format()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(msg).To(ContainSubstring("no ForeignCluster found"))
+			})
+		})
+	})
+})
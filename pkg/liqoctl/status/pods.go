@@ -17,6 +17,7 @@ package status
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -152,6 +153,25 @@ func (ps *componentState) addErrorForPod(pod string, err error) {
 	ps.errorFlag = true
 }
 
+// podErrors returns the errors of the pods belonging to the current deployment/application, sorted by pod name.
+func (ps *componentState) podErrors() []PodErrorReport {
+	pods := make([]string, 0, len(ps.errors))
+	for pod := range ps.errors {
+		pods = append(pods, pod)
+	}
+	sort.Strings(pods)
+
+	reports := make([]PodErrorReport, 0, len(pods))
+	for _, pod := range pods {
+		report := PodErrorReport{Pod: pod}
+		for _, err := range ps.errors[pod].errors {
+			report.Errors = append(report.Errors, err.Error())
+		}
+		reports = append(reports, report)
+	}
+	return reports
+}
+
 // format returns a string describing the status of the current deployment/application.
 func (ps *componentState) format() string {
 	var outputTokens []string
@@ -268,6 +288,36 @@ func (pc *podChecker) Format() (string, error) {
 	return buf.String(), nil
 }
 
+// Report implements the report method of the Checker interface.
+// it returns the status of each Liqo component, in the same order they are checked.
+func (pc *podChecker) Report() CheckerReport {
+	report := CheckerReport{Name: pc.name, Succeeded: pc.HasSucceeded()}
+
+	components := append(append([]string{}, pc.deployments...), pc.daemonSets...)
+	for _, component := range components {
+		cr := ComponentReport{Name: component, Succeeded: true}
+		if podState, found := pc.podsState[component]; found {
+			cr.Kind = podState.controllerType
+			cr.Succeeded = !podState.errorFlag
+			cr.Desired = podState.desired
+			cr.Ready = podState.ready
+			cr.Available = podState.available
+			cr.Unavailable = podState.unavailable
+			cr.Images = podState.getImages()
+			cr.PodErrors = podState.podErrors()
+		}
+		for _, err := range pc.collectionErrors {
+			if err.appName == component {
+				cr.Kind = err.appType
+				cr.Succeeded = false
+				cr.Errors = append(cr.Errors, err.err.Error())
+			}
+		}
+		report.Components = append(report.Components, cr)
+	}
+	return report
+}
+
 // deploymentStatus collects the status of a given kubernetes Deployment.
 func (pc *podChecker) deploymentStatus(ctx context.Context, deploymentName string) error {
 	var errors bool
@@ -391,6 +391,51 @@ var _ = Describe("Pods", func() {
 			})
 		})
 
+		Describe("Report() function", func() {
+			When("a component is not available", func() {
+				It("should report the status of each component", func() {
+					pod.SetLabels(depLabels)
+					podC.client = fake.NewSimpleClientset(deployment, pod)
+					Expect(podC.Collect(ctx)).To(BeNil())
+
+					report := podC.Report()
+					Expect(report.Name).To(Equal(checkerName))
+					Expect(report.Succeeded).To(BeFalse())
+					Expect(report.Components).To(HaveLen(2))
+
+					Expect(report.Components[0].Name).To(Equal(deploymentApp))
+					Expect(report.Components[0].Kind).To(Equal("Deployment"))
+					Expect(report.Components[0].Succeeded).To(BeTrue())
+					Expect(report.Components[0].Images).To(ConsistOf("nginx"))
+					Expect(report.Components[0].Errors).To(BeEmpty())
+
+					Expect(report.Components[1].Name).To(Equal(daemonSetApp))
+					Expect(report.Components[1].Kind).To(Equal("DaemonSet"))
+					Expect(report.Components[1].Succeeded).To(BeFalse())
+					Expect(report.Components[1].Errors).To(HaveLen(1))
+				})
+			})
+
+			When("a pod is not ready", func() {
+				It("should report the errors of the pod", func() {
+					podC.podsState[deploymentApp] = newComponentState("Deployment")
+					state := podC.podsState[deploymentApp]
+					state.addErrorForPod("pod-b", errors.New("not ready"))
+					state.addErrorForPod("pod-a", errors.New("not scheduled"))
+					podC.podsState[deploymentApp] = state
+					podC.errors = true
+
+					report := podC.Report()
+					Expect(report.Succeeded).To(BeFalse())
+					Expect(report.Components[0].Succeeded).To(BeFalse())
+					Expect(report.Components[0].PodErrors).To(Equal([]PodErrorReport{
+						{Pod: "pod-a", Errors: []string{"not scheduled"}},
+						{Pod: "pod-b", Errors: []string{"not ready"}},
+					}))
+				})
+			})
+		})
+
 		Describe("checkPodsStatus() function", func() {
 
 			var (
@@ -103,3 +103,29 @@ func (mock *IPAMClient) BelongsToPodCIDR(context.Context, *ipam.BelongsRequest,
 	...grpc.CallOption) (*ipam.BelongsResponse, error) {
 	return &ipam.BelongsResponse{Belongs: true}, nil
 }
+
+// ListPools mocks the corresponding IPAMClient function.
+func (mock *IPAMClient) ListPools(context.Context, *ipam.ListPoolsRequest, ...grpc.CallOption) (*ipam.ListPoolsResponse, error) {
+	return &ipam.ListPoolsResponse{}, nil
+}
+
+// ListClusterSubnets mocks the corresponding IPAMClient function.
+func (mock *IPAMClient) ListClusterSubnets(context.Context, *ipam.ListClusterSubnetsRequest,
+	...grpc.CallOption) (*ipam.ListClusterSubnetsResponse, error) {
+	return &ipam.ListClusterSubnetsResponse{}, nil
+}
+
+// ListEndpointMappings mocks the corresponding IPAMClient function.
+func (mock *IPAMClient) ListEndpointMappings(_ context.Context, req *ipam.ListEndpointMappingsRequest,
+	_ ...grpc.CallOption) (*ipam.ListEndpointMappingsResponse, error) {
+	response := &ipam.ListEndpointMappingsResponse{}
+	for endpointIP, ip := range mock.endpoints {
+		response.Mappings = append(response.Mappings, &ipam.EndpointMapping{EndpointIP: endpointIP, ExternalCIDRIP: ip})
+	}
+	return response, nil
+}
+
+// DetectLeaks mocks the corresponding IPAMClient function.
+func (mock *IPAMClient) DetectLeaks(context.Context, *ipam.DetectLeaksRequest, ...grpc.CallOption) (*ipam.DetectLeaksResponse, error) {
+	return &ipam.DetectLeaksResponse{}, nil
+}
@@ -0,0 +1,305 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"inet.af/netaddr"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	liqoneterrors "github.com/liqotech/liqo/pkg/liqonet/errors"
+)
+
+const (
+	// LeakKindClusterSubnets identifies the networks reserved for a remote cluster which no longer exists.
+	LeakKindClusterSubnets = "ClusterSubnets"
+	// LeakKindEndpointMapping identifies an endpoint mapping referencing an endpoint or a cluster which no longer exists.
+	LeakKindEndpointMapping = "EndpointMapping"
+)
+
+var (
+	podsGVR           = corev1.SchemeGroupVersion.WithResource("pods")
+	endpointSlicesGVR = discoveryv1.SchemeGroupVersion.WithResource("endpointslices")
+)
+
+// ListPools returns the network pools, along with the networks allocated from each of them, and the reserved subnets.
+func (liqoIPAM *IPAM) ListPools(ctx context.Context, request *ListPoolsRequest) (*ListPoolsResponse, error) {
+	liqoIPAM.mutex.Lock()
+	defer liqoIPAM.mutex.Unlock()
+
+	prefixes, err := liqoIPAM.ipamStorage.ReadAllPrefixCidrs()
+	if err != nil {
+		return &ListPoolsResponse{}, fmt.Errorf("cannot retrieve the allocated networks: %w", err)
+	}
+	sort.Strings(prefixes)
+
+	response := &ListPoolsResponse{ReservedSubnets: liqoIPAM.ipamStorage.getReservedSubnets()}
+	for _, pool := range liqoIPAM.ipamStorage.getPools() {
+		entry := &Pool{Network: pool}
+		for _, prefix := range prefixes {
+			contained, err := networkContains(pool, prefix)
+			if err != nil {
+				return &ListPoolsResponse{}, err
+			}
+			if contained && prefix != pool {
+				entry.AllocatedNetworks = append(entry.AllocatedNetworks, prefix)
+			}
+		}
+		response.Pools = append(response.Pools, entry)
+	}
+	return response, nil
+}
+
+// ListClusterSubnets returns the networks assigned to the given remote cluster, or to all of them if the clusterID is empty.
+func (liqoIPAM *IPAM) ListClusterSubnets(ctx context.Context, request *ListClusterSubnetsRequest) (*ListClusterSubnetsResponse, error) {
+	liqoIPAM.mutex.Lock()
+	defer liqoIPAM.mutex.Unlock()
+
+	response := &ListClusterSubnetsResponse{}
+	for _, ipv6 := range []bool{false, true} {
+		for clusterID, subnets := range liqoIPAM.clusterSubnets(ipv6) {
+			if request.GetClusterID() != "" && request.GetClusterID() != clusterID {
+				continue
+			}
+			response.Subnets = append(response.Subnets, &ClusterSubnets{
+				ClusterID:            clusterID,
+				Ipv6:                 ipv6,
+				RemotePodCIDR:        subnets.RemotePodCIDR,
+				RemoteExternalCIDR:   subnets.RemoteExternalCIDR,
+				LocalNATPodCIDR:      subnets.LocalNATPodCIDR,
+				LocalNATExternalCIDR: subnets.LocalNATExternalCIDR,
+			})
+		}
+	}
+
+	sort.SliceStable(response.Subnets, func(i, j int) bool {
+		if response.Subnets[i].ClusterID != response.Subnets[j].ClusterID {
+			return response.Subnets[i].ClusterID < response.Subnets[j].ClusterID
+		}
+		return !response.Subnets[i].Ipv6 && response.Subnets[j].Ipv6
+	})
+	return response, nil
+}
+
+// ListEndpointMappings returns the endpoint mappings reflected to the given remote cluster, or to any of them if the clusterID is empty.
+func (liqoIPAM *IPAM) ListEndpointMappings(ctx context.Context, request *ListEndpointMappingsRequest) (*ListEndpointMappingsResponse, error) {
+	liqoIPAM.mutex.Lock()
+	defer liqoIPAM.mutex.Unlock()
+
+	response := &ListEndpointMappingsResponse{}
+	for endpointIP, mapping := range liqoIPAM.ipamStorage.getEndpointMappings() {
+		if _, found := mapping.ClusterMappings[request.GetClusterID()]; request.GetClusterID() != "" && !found {
+			continue
+		}
+		response.Mappings = append(response.Mappings, &EndpointMapping{
+			EndpointIP:     endpointIP,
+			ExternalCIDRIP: mapping.IP,
+			ClusterIDs:     sets.StringKeySet(mapping.ClusterMappings).List(),
+		})
+	}
+
+	sort.Slice(response.Mappings, func(i, j int) bool {
+		return response.Mappings[i].EndpointIP < response.Mappings[j].EndpointIP
+	})
+	return response, nil
+}
+
+// DetectLeaks detects the IPAM entries referencing pods, endpoints or clusters which no longer exist.
+// If requested, the leaked entries are also garbage collected.
+func (liqoIPAM *IPAM) DetectLeaks(ctx context.Context, request *DetectLeaksRequest) (*DetectLeaksResponse, error) {
+	leaks, err := liqoIPAM.detectLeaksInternal(ctx, request.GetGarbageCollect())
+	if err != nil {
+		return &DetectLeaksResponse{}, fmt.Errorf("cannot detect leaks: %w", err)
+	}
+	return &DetectLeaksResponse{Leaks: leaks}, nil
+}
+
+// RunLeakDetection periodically detects, and optionally garbage collects, the leaked IPAM entries, until the context is canceled.
+func (liqoIPAM *IPAM) RunLeakDetection(ctx context.Context, period time.Duration, garbageCollect bool) {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		leaks, err := liqoIPAM.detectLeaksInternal(ctx, garbageCollect)
+		if err != nil {
+			klog.Errorf("Failed to detect IPAM leaks: %v", err)
+			return
+		}
+		for _, leak := range leaks {
+			klog.Warningf("Detected IPAM leak (kind: %s, cluster: %q, ip: %q, collected: %t): %s",
+				leak.GetKind(), leak.GetClusterID(), leak.GetIp(), leak.GetCollected(), leak.GetReason())
+		}
+	}, period)
+}
+
+// detectLeaksInternal is the internal implementation of DetectLeaks.
+func (liqoIPAM *IPAM) detectLeaksInternal(ctx context.Context, garbageCollect bool) ([]*Leak, error) {
+	// The entries are snapshotted before retrieving the existing resources, and only the ones in the snapshot are
+	// evaluated. This prevents entries created in the meanwhile (hence, possibly not matched by the retrieved
+	// resources) from being considered as leaked, without holding the mutex while interacting with the API server.
+	liqoIPAM.mutex.Lock()
+	candidateClusters := sets.StringKeySet(liqoIPAM.clusterSubnets(false)).Union(sets.StringKeySet(liqoIPAM.clusterSubnets(true)))
+	candidateMappings := make(map[string]sets.String)
+	for endpointIP, mapping := range liqoIPAM.ipamStorage.getEndpointMappings() {
+		candidateMappings[endpointIP] = sets.StringKeySet(mapping.ClusterMappings)
+	}
+	liqoIPAM.mutex.Unlock()
+
+	clusters, err := liqoIPAM.getExistingClusters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	endpointIPs, err := liqoIPAM.getExistingEndpointIPs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	liqoIPAM.mutex.Lock()
+	defer liqoIPAM.mutex.Unlock()
+
+	var leaks []*Leak
+	current := sets.StringKeySet(liqoIPAM.clusterSubnets(false)).Union(sets.StringKeySet(liqoIPAM.clusterSubnets(true)))
+	for _, clusterID := range candidateClusters.Intersection(current).Difference(clusters).List() {
+		leak := &Leak{Kind: LeakKindClusterSubnets, ClusterID: clusterID, Reason: "the remote cluster no longer exists"}
+		if garbageCollect {
+			// The removal of the cluster configuration also releases the corresponding endpoint mappings.
+			if err := liqoIPAM.RemoveClusterConfig(clusterID); err != nil {
+				klog.Errorf("Failed to garbage collect the networks of cluster %s: %v", clusterID, err)
+			} else {
+				klog.Infof("Networks of cluster %s have been garbage collected", clusterID)
+				leak.Collected = true
+			}
+		}
+		leaks = append(leaks, leak)
+	}
+
+	endpointMappings := liqoIPAM.ipamStorage.getEndpointMappings()
+	for _, endpointIP := range sets.StringKeySet(candidateMappings).List() {
+		mapping, found := endpointMappings[endpointIP]
+		if !found {
+			continue
+		}
+
+		for _, clusterID := range candidateMappings[endpointIP].Intersection(sets.StringKeySet(mapping.ClusterMappings)).List() {
+			leak := &Leak{Kind: LeakKindEndpointMapping, ClusterID: clusterID, Ip: endpointIP}
+			switch {
+			case !endpointIPs.Has(endpointIP):
+				leak.Reason = "no pod or endpoint references the IP anymore"
+			case !clusters.Has(clusterID):
+				leak.Reason = "the remote cluster no longer exists"
+			default:
+				continue
+			}
+
+			if garbageCollect {
+				// The NAT mappings of the remote cluster may have already been terminated, hence the corresponding error is ignored.
+				if err := liqoIPAM.releaseEndpointMapping(clusterID, endpointIP); err != nil && !errors.Is(err, &liqoneterrors.MissingInit{}) {
+					klog.Errorf("Failed to garbage collect the mapping of endpoint %s for cluster %s: %v", endpointIP, clusterID, err)
+				} else {
+					klog.Infof("Mapping of endpoint %s for cluster %s has been garbage collected", endpointIP, clusterID)
+					leak.Collected = true
+				}
+			}
+			leaks = append(leaks, leak)
+		}
+	}
+
+	return leaks, nil
+}
+
+// getExistingClusters returns the IDs of the remote clusters a ForeignCluster exists for.
+func (liqoIPAM *IPAM) getExistingClusters(ctx context.Context) (sets.String, error) {
+	foreignClusters, err := liqoIPAM.dynClient.Resource(discoveryv1alpha1.ForeignClusterGroupVersionResource).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list ForeignClusters: %w", err)
+	}
+
+	clusters := sets.NewString()
+	for i := range foreignClusters.Items {
+		clusterID, _, err := unstructured.NestedString(foreignClusters.Items[i].Object, "spec", "clusterIdentity", "clusterID")
+		if err != nil {
+			return nil, fmt.Errorf("cannot retrieve the cluster ID of ForeignCluster %s: %w", foreignClusters.Items[i].GetName(), err)
+		}
+		clusters.Insert(clusterID)
+	}
+	return clusters, nil
+}
+
+// getExistingEndpointIPs returns the IPs currently assigned to pods, or referenced by endpoints.
+func (liqoIPAM *IPAM) getExistingEndpointIPs(ctx context.Context) (sets.String, error) {
+	ips := sets.NewString()
+
+	pods, err := liqoIPAM.dynClient.Resource(podsGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list pods: %w", err)
+	}
+	for i := range pods.Items {
+		podIPs, _, err := unstructured.NestedSlice(pods.Items[i].Object, "status", "podIPs")
+		if err != nil {
+			return nil, fmt.Errorf("cannot retrieve the IPs of pod %s: %w", pods.Items[i].GetName(), err)
+		}
+		for _, podIP := range podIPs {
+			if fields, ok := podIP.(map[string]interface{}); ok {
+				ip, _, _ := unstructured.NestedString(fields, "ip")
+				ips.Insert(ip)
+			}
+		}
+	}
+
+	endpointSlices, err := liqoIPAM.dynClient.Resource(endpointSlicesGVR).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("cannot list endpointslices: %w", err)
+	}
+	for i := range endpointSlices.Items {
+		endpoints, _, err := unstructured.NestedSlice(endpointSlices.Items[i].Object, "endpoints")
+		if err != nil {
+			return nil, fmt.Errorf("cannot retrieve the endpoints of endpointslice %s: %w", endpointSlices.Items[i].GetName(), err)
+		}
+		for _, endpoint := range endpoints {
+			fields, ok := endpoint.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			addresses, _, err := unstructured.NestedStringSlice(fields, "addresses")
+			if err != nil {
+				return nil, fmt.Errorf("cannot retrieve the addresses of endpointslice %s: %w", endpointSlices.Items[i].GetName(), err)
+			}
+			ips.Insert(addresses...)
+		}
+	}
+	return ips, nil
+}
+
+// networkContains returns whether the given network is entirely contained in the parent one.
+func networkContains(parent, network string) (bool, error) {
+	parentPrefix, err := netaddr.ParseIPPrefix(parent)
+	if err != nil {
+		return false, fmt.Errorf("cannot parse network %s: %w", parent, err)
+	}
+	prefix, err := netaddr.ParseIPPrefix(network)
+	if err != nil {
+		return false, fmt.Errorf("cannot parse network %s: %w", network, err)
+	}
+	return parentPrefix.Contains(prefix.IP()) && prefix.Bits() >= parentPrefix.Bits(), nil
+}
@@ -50,6 +50,8 @@ type Ipam interface {
 	- Both.
 	*/
 	GetSubnetsPerCluster(podCidr, externalCIDR, clusterID string) (string, string, error)
+	// GetSubnetsPerClusterV6 is the counterpart of GetSubnetsPerCluster for the IPv6 networks of dual-stack clusters.
+	GetSubnetsPerClusterV6(podCidr, externalCIDR, clusterID string) (string, string, error)
 	// RemoveClusterConfig deletes the IPAM configuration of a remote cluster,
 	// by freeing networks and removing data structures related to that cluster.
 	RemoveClusterConfig(clusterID string) error
@@ -66,11 +68,19 @@ type Ipam interface {
 	this function must not reserve it. If the remote cluster has not remapped
 	a local subnet, then CIDR value should be equal to "None". */
 	AddLocalSubnetsPerCluster(podCIDR, externalCIDR, clusterID string) error
+	// AddLocalSubnetsPerClusterV6 is the counterpart of AddLocalSubnetsPerCluster for the IPv6 networks of dual-stack clusters.
+	AddLocalSubnetsPerClusterV6(podCIDR, externalCIDR, clusterID string) error
 	GetExternalCIDR(mask uint8) (string, error)
+	// GetExternalCIDRV6 chooses and returns the local cluster's IPv6 ExternalCIDR.
+	GetExternalCIDRV6(mask uint8) (string, error)
 	// SetPodCIDR sets the cluster PodCIDR.
 	SetPodCIDR(podCIDR string) error
 	// SetServiceCIDR sets the cluster ServiceCIDR.
 	SetServiceCIDR(serviceCIDR string) error
+	// SetPodCIDRV6 sets the cluster IPv6 PodCIDR, in case of dual-stack clusters.
+	SetPodCIDRV6(podCIDR string) error
+	// SetServiceCIDRV6 sets the cluster IPv6 ServiceCIDR, in case of dual-stack clusters.
+	SetServiceCIDRV6(serviceCIDR string) error
 	// Terminate function enforces a graceful termination of the IPAM module.
 	Terminate()
 	IpamServer
@@ -81,6 +91,7 @@ type IPAM struct {
 	ipam               goipam.Ipamer
 	ipamStorage        IpamStorage
 	natMappingInflater natmappinginflater.Interface
+	dynClient          dynamic.Interface
 	grpcServer         *grpc.Server
 	mutex              sync.Mutex
 	UnimplementedIpamServer
@@ -98,6 +109,12 @@ var Pools = []string{
 	"172.16.0.0/12",
 }
 
+// PoolsV6 is a constant slice containing private IPv6 networks (i.e., unique local addresses),
+// used in addition to Pools in case of dual-stack clusters.
+var PoolsV6 = []string{
+	"fd00::/8",
+}
+
 const emptyCIDR = ""
 
 // Init uses the Ipam resource to retrieve and allocate reserved networks.
@@ -127,6 +144,27 @@ func (liqoIPAM *IPAM) Init(pools []string, dynClient dynamic.Interface, listenin
 			return fmt.Errorf("cannot set pools: %w", err)
 		}
 	}
+
+	// IPv6 pools may be received when the dual-stack support is enabled on an already initialized IPAM.
+	var missingPoolsV6 []string
+	for _, network := range pools {
+		if utils.IsIPv6CIDR(network) && !slice.ContainsString(ipamPools, network) {
+			missingPoolsV6 = append(missingPoolsV6, network)
+		}
+	}
+	if len(missingPoolsV6) > 0 {
+		for _, network := range missingPoolsV6 {
+			if _, err := liqoIPAM.ipam.NewPrefix(network); err != nil {
+				return fmt.Errorf("failed to create a new prefix for network %s: %w", network, err)
+			}
+			ipamPools = append(ipamPools, network)
+			klog.Infof("Pool %s has been successfully added to the pool list", network)
+		}
+		if err = liqoIPAM.ipamStorage.updatePools(ipamPools); err != nil {
+			return fmt.Errorf("cannot set pools: %w", err)
+		}
+	}
+
 	if listeningPort > 0 {
 		err = liqoIPAM.initRPCServer(listeningPort)
 		if err != nil {
@@ -135,6 +173,7 @@ func (liqoIPAM *IPAM) Init(pools []string, dynClient dynamic.Interface, listenin
 	}
 
 	liqoIPAM.natMappingInflater = natmappinginflater.NewInflater(dynClient)
+	liqoIPAM.dynClient = dynClient
 	return nil
 }
 
@@ -266,7 +305,7 @@ func (liqoIPAM *IPAM) overlapsWithCluster(network string) (overlappingCluster st
 	var overlapsWithPodCIDR bool
 	var overlapsWithExternalCIDR bool
 	// Get cluster subnets
-	clusterSubnets := liqoIPAM.ipamStorage.getClusterSubnets()
+	clusterSubnets := liqoIPAM.clusterSubnets(utils.IsIPv6CIDR(network))
 	for cluster, subnets := range clusterSubnets {
 		overlapsWithPodCIDR, err = liqoIPAM.overlapsWithNetwork(network, subnets.RemotePodCIDR)
 		if err != nil {
@@ -360,7 +399,7 @@ func (liqoIPAM *IPAM) getPoolFromNetwork(network string) (networkPool string, su
 
 func (liqoIPAM *IPAM) clusterSubnetEqualToPool(pool string) (string, error) {
 	klog.Infof("Network %s is equal to a pool, looking for a mapping..", pool)
-	mappedNetwork, err := liqoIPAM.getNetworkFromPool(utils.GetMask(pool))
+	mappedNetwork, err := liqoIPAM.getNetworkFromPool(utils.GetMask(pool), utils.IsIPv6CIDR(pool))
 	if err != nil {
 		klog.Infof("Mapping not found, acquiring the entire network pool..")
 		err = liqoIPAM.reservePoolInHalves(pool)
@@ -416,7 +455,7 @@ func (liqoIPAM *IPAM) getOrRemapNetwork(network string) (string, error) {
 		}
 	}
 	/* Network is already reserved, need a mapping */
-	mappedNetwork, err = liqoIPAM.getNetworkFromPool(utils.GetMask(network))
+	mappedNetwork, err = liqoIPAM.getNetworkFromPool(utils.GetMask(network), utils.IsIPv6CIDR(network))
 	if err != nil {
 		return "", err
 	}
@@ -433,10 +472,26 @@ func (liqoIPAM *IPAM) GetSubnetsPerCluster(
 	podCidr,
 	externalCIDR,
 	clusterID string) (mappedPodCIDR, mappedExternalCIDR string, err error) {
+	return liqoIPAM.getSubnetsPerCluster(podCidr, externalCIDR, clusterID, false)
+}
+
+// GetSubnetsPerClusterV6 behaves as GetSubnetsPerCluster, but it deals with the IPv6 networks of a dual-stack cluster.
+// IPv6 networks are taken from the IPv6 network pools, and are tracked separately from the IPv4 ones.
+func (liqoIPAM *IPAM) GetSubnetsPerClusterV6(
+	podCidr,
+	externalCIDR,
+	clusterID string) (mappedPodCIDR, mappedExternalCIDR string, err error) {
+	return liqoIPAM.getSubnetsPerCluster(podCidr, externalCIDR, clusterID, true)
+}
+
+func (liqoIPAM *IPAM) getSubnetsPerCluster(
+	podCidr,
+	externalCIDR,
+	clusterID string, ipv6 bool) (mappedPodCIDR, mappedExternalCIDR string, err error) {
 	var exists bool
 
 	// Get subnets of clusters
-	clusterSubnets := liqoIPAM.ipamStorage.getClusterSubnets()
+	clusterSubnets := liqoIPAM.clusterSubnets(ipv6)
 
 	// Check existence
 	subnets, exists := clusterSubnets[clusterID]
@@ -449,6 +504,9 @@ func (liqoIPAM *IPAM) GetSubnetsPerCluster(
 	if err != nil {
 		return "", "", fmt.Errorf("PodCidr is an invalid CIDR: %w", err)
 	}
+	if utils.IsIPv6CIDR(podCidr) != ipv6 {
+		return "", "", fmt.Errorf("PodCidr %s does not belong to the expected IP family", podCidr)
+	}
 
 	klog.Infof("Cluster networks allocation request received: %s", clusterID)
 
@@ -465,6 +523,10 @@ func (liqoIPAM *IPAM) GetSubnetsPerCluster(
 	if err != nil {
 		return "", "", fmt.Errorf("ExternalCIDR is an invalid CIDR: %w", err)
 	}
+	if utils.IsIPv6CIDR(externalCIDR) != ipv6 {
+		_ = liqoIPAM.FreeReservedSubnet(mappedPodCIDR)
+		return "", "", fmt.Errorf("ExternalCIDR %s does not belong to the expected IP family", externalCIDR)
+	}
 
 	// Get ExternalCIDR
 	mappedExternalCIDR, err = liqoIPAM.getOrRemapNetwork(externalCIDR)
@@ -491,7 +553,7 @@ func (liqoIPAM *IPAM) GetSubnetsPerCluster(
 	clusterSubnets[clusterID] = subnets
 
 	// Push it in clusterSubnets
-	if err := liqoIPAM.ipamStorage.updateClusterSubnets(clusterSubnets); err != nil {
+	if err := liqoIPAM.updateClusterSubnets(clusterSubnets, ipv6); err != nil {
 		_ = liqoIPAM.FreeReservedSubnet(mappedPodCIDR)
 		_ = liqoIPAM.FreeReservedSubnet(mappedExternalCIDR)
 		return "", "", fmt.Errorf("cannot update cluster subnets: %w", err)
@@ -499,12 +561,15 @@ func (liqoIPAM *IPAM) GetSubnetsPerCluster(
 	return mappedPodCIDR, mappedExternalCIDR, nil
 }
 
-// getNetworkFromPool returns a network with mask length equal to mask taken by a network pool.
-func (liqoIPAM *IPAM) getNetworkFromPool(mask uint8) (string, error) {
+// getNetworkFromPool returns a network with mask length equal to mask taken by a network pool of the given IP family.
+func (liqoIPAM *IPAM) getNetworkFromPool(mask uint8, ipv6 bool) (string, error) {
 	// Get network pools
 	pools := liqoIPAM.ipamStorage.getPools()
 	// For each pool, try to get a network with mask length mask
 	for _, pool := range pools {
+		if utils.IsIPv6CIDR(pool) != ipv6 {
+			continue
+		}
 		if mappedNetwork, err := liqoIPAM.ipam.AcquireChildPrefix(pool, mask); err == nil {
 			klog.Infof("Acquired network %s", mappedNetwork)
 			return mappedNetwork.String(), nil
@@ -581,7 +646,7 @@ func (liqoIPAM *IPAM) FreeReservedSubnet(network string) error {
 
 // eventuallyDeleteClusterSubnet deletes cluster entry from cluster subnets if all fields are deleted (empty string).
 func (liqoIPAM *IPAM) eventuallyDeleteClusterSubnet(clusterID string,
-	clusterSubnets map[string]netv1alpha1.Subnets) error {
+	clusterSubnets map[string]netv1alpha1.Subnets, ipv6 bool) error {
 	// Get entry of cluster
 	subnets := clusterSubnets[clusterID]
 
@@ -594,7 +659,7 @@ func (liqoIPAM *IPAM) eventuallyDeleteClusterSubnet(clusterID string,
 		delete(clusterSubnets, clusterID)
 	}
 	// Update
-	if err := liqoIPAM.ipamStorage.updateClusterSubnets(clusterSubnets); err != nil {
+	if err := liqoIPAM.updateClusterSubnets(clusterSubnets, ipv6); err != nil {
 		return err
 	}
 	return nil
@@ -603,8 +668,7 @@ func (liqoIPAM *IPAM) eventuallyDeleteClusterSubnet(clusterID string,
 // RemoveClusterConfig frees remote PodCIDR and ExternalCIDR and
 // deletes local subnets for the remote cluster.
 func (liqoIPAM *IPAM) RemoveClusterConfig(clusterID string) error {
-	var subnets netv1alpha1.Subnets
-	var subnetsExist, natMappingsPerClusterConfigured bool
+	var subnetsExist, subnetsV6Exist, natMappingsPerClusterConfigured bool
 
 	if clusterID == "" {
 		return &liqoneterrors.WrongParameter{
@@ -615,35 +679,26 @@ func (liqoIPAM *IPAM) RemoveClusterConfig(clusterID string) error {
 
 	// Get cluster subnets
 	clusterSubnets := liqoIPAM.ipamStorage.getClusterSubnets()
+	clusterSubnetsV6 := liqoIPAM.ipamStorage.getClusterSubnetsV6()
 
 	// Get NatMappingsConfigured map
 	natMappingsConfigured := liqoIPAM.ipamStorage.getNatMappingsConfigured()
 
-	subnets, subnetsExist = clusterSubnets[clusterID]
+	_, subnetsExist = clusterSubnets[clusterID]
+	_, subnetsV6Exist = clusterSubnetsV6[clusterID]
 	_, natMappingsPerClusterConfigured = natMappingsConfigured[clusterID]
-	if !subnetsExist && !natMappingsPerClusterConfigured {
+	if !subnetsExist && !subnetsV6Exist && !natMappingsPerClusterConfigured {
 		// Nothing to be done here
 		return nil
 	}
 
-	// If an error happened after the following if, there is no need of
-	// re-executing the following block.
-	if subnetsExist {
-		// Free PodCidr
-		if err := liqoIPAM.FreeReservedSubnet(subnets.RemotePodCIDR); err != nil {
-			return err
-		}
-
-		// Free ExternalCidr
-		if err := liqoIPAM.FreeReservedSubnet(subnets.RemoteExternalCIDR); err != nil {
-			return err
-		}
-		klog.Infof("Networks assigned to cluster %s have just been freed", clusterID)
-
-		delete(clusterSubnets, clusterID)
-		if err := liqoIPAM.ipamStorage.updateClusterSubnets(clusterSubnets); err != nil {
-			return fmt.Errorf("cannot update clusterSubnets: %w", err)
-		}
+	// If an error happened after the following calls, there is no need of
+	// re-executing them, since the cluster entries have already been removed.
+	if err := liqoIPAM.freeClusterSubnets(clusterID, clusterSubnets, false); err != nil {
+		return err
+	}
+	if err := liqoIPAM.freeClusterSubnets(clusterID, clusterSubnetsV6, true); err != nil {
+		return err
 	}
 
 	// Terminate NatMappings
@@ -660,17 +715,46 @@ func (liqoIPAM *IPAM) RemoveClusterConfig(clusterID string) error {
 	return nil
 }
 
-// initNatMappingsPerCluster is a wrapper for inflater InitNatMappingsPerCluster.
-func (liqoIPAM *IPAM) initNatMappingsPerCluster(clusterID string, subnets netv1alpha1.Subnets) error {
+// freeClusterSubnets frees the remote PodCIDR and ExternalCIDR assigned to a cluster for the given IP family,
+// and removes the cluster entry from the cluster subnets.
+func (liqoIPAM *IPAM) freeClusterSubnets(clusterID string, clusterSubnets map[string]netv1alpha1.Subnets, ipv6 bool) error {
+	subnets, exists := clusterSubnets[clusterID]
+	if !exists {
+		return nil
+	}
+
+	// Free PodCidr
+	if err := liqoIPAM.FreeReservedSubnet(subnets.RemotePodCIDR); err != nil {
+		return err
+	}
+
+	// Free ExternalCidr
+	if err := liqoIPAM.FreeReservedSubnet(subnets.RemoteExternalCIDR); err != nil {
+		return err
+	}
+	klog.Infof("Networks assigned to cluster %s have just been freed", clusterID)
+
+	delete(clusterSubnets, clusterID)
+	if err := liqoIPAM.updateClusterSubnets(clusterSubnets, ipv6); err != nil {
+		return fmt.Errorf("cannot update clusterSubnets: %w", err)
+	}
+	return nil
+}
+
+// initNatMappingsPerCluster is a wrapper for inflater InitNatMappingsPerCluster and InitNatMappingsPerClusterV6.
+func (liqoIPAM *IPAM) initNatMappingsPerCluster(clusterID string, subnets netv1alpha1.Subnets, ipv6 bool) error {
 	// InitNatMappingsPerCluster does need the Pod CIDR used in home cluster for remote pods (subnets.RemotePodCIDR)
 	// and the ExternalCIDR used in remote cluster for local exported resources.
 	var externalCIDR string
 	if subnets.LocalNATExternalCIDR == consts.DefaultCIDRValue {
 		// Remote cluster has not remapped home ExternalCIDR
-		externalCIDR = liqoIPAM.ipamStorage.getExternalCIDR()
+		externalCIDR = liqoIPAM.externalCIDR(ipv6)
 	} else {
 		externalCIDR = subnets.LocalNATExternalCIDR
 	}
+	if ipv6 {
+		return liqoIPAM.natMappingInflater.InitNatMappingsPerClusterV6(subnets.RemotePodCIDR, externalCIDR, clusterID)
+	}
 	return liqoIPAM.natMappingInflater.InitNatMappingsPerCluster(subnets.RemotePodCIDR, externalCIDR, clusterID)
 }
 
@@ -708,7 +792,7 @@ func (liqoIPAM *IPAM) terminateNatMappingsPerCluster(clusterID string) error {
 			// There are no more clusters using this endpoint IP
 
 			// Get local ExternalCIDR
-			localExternalCIDR := liqoIPAM.ipamStorage.getExternalCIDR()
+			localExternalCIDR := liqoIPAM.externalCIDR(utils.IsIPv6(ip))
 			if localExternalCIDR == emptyCIDR {
 				return fmt.Errorf("cannot get ExternalCIDR: %w", err)
 			}
@@ -788,13 +872,13 @@ func (liqoIPAM *IPAM) RemoveNetworkPool(network string) error {
 	// Get resource
 	ipamPools := liqoIPAM.ipamStorage.getPools()
 	// Get cluster subnets
-	clusterSubnets := liqoIPAM.ipamStorage.getClusterSubnets()
+	clusterSubnets := liqoIPAM.clusterSubnets(utils.IsIPv6CIDR(network))
 	// Check existence
 	if exists := slice.ContainsString(ipamPools, network); !exists {
 		return fmt.Errorf("network %s is not a network pool", network)
 	}
 	// Cannot remove a default one
-	if contains := slice.ContainsString(Pools, network); contains {
+	if slice.ContainsString(Pools, network) || slice.ContainsString(PoolsV6, network) {
 		return fmt.Errorf("cannot remove a default network pool")
 	}
 	// Check overlapping with cluster networks
@@ -884,7 +968,7 @@ func (liqoIPAM *IPAM) AddLocalSubnetsPerCluster(podCIDR, externalCIDR, clusterID
 	}
 
 	// Init NAT mappings
-	if err := liqoIPAM.initNatMappingsPerCluster(clusterID, subnets); err != nil {
+	if err := liqoIPAM.initNatMappingsPerCluster(clusterID, subnets, false); err != nil {
 		return fmt.Errorf("unable to initialize NAT mappings per cluster %s: %w", clusterID, err)
 	}
 
@@ -896,45 +980,91 @@ func (liqoIPAM *IPAM) AddLocalSubnetsPerCluster(podCIDR, externalCIDR, clusterID
 	return nil
 }
 
-// RemoveLocalSubnetsPerCluster deletes networks related to a cluster.
-func (liqoIPAM *IPAM) RemoveLocalSubnetsPerCluster(clusterID string) error {
-	var exists bool
-	var subnets netv1alpha1.Subnets
+// AddLocalSubnetsPerClusterV6 stores how the IPv6 PodCIDR and ExternalCIDR of local cluster have been remapped
+// in a remote dual-stack cluster. It has to be called after AddLocalSubnetsPerCluster, which initializes the NAT mappings.
+func (liqoIPAM *IPAM) AddLocalSubnetsPerClusterV6(podCIDR, externalCIDR, clusterID string) error {
+	if clusterID == "" {
+		return &liqoneterrors.WrongParameter{
+			Parameter: consts.ClusterIDLabelName,
+			Reason:    liqoneterrors.StringNotEmpty,
+		}
+	}
 
 	// Get cluster subnets
-	clusterSubnets := liqoIPAM.ipamStorage.getClusterSubnets()
-	// Check existence
-	subnets, exists = clusterSubnets[clusterID]
-	if !exists || (subnets.LocalNATPodCIDR == "" && subnets.LocalNATExternalCIDR == "") {
-		return nil
+	clusterSubnets := liqoIPAM.ipamStorage.getClusterSubnetsV6()
+	subnets, exists := clusterSubnets[clusterID]
+	if !exists {
+		return fmt.Errorf("remote IPv6 subnets for cluster %s do not exist yet. Call first GetSubnetsPerClusterV6",
+			clusterID)
 	}
 
-	// Unset networks
-	subnets.LocalNATPodCIDR = ""
-	subnets.LocalNATExternalCIDR = ""
-	clusterSubnets[clusterID] = subnets
+	if subnets.LocalNATPodCIDR != podCIDR || subnets.LocalNATExternalCIDR != externalCIDR {
+		subnets.LocalNATPodCIDR = podCIDR
+		subnets.LocalNATExternalCIDR = externalCIDR
+		clusterSubnets[clusterID] = subnets
+		klog.Infof("Local NAT IPv6 PodCIDR of cluster %s set to %s", clusterID, podCIDR)
+		klog.Infof("Local NAT IPv6 ExternalCIDR of cluster %s set to %s", clusterID, externalCIDR)
 
-	klog.Infof("Local NAT networks of cluster %s deleted", clusterID)
-	if err := liqoIPAM.eventuallyDeleteClusterSubnet(clusterID, clusterSubnets); err != nil {
-		return err
+		// Push it in clusterSubnets
+		if err := liqoIPAM.ipamStorage.updateClusterSubnetsV6(clusterSubnets); err != nil {
+			return fmt.Errorf("cannot update cluster subnets: %w", err)
+		}
+	}
+
+	// Init NAT mappings
+	if err := liqoIPAM.initNatMappingsPerCluster(clusterID, subnets, true); err != nil {
+		return fmt.Errorf("unable to initialize IPv6 NAT mappings per cluster %s: %w", clusterID, err)
+	}
+	return nil
+}
+
+// RemoveLocalSubnetsPerCluster deletes networks related to a cluster, for both IP families.
+func (liqoIPAM *IPAM) RemoveLocalSubnetsPerCluster(clusterID string) error {
+	for _, ipv6 := range []bool{false, true} {
+		// Get cluster subnets
+		clusterSubnets := liqoIPAM.clusterSubnets(ipv6)
+		// Check existence
+		subnets, exists := clusterSubnets[clusterID]
+		if !exists || (subnets.LocalNATPodCIDR == "" && subnets.LocalNATExternalCIDR == "") {
+			continue
+		}
+
+		// Unset networks
+		subnets.LocalNATPodCIDR = ""
+		subnets.LocalNATExternalCIDR = ""
+		clusterSubnets[clusterID] = subnets
+
+		klog.Infof("Local NAT networks of cluster %s deleted", clusterID)
+		if err := liqoIPAM.eventuallyDeleteClusterSubnet(clusterID, clusterSubnets, ipv6); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
 // GetExternalCIDR chooses and returns the local cluster's ExternalCIDR.
 func (liqoIPAM *IPAM) GetExternalCIDR(mask uint8) (string, error) {
+	return liqoIPAM.getExternalCIDR(mask, false)
+}
+
+// GetExternalCIDRV6 chooses and returns the local cluster's IPv6 ExternalCIDR, taken from the IPv6 network pools.
+func (liqoIPAM *IPAM) GetExternalCIDRV6(mask uint8) (string, error) {
+	return liqoIPAM.getExternalCIDR(mask, true)
+}
+
+func (liqoIPAM *IPAM) getExternalCIDR(mask uint8, ipv6 bool) (string, error) {
 	var externalCIDR string
 	var err error
 
 	// Get cluster ExternalCIDR
-	externalCIDR = liqoIPAM.ipamStorage.getExternalCIDR()
+	externalCIDR = liqoIPAM.externalCIDR(ipv6)
 	if externalCIDR != "" {
 		return externalCIDR, nil
 	}
-	if externalCIDR, err = liqoIPAM.getNetworkFromPool(mask); err != nil {
+	if externalCIDR, err = liqoIPAM.getNetworkFromPool(mask, ipv6); err != nil {
 		return "", fmt.Errorf("cannot allocate an ExternalCIDR: %w", err)
 	}
-	if err := liqoIPAM.ipamStorage.updateExternalCIDR(externalCIDR); err != nil {
+	if err := liqoIPAM.updateExternalCIDR(externalCIDR, ipv6); err != nil {
 		_ = liqoIPAM.FreeReservedSubnet(externalCIDR)
 		return "", fmt.Errorf("cannot update ExternalCIDR: %w", err)
 	}
@@ -960,7 +1090,7 @@ func (liqoIPAM *IPAM) belongsToPodCIDRInternal(ip string) (bool, error) {
 		}
 	}
 
-	podCIDR := liqoIPAM.ipamStorage.getPodCIDR()
+	podCIDR := liqoIPAM.podCIDR(utils.IsIPv6(ip))
 	if podCIDR == "" {
 		return false, fmt.Errorf("the pod CIDR is not set")
 	}
@@ -987,7 +1117,7 @@ func (liqoIPAM *IPAM) mapIPToExternalCIDR(clusterID, remoteExternalCIDR, ip stri
 	endpointMappings := liqoIPAM.ipamStorage.getEndpointMappings()
 
 	// Get local ExternalCIDR
-	localExternalCIDR := liqoIPAM.ipamStorage.getExternalCIDR()
+	localExternalCIDR := liqoIPAM.externalCIDR(utils.IsIPv6(ip))
 
 	if remoteExternalCIDR == "None" {
 		externalCIDR = localExternalCIDR
@@ -1052,15 +1182,16 @@ func (liqoIPAM *IPAM) mapEndpointIPInternal(clusterID, ip string) (string, error
 	liqoIPAM.mutex.Lock()
 	defer liqoIPAM.mutex.Unlock()
 
-	// Get cluster subnets
-	clusterSubnets := liqoIPAM.ipamStorage.getClusterSubnets()
+	// Get cluster subnets, for the family of the endpoint IP
+	ipv6 := utils.IsIPv6(ip)
+	clusterSubnets := liqoIPAM.clusterSubnets(ipv6)
 	subnets, exists = clusterSubnets[clusterID]
 	if !exists {
 		return "", fmt.Errorf("cluster %s has not a network configuration", clusterID)
 	}
 
 	// Get PodCIDR
-	podCIDR := liqoIPAM.ipamStorage.getPodCIDR()
+	podCIDR := liqoIPAM.podCIDR(ipv6)
 	if podCIDR == emptyCIDR {
 		return "", fmt.Errorf("cannot get cluster PodCIDR: %w", err)
 	}
@@ -1156,7 +1287,7 @@ func (liqoIPAM *IPAM) getHomePodIPInternal(clusterID, ip string) (string, error)
 	defer liqoIPAM.mutex.Unlock()
 
 	// Get cluster subnets
-	clusterSubnets := liqoIPAM.ipamStorage.getClusterSubnets()
+	clusterSubnets := liqoIPAM.clusterSubnets(utils.IsIPv6(ip))
 	subnets, exists := clusterSubnets[clusterID]
 
 	// Check if RemotePodCIDR is set
@@ -1178,8 +1309,6 @@ func (liqoIPAM *IPAM) getHomePodIPInternal(clusterID, ip string) (string, error)
 // unmapEndpointIPInternal is the internal implementation of UnmapEndpointIP.
 // If the endpointIP is not reflected anymore in any remote cluster, then it frees the corresponding ExternalCIDR IP.
 func (liqoIPAM *IPAM) unmapEndpointIPInternal(clusterID, endpointIP string) error {
-	var exists bool
-
 	err := validateEndpointMappingInputs(clusterID, endpointIP)
 	if err != nil {
 		return err
@@ -1188,11 +1317,17 @@ func (liqoIPAM *IPAM) unmapEndpointIPInternal(clusterID, endpointIP string) erro
 	liqoIPAM.mutex.Lock()
 	defer liqoIPAM.mutex.Unlock()
 
+	return liqoIPAM.releaseEndpointMapping(clusterID, endpointIP)
+}
+
+// releaseEndpointMapping removes the given cluster from the ones the endpointIP is reflected to,
+// and frees the corresponding ExternalCIDR IP if no longer used. It shall be called holding the mutex.
+func (liqoIPAM *IPAM) releaseEndpointMapping(clusterID, endpointIP string) error {
 	// Get endpointMappings
 	endpointMappings := liqoIPAM.ipamStorage.getEndpointMappings()
 
 	// Get local ExternalCIDR
-	localExternalCIDR := liqoIPAM.ipamStorage.getExternalCIDR()
+	localExternalCIDR := liqoIPAM.externalCIDR(utils.IsIPv6(endpointIP))
 
 	endpointMapping, exists := endpointMappings[endpointIP]
 	if !exists {
@@ -1293,6 +1428,52 @@ func (liqoIPAM *IPAM) SetServiceCIDR(serviceCIDR string) error {
 	return nil
 }
 
+// SetPodCIDRV6 sets the IPv6 PodCIDR.
+func (liqoIPAM *IPAM) SetPodCIDRV6(podCIDR string) error {
+	if !utils.IsIPv6CIDR(podCIDR) {
+		return fmt.Errorf("PodCIDR %s is not an IPv6 network", podCIDR)
+	}
+	oldPodCIDR := liqoIPAM.ipamStorage.getPodCIDRV6()
+	if oldPodCIDR != "" && oldPodCIDR != podCIDR {
+		return fmt.Errorf("trying to change IPv6 PodCIDR")
+	}
+	if oldPodCIDR != "" && oldPodCIDR == podCIDR {
+		return nil
+	}
+	// Acquire PodCIDR
+	if err := liqoIPAM.AcquireReservedSubnet(podCIDR); err != nil {
+		return fmt.Errorf("cannot acquire IPv6 PodCIDR: %w", err)
+	}
+	// Update PodCIDR
+	if err := liqoIPAM.ipamStorage.updatePodCIDRV6(podCIDR); err != nil {
+		return fmt.Errorf("cannot set IPv6 PodCIDR: %w", err)
+	}
+	return nil
+}
+
+// SetServiceCIDRV6 sets the IPv6 ServiceCIDR.
+func (liqoIPAM *IPAM) SetServiceCIDRV6(serviceCIDR string) error {
+	if !utils.IsIPv6CIDR(serviceCIDR) {
+		return fmt.Errorf("ServiceCIDR %s is not an IPv6 network", serviceCIDR)
+	}
+	oldServiceCIDR := liqoIPAM.ipamStorage.getServiceCIDRV6()
+	if oldServiceCIDR != "" && oldServiceCIDR != serviceCIDR {
+		return fmt.Errorf("trying to change IPv6 ServiceCIDR")
+	}
+	if oldServiceCIDR != "" && oldServiceCIDR == serviceCIDR {
+		return nil
+	}
+	// Acquire Service CIDR
+	if err := liqoIPAM.AcquireReservedSubnet(serviceCIDR); err != nil {
+		return fmt.Errorf("cannot acquire IPv6 ServiceCIDR: %w", err)
+	}
+	// Update Service CIDR
+	if err := liqoIPAM.ipamStorage.updateServiceCIDRV6(serviceCIDR); err != nil {
+		return fmt.Errorf("cannot set IPv6 ServiceCIDR: %w", err)
+	}
+	return nil
+}
+
 // SetReservedSubnets acquires and/or frees the reserved networks.
 func (liqoIPAM *IPAM) SetReservedSubnets(subnets []string) error {
 	reserved := liqoIPAM.ipamStorage.getReservedSubnets()
@@ -1347,8 +1528,10 @@ func (liqoIPAM *IPAM) SetReservedSubnets(subnets []string) error {
 }
 
 func (liqoIPAM *IPAM) reservedSubnetOverlaps(subnet string) error {
+	ipv6 := utils.IsIPv6CIDR(subnet)
+
 	// Check if subnet overlaps with local pod CIDR.
-	podCidr := liqoIPAM.ipamStorage.getPodCIDR()
+	podCidr := liqoIPAM.podCIDR(ipv6)
 	overlaps, err := liqoIPAM.overlapsWithNetwork(subnet, podCidr)
 	if err != nil {
 		return err
@@ -1359,7 +1542,7 @@ func (liqoIPAM *IPAM) reservedSubnetOverlaps(subnet string) error {
 	}
 
 	// Check if subnet overlaps with local service CIDR.
-	serviceCidr := liqoIPAM.ipamStorage.getServiceCIDR()
+	serviceCidr := liqoIPAM.serviceCIDR(ipv6)
 	overlaps, err = liqoIPAM.overlapsWithNetwork(subnet, serviceCidr)
 	if err != nil {
 		return err
@@ -1370,7 +1553,7 @@ func (liqoIPAM *IPAM) reservedSubnetOverlaps(subnet string) error {
 	}
 
 	// Check if subnet overlaps with local external CIDR.
-	externalCidr := liqoIPAM.ipamStorage.getExternalCIDR()
+	externalCidr := liqoIPAM.externalCIDR(ipv6)
 	overlaps, err = liqoIPAM.overlapsWithNetwork(subnet, externalCidr)
 	if err != nil {
 		return err
@@ -1402,3 +1585,51 @@ func (liqoIPAM *IPAM) reservedSubnetOverlaps(subnet string) error {
 
 	return nil
 }
+
+// clusterSubnets returns the networks of the remote clusters for the given IP family.
+func (liqoIPAM *IPAM) clusterSubnets(ipv6 bool) map[string]netv1alpha1.Subnets {
+	if ipv6 {
+		return liqoIPAM.ipamStorage.getClusterSubnetsV6()
+	}
+	return liqoIPAM.ipamStorage.getClusterSubnets()
+}
+
+// updateClusterSubnets stores the networks of the remote clusters for the given IP family.
+func (liqoIPAM *IPAM) updateClusterSubnets(clusterSubnets map[string]netv1alpha1.Subnets, ipv6 bool) error {
+	if ipv6 {
+		return liqoIPAM.ipamStorage.updateClusterSubnetsV6(clusterSubnets)
+	}
+	return liqoIPAM.ipamStorage.updateClusterSubnets(clusterSubnets)
+}
+
+// podCIDR returns the local PodCIDR for the given IP family.
+func (liqoIPAM *IPAM) podCIDR(ipv6 bool) string {
+	if ipv6 {
+		return liqoIPAM.ipamStorage.getPodCIDRV6()
+	}
+	return liqoIPAM.ipamStorage.getPodCIDR()
+}
+
+// serviceCIDR returns the local ServiceCIDR for the given IP family.
+func (liqoIPAM *IPAM) serviceCIDR(ipv6 bool) string {
+	if ipv6 {
+		return liqoIPAM.ipamStorage.getServiceCIDRV6()
+	}
+	return liqoIPAM.ipamStorage.getServiceCIDR()
+}
+
+// externalCIDR returns the local ExternalCIDR for the given IP family.
+func (liqoIPAM *IPAM) externalCIDR(ipv6 bool) string {
+	if ipv6 {
+		return liqoIPAM.ipamStorage.getExternalCIDRV6()
+	}
+	return liqoIPAM.ipamStorage.getExternalCIDR()
+}
+
+// updateExternalCIDR stores the local ExternalCIDR for the given IP family.
+func (liqoIPAM *IPAM) updateExternalCIDR(externalCIDR string, ipv6 bool) error {
+	if ipv6 {
+		return liqoIPAM.ipamStorage.updateExternalCIDRV6(externalCIDR)
+	}
+	return liqoIPAM.ipamStorage.updateExternalCIDR(externalCIDR)
+}
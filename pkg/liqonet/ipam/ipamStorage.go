@@ -47,6 +47,10 @@ const (
 	podCIDRUpdate               = "podCIDR"
 	serviceCIDRUpdate           = "serviceCIDR"
 	natMappingsConfiguredUpdate = "natMappingsConfigured"
+	clusterSubnetV6Update       = "clusterSubnetsV6"
+	externalCIDRV6Update        = "externalCIDRv6"
+	podCIDRV6Update             = "podCIDRv6"
+	serviceCIDRV6Update         = "serviceCIDRv6"
 	updateOpAdd                 = "add"
 	updateOpRemove              = "remove"
 )
@@ -69,6 +73,14 @@ type IpamStorage interface {
 	getServiceCIDR() string
 	getReservedSubnets() []string
 	getNatMappingsConfigured() map[string]netv1alpha1.ConfiguredCluster
+	updateClusterSubnetsV6(clusterSubnet map[string]netv1alpha1.Subnets) error
+	updateExternalCIDRV6(externalCIDR string) error
+	updatePodCIDRV6(podCIDR string) error
+	updateServiceCIDRV6(serviceCIDR string) error
+	getClusterSubnetsV6() map[string]netv1alpha1.Subnets
+	getExternalCIDRV6() string
+	getPodCIDRV6() string
+	getServiceCIDRV6() string
 	goipam.Storage
 }
 
@@ -252,6 +264,22 @@ func (ipamStorage *IPAMStorage) updateNatMappingsConfigured(natMappingsConfigure
 	return ipamStorage.updateConfig(natMappingsConfiguredUpdate, natMappingsConfigured)
 }
 
+func (ipamStorage *IPAMStorage) updateClusterSubnetsV6(clusterSubnets map[string]netv1alpha1.Subnets) error {
+	return ipamStorage.updateConfig(clusterSubnetV6Update, clusterSubnets)
+}
+
+func (ipamStorage *IPAMStorage) updateExternalCIDRV6(externalCIDR string) error {
+	return ipamStorage.updateConfig(externalCIDRV6Update, externalCIDR)
+}
+
+func (ipamStorage *IPAMStorage) updatePodCIDRV6(podCIDR string) error {
+	return ipamStorage.updateConfig(podCIDRV6Update, podCIDR)
+}
+
+func (ipamStorage *IPAMStorage) updateServiceCIDRV6(serviceCIDR string) error {
+	return ipamStorage.updateConfig(serviceCIDRV6Update, serviceCIDR)
+}
+
 func (ipamStorage *IPAMStorage) updateConfig(updateType string, data interface{}) error {
 	jsonData, err := json.Marshal(data)
 	if err != nil {
@@ -259,9 +287,11 @@ func (ipamStorage *IPAMStorage) updateConfig(updateType string, data interface{}
 		return err
 	}
 
+	// The "add" operation replaces the value if the field is already present, and it is required
+	// to set the optional fields (e.g., the IPv6 ones), which may be omitted from the resource.
 	var b bytes.Buffer
 	patch := fmt.Sprintf(
-		`[{"op": "replace", "path": "/spec/%s", "value": `,
+		`[{"op": "add", "path": "/spec/%s", "value": `,
 		updateType)
 	b.WriteString(patch)
 	b.Write(jsonData)
@@ -317,6 +347,27 @@ func (ipamStorage *IPAMStorage) getNatMappingsConfigured() map[string]netv1alpha
 	return ipamStorage.getConfig().Spec.NatMappingsConfigured
 }
 
+func (ipamStorage *IPAMStorage) getClusterSubnetsV6() map[string]netv1alpha1.Subnets {
+	clusterSubnets := ipamStorage.getConfig().Spec.ClusterSubnetsV6
+	if clusterSubnets == nil {
+		// The field is optional, hence it may be missing in case of resources created by older versions.
+		clusterSubnets = make(map[string]netv1alpha1.Subnets)
+	}
+	return clusterSubnets
+}
+
+func (ipamStorage *IPAMStorage) getExternalCIDRV6() string {
+	return ipamStorage.getConfig().Spec.ExternalCIDRv6
+}
+
+func (ipamStorage *IPAMStorage) getPodCIDRV6() string {
+	return ipamStorage.getConfig().Spec.PodCIDRv6
+}
+
+func (ipamStorage *IPAMStorage) getServiceCIDRV6() string {
+	return ipamStorage.getConfig().Spec.ServiceCIDRv6
+}
+
 func (ipamStorage *IPAMStorage) getConfig() *netv1alpha1.IpamStorage {
 	ipamStorage.RLock()
 	defer ipamStorage.RUnlock()
@@ -370,6 +421,7 @@ func (ipamStorage *IPAMStorage) createConfig() (*netv1alpha1.IpamStorage, error)
 			Prefixes:              make(map[string][]byte),
 			Pools:                 make([]string, 0),
 			ClusterSubnets:        make(map[string]netv1alpha1.Subnets),
+			ClusterSubnetsV6:      make(map[string]netv1alpha1.Subnets),
 			EndpointMappings:      make(map[string]netv1alpha1.EndpointMapping),
 			NatMappingsConfigured: make(map[string]netv1alpha1.ConfiguredCluster),
 			ReservedSubnets:       []string{},
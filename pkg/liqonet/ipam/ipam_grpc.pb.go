@@ -27,6 +27,10 @@ type IpamClient interface {
 	UnmapEndpointIP(ctx context.Context, in *UnmapRequest, opts ...grpc.CallOption) (*UnmapResponse, error)
 	GetHomePodIP(ctx context.Context, in *GetHomePodIPRequest, opts ...grpc.CallOption) (*GetHomePodIPResponse, error)
 	BelongsToPodCIDR(ctx context.Context, in *BelongsRequest, opts ...grpc.CallOption) (*BelongsResponse, error)
+	ListPools(ctx context.Context, in *ListPoolsRequest, opts ...grpc.CallOption) (*ListPoolsResponse, error)
+	ListClusterSubnets(ctx context.Context, in *ListClusterSubnetsRequest, opts ...grpc.CallOption) (*ListClusterSubnetsResponse, error)
+	ListEndpointMappings(ctx context.Context, in *ListEndpointMappingsRequest, opts ...grpc.CallOption) (*ListEndpointMappingsResponse, error)
+	DetectLeaks(ctx context.Context, in *DetectLeaksRequest, opts ...grpc.CallOption) (*DetectLeaksResponse, error)
 }
 
 type ipamClient struct {
@@ -73,6 +77,42 @@ func (c *ipamClient) BelongsToPodCIDR(ctx context.Context, in *BelongsRequest, o
 	return out, nil
 }
 
+func (c *ipamClient) ListPools(ctx context.Context, in *ListPoolsRequest, opts ...grpc.CallOption) (*ListPoolsResponse, error) {
+	out := new(ListPoolsResponse)
+	err := c.cc.Invoke(ctx, "/ipam/ListPools", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ipamClient) ListClusterSubnets(ctx context.Context, in *ListClusterSubnetsRequest, opts ...grpc.CallOption) (*ListClusterSubnetsResponse, error) {
+	out := new(ListClusterSubnetsResponse)
+	err := c.cc.Invoke(ctx, "/ipam/ListClusterSubnets", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ipamClient) ListEndpointMappings(ctx context.Context, in *ListEndpointMappingsRequest, opts ...grpc.CallOption) (*ListEndpointMappingsResponse, error) {
+	out := new(ListEndpointMappingsResponse)
+	err := c.cc.Invoke(ctx, "/ipam/ListEndpointMappings", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ipamClient) DetectLeaks(ctx context.Context, in *DetectLeaksRequest, opts ...grpc.CallOption) (*DetectLeaksResponse, error) {
+	out := new(DetectLeaksResponse)
+	err := c.cc.Invoke(ctx, "/ipam/DetectLeaks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // IpamServer is the server API for Ipam service.
 // All implementations must embed UnimplementedIpamServer
 // for forward compatibility
@@ -81,6 +121,10 @@ type IpamServer interface {
 	UnmapEndpointIP(context.Context, *UnmapRequest) (*UnmapResponse, error)
 	GetHomePodIP(context.Context, *GetHomePodIPRequest) (*GetHomePodIPResponse, error)
 	BelongsToPodCIDR(context.Context, *BelongsRequest) (*BelongsResponse, error)
+	ListPools(context.Context, *ListPoolsRequest) (*ListPoolsResponse, error)
+	ListClusterSubnets(context.Context, *ListClusterSubnetsRequest) (*ListClusterSubnetsResponse, error)
+	ListEndpointMappings(context.Context, *ListEndpointMappingsRequest) (*ListEndpointMappingsResponse, error)
+	DetectLeaks(context.Context, *DetectLeaksRequest) (*DetectLeaksResponse, error)
 	mustEmbedUnimplementedIpamServer()
 }
 
@@ -100,6 +144,18 @@ func (UnimplementedIpamServer) GetHomePodIP(context.Context, *GetHomePodIPReques
 func (UnimplementedIpamServer) BelongsToPodCIDR(context.Context, *BelongsRequest) (*BelongsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method BelongsToPodCIDR not implemented")
 }
+func (UnimplementedIpamServer) ListPools(context.Context, *ListPoolsRequest) (*ListPoolsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPools not implemented")
+}
+func (UnimplementedIpamServer) ListClusterSubnets(context.Context, *ListClusterSubnetsRequest) (*ListClusterSubnetsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListClusterSubnets not implemented")
+}
+func (UnimplementedIpamServer) ListEndpointMappings(context.Context, *ListEndpointMappingsRequest) (*ListEndpointMappingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEndpointMappings not implemented")
+}
+func (UnimplementedIpamServer) DetectLeaks(context.Context, *DetectLeaksRequest) (*DetectLeaksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DetectLeaks not implemented")
+}
 func (UnimplementedIpamServer) mustEmbedUnimplementedIpamServer() {}
 
 // UnsafeIpamServer may be embedded to opt out of forward compatibility for this service.
@@ -185,6 +241,78 @@ func _Ipam_BelongsToPodCIDR_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Ipam_ListPools_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPoolsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IpamServer).ListPools(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ipam/ListPools",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IpamServer).ListPools(ctx, req.(*ListPoolsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ipam_ListClusterSubnets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListClusterSubnetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IpamServer).ListClusterSubnets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ipam/ListClusterSubnets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IpamServer).ListClusterSubnets(ctx, req.(*ListClusterSubnetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ipam_ListEndpointMappings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEndpointMappingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IpamServer).ListEndpointMappings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ipam/ListEndpointMappings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IpamServer).ListEndpointMappings(ctx, req.(*ListEndpointMappingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Ipam_DetectLeaks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DetectLeaksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(IpamServer).DetectLeaks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ipam/DetectLeaks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(IpamServer).DetectLeaks(ctx, req.(*DetectLeaksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Ipam_ServiceDesc is the grpc.ServiceDesc for Ipam service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -208,6 +336,22 @@ var Ipam_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "BelongsToPodCIDR",
 			Handler:    _Ipam_BelongsToPodCIDR_Handler,
 		},
+		{
+			MethodName: "ListPools",
+			Handler:    _Ipam_ListPools_Handler,
+		},
+		{
+			MethodName: "ListClusterSubnets",
+			Handler:    _Ipam_ListClusterSubnets_Handler,
+		},
+		{
+			MethodName: "ListEndpointMappings",
+			Handler:    _Ipam_ListEndpointMappings_Handler,
+		},
+		{
+			MethodName: "DetectLeaks",
+			Handler:    _Ipam_DetectLeaks_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pkg/liqonet/ipam/ipam.proto",
@@ -31,6 +31,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic/fake"
 
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
 	liqonetapi "github.com/liqotech/liqo/apis/net/v1alpha1"
 	"github.com/liqotech/liqo/pkg/consts"
 	liqoneterrors "github.com/liqotech/liqo/pkg/liqonet/errors"
@@ -50,6 +51,12 @@ const (
 	localNATExternalCIDR = "192.168.30.0/24"
 	externalEndpointIP   = "10.0.50.6"
 	invalidValue         = "invalid value"
+	remotePodCIDRv6      = "fd00:0:0:50::/64"
+	remoteExternalCIDRv6 = "fd00:0:0:60::/64"
+	homePodCIDRv6        = "fd00:0:0:100::/64"
+	localEndpointIPv6    = "fd00:0:0:100::20"
+	localNATPodCIDRv6    = "fd00:0:0:201::/64"
+	externalEndpointIPv6 = "fd00:0:0:500::6"
 )
 
 var (
@@ -104,6 +111,9 @@ func setDynClient() error {
 		Version:  "v1alpha1",
 		Resource: "natmappings",
 	}] = "natmappingsList"
+	m[discoveryv1alpha1.ForeignClusterGroupVersionResource] = "ForeignClusterList"
+	m[podsGVR] = "PodList"
+	m[endpointSlicesGVR] = "EndpointSliceList"
 
 	// Init fake dynamic client with objects in order to avoid errors in InitNatMappings func
 	// due to the lack of support of fake.dynamicClient for creation of more than 2 resources of the same Kind.
@@ -1721,8 +1731,301 @@ var _ = Describe("Ipam", func() {
 			})
 		})
 	})
+
+	Describe("Dual-stack", func() {
+		BeforeEach(func() {
+			// Re-initialize the IPAM enabling the IPv6 pools, as it happens when
+			// the dual-stack support is enabled on an already running cluster.
+			ipam.Terminate()
+			ipam = NewIPAM()
+			n, err := rand.Int(rand.Reader, big.NewInt(10000))
+			Expect(err).To(BeNil())
+			pools := append(append([]string{}, Pools...), PoolsV6...)
+			Expect(ipam.Init(pools, dynClient, 2000+int(n.Int64()))).To(Succeed())
+		})
+
+		Context("Initializing an existing IPAM with the IPv6 pools", func() {
+			It("should add the IPv6 pools", func() {
+				Expect(ipam.ipamStorage.getPools()).To(ConsistOf(append(append([]string{}, Pools...), PoolsV6...)))
+			})
+		})
+
+		Context("Removing a default IPv6 network pool", func() {
+			It("should return an error", func() {
+				Expect(ipam.RemoveNetworkPool(PoolsV6[0])).To(MatchError("cannot remove a default network pool"))
+			})
+		})
+
+		Context("Asking for IPv6 subnets", func() {
+			It("should store them separately from the IPv4 ones", func() {
+				mappedPodCIDR, mappedExternalCIDR, err := ipam.GetSubnetsPerClusterV6(remotePodCIDRv6, remoteExternalCIDRv6, clusterID1)
+				Expect(err).To(BeNil())
+				Expect(mappedPodCIDR).To(Equal(remotePodCIDRv6))
+				Expect(mappedExternalCIDR).To(Equal(remoteExternalCIDRv6))
+
+				ipamStorage, err := getIpamStorageResource()
+				Expect(err).To(BeNil())
+				Expect(ipamStorage.Spec.ClusterSubnets).ToNot(HaveKey(clusterID1))
+				Expect(ipamStorage.Spec.ClusterSubnetsV6).To(HaveKeyWithValue(clusterID1, liqonetapi.Subnets{
+					RemotePodCIDR:      remotePodCIDRv6,
+					RemoteExternalCIDR: remoteExternalCIDRv6,
+				}))
+			})
+			It("should remap them in case of conflicts", func() {
+				_, _, err := ipam.GetSubnetsPerClusterV6(remotePodCIDRv6, remoteExternalCIDRv6, clusterID1)
+				Expect(err).To(BeNil())
+				mappedPodCIDR, mappedExternalCIDR, err := ipam.GetSubnetsPerClusterV6(remotePodCIDRv6, remoteExternalCIDRv6, clusterID2)
+				Expect(err).To(BeNil())
+				Expect(mappedPodCIDR).ToNot(Equal(remotePodCIDRv6))
+				Expect(mappedExternalCIDR).ToNot(Equal(remoteExternalCIDRv6))
+				Expect(utils.IsIPv6CIDR(mappedPodCIDR)).To(BeTrue())
+				Expect(utils.IsIPv6CIDR(mappedExternalCIDR)).To(BeTrue())
+				Expect(mappedPodCIDR).To(HaveSuffix("/64"))
+				Expect(mappedExternalCIDR).To(HaveSuffix("/64"))
+			})
+			It("should return an error if the networks are IPv4 ones", func() {
+				_, _, err := ipam.GetSubnetsPerClusterV6(remotePodCIDR, remoteExternalCIDR, clusterID1)
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Context("Asking for an IPv6 ExternalCIDR", func() {
+			It("should return a network taken from the IPv6 pools", func() {
+				externalCIDR, err := ipam.GetExternalCIDRV6(64)
+				Expect(err).To(BeNil())
+				Expect(utils.IsIPv6CIDR(externalCIDR)).To(BeTrue())
+				Expect(ipam.ipamStorage.getExternalCIDRV6()).To(Equal(externalCIDR))
+
+				// The IPv4 ExternalCIDR should not be affected.
+				Expect(ipam.ipamStorage.getExternalCIDR()).To(BeEmpty())
+			})
+		})
+
+		Context("Setting the IPv6 PodCIDR", func() {
+			It("should refuse IPv4 networks", func() {
+				Expect(ipam.SetPodCIDRV6(homePodCIDR)).ToNot(Succeed())
+			})
+			It("should store the network", func() {
+				Expect(ipam.SetPodCIDRV6(homePodCIDRv6)).To(Succeed())
+				Expect(ipam.ipamStorage.getPodCIDRV6()).To(Equal(homePodCIDRv6))
+				Expect(ipam.ipamStorage.getPodCIDR()).To(BeEmpty())
+			})
+		})
+
+		Context("Mapping IPv6 endpoints", func() {
+			var externalCIDRv6 string
+			BeforeEach(func() {
+				var err error
+				Expect(ipam.SetPodCIDR(homePodCIDR)).To(Succeed())
+				_, err = ipam.GetExternalCIDR(24)
+				Expect(err).To(BeNil())
+				Expect(ipam.SetPodCIDRV6(homePodCIDRv6)).To(Succeed())
+				externalCIDRv6, err = ipam.GetExternalCIDRV6(64)
+				Expect(err).To(BeNil())
+
+				_, _, err = ipam.GetSubnetsPerCluster(remotePodCIDR, remoteExternalCIDR, clusterID1)
+				Expect(err).To(BeNil())
+				Expect(ipam.AddLocalSubnetsPerCluster(localNATPodCIDR, localNATExternalCIDR, clusterID1)).To(Succeed())
+				_, _, err = ipam.GetSubnetsPerClusterV6(remotePodCIDRv6, remoteExternalCIDRv6, clusterID1)
+				Expect(err).To(BeNil())
+				Expect(ipam.AddLocalSubnetsPerClusterV6(localNATPodCIDRv6, consts.DefaultCIDRValue, clusterID1)).To(Succeed())
+			})
+
+			It("should record the IPv6 networks in the NatMapping resource", func() {
+				natMappings, err := getNatMappingResourcePerCluster(clusterID1)
+				Expect(err).To(BeNil())
+				Expect(natMappings.Spec.PodCIDRv6).To(Equal(remotePodCIDRv6))
+				Expect(natMappings.Spec.ExternalCIDRv6).To(Equal(externalCIDRv6))
+			})
+			It("should map an IP of the local IPv6 PodCIDR to the remapped IPv6 PodCIDR", func() {
+				response, err := ipam.MapEndpointIP(context.Background(), &MapRequest{ClusterID: clusterID1, Ip: localEndpointIPv6})
+				Expect(err).To(BeNil())
+				Expect(response.GetIp()).To(Equal("fd00:0:0:201::20"))
+			})
+			It("should map an external IPv6 endpoint to the IPv6 ExternalCIDR", func() {
+				response, err := ipam.MapEndpointIP(context.Background(), &MapRequest{ClusterID: clusterID1, Ip: externalEndpointIPv6})
+				Expect(err).To(BeNil())
+				belongs, err := ipBelongsToNetwork(response.GetIp(), externalCIDRv6)
+				Expect(err).To(BeNil())
+				Expect(belongs).To(BeTrue())
+
+				_, err = ipam.UnmapEndpointIP(context.Background(), &UnmapRequest{ClusterID: clusterID1, Ip: externalEndpointIPv6})
+				Expect(err).To(BeNil())
+				Expect(ipam.ipamStorage.getEndpointMappings()).ToNot(HaveKey(externalEndpointIPv6))
+			})
+			It("should return the home IP of a remote IPv6 pod", func() {
+				response, err := ipam.GetHomePodIP(context.Background(), &GetHomePodIPRequest{ClusterID: clusterID1, Ip: "fd00:0:0:50::6"})
+				Expect(err).To(BeNil())
+				Expect(response.GetHomeIP()).To(Equal("fd00:0:0:50::6"))
+			})
+			It("should free the IPv6 networks when the cluster configuration is removed", func() {
+				Expect(ipam.RemoveClusterConfig(clusterID1)).To(Succeed())
+				Expect(ipam.ipamStorage.getClusterSubnets()).ToNot(HaveKey(clusterID1))
+				Expect(ipam.ipamStorage.getClusterSubnetsV6()).ToNot(HaveKey(clusterID1))
+				Expect(ipam.isAcquired(remotePodCIDRv6)).To(BeFalse())
+				Expect(ipam.isAcquired(remoteExternalCIDRv6)).To(BeFalse())
+			})
+		})
+	})
+
+	Describe("Introspection", func() {
+		const endpointIP = "20.0.0.1"
+		var ctx context.Context
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			Expect(ipam.SetPodCIDR(homePodCIDR)).To(Succeed())
+			_, err := ipam.GetExternalCIDR(24)
+			Expect(err).To(BeNil())
+			_, _, err = ipam.GetSubnetsPerCluster(remotePodCIDR, remoteExternalCIDR, clusterID1)
+			Expect(err).To(BeNil())
+			Expect(ipam.AddLocalSubnetsPerCluster(consts.DefaultCIDRValue, consts.DefaultCIDRValue, clusterID1)).To(Succeed())
+			_, err = ipam.MapEndpointIP(ctx, &MapRequest{ClusterID: clusterID1, Ip: endpointIP})
+			Expect(err).To(BeNil())
+		})
+
+		Context("ListPools", func() {
+			It("should return the pools along with the allocated networks", func() {
+				response, err := ipam.ListPools(ctx, &ListPoolsRequest{})
+				Expect(err).To(BeNil())
+				Expect(response.GetPools()).To(HaveLen(len(Pools)))
+				Expect(response.GetPools()[0].GetNetwork()).To(Equal(Pools[0]))
+				Expect(response.GetPools()[0].GetAllocatedNetworks()).To(ContainElements(remotePodCIDR, remoteExternalCIDR))
+				Expect(response.GetPools()[0].GetAllocatedNetworks()).ToNot(ContainElement(Pools[0]))
+			})
+		})
+
+		Context("ListClusterSubnets", func() {
+			It("should return the networks of the given cluster", func() {
+				response, err := ipam.ListClusterSubnets(ctx, &ListClusterSubnetsRequest{ClusterID: clusterID1})
+				Expect(err).To(BeNil())
+				Expect(response.GetSubnets()).To(HaveLen(1))
+				Expect(response.GetSubnets()[0].GetClusterID()).To(Equal(clusterID1))
+				Expect(response.GetSubnets()[0].GetIpv6()).To(BeFalse())
+				Expect(response.GetSubnets()[0].GetRemotePodCIDR()).To(Equal(remotePodCIDR))
+				Expect(response.GetSubnets()[0].GetRemoteExternalCIDR()).To(Equal(remoteExternalCIDR))
+				Expect(response.GetSubnets()[0].GetLocalNATPodCIDR()).To(Equal(consts.DefaultCIDRValue))
+			})
+			It("should return no networks for an unknown cluster", func() {
+				response, err := ipam.ListClusterSubnets(ctx, &ListClusterSubnetsRequest{ClusterID: clusterID2})
+				Expect(err).To(BeNil())
+				Expect(response.GetSubnets()).To(BeEmpty())
+			})
+		})
+
+		Context("ListEndpointMappings", func() {
+			It("should return the mappings along with the owning clusters", func() {
+				response, err := ipam.ListEndpointMappings(ctx, &ListEndpointMappingsRequest{})
+				Expect(err).To(BeNil())
+				Expect(response.GetMappings()).To(HaveLen(1))
+				Expect(response.GetMappings()[0].GetEndpointIP()).To(Equal(endpointIP))
+				Expect(response.GetMappings()[0].GetExternalCIDRIP()).ToNot(BeEmpty())
+				Expect(response.GetMappings()[0].GetClusterIDs()).To(ConsistOf(clusterID1))
+			})
+			It("should filter the mappings by cluster", func() {
+				response, err := ipam.ListEndpointMappings(ctx, &ListEndpointMappingsRequest{ClusterID: clusterID2})
+				Expect(err).To(BeNil())
+				Expect(response.GetMappings()).To(BeEmpty())
+			})
+		})
+
+		Context("DetectLeaks", func() {
+			When("the cluster and the endpoint exist", func() {
+				BeforeEach(func() {
+					createForeignCluster(clusterID1)
+					createEndpointSlice(endpointIP)
+				})
+				It("should not detect any leak", func() {
+					response, err := ipam.DetectLeaks(ctx, &DetectLeaksRequest{GarbageCollect: true})
+					Expect(err).To(BeNil())
+					Expect(response.GetLeaks()).To(BeEmpty())
+				})
+			})
+
+			When("the endpoint no longer exists", func() {
+				BeforeEach(func() { createForeignCluster(clusterID1) })
+
+				It("should report the endpoint mapping without releasing it", func() {
+					response, err := ipam.DetectLeaks(ctx, &DetectLeaksRequest{})
+					Expect(err).To(BeNil())
+					Expect(response.GetLeaks()).To(HaveLen(1))
+					Expect(response.GetLeaks()[0].GetKind()).To(Equal(LeakKindEndpointMapping))
+					Expect(response.GetLeaks()[0].GetIp()).To(Equal(endpointIP))
+					Expect(response.GetLeaks()[0].GetClusterID()).To(Equal(clusterID1))
+					Expect(response.GetLeaks()[0].GetCollected()).To(BeFalse())
+					Expect(ipam.ipamStorage.getEndpointMappings()).To(HaveKey(endpointIP))
+				})
+				It("should release the endpoint mapping if garbage collection is requested", func() {
+					response, err := ipam.DetectLeaks(ctx, &DetectLeaksRequest{GarbageCollect: true})
+					Expect(err).To(BeNil())
+					Expect(response.GetLeaks()).To(HaveLen(1))
+					Expect(response.GetLeaks()[0].GetCollected()).To(BeTrue())
+					Expect(ipam.ipamStorage.getEndpointMappings()).ToNot(HaveKey(endpointIP))
+
+					nm, err := getNatMappingResourcePerCluster(clusterID1)
+					Expect(err).To(BeNil())
+					Expect(nm.Spec.ClusterMappings).ToNot(HaveKey(endpointIP))
+				})
+			})
+
+			When("the cluster no longer exists", func() {
+				BeforeEach(func() { createEndpointSlice(endpointIP) })
+
+				It("should report both the cluster networks and the endpoint mapping", func() {
+					response, err := ipam.DetectLeaks(ctx, &DetectLeaksRequest{})
+					Expect(err).To(BeNil())
+					Expect(response.GetLeaks()).To(HaveLen(2))
+					Expect(response.GetLeaks()[0].GetKind()).To(Equal(LeakKindClusterSubnets))
+					Expect(response.GetLeaks()[0].GetClusterID()).To(Equal(clusterID1))
+					Expect(response.GetLeaks()[1].GetKind()).To(Equal(LeakKindEndpointMapping))
+					Expect(ipam.isAcquired(remotePodCIDR)).To(BeTrue())
+				})
+				It("should free the cluster networks if garbage collection is requested", func() {
+					response, err := ipam.DetectLeaks(ctx, &DetectLeaksRequest{GarbageCollect: true})
+					Expect(err).To(BeNil())
+					Expect(response.GetLeaks()).To(HaveLen(1))
+					Expect(response.GetLeaks()[0].GetKind()).To(Equal(LeakKindClusterSubnets))
+					Expect(response.GetLeaks()[0].GetCollected()).To(BeTrue())
+					Expect(ipam.ipamStorage.getClusterSubnets()).ToNot(HaveKey(clusterID1))
+					Expect(ipam.ipamStorage.getEndpointMappings()).ToNot(HaveKey(endpointIP))
+					Expect(ipam.isAcquired(remotePodCIDR)).To(BeFalse())
+				})
+			})
+		})
+	})
 })
 
+func createForeignCluster(clusterID string) {
+	fc := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": discoveryv1alpha1.GroupVersion.String(),
+		"kind":       "ForeignCluster",
+		"metadata":   map[string]interface{}{"name": clusterID},
+		"spec":       map[string]interface{}{"clusterIdentity": map[string]interface{}{"clusterID": clusterID}},
+	}}
+	_, err := dynClient.Resource(discoveryv1alpha1.ForeignClusterGroupVersionResource).Create(context.Background(), fc, v1.CreateOptions{})
+	Expect(err).ToNot(HaveOccurred())
+}
+
+func createEndpointSlice(addresses ...string) {
+	eps := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion":  "discovery.k8s.io/v1",
+		"kind":        "EndpointSlice",
+		"metadata":    map[string]interface{}{"name": "endpointslice", "namespace": "default"},
+		"addressType": "IPv4",
+		"endpoints":   []interface{}{map[string]interface{}{"addresses": stringsToInterfaces(addresses)}},
+	}}
+	_, err := dynClient.Resource(endpointSlicesGVR).Namespace("default").Create(context.Background(), eps, v1.CreateOptions{})
+	Expect(err).ToNot(HaveOccurred())
+}
+
+func stringsToInterfaces(values []string) []interface{} {
+	out := make([]interface{}, len(values))
+	for i := range values {
+		out[i] = values[i]
+	}
+	return out
+}
+
 func checkForPrefixes(subnets []string) {
 	for _, s := range subnets {
 		prefix, err := ipam.ipamStorage.ReadPrefix(s)
@@ -77,6 +77,8 @@ type IPTableRule []string
 // IPTHandler a handler that exposes all the functions needed to configure the iptables chains and rules.
 type IPTHandler struct {
 	ipt iptables.IPTables
+	// ip6t is set only in case of dual-stack clusters, and it is used to mirror the configuration through ip6tables.
+	ip6t *iptables.IPTables
 }
 
 // NewIPTHandler return the iptables handler used to configure the iptables rules.
@@ -90,6 +92,29 @@ func NewIPTHandler() (IPTHandler, error) {
 	}, err
 }
 
+// NewDualStackIPTHandler returns the iptables handler used to configure both the iptables and the ip6tables rules.
+// The IPv6 rules are derived from the IPv6 fields of the TunnelEndpoint resources, and from the IPv6 NAT mappings.
+func NewDualStackIPTHandler() (IPTHandler, error) {
+	h, err := NewIPTHandler()
+	if err != nil {
+		return IPTHandler{}, err
+	}
+	ip6t, err := iptables.NewWithProtocol(iptables.ProtocolIPv6)
+	if err != nil {
+		return IPTHandler{}, err
+	}
+	h.ip6t = ip6t
+	return h, nil
+}
+
+// ipv6Handler returns the handler operating through ip6tables, if the dual-stack support is enabled.
+func (h IPTHandler) ipv6Handler() (IPTHandler, bool) {
+	if h.ip6t == nil {
+		return IPTHandler{}, false
+	}
+	return IPTHandler{ipt: *h.ip6t}, true
+}
+
 // Init function is called at startup of the operator.
 // here we:
 // create LIQONET-FORWARD in the filter table and insert it in the "FORWARD" chain.
@@ -112,6 +137,10 @@ func (h IPTHandler) Init() error {
 	if err := h.ensureLiqoRules(liqoRules); err != nil {
 		return err
 	}
+
+	if h6, ok := h.ipv6Handler(); ok {
+		return h6.Init()
+	}
 	return nil
 }
 
@@ -158,6 +187,10 @@ func (h IPTHandler) Terminate() error {
 		return fmt.Errorf("cannot delete Liqo default chains: %w", err)
 	}
 	klog.Infof("IPTables Liqo configuration has been successfully removed.")
+
+	if h6, ok := h.ipv6Handler(); ok {
+		return h6.Terminate()
+	}
 	return nil
 }
 
@@ -271,9 +304,27 @@ func (h IPTHandler) EnsureChainRulesPerCluster(tep *netv1alpha1.TunnelEndpoint)
 			return fmt.Errorf("cannot update rule for chain %s (table %s): %w", chain, getTableFromChain(chain), err)
 		}
 	}
+
+	if h6, tep6, ok := h.ipv6HandlerFor(tep); ok {
+		return h6.EnsureChainRulesPerCluster(tep6)
+	}
 	return nil
 }
 
+// ipv6HandlerFor returns the ip6tables handler and the IPv6 view of the given TunnelEndpoint,
+// if both the dual-stack support is enabled and the TunnelEndpoint carries the IPv6 networks.
+func (h IPTHandler) ipv6HandlerFor(tep *netv1alpha1.TunnelEndpoint) (IPTHandler, *netv1alpha1.TunnelEndpoint, bool) {
+	h6, ok := h.ipv6Handler()
+	if !ok {
+		return IPTHandler{}, nil, false
+	}
+	tep6, ok := utils.GetIPv6View(tep)
+	if !ok {
+		return IPTHandler{}, nil, false
+	}
+	return h6, tep6, true
+}
+
 func (h IPTHandler) getExistingChainRules(clusterID, chain string) ([]string, error) {
 	existingChainRules := make([]string, 0)
 	// Get rules in chain
@@ -311,6 +362,15 @@ func getChainsPerCluster(clusterID string) []string {
 	return chains
 }
 
+// ChainsPerCluster returns the chains created for the given remote cluster, mapped to the tables they belong to.
+func ChainsPerCluster(clusterID string) map[string]string {
+	chains := make(map[string]string)
+	for _, chain := range getChainsPerCluster(clusterID) {
+		chains[chain] = getTableFromChain(chain)
+	}
+	return chains
+}
+
 // EnsureChainsPerCluster is used to be sure input, output, postrouting and prerouting chain for a given
 // cluster are present in the NAT table and Filter table.
 func (h IPTHandler) EnsureChainsPerCluster(clusterID string) error {
@@ -327,6 +387,10 @@ func (h IPTHandler) EnsureChainsPerCluster(clusterID string) error {
 			return err
 		}
 	}
+
+	if h6, ok := h.ipv6Handler(); ok {
+		return h6.EnsureChainsPerCluster(clusterID)
+	}
 	return nil
 }
 
@@ -368,6 +432,17 @@ func (h IPTHandler) RemoveIPTablesConfigurationPerCluster(tep *netv1alpha1.Tunne
 		return fmt.Errorf("cannot remove chains per cluster: %w", err)
 	}
 	klog.Infof("IPTables config per cluster %s has been deleted", tep.Spec.ClusterID)
+
+	if h6, tep6, ok := h.ipv6HandlerFor(tep); ok {
+		return h6.RemoveIPTablesConfigurationPerCluster(tep6)
+	}
+	if h6, ok := h.ipv6Handler(); ok {
+		// The IPv6 chains per cluster are always created, hence they need to be removed even if
+		// the TunnelEndpoint does not carry the IPv6 networks.
+		if err := h6.removeChainsPerCluster(tep.Spec.ClusterID); err != nil {
+			return fmt.Errorf("cannot remove IPv6 chains per cluster: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -460,7 +535,14 @@ func (h IPTHandler) EnsurePostroutingRules(tep *netv1alpha1.TunnelEndpoint) erro
 	if err != nil {
 		return err
 	}
-	return h.updateRulesPerChain(getClusterPostRoutingChain(clusterID), rules)
+	if err := h.updateRulesPerChain(getClusterPostRoutingChain(clusterID), rules); err != nil {
+		return err
+	}
+
+	if h6, tep6, ok := h.ipv6HandlerFor(tep); ok {
+		return h6.EnsurePostroutingRules(tep6)
+	}
+	return nil
 }
 
 // EnsurePreroutingRulesPerTunnelEndpoint makes sure that the prerouting rules extracted from a
@@ -471,14 +553,32 @@ func (h IPTHandler) EnsurePreroutingRulesPerTunnelEndpoint(tep *netv1alpha1.Tunn
 	if err != nil {
 		return err
 	}
-	return h.updateRulesPerChain(getClusterPreRoutingChain(clusterID), rules)
+	if err := h.updateRulesPerChain(getClusterPreRoutingChain(clusterID), rules); err != nil {
+		return err
+	}
+
+	if h6, tep6, ok := h.ipv6HandlerFor(tep); ok {
+		return h6.EnsurePreroutingRulesPerTunnelEndpoint(tep6)
+	}
+	return nil
 }
 
 // EnsurePreroutingRulesPerNatMapping makes sure that the prerouting rules extracted from a
 // NatMapping resource are place and updated.
 func (h IPTHandler) EnsurePreroutingRulesPerNatMapping(nm *netv1alpha1.NatMapping) error {
+	if err := h.ensurePreroutingRulesPerNatMapping(nm, false); err != nil {
+		return err
+	}
+
+	if h6, ok := h.ipv6Handler(); ok {
+		return h6.ensurePreroutingRulesPerNatMapping(nm, true)
+	}
+	return nil
+}
+
+func (h IPTHandler) ensurePreroutingRulesPerNatMapping(nm *netv1alpha1.NatMapping, ipv6 bool) error {
 	clusterID := nm.Spec.ClusterID
-	rules, err := getPreRoutingRulesPerNatMapping(nm)
+	rules, err := getPreRoutingRulesPerNatMapping(nm, ipv6)
 	if err != nil {
 		return err
 	}
@@ -506,7 +606,8 @@ func getPreRoutingRulesPerTunnelEndpoint(tep *netv1alpha1.TunnelEndpoint) ([]IPT
 	return rules, nil
 }
 
-func getPreRoutingRulesPerNatMapping(nm *netv1alpha1.NatMapping) ([]IPTableRule, error) {
+// getPreRoutingRulesPerNatMapping returns the rules for the NAT mappings of the given IP family.
+func getPreRoutingRulesPerNatMapping(nm *netv1alpha1.NatMapping, ipv6 bool) ([]IPTableRule, error) {
 	// Check tep fields
 	if nm.Spec.ClusterID == "" {
 		return nil, &errors.WrongParameter{
@@ -518,6 +619,9 @@ func getPreRoutingRulesPerNatMapping(nm *netv1alpha1.NatMapping) ([]IPTableRule,
 	rules := make([]IPTableRule, 0, len(nm.Spec.ClusterMappings))
 
 	for oldIP, newIP := range nm.Spec.ClusterMappings {
+		if utils.IsIPv6(oldIP) != ipv6 {
+			continue
+		}
 		rules = append(rules,
 			IPTableRule{"-d", newIP, "-j", DNAT, "--to-destination", oldIP},
 		)
@@ -547,6 +651,7 @@ func (h IPTHandler) ListRulesInChain(chain string) ([]string, error) {
 	for _, rule := range existingRules {
 		if rule != ruleToRemove {
 			rule = strings.ReplaceAll(rule, "/32", "")
+			rule = strings.ReplaceAll(rule, "/128", "")
 			tmp := strings.Split(rule, " ")
 			rules = append(rules, strings.Join(tmp[2:], " "))
 		}
@@ -1027,6 +1027,23 @@ var _ = Describe("iptables", func() {
 				))
 			})
 		})
+		Context("If the NatMapping contains mappings of both IP families", func() {
+			It("should split the rules by family", func() {
+				dualStackNm := nm.DeepCopy()
+				dualStackNm.Spec.ClusterMappings = v1alpha1.Mappings{
+					oldIP1:     newIP1,
+					"fd00::10": "fd00:0:0:1::10",
+				}
+
+				rules, err := getPreRoutingRulesPerNatMapping(dualStackNm, false)
+				Expect(err).To(BeNil())
+				Expect(rules).To(ConsistOf(IPTableRule{"-d", newIP1, "-j", DNAT, "--to-destination", oldIP1}))
+
+				rules, err = getPreRoutingRulesPerNatMapping(dualStackNm, true)
+				Expect(err).To(BeNil())
+				Expect(rules).To(ConsistOf(IPTableRule{"-d", "fd00:0:0:1::10", "-j", DNAT, "--to-destination", "fd00::10"}))
+			})
+		})
 	})
 })
 
@@ -41,6 +41,9 @@ type Interface interface {
 	// externalCIDR is the ExternalCIDR used in the remote cluster for local exported resources:
 	// it can be either the LocalExternalCIDR or the LocalNATExternalCIDR.
 	InitNatMappingsPerCluster(podCIDR, externalCIDR, clusterID string) error
+	// InitNatMappingsPerClusterV6 records the IPv6 networks of a dual-stack remote cluster, with the same semantic
+	// of the InitNatMappingsPerCluster parameters. NAT mappings have to be initialized first for the IPv4 networks.
+	InitNatMappingsPerClusterV6(podCIDR, externalCIDR, clusterID string) error
 	// TerminateNatMappingsPerCluster frees/deletes resources allocated for remote cluster.
 	TerminateNatMappingsPerCluster(clusterID string) error
 	// GetNatMappings returns the set of mappings related to a remote cluster.
@@ -130,6 +133,42 @@ func (inflater *NatMappingInflater) InitNatMappingsPerCluster(podCIDR, externalC
 	return inflater.initResource(podCIDR, externalCIDR, clusterID)
 }
 
+// InitNatMappingsPerClusterV6 sets the IPv6 networks in the NatMapping resource of the remote cluster.
+// Mappings are keyed by the original IP, hence the ones of both families are stored in the same resource.
+func (inflater *NatMappingInflater) InitNatMappingsPerClusterV6(podCIDR, externalCIDR, clusterID string) error {
+	// Check parameters
+	if err := checkParams(podCIDR, externalCIDR, clusterID); err != nil {
+		return err
+	}
+	if !utils.IsIPv6CIDR(podCIDR) || !utils.IsIPv6CIDR(externalCIDR) {
+		return &errors.WrongParameter{
+			Reason:    errors.ValidCIDR,
+			Parameter: fmt.Sprintf("%s, %s", podCIDR, externalCIDR),
+		}
+	}
+	// Check if it has been already initialized
+	if _, exists := inflater.natMappingsPerCluster[clusterID]; !exists {
+		return &errors.MissingInit{
+			StructureName: fmt.Sprintf("%s for cluster %s", consts.NatMappingKind, clusterID),
+		}
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		natMappings, err := inflater.getNatMappingResource(clusterID)
+		if err != nil {
+			return fmt.Errorf("cannot retrieve NatMapping resource for cluster %s: %w", clusterID, err)
+		}
+		if natMappings.Spec.PodCIDRv6 == podCIDR && natMappings.Spec.ExternalCIDRv6 == externalCIDR {
+			return nil
+		}
+		natMappings.Spec.PodCIDRv6 = podCIDR
+		natMappings.Spec.ExternalCIDRv6 = externalCIDR
+		if err := inflater.updateNatMappingResource(natMappings); err != nil {
+			return fmt.Errorf("cannot update NatMapping resource for cluster %s: %w", clusterID, err)
+		}
+		return nil
+	})
+}
+
 func (inflater *NatMappingInflater) initResource(podCIDR, externalCIDR, clusterID string) error {
 	// Check existence of resource
 	natMappings, err := inflater.getNatMappingResource(clusterID)
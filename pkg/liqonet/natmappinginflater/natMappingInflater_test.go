@@ -44,16 +44,18 @@ var (
 )
 
 const (
-	invalidValue = "invalid value"
-	clusterID1   = "cluster-test"
-	clusterID2   = "cluster-test-2"
-	clusterID3   = "cluster-test-3"
-	podCIDR      = "10.0.0.0/24"
-	externalCIDR = "10.0.1.0/24"
-	oldIP        = "20.0.0.1"
-	oldIP2       = "20.0.0.3"
-	newIP        = "10.0.3.3"
-	newIP2       = "10.0.3.4"
+	invalidValue   = "invalid value"
+	clusterID1     = "cluster-test"
+	clusterID2     = "cluster-test-2"
+	clusterID3     = "cluster-test-3"
+	podCIDR        = "10.0.0.0/24"
+	externalCIDR   = "10.0.1.0/24"
+	podCIDRv6      = "fd00:0:0:1::/64"
+	externalCIDRv6 = "fd00:0:0:2::/64"
+	oldIP          = "20.0.0.1"
+	oldIP2         = "20.0.0.3"
+	newIP          = "10.0.3.3"
+	newIP2         = "10.0.3.4"
 )
 
 func setDynClient() error {
@@ -220,6 +222,32 @@ var _ = Describe("NatMappingInflater", func() {
 			})
 		})
 	})
+	Describe("InitNatMappingsPerClusterV6", func() {
+		Context("Passing IPv4 networks", func() {
+			It("should return a WrongParameter error", func() {
+				err := inflater.InitNatMappingsPerClusterV6(podCIDR, externalCIDR, clusterID1)
+				Expect(err).To(MatchError(fmt.Sprintf("%s, %s must be %s", podCIDR, externalCIDR, liqoneterrors.ValidCIDR)))
+			})
+		})
+		Context("If the cluster has not been initialized yet", func() {
+			It("should return a MissingInit error", func() {
+				err := inflater.InitNatMappingsPerClusterV6(podCIDRv6, externalCIDRv6, clusterID3)
+				Expect(err).To(MatchError(fmt.Sprintf("%s for cluster %s must be %s",
+					consts.NatMappingKind, clusterID3, liqoneterrors.Initialization)))
+			})
+		})
+		Context("If the cluster has already been initialized", func() {
+			It("should set the IPv6 networks in the resource", func() {
+				err := inflater.InitNatMappingsPerClusterV6(podCIDRv6, externalCIDRv6, clusterID1)
+				Expect(err).To(BeNil())
+				nm, err := inflater.getNatMappingResource(clusterID1)
+				Expect(err).To(BeNil())
+				Expect(nm.Spec.PodCIDR).To(Equal(podCIDR))
+				Expect(nm.Spec.PodCIDRv6).To(Equal(podCIDRv6))
+				Expect(nm.Spec.ExternalCIDRv6).To(Equal(externalCIDRv6))
+			})
+		})
+	})
 	Describe("GetNatMappings", func() {
 		Context("If the cluster has not been initialized yet", func() {
 			It("should return a WrongParameterError", func() {
@@ -61,7 +61,7 @@ func AddRoute(dstNet, gwIP string, iFaceIndex, tableID, flags int, scope netlink
 		Scope:     scope,
 	}
 	// Check if already exists a route for the given destination.
-	routes, err := netlink.RouteListFiltered(netlink.FAMILY_V4, route, netlink.RT_FILTER_TABLE|netlink.RT_FILTER_DST)
+	routes, err := netlink.RouteListFiltered(routeFamily(destinationNet), route, netlink.RT_FILTER_TABLE|netlink.RT_FILTER_DST)
 	if err != nil {
 		return false, err
 	}
@@ -70,7 +70,7 @@ func AddRoute(dstNet, gwIP string, iFaceIndex, tableID, flags int, scope netlink
 	if len(routes) == 1 {
 		r := routes[0]
 		// Check if the existing rule is equal to the one that we want to configure.
-		if reflect.DeepEqual(r.Gw, gatewayIP.To4()) && r.LinkIndex == iFaceIndex {
+		if reflect.DeepEqual(r.Gw, normalizeIP(gatewayIP)) && r.LinkIndex == iFaceIndex {
 			klog.V(5).Infof("route {%s} already exists", route.String())
 			return false, nil
 		}
@@ -126,7 +126,7 @@ func flushRoutesForRoutingTable(tableID int) error {
 	route := &netlink.Route{
 		Table: tableID,
 	}
-	routes, err := netlink.RouteListFiltered(netlink.FAMILY_V4, route, netlink.RT_FILTER_TABLE)
+	routes, err := netlink.RouteListFiltered(netlink.FAMILY_ALL, route, netlink.RT_FILTER_TABLE)
 	if err != nil {
 		return err
 	}
@@ -300,6 +300,22 @@ func validatePolicyRoutingRulesParameters(fromSubnet, toSubnet string) (sourceNe
 	return sourceNet, destinationNet, err
 }
 
+// routeFamily returns the netlink family of the given destination network.
+func routeFamily(dst *net.IPNet) int {
+	if dst.IP.To4() == nil {
+		return netlink.FAMILY_V6
+	}
+	return netlink.FAMILY_V4
+}
+
+// normalizeIP returns the IP in the same representation used by netlink for the routes of its family.
+func normalizeIP(ip net.IP) net.IP {
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4
+	}
+	return ip
+}
+
 func parseIP(ip string) (net.IP, error) {
 	address := net.ParseIP(ip)
 	if address == nil {
@@ -312,3 +328,8 @@ func parseIP(ip string) (net.IP, error) {
 func EnableIPForwarding() error {
 	return os.WriteFile("/proc/sys/net/ipv4/ip_forward", []byte("1"), 0o600)
 }
+
+// EnableIPv6Forwarding enables ipv6 forwarding in the current network namespace.
+func EnableIPv6Forwarding() error {
+	return os.WriteFile("/proc/sys/net/ipv6/conf/all/forwarding", []byte("1"), 0o600)
+}
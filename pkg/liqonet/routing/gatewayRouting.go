@@ -75,6 +75,11 @@ func (grm *GatewayRoutingManager) EnsureRoutesPerCluster(tep *netv1alpha1.Tunnel
 	if routePodCIDRAdd || routeExternalCIDRAdd {
 		configured = true
 	}
+	// Routes are bound to the tunnel device only, hence the same logic applies to the IPv6 networks.
+	if tep6, ok := utils.GetIPv6View(tep); ok {
+		configuredV6, err := grm.EnsureRoutesPerCluster(tep6)
+		return configured || configuredV6, err
+	}
 	return configured, nil
 }
 
@@ -100,6 +105,10 @@ func (grm *GatewayRoutingManager) RemoveRoutesPerCluster(tep *netv1alpha1.Tunnel
 	if routePodCIDRDel || routeExternalCIDRDel {
 		configured = true
 	}
+	if tep6, ok := utils.GetIPv6View(tep); ok {
+		configuredV6, err := grm.RemoveRoutesPerCluster(tep6)
+		return configured || configuredV6, err
+	}
 	return configured, nil
 }
 
@@ -0,0 +1,44 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sharding
+
+import (
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+
+	podutils "github.com/liqotech/liqo/pkg/utils/pod"
+)
+
+// IsMember returns whether the given gateway pod is eligible to carry tunnels, i.e., it is ready and not terminating.
+func IsMember(pod *corev1.Pod) bool {
+	if pod.Status.PodIP == "" || !pod.GetDeletionTimestamp().IsZero() {
+		return false
+	}
+	ready, _ := podutils.IsPodReady(pod)
+	return ready
+}
+
+// Members returns the identifiers (i.e., the IP addresses) of the given gateway pods eligible to carry tunnels, sorted.
+func Members(pods []corev1.Pod) []string {
+	members := make([]string, 0, len(pods))
+	for i := range pods {
+		if IsMember(&pods[i]) {
+			members = append(members, pods[i].Status.PodIP)
+		}
+	}
+	sort.Strings(members)
+	return members
+}
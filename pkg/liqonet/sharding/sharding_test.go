@@ -0,0 +1,121 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sharding
+
+import (
+	"fmt"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Consistent hashing", func() {
+	var (
+		keys    []string
+		members []string
+	)
+
+	assign := func(ring *Ring) map[string]string {
+		assignment := make(map[string]string, len(keys))
+		for _, key := range keys {
+			assignment[key] = ring.Owner(key)
+		}
+		return assignment
+	}
+
+	BeforeEach(func() {
+		keys = nil
+		for i := 0; i < 1000; i++ {
+			keys = append(keys, fmt.Sprintf("cluster-%d", i))
+		}
+		members = []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}
+	})
+
+	When("the ring has no members", func() {
+		It("should return no owner", func() {
+			Expect(NewRing(nil).Owner("cluster")).To(BeEmpty())
+		})
+	})
+
+	When("the ring has some members", func() {
+		It("should assign each key to one of them", func() {
+			for _, owner := range assign(NewRing(members)) {
+				Expect(members).To(ContainElement(owner))
+			}
+		})
+
+		It("should return the sorted list of members", func() {
+			Expect(NewRing([]string{members[2], members[0], members[1]}).Members()).To(Equal(members))
+		})
+
+		It("should not depend on the order of the members", func() {
+			Expect(assign(NewRing(members))).To(Equal(assign(NewRing([]string{members[2], members[0], members[1]}))))
+		})
+
+		It("should distribute the keys among all members", func() {
+			counts := make(map[string]int)
+			for _, owner := range assign(NewRing(members)) {
+				counts[owner]++
+			}
+			for _, member := range members {
+				Expect(counts[member]).To(BeNumerically(">", len(keys)/len(members)/2))
+			}
+		})
+	})
+
+	When("a member is removed", func() {
+		It("should reassign only the keys owned by that member", func() {
+			before := assign(NewRing(members))
+			after := assign(NewRing(members[:2]))
+
+			for _, key := range keys {
+				if before[key] != members[2] {
+					Expect(after[key]).To(Equal(before[key]))
+				} else {
+					Expect(after[key]).ToNot(Equal(members[2]))
+				}
+			}
+		})
+	})
+})
+
+var _ = Describe("Members", func() {
+	pod := func(ip string, ready, terminating bool) corev1.Pod {
+		p := corev1.Pod{Status: corev1.PodStatus{PodIP: ip}}
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		p.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: status}}
+		if terminating {
+			now := metav1.Now()
+			p.SetDeletionTimestamp(&now)
+		}
+		return p
+	}
+
+	It("should return only the ready and non terminating pods, sorted", func() {
+		pods := []corev1.Pod{
+			pod("10.0.0.3", true, false),
+			pod("10.0.0.1", true, false),
+			pod("10.0.0.2", false, false),
+			pod("10.0.0.4", true, true),
+			pod("", true, false),
+		}
+		Expect(Members(pods)).To(Equal([]string{"10.0.0.1", "10.0.0.3"}))
+	})
+})
@@ -15,6 +15,8 @@
 package tunnel
 
 import (
+	"time"
+
 	"github.com/vishvananda/netlink"
 	k8s "k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
@@ -55,3 +57,9 @@ type Driver interface {
 
 	Close() error
 }
+
+// HandshakeReporter is the interface optionally implemented by the vpn drivers performing periodic
+// handshakes with the remote peers, to report the time of the last one.
+type HandshakeReporter interface {
+	LastHandshake(tep *netv1alpha1.TunnelEndpoint) (time.Time, error)
+}
@@ -0,0 +1,690 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
+	liqoconst "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/liqonet/tunnel"
+	"github.com/liqotech/liqo/pkg/liqonet/utils"
+	"github.com/liqotech/liqo/pkg/liqonet/utils/links"
+)
+
+const (
+	// PrivateKey is the key of private for the secret containing the IPsec keys.
+	PrivateKey = "privateKey"
+	// EndpointIP is the key of the endpointIP entry in the peer configuration map.
+	EndpointIP = "endpointIP"
+	// AllowedIPs is the key of the allowedIPs entry in the peer configuration map.
+	AllowedIPs = "allowedIPs"
+	// InboundReqID is the key of the entry of the peer configuration map reporting the request ID of the inbound SAs.
+	InboundReqID = "inboundReqID"
+	// OutboundReqID is the key of the entry of the peer configuration map reporting the request ID of the outbound SAs.
+	OutboundReqID = "outboundReqID"
+	// SAState is the key of the entry of the peer configuration map reporting the state of the SAs.
+	SAState = "saState"
+	// SAStateInstalled is the value of the SAState entry when both the inbound and outbound SAs are installed.
+	SAStateInstalled = "Installed"
+
+	// name of the secret that contains the keys used by the IPsec driver.
+	keysName = "ipsec-keys"
+	// aeadAlgorithm is the AEAD algorithm used to protect the ESP packets (i.e., AES-GCM).
+	aeadAlgorithm = "rfc4106(gcm(aes))"
+	// aeadICVLength is the length (in bits) of the integrity check value.
+	aeadICVLength = 128
+	// replayWindow is the size (in packets) of the anti-replay window of the SAs, which use extended sequence numbers.
+	replayWindow = 128
+	// rekeyInterval is the interval after which new SAs are derived and installed.
+	rekeyInterval = time.Hour
+	// udpEncap and udpEncapEspInUDP are the UDP_ENCAP socket option and the UDP_ENCAP_ESPINUDP encapsulation
+	// type (see linux/udp.h), which are not exposed by the vendored version of golang.org/x/sys/unix.
+	udpEncap         = 100
+	udpEncapEspInUDP = 2
+)
+
+// Registering the driver as available.
+func init() {
+	tunnel.AddDriver(liqoconst.IPsecDriverName, NewDriver)
+}
+
+type ipsecConfig struct {
+	// listening port, used for the UDP encapsulation of the ESP packets.
+	port int
+	// local address, used as source of the ESP packets.
+	localIP net.IP
+	// private key, used for the key agreement.
+	priKey []byte
+	// public key, advertised to the remote clusters.
+	pubKey string
+	// nonce, regenerated at every start and advertised to the remote clusters.
+	nonce []byte
+	// iFaceMTU mtu of the XFRM interface.
+	iFaceMTU int
+}
+
+// IPsec a wrapper for the XFRM interface and the IPsec configuration.
+type IPsec struct {
+	// mutex protects the connections and the peers, which are also accessed during the periodic rekeys.
+	mutex       sync.Mutex
+	connections map[string]*netv1alpha1.Connection
+	peers       map[string]*peer
+	stopRekey   context.CancelFunc
+	// handle is bound to the network namespace the driver is created in, which hosts the states and policies.
+	handle *netlink.Handle
+	socket *net.UDPConn
+	link   netlink.Link
+	conf   ipsecConfig
+}
+
+// NewDriver creates a new IPsec driver.
+func NewDriver(k8sClient k8s.Interface, namespace string, config tunnel.Config) (tunnel.Driver, error) {
+	var err error
+	d := IPsec{
+		connections: make(map[string]*netv1alpha1.Connection),
+		peers:       make(map[string]*peer),
+		conf: ipsecConfig{
+			port:     config.ListeningPort,
+			iFaceMTU: config.MTU,
+		},
+	}
+
+	if err = d.setKeys(k8sClient, namespace); err != nil {
+		return nil, err
+	}
+	if d.conf.localIP, err = utils.GetPodIP(); err != nil {
+		return nil, fmt.Errorf("failed to retrieve the local address: %w", err)
+	}
+
+	// The handle is created in the current network namespace, where the ESP packets are received,
+	// and it keeps operating there also once the XFRM interface is moved to the gateway network namespace.
+	if d.handle, err = netlink.NewHandle(unix.NETLINK_XFRM); err != nil {
+		return nil, fmt.Errorf("failed to create xfrm netlink handle: %w", err)
+	}
+	if err = d.setXfrmLink(); err != nil {
+		d.handle.Delete()
+		return nil, fmt.Errorf("failed to setup %s link: %w", liqoconst.IPsecDriverName, err)
+	}
+	if d.socket, err = openEncapSocket(d.conf.port); err != nil {
+		d.handle.Delete()
+		return nil, fmt.Errorf("failed to open the UDP encapsulation socket: %w", err)
+	}
+
+	var ctx context.Context
+	ctx, d.stopRekey = context.WithCancel(context.Background())
+	go d.rekeyLoop(ctx)
+
+	klog.Infof("created %s interface named %s with publicKey %s", liqoconst.IPsecDriverName, liqoconst.IPsecDeviceName, d.conf.pubKey)
+	return &d, nil
+}
+
+// Init initializes the XFRM interface.
+func (d *IPsec) Init() error {
+	// ip link set $IPsecDeviceName up.
+	if err := netlink.LinkSetUp(d.link); err != nil {
+		return fmt.Errorf("failed to bring up IPsec device: %w", err)
+	}
+
+	if err := netlink.LinkSetMTU(d.link, d.conf.iFaceMTU); err != nil {
+		return fmt.Errorf("failed to set MTU for interface %s: %w", liqoconst.IPsecDeviceName, err)
+	}
+
+	klog.Infof("%s interface named %s, is up on i/f number %d, listening on port :%d, with key %s", liqoconst.IPsecDriverName,
+		d.link.Attrs().Name, d.link.Attrs().Index, d.conf.port, d.conf.pubKey)
+	return nil
+}
+
+// peer groups the parameters characterizing the connection towards a remote cluster.
+type peer struct {
+	endpoint   *net.UDPAddr
+	allowedIPs []*net.IPNet
+
+	// secret is the shared secret the SAs are derived from.
+	secret        []byte
+	local, remote saEndpoint
+
+	inboundReqID, outboundReqID uint32
+}
+
+// ConnectToEndpoint connects to a remote cluster described by the given tep.
+func (d *IPsec) ConnectToEndpoint(tep *netv1alpha1.TunnelEndpoint) (*netv1alpha1.Connection, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	p, err := d.getPeer(tep)
+	if err != nil {
+		return newConnectionOnError(err.Error()), err
+	}
+
+	c := &netv1alpha1.Connection{
+		Status:        netv1alpha1.Connected,
+		StatusMessage: netv1alpha1.ConnectedMessage,
+		PeerConfiguration: map[string]string{
+			liqoconst.ListeningPort: strconv.Itoa(p.endpoint.Port), EndpointIP: p.endpoint.IP.String(),
+			AllowedIPs: stringifyAllowedIPs(p.allowedIPs), liqoconst.PublicKey: p.remote.publicKey,
+			liqoconst.IPsecNonce: base64.StdEncoding.EncodeToString(p.remote.nonce),
+			InboundReqID:         strconv.FormatUint(uint64(p.inboundReqID), 10),
+			OutboundReqID:        strconv.FormatUint(uint64(p.outboundReqID), 10),
+			SAState:              SAStateInstalled,
+		},
+	}
+
+	// check if the peer configuration is updated, and the SAs are still installed.
+	keyEpoch := epoch(time.Now(), rekeyInterval)
+	oldCon, found := d.connections[tep.Spec.ClusterID]
+	if found && reflect.DeepEqual(oldCon.PeerConfiguration, c.PeerConfiguration) && d.statesInstalled(p, keyEpoch) {
+		return oldCon, nil
+	}
+
+	// If the configuration has changed then remove the old states and policies.
+	if found {
+		klog.V(4).Infof("updating peer configuration for cluster %s", tep.Spec.ClusterID)
+		if err = d.removePeer(oldCon.PeerConfiguration); err != nil {
+			return newConnectionOnError(err.Error()), fmt.Errorf("failed to remove outdated SAs for clusterid %s: %w", tep.Spec.ClusterID, err)
+		}
+	} else {
+		klog.V(4).Infof("Connecting cluster %s endpoint %s with publicKey %s", tep.Spec.ClusterID, p.endpoint.String(), p.remote.publicKey)
+	}
+
+	if err = d.installPeer(p, keyEpoch); err != nil {
+		return newConnectionOnError(err.Error()), fmt.Errorf("failed to configure SAs for clusterid %s: %w", tep.Spec.ClusterID, err)
+	}
+
+	d.connections[tep.Spec.ClusterID] = c
+	d.peers[tep.Spec.ClusterID] = p
+	klog.V(4).Infof("Done connecting cluster peer %s@%s (inbound reqid: %#x, outbound reqid: %#x)",
+		tep.Spec.ClusterID, p.endpoint.String(), p.inboundReqID, p.outboundReqID)
+	return c, nil
+}
+
+// DisconnectFromEndpoint disconnects a remote cluster described by the given tep.
+func (d *IPsec) DisconnectFromEndpoint(tep *netv1alpha1.TunnelEndpoint) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	klog.V(4).Infof("Removing connection with cluster %s", tep.Spec.ClusterID)
+
+	if _, found := tep.Status.Connection.PeerConfiguration[SAState]; !found {
+		klog.V(4).Infof("no tunnel configured for cluster %s, nothing to be removed", tep.Spec.ClusterID)
+		return nil
+	}
+
+	if err := d.removePeer(tep.Status.Connection.PeerConfiguration); err != nil {
+		return fmt.Errorf("failed to remove IPsec SAs with clusterid %s: %w", tep.Spec.ClusterID, err)
+	}
+
+	klog.V(4).Infof("Done removing IPsec SAs with clusterid %s", tep.Spec.ClusterID)
+	delete(d.connections, tep.Spec.ClusterID)
+	delete(d.peers, tep.Spec.ClusterID)
+	return nil
+}
+
+// GetLink returns the netlink.Link referred to the XFRM interface.
+func (d *IPsec) GetLink() netlink.Link {
+	return d.link
+}
+
+// Close removes the SAs, the XFRM interface and the encapsulation socket from the host.
+func (d *IPsec) Close() error {
+	d.stopRekey()
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	for clusterID, con := range d.connections {
+		if err := d.removePeer(con.PeerConfiguration); err != nil {
+			klog.Errorf("failed to remove IPsec SAs with clusterid %s: %v", clusterID, err)
+		}
+	}
+
+	if d.socket != nil {
+		if err := d.socket.Close(); err != nil {
+			klog.Errorf("failed to close the UDP encapsulation socket: %v", err)
+		}
+	}
+	d.handle.Delete()
+
+	// it removes the XFRM interface.
+	if err := links.DeleteIFaceByName(liqoconst.IPsecDeviceName); err != nil {
+		return fmt.Errorf("failed to delete existing IPsec device: %w", err)
+	}
+	return nil
+}
+
+// getPeer parses the parameters of the remote peer from the given tep, and computes the corresponding shared secret.
+func (d *IPsec) getPeer(tep *netv1alpha1.TunnelEndpoint) (*peer, error) {
+	allowedIPs, err := getAllowedIPs(tep)
+	if err != nil {
+		return nil, err
+	}
+
+	remoteKey, found := tep.Spec.BackendConfig[liqoconst.PublicKey]
+	if !found {
+		return nil, fmt.Errorf("endpoint is missing public key")
+	}
+	remoteNonce, found := tep.Spec.BackendConfig[liqoconst.IPsecNonce]
+	if !found {
+		return nil, fmt.Errorf("endpoint is missing nonce")
+	}
+
+	endpoint, err := getEndpoint(tep)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := sharedSecret(d.conf.priKey, remoteKey)
+	if err != nil {
+		return nil, err
+	}
+
+	p := peer{endpoint: endpoint, allowedIPs: allowedIPs, secret: secret,
+		local: saEndpoint{publicKey: d.conf.pubKey, nonce: d.conf.nonce}, remote: saEndpoint{publicKey: remoteKey}}
+	if p.remote.nonce, err = ParseNonce(remoteNonce); err != nil {
+		return nil, err
+	}
+	p.outboundReqID, p.inboundReqID = deriveReqID(p.local, p.remote), deriveReqID(p.remote, p.local)
+	return &p, nil
+}
+
+// installPeer configures the inbound and outbound states and policies towards the given peer, for the given key epoch.
+// The inbound states of the adjacent epochs are installed as well, to tolerate limited clock skews between the peers.
+func (d *IPsec) installPeer(p *peer, keyEpoch uint64) error {
+	states := make([]*netlink.XfrmState, 0, 4)
+	for _, e := range []uint64{keyEpoch - 1, keyEpoch, keyEpoch + 1} {
+		inbound, err := d.inboundState(p, e)
+		if err != nil {
+			return err
+		}
+		states = append(states, inbound)
+	}
+	outbound, err := d.outboundState(p, keyEpoch)
+	if err != nil {
+		return err
+	}
+
+	if err := d.addStates(append(states, outbound)...); err != nil {
+		return err
+	}
+
+	for _, policy := range d.policies(p.endpoint, p.allowedIPs, p.outboundReqID, p.inboundReqID) {
+		if err := d.handle.XfrmPolicyUpdate(policy); err != nil {
+			return fmt.Errorf("failed to configure policy for %s: %w", policy.Dst.String(), err)
+		}
+	}
+	return nil
+}
+
+// rekeyPeer replaces the states towards the given peer with the ones derived for the given key epoch. The new outbound
+// state is added before removing the previous one, as the kernel always prefers the most recent matching state.
+func (d *IPsec) rekeyPeer(p *peer, keyEpoch uint64) error {
+	inbound, err := d.inboundState(p, keyEpoch+1)
+	if err != nil {
+		return err
+	}
+	outbound, err := d.outboundState(p, keyEpoch)
+	if err != nil {
+		return err
+	}
+	if err := d.addStates(inbound, outbound); err != nil {
+		return err
+	}
+
+	staleInbound, err := d.inboundState(p, keyEpoch-2)
+	if err != nil {
+		return err
+	}
+	staleOutbound, err := d.outboundState(p, keyEpoch-1)
+	if err != nil {
+		return err
+	}
+	for _, state := range []*netlink.XfrmState{staleOutbound, staleInbound} {
+		if err := d.handle.XfrmStateDel(state); err != nil && !isNotFound(err) {
+			return fmt.Errorf("failed to remove state with SPI %#x: %w", state.Spi, err)
+		}
+	}
+	return nil
+}
+
+// rekeyLoop periodically rekeys the SAs towards all peers, at the beginning of every key epoch.
+func (d *IPsec) rekeyLoop(ctx context.Context) {
+	for {
+		next := time.Unix(int64((epoch(time.Now(), rekeyInterval)+1)*uint64(rekeyInterval.Seconds())), 0)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Until(next)):
+		}
+
+		keyEpoch := epoch(time.Now(), rekeyInterval)
+		d.mutex.Lock()
+		for clusterID, p := range d.peers {
+			if err := d.rekeyPeer(p, keyEpoch); err != nil {
+				klog.Errorf("failed to rekey IPsec SAs with clusterid %s: %v", clusterID, err)
+				continue
+			}
+			klog.V(4).Infof("Done rekeying IPsec SAs with clusterid %s (epoch %d)", clusterID, keyEpoch)
+		}
+		d.mutex.Unlock()
+	}
+}
+
+// removePeer removes the inbound and outbound states and policies described by the given peer configuration.
+func (d *IPsec) removePeer(peerConfiguration map[string]string) error {
+	ip := net.ParseIP(peerConfiguration[EndpointIP])
+	if ip == nil {
+		return fmt.Errorf("failed to parse endpoint IP %q", peerConfiguration[EndpointIP])
+	}
+	allowedIPs, err := parseAllowedIPs(peerConfiguration[AllowedIPs])
+	if err != nil {
+		return err
+	}
+
+	endpoint := &net.UDPAddr{IP: ip}
+	// The request IDs are not considered when removing the policies, hence they are not retrieved.
+	for _, policy := range d.policies(endpoint, allowedIPs, 0, 0) {
+		if err := d.handle.XfrmPolicyDel(policy); err != nil && !isNotFound(err) {
+			return fmt.Errorf("failed to remove policy for %s: %w", policy.Dst.String(), err)
+		}
+	}
+
+	// All the states exchanged with the peer are removed, independently of the key epoch they refer to.
+	states, err := d.handle.XfrmStateList(netlink.FAMILY_V4)
+	if err != nil {
+		return fmt.Errorf("failed to list the states: %w", err)
+	}
+	for i := range states {
+		state := &states[i]
+		if state.Ifid != liqoconst.IPsecInterfaceID || !d.exchangedWith(state, ip) {
+			continue
+		}
+		if err := d.handle.XfrmStateDel(state); err != nil && !isNotFound(err) {
+			return fmt.Errorf("failed to remove state with SPI %#x: %w", state.Spi, err)
+		}
+	}
+	return nil
+}
+
+// statesInstalled returns whether both the inbound and outbound states towards the given peer are installed.
+func (d *IPsec) statesInstalled(p *peer, keyEpoch uint64) bool {
+	outbound, err := d.outboundState(p, keyEpoch)
+	if err != nil {
+		return false
+	}
+	inbound, err := d.inboundState(p, keyEpoch)
+	if err != nil {
+		return false
+	}
+
+	for _, state := range []*netlink.XfrmState{outbound, inbound} {
+		if _, err := d.handle.XfrmStateGet(state); err != nil {
+			klog.Warningf("state with SPI %#x not found: %v", state.Spi, err)
+			return false
+		}
+	}
+	return true
+}
+
+// addStates adds the given states, or updates them in case they already exist.
+func (d *IPsec) addStates(states ...*netlink.XfrmState) error {
+	for _, state := range states {
+		if err := d.handle.XfrmStateAdd(state); err != nil {
+			if !errors.Is(err, unix.EEXIST) {
+				return fmt.Errorf("failed to add state with SPI %#x: %w", state.Spi, err)
+			}
+			if err := d.handle.XfrmStateUpdate(state); err != nil {
+				return fmt.Errorf("failed to update state with SPI %#x: %w", state.Spi, err)
+			}
+		}
+	}
+	return nil
+}
+
+// exchangedWith returns whether the given state protects the packets exchanged with the given endpoint.
+func (d *IPsec) exchangedWith(state *netlink.XfrmState, endpoint net.IP) bool {
+	return (state.Src.Equal(d.conf.localIP) && state.Dst.Equal(endpoint)) ||
+		(state.Src.Equal(endpoint) && state.Dst.Equal(d.conf.localIP))
+}
+
+// outboundState returns the outbound state towards the given peer, for the given key epoch.
+func (d *IPsec) outboundState(p *peer, keyEpoch uint64) (*netlink.XfrmState, error) {
+	key, spi, err := deriveSA(p.secret, p.local, p.remote, keyEpoch)
+	if err != nil {
+		return nil, err
+	}
+	return d.state(d.conf.localIP, p.endpoint.IP, d.conf.port, p.endpoint.Port, spi, p.outboundReqID, key), nil
+}
+
+// inboundState returns the inbound state from the given peer, for the given key epoch.
+func (d *IPsec) inboundState(p *peer, keyEpoch uint64) (*netlink.XfrmState, error) {
+	key, spi, err := deriveSA(p.secret, p.remote, p.local, keyEpoch)
+	if err != nil {
+		return nil, err
+	}
+	return d.state(p.endpoint.IP, d.conf.localIP, p.endpoint.Port, d.conf.port, spi, p.inboundReqID, key), nil
+}
+
+// state returns the state characterized by the given parameters, with anti-replay protection and extended sequence numbers.
+func (d *IPsec) state(src, dst net.IP, srcPort, dstPort int, spi, reqID uint32, key []byte) *netlink.XfrmState {
+	return &netlink.XfrmState{
+		Src: src, Dst: dst, Proto: netlink.XFRM_PROTO_ESP, Mode: netlink.XFRM_MODE_TUNNEL,
+		Spi: int(spi), Reqid: int(reqID), Ifid: liqoconst.IPsecInterfaceID,
+		ReplayWindow: replayWindow, ESN: true,
+		Aead:  &netlink.XfrmStateAlgo{Name: aeadAlgorithm, Key: key, ICVLen: aeadICVLength},
+		Encap: &netlink.XfrmStateEncap{Type: netlink.XFRM_ENCAP_ESPINUDP, SrcPort: srcPort, DstPort: dstPort, OriginalAddress: net.IPv4zero},
+	}
+}
+
+// policies returns the outbound and inbound policies for the given set of remote networks.
+func (d *IPsec) policies(endpoint *net.UDPAddr, allowedIPs []*net.IPNet, outboundReqID, inboundReqID uint32) []*netlink.XfrmPolicy {
+	anyNetwork := &net.IPNet{IP: net.IPv4zero, Mask: net.CIDRMask(0, 8*net.IPv4len)}
+	policies := make([]*netlink.XfrmPolicy, 0, 2*len(allowedIPs))
+	for _, network := range allowedIPs {
+		policies = append(policies,
+			&netlink.XfrmPolicy{
+				Src: anyNetwork, Dst: network, Dir: netlink.XFRM_DIR_OUT, Ifid: liqoconst.IPsecInterfaceID,
+				Tmpls: []netlink.XfrmPolicyTmpl{{Src: d.conf.localIP, Dst: endpoint.IP,
+					Proto: netlink.XFRM_PROTO_ESP, Mode: netlink.XFRM_MODE_TUNNEL, Reqid: int(outboundReqID)}},
+			},
+			&netlink.XfrmPolicy{
+				Src: network, Dst: anyNetwork, Dir: netlink.XFRM_DIR_IN, Ifid: liqoconst.IPsecInterfaceID,
+				Tmpls: []netlink.XfrmPolicyTmpl{{Src: endpoint.IP, Dst: d.conf.localIP,
+					Proto: netlink.XFRM_PROTO_ESP, Mode: netlink.XFRM_MODE_TUNNEL, Reqid: int(inboundReqID)}},
+			})
+	}
+	return policies
+}
+
+// Create new XFRM link.
+func (d *IPsec) setXfrmLink() error {
+	// delete existing XFRM device if needed.
+	if err := links.DeleteIFaceByName(liqoconst.IPsecDeviceName); err != nil {
+		return fmt.Errorf("failed to delete existing IPsec device: %w", err)
+	}
+
+	// create the XFRM device (ip link add dev $IPsecDeviceName type xfrm if_id $IPsecInterfaceID).
+	la := netlink.NewLinkAttrs()
+	la.Name = liqoconst.IPsecDeviceName
+	la.MTU = d.conf.iFaceMTU
+	link := &netlink.Xfrmi{LinkAttrs: la, Ifid: liqoconst.IPsecInterfaceID}
+	if err := netlink.LinkAdd(link); err != nil {
+		return fmt.Errorf("failed to add IPsec device '%s': %w", liqoconst.IPsecDeviceName, err)
+	}
+	d.link = link
+	return nil
+}
+
+// setKeys retrieves the key pair from the secret (or generates it, if not present), and generates a new nonce,
+// which is stored in the same secret to be advertised to the remote clusters.
+func (d *IPsec) setKeys(c k8s.Interface, namespace string) error {
+	nonce, err := generateNonce()
+	if err != nil {
+		return fmt.Errorf("error generating nonce for IPsec backend: %w", err)
+	}
+	if d.conf.nonce, err = ParseNonce(nonce); err != nil {
+		return err
+	}
+
+	// first we check if a secret containing valid keys already exists.
+	s, err := c.CoreV1().Secrets(namespace).Get(context.Background(), keysName, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	// if the secret does not exist then keys are generated and saved into a secret.
+	if apierrors.IsNotFound(err) {
+		priv, pub, err := generateKeyPair()
+		if err != nil {
+			return fmt.Errorf("error generating private key for IPsec backend: %w", err)
+		}
+		d.conf.priKey = priv
+		d.conf.pubKey = pub
+		pKey := corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      keysName,
+				Namespace: namespace,
+				Labels:    map[string]string{liqoconst.KeysLabel: liqoconst.IPsecDriverName},
+			},
+			StringData: map[string]string{liqoconst.PublicKey: pub, PrivateKey: base64.StdEncoding.EncodeToString(priv), liqoconst.IPsecNonce: nonce},
+		}
+		if _, err = c.CoreV1().Secrets(namespace).Create(context.Background(), &pKey, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create the secret with name %s: %w", keysName, err)
+		}
+		return nil
+	}
+
+	// get the private key from the existing secret, and derive the public one.
+	privKey, found := s.Data[PrivateKey]
+	if !found {
+		return fmt.Errorf("no data with key '%s' found in secret %s", PrivateKey, keysName)
+	}
+	if d.conf.priKey, err = base64.StdEncoding.DecodeString(string(privKey)); err != nil {
+		return fmt.Errorf("an error occurred while parsing the private key for the IPsec driver: %w", err)
+	}
+	if d.conf.pubKey, err = publicKey(d.conf.priKey); err != nil {
+		return fmt.Errorf("an error occurred while deriving the public key for the IPsec driver: %w", err)
+	}
+
+	// store the new nonce in the secret, to advertise it to the remote clusters.
+	s.Data[liqoconst.PublicKey] = []byte(d.conf.pubKey)
+	s.Data[liqoconst.IPsecNonce] = []byte(nonce)
+	if _, err = c.CoreV1().Secrets(namespace).Update(context.Background(), s, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update the secret with name %s: %w", keysName, err)
+	}
+	return nil
+}
+
+// openEncapSocket opens the UDP socket receiving the ESP packets encapsulated in UDP (to support NAT traversal).
+func openEncapSocket(port int) (*net.UDPConn, error) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{Port: port})
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	var serr error
+	if err := raw.Control(func(fd uintptr) {
+		serr = unix.SetsockoptInt(int(fd), unix.IPPROTO_UDP, udpEncap, udpEncapEspInUDP)
+	}); err != nil || serr != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to enable the UDP encapsulation: %v, %v", err, serr)
+	}
+	return conn, nil
+}
+
+// Function that receives a TunnelEndpoint resource and extracts the remote networks reachable through the tunnel.
+func getAllowedIPs(tep *netv1alpha1.TunnelEndpoint) ([]*net.IPNet, error) {
+	return parseAllowedIPs(strings.Join(utils.GetRemoteCIDRs(tep), ", "))
+}
+
+// parseAllowedIPs parses the comma-separated list of networks.
+func parseAllowedIPs(allowedIPs string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+	for _, cidr := range strings.Split(allowedIPs, ",") {
+		_, network, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse CIDR %s: %w", cidr, err)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// stringifyAllowedIPs returns the comma-separated list of networks.
+func stringifyAllowedIPs(allowedIPs []*net.IPNet) string {
+	networks := make([]string, len(allowedIPs))
+	for i := range allowedIPs {
+		networks[i] = allowedIPs[i].String()
+	}
+	return strings.Join(networks, ", ")
+}
+
+func getEndpoint(tep *netv1alpha1.TunnelEndpoint) (*net.UDPAddr, error) {
+	// Get port.
+	port, found := tep.Spec.BackendConfig[liqoconst.ListeningPort]
+	if !found {
+		return nil, fmt.Errorf("port not found in BackendConfig map using key {%s}", liqoconst.ListeningPort)
+	}
+	// Convert port from string to int.
+	tunnelPort, err := strconv.ParseInt(port, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse port {%s} to int: %w", port, err)
+	}
+	// If port is not in the correct range, then return an error.
+	if tunnelPort < liqoconst.UDPMinPort || tunnelPort > liqoconst.UDPMaxPort {
+		return nil, fmt.Errorf("port {%s} should be greater than {%d} and minor than {%d}", port, liqoconst.UDPMinPort, liqoconst.UDPMaxPort)
+	}
+
+	// Get tunnel ip (only IPv4 addresses are currently supported, as the encapsulation socket is IPv4).
+	address, err := net.ResolveIPAddr("ip4", tep.Spec.EndpointIP)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve endpoint address {%s}: %w", tep.Spec.EndpointIP, err)
+	}
+	return &net.UDPAddr{IP: address.IP, Port: int(tunnelPort)}, nil
+}
+
+// isNotFound returns whether the given error signals that the state or policy does not exist.
+func isNotFound(err error) bool {
+	return errors.Is(err, unix.ESRCH) || errors.Is(err, unix.ENOENT)
+}
+
+func newConnectionOnError(msg string) *netv1alpha1.Connection {
+	return &netv1alpha1.Connection{
+		Status:            netv1alpha1.ConnectionError,
+		StatusMessage:     msg,
+		PeerConfiguration: nil,
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"net"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
+	liqoconst "github.com/liqotech/liqo/pkg/consts"
+)
+
+var _ = Describe("Driver", func() {
+	Describe("The getEndpoint function", func() {
+		tep := func(port string) *netv1alpha1.TunnelEndpoint {
+			return &netv1alpha1.TunnelEndpoint{Spec: netv1alpha1.TunnelEndpointSpec{
+				EndpointIP:    "10.0.0.1",
+				BackendConfig: map[string]string{liqoconst.ListeningPort: port},
+			}}
+		}
+
+		DescribeTable("should validate the endpoint port",
+			func(port string, expected int, shouldFail bool) {
+				endpoint, err := getEndpoint(tep(port))
+				if shouldFail {
+					Expect(err).To(HaveOccurred())
+					return
+				}
+				Expect(err).ToNot(HaveOccurred())
+				Expect(endpoint.IP.Equal(net.ParseIP("10.0.0.1"))).To(BeTrue())
+				Expect(endpoint.Port).To(Equal(expected))
+			},
+			Entry("a valid port", "55555", 55555, false),
+			Entry("a port lower than the minimum value", "0", 0, true),
+			Entry("a port greater than the maximum value", "65536", 0, true),
+			Entry("not a number", "notANumber", 0, true),
+		)
+
+		It("should fail if the port is not set", func() {
+			endpoint := tep("")
+			delete(endpoint.Spec.BackendConfig, liqoconst.ListeningPort)
+			_, err := getEndpoint(endpoint)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("The allowed IPs parsing", func() {
+		It("should correctly parse and stringify the networks", func() {
+			networks, err := parseAllowedIPs("10.0.0.0/16, 192.168.0.0/24")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(networks).To(HaveLen(2))
+			Expect(stringifyAllowedIPs(networks)).To(Equal("10.0.0.0/16, 192.168.0.0/24"))
+		})
+
+		It("should fail with an invalid network", func() {
+			_, err := parseAllowedIPs("10.0.0.0/16, invalid")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,149 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// aeadKeyLength is the length of the key material of the AEAD algorithm (i.e., 256 bits key and 32 bits salt).
+	aeadKeyLength = 36
+	// nonceLength is the length of the random nonce generated at every start of the driver, and mixed in the key
+	// derivation to guarantee that the key material is never reused (which would cause the reuse of the GCM nonces).
+	nonceLength = 16
+	// keyDerivationLabel is the label used to bind the derived key material to the IPsec driver.
+	keyDerivationLabel = "liqo-ipsec"
+)
+
+// saEndpoint groups the parameters identifying one of the endpoints of a security association.
+type saEndpoint struct {
+	publicKey string
+	nonce     []byte
+}
+
+// curve returns the elliptic curve used for the key agreement.
+func curve() ecdh.Curve {
+	return ecdh.P256()
+}
+
+// generateKeyPair generates a new key pair for the key agreement, returning
+// the raw private key and the base64-encoded uncompressed public key.
+func generateKeyPair() (priv []byte, pub string, err error) {
+	key, err := curve().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, "", err
+	}
+	return key.Bytes(), base64.StdEncoding.EncodeToString(key.PublicKey().Bytes()), nil
+}
+
+// publicKey returns the base64-encoded public key corresponding to the given private key.
+func publicKey(priv []byte) (string, error) {
+	key, err := curve().NewPrivateKey(priv)
+	if err != nil {
+		return "", fmt.Errorf("invalid private key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(key.PublicKey().Bytes()), nil
+}
+
+// ParsePublicKey parses a base64-encoded public key, and returns an error if not valid.
+func ParsePublicKey(pub string) (*ecdh.PublicKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(pub)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode public key %s: %w", pub, err)
+	}
+
+	key, err := curve().NewPublicKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("public key %s is not a valid point of the curve: %w", pub, err)
+	}
+	return key, nil
+}
+
+// generateNonce generates a new random nonce, returning it base64-encoded.
+func generateNonce() (string, error) {
+	nonce := make([]byte, nonceLength)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(nonce), nil
+}
+
+// ParseNonce parses a base64-encoded nonce, and returns an error if not valid.
+func ParseNonce(nonce string) ([]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(nonce)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode nonce %s: %w", nonce, err)
+	}
+	if len(raw) != nonceLength {
+		return nil, fmt.Errorf("nonce %s has an invalid length (expected %d bytes)", nonce, nonceLength)
+	}
+	return raw, nil
+}
+
+// sharedSecret computes the shared secret between the local private key and the remote public key.
+func sharedSecret(priv []byte, remotePub string) ([]byte, error) {
+	key, err := curve().NewPrivateKey(priv)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+
+	remote, err := ParsePublicKey(remotePub)
+	if err != nil {
+		return nil, err
+	}
+	return key.ECDH(remote)
+}
+
+// epoch returns the key epoch corresponding to the given time, according to the rekey interval.
+func epoch(t time.Time, interval time.Duration) uint64 {
+	return uint64(t.Unix()) / uint64(interval.Seconds())
+}
+
+// deriveSA derives the key material and the SPI of the security association from the source to the destination
+// cluster, for the given key epoch. The derivation is deterministic, hence the outbound security association of a
+// cluster matches the inbound one of its peer. Still, the nonces (regenerated at every restart of the drivers) and the
+// epoch (periodically incremented) are mixed in the derivation, to guarantee that the key material is never reused.
+func deriveSA(secret []byte, src, dst saEndpoint, keyEpoch uint64) (key []byte, spi uint32, err error) {
+	salt := make([]byte, 0, len(src.nonce)+len(dst.nonce))
+	salt = append(append(salt, src.nonce...), dst.nonce...)
+	info := fmt.Sprintf("%s %s %s %d", keyDerivationLabel, src.publicKey, dst.publicKey, keyEpoch)
+	reader := hkdf.New(sha256.New, secret, salt, []byte(info))
+
+	buffer := make([]byte, aeadKeyLength+4)
+	if _, err := io.ReadFull(reader, buffer); err != nil {
+		return nil, 0, fmt.Errorf("failed to derive the key material: %w", err)
+	}
+
+	// The most significant bit is always set, to ensure the SPI does not fall in the reserved range (i.e., 1-255).
+	return buffer[:aeadKeyLength], binary.BigEndian.Uint32(buffer[aeadKeyLength:]) | 1<<31, nil
+}
+
+// deriveReqID derives the request identifier binding the policies to the security associations from the source to
+// the destination cluster. Differently from the SPI, it does not depend on the key epoch, to be stable across rekeys.
+func deriveReqID(src, dst saEndpoint) uint32 {
+	digest := sha256.Sum256([]byte(fmt.Sprintf("%s %s %s", keyDerivationLabel, src.publicKey, dst.publicKey)))
+	// The request identifier is kept positive and non-zero, as zero matches any security association.
+	return binary.BigEndian.Uint32(digest[:4])&(1<<30-1) | 1
+}
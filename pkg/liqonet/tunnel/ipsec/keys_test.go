@@ -0,0 +1,160 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipsec
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Key management", func() {
+	var (
+		localPriv, remotePriv []byte
+		localPub, remotePub   string
+	)
+
+	BeforeEach(func() {
+		var err error
+		localPriv, localPub, err = generateKeyPair()
+		Expect(err).ToNot(HaveOccurred())
+		remotePriv, remotePub, err = generateKeyPair()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Describe("The publicKey function", func() {
+		It("should return the public key corresponding to the private one", func() {
+			Expect(publicKey(localPriv)).To(Equal(localPub))
+			Expect(publicKey(remotePriv)).To(Equal(remotePub))
+		})
+		It("should fail with an invalid private key", func() {
+			_, err := publicKey([]byte("invalid"))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("The ParsePublicKey function", func() {
+		It("should succeed with a valid key", func() {
+			_, err := ParsePublicKey(localPub)
+			Expect(err).ToNot(HaveOccurred())
+		})
+		It("should fail with an invalid encoding", func() {
+			_, err := ParsePublicKey("invalid!")
+			Expect(err).To(HaveOccurred())
+		})
+		It("should fail with a point not belonging to the curve", func() {
+			_, err := ParsePublicKey("cHVibGljLWtleS1vZi10aGUtY29ycmVjdC1sZW5ndGg=")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("The nonce generation", func() {
+		It("should generate valid and different nonces", func() {
+			first, err := generateNonce()
+			Expect(err).ToNot(HaveOccurred())
+			second, err := generateNonce()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ParseNonce(first)).To(HaveLen(nonceLength))
+			Expect(first).ToNot(Equal(second))
+		})
+		It("should fail to parse a nonce with an invalid length", func() {
+			_, err := ParseNonce("AAECAw==")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("The key derivation", func() {
+		var (
+			localSecret, remoteSecret []byte
+			local, remote             saEndpoint
+		)
+
+		BeforeEach(func() {
+			var err error
+			localSecret, err = sharedSecret(localPriv, remotePub)
+			Expect(err).ToNot(HaveOccurred())
+			remoteSecret, err = sharedSecret(remotePriv, localPub)
+			Expect(err).ToNot(HaveOccurred())
+
+			local = saEndpoint{publicKey: localPub, nonce: []byte("local-nonce-0123")}
+			remote = saEndpoint{publicKey: remotePub, nonce: []byte("remote-nonce-012")}
+		})
+
+		It("should compute the same shared secret on both sides", func() {
+			Expect(localSecret).To(Equal(remoteSecret))
+		})
+
+		It("should derive matching security associations on both sides", func() {
+			localOutKey, localOutSPI, err := deriveSA(localSecret, local, remote, 10)
+			Expect(err).ToNot(HaveOccurred())
+			remoteInKey, remoteInSPI, err := deriveSA(remoteSecret, local, remote, 10)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(localOutKey).To(HaveLen(aeadKeyLength))
+			Expect(localOutKey).To(Equal(remoteInKey))
+			Expect(localOutSPI).To(Equal(remoteInSPI))
+			Expect(localOutSPI).To(BeNumerically(">", 255))
+		})
+
+		It("should derive different security associations for the two directions", func() {
+			outKey, outSPI, err := deriveSA(localSecret, local, remote, 10)
+			Expect(err).ToNot(HaveOccurred())
+			inKey, inSPI, err := deriveSA(localSecret, remote, local, 10)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(outKey).ToNot(Equal(inKey))
+			Expect(outSPI).ToNot(Equal(inSPI))
+			Expect(deriveReqID(local, remote)).ToNot(Equal(deriveReqID(remote, local)))
+		})
+
+		It("should derive different security associations when a nonce changes", func() {
+			before, _, err := deriveSA(localSecret, local, remote, 10)
+			Expect(err).ToNot(HaveOccurred())
+			local.nonce = []byte("local-nonce-4567")
+			after, _, err := deriveSA(localSecret, local, remote, 10)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(before).ToNot(Equal(after))
+		})
+
+		It("should derive different security associations for different epochs", func() {
+			current, currentSPI, err := deriveSA(localSecret, local, remote, 10)
+			Expect(err).ToNot(HaveOccurred())
+			next, nextSPI, err := deriveSA(localSecret, local, remote, 11)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(current).ToNot(Equal(next))
+			Expect(currentSPI).ToNot(Equal(nextSPI))
+		})
+
+		It("should derive request IDs independent of the nonces", func() {
+			reqID := deriveReqID(local, remote)
+			local.nonce = []byte("local-nonce-4567")
+			Expect(deriveReqID(local, remote)).To(Equal(reqID))
+			Expect(reqID).To(BeNumerically(">", 0))
+		})
+	})
+
+	Describe("The epoch function", func() {
+		It("should return the same epoch within the rekey interval", func() {
+			start := time.Unix(7200, 0)
+			Expect(epoch(start, time.Hour)).To(BeNumerically("==", 2))
+			Expect(epoch(start.Add(59*time.Minute), time.Hour)).To(BeNumerically("==", 2))
+			Expect(epoch(start.Add(time.Hour), time.Hour)).To(BeNumerically("==", 3))
+		})
+	})
+})
@@ -0,0 +1,66 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probe
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+type packetType byte
+
+const (
+	// requestPacket identifies the probes sent towards the remote peers.
+	requestPacket packetType = iota + 1
+	// replyPacket identifies the answers to the probes received from the remote peers.
+	replyPacket
+
+	// packetSize is the size of the probe packets: magic (4 bytes), type (1 byte) and sequence number (8 bytes).
+	packetSize = 13
+)
+
+// magic prefixes all the probe packets, to discard unrelated traffic reaching the socket.
+var magic = [4]byte{'l', 'q', 'p', 'b'}
+
+// packet represents a probe exchanged with a remote peer.
+type packet struct {
+	kind packetType
+	seq  uint64
+}
+
+// marshal returns the binary representation of the packet.
+func (p *packet) marshal() []byte {
+	buffer := make([]byte, packetSize)
+	copy(buffer, magic[:])
+	buffer[4] = byte(p.kind)
+	binary.BigEndian.PutUint64(buffer[5:], p.seq)
+	return buffer
+}
+
+// unmarshal parses the binary representation of a packet.
+func unmarshal(buffer []byte) (*packet, error) {
+	if len(buffer) != packetSize {
+		return nil, errors.New("unexpected packet size")
+	}
+	if [4]byte{buffer[0], buffer[1], buffer[2], buffer[3]} != magic {
+		return nil, errors.New("unexpected packet prefix")
+	}
+
+	kind := packetType(buffer[4])
+	if kind != requestPacket && kind != replyPacket {
+		return nil, errors.New("unexpected packet type")
+	}
+	return &packet{kind: kind, seq: binary.BigEndian.Uint64(buffer[5:])}, nil
+}
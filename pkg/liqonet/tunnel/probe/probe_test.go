@@ -0,0 +1,137 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probe
+
+import (
+	"context"
+	"net"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Probe packets", func() {
+	It("should be correctly marshaled and unmarshaled", func() {
+		original := packet{kind: requestPacket, seq: 42}
+		parsed, err := unmarshal(original.marshal())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*parsed).To(Equal(original))
+	})
+
+	DescribeTable("should refuse invalid packets",
+		func(buffer []byte) {
+			_, err := unmarshal(buffer)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("packet too short", []byte{'l', 'q', 'p', 'b', 1}),
+		Entry("wrong prefix", []byte{'l', 'q', 'p', 'x', 1, 0, 0, 0, 0, 0, 0, 0, 1}),
+		Entry("wrong type", []byte{'l', 'q', 'p', 'b', 7, 0, 0, 0, 0, 0, 0, 0, 1}),
+	)
+})
+
+var _ = Describe("Statistics computation", func() {
+	DescribeTable("the computeStats function",
+		func(samples []sample, expected Stats) {
+			Expect(computeStats(samples)).To(Equal(expected))
+		},
+		Entry("no samples", nil, Stats{}),
+		Entry("all probes answered", []sample{
+			{rtt: 10 * time.Millisecond}, {rtt: 20 * time.Millisecond}, {rtt: 15 * time.Millisecond},
+		}, Stats{RTT: 15 * time.Millisecond, Jitter: 7500 * time.Microsecond, Samples: 3}),
+		Entry("some probes lost", []sample{
+			{rtt: 10 * time.Millisecond}, {lost: true}, {rtt: 30 * time.Millisecond}, {lost: true},
+		}, Stats{RTT: 20 * time.Millisecond, Jitter: 20 * time.Millisecond, PacketLoss: 50, Samples: 4}),
+		Entry("all probes lost", []sample{{lost: true}, {lost: true}}, Stats{PacketLoss: 100, Samples: 2}),
+	)
+})
+
+var _ = Describe("Prober", func() {
+	var (
+		ctx    context.Context
+		cancel context.CancelFunc
+
+		local, remote *Prober
+		remoteAddr    *net.UDPAddr
+	)
+
+	listen := func() *net.UDPConn {
+		conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+		Expect(err).ToNot(HaveOccurred())
+		return conn
+	}
+
+	BeforeEach(func() {
+		ctx, cancel = context.WithCancel(context.Background())
+
+		localConn, remoteConn := listen(), listen()
+		remoteAddr = remoteConn.LocalAddr().(*net.UDPAddr)
+
+		local = NewProber(localConn, 20*time.Millisecond, DefaultWindow, nil)
+		remote = NewProber(remoteConn, 20*time.Millisecond, DefaultWindow, nil)
+	})
+
+	AfterEach(func() { cancel() })
+
+	When("the remote peer answers the probes", func() {
+		BeforeEach(func() {
+			local.Add("remote", remoteAddr)
+			local.Start(ctx)
+			remote.Start(ctx)
+		})
+
+		It("should report no packet loss", func() {
+			Eventually(func() int {
+				stats, _ := local.Stats("remote")
+				return stats.Samples
+			}).Should(BeNumerically(">=", 3))
+
+			stats, found := local.Stats("remote")
+			Expect(found).To(BeTrue())
+			Expect(stats.PacketLoss).To(BeZero())
+			Expect(stats.RTT).To(BeNumerically(">", 0))
+		})
+	})
+
+	When("the remote peer does not answer the probes", func() {
+		BeforeEach(func() {
+			local.Add("remote", remoteAddr)
+			local.Start(ctx)
+		})
+
+		It("should report full packet loss", func() {
+			Eventually(func() int {
+				stats, _ := local.Stats("remote")
+				return stats.Samples
+			}).Should(BeNumerically(">=", 3))
+
+			stats, _ := local.Stats("remote")
+			Expect(stats.PacketLoss).To(Equal(100))
+		})
+	})
+
+	When("a peer is removed", func() {
+		BeforeEach(func() {
+			local.Add("remote", remoteAddr)
+			local.Remove("remote")
+		})
+
+		It("should no longer report its statistics", func() {
+			_, found := local.Stats("remote")
+			Expect(found).To(BeFalse())
+		})
+	})
+})
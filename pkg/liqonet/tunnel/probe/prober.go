@@ -0,0 +1,207 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probe
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// DefaultWindow is the default number of probes considered to compute the statistics.
+const DefaultWindow = 10
+
+// target tracks the probes sent towards a given peer.
+type target struct {
+	addr    *net.UDPAddr
+	seq     uint64
+	pending map[uint64]time.Time
+	samples []sample
+}
+
+// Prober periodically sends probes to a set of peers through a UDP socket,
+// and answers the probes received from the remote ones on the same socket.
+type Prober struct {
+	conn     *net.UDPConn
+	interval time.Duration
+	window   int
+	notify   func(key string)
+
+	mutex   sync.Mutex
+	targets map[string]*target
+}
+
+// NewProber returns a new Prober, which sends a probe every interval to each peer, and computes the
+// statistics considering the last window probes. The notify function, if not nil, is invoked with the
+// key of each peer every time the corresponding statistics are updated.
+func NewProber(conn *net.UDPConn, interval time.Duration, window int, notify func(key string)) *Prober {
+	return &Prober{
+		conn:     conn,
+		interval: interval,
+		window:   window,
+		notify:   notify,
+		targets:  make(map[string]*target),
+	}
+}
+
+// Add starts probing the peer identified by key, reachable at the given address.
+// In case the address of an existing peer changed, the corresponding statistics are reset.
+func (p *Prober) Add(key string, addr *net.UDPAddr) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if t, found := p.targets[key]; found && t.addr.String() == addr.String() {
+		return
+	}
+
+	klog.V(4).Infof("Starting to probe peer %q at %s", key, addr)
+	p.targets[key] = &target{addr: addr, pending: make(map[uint64]time.Time)}
+}
+
+// Remove stops probing the peer identified by key.
+func (p *Prober) Remove(key string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if _, found := p.targets[key]; found {
+		klog.V(4).Infof("Stopping to probe peer %q", key)
+		delete(p.targets, key)
+	}
+}
+
+// Stats returns the current statistics of the peer identified by key, and whether it is being probed.
+func (p *Prober) Stats(key string) (Stats, bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	t, found := p.targets[key]
+	if !found {
+		return Stats{}, false
+	}
+	return computeStats(t.samples), true
+}
+
+// Start starts sending the probes and answering the ones received, until the given context is canceled.
+// The underlying socket is closed at termination.
+func (p *Prober) Start(ctx context.Context) {
+	go p.receive()
+	go func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				if err := p.conn.Close(); err != nil {
+					klog.Errorf("Failed to close the probe socket: %v", err)
+				}
+				return
+			case now := <-ticker.C:
+				p.probe(now)
+			}
+		}
+	}()
+}
+
+// probe accounts for the probes not answered in time, and sends a new one to each peer.
+func (p *Prober) probe(now time.Time) {
+	p.mutex.Lock()
+	keys := make([]string, 0, len(p.targets))
+	for key, t := range p.targets {
+		for seq, sent := range t.pending {
+			if now.Sub(sent) >= p.interval {
+				delete(t.pending, seq)
+				p.record(t, sample{lost: true})
+			}
+		}
+
+		t.seq++
+		pkt := packet{kind: requestPacket, seq: t.seq}
+		if _, err := p.conn.WriteToUDP(pkt.marshal(), t.addr); err != nil {
+			// The probe is accounted as lost at the next round.
+			klog.V(4).Infof("Failed to send probe to peer %q at %s: %v", key, t.addr, err)
+		}
+		t.pending[t.seq] = now
+		keys = append(keys, key)
+	}
+	p.mutex.Unlock()
+
+	if p.notify != nil {
+		for _, key := range keys {
+			p.notify(key)
+		}
+	}
+}
+
+// receive answers the probes received from the remote peers, and accounts for the replies to the ones sent.
+func (p *Prober) receive() {
+	buffer := make([]byte, packetSize+1)
+	for {
+		n, addr, err := p.conn.ReadFromUDP(buffer)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			klog.V(4).Infof("Failed to receive probe: %v", err)
+			continue
+		}
+		now := time.Now()
+
+		pkt, err := unmarshal(buffer[:n])
+		if err != nil {
+			klog.V(5).Infof("Discarding invalid probe received from %s: %v", addr, err)
+			continue
+		}
+
+		switch pkt.kind {
+		case requestPacket:
+			reply := packet{kind: replyPacket, seq: pkt.seq}
+			if _, err := p.conn.WriteToUDP(reply.marshal(), addr); err != nil {
+				klog.V(4).Infof("Failed to answer probe received from %s: %v", addr, err)
+			}
+		case replyPacket:
+			p.handleReply(addr, pkt.seq, now)
+		}
+	}
+}
+
+// handleReply records the round-trip time of the probe answered by the given peer.
+func (p *Prober) handleReply(addr *net.UDPAddr, seq uint64, now time.Time) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for _, t := range p.targets {
+		if t.addr.String() != addr.String() {
+			continue
+		}
+		if sent, found := t.pending[seq]; found {
+			delete(t.pending, seq)
+			p.record(t, sample{rtt: now.Sub(sent)})
+		}
+		return
+	}
+}
+
+// record appends a new sample to the given target, discarding the ones outside the observation window.
+func (p *Prober) record(t *target, s sample) {
+	t.samples = append(t.samples, s)
+	if len(t.samples) > p.window {
+		t.samples = t.samples[len(t.samples)-p.window:]
+	}
+}
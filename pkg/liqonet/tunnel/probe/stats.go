@@ -0,0 +1,77 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probe
+
+import "time"
+
+// Stats summarizes the outcome of the probes towards a given peer, over the observation window.
+type Stats struct {
+	// RTT is the average round-trip time of the probes answered by the peer.
+	RTT time.Duration
+	// Jitter is the average variation of the round-trip time between consecutive answered probes.
+	Jitter time.Duration
+	// PacketLoss is the percentage of probes not answered by the peer.
+	PacketLoss int
+	// Samples is the number of probes the statistics refer to.
+	Samples int
+}
+
+// sample is the outcome of a single probe.
+type sample struct {
+	rtt  time.Duration
+	lost bool
+}
+
+// computeStats aggregates the given samples into the corresponding statistics.
+func computeStats(samples []sample) Stats {
+	stats := Stats{Samples: len(samples)}
+	if len(samples) == 0 {
+		return stats
+	}
+
+	var received, lost, variations int
+	var rttSum, jitterSum time.Duration
+	var previous *time.Duration
+	for i := range samples {
+		if samples[i].lost {
+			lost++
+			continue
+		}
+
+		received++
+		rttSum += samples[i].rtt
+		if previous != nil {
+			jitterSum += absDuration(samples[i].rtt - *previous)
+			variations++
+		}
+		previous = &samples[i].rtt
+	}
+
+	stats.PacketLoss = lost * 100 / len(samples)
+	if received > 0 {
+		stats.RTT = rttSum / time.Duration(received)
+	}
+	if variations > 0 {
+		stats.Jitter = jitterSum / time.Duration(variations)
+	}
+	return stats
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
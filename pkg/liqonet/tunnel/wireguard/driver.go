@@ -23,6 +23,7 @@ import (
 	"os"
 	"os/exec"
 	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -331,18 +332,17 @@ func (w *Wireguard) setWGLink() error {
 // wireguard allowedIPs. They are returned as []net.IPNet and
 // as a string (to accommodate comparison/storing on TEP resource).
 func getAllowedIPs(tep *netv1alpha1.TunnelEndpoint) ([]net.IPNet, string, error) {
-	_, remotePodCIDR := utils.GetPodCIDRS(tep)
-	_, remoteExternalCIDR := utils.GetExternalCIDRS(tep)
+	remoteCIDRs := utils.GetRemoteCIDRs(tep)
 
-	_, podCIDR, err := net.ParseCIDR(remotePodCIDR)
-	if err != nil {
-		return nil, "", fmt.Errorf("unable to parse podCIDR %s for cluster %s: %w", remotePodCIDR, tep.Spec.ClusterID, err)
-	}
-	_, externalCIDR, err := net.ParseCIDR(remoteExternalCIDR)
-	if err != nil {
-		return nil, "", fmt.Errorf("unable to parse externalCIDR %s for cluster %s: %w", remoteExternalCIDR, tep.Spec.ClusterID, err)
+	allowedIPs := make([]net.IPNet, 0, len(remoteCIDRs))
+	for _, cidr := range remoteCIDRs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, "", fmt.Errorf("unable to parse CIDR %s for cluster %s: %w", cidr, tep.Spec.ClusterID, err)
+		}
+		allowedIPs = append(allowedIPs, *network)
 	}
-	return []net.IPNet{*podCIDR, *externalCIDR}, fmt.Sprintf("%s, %s", remotePodCIDR, remoteExternalCIDR), nil
+	return allowedIPs, strings.Join(remoteCIDRs, ", "), nil
 }
 
 func getKey(tep *netv1alpha1.TunnelEndpoint) (*wgtypes.Key, error) {
@@ -492,6 +492,26 @@ func (w *Wireguard) SetNewClient() error {
 	return nil
 }
 
+// LastHandshake returns the time of the last handshake with the peer associated with the given tunnel endpoint.
+func (w *Wireguard) LastHandshake(tep *netv1alpha1.TunnelEndpoint) (time.Time, error) {
+	con, found := w.connections[tep.Spec.ClusterID]
+	if !found {
+		return time.Time{}, fmt.Errorf("no connection found for cluster %s", tep.Spec.ClusterID)
+	}
+
+	wgDev, err := w.client.Device(liqoconst.DeviceName)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	for i := range wgDev.Peers {
+		if wgDev.Peers[i].PublicKey.String() == con.PeerConfiguration[liqoconst.PublicKey] {
+			return wgDev.Peers[i].LastHandshakeTime, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("no peer with pubKey {%s} found on device {%s}", con.PeerConfiguration[liqoconst.PublicKey], liqoconst.DeviceName)
+}
+
 func (w *Wireguard) updateConnectionStatus(oldConn *netv1alpha1.Connection) (*netv1alpha1.Connection, error) {
 	var err error
 	var wgDev *wgtypes.Device
@@ -57,8 +57,19 @@ func MapIPToNetwork(newNetwork, oldIP string) (newIP string, err error) {
 	if parsedOldIP == nil {
 		return "", fmt.Errorf("cannot parse oldIP")
 	}
-	parsedOldIP = parsedOldIP.To4()
-	// Substitute the last 32-mask bits of newNetwork with bits taken by the old ip
+	if parsedNewIP != nil {
+		parsedOldIP = parsedOldIP.To4()
+	} else {
+		// IPv6 network: the addresses are represented on 16 bytes.
+		parsedNewIP = ip.To16()
+		if parsedOldIP.To4() != nil {
+			parsedOldIP = nil
+		}
+	}
+	if parsedOldIP == nil {
+		return "", fmt.Errorf("IP family mismatch between oldIP %s and network %s", oldIP, newNetwork)
+	}
+	// Substitute the last (32|128)-mask bits of newNetwork with bits taken by the old ip
 	for i := 0; i < len(mask); i++ {
 		// Step 1: NOT(mask[i]) = mask[i] ^ 0xff. They are the 'host' bits
 		// Step 2: BITWISE AND between the host bits and parsedOldIP[i] zeroes the network bits in parsedOldIP[i]
@@ -110,7 +121,8 @@ func GetMask(network string) uint8 {
 func SetMask(network string, mask uint8) string {
 	_, n, err := net.ParseCIDR(network)
 	utilruntime.Must(err)
-	newMask := net.CIDRMask(int(mask), 32)
+	_, bits := n.Mask.Size()
+	newMask := net.CIDRMask(int(mask), bits)
 	n.Mask = newMask
 	return n.String()
 }
@@ -160,6 +172,66 @@ func IsValidCIDR(cidr string) error {
 	return err
 }
 
+// IsIPv6 returns whether the received IP address belongs to the IPv6 family.
+func IsIPv6(ip string) bool {
+	parsed := net.ParseIP(ip)
+	return parsed != nil && parsed.To4() == nil
+}
+
+// IsIPv6CIDR returns whether the received CIDR belongs to the IPv6 family.
+func IsIPv6CIDR(cidr string) bool {
+	ip, _, err := net.ParseCIDR(cidr)
+	return err == nil && ip.To4() == nil
+}
+
+// GetIPv6View returns a copy of the given TunnelEndpoint in which the IPv4 networks are replaced by the IPv6 ones,
+// so that the same logic can be applied to both families. The second return value is false if the TunnelEndpoint
+// does not carry IPv6 networks (i.e., either of the two peered clusters is not dual-stack).
+func GetIPv6View(tep *netv1alpha1.TunnelEndpoint) (*netv1alpha1.TunnelEndpoint, bool) {
+	if tep.Spec.LocalPodCIDRv6 == "" || tep.Spec.RemotePodCIDRv6 == "" {
+		return nil, false
+	}
+
+	view := tep.DeepCopy()
+	view.Spec.LocalPodCIDR = tep.Spec.LocalPodCIDRv6
+	view.Spec.LocalNATPodCIDR = defaultIfEmpty(tep.Spec.LocalNATPodCIDRv6)
+	view.Spec.LocalExternalCIDR = tep.Spec.LocalExternalCIDRv6
+	view.Spec.LocalNATExternalCIDR = defaultIfEmpty(tep.Spec.LocalNATExternalCIDRv6)
+	view.Spec.RemotePodCIDR = tep.Spec.RemotePodCIDRv6
+	view.Spec.RemoteNATPodCIDR = defaultIfEmpty(tep.Spec.RemoteNATPodCIDRv6)
+	view.Spec.RemoteExternalCIDR = tep.Spec.RemoteExternalCIDRv6
+	view.Spec.RemoteNATExternalCIDR = defaultIfEmpty(tep.Spec.RemoteNATExternalCIDRv6)
+
+	// The view is a single-stack TunnelEndpoint, hence the IPv6 specific fields are cleared.
+	view.Spec.LocalPodCIDRv6, view.Spec.LocalNATPodCIDRv6 = "", ""
+	view.Spec.LocalExternalCIDRv6, view.Spec.LocalNATExternalCIDRv6 = "", ""
+	view.Spec.RemotePodCIDRv6, view.Spec.RemoteNATPodCIDRv6 = "", ""
+	view.Spec.RemoteExternalCIDRv6, view.Spec.RemoteNATExternalCIDRv6 = "", ""
+	return view, true
+}
+
+// GetRemoteCIDRs returns the networks used in the local cluster to reach the remote one (i.e., the possibly
+// remapped PodCIDR and ExternalCIDR), including the IPv6 ones in case both clusters are dual-stack.
+func GetRemoteCIDRs(tep *netv1alpha1.TunnelEndpoint) []string {
+	_, remotePodCIDR := GetPodCIDRS(tep)
+	_, remoteExternalCIDR := GetExternalCIDRS(tep)
+	cidrs := []string{remotePodCIDR, remoteExternalCIDR}
+	if view, ok := GetIPv6View(tep); ok {
+		_, remotePodCIDR = GetPodCIDRS(view)
+		_, remoteExternalCIDR = GetExternalCIDRS(view)
+		cidrs = append(cidrs, remotePodCIDR, remoteExternalCIDR)
+	}
+	return cidrs
+}
+
+// defaultIfEmpty returns the default CIDR value ("None") if the given one is empty.
+func defaultIfEmpty(cidr string) string {
+	if cidr == "" {
+		return consts.DefaultCIDRValue
+	}
+	return cidr
+}
+
 // GetFirstIP returns the first IP address of a network.
 func GetFirstIP(network string) (string, error) {
 	firstIP, _, err := net.ParseCIDR(network)
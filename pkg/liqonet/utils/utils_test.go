@@ -23,6 +23,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	netv1alpha1 "github.com/liqotech/liqo/apis/net/v1alpha1"
 	"github.com/liqotech/liqo/pkg/liqonet/utils"
 )
 
@@ -71,6 +72,25 @@ var _ = Describe("Liqonet", func() {
 		Entry("Mapping 10.2.128.128 to 10.0.126.0/25", "10.0.126.0/25", "10.2.128.128", "10.0.126.0", ""),
 		Entry("Using an invalid newPodCidr", "10.0..0/25", "10.2.128.128", "", "invalid CIDR address: 10.0..0/25"),
 		Entry("Using an invalid oldIp", "10.0.0.0/25", "10.2...128", "", "cannot parse oldIP"),
+		Entry("Mapping fd00:1::a to fd00:2::/64", "fd00:2::/64", "fd00:1::a", "fd00:2::a", ""),
+		Entry("Mapping fd00:1::1:a to fd00:2::/112", "fd00:2::/112", "fd00:1::1:a", "fd00:2::a", ""),
+		Entry("Mapping an IPv4 address to an IPv6 network", "fd00:2::/64", "10.2.1.3", "",
+			"IP family mismatch between oldIP 10.2.1.3 and network fd00:2::/64"),
+	)
+
+	DescribeTable("IsIPv6CIDR",
+		func(cidr string, expected bool) { Expect(utils.IsIPv6CIDR(cidr)).To(Equal(expected)) },
+		Entry("IPv4 network", "10.0.0.0/8", false),
+		Entry("IPv6 network", "fd00::/8", true),
+		Entry("invalid network", invalidValue, false),
+	)
+
+	DescribeTable("SetMask",
+		func(network string, mask uint8, expected string) {
+			Expect(utils.SetMask(network, mask)).To(Equal(expected))
+		},
+		Entry("IPv4 network", "10.0.0.0/8", uint8(9), "10.0.0.0/9"),
+		Entry("IPv6 network", "fd00::/8", uint8(9), "fd00::/9"),
 	)
 
 	DescribeTable("GetFirstIP",
@@ -90,6 +110,35 @@ var _ = Describe("Liqonet", func() {
 		Entry("Getting first IP of 192.168.0.0/16", "192.168.0.0/16", "192.168.0.0", nil),
 	)
 
+	Describe("testing GetRemoteCIDRs function", func() {
+		var tep *netv1alpha1.TunnelEndpoint
+		BeforeEach(func() {
+			tep = &netv1alpha1.TunnelEndpoint{Spec: netv1alpha1.TunnelEndpointSpec{
+				RemotePodCIDR:         "10.0.0.0/16",
+				RemoteNATPodCIDR:      "10.1.0.0/16",
+				RemoteExternalCIDR:    "10.2.0.0/16",
+				RemoteNATExternalCIDR: "None",
+			}}
+		})
+
+		Context("when the TunnelEndpoint does not carry IPv6 networks", func() {
+			It("should return the IPv4 networks only", func() {
+				Expect(utils.GetRemoteCIDRs(tep)).To(Equal([]string{"10.1.0.0/16", "10.2.0.0/16"}))
+			})
+		})
+
+		Context("when the TunnelEndpoint carries IPv6 networks", func() {
+			It("should return the networks of both families", func() {
+				tep.Spec.LocalPodCIDRv6 = "fd00:0:0:1::/64"
+				tep.Spec.RemotePodCIDRv6 = "fd00:0:0:2::/64"
+				tep.Spec.RemoteExternalCIDRv6 = "fd00:0:0:3::/64"
+				tep.Spec.RemoteNATExternalCIDRv6 = "fd00:0:0:4::/64"
+				Expect(utils.GetRemoteCIDRs(tep)).To(Equal([]string{
+					"10.1.0.0/16", "10.2.0.0/16", "fd00:0:0:2::/64", "fd00:0:0:4::/64"}))
+			})
+		})
+	})
+
 	Describe("testing getOverlayIP function", func() {
 		Context("when input parameter is correct", func() {
 			It("should return a valid ip", func() {
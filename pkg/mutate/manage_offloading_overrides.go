@@ -0,0 +1,177 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mutate
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/component-helpers/scheduling/corev1/nodeaffinity"
+	"k8s.io/klog/v2"
+
+	offv1alpha1 "github.com/liqotech/liqo/apis/offloading/v1alpha1"
+	liqoconst "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/utils"
+)
+
+// errInvalidOffloadingOverride is returned when the offloading override of a pod is not valid,
+// or it exceeds the bounds of the NamespaceOffloading.
+var errInvalidOffloadingOverride = errors.New("invalid offloading override")
+
+// offloadingOverride is the offloading configuration overriding the one of the NamespaceOffloading for a given pod.
+type offloadingOverride struct {
+	// Source identifies where the override comes from (i.e., the pod annotations or an OffloadingPolicy).
+	Source string
+	// PodOffloadingStrategy overrides the PodOffloadingStrategy of the NamespaceOffloading, if not empty.
+	PodOffloadingStrategy offv1alpha1.PodOffloadingStrategyType
+	// ClusterSelector further restricts the ClusterSelector of the NamespaceOffloading, if not nil.
+	ClusterSelector *corev1.NodeSelector
+}
+
+// sortOffloadingPolicies sorts the given OffloadingPolicies by decreasing priority (and then by name).
+func sortOffloadingPolicies(policies []offv1alpha1.OffloadingPolicy) {
+	sort.SliceStable(policies, func(i, j int) bool {
+		if policies[i].Spec.Priority != policies[j].Spec.Priority {
+			return policies[i].Spec.Priority > policies[j].Spec.Priority
+		}
+		return policies[i].Name < policies[j].Name
+	})
+}
+
+// resolveOffloadingOverride returns the offloading override applying to the given pod, or nil if none does.
+// The settings specified through the pod annotations take precedence over the ones of the OffloadingPolicy
+// with the highest priority selecting the pod. The policies are expected to be sorted by sortOffloadingPolicies.
+func resolveOffloadingOverride(pod *corev1.Pod, policies []offv1alpha1.OffloadingPolicy) (*offloadingOverride, error) {
+	var override *offloadingOverride
+	for i := range policies {
+		selector, err := metav1.LabelSelectorAsSelector(&policies[i].Spec.PodSelector)
+		if err != nil {
+			klog.Errorf("OffloadingPolicy %q specifies an invalid pod selector: %v", policies[i].Name, err)
+			continue
+		}
+		if selector.Matches(labels.Set(pod.GetLabels())) {
+			override = &offloadingOverride{
+				Source:                fmt.Sprintf("OffloadingPolicy %q", policies[i].Name),
+				PodOffloadingStrategy: policies[i].Spec.PodOffloadingStrategy,
+				ClusterSelector:       policies[i].Spec.ClusterSelector,
+			}
+			break
+		}
+	}
+
+	strategy, strategyFound := pod.GetAnnotations()[liqoconst.PodOffloadingStrategyAnnotationKey]
+	clusterSelector, clusterSelectorFound := pod.GetAnnotations()[liqoconst.ClusterSelectorAnnotationKey]
+	if !strategyFound && !clusterSelectorFound {
+		return override, nil
+	}
+
+	if override == nil {
+		override = &offloadingOverride{}
+	}
+	override.Source = "pod annotations"
+	if strategyFound {
+		override.PodOffloadingStrategy = offv1alpha1.PodOffloadingStrategyType(strategy)
+	}
+	if clusterSelectorFound {
+		nodeSelector, err := nodeSelectorFromLabelSelector(clusterSelector)
+		if err != nil {
+			return nil, fmt.Errorf("%w: annotation %q: %v", errInvalidOffloadingOverride, liqoconst.ClusterSelectorAnnotationKey, err)
+		}
+		override.ClusterSelector = nodeSelector
+	}
+	return override, nil
+}
+
+// nodeSelectorFromLabelSelector converts a label selector (e.g., "region=eu,provider!=aws") into the equivalent NodeSelector.
+func nodeSelectorFromLabelSelector(selector string) (*corev1.NodeSelector, error) {
+	parsed, err := labels.Parse(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	requirements, _ := parsed.Requirements()
+	if len(requirements) == 0 {
+		return nil, errors.New("the selector must not be empty")
+	}
+
+	term := corev1.NodeSelectorTerm{}
+	for i := range requirements {
+		var operator corev1.NodeSelectorOperator
+		switch requirements[i].Operator() {
+		case selection.In, selection.Equals, selection.DoubleEquals:
+			operator = corev1.NodeSelectorOpIn
+		case selection.NotIn, selection.NotEquals:
+			operator = corev1.NodeSelectorOpNotIn
+		case selection.Exists:
+			operator = corev1.NodeSelectorOpExists
+		case selection.DoesNotExist:
+			operator = corev1.NodeSelectorOpDoesNotExist
+		case selection.GreaterThan:
+			operator = corev1.NodeSelectorOpGt
+		case selection.LessThan:
+			operator = corev1.NodeSelectorOpLt
+		default:
+			return nil, fmt.Errorf("unsupported operator %q", requirements[i].Operator())
+		}
+
+		requirement := corev1.NodeSelectorRequirement{Key: requirements[i].Key(), Operator: operator}
+		if values := requirements[i].Values(); values.Len() > 0 {
+			requirement.Values = values.List()
+		}
+		term.MatchExpressions = append(term.MatchExpressions, requirement)
+	}
+	return &corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{term}}, nil
+}
+
+// isStrategyWithinBounds checks whether the overriding strategy is at least as restrictive as the one of the namespace.
+func isStrategyWithinBounds(namespaceStrategy, overridingStrategy offv1alpha1.PodOffloadingStrategyType) bool {
+	switch overridingStrategy {
+	case offv1alpha1.LocalPodOffloadingStrategyType, offv1alpha1.RemotePodOffloadingStrategyType,
+		offv1alpha1.LocalAndRemotePodOffloadingStrategyType:
+		return overridingStrategy == namespaceStrategy || namespaceStrategy == offv1alpha1.LocalAndRemotePodOffloadingStrategyType
+	default:
+		return false
+	}
+}
+
+// applyOffloadingOverride validates the offloading override against the bounds of the NamespaceOffloading,
+// and returns a copy of the NamespaceOffloading reflecting the configuration to be enforced on the pod.
+func applyOffloadingOverride(nsoff *offv1alpha1.NamespaceOffloading,
+	override *offloadingOverride) (*offv1alpha1.NamespaceOffloading, error) {
+	effective := nsoff.DeepCopy()
+
+	if override.PodOffloadingStrategy != "" {
+		if !isStrategyWithinBounds(nsoff.Spec.PodOffloadingStrategy, override.PodOffloadingStrategy) {
+			return nil, fmt.Errorf("%w: %s: PodOffloadingStrategy %q is not allowed in a namespace offloaded with strategy %q",
+				errInvalidOffloadingOverride, override.Source, override.PodOffloadingStrategy, nsoff.Spec.PodOffloadingStrategy)
+		}
+		effective.Spec.PodOffloadingStrategy = override.PodOffloadingStrategy
+	}
+
+	if override.ClusterSelector != nil {
+		if _, err := nodeaffinity.NewNodeSelector(override.ClusterSelector); err != nil {
+			return nil, fmt.Errorf("%w: %s: invalid ClusterSelector: %v", errInvalidOffloadingOverride, override.Source, err)
+		}
+		// The overriding ClusterSelector is ANDed with the one of the NamespaceOffloading, hence it can only restrict it.
+		effective.Spec.ClusterSelector = utils.MergeNodeSelector(&nsoff.Spec.ClusterSelector, override.ClusterSelector)
+	}
+
+	return effective, nil
+}
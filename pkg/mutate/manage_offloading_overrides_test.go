@@ -0,0 +1,201 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mutate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	offv1alpha1 "github.com/liqotech/liqo/apis/offloading/v1alpha1"
+	liqoconst "github.com/liqotech/liqo/pkg/consts"
+	testutils "github.com/liqotech/liqo/pkg/mutate/testUtils"
+)
+
+var _ = Describe("Offloading overrides", func() {
+	var (
+		pod      corev1.Pod
+		policies []offv1alpha1.OffloadingPolicy
+	)
+
+	policy := func(name string, priority int32, app string, strategy offv1alpha1.PodOffloadingStrategyType) offv1alpha1.OffloadingPolicy {
+		return offv1alpha1.OffloadingPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test"},
+			Spec: offv1alpha1.OffloadingPolicySpec{
+				PodSelector:           metav1.LabelSelector{MatchLabels: map[string]string{"app": app}},
+				Priority:              priority,
+				PodOffloadingStrategy: strategy,
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		pod = corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "test", Labels: map[string]string{"app": "db"}}}
+		policies = []offv1alpha1.OffloadingPolicy{
+			policy("frontend", 0, "frontend", offv1alpha1.RemotePodOffloadingStrategyType),
+			policy("database", 0, "db", offv1alpha1.LocalPodOffloadingStrategyType),
+			policy("database-high", 10, "db", offv1alpha1.LocalAndRemotePodOffloadingStrategyType),
+		}
+		sortOffloadingPolicies(policies)
+	})
+
+	Context("Resolving the override applying to a pod", func() {
+		It("should select the matching policy with the highest priority", func() {
+			override, err := resolveOffloadingOverride(&pod, policies)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(override).ToNot(BeNil())
+			Expect(override.Source).To(ContainSubstring("database-high"))
+			Expect(override.PodOffloadingStrategy).To(Equal(offv1alpha1.LocalAndRemotePodOffloadingStrategyType))
+		})
+
+		It("should return nil if no policy matches the pod", func() {
+			pod.Labels = map[string]string{"app": "other"}
+			Expect(resolveOffloadingOverride(&pod, policies)).To(BeNil())
+		})
+
+		It("should give precedence to the pod annotations", func() {
+			pod.Annotations = map[string]string{
+				liqoconst.PodOffloadingStrategyAnnotationKey: string(offv1alpha1.RemotePodOffloadingStrategyType),
+				liqoconst.ClusterSelectorAnnotationKey:       "region in (eu),provider!=aws",
+			}
+			override, err := resolveOffloadingOverride(&pod, policies)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(override.PodOffloadingStrategy).To(Equal(offv1alpha1.RemotePodOffloadingStrategyType))
+			Expect(*override.ClusterSelector).To(Equal(corev1.NodeSelector{NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{
+					{Key: "provider", Operator: corev1.NodeSelectorOpNotIn, Values: []string{"aws"}},
+					{Key: "region", Operator: corev1.NodeSelectorOpIn, Values: []string{"eu"}},
+				},
+			}}}))
+		})
+
+		DescribeTable("should reject invalid cluster selector annotations",
+			func(selector string) {
+				pod.Annotations = map[string]string{liqoconst.ClusterSelectorAnnotationKey: selector}
+				_, err := resolveOffloadingOverride(&pod, policies)
+				Expect(errors.Is(err, errInvalidOffloadingOverride)).To(BeTrue())
+			},
+			Entry("empty selector", ""),
+			Entry("malformed selector", "region in eu"),
+		)
+	})
+
+	Context("Mutating a pod with an override", func() {
+		DescribeTable("should enforce the strategy only within the bounds of the namespace",
+			func(namespaceStrategy, podStrategy offv1alpha1.PodOffloadingStrategyType, allowed, tolerated bool) {
+				namespaceOffloading := testutils.GetNamespaceOffloading(namespaceStrategy)
+				override := &offloadingOverride{Source: "test", PodOffloadingStrategy: podStrategy}
+				err := mutatePod(&namespaceOffloading, override, nil, &pod)
+				if !allowed {
+					Expect(errors.Is(err, errInvalidOffloadingOverride)).To(BeTrue())
+					return
+				}
+				Expect(err).ToNot(HaveOccurred())
+				if tolerated {
+					Expect(pod.Spec.Tolerations).To(HaveLen(1))
+					Expect(*pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution).To(
+						Equal(testutils.GetImposedNodeSelector(podStrategy)))
+				} else {
+					Expect(pod.Spec.Tolerations).To(BeEmpty())
+					Expect(pod.Spec.Affinity).To(BeNil())
+				}
+			},
+			Entry("LocalAndRemote namespace, Local pod", offv1alpha1.LocalAndRemotePodOffloadingStrategyType,
+				offv1alpha1.LocalPodOffloadingStrategyType, true, false),
+			Entry("LocalAndRemote namespace, Remote pod", offv1alpha1.LocalAndRemotePodOffloadingStrategyType,
+				offv1alpha1.RemotePodOffloadingStrategyType, true, true),
+			Entry("Remote namespace, Remote pod", offv1alpha1.RemotePodOffloadingStrategyType,
+				offv1alpha1.RemotePodOffloadingStrategyType, true, true),
+			Entry("Remote namespace, Local pod", offv1alpha1.RemotePodOffloadingStrategyType,
+				offv1alpha1.LocalPodOffloadingStrategyType, false, false),
+			Entry("Remote namespace, LocalAndRemote pod", offv1alpha1.RemotePodOffloadingStrategyType,
+				offv1alpha1.LocalAndRemotePodOffloadingStrategyType, false, false),
+			Entry("LocalAndRemote namespace, unknown strategy", offv1alpha1.LocalAndRemotePodOffloadingStrategyType,
+				offv1alpha1.PodOffloadingStrategyType("Everywhere"), false, false),
+		)
+
+		It("should AND the cluster selector with the one of the namespace", func() {
+			namespaceOffloading := testutils.GetNamespaceOffloading(offv1alpha1.RemotePodOffloadingStrategyType)
+			restriction := corev1.NodeSelectorRequirement{Key: "provider", Operator: corev1.NodeSelectorOpIn, Values: []string{"gke"}}
+			override := &offloadingOverride{Source: "test", ClusterSelector: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{MatchExpressions: []corev1.NodeSelectorRequirement{restriction}}},
+			}}
+
+			Expect(mutatePod(&namespaceOffloading, override, nil, &pod)).To(Succeed())
+			terms := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+			Expect(terms).To(HaveLen(len(namespaceOffloading.Spec.ClusterSelector.NodeSelectorTerms)))
+			for i := range terms {
+				Expect(terms[i].MatchExpressions).To(ContainElement(restriction))
+			}
+			// The NamespaceOffloading must not be modified.
+			Expect(namespaceOffloading.Spec.ClusterSelector).To(Equal(testutils.GetImposedNodeSelector("")))
+		})
+	})
+
+	Context("Handling an admission request", func() {
+		review := func(s *MutationServer) *admissionv1beta1.AdmissionResponse {
+			raw, err := json.Marshal(pod)
+			Expect(err).ToNot(HaveOccurred())
+			body, err := json.Marshal(admissionv1beta1.AdmissionReview{
+				Request: &admissionv1beta1.AdmissionRequest{
+					UID: "uid", Namespace: pod.Namespace, Name: pod.Name, Object: runtime.RawExtension{Raw: raw},
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			output, err := s.Mutate(body)
+			Expect(err).ToNot(HaveOccurred())
+			var result admissionv1beta1.AdmissionReview
+			Expect(json.Unmarshal(output, &result)).To(Succeed())
+			Expect(result.Response).ToNot(BeNil())
+			return result.Response
+		}
+
+		var s *MutationServer
+
+		BeforeEach(func() {
+			scheme := runtime.NewScheme()
+			Expect(offv1alpha1.AddToScheme(scheme)).To(Succeed())
+			namespaceOffloading := testutils.GetNamespaceOffloading(offv1alpha1.RemotePodOffloadingStrategyType)
+			s = &MutationServer{ctx: context.Background(), webhookClient: fake.NewClientBuilder().WithScheme(scheme).
+				WithObjects(&namespaceOffloading, &policies[1], &policies[2]).Build()}
+		})
+
+		It("should mutate the pods whose override is within the bounds of the namespace", func() {
+			pod.Labels = map[string]string{"app": "frontend"}
+			response := review(s)
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Patch).ToNot(BeEmpty())
+		})
+
+		It("should reject the pods whose override exceeds the bounds of the namespace", func() {
+			response := review(s)
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.Patch).To(BeEmpty())
+			Expect(response.Result.Code).To(BeNumerically("==", http.StatusUnprocessableEntity))
+			Expect(response.Result.Message).To(ContainSubstring(`OffloadingPolicy "database"`))
+		})
+	})
+})
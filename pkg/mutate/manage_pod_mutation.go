@@ -15,6 +15,7 @@
 package mutate
 
 import (
+	"errors"
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
@@ -51,8 +52,24 @@ func createTolerationFromNamespaceOffloading(strategy offv1alpha1.PodOffloadingS
 }
 
 // createNodeSelectorFromNamespaceOffloading creates the right NodeSelector according to the PodOffloadingStrategy chosen.
-func createNodeSelectorFromNamespaceOffloading(nsoff *offv1alpha1.NamespaceOffloading) (corev1.NodeSelector, error) {
-	nodeSelector := nsoff.Spec.ClusterSelector
+// In case the number of clusters is capped, the remote terms are restricted to the clusters hosting the remote namespace.
+func createNodeSelectorFromNamespaceOffloading(nsoff *offv1alpha1.NamespaceOffloading,
+	offloadedClusters []string) (corev1.NodeSelector, error) {
+	nodeSelector := *nsoff.Spec.ClusterSelector.DeepCopy()
+	// When the number of clusters is capped, only a subset of the eligible ones hosts the remote namespace,
+	// hence the pod must not be scheduled on the others.
+	if nsoff.Spec.MaxClusters != nil {
+		if len(offloadedClusters) == 0 {
+			// No cluster hosts the remote namespace yet, hence the pod cannot be offloaded: it is rejected if it cannot
+			// run locally either, while otherwise the remote terms are dropped, and it is restricted to the local nodes.
+			if nsoff.Spec.PodOffloadingStrategy == offv1alpha1.RemotePodOffloadingStrategyType {
+				return corev1.NodeSelector{}, fmt.Errorf("%w: namespace %q", errNamespaceNotOffloaded, nsoff.Namespace)
+			}
+			nodeSelector.NodeSelectorTerms = nil
+		} else {
+			restrictToOffloadedClusters(&nodeSelector, offloadedClusters)
+		}
+	}
 	switch {
 	case nsoff.Spec.PodOffloadingStrategy == offv1alpha1.RemotePodOffloadingStrategyType:
 		// To ensure that the pod is not scheduled on local nodes is necessary to add to every NodeSelectorTerm a
@@ -84,6 +101,41 @@ func createNodeSelectorFromNamespaceOffloading(nsoff *offv1alpha1.NamespaceOfflo
 	return nodeSelector, nil
 }
 
+// errNamespaceNotOffloaded is returned when a pod shall be offloaded, but its namespace has not yet been offloaded to any cluster.
+var errNamespaceNotOffloaded = errors.New("namespace not yet offloaded")
+
+// restrictToOffloadedClusters adds to every NodeSelectorTerm a new NodeSelectorRequirement, which allows only the
+// virtual nodes associated with the clusters hosting the remote namespace.
+func restrictToOffloadedClusters(nodeSelector *corev1.NodeSelector, offloadedClusters []string) {
+	for i := range nodeSelector.NodeSelectorTerms {
+		nodeSelector.NodeSelectorTerms[i].MatchExpressions = append(nodeSelector.NodeSelectorTerms[i].MatchExpressions,
+			corev1.NodeSelectorRequirement{
+				Key:      liqoconst.RemoteClusterID,
+				Operator: corev1.NodeSelectorOpIn,
+				Values:   offloadedClusters,
+			})
+	}
+}
+
+// fillPodWithThePreferredClusters appends the preferences specified in the NamespaceOffloading to the ones possibly
+// already present in the Pod Affinity.
+func fillPodWithThePreferredClusters(preferences []corev1.PreferredSchedulingTerm, pod *corev1.Pod) {
+	if len(preferences) == 0 {
+		return
+	}
+
+	if pod.Spec.Affinity == nil {
+		pod.Spec.Affinity = &corev1.Affinity{}
+	}
+	if pod.Spec.Affinity.NodeAffinity == nil {
+		pod.Spec.Affinity.NodeAffinity = &corev1.NodeAffinity{}
+	}
+	for i := range preferences {
+		pod.Spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+			pod.Spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution, *preferences[i].DeepCopy())
+	}
+}
+
 // fillPodWithTheNewNodeSelector gets the previously computed NodeSelector imposed by the PodOffloadingStrategy and
 // merges it with the Pod NodeSelector if it is already present. It simply adds it to the Pod if previously unset.
 func fillPodWithTheNewNodeSelector(imposedNodeSelector *corev1.NodeSelector, pod *corev1.Pod) {
@@ -115,7 +167,21 @@ func fillPodWithTheNewNodeSelector(imposedNodeSelector *corev1.NodeSelector, pod
 // chosen in the CR. Two possible modifications:
 // - The VirtualNodeToleration is added to the Pod Toleration if necessary.
 // - The old Pod NodeSelector is substituted with a new one according to the PodOffloadingStrategyType.
-func mutatePod(namespaceOffloading *offv1alpha1.NamespaceOffloading, pod *corev1.Pod) error {
+// Additionally, the cluster preferences are appended to the preferred node affinity of the Pod.
+// If not nil, the override (originating from the pod annotations or an OffloadingPolicy) is validated against the
+// bounds of the NamespaceOffloading and it replaces the PodOffloadingStrategy and restricts the ClusterSelector.
+func mutatePod(namespaceOffloading *offv1alpha1.NamespaceOffloading, override *offloadingOverride,
+	offloadedClusters []string, pod *corev1.Pod) error {
+	if override != nil {
+		effective, err := applyOffloadingOverride(namespaceOffloading, override)
+		if err != nil {
+			klog.Error(err)
+			return err
+		}
+		klog.V(4).Infof("Offloading configuration of pod '%s/%s' overridden by %s", pod.Namespace, pod.Name, override.Source)
+		namespaceOffloading = effective
+	}
+
 	// The NamespaceOffloading CR contains information about the PodOffloadingStrategy and
 	// the NodeSelector inserted by the user (ClusterSelector field).
 	klog.V(5).Infof("Chosen strategy: %s", namespaceOffloading.Spec.PodOffloadingStrategy)
@@ -135,10 +201,10 @@ func mutatePod(namespaceOffloading *offv1alpha1.NamespaceOffloading, pod *corev1
 	klog.V(5).Infof("Generated Toleration: %s", toleration)
 
 	// Create the right NodeSelector according to the PodOffloadingStrategy case.
-	imposedNodeSelector, err := createNodeSelectorFromNamespaceOffloading(namespaceOffloading)
+	imposedNodeSelector, err := createNodeSelectorFromNamespaceOffloading(namespaceOffloading, offloadedClusters)
 	if err != nil {
-		klog.Errorf("The NamespaceOffloading in namespace '%s' has unknown strategy '%s'",
-			namespaceOffloading.Namespace, namespaceOffloading.Spec.PodOffloadingStrategy)
+		klog.Errorf("Unable to create the NodeSelector for the NamespaceOffloading in namespace '%s': %v",
+			namespaceOffloading.Namespace, err)
 		return err
 	}
 	klog.V(5).Infof("ImposedNodeSelector: %s", imposedNodeSelector)
@@ -149,5 +215,8 @@ func mutatePod(namespaceOffloading *offv1alpha1.NamespaceOffloading, pod *corev1
 	// Enforce the new NodeSelector policy imposed by the NamespaceOffloading creator.
 	fillPodWithTheNewNodeSelector(&imposedNodeSelector, pod)
 	klog.V(5).Infof("Pod NodeSelector: %s", imposedNodeSelector)
+
+	// Enforce the cluster preferences expressed by the NamespaceOffloading creator.
+	fillPodWithThePreferredClusters(namespaceOffloading.Spec.PreferredClusterSelector, pod)
 	return nil
 }
@@ -0,0 +1,145 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mutate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"gomodules.xyz/jsonpatch/v2"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
+	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
+	"github.com/liqotech/liqo/pkg/utils/podsecurity"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+)
+
+// RewrittenAuditAnnotation is the key of the audit annotation listing the pod security violations rewritten in a ShadowPod.
+const RewrittenAuditAnnotation = "rewritten"
+
+func (s *MutationServer) handleShadowPodMutate(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		klog.Error(err)
+		s.sendError(fmt.Errorf("unable to correctly read the body of the request"), w)
+		return
+	}
+
+	mutated, err := s.MutateShadowPod(body)
+	if err != nil {
+		klog.Error(err)
+		s.sendError(fmt.Errorf("unable to correctly mutate the request"), w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(mutated)
+}
+
+// MutateShadowPod rewrites the ShadowPod received via the admission review, to make it comply with the pod security
+// constraints enforced for its origin cluster, in case they are configured with the Rewrite action.
+// The ShadowPods violating constraints configured with the Deny action are rejected by the validating webhook,
+// while the ones created by a remote cluster and specifying a different origin cluster are directly rejected.
+func (s *MutationServer) MutateShadowPod(body []byte) ([]byte, error) {
+	review := admissionv1.AdmissionReview{}
+	if err := json.Unmarshal(body, &review); err != nil {
+		return nil, fmt.Errorf("unmarshaling request failed with %w", err)
+	}
+
+	if review.Request == nil {
+		return nil, fmt.Errorf("received admissionReview with empty request")
+	}
+
+	var shadowPod vkv1alpha1.ShadowPod
+	if err := json.Unmarshal(review.Request.Object.Raw, &shadowPod); err != nil {
+		return nil, fmt.Errorf("unable unmarshal shadowpod json object %w", err)
+	}
+
+	review.Response = &admissionv1.AdmissionResponse{
+		UID:     review.Request.UID,
+		Allowed: true,
+		Result:  &metav1.Status{Status: metav1.StatusSuccess},
+	}
+
+	// ShadowPods not specifying their origin cluster are rejected by the validating webhook.
+	clusterID := shadowPod.Labels[forge.LiqoOriginClusterIDKey]
+	if clusterID == "" {
+		return json.Marshal(review)
+	}
+
+	// The origin cluster label is not trusted in case the requester is a remote cluster, since it could be spoofed
+	// to be subject to the (looser) constraints enforced for another cluster.
+	if _, err := podsecurity.OriginClusterID(s.ctx, s.webhookClient, &review.Request.UserInfo, clusterID); err != nil {
+		if !errors.Is(err, podsecurity.ErrOriginClusterMismatch) {
+			return nil, err
+		}
+
+		klog.Warningf("Rejected ShadowPod %s/%s: %v", review.Request.Namespace, review.Request.Name, err)
+		review.Response.Allowed = false
+		review.Response.Result = &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusForbidden,
+			Reason:  metav1.StatusReasonForbidden,
+			Message: fmt.Sprintf("ShadowPod %s/%s rejected: %v", review.Request.Namespace, review.Request.Name, err),
+		}
+		return json.Marshal(review)
+	}
+
+	constraints, err := podsecurity.ConstraintsFor(s.ctx, s.webhookClient, clusterID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the pod security constraints of cluster %q: %w", clusterID, err)
+	}
+	if constraints == nil || constraints.Action != sharingv1alpha1.PodSecurityActionRewrite {
+		return json.Marshal(review)
+	}
+
+	original, err := json.Marshal(shadowPod)
+	if err != nil {
+		return nil, err
+	}
+
+	rewritten := podsecurity.Rewrite(&shadowPod.Spec.Pod, constraints)
+	if len(rewritten) == 0 {
+		return json.Marshal(review)
+	}
+
+	target, err := json.Marshal(shadowPod)
+	if err != nil {
+		return nil, err
+	}
+
+	ops, err := jsonpatch.CreatePatch(original, target)
+	if err != nil {
+		return nil, err
+	}
+
+	patchType := admissionv1.PatchTypeJSONPatch
+	review.Response.PatchType = &patchType
+	if review.Response.Patch, err = json.Marshal(ops); err != nil {
+		return nil, err
+	}
+	review.Response.AuditAnnotations = map[string]string{RewrittenAuditAnnotation: strings.Join(rewritten, ", ")}
+
+	klog.Infof("ShadowPod %s/%s of cluster %q rewritten to comply with the pod security constraints: %s",
+		review.Request.Namespace, review.Request.Name, clusterID, strings.Join(rewritten, ", "))
+	return json.Marshal(review)
+}
@@ -0,0 +1,138 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mutate
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gomodules.xyz/jsonpatch/v2"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
+	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
+	"github.com/liqotech/liqo/pkg/discovery"
+	tenantnamespace "github.com/liqotech/liqo/pkg/tenantNamespace"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+)
+
+var _ = Describe("ShadowPod mutation", func() {
+	const clusterID = "remote-cluster-id"
+
+	var (
+		action    sharingv1alpha1.PodSecurityAction
+		shadowPod vkv1alpha1.ShadowPod
+		userInfo  authenticationv1.UserInfo
+		response  *admissionv1.AdmissionResponse
+	)
+
+	BeforeEach(func() {
+		action = sharingv1alpha1.PodSecurityActionRewrite
+		userInfo = authenticationv1.UserInfo{}
+		shadowPod = vkv1alpha1.ShadowPod{
+			ObjectMeta: metav1.ObjectMeta{Name: "name", Namespace: "foo", Labels: map[string]string{forge.LiqoOriginClusterIDKey: clusterID}},
+			Spec: vkv1alpha1.ShadowPodSpec{Pod: corev1.PodSpec{
+				HostNetwork: true,
+				Containers:  []corev1.Container{{Name: "container", Image: "nginx"}},
+			}},
+		}
+	})
+
+	JustBeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(sharingv1alpha1.AddToScheme(scheme)).To(Succeed())
+		policy := &sharingv1alpha1.SharingPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+			Spec: sharingv1alpha1.SharingPolicySpec{
+				ClusterIDs:  []string{clusterID},
+				PodSecurity: &sharingv1alpha1.PodSecurityConstraints{Action: action, AllowRunAsRoot: true},
+			},
+		}
+
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant", Labels: map[string]string{
+			discovery.ClusterIDLabel: clusterID, discovery.TenantNamespaceLabel: "true"}}}
+		cl := fake.NewClientBuilder().WithScheme(scheme).WithObjects(policy, namespace).Build()
+		s := &MutationServer{ctx: context.Background(), webhookClient: cl}
+
+		raw, err := json.Marshal(shadowPod)
+		Expect(err).ToNot(HaveOccurred())
+		body, err := json.Marshal(admissionv1.AdmissionReview{
+			TypeMeta: metav1.TypeMeta{APIVersion: admissionv1.SchemeGroupVersion.String(), Kind: "AdmissionReview"},
+			Request: &admissionv1.AdmissionRequest{
+				UID: "uid", Operation: admissionv1.Create, Namespace: shadowPod.Namespace, Name: shadowPod.Name,
+				Object: runtime.RawExtension{Raw: raw}, UserInfo: userInfo,
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		output, err := s.MutateShadowPod(body)
+		Expect(err).ToNot(HaveOccurred())
+
+		var review admissionv1.AdmissionReview
+		Expect(json.Unmarshal(output, &review)).To(Succeed())
+		response = review.Response
+		Expect(response).ToNot(BeNil())
+	})
+
+	When("the constraints are configured with the Rewrite action", func() {
+		It("should patch the offending fields", func() {
+			Expect(response.Allowed).To(BeTrue())
+			var ops []jsonpatch.Operation
+			Expect(json.Unmarshal(response.Patch, &ops)).To(Succeed())
+			Expect(ops).To(ConsistOf(jsonpatch.Operation{Operation: "remove", Path: "/spec/pod/hostNetwork"}))
+			Expect(response.AuditAnnotations).To(HaveKeyWithValue(RewrittenAuditAnnotation, "host network namespace shared"))
+		})
+	})
+
+	When("the constraints are configured with the Deny action", func() {
+		BeforeEach(func() { action = sharingv1alpha1.PodSecurityActionDeny })
+
+		It("should not patch the ShadowPod", func() {
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Patch).To(BeEmpty())
+		})
+	})
+
+	When("the ShadowPod is created by the remote cluster it originates from", func() {
+		BeforeEach(func() {
+			userInfo = authenticationv1.UserInfo{Username: "system:serviceaccount:tenant:" + tenantnamespace.RemoteIdentityServiceAccountName}
+		})
+
+		It("should patch the offending fields", func() {
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.Patch).ToNot(BeEmpty())
+		})
+	})
+
+	When("the ShadowPod is created by a remote cluster specifying a different origin cluster", func() {
+		BeforeEach(func() {
+			userInfo = authenticationv1.UserInfo{Username: "other-cluster-id", Groups: []string{tenantnamespace.RemoteClusterGroup}}
+		})
+
+		It("should reject the ShadowPod", func() {
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.Result.Message).To(ContainSubstring("other-cluster-id"))
+			Expect(response.Patch).To(BeEmpty())
+		})
+	})
+})
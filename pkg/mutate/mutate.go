@@ -16,22 +16,37 @@ package mutate
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"sort"
 
 	"gomodules.xyz/jsonpatch/v2"
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	offv1alpha1 "github.com/liqotech/liqo/apis/offloading/v1alpha1"
+	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
+	"github.com/liqotech/liqo/internal/crdReplicator/reflection"
 	liqoconst "github.com/liqotech/liqo/pkg/consts"
 )
 
 // cluster-role
 // +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations,verbs=get;list;update;patch
+// +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations,verbs=get;list;update;patch
 // +kubebuilder:rbac:groups=offloading.liqo.io,resources=namespaceoffloadings,verbs=get;list;watch
+// +kubebuilder:rbac:groups=offloading.liqo.io,resources=offloadingpolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=virtualkubelet.liqo.io,resources=shadowpods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=virtualkubelet.liqo.io,resources=namespacemaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups=sharing.liqo.io,resources=resourceoffers,verbs=get;list;watch
+// +kubebuilder:rbac:groups=sharing.liqo.io,resources=sharingpolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=discovery.liqo.io,resources=foreignclusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
 // role
 // +kubebuilder:rbac:groups=core,namespace="do-not-care",resources=secrets,verbs=create;get;list;watch
 
@@ -88,7 +103,40 @@ func (s *MutationServer) Mutate(body []byte) ([]byte, error) {
 	}
 
 	klog.V(5).Infof("The namespace '%s' has a NamespaceOffloading resource", pod.Namespace)
-	if err = mutatePod(namespaceOffloading, pod); err != nil {
+	var offloadedClusters []string
+	if namespaceOffloading.Spec.MaxClusters != nil {
+		if offloadedClusters, err = s.getOffloadedClusters(admissionReviewRequest.Namespace); err != nil {
+			return nil, err
+		}
+	}
+
+	policies := &offv1alpha1.OffloadingPolicyList{}
+	if err = s.webhookClient.List(s.ctx, policies, client.InNamespace(admissionReviewRequest.Namespace)); err != nil {
+		return nil, fmt.Errorf("%w -> unable to list the OffloadingPolicies for the Namespace: %s", err, pod.Namespace)
+	}
+	sortOffloadingPolicies(policies.Items)
+
+	override, err := resolveOffloadingOverride(pod, policies.Items)
+	if err == nil {
+		err = mutatePod(namespaceOffloading, override, offloadedClusters, pod)
+	}
+	if errors.Is(err, errInvalidOffloadingOverride) || errors.Is(err, errNamespaceNotOffloaded) {
+		// The pod is rejected, as it attempts to escape the bounds of the NamespaceOffloading,
+		// or it shall be offloaded but its namespace has not yet been offloaded to any cluster.
+		resp.Allowed = false
+		resp.PatchType = nil
+		resp.AuditAnnotations = nil
+		resp.Result = &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusUnprocessableEntity,
+			Reason:  metav1.StatusReasonInvalid,
+			Message: err.Error(),
+		}
+		admReview.Response = &resp
+		klog.Warningf("Namespace: %s  Pod name: %s  -> rejected: %v", admissionReviewRequest.Namespace, admissionReviewRequest.Name, err)
+		return json.Marshal(admReview)
+	}
+	if err != nil {
 		return nil, err
 	}
 
@@ -128,3 +176,25 @@ func (s *MutationServer) Mutate(body []byte) ([]byte, error) {
 
 	return responseBody, nil
 }
+
+// getOffloadedClusters returns the IDs of the clusters the given namespace is currently offloaded to,
+// according to the DesiredMapping field of the local NamespaceMaps.
+func (s *MutationServer) getOffloadedClusters(namespace string) ([]string, error) {
+	metals := reflection.LocalResourcesLabelSelector()
+	selector, err := metav1.LabelSelectorAsSelector(&metals)
+	utilruntime.Must(err)
+
+	nms := &vkv1alpha1.NamespaceMapList{}
+	if err := s.webhookClient.List(s.ctx, nms, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, fmt.Errorf("%w -> unable to list the NamespaceMaps", err)
+	}
+
+	clusterIDs := []string{}
+	for i := range nms.Items {
+		if _, found := nms.Items[i].Spec.DesiredMapping[namespace]; found {
+			clusterIDs = append(clusterIDs, nms.Items[i].Labels[liqoconst.RemoteClusterID])
+		}
+	}
+	sort.Strings(clusterIDs)
+	return clusterIDs, nil
+}
@@ -22,26 +22,34 @@ import (
 	"net/http"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
 	offv1alpha1 "github.com/liqotech/liqo/apis/offloading/v1alpha1"
+	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
+	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
+	"github.com/liqotech/liqo/pkg/shadowpodvalidator"
 	cachedclient "github.com/liqotech/liqo/pkg/utils/cachedClient"
 )
 
 type MutationConfig struct {
 	CertFile string
 	KeyFile  string
+	// ShadowPodQuotaResyncPeriod is the period after which the resources tracked by the ShadowPod validator are resynchronized.
+	ShadowPodQuotaResyncPeriod time.Duration
 }
 
 type MutationServer struct {
 	mux    *http.ServeMux
 	server *http.Server
 
-	webhookClient client.Client
-	config        *MutationConfig
-	ctx           context.Context
+	webhookClient      client.Client
+	shadowPodValidator *shadowpodvalidator.Validator
+	config             *MutationConfig
+	ctx                context.Context
 }
 
 // NewMutationServer creates a new mutation server.
@@ -52,7 +60,11 @@ func NewMutationServer(ctx context.Context, c *MutationConfig) (*MutationServer,
 
 	// This scheme is necessary for the WebhookClient.
 	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = discoveryv1alpha1.AddToScheme(scheme)
 	_ = offv1alpha1.AddToScheme(scheme)
+	_ = sharingv1alpha1.AddToScheme(scheme)
+	_ = vkv1alpha1.AddToScheme(scheme)
 
 	var err error
 	if s.webhookClient, err = cachedclient.GetCachedClient(ctx, scheme); err != nil {
@@ -61,6 +73,11 @@ func NewMutationServer(ctx context.Context, c *MutationConfig) (*MutationServer,
 
 	s.mux = http.NewServeMux()
 	s.mux.HandleFunc("/mutate", s.handleMutate)
+	s.mux.HandleFunc("/mutate/shadowpods", s.handleShadowPodMutate)
+
+	s.shadowPodValidator = shadowpodvalidator.NewValidator(s.webhookClient)
+	s.mux.Handle("/validate/shadowpods", s.shadowPodValidator)
+	go s.shadowPodValidator.Resync(ctx, c.ShadowPodQuotaResyncPeriod)
 
 	s.server = &http.Server{
 		Addr:           ":8443",
@@ -23,6 +23,7 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
 
 	offv1alpha1 "github.com/liqotech/liqo/apis/offloading/v1alpha1"
 	liqoconst "github.com/liqotech/liqo/pkg/consts"
@@ -78,7 +79,7 @@ var _ = Describe("Webhook", func() {
 		DescribeTable("3 Different type of PodOffloadingStrategy",
 			func(namespaceOffloading offv1alpha1.NamespaceOffloading, expectedNodeSelector corev1.NodeSelector) {
 				By(fmt.Sprintf("Testing %s", namespaceOffloading.Spec.PodOffloadingStrategy))
-				nodeSelector, err := createNodeSelectorFromNamespaceOffloading(&namespaceOffloading)
+				nodeSelector, err := createNodeSelectorFromNamespaceOffloading(&namespaceOffloading, nil)
 				if namespaceOffloading.Spec.PodOffloadingStrategy == offv1alpha1.LocalPodOffloadingStrategyType {
 					Expect(err != nil).Should(BeTrue())
 				}
@@ -253,7 +254,7 @@ var _ = Describe("Webhook", func() {
 		It("Check the toleration added and the new NodeSelector", func() {
 			namespaceOffloading := testutils.GetNamespaceOffloading(offv1alpha1.LocalAndRemotePodOffloadingStrategyType)
 			podTest := pod.DeepCopy()
-			err := mutatePod(&namespaceOffloading, podTest)
+			err := mutatePod(&namespaceOffloading, nil, nil, podTest)
 			Expect(err == nil).To(BeTrue())
 			Expect(len(podTest.Spec.Tolerations) == 2).To(BeTrue())
 			Expect(podTest.Spec.Tolerations[1].MatchToleration(&virtualNodeToleration)).To(BeTrue())
@@ -265,10 +266,94 @@ var _ = Describe("Webhook", func() {
 			namespaceOffloading := testutils.GetNamespaceOffloading(offv1alpha1.LocalPodOffloadingStrategyType)
 			podTest := pod.DeepCopy()
 			oldPodNodeSelector := *podTest.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution
-			err := mutatePod(&namespaceOffloading, podTest)
+			err := mutatePod(&namespaceOffloading, nil, nil, podTest)
 			Expect(err == nil).To(BeTrue())
 			Expect(len(podTest.Spec.Tolerations) == 1).To(BeTrue())
 			Expect(*podTest.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution).To(Equal(oldPodNodeSelector))
 		})
 	})
+
+	Context("6 - Call the mutatePod function with cluster preferences and a cap on the number of clusters", func() {
+
+		preference := corev1.PreferredSchedulingTerm{
+			Weight: 10,
+			Preference: corev1.NodeSelectorTerm{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{
+					Key:      "provider",
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{"A"},
+				}},
+			},
+		}
+		userPreference := corev1.PreferredSchedulingTerm{
+			Weight: 1,
+			Preference: corev1.NodeSelectorTerm{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{
+					Key:      "storage",
+					Operator: corev1.NodeSelectorOpExists,
+				}},
+			},
+		}
+
+		var (
+			namespaceOffloading offv1alpha1.NamespaceOffloading
+			pod                 corev1.Pod
+		)
+
+		BeforeEach(func() {
+			namespaceOffloading = testutils.GetNamespaceOffloading(offv1alpha1.RemotePodOffloadingStrategyType)
+			namespaceOffloading.Spec.PreferredClusterSelector = []corev1.PreferredSchedulingTerm{preference}
+			pod = corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "test"}}
+		})
+
+		It("Check the preferences are appended to the ones of the pod", func() {
+			pod.Spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+				PreferredDuringSchedulingIgnoredDuringExecution: []corev1.PreferredSchedulingTerm{userPreference},
+			}}
+			Expect(mutatePod(&namespaceOffloading, nil, nil, &pod)).To(Succeed())
+			Expect(pod.Spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(
+				Equal([]corev1.PreferredSchedulingTerm{userPreference, preference}))
+			Expect(*pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution).To(
+				Equal(testutils.GetImposedNodeSelector(offv1alpha1.RemotePodOffloadingStrategyType)))
+		})
+
+		It("Check the pod is restricted to the clusters hosting the remote namespace", func() {
+			namespaceOffloading.Spec.MaxClusters = pointer.Int32(1)
+			Expect(mutatePod(&namespaceOffloading, nil, []string{"cluster-b"}, &pod)).To(Succeed())
+			Expect(pod.Spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution).To(
+				Equal([]corev1.PreferredSchedulingTerm{preference}))
+
+			terms := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+			Expect(terms).To(HaveLen(2))
+			for i := range terms {
+				Expect(terms[i].MatchExpressions).To(ContainElement(corev1.NodeSelectorRequirement{
+					Key:      liqoconst.RemoteClusterID,
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{"cluster-b"},
+				}))
+			}
+			// The NamespaceOffloading must not be modified.
+			Expect(namespaceOffloading.Spec.ClusterSelector).To(Equal(testutils.GetImposedNodeSelector("")))
+		})
+
+		It("Check the pod is rejected if the remote namespace is not hosted by any cluster", func() {
+			namespaceOffloading.Spec.MaxClusters = pointer.Int32(1)
+			Expect(mutatePod(&namespaceOffloading, nil, []string{}, &pod)).To(MatchError(errNamespaceNotOffloaded))
+		})
+
+		It("Check the pod is restricted to the local nodes if the remote namespace is not hosted by any cluster", func() {
+			namespaceOffloading = testutils.GetNamespaceOffloading(offv1alpha1.LocalAndRemotePodOffloadingStrategyType)
+			namespaceOffloading.Spec.MaxClusters = pointer.Int32(1)
+			Expect(mutatePod(&namespaceOffloading, nil, []string{}, &pod)).To(Succeed())
+
+			terms := pod.Spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+			Expect(terms).To(ConsistOf(corev1.NodeSelectorTerm{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{
+					Key:      liqoconst.TypeLabel,
+					Operator: corev1.NodeSelectorOpNotIn,
+					Values:   []string{liqoconst.TypeNode},
+				}},
+			}))
+		})
+	})
 })
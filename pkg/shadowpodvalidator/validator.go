@@ -0,0 +1,409 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shadowpodvalidator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	resourcehelper "k8s.io/kubectl/pkg/util/resource"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
+	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/discovery"
+	"github.com/liqotech/liqo/pkg/utils/podsecurity"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+)
+
+// UsageAuditAnnotation is the key of the audit annotation reporting the resources used by the consumer cluster,
+// including the ones requested by the admitted ShadowPod.
+const UsageAuditAnnotation = "usage"
+
+const statusSubresource = "status"
+
+// Validator validates the ShadowPods created by the consumer clusters, ensuring that each of them does not exceed
+// the quota granted through the corresponding ResourceOffer.
+type Validator struct {
+	client client.Client
+
+	mutex sync.Mutex
+	// usage tracks, for each consumer cluster, the resources requested by each of its ShadowPods.
+	usage map[string]map[types.NamespacedName]corev1.ResourceList
+}
+
+// NewValidator returns a new ShadowPod Validator.
+func NewValidator(cl client.Client) *Validator {
+	return &Validator{
+		client: cl,
+		usage:  map[string]map[types.NamespacedName]corev1.ResourceList{},
+	}
+}
+
+// Resync periodically discards the tracked usage, which is then rebuilt from the existing ShadowPods.
+// This compensates for the operations which failed after being admitted. It blocks until the context is canceled.
+func (v *Validator) Resync(ctx context.Context, period time.Duration) {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		v.mutex.Lock()
+		defer v.mutex.Unlock()
+		v.usage = map[string]map[types.NamespacedName]corev1.ResourceList{}
+	}, period)
+}
+
+// Usage returns the resources currently requested by the ShadowPods of the given consumer cluster.
+func (v *Validator) Usage(ctx context.Context, clusterID string) (corev1.ResourceList, error) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	usage, err := v.clusterUsage(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	return sumResources(usage, types.NamespacedName{}), nil
+}
+
+// ServeHTTP handles the admission review requests concerning ShadowPods.
+func (v *Validator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		klog.Errorf("Failed to read the body of the request: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	response, err := v.Validate(r.Context(), body)
+	if err != nil {
+		klog.Errorf("Failed to validate the request: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, _ = fmt.Fprintf(w, "%s", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(response)
+}
+
+// Validate validates the ShadowPod embedded in the given admission review, and returns the serialized review
+// including the corresponding response.
+func (v *Validator) Validate(ctx context.Context, body []byte) ([]byte, error) {
+	review := admissionv1.AdmissionReview{}
+	if err := json.Unmarshal(body, &review); err != nil {
+		return nil, fmt.Errorf("unmarshaling request failed with %w", err)
+	}
+
+	if review.Request == nil {
+		return nil, fmt.Errorf("received admissionReview with empty request")
+	}
+
+	review.Response = v.validate(ctx, review.Request)
+	review.Response.UID = review.Request.UID
+	return json.Marshal(review)
+}
+
+// validate performs the actual validation of the admission request.
+// Dry-run requests are validated as usual, but they do not alter the tracked usage.
+func (v *Validator) validate(ctx context.Context, request *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	key := types.NamespacedName{Namespace: request.Namespace, Name: request.Name}
+	dryRun := request.DryRun != nil && *request.DryRun
+
+	switch request.Operation {
+	case admissionv1.Delete:
+		var shadowPod vkv1alpha1.ShadowPod
+		if err := json.Unmarshal(request.OldObject.Raw, &shadowPod); err != nil {
+			return errored(fmt.Errorf("failed to decode ShadowPod %q: %w", key, err))
+		}
+		if !dryRun {
+			v.forget(shadowPod.Labels[forge.LiqoOriginClusterIDKey], key)
+		}
+		return allowed(nil)
+	case admissionv1.Create, admissionv1.Update:
+	default:
+		return allowed(nil)
+	}
+
+	var shadowPod vkv1alpha1.ShadowPod
+	if err := json.Unmarshal(request.Object.Raw, &shadowPod); err != nil {
+		return errored(fmt.Errorf("failed to decode ShadowPod %q: %w", key, err))
+	}
+
+	// The status is updated by the local controller only, hence it is just checked to release the resources
+	// requested by the ShadowPods which terminated.
+	if request.SubResource == statusSubresource {
+		if terminated(&shadowPod) && !dryRun {
+			v.forget(shadowPod.Labels[forge.LiqoOriginClusterIDKey], key)
+		}
+		return allowed(nil)
+	}
+
+	clusterID, found := shadowPod.Labels[forge.LiqoOriginClusterIDKey]
+	if !found || clusterID == "" {
+		return denied(fmt.Sprintf("ShadowPod %q does not specify its origin cluster (label %q)", key, forge.LiqoOriginClusterIDKey))
+	}
+
+	// The origin cluster label is not trusted in case the requester is a remote cluster, since it could be spoofed
+	// to consume the quota granted to another cluster.
+	if _, err := podsecurity.OriginClusterID(ctx, v.client, &request.UserInfo, clusterID); err != nil {
+		if errors.Is(err, podsecurity.ErrOriginClusterMismatch) {
+			klog.Warningf("Rejected ShadowPod %q: %v", key, err)
+			return denied(fmt.Sprintf("ShadowPod %q rejected: %v", key, err))
+		}
+		return errored(err)
+	}
+
+	var oldRequests corev1.ResourceList
+	if request.Operation == admissionv1.Update {
+		var oldShadowPod vkv1alpha1.ShadowPod
+		if err := json.Unmarshal(request.OldObject.Raw, &oldShadowPod); err != nil {
+			return errored(fmt.Errorf("failed to decode ShadowPod %q: %w", key, err))
+		}
+		if oldShadowPod.Labels[forge.LiqoOriginClusterIDKey] != clusterID {
+			return denied(fmt.Sprintf("the origin cluster of ShadowPod %q cannot be changed", key))
+		}
+		oldRequests = shadowPodRequests(&oldShadowPod)
+	}
+
+	constraints, err := podsecurity.ConstraintsFor(ctx, v.client, clusterID)
+	if err != nil {
+		return errored(fmt.Errorf("failed to retrieve the pod security constraints of cluster %q: %w", clusterID, err))
+	}
+	if violations := podsecurity.Violations(&shadowPod.Spec.Pod, constraints); len(violations) > 0 {
+		klog.Warningf("Rejected ShadowPod %q of cluster %q, as violating the pod security constraints: %s", key, clusterID, strings.Join(violations, ", "))
+		return forbidden(fmt.Sprintf("ShadowPod %q violates the pod security constraints enforced for cluster %q: %s",
+			key, clusterID, strings.Join(violations, ", ")))
+	}
+
+	// Terminated ShadowPods no longer consume the requested resources, hence they are not accounted in the quota.
+	if terminated(&shadowPod) {
+		if !dryRun {
+			v.forget(clusterID, key)
+		}
+		return allowed(nil)
+	}
+
+	quota, err := v.quota(ctx, clusterID)
+	if err != nil {
+		klog.Errorf("Failed to retrieve the quota of cluster %q: %v", clusterID, err)
+		return denied(fmt.Sprintf("cannot retrieve the quota granted to cluster %q: %v", clusterID, err))
+	}
+
+	requests := shadowPodRequests(&shadowPod)
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	usage, err := v.clusterUsage(ctx, clusterID)
+	if err != nil {
+		return errored(err)
+	}
+
+	// The resources possibly already requested by the same ShadowPod (in case of updates) are not considered.
+	// Additionally, updates are rejected only if increasing the requested resources, to allow modifying the
+	// ShadowPods of a cluster exceeding the quota (e.g., since reduced) as long as they do not worsen the situation.
+	used := sumResources(usage, key)
+	if exceeded := exceededResources(quota, used, increasedResources(oldRequests, requests)); len(exceeded) > 0 {
+		klog.Warningf("Rejected ShadowPod %q of cluster %q, as it would exceed the quota for %s", key, clusterID, strings.Join(exceeded, ", "))
+		return denied(fmt.Sprintf("ShadowPod %q exceeds the quota granted to cluster %q for %s (requested: %s, used: %s, quota: %s)",
+			key, clusterID, strings.Join(exceeded, ", "), formatResources(requests), formatResources(used), formatResources(quota)))
+	}
+
+	if !dryRun {
+		usage[key] = requests
+	}
+	addResources(used, requests)
+	klog.V(4).Infof("Admitted ShadowPod %q of cluster %q (used: %s, quota: %s)", key, clusterID, formatResources(used), formatResources(quota))
+	return allowed(map[string]string{UsageAuditAnnotation: formatResources(used)})
+}
+
+// quota returns the resources granted to the given consumer cluster through the corresponding ResourceOffer.
+func (v *Validator) quota(ctx context.Context, clusterID string) (corev1.ResourceList, error) {
+	var offers sharingv1alpha1.ResourceOfferList
+	if err := v.client.List(ctx, &offers, client.MatchingLabels{
+		discovery.ClusterIDLabel:         clusterID,
+		consts.ReplicationRequestedLabel: "true",
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list ResourceOffers: %w", err)
+	}
+
+	if len(offers.Items) == 0 {
+		return nil, fmt.Errorf("no ResourceOffer found")
+	}
+	return offers.Items[0].Spec.ResourceQuota.Hard, nil
+}
+
+// clusterUsage returns the resources requested by each ShadowPod of the given consumer cluster,
+// initializing them from the existing ShadowPods if not yet tracked. It must be called with the mutex held.
+func (v *Validator) clusterUsage(ctx context.Context, clusterID string) (map[types.NamespacedName]corev1.ResourceList, error) {
+	if usage, found := v.usage[clusterID]; found {
+		return usage, nil
+	}
+
+	var shadowPods vkv1alpha1.ShadowPodList
+	if err := v.client.List(ctx, &shadowPods, client.MatchingLabels{forge.LiqoOriginClusterIDKey: clusterID}); err != nil {
+		return nil, fmt.Errorf("failed to list the ShadowPods of cluster %q: %w", clusterID, err)
+	}
+
+	usage := map[types.NamespacedName]corev1.ResourceList{}
+	for i := range shadowPods.Items {
+		if !shadowPods.Items[i].DeletionTimestamp.IsZero() || terminated(&shadowPods.Items[i]) {
+			continue
+		}
+		usage[client.ObjectKeyFromObject(&shadowPods.Items[i])] = shadowPodRequests(&shadowPods.Items[i])
+	}
+	v.usage[clusterID] = usage
+	return usage, nil
+}
+
+// forget stops tracking the resources requested by the given ShadowPod.
+func (v *Validator) forget(clusterID string, key types.NamespacedName) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	if usage, found := v.usage[clusterID]; found {
+		delete(usage, key)
+	}
+}
+
+// terminated returns whether the pod managed by the given ShadowPod terminated, hence releasing the requested resources.
+func terminated(shadowPod *vkv1alpha1.ShadowPod) bool {
+	return shadowPod.Status.Phase == corev1.PodSucceeded || shadowPod.Status.Phase == corev1.PodFailed
+}
+
+// shadowPodRequests returns the resources requested by the given ShadowPod.
+func shadowPodRequests(shadowPod *vkv1alpha1.ShadowPod) corev1.ResourceList {
+	requests, _ := resourcehelper.PodRequestsAndLimits(&corev1.Pod{Spec: shadowPod.Spec.Pod})
+	return requests
+}
+
+// sumResources returns the sum of the given resources, excluding the ones associated with the given key.
+func sumResources(usage map[types.NamespacedName]corev1.ResourceList, exclude types.NamespacedName) corev1.ResourceList {
+	total := corev1.ResourceList{}
+	for key, resources := range usage {
+		if key != exclude {
+			addResources(total, resources)
+		}
+	}
+	return total
+}
+
+// addResources adds the given resources to the current ones.
+func addResources(current, toAdd corev1.ResourceList) {
+	for name, quantity := range toAdd {
+		value := current[name]
+		value.Add(quantity)
+		current[name] = value
+	}
+}
+
+// exceededResources returns the names of the resources whose quota would be exceeded by the given requests.
+// The resources not constrained by the quota are not considered.
+func exceededResources(quota, used, requests corev1.ResourceList) []string {
+	var exceeded []string
+	for name, requested := range requests {
+		limit, found := quota[name]
+		if !found || requested.IsZero() {
+			continue
+		}
+
+		total := used[name]
+		total.Add(requested)
+		if total.Cmp(limit) > 0 {
+			exceeded = append(exceeded, string(name))
+		}
+	}
+	sort.Strings(exceeded)
+	return exceeded
+}
+
+// increasedResources returns the resources whose requests increased with respect to the previous ones (if any),
+// i.e., all of them if the previous requests are nil.
+func increasedResources(previous, requests corev1.ResourceList) corev1.ResourceList {
+	if previous == nil {
+		return requests
+	}
+
+	increased := corev1.ResourceList{}
+	for name, requested := range requests {
+		if old, found := previous[name]; !found || requested.Cmp(old) > 0 {
+			increased[name] = requested
+		}
+	}
+	return increased
+}
+
+// formatResources returns the given resources in the form "cpu=2,memory=4Gi" (sorted by resource name).
+func formatResources(resources corev1.ResourceList) string {
+	entries := make([]string, 0, len(resources))
+	for name, quantity := range resources {
+		entries = append(entries, fmt.Sprintf("%s=%s", name, quantity.String()))
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
+
+func allowed(auditAnnotations map[string]string) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{
+		Allowed:          true,
+		AuditAnnotations: auditAnnotations,
+		Result:           &metav1.Status{Status: metav1.StatusSuccess},
+	}
+}
+
+func denied(message string) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusForbidden,
+			Reason:  metav1.StatusReasonForbidden,
+			Message: message,
+		},
+	}
+}
+
+// forbidden returns a response rejecting a ShadowPod violating the pod security constraints, with a specific reason
+// to allow the consumer cluster to mark the corresponding pod as rejected.
+func forbidden(message string) *admissionv1.AdmissionResponse {
+	response := denied(message)
+	response.Result.Reason = metav1.StatusReason(forge.PodOffloadingForbiddenReason)
+	return response
+}
+
+func errored(err error) *admissionv1.AdmissionResponse {
+	klog.Error(err)
+	return &admissionv1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusInternalServerError,
+			Reason:  metav1.StatusReasonInternalError,
+			Message: err.Error(),
+		},
+	}
+}
@@ -0,0 +1,322 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shadowpodvalidator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
+	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/discovery"
+	tenantnamespace "github.com/liqotech/liqo/pkg/tenantNamespace"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+)
+
+var _ = Describe("ShadowPod validator", func() {
+	const (
+		namespace = "foo"
+		clusterID = "remote-cluster-id"
+	)
+
+	var (
+		ctx       context.Context
+		validator *Validator
+		objects   []runtime.Object
+		userInfo  authenticationv1.UserInfo
+
+		dryRun      bool
+		subResource string
+	)
+
+	ShadowPod := func(name, origin, cpu string) *vkv1alpha1.ShadowPod {
+		shadowPod := &vkv1alpha1.ShadowPod{
+			TypeMeta:   metav1.TypeMeta{APIVersion: vkv1alpha1.SchemeGroupVersion.String(), Kind: "ShadowPod"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{}},
+			Spec: vkv1alpha1.ShadowPodSpec{Pod: corev1.PodSpec{Containers: []corev1.Container{{
+				Name: "container", Image: "nginx",
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)}},
+			}}}},
+		}
+		if origin != "" {
+			shadowPod.Labels[forge.LiqoOriginClusterIDKey] = origin
+		}
+		return shadowPod
+	}
+
+	Review := func(operation admissionv1.Operation, shadowPod, oldShadowPod *vkv1alpha1.ShadowPod) *admissionv1.AdmissionResponse {
+		request := &admissionv1.AdmissionRequest{
+			UID: "uid", Operation: operation, Namespace: shadowPod.Namespace, Name: shadowPod.Name, UserInfo: userInfo,
+			DryRun: &dryRun, SubResource: subResource,
+		}
+		if operation != admissionv1.Delete {
+			request.Object.Raw, _ = json.Marshal(shadowPod)
+		}
+		if oldShadowPod != nil {
+			request.OldObject.Raw, _ = json.Marshal(oldShadowPod)
+		}
+
+		body, err := json.Marshal(admissionv1.AdmissionReview{
+			TypeMeta: metav1.TypeMeta{APIVersion: admissionv1.SchemeGroupVersion.String(), Kind: "AdmissionReview"},
+			Request:  request,
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		output, err := validator.Validate(ctx, body)
+		Expect(err).ToNot(HaveOccurred())
+
+		var review admissionv1.AdmissionReview
+		Expect(json.Unmarshal(output, &review)).To(Succeed())
+		Expect(review.Response).ToNot(BeNil())
+		Expect(review.Response.UID).To(BeEquivalentTo("uid"))
+		return review.Response
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		userInfo = authenticationv1.UserInfo{}
+		dryRun, subResource = false, ""
+		objects = []runtime.Object{
+			&sharingv1alpha1.ResourceOffer{
+				ObjectMeta: metav1.ObjectMeta{Name: "offer", Namespace: "tenant", Labels: map[string]string{
+					discovery.ClusterIDLabel:         clusterID,
+					consts.ReplicationRequestedLabel: "true",
+				}},
+				Spec: sharingv1alpha1.ResourceOfferSpec{ResourceQuota: corev1.ResourceQuotaSpec{
+					Hard: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2"), corev1.ResourceMemory: resource.MustParse("4Gi")},
+				}},
+			},
+			ShadowPod("existing", clusterID, "1"),
+		}
+	})
+
+	JustBeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(corev1.AddToScheme(scheme)).To(Succeed())
+		Expect(sharingv1alpha1.AddToScheme(scheme)).To(Succeed())
+		Expect(vkv1alpha1.AddToScheme(scheme)).To(Succeed())
+		validator = NewValidator(fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build())
+	})
+
+	When("a ShadowPod fits in the quota granted to its origin cluster", func() {
+		It("should be admitted, and its resources should be tracked", func() {
+			response := Review(admissionv1.Create, ShadowPod("new", clusterID, "500m"), nil)
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.AuditAnnotations).To(HaveKeyWithValue(UsageAuditAnnotation, "cpu=1500m"))
+
+			usage, err := validator.Usage(ctx, clusterID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(usage.Cpu().Equal(resource.MustParse("1500m"))).To(BeTrue())
+		})
+	})
+
+	When("a ShadowPod exceeds the quota granted to its origin cluster", func() {
+		It("should be rejected", func() {
+			response := Review(admissionv1.Create, ShadowPod("new", clusterID, "1500m"), nil)
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.Result.Code).To(BeNumerically("==", http.StatusForbidden))
+			Expect(response.Result.Message).To(ContainSubstring("cpu"))
+		})
+
+		It("should be admitted once enough resources are released", func() {
+			Expect(Review(admissionv1.Create, ShadowPod("new", clusterID, "1500m"), nil).Allowed).To(BeFalse())
+			Expect(Review(admissionv1.Delete, ShadowPod("existing", clusterID, "1"), ShadowPod("existing", clusterID, "1")).Allowed).To(BeTrue())
+			Expect(Review(admissionv1.Create, ShadowPod("new", clusterID, "1500m"), nil).Allowed).To(BeTrue())
+		})
+	})
+
+	When("an existing ShadowPod is updated", func() {
+		It("should not account its resources twice", func() {
+			existing := ShadowPod("existing", clusterID, "1")
+			response := Review(admissionv1.Update, ShadowPod("existing", clusterID, "2"), existing)
+			Expect(response.Allowed).To(BeTrue())
+			Expect(response.AuditAnnotations).To(HaveKeyWithValue(UsageAuditAnnotation, "cpu=2"))
+		})
+
+		It("should be rejected if the origin cluster is changed", func() {
+			existing := ShadowPod("existing", clusterID, "1")
+			Expect(Review(admissionv1.Update, ShadowPod("existing", "other-cluster-id", "1"), existing).Allowed).To(BeFalse())
+		})
+
+		When("the origin cluster already exceeds its quota", func() {
+			BeforeEach(func() {
+				objects = append(objects, ShadowPod("other", clusterID, "2"))
+			})
+
+			It("should be admitted if not increasing the requested resources", func() {
+				existing := ShadowPod("existing", clusterID, "1")
+				updated := ShadowPod("existing", clusterID, "1")
+				updated.Labels["foo"] = "bar"
+				Expect(Review(admissionv1.Update, updated, existing).Allowed).To(BeTrue())
+				Expect(Review(admissionv1.Update, ShadowPod("existing", clusterID, "500m"), existing).Allowed).To(BeTrue())
+			})
+
+			It("should be rejected if increasing the requested resources", func() {
+				existing := ShadowPod("existing", clusterID, "1")
+				Expect(Review(admissionv1.Update, ShadowPod("existing", clusterID, "1500m"), existing).Allowed).To(BeFalse())
+			})
+		})
+	})
+
+	When("a ShadowPod is created by a remote cluster", func() {
+		BeforeEach(func() {
+			objects = append(objects, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant", Labels: map[string]string{
+				discovery.ClusterIDLabel:       clusterID,
+				discovery.TenantNamespaceLabel: "true",
+			}}})
+		})
+
+		It("should be admitted if the origin cluster matches the certificate-based identity", func() {
+			userInfo = authenticationv1.UserInfo{Username: clusterID, Groups: []string{tenantnamespace.RemoteClusterGroup}}
+			Expect(Review(admissionv1.Create, ShadowPod("new", clusterID, "100m"), nil).Allowed).To(BeTrue())
+		})
+
+		It("should be admitted if the origin cluster matches the OIDC identity", func() {
+			userInfo = authenticationv1.UserInfo{Username: "system:serviceaccount:tenant:" + tenantnamespace.RemoteIdentityServiceAccountName}
+			Expect(Review(admissionv1.Create, ShadowPod("new", clusterID, "100m"), nil).Allowed).To(BeTrue())
+		})
+
+		It("should be rejected if the origin cluster is spoofed", func() {
+			userInfo = authenticationv1.UserInfo{Username: "other-cluster-id", Groups: []string{tenantnamespace.RemoteClusterGroup}}
+			response := Review(admissionv1.Create, ShadowPod("new", clusterID, "100m"), nil)
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.Result.Message).To(ContainSubstring("other-cluster-id"))
+
+			userInfo = authenticationv1.UserInfo{Username: "system:serviceaccount:tenant:" + tenantnamespace.RemoteIdentityServiceAccountName}
+			Expect(Review(admissionv1.Create, ShadowPod("new", "other-cluster-id", "100m"), nil).Allowed).To(BeFalse())
+		})
+	})
+
+	When("a ShadowPod does not specify its origin cluster", func() {
+		It("should be rejected", func() {
+			Expect(Review(admissionv1.Create, ShadowPod("new", "", "100m"), nil).Allowed).To(BeFalse())
+		})
+	})
+
+	When("no ResourceOffer exists for the origin cluster", func() {
+		It("should be rejected", func() {
+			Expect(Review(admissionv1.Create, ShadowPod("new", "other-cluster-id", "100m"), nil).Allowed).To(BeFalse())
+		})
+	})
+
+	When("a ShadowPod violates the pod security constraints enforced for its origin cluster", func() {
+		BeforeEach(func() {
+			objects = append(objects, &sharingv1alpha1.SharingPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "policy"},
+				Spec: sharingv1alpha1.SharingPolicySpec{
+					ClusterIDs:  []string{clusterID},
+					PodSecurity: &sharingv1alpha1.PodSecurityConstraints{AllowRunAsRoot: true},
+				},
+			})
+		})
+
+		It("should be rejected with the appropriate reason", func() {
+			shadowPod := ShadowPod("new", clusterID, "100m")
+			shadowPod.Spec.Pod.HostNetwork = true
+			response := Review(admissionv1.Create, shadowPod, nil)
+			Expect(response.Allowed).To(BeFalse())
+			Expect(response.Result.Reason).To(BeEquivalentTo(forge.PodOffloadingForbiddenReason))
+			Expect(response.Result.Message).To(ContainSubstring("host network namespace shared"))
+		})
+
+		It("should be admitted if complying with the constraints", func() {
+			Expect(Review(admissionv1.Create, ShadowPod("new", clusterID, "100m"), nil).Allowed).To(BeTrue())
+		})
+	})
+
+	When("a ShadowPod is validated in dry-run mode", func() {
+		BeforeEach(func() { dryRun = true })
+
+		It("should be admitted, without tracking its resources", func() {
+			Expect(Review(admissionv1.Create, ShadowPod("new", clusterID, "500m"), nil).Allowed).To(BeTrue())
+			usage, err := validator.Usage(ctx, clusterID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(usage.Cpu().Equal(resource.MustParse("1"))).To(BeTrue())
+		})
+
+		It("should not release the resources in case of deletions", func() {
+			Expect(Review(admissionv1.Delete, ShadowPod("existing", clusterID, "1"), ShadowPod("existing", clusterID, "1")).Allowed).To(BeTrue())
+			usage, err := validator.Usage(ctx, clusterID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(usage.Cpu().Equal(resource.MustParse("1"))).To(BeTrue())
+		})
+	})
+
+	When("a ShadowPod terminated", func() {
+		Terminated := func(shadowPod *vkv1alpha1.ShadowPod, phase corev1.PodPhase) *vkv1alpha1.ShadowPod {
+			shadowPod.Status.Phase = phase
+			return shadowPod
+		}
+
+		JustBeforeEach(func() {
+			// Ensure the usage is already tracked, as the ShadowPods stored by the fake client are not updated.
+			_, err := validator.Usage(ctx, clusterID)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should release the requested resources once its status is updated", func() {
+			existing := ShadowPod("existing", clusterID, "1")
+			subResource = "status"
+			Expect(Review(admissionv1.Update, Terminated(ShadowPod("existing", clusterID, "1"), corev1.PodSucceeded), existing).Allowed).To(BeTrue())
+
+			subResource = ""
+			Expect(Review(admissionv1.Create, ShadowPod("new", clusterID, "1500m"), nil).Allowed).To(BeTrue())
+		})
+
+		It("should release the requested resources once updated", func() {
+			existing := Terminated(ShadowPod("existing", clusterID, "1"), corev1.PodFailed)
+			Expect(Review(admissionv1.Update, Terminated(ShadowPod("existing", clusterID, "1"), corev1.PodFailed), existing).Allowed).To(BeTrue())
+			Expect(Review(admissionv1.Create, ShadowPod("new", clusterID, "1500m"), nil).Allowed).To(BeTrue())
+		})
+
+		When("it already existed", func() {
+			BeforeEach(func() { objects[1] = Terminated(ShadowPod("existing", clusterID, "1"), corev1.PodFailed) })
+
+			It("should not be accounted in the tracked usage", func() {
+				usage, err := validator.Usage(ctx, clusterID)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(usage.Cpu().IsZero()).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("the tracked usage", func() {
+		It("should be rebuilt from the existing ShadowPods after a resync", func() {
+			Expect(Review(admissionv1.Create, ShadowPod("new", clusterID, "500m"), nil).Allowed).To(BeTrue())
+
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+			go validator.Resync(ctx, 10*time.Millisecond)
+
+			Eventually(func() bool {
+				usage, err := validator.Usage(ctx, clusterID)
+				return err == nil && usage.Cpu().Equal(resource.MustParse("1"))
+			}).Should(BeTrue())
+		})
+	})
+})
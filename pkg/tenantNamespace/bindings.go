@@ -16,7 +16,10 @@ package tenantnamespace
 
 import (
 	"context"
+	"reflect"
+	"strings"
 
+	authenticationv1 "k8s.io/api/authentication/v1"
 	v1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -25,6 +28,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	"github.com/liqotech/liqo/pkg/discovery"
 )
 
 // add the bindings for the remote clusterid for the given ClusterRoles
@@ -86,13 +90,7 @@ func (nm *tenantNamespaceManager) bindClusterRole(cluster discoveryv1alpha1.Clus
 				ownerRef,
 			},
 		},
-		Subjects: []rbacv1.Subject{
-			{
-				Kind:     rbacv1.UserKind,
-				APIGroup: rbacv1.GroupName,
-				Name:     cluster.ClusterID,
-			},
-		},
+		Subjects: RemoteClusterSubjects(cluster.ClusterID, namespace.Name),
 		RoleRef: rbacv1.RoleRef{
 			APIGroup: rbacv1.GroupName,
 			Kind:     "ClusterRole",
@@ -100,11 +98,55 @@ func (nm *tenantNamespaceManager) bindClusterRole(cluster discoveryv1alpha1.Clus
 		},
 	}
 
-	rb, err := nm.client.RbacV1().RoleBindings(namespace.Name).Create(context.TODO(), rb, metav1.CreateOptions{})
-	if apierrors.IsAlreadyExists(err) {
-		return nm.client.RbacV1().RoleBindings(namespace.Name).Get(context.TODO(), name, metav1.GetOptions{})
+	created, err := nm.client.RbacV1().RoleBindings(namespace.Name).Create(context.TODO(), rb, metav1.CreateOptions{})
+	if !apierrors.IsAlreadyExists(err) {
+		return created, err
+	}
+
+	existing, err := nm.client.RbacV1().RoleBindings(namespace.Name).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil || reflect.DeepEqual(existing.Subjects, rb.Subjects) {
+		return existing, err
+	}
+	// the binding has been created by a previous version, hence the subjects are aligned.
+	existing.Subjects = rb.Subjects
+	return nm.client.RbacV1().RoleBindings(namespace.Name).Update(context.TODO(), existing, metav1.UpdateOptions{})
+}
+
+// RemoteClusterSubjects returns the subjects identifying the given remote cluster, to be bound to the permissions
+// granted to it: the user authenticated through the certificate-based identities, and the ServiceAccount (in the
+// corresponding tenant namespace) whose tokens are issued as identities when the OIDC identity provider is enabled.
+func RemoteClusterSubjects(clusterID, tenantNamespace string) []rbacv1.Subject {
+	return []rbacv1.Subject{
+		{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: clusterID},
+		{Kind: rbacv1.ServiceAccountKind, Name: RemoteIdentityServiceAccountName, Namespace: tenantNamespace},
+	}
+}
+
+// RemoteClusterID returns the identifier of the remote cluster the given requester corresponds to, i.e., the inverse
+// of RemoteClusterSubjects. The boolean is false if the requester does not correspond to any remote cluster.
+// The identifier is retrieved from the username in case of certificate-based identities, and from the cluster-id
+// label of the tenant namespace hosting the remote identity ServiceAccount in case of OIDC identities.
+func RemoteClusterID(ctx context.Context, cl client.Client, userInfo *authenticationv1.UserInfo) (string, bool, error) {
+	if strings.HasPrefix(userInfo.Username, serviceAccountUsernamePrefix) {
+		parts := strings.Split(strings.TrimPrefix(userInfo.Username, serviceAccountUsernamePrefix), ":")
+		if len(parts) != 2 || parts[0] == "" || parts[1] != RemoteIdentityServiceAccountName {
+			return "", false, nil
+		}
+
+		var ns v1.Namespace
+		if err := cl.Get(ctx, client.ObjectKey{Name: parts[0]}, &ns); err != nil {
+			return "", false, client.IgnoreNotFound(err)
+		}
+		clusterID := ns.Labels[discovery.ClusterIDLabel]
+		return clusterID, ns.Labels[discovery.TenantNamespaceLabel] == "true" && clusterID != "", nil
+	}
+
+	for _, group := range userInfo.Groups {
+		if group == RemoteClusterGroup {
+			return userInfo.Username, userInfo.Username != "", nil
+		}
 	}
-	return rb, err
+	return "", false, nil
 }
 
 // delete a RoleBinding in the given Namespace.
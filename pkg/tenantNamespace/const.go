@@ -16,4 +16,14 @@ package tenantnamespace
 
 const (
 	roleBindingRoot = "liqo-binding"
+
+	// serviceAccountUsernamePrefix is the prefix of the usernames of the ServiceAccounts, followed by "<namespace>:<name>".
+	serviceAccountUsernamePrefix = "system:serviceaccount:"
+
+	// RemoteIdentityServiceAccountName is the name of the ServiceAccount representing a remote cluster in its tenant namespace,
+	// whose tokens are issued as identities when the OIDC identity provider is enabled.
+	RemoteIdentityServiceAccountName = "liqo-remote-identity"
+
+	// RemoteClusterGroup is the group the remote clusters authenticated through the certificate-based identities belong to.
+	RemoteClusterGroup = "liqo.io"
 )
@@ -23,12 +23,16 @@ import (
 	"testing"
 
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
+	authenticationv1 "k8s.io/api/authentication/v1"
 	v1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
 	"github.com/liqotech/liqo/pkg/discovery"
@@ -242,14 +246,52 @@ var _ = Describe("TenantNamespace", func() {
 
 	})
 
+	Context("RemoteClusterID", func() {
+
+		var cl ctrlclient.Client
+
+		BeforeEach(func() {
+			cl = fake.NewClientBuilder().WithObjects(
+				&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant", Labels: map[string]string{
+					discovery.ClusterIDLabel: "remote-cluster-id", discovery.TenantNamespaceLabel: "true"}}},
+				&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "other", Labels: map[string]string{
+					discovery.ClusterIDLabel: "remote-cluster-id"}}},
+			).Build()
+		})
+
+		DescribeTable("should resolve the remote cluster the requester corresponds to",
+			func(userInfo authenticationv1.UserInfo, expectedClusterID string, expectedFound bool) {
+				clusterID, found, err := RemoteClusterID(context.Background(), cl, &userInfo)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(found).To(Equal(expectedFound))
+				Expect(clusterID).To(Equal(expectedClusterID))
+			},
+			Entry("certificate-based identity", authenticationv1.UserInfo{
+				Username: "remote-cluster-id", Groups: []string{RemoteClusterGroup, "system:authenticated"}}, "remote-cluster-id", true),
+			Entry("user not belonging to the remote clusters group", authenticationv1.UserInfo{
+				Username: "remote-cluster-id", Groups: []string{"system:authenticated"}}, "", false),
+			Entry("remote identity ServiceAccount", authenticationv1.UserInfo{
+				Username: "system:serviceaccount:tenant:" + RemoteIdentityServiceAccountName}, "remote-cluster-id", true),
+			Entry("remote identity ServiceAccount outside a tenant namespace", authenticationv1.UserInfo{
+				Username: "system:serviceaccount:other:" + RemoteIdentityServiceAccountName}, "", false),
+			Entry("remote identity ServiceAccount in a non-existing namespace", authenticationv1.UserInfo{
+				Username: "system:serviceaccount:missing:" + RemoteIdentityServiceAccountName}, "", false),
+			Entry("other ServiceAccount", authenticationv1.UserInfo{
+				Username: "system:serviceaccount:tenant:default", Groups: []string{RemoteClusterGroup}}, "", false),
+		)
+	})
+
 })
 
 func checkRoleBinding(rb *rbacv1.RoleBinding, namespace string, homeCluster discoveryv1alpha1.ClusterIdentity,
 	clusterRoleName string) {
 	Expect(rb.Namespace).To(Equal(namespace))
-	Expect(len(rb.Subjects)).To(Equal(1))
+	Expect(len(rb.Subjects)).To(Equal(2))
 	Expect(rb.Subjects[0].Kind).To(Equal(rbacv1.UserKind))
 	Expect(rb.Subjects[0].Name).To(Equal(homeCluster.ClusterID))
+	Expect(rb.Subjects[1].Kind).To(Equal(rbacv1.ServiceAccountKind))
+	Expect(rb.Subjects[1].Name).To(Equal(RemoteIdentityServiceAccountName))
+	Expect(rb.Subjects[1].Namespace).To(Equal(namespace))
 	Expect(rb.RoleRef.Kind).To(Equal("ClusterRole"))
 	Expect(rb.RoleRef.Name).To(Equal(clusterRoleName))
 }
@@ -23,6 +23,7 @@ import (
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/types"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 )
 
@@ -207,8 +208,20 @@ var _ = Describe("ParseArguments", func() {
 				cidr:          "10.0.0..0/16",
 				expectedError: HaveOccurred(),
 			}),
+
+			Entry("correct IPv6 cidr", parseCidrTestCase{
+				cidr:          "fd00:10:244::/56",
+				expectedError: Succeed(),
+			}),
 		)
 
+		It("should report whether it has been set", func() {
+			cl := CIDR{}
+			Expect(cl.IsSet()).To(BeFalse())
+			Expect(cl.Set("10.0.0.0/16")).To(Succeed())
+			Expect(cl.IsSet()).To(BeTrue())
+		})
+
 	})
 
 	Context("ClusterIdentity", func() {
@@ -326,4 +339,57 @@ var _ = Describe("ParseArguments", func() {
 		)
 	})
 
+	Context("ResourceMap", func() {
+		type parseResourceMapTestcase struct {
+			str            string
+			expectedError  OmegaMatcher
+			expectedValue  corev1.ResourceList
+			expectedString string
+		}
+
+		DescribeTable("ResourceMap table",
+			func(c parseResourceMapTestcase) {
+				rm := ResourceMap{}
+				err := rm.Set(c.str)
+				Expect(err).To(c.expectedError)
+
+				if err == nil {
+					Expect(rm.ResourceList).To(HaveLen(len(c.expectedValue)))
+					for name, quantity := range c.expectedValue {
+						Expect(rm.ResourceList).To(HaveKey(name))
+						Expect(rm.ResourceList[name].Equal(quantity)).To(BeTrue())
+					}
+					Expect(rm.String()).To(Equal(c.expectedString))
+				}
+			},
+
+			Entry("empty string", parseResourceMapTestcase{
+				str:            "",
+				expectedError:  Not(HaveOccurred()),
+				expectedValue:  corev1.ResourceList{},
+				expectedString: "",
+			}),
+
+			Entry("invalid format", parseResourceMapTestcase{
+				str:           "cpu:1",
+				expectedError: HaveOccurred(),
+			}),
+
+			Entry("invalid quantity", parseResourceMapTestcase{
+				str:           "cpu=11z",
+				expectedError: HaveOccurred(),
+			}),
+
+			Entry("valid string", parseResourceMapTestcase{
+				str:           "memory=1Gi,cpu=500m",
+				expectedError: Not(HaveOccurred()),
+				expectedValue: corev1.ResourceList{
+					corev1.ResourceCPU:    *resource.NewScaledQuantity(500, resource.Milli),
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+				},
+				expectedString: "cpu=500m,memory=1Gi",
+			}),
+		)
+	})
+
 })
@@ -41,3 +41,8 @@ func (c *CIDR) Set(str string) error {
 func (c *CIDR) Type() string {
 	return "cidr"
 }
+
+// IsSet returns whether the CIDR has been set.
+func (c *CIDR) IsSet() bool {
+	return c.network.IP != nil
+}
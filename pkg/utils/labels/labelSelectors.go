@@ -67,6 +67,44 @@ var (
 		},
 	}
 
+	// GatewayPodLabelSelector selector used to get the Gateway Pods.
+	GatewayPodLabelSelector = metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      liqoconst.K8sAppNameKey,
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   []string{liqoconst.GatewayAppName},
+			},
+		},
+	}
+
+	// ActiveGatewayPodLabelSelector selector used to get the active Gateway Pod.
+	ActiveGatewayPodLabelSelector = metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      liqoconst.K8sAppNameKey,
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   []string{liqoconst.GatewayAppName},
+			},
+			{
+				Key:      liqoconst.GatewayServiceLabelKey,
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   []string{liqoconst.GatewayActiveLabelValue},
+			},
+		},
+	}
+
+	// RoutePodLabelSelector selector used to get the Route Pods.
+	RoutePodLabelSelector = metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      liqoconst.K8sAppNameKey,
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   []string{liqoconst.RouteAppName},
+			},
+		},
+	}
+
 	// NetworkManagerPodLabelSelector selector used to get the Network Manager Pod.
 	NetworkManagerPodLabelSelector = metav1.LabelSelector{
 		MatchExpressions: []metav1.LabelSelectorRequirement{
@@ -100,3 +138,16 @@ var (
 		},
 	}
 )
+
+// TunnelSecretLabelSelector returns the selector used to get the secret storing the keys of the given tunnel backend.
+func TunnelSecretLabelSelector(backendType string) metav1.LabelSelector {
+	return metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      liqoconst.KeysLabel,
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   []string{backendType},
+			},
+		},
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package podsecurity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
+	tenantnamespace "github.com/liqotech/liqo/pkg/tenantNamespace"
+	sharingpolicyutils "github.com/liqotech/liqo/pkg/utils/sharingpolicy"
+)
+
+// ErrOriginClusterMismatch is returned when a remote cluster specifies a different origin cluster for a ShadowPod.
+var ErrOriginClusterMismatch = errors.New("origin cluster mismatch")
+
+// OriginClusterID returns the cluster a ShadowPod originates from, given the requester and the origin cluster claimed by
+// the ShadowPod (i.e., its origin cluster label). The claimed cluster is not trusted in case the requester is a remote cluster,
+// since it could claim to be another one to be subject to looser constraints: the cluster is derived from its identity, and
+// an error wrapping ErrOriginClusterMismatch is returned if the two differ. Requests of local users are authorized through RBAC.
+func OriginClusterID(ctx context.Context, cl client.Client, userInfo *authenticationv1.UserInfo, claimed string) (string, error) {
+	requester, remote, err := tenantnamespace.RemoteClusterID(ctx, cl, userInfo)
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve the cluster corresponding to user %q: %w", userInfo.Username, err)
+	}
+
+	if remote && requester != claimed {
+		return "", fmt.Errorf("%w: %q specified as the origin cluster, while requested by cluster %q",
+			ErrOriginClusterMismatch, claimed, requester)
+	}
+	return claimed, nil
+}
+
+// ConstraintsFor returns the pod security constraints enforced for the given cluster, according to the SharingPolicy
+// selecting it. It returns nil if no constraint is enforced. The cluster shall be retrieved through OriginClusterID.
+func ConstraintsFor(ctx context.Context, cl client.Client, clusterID string) (*sharingv1alpha1.PodSecurityConstraints, error) {
+	policies, err := sharingpolicyutils.List(ctx, cl)
+	if err != nil {
+		return nil, err
+	}
+
+	if policy := sharingpolicyutils.ForCluster(ctx, cl, clusterID, policies); policy != nil {
+		return policy.Spec.PodSecurity, nil
+	}
+	return nil, nil
+}
+
+// Violations returns the description of the constraints violated by the given pod specification.
+func Violations(spec *corev1.PodSpec, constraints *sharingv1alpha1.PodSecurityConstraints) []string {
+	return enforce(spec, constraints, false)
+}
+
+// Rewrite mutates the given pod specification, removing or overriding the fields violating the constraints.
+// It returns the description of the violations which have been rewritten.
+func Rewrite(spec *corev1.PodSpec, constraints *sharingv1alpha1.PodSecurityConstraints) []string {
+	return enforce(spec, constraints, true)
+}
+
+// enforce checks the given pod specification against the constraints, possibly rewriting the offending fields.
+func enforce(spec *corev1.PodSpec, constraints *sharingv1alpha1.PodSecurityConstraints, rewrite bool) []string {
+	if constraints == nil {
+		return nil
+	}
+
+	var violations []string
+	violated := func(format string, args ...interface{}) bool {
+		violations = append(violations, fmt.Sprintf(format, args...))
+		return rewrite
+	}
+
+	if !constraints.AllowHostNamespaces {
+		if spec.HostNetwork && violated("host network namespace shared") {
+			spec.HostNetwork = false
+		}
+		if spec.HostPID && violated("host PID namespace shared") {
+			spec.HostPID = false
+		}
+		if spec.HostIPC && violated("host IPC namespace shared") {
+			spec.HostIPC = false
+		}
+	}
+
+	if !constraints.AllowHostPath {
+		for i := range spec.Volumes {
+			if spec.Volumes[i].HostPath != nil && violated("volume %q of type hostPath", spec.Volumes[i].Name) {
+				spec.Volumes[i].VolumeSource = corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}
+			}
+		}
+	}
+
+	for _, containers := range [][]corev1.Container{spec.InitContainers, spec.Containers} {
+		for i := range containers {
+			container := &containers[i]
+
+			if !constraints.AllowPrivileged && isPrivileged(container.SecurityContext) &&
+				violated("container %q is privileged", container.Name) {
+				container.SecurityContext.Privileged = pointer.Bool(false)
+			}
+
+			if sc := container.SecurityContext; sc != nil && sc.Capabilities != nil {
+				allowed := make([]corev1.Capability, 0, len(sc.Capabilities.Add))
+				for _, capability := range sc.Capabilities.Add {
+					if isCapabilityAllowed(capability, constraints.AllowedCapabilities) ||
+						!violated("container %q adds capability %q", container.Name, capability) {
+						allowed = append(allowed, capability)
+					}
+				}
+				if len(allowed) != len(sc.Capabilities.Add) {
+					sc.Capabilities.Add = allowed
+				}
+			}
+
+			if !constraints.AllowRunAsRoot && mayRunAsRoot(spec.SecurityContext, container.SecurityContext) &&
+				violated("container %q may run as root", container.Name) {
+				if container.SecurityContext == nil {
+					container.SecurityContext = &corev1.SecurityContext{}
+				}
+				container.SecurityContext.RunAsNonRoot = pointer.Bool(true)
+				if container.SecurityContext.RunAsUser != nil && *container.SecurityContext.RunAsUser == 0 {
+					container.SecurityContext.RunAsUser = nil
+				}
+				if spec.SecurityContext != nil && spec.SecurityContext.RunAsUser != nil && *spec.SecurityContext.RunAsUser == 0 {
+					spec.SecurityContext.RunAsUser = nil
+				}
+			}
+		}
+	}
+
+	return violations
+}
+
+// isPrivileged returns whether the container is configured to run in privileged mode.
+func isPrivileged(sc *corev1.SecurityContext) bool {
+	return sc != nil && sc.Privileged != nil && *sc.Privileged
+}
+
+// isCapabilityAllowed returns whether the given capability is included in the allowed ones (or all are allowed).
+func isCapabilityAllowed(capability corev1.Capability, allowed []corev1.Capability) bool {
+	for _, candidate := range allowed {
+		if candidate == capability || candidate == "ALL" {
+			return true
+		}
+	}
+	return false
+}
+
+// mayRunAsRoot returns whether a container may run as root, given the pod and container security contexts.
+// This is the case if the user is set to root, or it is not set and runAsNonRoot is not enabled.
+func mayRunAsRoot(podSC *corev1.PodSecurityContext, containerSC *corev1.SecurityContext) bool {
+	var runAsUser *int64
+	var runAsNonRoot *bool
+	if podSC != nil {
+		runAsUser, runAsNonRoot = podSC.RunAsUser, podSC.RunAsNonRoot
+	}
+	if containerSC != nil {
+		if containerSC.RunAsUser != nil {
+			runAsUser = containerSC.RunAsUser
+		}
+		if containerSC.RunAsNonRoot != nil {
+			runAsNonRoot = containerSC.RunAsNonRoot
+		}
+	}
+
+	if runAsUser != nil {
+		return *runAsUser == 0
+	}
+	return runAsNonRoot == nil || !*runAsNonRoot
+}
@@ -0,0 +1,168 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package podsecurity_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gstruct"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+
+	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
+	"github.com/liqotech/liqo/pkg/utils/podsecurity"
+)
+
+var _ = Describe("Pod security constraints", func() {
+	// compliant returns a pod specification complying with the default constraints.
+	compliant := func() *corev1.PodSpec {
+		return &corev1.PodSpec{
+			SecurityContext: &corev1.PodSecurityContext{RunAsNonRoot: pointer.Bool(true)},
+			Containers:      []corev1.Container{{Name: "foo", Image: "nginx"}},
+			Volumes:         []corev1.Volume{{Name: "data", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}}},
+		}
+	}
+
+	type ViolationsCase struct {
+		Mutate      func(spec *corev1.PodSpec)
+		Constraints *sharingv1alpha1.PodSecurityConstraints
+		Expected    []string
+	}
+
+	DescribeTable("The Violations function",
+		func(c ViolationsCase) {
+			spec := compliant()
+			if c.Mutate != nil {
+				c.Mutate(spec)
+			}
+			original := spec.DeepCopy()
+
+			Expect(podsecurity.Violations(spec, c.Constraints)).To(ConsistOf(c.Expected))
+			Expect(spec).To(Equal(original), "the pod specification should not be modified")
+		},
+		Entry("no constraints are specified", ViolationsCase{
+			Mutate:      func(spec *corev1.PodSpec) { spec.HostNetwork = true },
+			Constraints: nil,
+			Expected:    []string{},
+		}),
+		Entry("the pod complies with the constraints", ViolationsCase{
+			Constraints: &sharingv1alpha1.PodSecurityConstraints{},
+			Expected:    []string{},
+		}),
+		Entry("the pod shares the host namespaces", ViolationsCase{
+			Mutate:      func(spec *corev1.PodSpec) { spec.HostNetwork, spec.HostPID, spec.HostIPC = true, true, true },
+			Constraints: &sharingv1alpha1.PodSecurityConstraints{},
+			Expected:    []string{"host network namespace shared", "host PID namespace shared", "host IPC namespace shared"},
+		}),
+		Entry("the pod shares the host namespaces, but it is allowed", ViolationsCase{
+			Mutate:      func(spec *corev1.PodSpec) { spec.HostNetwork = true },
+			Constraints: &sharingv1alpha1.PodSecurityConstraints{AllowHostNamespaces: true},
+			Expected:    []string{},
+		}),
+		Entry("the pod mounts a hostPath volume", ViolationsCase{
+			Mutate: func(spec *corev1.PodSpec) {
+				spec.Volumes[0].VolumeSource = corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/"}}
+			},
+			Constraints: &sharingv1alpha1.PodSecurityConstraints{},
+			Expected:    []string{`volume "data" of type hostPath`},
+		}),
+		Entry("a container is privileged", ViolationsCase{
+			Mutate: func(spec *corev1.PodSpec) {
+				spec.Containers[0].SecurityContext = &corev1.SecurityContext{Privileged: pointer.Bool(true)}
+			},
+			Constraints: &sharingv1alpha1.PodSecurityConstraints{},
+			Expected:    []string{`container "foo" is privileged`},
+		}),
+		Entry("a container adds capabilities", ViolationsCase{
+			Mutate: func(spec *corev1.PodSpec) {
+				spec.Containers[0].SecurityContext = &corev1.SecurityContext{
+					Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"NET_ADMIN", "NET_BIND_SERVICE"}},
+				}
+			},
+			Constraints: &sharingv1alpha1.PodSecurityConstraints{AllowedCapabilities: []corev1.Capability{"NET_BIND_SERVICE"}},
+			Expected:    []string{`container "foo" adds capability "NET_ADMIN"`},
+		}),
+		Entry("an init container may run as root", ViolationsCase{
+			Mutate: func(spec *corev1.PodSpec) {
+				spec.InitContainers = []corev1.Container{{Name: "init", SecurityContext: &corev1.SecurityContext{RunAsUser: pointer.Int64(0)}}}
+			},
+			Constraints: &sharingv1alpha1.PodSecurityConstraints{},
+			Expected:    []string{`container "init" may run as root`},
+		}),
+		Entry("a container may run as root, as runAsNonRoot is not set", ViolationsCase{
+			Mutate:      func(spec *corev1.PodSpec) { spec.SecurityContext = nil },
+			Constraints: &sharingv1alpha1.PodSecurityConstraints{},
+			Expected:    []string{`container "foo" may run as root`},
+		}),
+		Entry("a container runs as a non-root user", ViolationsCase{
+			Mutate: func(spec *corev1.PodSpec) {
+				spec.SecurityContext = nil
+				spec.Containers[0].SecurityContext = &corev1.SecurityContext{RunAsUser: pointer.Int64(1000)}
+			},
+			Constraints: &sharingv1alpha1.PodSecurityConstraints{},
+			Expected:    []string{},
+		}),
+		Entry("a container may run as root, but it is allowed", ViolationsCase{
+			Mutate:      func(spec *corev1.PodSpec) { spec.SecurityContext = nil },
+			Constraints: &sharingv1alpha1.PodSecurityConstraints{AllowRunAsRoot: true},
+			Expected:    []string{},
+		}),
+	)
+
+	Describe("The Rewrite function", func() {
+		var (
+			spec        *corev1.PodSpec
+			constraints *sharingv1alpha1.PodSecurityConstraints
+			rewritten   []string
+		)
+
+		BeforeEach(func() {
+			spec = compliant()
+			spec.HostNetwork = true
+			spec.SecurityContext.RunAsUser = pointer.Int64(0)
+			spec.Volumes[0].VolumeSource = corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/"}}
+			spec.Containers[0].SecurityContext = &corev1.SecurityContext{
+				Privileged:   pointer.Bool(true),
+				Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"NET_ADMIN", "NET_BIND_SERVICE"}},
+			}
+			constraints = &sharingv1alpha1.PodSecurityConstraints{
+				Action:              sharingv1alpha1.PodSecurityActionRewrite,
+				AllowedCapabilities: []corev1.Capability{"NET_BIND_SERVICE"},
+			}
+		})
+
+		JustBeforeEach(func() { rewritten = podsecurity.Rewrite(spec, constraints) })
+
+		It("should return the rewritten violations", func() {
+			Expect(rewritten).To(ConsistOf("host network namespace shared", `volume "data" of type hostPath`,
+				`container "foo" is privileged`, `container "foo" adds capability "NET_ADMIN"`, `container "foo" may run as root`))
+		})
+
+		It("should rewrite the offending fields", func() {
+			Expect(spec.HostNetwork).To(BeFalse())
+			Expect(spec.Volumes[0].HostPath).To(BeNil())
+			Expect(spec.Volumes[0].EmptyDir).ToNot(BeNil())
+			Expect(spec.Containers[0].SecurityContext.Privileged).To(PointTo(BeFalse()))
+			Expect(spec.Containers[0].SecurityContext.Capabilities.Add).To(ConsistOf(corev1.Capability("NET_BIND_SERVICE")))
+			Expect(spec.Containers[0].SecurityContext.RunAsNonRoot).To(PointTo(BeTrue()))
+			Expect(spec.SecurityContext.RunAsUser).To(BeNil())
+		})
+
+		It("should produce a pod specification complying with the constraints", func() {
+			Expect(podsecurity.Violations(spec, constraints)).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,83 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sharingpolicy
+
+import (
+	"context"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
+	foreignclusterutils "github.com/liqotech/liqo/pkg/utils/foreignCluster"
+)
+
+// List returns the existing SharingPolicies, sorted by decreasing priority (and then by name).
+func List(ctx context.Context, cl client.Client) ([]sharingv1alpha1.SharingPolicy, error) {
+	var policies sharingv1alpha1.SharingPolicyList
+	if err := cl.List(ctx, &policies); err != nil {
+		klog.Errorf("Failed to list SharingPolicies: %v", err)
+		return nil, err
+	}
+
+	sort.SliceStable(policies.Items, func(i, j int) bool {
+		if policies.Items[i].Spec.Priority != policies.Items[j].Spec.Priority {
+			return policies.Items[i].Spec.Priority > policies.Items[j].Spec.Priority
+		}
+		return policies.Items[i].Name < policies.Items[j].Name
+	})
+	return policies.Items, nil
+}
+
+// ForCluster returns the policy with the highest priority selecting the given cluster, or nil if none does.
+// The policies are expected to be sorted as returned by List.
+func ForCluster(ctx context.Context, cl client.Client, clusterID string,
+	policies []sharingv1alpha1.SharingPolicy) *sharingv1alpha1.SharingPolicy {
+	var clusterLabels labels.Set
+	for i := range policies {
+		policy := &policies[i]
+		for _, id := range policy.Spec.ClusterIDs {
+			if id == clusterID {
+				return policy
+			}
+		}
+
+		if policy.Spec.ClusterSelector == nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(policy.Spec.ClusterSelector)
+		if err != nil {
+			klog.Errorf("SharingPolicy %q specifies an invalid cluster selector: %v", policy.Name, err)
+			continue
+		}
+
+		if clusterLabels == nil {
+			fc, err := foreignclusterutils.GetForeignClusterByID(ctx, cl, clusterID)
+			if err != nil {
+				klog.Warningf("Failed to retrieve the ForeignCluster of cluster %q: %v", clusterID, err)
+				clusterLabels = labels.Set{}
+			} else {
+				clusterLabels = fc.GetLabels()
+			}
+		}
+		if selector.Matches(clusterLabels) {
+			return policy
+		}
+	}
+	return nil
+}
@@ -14,7 +14,13 @@
 
 package virtualKubelet
 
-import discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+import (
+	"regexp"
+	"strings"
+
+	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
+	"github.com/liqotech/liqo/pkg/consts"
+)
 
 const (
 	// VirtualNodePrefix -> the prefix used to generate the virtual node name.
@@ -25,9 +31,40 @@ const (
 	ReflectedpodKey = "virtualkubelet.liqo.io/source-pod"
 	// HomePodFinalizer -> the finalizer added to local pods when reflected.
 	HomePodFinalizer = "virtual-kubelet.liqo.io/provider"
+
+	// maxNodePoolSuffixLength -> the maximum length of the suffix identifying a node pool,
+	// to generate valid finalizers and resource names.
+	maxNodePoolSuffixLength = 50
 )
 
+var invalidNodePoolCharacters = regexp.MustCompile("[^a-z0-9-]+")
+
 // VirtualNodeName generates the virtual node name based on the cluster ID.
 func VirtualNodeName(cluster discoveryv1alpha1.ClusterIdentity) string {
 	return VirtualNodePrefix + cluster.ClusterName
 }
+
+// VirtualNodePoolName generates the name of the virtual node associated with the given node pool of the cluster.
+func VirtualNodePoolName(cluster discoveryv1alpha1.ClusterIdentity, pool string) string {
+	return VirtualNodeName(cluster) + "-" + NodePoolSuffix(pool)
+}
+
+// NodePoolFinalizer returns the finalizer added on a ResourceOffer when the virtual node
+// associated with the given node pool is up.
+func NodePoolFinalizer(pool string) string {
+	return consts.NodeFinalizer + "-" + NodePoolSuffix(pool)
+}
+
+// IsNodeFinalizer returns whether the given finalizer is set by a virtual kubelet (either associated with a node pool or not).
+func IsNodeFinalizer(finalizer string) bool {
+	return finalizer == consts.NodeFinalizer || strings.HasPrefix(finalizer, consts.NodeFinalizer+"-")
+}
+
+// NodePoolSuffix returns a sanitized version of the node pool name, suitable to be used in resource names.
+func NodePoolSuffix(pool string) string {
+	suffix := invalidNodePoolCharacters.ReplaceAllString(strings.ToLower(pool), "-")
+	if len(suffix) > maxNodePoolSuffixLength {
+		suffix = suffix[:maxNodePoolSuffixLength]
+	}
+	return strings.Trim(suffix, "-")
+}
@@ -152,6 +152,16 @@ var _ = Describe("EndpointSlices Forging", func() {
 			It("should return no endpoints", func() { Expect(output).To(HaveLen(0)) })
 		})
 
+		When("translating an endpoint referring to a sibling virtual node", func() {
+			BeforeEach(func() {
+				forge.InitSiblingNodes("sibling-node")
+				endpoint.NodeName = pointer.String("sibling-node")
+				input = []discoveryv1beta1.Endpoint{endpoint, endpoint, endpoint}
+			})
+			AfterEach(func() { forge.InitSiblingNodes() })
+			It("should return no endpoints", func() { Expect(output).To(HaveLen(0)) })
+		})
+
 		When("translating multiple endpoints", func() {
 			BeforeEach(func() { input = []discoveryv1beta1.Endpoint{endpoint, endpoint, endpoint} })
 			It("should return the correct number of endpoints", func() { Expect(output).To(HaveLen(3)) })
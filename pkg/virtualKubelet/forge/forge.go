@@ -32,6 +32,8 @@ var (
 
 	// LiqoNodeName -> the name of the node associated with the current virtual-kubelet.
 	LiqoNodeName string
+	// LiqoSiblingNodeNames -> the names of the other nodes associated with the same remote cluster.
+	LiqoSiblingNodeNames []string
 	// LiqoNodeIP -> the local IP of the node associated with the current virtual-kubelet.
 	LiqoNodeIP string
 	// StartTime -> the instant in time the forging logic has been started.
@@ -58,6 +60,24 @@ func Init(localClusterID, remoteClusterID, nodeName, nodeIP string) {
 	}
 }
 
+// InitSiblingNodes configures the names of the other virtual nodes associated with the same remote cluster.
+func InitSiblingNodes(nodeNames ...string) {
+	LiqoSiblingNodeNames = nodeNames
+}
+
+// IsLiqoNodeForRemoteCluster returns whether the given node is one of the virtual nodes associated with the remote cluster.
+func IsLiqoNodeForRemoteCluster(nodeName string) bool {
+	if nodeName == LiqoNodeName {
+		return true
+	}
+	for _, sibling := range LiqoSiblingNodeNames {
+		if nodeName == sibling {
+			return true
+		}
+	}
+	return false
+}
+
 // ApplyOptions returns the apply options configured for object reflection.
 func ApplyOptions() metav1.ApplyOptions {
 	return metav1.ApplyOptions{
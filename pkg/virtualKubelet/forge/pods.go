@@ -36,6 +36,8 @@ const (
 	PodOffloadingBackoffReason = "OffloadingBackoff"
 	// PodOffloadingAbortedReason -> the reason assigned to pods rejected by the virtual kubelet after offloading has started.
 	PodOffloadingAbortedReason = "OffloadingAborted"
+	// PodOffloadingForbiddenReason -> the reason assigned to pods rejected by the provider cluster, as violating its pod security constraints.
+	PodOffloadingForbiddenReason = "OffloadingForbidden"
 
 	// ServiceAccountVolumeName is the prefix name that will be added to volumes that mount ServiceAccount secrets.
 	// This constant is taken from kubernetes/kubernetes (plugin/pkg/admission/serviceaccount/admission.go).
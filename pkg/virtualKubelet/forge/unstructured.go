@@ -0,0 +1,70 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forge
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ReverseReflectionLabels returns the labels assigned to the objects reflected from the remote to the local cluster.
+func ReverseReflectionLabels() labels.Set {
+	return map[string]string{
+		LiqoOriginClusterIDKey:      RemoteClusterID,
+		LiqoDestinationClusterIDKey: LocalClusterID,
+	}
+}
+
+// IsReverseReflected returns whether the current object has been reflected from the remote to the local cluster.
+func IsReverseReflected(obj metav1.Object) bool {
+	return ReverseReflectionLabels().AsSelectorPreValidated().Matches(labels.Set(obj.GetLabels()))
+}
+
+// ReflectedUnstructured forges the apply patch for a generic reflected object, given the source one.
+// The resulting object preserves the source labels, annotations and content (except for the status,
+// which is owned by the destination cluster), while the given ownership labels are enforced.
+func ReflectedUnstructured(source *unstructured.Unstructured, targetNamespace string, ownership labels.Set) *unstructured.Unstructured {
+	output := &unstructured.Unstructured{Object: make(map[string]interface{}, len(source.Object))}
+	for key, value := range source.Object {
+		if key == "metadata" || key == "status" {
+			continue
+		}
+		output.Object[key] = runtime.DeepCopyJSONValue(value)
+	}
+
+	output.SetAPIVersion(source.GetAPIVersion())
+	output.SetKind(source.GetKind())
+	output.SetName(source.GetName())
+	output.SetNamespace(targetNamespace)
+	output.SetLabels(labels.Merge(source.GetLabels(), ownership))
+	output.SetAnnotations(source.GetAnnotations())
+	return output
+}
+
+// ReflectedUnstructuredStatus forges the apply patch to propagate the status of the source object to the target one.
+func ReflectedUnstructuredStatus(source, target *unstructured.Unstructured) *unstructured.Unstructured {
+	output := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	output.SetAPIVersion(target.GetAPIVersion())
+	output.SetKind(target.GetKind())
+	output.SetName(target.GetName())
+	output.SetNamespace(target.GetNamespace())
+
+	if status, found := source.Object["status"]; found {
+		output.Object["status"] = runtime.DeepCopyJSONValue(status)
+	}
+	return output
+}
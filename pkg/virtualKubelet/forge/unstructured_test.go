@@ -0,0 +1,106 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package forge_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+)
+
+var _ = Describe("Unstructured forging", func() {
+	var source *unstructured.Unstructured
+
+	BeforeEach(func() {
+		forge.Init(LocalClusterID, RemoteClusterID, LiqoNodeName, LiqoNodeIP)
+		source = &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "cert-manager.io/v1",
+			"kind":       "Certificate",
+			"metadata": map[string]interface{}{
+				"name": "name", "namespace": "local-namespace", "uid": "uid", "resourceVersion": "111",
+				"labels": map[string]interface{}{"foo": "bar"}, "annotations": map[string]interface{}{"bar": "baz"},
+			},
+			"spec":   map[string]interface{}{"secretName": "secret"},
+			"status": map[string]interface{}{"ready": true},
+		}}
+	})
+
+	Describe("the ReverseReflectionLabels function", func() {
+		It("should set the origin cluster label", func() {
+			Expect(forge.ReverseReflectionLabels()).To(HaveKeyWithValue(forge.LiqoOriginClusterIDKey, RemoteClusterID))
+		})
+		It("should set the destination cluster label", func() {
+			Expect(forge.ReverseReflectionLabels()).To(HaveKeyWithValue(forge.LiqoDestinationClusterIDKey, LocalClusterID))
+		})
+	})
+
+	Describe("the IsReverseReflected function", func() {
+		It("should return false for objects reflected towards the remote cluster", func() {
+			source.SetLabels(forge.ReflectionLabels())
+			Expect(forge.IsReverseReflected(source)).To(BeFalse())
+		})
+		It("should return true for objects reflected from the remote cluster", func() {
+			source.SetLabels(forge.ReverseReflectionLabels())
+			Expect(forge.IsReverseReflected(source)).To(BeTrue())
+		})
+	})
+
+	Describe("the ReflectedUnstructured function", func() {
+		var output *unstructured.Unstructured
+
+		JustBeforeEach(func() { output = forge.ReflectedUnstructured(source, "remote-namespace", forge.ReflectionLabels()) })
+
+		It("should correctly set the type information", func() {
+			Expect(output.GetAPIVersion()).To(Equal("cert-manager.io/v1"))
+			Expect(output.GetKind()).To(Equal("Certificate"))
+		})
+		It("should correctly set the name and the namespace", func() {
+			Expect(output.GetName()).To(Equal("name"))
+			Expect(output.GetNamespace()).To(Equal("remote-namespace"))
+		})
+		It("should merge the source labels with the ownership ones", func() {
+			Expect(output.GetLabels()).To(HaveKeyWithValue("foo", "bar"))
+			Expect(output.GetLabels()).To(HaveKeyWithValue(forge.LiqoOriginClusterIDKey, LocalClusterID))
+			Expect(output.GetLabels()).To(HaveKeyWithValue(forge.LiqoDestinationClusterIDKey, RemoteClusterID))
+		})
+		It("should preserve the annotations", func() { Expect(output.GetAnnotations()).To(HaveKeyWithValue("bar", "baz")) })
+		It("should not propagate the cluster specific metadata", func() {
+			Expect(output.GetUID()).To(BeEmpty())
+			Expect(output.GetResourceVersion()).To(BeEmpty())
+		})
+		It("should propagate the spec", func() { Expect(output.Object).To(HaveKeyWithValue("spec", source.Object["spec"])) })
+		It("should not propagate the status", func() { Expect(output.Object).ToNot(HaveKey("status")) })
+	})
+
+	Describe("the ReflectedUnstructuredStatus function", func() {
+		var target, output *unstructured.Unstructured
+
+		BeforeEach(func() {
+			target = source.DeepCopy()
+			target.SetNamespace("target-namespace")
+			target.Object["status"] = map[string]interface{}{"ready": false}
+		})
+		JustBeforeEach(func() { output = forge.ReflectedUnstructuredStatus(source, target) })
+
+		It("should target the given object", func() {
+			Expect(output.GetName()).To(Equal("name"))
+			Expect(output.GetNamespace()).To(Equal("target-namespace"))
+		})
+		It("should propagate the source status", func() { Expect(output.Object).To(HaveKeyWithValue("status", source.Object["status"])) })
+		It("should not propagate the spec", func() { Expect(output.Object).ToNot(HaveKey("spec")) })
+	})
+})
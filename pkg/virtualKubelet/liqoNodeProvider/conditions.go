@@ -97,8 +97,11 @@ func nodePIDPressureStatus(pressure bool) func() (corev1.ConditionStatus, string
 }
 
 // nodeNetworkUnavailableStatus returns a function containing the condition information about the networking status.
-func nodeNetworkUnavailableStatus(unavailable bool) func() (corev1.ConditionStatus, string, string) {
+func nodeNetworkUnavailableStatus(unavailable, degraded bool) func() (corev1.ConditionStatus, string, string) {
 	return func() (status corev1.ConditionStatus, reason, message string) {
+		if unavailable && degraded {
+			return corev1.ConditionTrue, "LiqoNetworkingDegraded", "The Liqo cluster interconnection is established, but degraded"
+		}
 		if unavailable {
 			return corev1.ConditionTrue, "LiqoNetworkingDown", "The Liqo cluster interconnection is down"
 		}
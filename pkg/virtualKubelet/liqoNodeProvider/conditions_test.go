@@ -188,13 +188,19 @@ var _ = Describe("Liqo node conditions generation", func() {
 				ExpectedMessage: "The remote cluster is advertising sufficient resources",
 			}),
 			Entry("of the network unavailable condition, when set", StatusGenerationCase{
-				Generator:       nodeNetworkUnavailableStatus(true),
+				Generator:       nodeNetworkUnavailableStatus(true, false),
 				ExpectedStatus:  corev1.ConditionTrue,
 				ExpectedReason:  "LiqoNetworkingDown",
 				ExpectedMessage: "The Liqo cluster interconnection is down",
 			}),
+			Entry("of the network unavailable condition, when set due to degradation", StatusGenerationCase{
+				Generator:       nodeNetworkUnavailableStatus(true, true),
+				ExpectedStatus:  corev1.ConditionTrue,
+				ExpectedReason:  "LiqoNetworkingDegraded",
+				ExpectedMessage: "The Liqo cluster interconnection is established, but degraded",
+			}),
 			Entry("of the network unavailable condition, when unset", StatusGenerationCase{
-				Generator:       nodeNetworkUnavailableStatus(false),
+				Generator:       nodeNetworkUnavailableStatus(false, false),
 				ExpectedStatus:  corev1.ConditionFalse,
 				ExpectedReason:  "LiqoNetworkingUp",
 				ExpectedMessage: "The Liqo cluster interconnection is established",
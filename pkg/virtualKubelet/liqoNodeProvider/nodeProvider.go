@@ -32,17 +32,21 @@ type LiqoNodeProvider struct {
 	remoteDiscoveryClient discovery.DiscoveryInterface
 	dynClient             dynamic.Interface
 
-	node              *corev1.Node
-	terminating       bool
-	lastAppliedLabels map[string]string
+	node                   *corev1.Node
+	terminating            bool
+	lastAppliedLabels      map[string]string
+	lastAppliedAnnotations map[string]string
+	lastAppliedTaints      []corev1.Taint
 
 	nodeName         string
+	nodePool         string
 	foreignClusterID string
 	tenantNamespace  string
 	resyncPeriod     time.Duration
 	pingDisabled     bool
 
-	networkReady bool
+	networkReady    bool
+	networkDegraded bool
 
 	onNodeChangeCallback func(*corev1.Node)
 	updateMutex          sync.Mutex
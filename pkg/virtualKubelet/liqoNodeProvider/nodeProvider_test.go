@@ -350,6 +350,118 @@ var _ = Describe("NodeProvider", func() {
 		Expect(ok).To(BeFalse())
 	})
 
+	It("Taints patch", func() {
+		client := kubernetes.NewForConfigOrDie(cluster.GetCfg())
+		liqoTaint := v1.Taint{Key: consts.VirtualNodeTolerationKey, Value: "true", Effect: v1.TaintEffectNoExecute}
+
+		By("Add taints")
+
+		taints := []v1.Taint{
+			{Key: "test1", Value: "value1", Effect: v1.TaintEffectNoSchedule},
+			{Key: "test2", Value: "value2", Effect: v1.TaintEffectNoExecute},
+		}
+
+		err := nodeProvider.patchTaints(taints)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(nodeProvider.lastAppliedTaints).To(Equal(taints))
+
+		node, err := client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(node.Spec.Taints).To(ConsistOf(liqoTaint, taints[0], taints[1]))
+
+		By("Delete taints")
+
+		taints = []v1.Taint{
+			{Key: "test1", Value: "value3", Effect: v1.TaintEffectNoSchedule},
+		}
+
+		err = nodeProvider.patchTaints(taints)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(nodeProvider.lastAppliedTaints).To(Equal(taints))
+
+		node, err = client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(node.Spec.Taints).To(ConsistOf(liqoTaint, taints[0]))
+	})
+
+	It("Fragmentation annotations", func() {
+		resourceOffer := &sharingv1alpha1.ResourceOffer{
+			Spec: sharingv1alpha1.ResourceOfferSpec{
+				LargestAllocatable: v1.ResourceList{
+					v1.ResourceMemory: resource.MustParse("4Gi"),
+					v1.ResourceCPU:    resource.MustParse("2"),
+				},
+				Fragmentation: []sharingv1alpha1.ResourceFragmentation{{
+					Name:    v1.ResourceCPU,
+					Buckets: []sharingv1alpha1.FragmentationBucket{{Available: resource.MustParse("1"), Nodes: 3}},
+				}},
+			},
+		}
+
+		By("Forging the annotations from the ResourceOffer")
+		annotations := fragmentationAnnotations(resourceOffer, nil)
+		Expect(annotations).To(HaveKeyWithValue(consts.LargestAllocatableAnnotation, "cpu=2,memory=4Gi"))
+		Expect(annotations).To(HaveKeyWithValue(consts.FragmentationAnnotation,
+			`[{"name":"cpu","buckets":[{"available":"1","nodes":3}]}]`))
+
+		By("Forging the annotations from the node pool")
+		pool := &sharingv1alpha1.NodePool{MaxAllocatablePerNode: v1.ResourceList{v1.ResourceCPU: resource.MustParse("500m")}}
+		annotations = fragmentationAnnotations(resourceOffer, pool)
+		Expect(annotations).To(HaveKeyWithValue(consts.LargestAllocatableAnnotation, "cpu=500m"))
+		Expect(annotations).ToNot(HaveKey(consts.FragmentationAnnotation))
+
+		By("Patching the node")
+		Expect(nodeProvider.patchAnnotations(annotations)).To(Succeed())
+		client := kubernetes.NewForConfigOrDie(cluster.GetCfg())
+		node, err := client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(node.GetAnnotations()).To(HaveKeyWithValue(consts.LargestAllocatableAnnotation, "cpu=500m"))
+	})
+
+	It("Unaccounted resource usage", func() {
+		client := kubernetes.NewForConfigOrDie(cluster.GetCfg())
+		used := v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("2"),
+			v1.ResourceMemory: resource.MustParse("2Gi"),
+		}
+
+		By("Computing the usage with no pods running on the virtual node")
+		unaccounted, err := nodeProvider.unaccountedUsage(ctx, used)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(unaccounted.Cpu().Equal(resource.MustParse("2"))).To(BeTrue())
+		Expect(unaccounted.Memory().Equal(resource.MustParse("2Gi"))).To(BeTrue())
+
+		By("Computing the usage with a pod running on the virtual node")
+		pod := &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default"},
+			Spec: v1.PodSpec{
+				NodeName: nodeName,
+				Containers: []v1.Container{{
+					Name: "container", Image: "nginx",
+					Resources: v1.ResourceRequirements{Requests: v1.ResourceList{
+						v1.ResourceCPU:    resource.MustParse("500m"),
+						v1.ResourceMemory: resource.MustParse("4Gi"),
+					}},
+				}},
+			},
+		}
+		_, err = client.CoreV1().Pods("default").Create(ctx, pod, metav1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+
+		unaccounted, err = nodeProvider.unaccountedUsage(ctx, used)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(unaccounted.Cpu().Equal(resource.MustParse("1500m"))).To(BeTrue())
+		Expect(unaccounted.Memory().IsZero()).To(BeTrue())
+
+		By("Subtracting the unaccounted usage from the offered resources")
+		allocatable := subtractResources(v1.ResourceList{
+			v1.ResourceCPU:    resource.MustParse("4"),
+			v1.ResourceMemory: resource.MustParse("8Gi"),
+		}, unaccounted)
+		Expect(allocatable.Cpu().Equal(resource.MustParse("2500m"))).To(BeTrue())
+		Expect(allocatable.Memory().Equal(resource.MustParse("8Gi"))).To(BeTrue())
+	})
+
 	Context("Node Cleanup", func() {
 
 		It("Cordon Node", func() {
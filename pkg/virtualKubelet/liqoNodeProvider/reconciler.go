@@ -18,7 +18,10 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"reflect"
+	"sort"
+	"strings"
 
 	"gomodules.xyz/jsonpatch/v2"
 	v1 "k8s.io/api/core/v1"
@@ -28,6 +31,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/klog/v2"
+	resourcehelper "k8s.io/kubectl/pkg/util/resource"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -35,6 +39,7 @@ import (
 	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
 	"github.com/liqotech/liqo/pkg/consts"
 	"github.com/liqotech/liqo/pkg/utils"
+	"github.com/liqotech/liqo/pkg/virtualKubelet"
 )
 
 func isResourceOfferTerminating(resourceOffer *sharingv1alpha1.ResourceOffer) bool {
@@ -56,7 +61,7 @@ func (p *LiqoNodeProvider) reconcileNodeFromResourceOffer(event watch.Event) err
 		return err
 	}
 
-	if event.Type == watch.Deleted || isResourceOfferTerminating(&resourceOffer) {
+	if event.Type == watch.Deleted || isResourceOfferTerminating(&resourceOffer) || !p.isNodePoolDesired(&resourceOffer) {
 		p.updateMutex.Lock()
 		defer p.updateMutex.Unlock()
 		klog.Infof("resourceOffer %v is going to be deleted... set node status not ready", resourceOffer.Name)
@@ -82,7 +87,7 @@ func (p *LiqoNodeProvider) reconcileNodeFromResourceOffer(event watch.Event) err
 	}
 
 	if err := p.ensureFinalizer(&resourceOffer, func() bool {
-		return !controllerutil.ContainsFinalizer(&resourceOffer, consts.NodeFinalizer)
+		return !controllerutil.ContainsFinalizer(&resourceOffer, p.finalizer())
 	}, controllerutil.AddFinalizer); err != nil {
 		klog.Error(err)
 		return err
@@ -107,7 +112,7 @@ func (p *LiqoNodeProvider) ensureFinalizer(resourceOffer *sharingv1alpha1.Resour
 			return err
 		}
 
-		changeFinalizer(resourceOffer, consts.NodeFinalizer)
+		changeFinalizer(resourceOffer, p.finalizer())
 
 		target, err := json.Marshal(resourceOffer)
 		if err != nil {
@@ -153,6 +158,7 @@ func (p *LiqoNodeProvider) reconcileNodeFromTep(event watch.Event) error {
 		defer p.updateMutex.Unlock()
 		klog.Infof("tunnelEndpoint %v deleted", tep.Name)
 		p.networkReady = false
+		p.networkDegraded = false
 		err := p.updateNode()
 		if err != nil {
 			klog.Error(err)
@@ -183,20 +189,55 @@ func (p *LiqoNodeProvider) updateFromResourceOffer(resourceOffer *sharingv1alpha
 		lbls[consts.StorageAvailableLabel] = "true"
 	}
 
+	resources := resourceOffer.Spec.ResourceQuota.Hard
+	var taints []v1.Taint
+	pool := nodePoolFromResourceOffer(resourceOffer, p.nodePool)
+	if pool != nil {
+		lbls = utils.MergeMaps(lbls, pool.Labels)
+		resources = pool.Resources
+		taints = pool.Taints
+	}
+
 	if err := p.patchLabels(lbls); err != nil {
 		klog.Error(err)
 		return err
 	}
 
+	annotations := fragmentationAnnotations(resourceOffer, pool)
+	if len(resourceOffer.Status.Used) > 0 {
+		annotations[consts.ResourceUsageAnnotation] = stringifyResources(resourceOffer.Status.Used)
+	}
+	if err := p.patchAnnotations(annotations); err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	if err := p.patchTaints(taints); err != nil {
+		klog.Error(err)
+		return err
+	}
+
 	if p.node.Status.Capacity == nil {
 		p.node.Status.Capacity = v1.ResourceList{}
 	}
 	if p.node.Status.Allocatable == nil {
 		p.node.Status.Allocatable = v1.ResourceList{}
 	}
-	for k, v := range resourceOffer.Spec.ResourceQuota.Hard {
+	allocatable := resources
+	if pool == nil {
+		// The resources used in the remote cluster, but not accounted for by the pods running on the virtual node
+		// (e.g., following a restart, or due to out-of-band changes), are not considered as allocatable.
+		unaccounted, err := p.unaccountedUsage(context.TODO(), resourceOffer.Status.Used)
+		if err != nil {
+			klog.Errorf("error computing the resources used in the remote cluster: %v", err)
+			return err
+		}
+		allocatable = subtractResources(resources, unaccounted)
+	}
+
+	for k, v := range resources {
 		p.node.Status.Capacity[k] = v
-		p.node.Status.Allocatable[k] = v
+		p.node.Status.Allocatable[k] = allocatable[k]
 	}
 
 	p.node.Status.Images = []v1.ContainerImage{}
@@ -209,6 +250,9 @@ func (p *LiqoNodeProvider) updateFromTep(tep *netv1alpha1.TunnelEndpoint) error
 	p.updateMutex.Lock()
 	defer p.updateMutex.Unlock()
 
+	// a degraded tunnel (i.e., exceeding the health probes thresholds) is considered as unavailable.
+	p.networkDegraded = tep.Status.Connection.Status == netv1alpha1.ConnectionDegraded
+
 	// if tep is not connected yet, return
 	if tep.Status.Connection.Status != netv1alpha1.Connected {
 		p.networkReady = false
@@ -219,13 +263,14 @@ func (p *LiqoNodeProvider) updateFromTep(tep *netv1alpha1.TunnelEndpoint) error
 }
 
 func (p *LiqoNodeProvider) updateNode() error {
-	resourcesReady := areResourcesReady(p.node.Status.Allocatable)
+	// The readiness depends on the offered resources, regardless of whether they are currently used or not.
+	resourcesReady := areResourcesReady(p.node.Status.Capacity)
 
 	UpdateNodeCondition(p.node, v1.NodeReady, nodeReadyStatus(resourcesReady && p.networkReady))
 	UpdateNodeCondition(p.node, v1.NodeMemoryPressure, nodeMemoryPressureStatus(!resourcesReady))
 	UpdateNodeCondition(p.node, v1.NodeDiskPressure, nodeDiskPressureStatus(!resourcesReady))
 	UpdateNodeCondition(p.node, v1.NodePIDPressure, nodePIDPressureStatus(!resourcesReady))
-	UpdateNodeCondition(p.node, v1.NodeNetworkUnavailable, nodeNetworkUnavailableStatus(!p.networkReady))
+	UpdateNodeCondition(p.node, v1.NodeNetworkUnavailable, nodeNetworkUnavailableStatus(!p.networkReady, p.networkDegraded))
 
 	p.onNodeChangeCallback(p.node.DeepCopy())
 	return nil
@@ -252,7 +297,7 @@ func (p *LiqoNodeProvider) handleResourceOfferDelete(resourceOffer *sharingv1alp
 
 	// remove the finalizer
 	if err := p.ensureFinalizer(resourceOffer, func() bool {
-		return controllerutil.ContainsFinalizer(resourceOffer, consts.NodeFinalizer)
+		return controllerutil.ContainsFinalizer(resourceOffer, p.finalizer())
 	}, controllerutil.RemoveFinalizer); err != nil {
 		klog.Errorf("error removing finalizer from resource offer %v/%v: %v", resourceOffer.GetNamespace(), resourceOffer.GetName(), err)
 		return err
@@ -284,6 +329,174 @@ func (p *LiqoNodeProvider) patchLabels(labels map[string]string) error {
 	return nil
 }
 
+func (p *LiqoNodeProvider) patchAnnotations(annotations map[string]string) error {
+	if reflect.DeepEqual(annotations, p.lastAppliedAnnotations) {
+		return nil
+	}
+
+	if err := p.patchNode(func(node *v1.Node) error {
+		nodeAnnotations := node.GetAnnotations()
+		nodeAnnotations = utils.SubMaps(nodeAnnotations, p.lastAppliedAnnotations)
+		nodeAnnotations = utils.MergeMaps(nodeAnnotations, annotations)
+		node.Annotations = nodeAnnotations
+		return nil
+	}); err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	p.lastAppliedAnnotations = annotations
+	return nil
+}
+
+func (p *LiqoNodeProvider) patchTaints(taints []v1.Taint) error {
+	if (len(taints) == 0 && len(p.lastAppliedTaints) == 0) || reflect.DeepEqual(taints, p.lastAppliedTaints) {
+		return nil
+	}
+
+	if err := p.patchNode(func(node *v1.Node) error {
+		// Remove the previously applied taints, and then add the current ones (preserving the ones not managed here).
+		nodeTaints := []v1.Taint{}
+		for i := range node.Spec.Taints {
+			if !containsTaint(p.lastAppliedTaints, &node.Spec.Taints[i]) {
+				nodeTaints = append(nodeTaints, node.Spec.Taints[i])
+			}
+		}
+		for i := range taints {
+			if !containsTaint(nodeTaints, &taints[i]) {
+				nodeTaints = append(nodeTaints, taints[i])
+			}
+		}
+		node.Spec.Taints = nodeTaints
+		return nil
+	}); err != nil {
+		klog.Error(err)
+		return err
+	}
+
+	p.lastAppliedTaints = taints
+	return nil
+}
+
+// unaccountedUsage returns the resources used in the remote cluster (as reported by the ResourceOffer),
+// exceeding the ones requested by the pods running on the virtual node.
+func (p *LiqoNodeProvider) unaccountedUsage(ctx context.Context, used v1.ResourceList) (v1.ResourceList, error) {
+	if len(used) == 0 {
+		return v1.ResourceList{}, nil
+	}
+
+	podList, err := p.getPodsForDeletion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	requested := v1.ResourceList{}
+	for i := range podList.Items {
+		if podList.Items[i].Status.Phase == v1.PodSucceeded || podList.Items[i].Status.Phase == v1.PodFailed {
+			continue
+		}
+		requests, _ := resourcehelper.PodRequestsAndLimits(&podList.Items[i])
+		for name, quantity := range requests {
+			total := requested[name]
+			total.Add(quantity)
+			requested[name] = total
+		}
+	}
+	return subtractResources(used, requested), nil
+}
+
+// finalizer returns the finalizer added by the current virtual kubelet on the ResourceOffer.
+func (p *LiqoNodeProvider) finalizer() string {
+	if p.nodePool == "" {
+		return consts.NodeFinalizer
+	}
+	return virtualKubelet.NodePoolFinalizer(p.nodePool)
+}
+
+// isNodePoolDesired returns whether the ResourceOffer still requires the virtual node managed by the current provider.
+// A virtual node associated with a node pool is no longer desired if the pool is not advertised anymore, while a
+// virtual node not associated with any pool is no longer desired if the ResourceOffer started advertising node pools.
+func (p *LiqoNodeProvider) isNodePoolDesired(resourceOffer *sharingv1alpha1.ResourceOffer) bool {
+	if p.nodePool == "" {
+		return len(resourceOffer.Spec.NodePools) == 0
+	}
+	return nodePoolFromResourceOffer(resourceOffer, p.nodePool) != nil
+}
+
+// nodePoolFromResourceOffer returns the node pool with the given name, or nil if not found.
+func nodePoolFromResourceOffer(resourceOffer *sharingv1alpha1.ResourceOffer, pool string) *sharingv1alpha1.NodePool {
+	if pool == "" {
+		return nil
+	}
+	for i := range resourceOffer.Spec.NodePools {
+		if resourceOffer.Spec.NodePools[i].Name == pool {
+			return &resourceOffer.Spec.NodePools[i]
+		}
+	}
+	return nil
+}
+
+// fragmentationAnnotations returns the annotations advertising how the resources are distributed across the nodes
+// of the remote cluster. In case of node pools, only the largest quantities allocatable on a single node are advertised.
+func fragmentationAnnotations(resourceOffer *sharingv1alpha1.ResourceOffer, pool *sharingv1alpha1.NodePool) map[string]string {
+	largest := resourceOffer.Spec.LargestAllocatable
+	fragmentation := resourceOffer.Spec.Fragmentation
+	if pool != nil {
+		largest = pool.MaxAllocatablePerNode
+		fragmentation = nil
+	}
+
+	annotations := map[string]string{}
+	if len(largest) > 0 {
+		annotations[consts.LargestAllocatableAnnotation] = stringifyResources(largest)
+	}
+
+	if len(fragmentation) > 0 {
+		bytes, err := json.Marshal(fragmentation)
+		if err != nil {
+			klog.Errorf("Failed to marshal the resource fragmentation information: %v", err)
+		} else {
+			annotations[consts.FragmentationAnnotation] = string(bytes)
+		}
+	}
+	return annotations
+}
+
+// stringifyResources returns the given resources in the form "cpu=2,memory=4Gi" (sorted by resource name).
+func stringifyResources(resources v1.ResourceList) string {
+	entries := make([]string, 0, len(resources))
+	for name, quantity := range resources {
+		entries = append(entries, fmt.Sprintf("%s=%s", name, quantity.String()))
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
+
+// subtractResources returns the difference between the given resources, clamping the negative quantities to zero.
+func subtractResources(resources, toSubtract v1.ResourceList) v1.ResourceList {
+	result := resources.DeepCopy()
+	for name, quantity := range result {
+		if sub, found := toSubtract[name]; found {
+			quantity.Sub(sub)
+			if quantity.Sign() < 0 {
+				quantity.Set(0)
+			}
+			result[name] = quantity
+		}
+	}
+	return result
+}
+
+// containsTaint returns whether the given taint (matched by key and effect) is present in the list.
+func containsTaint(taints []v1.Taint, taint *v1.Taint) bool {
+	for i := range taints {
+		if taints[i].MatchTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
+
 // areResourcesReady returns true if both cpu and memory are more than zero.
 func areResourcesReady(allocatable v1.ResourceList) bool {
 	if allocatable == nil {
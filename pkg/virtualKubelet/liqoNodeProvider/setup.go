@@ -28,6 +28,7 @@ import (
 	"k8s.io/client-go/rest"
 
 	liqoconst "github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/virtualKubelet"
 )
 
 const (
@@ -48,6 +49,7 @@ type InitConfig struct {
 	Namespace       string
 
 	NodeName         string
+	NodePool         string
 	InternalIP       string
 	DaemonPort       uint16
 	Version          string
@@ -66,15 +68,17 @@ func NewLiqoNodeProvider(cfg *InitConfig) *LiqoNodeProvider {
 		remoteDiscoveryClient: discovery.NewDiscoveryClientForConfigOrDie(cfg.RemoteConfig),
 		dynClient:             dynamic.NewForConfigOrDie(cfg.HomeConfig),
 
-		node:              node(cfg),
-		terminating:       false,
-		lastAppliedLabels: map[string]string{},
+		node:                   node(cfg),
+		terminating:            false,
+		lastAppliedLabels:      map[string]string{},
+		lastAppliedAnnotations: map[string]string{},
 
 		networkReady: false,
 		resyncPeriod: cfg.InformerResyncPeriod,
 		pingDisabled: cfg.PingDisabled,
 
 		nodeName:         cfg.NodeName,
+		nodePool:         cfg.NodePool,
 		foreignClusterID: cfg.RemoteClusterID,
 		tenantNamespace:  cfg.Namespace,
 	}
@@ -94,6 +98,9 @@ func node(cfg *InitConfig) *corev1.Node {
 		corev1.LabelNodeExcludeBalancers: strconv.FormatBool(true),
 		labelNodeExcludeBalancersAlpha:   strconv.FormatBool(true),
 	}
+	if cfg.NodePool != "" {
+		lbls[liqoconst.NodePoolLabel] = virtualKubelet.NodePoolSuffix(cfg.NodePool)
+	}
 
 	return &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
@@ -21,6 +21,7 @@ import (
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
@@ -33,6 +34,7 @@ import (
 	"github.com/liqotech/liqo/pkg/liqonet/ipam"
 	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
 	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/configuration"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/customresource"
 	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/exposition"
 	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/manager"
 	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/namespacemap"
@@ -64,6 +66,15 @@ type InitConfig struct {
 	PersistenVolumeClaimWorkers uint
 	ConfigMapWorkers            uint
 	SecretWorkers               uint
+	CustomResourceWorkers       uint
+
+	CustomResourceReflections []customresource.ResourceReflection
+
+	// SiblingNodeNames are the names of the other virtual nodes associated with the same remote cluster.
+	SiblingNodeNames []string
+	// PodReflectionOnly specifies whether only the pods shall be reflected, since the other resources are
+	// already managed by the virtual kubelet associated with a sibling virtual node.
+	PodReflectionOnly bool
 
 	EnableStorage              bool
 	VirtualStorageClassName    string
@@ -79,11 +90,14 @@ type LiqoProvider struct {
 // NewLiqoProvider creates a new NewLiqoProvider instance.
 func NewLiqoProvider(ctx context.Context, cfg *InitConfig, eb record.EventBroadcaster) (*LiqoProvider, error) {
 	forge.Init(cfg.HomeCluster.ClusterID, cfg.RemoteCluster.ClusterID, cfg.NodeName, cfg.NodeIP)
+	forge.InitSiblingNodes(cfg.SiblingNodeNames...)
 	homeClient := kubernetes.NewForConfigOrDie(cfg.HomeConfig)
 	homeLiqoClient := liqoclient.NewForConfigOrDie(cfg.HomeConfig)
+	homeDynamicClient := dynamic.NewForConfigOrDie(cfg.HomeConfig)
 
 	foreignClient := kubernetes.NewForConfigOrDie(cfg.RemoteConfig)
 	foreignLiqoClient := liqoclient.NewForConfigOrDie(cfg.RemoteConfig)
+	foreignDynamicClient := dynamic.NewForConfigOrDie(cfg.RemoteConfig)
 	foreignMetricsClient := metrics.NewForConfigOrDie(cfg.RemoteConfig)
 
 	dialctx, cancel := context.WithTimeout(ctx, 10*time.Second)
@@ -94,19 +108,32 @@ func NewLiqoProvider(ctx context.Context, cfg *InitConfig, eb record.EventBroadc
 	}
 	ipamClient := ipam.NewIpamClient(connection)
 
-	reflectionManager := manager.New(homeClient, foreignClient, homeLiqoClient, foreignLiqoClient, cfg.InformerResyncPeriod, eb)
+	reflectionManager := manager.New(homeClient, foreignClient, homeLiqoClient, foreignLiqoClient,
+		homeDynamicClient, foreignDynamicClient, cfg.InformerResyncPeriod, eb)
 	podreflector := workload.NewPodReflector(cfg.RemoteConfig, foreignMetricsClient.MetricsV1beta1().PodMetricses, ipamClient, cfg.PodWorkers)
 	namespaceMapHandler := namespacemap.NewHandler(homeLiqoClient, cfg.Namespace, cfg.InformerResyncPeriod)
-	reflectionManager.
-		With(exposition.NewServiceReflector(cfg.ServiceWorkers)).
-		With(exposition.NewEndpointSliceReflector(ipamClient, cfg.EndpointSliceWorkers)).
-		With(exposition.NewIngressReflector(cfg.IngressWorkers)).
-		With(configuration.NewConfigMapReflector(cfg.ConfigMapWorkers)).
-		With(configuration.NewSecretReflector(cfg.SecretWorkers)).
-		With(podreflector).
-		With(storage.NewPersistentVolumeClaimReflector(cfg.PersistenVolumeClaimWorkers,
-			cfg.VirtualStorageClassName, cfg.RemoteRealStorageClassName, cfg.EnableStorage)).
-		WithNamespaceHandler(namespaceMapHandler)
+	reflectionManager.With(podreflector).WithNamespaceHandler(namespaceMapHandler)
+
+	// The remaining resources are reflected only once per remote cluster, by the virtual kubelet of the primary virtual node.
+	if !cfg.PodReflectionOnly {
+		reflectionManager.
+			With(exposition.NewServiceReflector(cfg.ServiceWorkers)).
+			With(exposition.NewEndpointSliceReflector(ipamClient, cfg.EndpointSliceWorkers)).
+			With(exposition.NewIngressReflector(cfg.IngressWorkers)).
+			With(configuration.NewConfigMapReflector(cfg.ConfigMapWorkers)).
+			With(configuration.NewSecretReflector(cfg.SecretWorkers)).
+			With(storage.NewPersistentVolumeClaimReflector(cfg.PersistenVolumeClaimWorkers,
+				cfg.VirtualStorageClassName, cfg.RemoteRealStorageClassName, cfg.EnableStorage))
+
+		customResourceReflections, err := customresource.FilterAvailable(homeClient.Discovery(), foreignClient.Discovery(),
+			cfg.CustomResourceReflections)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to check the availability of the custom resources to be reflected")
+		}
+		for i := range customResourceReflections {
+			reflectionManager.With(customresource.NewCustomResourceReflector(&customResourceReflections[i], cfg.CustomResourceWorkers))
+		}
+	}
 
 	reflectionManager.Start(ctx)
 
@@ -19,6 +19,7 @@ import (
 
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	corev1clients "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -83,6 +84,18 @@ func (ncr *NamespacedConfigMapReflector) RemoteRef(name string) klog.ObjectRef {
 	return klog.KRef(ncr.RemoteNamespace(), forge.RemoteConfigMapName(name))
 }
 
+// Keys returns the keys of the local ConfigMaps, to re-enqueue them when the reflection policies change.
+func (ncr *NamespacedConfigMapReflector) Keys() []types.NamespacedName {
+	objects, err := ncr.localConfigMaps.List(labels.Everything())
+	utilruntime.Must(err)
+
+	keys := make([]types.NamespacedName, 0, len(objects))
+	for _, object := range objects {
+		keys = append(keys, types.NamespacedName{Namespace: ncr.LocalNamespace(), Name: object.GetName()})
+	}
+	return keys
+}
+
 // Handle is responsible for reconciling the given object and ensuring it is correctly reflected.
 func (ncr *NamespacedConfigMapReflector) Handle(ctx context.Context, name string) error {
 	tracer := trace.FromContext(ctx)
@@ -104,6 +117,7 @@ func (ncr *NamespacedConfigMapReflector) Handle(ctx context.Context, name string
 	tracer.Step("Performed the sanity checks")
 
 	if kerrors.IsNotFound(lerr) {
+		ncr.ForgetPolicyDecision(name)
 		defer tracer.Step("Ensured the absence of the remote object")
 		if !kerrors.IsNotFound(rerr) {
 			klog.V(4).Infof("Deleting remote ConfigMap %q, since local %q does no longer exist", ncr.RemoteRef(name), ncr.LocalRef(name))
@@ -114,8 +128,26 @@ func (ncr *NamespacedConfigMapReflector) Handle(ctx context.Context, name string
 		return nil
 	}
 
+	// Evaluate the reflection policies, and ensure the absence of the remote object if the local one shall not be reflected.
+	decision := ncr.EvaluatePolicy(local)
+	if decision.Skip {
+		defer tracer.Step("Ensured the absence of the remote object")
+		if !kerrors.IsNotFound(rerr) {
+			klog.V(4).Infof("Deleting remote ConfigMap %q, since local %q shall not be reflected (%v)",
+				ncr.RemoteRef(name), ncr.LocalRef(name), decision.Reason)
+			return ncr.DeleteRemote(ctx, ncr.remoteConfigMapsClient, ConfigMapReflectorName, remote.GetName(), remote.GetUID())
+		}
+
+		klog.V(4).Infof("Skipping reflection of local ConfigMap %q, as required by %v", ncr.LocalRef(name), decision.Reason)
+		return nil
+	}
+	tracer.Step("Evaluated the reflection policies")
+
 	// Forge the mutation to be applied to the remote cluster.
 	mutation := forge.RemoteConfigMap(local, ncr.RemoteNamespace())
+	decision.MutateObjectMeta(mutation.ObjectMetaApplyConfiguration)
+	decision.StripStringData(mutation.Data)
+	decision.StripBinaryData(mutation.BinaryData)
 	tracer.Step("Remote mutation created")
 
 	defer tracer.Step("Enforced the correctness of the remote object")
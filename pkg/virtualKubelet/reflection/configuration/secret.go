@@ -18,6 +18,8 @@ import (
 	"context"
 
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	corev1clients "k8s.io/client-go/kubernetes/typed/core/v1"
 	corev1listers "k8s.io/client-go/listers/core/v1"
@@ -68,6 +70,18 @@ func NewNamespacedSecretReflector(opts *options.NamespacedOpts) manager.Namespac
 	}
 }
 
+// Keys returns the keys of the local Secrets, to re-enqueue them when the reflection policies change.
+func (nsr *NamespacedSecretReflector) Keys() []types.NamespacedName {
+	objects, err := nsr.localSecrets.List(labels.Everything())
+	utilruntime.Must(err)
+
+	keys := make([]types.NamespacedName, 0, len(objects))
+	for _, object := range objects {
+		keys = append(keys, types.NamespacedName{Namespace: nsr.LocalNamespace(), Name: object.GetName()})
+	}
+	return keys
+}
+
 // Handle is responsible for reconciling the given object and ensuring it is correctly reflected.
 func (nsr *NamespacedSecretReflector) Handle(ctx context.Context, name string) error {
 	tracer := trace.FromContext(ctx)
@@ -89,6 +103,7 @@ func (nsr *NamespacedSecretReflector) Handle(ctx context.Context, name string) e
 	tracer.Step("Performed the sanity checks")
 
 	if kerrors.IsNotFound(lerr) {
+		nsr.ForgetPolicyDecision(name)
 		defer tracer.Step("Ensured the absence of the remote object")
 		if !kerrors.IsNotFound(rerr) {
 			klog.V(4).Infof("Deleting remote Secret %q, since local %q does no longer exist", nsr.RemoteRef(name), nsr.LocalRef(name))
@@ -99,8 +114,25 @@ func (nsr *NamespacedSecretReflector) Handle(ctx context.Context, name string) e
 		return nil
 	}
 
+	// Evaluate the reflection policies, and ensure the absence of the remote object if the local one shall not be reflected.
+	decision := nsr.EvaluatePolicy(local)
+	if decision.Skip {
+		defer tracer.Step("Ensured the absence of the remote object")
+		if !kerrors.IsNotFound(rerr) {
+			klog.V(4).Infof("Deleting remote Secret %q, since local %q shall not be reflected (%v)",
+				nsr.RemoteRef(name), nsr.LocalRef(name), decision.Reason)
+			return nsr.DeleteRemote(ctx, nsr.remoteSecretsClient, SecretReflectorName, name, remote.GetUID())
+		}
+
+		klog.V(4).Infof("Skipping reflection of local Secret %q, as required by %v", nsr.LocalRef(name), decision.Reason)
+		return nil
+	}
+	tracer.Step("Evaluated the reflection policies")
+
 	// Forge the mutation to be applied to the remote cluster.
 	mutation := forge.RemoteSecret(local, nsr.RemoteNamespace())
+	decision.MutateObjectMeta(mutation.ObjectMetaApplyConfiguration)
+	decision.StripBinaryData(mutation.Data)
 	tracer.Step("Remote mutation created")
 
 	defer tracer.Step("Enforced the correctness of the remote object")
@@ -0,0 +1,131 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package customresource
+
+import (
+	"fmt"
+	"strings"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/klog/v2"
+)
+
+// Direction identifies the direction of the reflection of a given resource.
+type Direction string
+
+const (
+	// LocalToRemote reflects the objects from the local to the remote cluster (status excluded).
+	LocalToRemote Direction = "LocalToRemote"
+	// RemoteToLocal reflects the objects from the remote to the local cluster (status excluded).
+	RemoteToLocal Direction = "RemoteToLocal"
+	// StatusBackPropagation reflects the objects from the local to the remote cluster,
+	// and propagates back the status of the remote objects to the local ones.
+	StatusBackPropagation Direction = "StatusBackPropagation"
+)
+
+// ResourceReflection represents the reflection configuration of a given resource.
+type ResourceReflection struct {
+	GroupVersionResource schema.GroupVersionResource
+	Direction            Direction
+}
+
+// String returns the stringified ResourceReflection.
+func (rr ResourceReflection) String() string {
+	return fmt.Sprintf("%v=%v", rr.GroupVersionResource.String(), rr.Direction)
+}
+
+// ParseResourceReflection parses a ResourceReflection in the form "resource.version.group[=direction]"
+// (e.g., "certificates.v1.cert-manager.io=StatusBackPropagation"). The direction defaults to LocalToRemote.
+func ParseResourceReflection(value string) (ResourceReflection, error) {
+	resource, direction, found := strings.Cut(value, "=")
+	if !found {
+		direction = string(LocalToRemote)
+	}
+
+	gvr, _ := schema.ParseResourceArg(resource)
+	if gvr == nil || gvr.Resource == "" || gvr.Version == "" {
+		return ResourceReflection{}, fmt.Errorf("invalid resource %q: expected format is resource.version.group", resource)
+	}
+
+	switch Direction(direction) {
+	case LocalToRemote, RemoteToLocal, StatusBackPropagation:
+	default:
+		return ResourceReflection{}, fmt.Errorf("invalid reflection direction %q for resource %q: allowed values are %v, %v and %v",
+			direction, resource, LocalToRemote, RemoteToLocal, StatusBackPropagation)
+	}
+
+	return ResourceReflection{GroupVersionResource: *gvr, Direction: Direction(direction)}, nil
+}
+
+// ParseResourceReflections parses a list of ResourceReflections, as described in ParseResourceReflection.
+func ParseResourceReflections(values []string) ([]ResourceReflection, error) {
+	reflections := make([]ResourceReflection, 0, len(values))
+	for _, value := range values {
+		reflection, err := ParseResourceReflection(value)
+		if err != nil {
+			return nil, err
+		}
+		reflections = append(reflections, reflection)
+	}
+	return reflections, nil
+}
+
+// FilterAvailable returns the ResourceReflections whose resource is served by both the local and the remote cluster.
+// The other ones are skipped, since the corresponding reflectors would never become ready.
+func FilterAvailable(local, remote discovery.ServerResourcesInterface, reflections []ResourceReflection) ([]ResourceReflection, error) {
+	available := make([]ResourceReflection, 0, len(reflections))
+	for _, reflection := range reflections {
+		found, err := isServed(local, reflection.GroupVersionResource)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check whether %v is served by the local cluster: %w", reflection.GroupVersionResource, err)
+		}
+		if !found {
+			klog.Warningf("Skipping the reflection of %v, as not served by the local cluster", reflection.GroupVersionResource)
+			continue
+		}
+
+		found, err = isServed(remote, reflection.GroupVersionResource)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check whether %v is served by the remote cluster: %w", reflection.GroupVersionResource, err)
+		}
+		if !found {
+			klog.Warningf("Skipping the reflection of %v, as not served by the remote cluster", reflection.GroupVersionResource)
+			continue
+		}
+
+		available = append(available, reflection)
+	}
+	return available, nil
+}
+
+// isServed returns whether the given resource is served by the cluster the discovery client refers to.
+func isServed(cl discovery.ServerResourcesInterface, gvr schema.GroupVersionResource) (bool, error) {
+	resources, err := cl.ServerResourcesForGroupVersion(gvr.GroupVersion().String())
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	for i := range resources.APIResources {
+		if resources.APIResources[i].Name == gvr.Resource {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package customresource_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/customresource"
+)
+
+var _ = Describe("Configuration parsing", func() {
+	certificates := schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+
+	DescribeTable("the ParseResourceReflection function",
+		func(value string, expected customresource.ResourceReflection, shouldFail bool) {
+			reflection, err := customresource.ParseResourceReflection(value)
+			if shouldFail {
+				Expect(err).To(HaveOccurred())
+				return
+			}
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reflection).To(Equal(expected))
+		},
+		Entry("when the direction is not specified", "certificates.v1.cert-manager.io",
+			customresource.ResourceReflection{GroupVersionResource: certificates, Direction: customresource.LocalToRemote}, false),
+		Entry("when the direction is RemoteToLocal", "certificates.v1.cert-manager.io=RemoteToLocal",
+			customresource.ResourceReflection{GroupVersionResource: certificates, Direction: customresource.RemoteToLocal}, false),
+		Entry("when the direction is StatusBackPropagation", "certificates.v1.cert-manager.io=StatusBackPropagation",
+			customresource.ResourceReflection{GroupVersionResource: certificates, Direction: customresource.StatusBackPropagation}, false),
+		Entry("when the direction is invalid", "certificates.v1.cert-manager.io=Invalid", customresource.ResourceReflection{}, true),
+		Entry("when the version is missing", "certificates", customresource.ResourceReflection{}, true),
+		Entry("when the resource is empty", "", customresource.ResourceReflection{}, true),
+	)
+
+	Describe("the ParseResourceReflections function", func() {
+		It("should parse all the given values", func() {
+			reflections, err := customresource.ParseResourceReflections([]string{
+				"certificates.v1.cert-manager.io", "servicemonitors.v1.monitoring.coreos.com=RemoteToLocal"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(reflections).To(HaveLen(2))
+			Expect(reflections[1].GroupVersionResource.Resource).To(Equal("servicemonitors"))
+			Expect(reflections[1].Direction).To(Equal(customresource.RemoteToLocal))
+		})
+		It("should fail if any value is invalid", func() {
+			_, err := customresource.ParseResourceReflections([]string{"certificates.v1.cert-manager.io", "foo"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("the FilterAvailable function", func() {
+		Discovery := func(resources ...string) *fakediscovery.FakeDiscovery {
+			discovery := fake.NewSimpleClientset().Discovery().(*fakediscovery.FakeDiscovery)
+			list := &metav1.APIResourceList{GroupVersion: certificates.GroupVersion().String()}
+			for _, resource := range resources {
+				list.APIResources = append(list.APIResources, metav1.APIResource{Name: resource, Namespaced: true})
+			}
+			discovery.Resources = []*metav1.APIResourceList{list}
+			return discovery
+		}
+
+		reflections := []customresource.ResourceReflection{
+			{GroupVersionResource: certificates, Direction: customresource.LocalToRemote},
+			{GroupVersionResource: certificates.GroupVersion().WithResource("issuers"), Direction: customresource.LocalToRemote},
+			{GroupVersionResource: schema.GroupVersionResource{Group: "monitoring.coreos.com", Version: "v1", Resource: "servicemonitors"}},
+		}
+
+		It("should return only the resources served by both clusters", func() {
+			available, err := customresource.FilterAvailable(Discovery("certificates", "issuers"), Discovery("certificates"), reflections)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(available).To(ConsistOf(reflections[0]))
+		})
+	})
+})
@@ -0,0 +1,263 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package customresource
+
+import (
+	"context"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/trace"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/generic"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/manager"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/options"
+)
+
+const (
+	// ReflectionConflictReason is the reason of the event emitted in case the destination object is not managed by Liqo.
+	ReflectionConflictReason = "ReflectionConflict"
+)
+
+var _ manager.NamespacedReflector = (*NamespacedCustomResourceReflector)(nil)
+
+// NamespacedCustomResourceReflector manages the reflection of a generic resource for a given pair of local and remote namespaces.
+type NamespacedCustomResourceReflector struct {
+	generic.NamespacedReflector
+
+	resource  string
+	direction Direction
+
+	localObjects        cache.GenericNamespaceLister
+	remoteObjects       cache.GenericNamespaceLister
+	localObjectsClient  dynamic.ResourceInterface
+	remoteObjectsClient dynamic.ResourceInterface
+
+	// synced returns whether the local and remote informers have synced, as they are not awaited by the manager.
+	synced func() bool
+
+	eventRecorder record.EventRecorder
+}
+
+// NewCustomResourceReflector returns a new reflector for the resource described by the given configuration.
+func NewCustomResourceReflector(reflection *ResourceReflection, workers uint) manager.Reflector {
+	return generic.NewReflector(reflection.GroupVersionResource.String(),
+		NewNamespacedCustomResourceReflector(reflection), generic.WithoutFallback(), workers)
+}
+
+// NewNamespacedCustomResourceReflector returns a function generating NamespacedCustomResourceReflector instances.
+func NewNamespacedCustomResourceReflector(reflection *ResourceReflection) func(*options.NamespacedOpts) manager.NamespacedReflector {
+	return func(opts *options.NamespacedOpts) manager.NamespacedReflector {
+		gvr := reflection.GroupVersionResource
+		local := opts.LocalDynamicFactory.ForResource(gvr)
+		remote := opts.RemoteDynamicFactory.ForResource(gvr)
+
+		// Using opts.LocalNamespace for both event handlers so that the object will be put in the same workqueue
+		// no matter the cluster, hence it will be processed by the handle function in the same way.
+		local.Informer().AddEventHandler(opts.HandlerFactory(generic.NamespacedKeyer(opts.LocalNamespace)))
+		remote.Informer().AddEventHandler(opts.HandlerFactory(generic.NamespacedKeyer(opts.LocalNamespace)))
+
+		return &NamespacedCustomResourceReflector{
+			NamespacedReflector: generic.NewNamespacedReflector(opts),
+
+			resource:  gvr.String(),
+			direction: reflection.Direction,
+
+			localObjects:        local.Lister().ByNamespace(opts.LocalNamespace),
+			remoteObjects:       remote.Lister().ByNamespace(opts.RemoteNamespace),
+			localObjectsClient:  opts.LocalDynamicClient.Resource(gvr).Namespace(opts.LocalNamespace),
+			remoteObjectsClient: opts.RemoteDynamicClient.Resource(gvr).Namespace(opts.RemoteNamespace),
+
+			synced: func() bool { return local.Informer().HasSynced() && remote.Informer().HasSynced() },
+
+			eventRecorder: opts.EventBroadcaster.NewRecorder(scheme.Scheme,
+				corev1.EventSource{Component: path.Join("liqo-reflection", forge.RemoteClusterID)}),
+		}
+	}
+}
+
+// Ready returns whether the NamespacedCustomResourceReflector is completely initialized.
+func (ncr *NamespacedCustomResourceReflector) Ready() bool {
+	return ncr.NamespacedReflector.Ready() && ncr.synced()
+}
+
+// Handle is responsible for reconciling the given object and ensuring it is correctly reflected.
+func (ncr *NamespacedCustomResourceReflector) Handle(ctx context.Context, name string) error {
+	tracer := trace.FromContext(ctx)
+
+	// Retrieve the local and remote objects (only not found errors can occur).
+	klog.V(4).Infof("Handling reflection of %v %q (remote: %q, direction: %v)", ncr.resource, ncr.LocalRef(name), ncr.RemoteRef(name), ncr.direction)
+
+	local, lerr := ncr.get(ncr.localObjects, name)
+	utilruntime.Must(client.IgnoreNotFound(lerr))
+	remote, rerr := ncr.get(ncr.remoteObjects, name)
+	utilruntime.Must(client.IgnoreNotFound(rerr))
+	tracer.Step("Retrieved the local and remote objects")
+
+	if ncr.direction == RemoteToLocal {
+		return ncr.handleRemoteToLocal(ctx, name, local, remote)
+	}
+	return ncr.handleLocalToRemote(ctx, name, local, remote)
+}
+
+// handleLocalToRemote reconciles the objects reflected from the local to the remote cluster, optionally propagating back their status.
+func (ncr *NamespacedCustomResourceReflector) handleLocalToRemote(ctx context.Context, name string, local, remote *unstructured.Unstructured) error {
+	tracer := trace.FromContext(ctx)
+
+	// Abort the reflection if the remote object is not managed by us, as we do not want to mutate others' objects.
+	if remote != nil && !forge.IsReflected(remote) {
+		if local != nil {
+			ncr.eventRecorder.Eventf(local, corev1.EventTypeWarning, ReflectionConflictReason,
+				"Remote %v %q already exists and is not managed by Liqo", ncr.resource, ncr.RemoteRef(name))
+		}
+		klog.Infof("Skipping reflection of local %v %q as remote already exists and is not managed by us", ncr.resource, ncr.LocalRef(name))
+		return nil
+	}
+	tracer.Step("Performed the sanity checks")
+
+	if local == nil {
+		defer tracer.Step("Ensured the absence of the remote object")
+		if remote != nil {
+			klog.V(4).Infof("Deleting remote %v %q, since local %q does no longer exist", ncr.resource, ncr.RemoteRef(name), ncr.LocalRef(name))
+			return ncr.DeleteRemote(ctx, resourceDeleter{ncr.remoteObjectsClient}, ncr.resource, name, remote.GetUID())
+		}
+
+		klog.V(4).Infof("Local %v %q and remote %v %q both vanished", ncr.resource, ncr.LocalRef(name), ncr.resource, ncr.RemoteRef(name))
+		return nil
+	}
+
+	// Forge the mutation to be applied to the remote cluster.
+	mutation := forge.ReflectedUnstructured(local, ncr.RemoteNamespace(), forge.ReflectionLabels())
+	tracer.Step("Remote mutation created")
+
+	if err := ncr.apply(ctx, ncr.remoteObjectsClient, name, mutation); err != nil {
+		klog.Errorf("Failed to enforce remote %v %q (local: %q): %v", ncr.resource, ncr.RemoteRef(name), ncr.LocalRef(name), err)
+		return err
+	}
+	tracer.Step("Enforced the correctness of the remote object")
+	klog.Infof("Remote %v %q successfully enforced (local: %q)", ncr.resource, ncr.RemoteRef(name), ncr.LocalRef(name))
+
+	if ncr.direction != StatusBackPropagation || remote == nil {
+		// The status of newly created remote objects is propagated once the corresponding event is received.
+		return nil
+	}
+
+	defer tracer.Step("Enforced the correctness of the local object status")
+	if err := ncr.apply(ctx, ncr.localObjectsClient, name, forge.ReflectedUnstructuredStatus(remote, local), "status"); err != nil {
+		klog.Errorf("Failed to update local %v %q status (remote: %q): %v", ncr.resource, ncr.LocalRef(name), ncr.RemoteRef(name), err)
+		return err
+	}
+
+	klog.Infof("Local %v %q status successfully updated (remote: %q)", ncr.resource, ncr.LocalRef(name), ncr.RemoteRef(name))
+	return nil
+}
+
+// handleRemoteToLocal reconciles the objects reflected from the remote to the local cluster.
+func (ncr *NamespacedCustomResourceReflector) handleRemoteToLocal(ctx context.Context, name string, local, remote *unstructured.Unstructured) error {
+	tracer := trace.FromContext(ctx)
+
+	// Remote objects originating from the local cluster are never reflected back, to prevent loops.
+	if remote != nil && forge.IsReflected(remote) {
+		klog.V(4).Infof("Skipping reflection of remote %v %q as originated from the local cluster", ncr.resource, ncr.RemoteRef(name))
+		return nil
+	}
+
+	// Abort the reflection if the local object is not managed by us, as we do not want to mutate others' objects.
+	if local != nil && !forge.IsReverseReflected(local) {
+		if remote != nil {
+			ncr.eventRecorder.Eventf(local, corev1.EventTypeWarning, ReflectionConflictReason,
+				"Local %v %q conflicts with the one existing in the remote cluster", ncr.resource, ncr.LocalRef(name))
+		}
+		klog.Infof("Skipping reflection of remote %v %q as local already exists and is not managed by us", ncr.resource, ncr.RemoteRef(name))
+		return nil
+	}
+	tracer.Step("Performed the sanity checks")
+
+	if remote == nil {
+		defer tracer.Step("Ensured the absence of the local object")
+		if local != nil {
+			klog.V(4).Infof("Deleting local %v %q, since remote %q does no longer exist", ncr.resource, ncr.LocalRef(name), ncr.RemoteRef(name))
+			err := ncr.localObjectsClient.Delete(ctx, name, *metav1.NewPreconditionDeleteOptions(string(local.GetUID())))
+			if err != nil && !kerrors.IsNotFound(err) {
+				klog.Errorf("Failed to delete local %v %q (remote: %q): %v", ncr.resource, ncr.LocalRef(name), ncr.RemoteRef(name), err)
+				return err
+			}
+			klog.Infof("Local %v %q successfully deleted (remote: %q)", ncr.resource, ncr.LocalRef(name), ncr.RemoteRef(name))
+			return nil
+		}
+
+		klog.V(4).Infof("Local %v %q and remote %v %q both vanished", ncr.resource, ncr.LocalRef(name), ncr.resource, ncr.RemoteRef(name))
+		return nil
+	}
+
+	// Forge the mutation to be applied to the local cluster.
+	mutation := forge.ReflectedUnstructured(remote, ncr.LocalNamespace(), forge.ReverseReflectionLabels())
+	tracer.Step("Local mutation created")
+
+	defer tracer.Step("Enforced the correctness of the local object")
+	if err := ncr.apply(ctx, ncr.localObjectsClient, name, mutation); err != nil {
+		klog.Errorf("Failed to enforce local %v %q (remote: %q): %v", ncr.resource, ncr.LocalRef(name), ncr.RemoteRef(name), err)
+		return err
+	}
+
+	klog.Infof("Local %v %q successfully enforced (remote: %q)", ncr.resource, ncr.LocalRef(name), ncr.RemoteRef(name))
+	return nil
+}
+
+// apply enforces the given object (or the given subresources) through a server-side apply patch.
+func (ncr *NamespacedCustomResourceReflector) apply(ctx context.Context, cl dynamic.ResourceInterface,
+	name string, obj *unstructured.Unstructured, subresources ...string) error {
+	data, err := obj.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	_, err = cl.Patch(ctx, name, types.ApplyPatchType, data, forge.ApplyOptions().ToPatchOptions(), subresources...)
+	return err
+}
+
+// resourceDeleter adapts a dynamic.ResourceInterface to the generic.ResourceDeleter interface.
+type resourceDeleter struct {
+	dynamic.ResourceInterface
+}
+
+// Delete deletes the object with the given name.
+func (rd resourceDeleter) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return rd.ResourceInterface.Delete(ctx, name, opts)
+}
+
+// get retrieves the object with the given name from the lister (only not found errors can occur).
+// A nil object is returned in case it does not exist.
+func (ncr *NamespacedCustomResourceReflector) get(lister cache.GenericNamespaceLister, name string) (*unstructured.Unstructured, error) {
+	obj, err := lister.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Objects retrieved through dynamic informers are always represented as unstructured ones.
+	return obj.(*unstructured.Unstructured), nil
+}
@@ -0,0 +1,260 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package customresource_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/trace"
+
+	. "github.com/liqotech/liqo/pkg/utils/testutil"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/customresource"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/manager"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/options"
+)
+
+var _ = Describe("Custom Resource Reflection", func() {
+	// ShadowPods are leveraged as sample custom resource, since they are namespaced and feature the status subresource.
+	gvr := schema.GroupVersionResource{Group: "virtualkubelet.liqo.io", Version: "v1alpha1", Resource: "shadowpods"}
+
+	Describe("NewCustomResourceReflector", func() {
+		It("should create a non-nil reflector", func() {
+			reflection := customresource.ResourceReflection{GroupVersionResource: gvr, Direction: customresource.LocalToRemote}
+			Expect(customresource.NewCustomResourceReflector(&reflection, 1)).NotTo(BeNil())
+		})
+	})
+
+	Describe("Handle", func() {
+		const ObjectName = "name"
+
+		var (
+			reflector manager.NamespacedReflector
+			direction customresource.Direction
+
+			local, remote *unstructured.Unstructured
+			err           error
+		)
+
+		Forge := func(namespace, image string, labels map[string]string) *unstructured.Unstructured {
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{
+				"spec": map[string]interface{}{"pod": map[string]interface{}{
+					"containers": []interface{}{map[string]interface{}{"name": "container", "image": image}},
+				}},
+			}}
+			obj.SetAPIVersion(gvr.GroupVersion().String())
+			obj.SetKind("ShadowPod")
+			obj.SetName(ObjectName)
+			obj.SetNamespace(namespace)
+			obj.SetLabels(labels)
+			return obj
+		}
+
+		Create := func(obj *unstructured.Unstructured) *unstructured.Unstructured {
+			created, errCreate := dynamicClient.Resource(gvr).Namespace(obj.GetNamespace()).Create(ctx, obj, metav1.CreateOptions{})
+			Expect(errCreate).ToNot(HaveOccurred())
+			return created
+		}
+
+		SetPhase := func(obj *unstructured.Unstructured, phase string) {
+			Expect(unstructured.SetNestedField(obj.Object, phase, "status", "phase")).To(Succeed())
+			_, errUpdate := dynamicClient.Resource(gvr).Namespace(obj.GetNamespace()).UpdateStatus(ctx, obj, metav1.UpdateOptions{})
+			Expect(errUpdate).ToNot(HaveOccurred())
+		}
+
+		Get := func(namespace string) (*unstructured.Unstructured, error) {
+			return dynamicClient.Resource(gvr).Namespace(namespace).Get(ctx, ObjectName, metav1.GetOptions{})
+		}
+
+		Image := func(obj *unstructured.Unstructured) string {
+			containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "pod", "containers")
+			Expect(containers).To(HaveLen(1))
+			return containers[0].(map[string]interface{})["image"].(string)
+		}
+
+		BeforeEach(func() {
+			direction = customresource.LocalToRemote
+			local, remote = nil, nil
+		})
+
+		AfterEach(func() {
+			for _, namespace := range []string{LocalNamespace, RemoteNamespace} {
+				Expect(dynamicClient.Resource(gvr).Namespace(namespace).Delete(ctx, ObjectName, metav1.DeleteOptions{})).To(
+					Or(BeNil(), BeNotFound()))
+			}
+		})
+
+		JustBeforeEach(func() {
+			if local != nil {
+				local = Create(local)
+			}
+			if remote != nil {
+				remote = Create(remote)
+			}
+
+			factory := dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 10*time.Hour)
+			reflection := customresource.ResourceReflection{GroupVersionResource: gvr, Direction: direction}
+			reflector = customresource.NewNamespacedCustomResourceReflector(&reflection)(options.NewNamespaced().
+				WithLocal(LocalNamespace, nil, nil).WithRemote(RemoteNamespace, nil, nil).
+				WithDynamicLocal(dynamicClient, factory).WithDynamicRemote(dynamicClient, factory).
+				WithEventBroadcaster(record.NewBroadcaster()).WithHandlerFactory(FakeEventHandler))
+
+			factory.Start(ctx.Done())
+			factory.WaitForCacheSync(ctx.Done())
+
+			err = reflector.Handle(trace.ContextWithTrace(ctx, trace.New("CustomResource")), ObjectName)
+		})
+
+		When("the direction is LocalToRemote", func() {
+			When("the local object does not exist", func() {
+				When("the remote object does not exist", func() {
+					It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+					It("the remote object should not be created", func() {
+						_, err = Get(RemoteNamespace)
+						Expect(err).To(BeNotFound())
+					})
+				})
+
+				When("the remote object exists and is managed by the reflection", func() {
+					BeforeEach(func() { remote = Forge(RemoteNamespace, "nginx", forge.ReflectionLabels()) })
+
+					It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+					It("the remote object should be deleted", func() {
+						_, err = Get(RemoteNamespace)
+						Expect(err).To(BeNotFound())
+					})
+				})
+			})
+
+			When("the local object exists", func() {
+				BeforeEach(func() { local = Forge(LocalNamespace, "nginx", map[string]string{"foo": "bar"}) })
+
+				When("the remote object does not exist", func() {
+					It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+					It("the remote object should be created", func() {
+						remoteAfter, errGet := Get(RemoteNamespace)
+						Expect(errGet).ToNot(HaveOccurred())
+						Expect(remoteAfter.GetLabels()).To(HaveKeyWithValue(forge.LiqoOriginClusterIDKey, LocalClusterID))
+						Expect(remoteAfter.GetLabels()).To(HaveKeyWithValue(forge.LiqoDestinationClusterIDKey, RemoteClusterID))
+						Expect(remoteAfter.GetLabels()).To(HaveKeyWithValue("foo", "bar"))
+						Expect(Image(remoteAfter)).To(Equal("nginx"))
+					})
+				})
+
+				When("the remote object exists and is managed by the reflection", func() {
+					BeforeEach(func() { remote = Forge(RemoteNamespace, "apache", forge.ReflectionLabels()) })
+
+					It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+					It("the remote object should be updated", func() {
+						remoteAfter, errGet := Get(RemoteNamespace)
+						Expect(errGet).ToNot(HaveOccurred())
+						Expect(Image(remoteAfter)).To(Equal("nginx"))
+					})
+				})
+
+				When("the remote object exists, but is not managed by the reflection", func() {
+					BeforeEach(func() { remote = Forge(RemoteNamespace, "apache", nil) })
+
+					It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+					It("the remote object should be unmodified", func() {
+						remoteAfter, errGet := Get(RemoteNamespace)
+						Expect(errGet).ToNot(HaveOccurred())
+						Expect(remoteAfter).To(Equal(remote))
+					})
+				})
+			})
+		})
+
+		When("the direction is StatusBackPropagation", func() {
+			BeforeEach(func() {
+				direction = customresource.StatusBackPropagation
+				local = Forge(LocalNamespace, "nginx", nil)
+				remote = Forge(RemoteNamespace, "nginx", forge.ReflectionLabels())
+			})
+
+			JustBeforeEach(func() {
+				remote, err = Get(RemoteNamespace)
+				Expect(err).ToNot(HaveOccurred())
+				SetPhase(remote, "Running")
+			})
+
+			It("the status of the remote object should be propagated to the local one", func() {
+				// The object is handled again, until the informers observe the updated status of the remote object.
+				Eventually(func() string {
+					Expect(reflector.Handle(trace.ContextWithTrace(ctx, trace.New("CustomResource")), ObjectName)).To(Succeed())
+					localAfter, errGet := Get(LocalNamespace)
+					Expect(errGet).ToNot(HaveOccurred())
+					phase, _, _ := unstructured.NestedString(localAfter.Object, "status", "phase")
+					return phase
+				}).Should(Equal("Running"))
+			})
+		})
+
+		When("the direction is RemoteToLocal", func() {
+			BeforeEach(func() { direction = customresource.RemoteToLocal })
+
+			When("the remote object exists", func() {
+				BeforeEach(func() { remote = Forge(RemoteNamespace, "nginx", map[string]string{"foo": "bar"}) })
+
+				It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+				It("the local object should be created", func() {
+					localAfter, errGet := Get(LocalNamespace)
+					Expect(errGet).ToNot(HaveOccurred())
+					Expect(localAfter.GetLabels()).To(HaveKeyWithValue(forge.LiqoOriginClusterIDKey, RemoteClusterID))
+					Expect(localAfter.GetLabels()).To(HaveKeyWithValue(forge.LiqoDestinationClusterIDKey, LocalClusterID))
+					Expect(localAfter.GetLabels()).To(HaveKeyWithValue("foo", "bar"))
+					Expect(Image(localAfter)).To(Equal("nginx"))
+				})
+			})
+
+			When("the remote object originates from the local cluster", func() {
+				BeforeEach(func() { remote = Forge(RemoteNamespace, "nginx", forge.ReflectionLabels()) })
+
+				It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+				It("the local object should not be created", func() {
+					_, err = Get(LocalNamespace)
+					Expect(err).To(BeNotFound())
+				})
+			})
+
+			When("the remote object does not exist, and the local one is managed by the reflection", func() {
+				BeforeEach(func() { local = Forge(LocalNamespace, "nginx", forge.ReverseReflectionLabels()) })
+
+				It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+				It("the local object should be deleted", func() {
+					_, err = Get(LocalNamespace)
+					Expect(err).To(BeNotFound())
+				})
+			})
+
+			When("the remote object does not exist, and the local one is not managed by the reflection", func() {
+				BeforeEach(func() { local = Forge(LocalNamespace, "nginx", nil) })
+
+				It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+				It("the local object should be preserved", func() {
+					_, err = Get(LocalNamespace)
+					Expect(err).ToNot(HaveOccurred())
+				})
+			})
+		})
+	})
+})
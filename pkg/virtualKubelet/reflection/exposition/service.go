@@ -19,6 +19,8 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	corev1clients "k8s.io/client-go/kubernetes/typed/core/v1"
 	corev1listers "k8s.io/client-go/listers/core/v1"
@@ -32,7 +34,7 @@ import (
 	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/options"
 )
 
-var _ manager.NamespacedReflector = (*NamespacedServiceReflector)(nil)
+var _ manager.ResyncableNamespacedReflector = (*NamespacedServiceReflector)(nil)
 
 const (
 	// ServiceReflectorName -> The name associated with the Service reflector.
@@ -72,6 +74,18 @@ func NewNamespacedServiceReflector(opts *options.NamespacedOpts) manager.Namespa
 	}
 }
 
+// Keys returns the keys of the local Services, to re-enqueue them when the reflection policies change.
+func (nsr *NamespacedServiceReflector) Keys() []types.NamespacedName {
+	objects, err := nsr.localServices.List(labels.Everything())
+	utilruntime.Must(err)
+
+	keys := make([]types.NamespacedName, 0, len(objects))
+	for _, object := range objects {
+		keys = append(keys, types.NamespacedName{Namespace: nsr.LocalNamespace(), Name: object.GetName()})
+	}
+	return keys
+}
+
 // Handle reconciles service objects.
 func (nsr *NamespacedServiceReflector) Handle(ctx context.Context, name string) error {
 	tracer := trace.FromContext(ctx)
@@ -99,6 +113,7 @@ func (nsr *NamespacedServiceReflector) Handle(ctx context.Context, name string)
 
 	// The local service does no longer exist. Ensure it is also absent from the remote cluster.
 	if kerrors.IsNotFound(lerr) {
+		nsr.ForgetPolicyDecision(name)
 		defer tracer.Step("Ensured the absence of the remote object")
 		if !kerrors.IsNotFound(rerr) {
 			klog.V(4).Infof("Deleting remote Service %q, since local %q does no longer exist", nsr.RemoteRef(name), nsr.LocalRef(name))
@@ -109,8 +124,24 @@ func (nsr *NamespacedServiceReflector) Handle(ctx context.Context, name string)
 		return nil
 	}
 
+	// Evaluate the reflection policies, and ensure the absence of the remote object if the local one shall not be reflected.
+	decision := nsr.EvaluatePolicy(local)
+	if decision.Skip {
+		defer tracer.Step("Ensured the absence of the remote object")
+		if !kerrors.IsNotFound(rerr) {
+			klog.V(4).Infof("Deleting remote Service %q, since local %q shall not be reflected (%v)",
+				nsr.RemoteRef(name), nsr.LocalRef(name), decision.Reason)
+			return nsr.DeleteRemote(ctx, nsr.remoteServicesClient, ServiceReflectorName, name, remote.GetUID())
+		}
+
+		klog.V(4).Infof("Skipping reflection of local Service %q, as required by %v", nsr.LocalRef(name), decision.Reason)
+		return nil
+	}
+	tracer.Step("Evaluated the reflection policies")
+
 	// Forge the mutation to be applied to the remote cluster.
 	mutation := forge.RemoteService(local, nsr.RemoteNamespace())
+	decision.MutateObjectMeta(mutation.ObjectMetaApplyConfiguration)
 	tracer.Step("Remote mutation created")
 
 	defer tracer.Step("Enforced the correctness of the remote object")
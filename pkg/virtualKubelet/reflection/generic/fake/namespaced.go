@@ -17,14 +17,17 @@ package fake
 import (
 	"context"
 
+	"k8s.io/apimachinery/pkg/types"
+
 	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/options"
 )
 
 // NamespacedReflector implements a fake NamespacedReflector for testing purposes.
 type NamespacedReflector struct {
-	Opts    options.NamespacedOpts
-	Handled int
-	ready   bool
+	Opts       options.NamespacedOpts
+	Handled    int
+	ResyncKeys []types.NamespacedName
+	ready      bool
 }
 
 // NewNamespacedReflector returns a new fake NamespacedReflector.
@@ -43,3 +46,6 @@ func (r *NamespacedReflector) Ready() bool { return r.ready }
 
 // SetReady marks the NamespacedReflector as completely initialized.
 func (r *NamespacedReflector) SetReady() { r.ready = true }
+
+// Keys returns the configured ResyncKeys.
+func (r *NamespacedReflector) Keys() []types.NamespacedName { return r.ResyncKeys }
@@ -16,21 +16,38 @@ package generic
 
 import (
 	"context"
+	"path"
+	"sync"
 
+	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
 	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/options"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/policy"
 )
 
+// ReflectionSkippedReason is the reason of the events recorded when an object is not reflected due to the reflection policies.
+const ReflectionSkippedReason = "ReflectionSkipped"
+
 // NamespacedReflector implements the logic common to all namespaced reflectors.
 type NamespacedReflector struct {
 	ready func() bool
 
 	local  string
 	remote string
+
+	policy   *policy.Evaluator
+	recorder record.EventRecorder
+	// skipped tracks the names of the local objects currently not reflected due to the policies,
+	// to record the corresponding event only when the reflection state changes.
+	skipped *sync.Map
 }
 
 // ResourceDeleter know how to delete a Kubernetes object with the given name.
@@ -40,7 +57,13 @@ type ResourceDeleter interface {
 
 // NewNamespacedReflector returns a new NamespacedReflector for the given namespaces.
 func NewNamespacedReflector(opts *options.NamespacedOpts) NamespacedReflector {
-	return NamespacedReflector{local: opts.LocalNamespace, remote: opts.RemoteNamespace, ready: opts.Ready}
+	reflector := NamespacedReflector{local: opts.LocalNamespace, remote: opts.RemoteNamespace, ready: opts.Ready,
+		policy: opts.ReflectionPolicy, skipped: &sync.Map{}}
+	if opts.EventBroadcaster != nil {
+		reflector.recorder = opts.EventBroadcaster.NewRecorder(scheme.Scheme,
+			corev1.EventSource{Component: path.Join("liqo-reflection", forge.RemoteClusterID)})
+	}
+	return reflector
 }
 
 // Ready returns whether the NamespacedReflector is completely initialized.
@@ -79,3 +102,24 @@ func (gnr *NamespacedReflector) DeleteRemote(ctx context.Context, deleter Resour
 	klog.Infof("Remote %v %q successfully deleted (local: %q)", resource, gnr.RemoteRef(name), gnr.LocalRef(name))
 	return nil
 }
+
+// EvaluatePolicy evaluates the reflection policies for the given local object, and records an event
+// on the object in case it shall no longer be reflected (i.e., only when the reflection state changes).
+func (gnr *NamespacedReflector) EvaluatePolicy(obj client.Object) *policy.Decision {
+	decision := gnr.policy.Evaluate(obj)
+	if !decision.Skip {
+		gnr.skipped.Delete(obj.GetName())
+		return decision
+	}
+
+	if _, already := gnr.skipped.LoadOrStore(obj.GetName(), struct{}{}); !already && gnr.recorder != nil {
+		gnr.recorder.Eventf(obj, corev1.EventTypeNormal, ReflectionSkippedReason,
+			"Reflection towards remote cluster %q skipped, as required by %v", forge.RemoteClusterID, decision.Reason)
+	}
+	return decision
+}
+
+// ForgetPolicyDecision forgets the reflection state of the given local object, which no longer exists.
+func (gnr *NamespacedReflector) ForgetPolicyDecision(name string) {
+	gnr.skipped.Delete(name)
+}
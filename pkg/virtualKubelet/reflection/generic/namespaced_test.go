@@ -24,8 +24,10 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/fake"
 	corev1clients "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 
+	"github.com/liqotech/liqo/pkg/consts"
 	. "github.com/liqotech/liqo/pkg/utils/testutil"
 	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/options"
 )
@@ -108,5 +110,58 @@ var _ = Describe("NamespacedReflector tests", func() {
 				})
 			})
 		})
+
+		Context("the reflection policy evaluation", func() {
+			var (
+				recorder *record.FakeRecorder
+				secret   *corev1.Secret
+			)
+
+			Skipped := func() *corev1.Secret {
+				skipped := secret.DeepCopy()
+				skipped.SetAnnotations(map[string]string{consts.SkipReflectionAnnotationKey: "true"})
+				return skipped
+			}
+
+			JustBeforeEach(func() {
+				recorder = record.NewFakeRecorder(10)
+				nsrfl.recorder = recorder
+				secret = &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: localNamespace}}
+			})
+
+			When("the object is reflected", func() {
+				It("should not record any event", func() {
+					Expect(nsrfl.EvaluatePolicy(secret).Skip).To(BeFalse())
+					Expect(recorder.Events).To(BeEmpty())
+				})
+			})
+
+			When("the object is repeatedly skipped", func() {
+				It("should record the event only once", func() {
+					Expect(nsrfl.EvaluatePolicy(Skipped()).Skip).To(BeTrue())
+					Expect(nsrfl.EvaluatePolicy(Skipped()).Skip).To(BeTrue())
+					Expect(recorder.Events).To(HaveLen(1))
+					Expect(<-recorder.Events).To(ContainSubstring(ReflectionSkippedReason))
+				})
+			})
+
+			When("the reflection state of the object changes", func() {
+				It("should record a new event", func() {
+					nsrfl.EvaluatePolicy(Skipped())
+					nsrfl.EvaluatePolicy(secret)
+					nsrfl.EvaluatePolicy(Skipped())
+					Expect(recorder.Events).To(HaveLen(2))
+				})
+			})
+
+			When("the object is forgotten", func() {
+				It("should record a new event", func() {
+					nsrfl.EvaluatePolicy(Skipped())
+					nsrfl.ForgetPolicyDecision(name)
+					nsrfl.EvaluatePolicy(Skipped())
+					Expect(recorder.Events).To(HaveLen(2))
+				})
+			})
+		})
 	})
 })
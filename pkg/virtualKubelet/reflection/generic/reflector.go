@@ -35,7 +35,7 @@ import (
 	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/options"
 )
 
-var _ manager.Reflector = (*reflector)(nil)
+var _ manager.ResyncableReflector = (*reflector)(nil)
 
 // NamespacedReflectorFactoryFunc represents the function type to create a new NamespacedReflector.
 type NamespacedReflectorFactoryFunc func(*options.NamespacedOpts) manager.NamespacedReflector
@@ -140,6 +140,21 @@ func (gr *reflector) StopNamespace(local, remote string) {
 	klog.Infof("Reflection between local namespace %q and remote namespace %q correctly stopped", local, remote)
 }
 
+// Resync re-enqueues all the objects associated with the given local namespace, in case the corresponding
+// namespaced reflector supports their enumeration.
+func (gr *reflector) Resync(local string) {
+	reflector, found := gr.namespace(local)
+	if !found || !reflector.Ready() {
+		return
+	}
+
+	if resyncable, ok := reflector.(manager.ResyncableNamespacedReflector); ok {
+		for _, key := range resyncable.Keys() {
+			gr.workqueue.Add(key)
+		}
+	}
+}
+
 // namespace returns the service reflector associated with a given namespace (if any).
 func (gr *reflector) namespace(namespace string) (manager.NamespacedReflector, bool) {
 	gr.Lock()
@@ -140,6 +140,38 @@ var _ = Describe("Reflector tests", func() {
 						})
 					})
 
+					Context("the namespace is resynced", func() {
+						key := types.NamespacedName{Namespace: localNamespace, Name: "foo"}
+
+						JustBeforeEach(func() {
+							nsrfl.ResyncKeys = []types.NamespacedName{key}
+						})
+
+						When("the namespaced reflector is not ready", func() {
+							JustBeforeEach(func() { rfl.(manager.ResyncableReflector).Resync(localNamespace) })
+							It("should not enqueue any element", func() {
+								Expect(rfl.(*reflector).workqueue.Len()).To(BeNumerically("==", 1))
+							})
+						})
+
+						When("the namespaced reflector is ready", func() {
+							JustBeforeEach(func() {
+								nsrfl.SetReady()
+								rfl.(manager.ResyncableReflector).Resync(localNamespace)
+							})
+							It("should enqueue the returned elements", func() {
+								Expect(rfl.(*reflector).workqueue.Len()).To(BeNumerically("==", 2))
+							})
+						})
+
+						When("the namespace does not exist", func() {
+							JustBeforeEach(func() { rfl.(manager.ResyncableReflector).Resync("baz") })
+							It("should not enqueue any element", func() {
+								Expect(rfl.(*reflector).workqueue.Len()).To(BeNumerically("==", 1))
+							})
+						})
+					})
+
 					Context("a namespaced reflector is retrieved", func() {
 						var (
 							namespace string
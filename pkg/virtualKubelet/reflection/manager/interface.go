@@ -60,6 +60,20 @@ type NamespacedReflector interface {
 	Ready() bool
 }
 
+// ResyncableReflector is a Reflector which can be requested to re-process all the objects of a given namespace.
+type ResyncableReflector interface {
+	Reflector
+	// Resync re-enqueues all the objects associated with the given local namespace.
+	Resync(local string)
+}
+
+// ResyncableNamespacedReflector is a NamespacedReflector able to enumerate the local objects it manages.
+type ResyncableNamespacedReflector interface {
+	NamespacedReflector
+	// Keys returns the keys of the local objects managed by the reflector.
+	Keys() []types.NamespacedName
+}
+
 // FallbackReflector implements fallback reflection for "orphan" local objects not managed by namespaced reflectors.
 type FallbackReflector interface {
 	// Handle is responsible for reconciling the given "orphan" object.
@@ -22,17 +22,22 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/trace"
 
+	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
 	liqoclient "github.com/liqotech/liqo/pkg/client/clientset/versioned"
 	liqoinformers "github.com/liqotech/liqo/pkg/client/informers/externalversions"
 	traceutils "github.com/liqotech/liqo/pkg/utils/trace"
 	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
 	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/options"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/policy"
 )
 
 var _ Manager = (*manager)(nil)
@@ -45,12 +50,18 @@ type manager struct {
 	remote           kubernetes.Interface
 	localLiqo        liqoclient.Interface
 	remoteLiqo       liqoclient.Interface
+	localDynamic     dynamic.Interface
+	remoteDynamic    dynamic.Interface
 	resync           time.Duration
 	eventBroadcaster record.EventBroadcaster
 
 	reflectors              []Reflector
 	localPodInformerFactory informers.SharedInformerFactory
 
+	// The informer factory and the evaluator associated with the (cluster-scoped) reflection policies.
+	reflectionPolicyFactory liqoinformers.SharedInformerFactory
+	reflectionPolicy        *policy.Evaluator
+
 	namespaceHandler NamespaceHandler
 
 	started bool
@@ -58,18 +69,23 @@ type manager struct {
 }
 
 // New returns a new manager to start the reflection towards a remote cluster.
-func New(local, remote kubernetes.Interface, localLiqo, remoteLiqo liqoclient.Interface, resync time.Duration,
-	eb record.EventBroadcaster) Manager {
+func New(local, remote kubernetes.Interface, localLiqo, remoteLiqo liqoclient.Interface,
+	localDynamic, remoteDynamic dynamic.Interface, resync time.Duration, eb record.EventBroadcaster) Manager {
 	// Configure the field selector to retrieve only the pods scheduled on the current virtual node.
 	localPodTweakListOptions := func(opts *metav1.ListOptions) {
 		opts.FieldSelector = fields.OneTermEqualSelector("spec.nodeName", forge.LiqoNodeName).String()
 	}
 
-	return &manager{
+	reflectionPolicyFactory := liqoinformers.NewSharedInformerFactory(localLiqo, resync)
+	reflectionPolicies := reflectionPolicyFactory.Virtualkubelet().V1alpha1().ReflectionPolicies()
+
+	m := &manager{
 		local:            local,
 		remote:           remote,
 		localLiqo:        localLiqo,
 		remoteLiqo:       remoteLiqo,
+		localDynamic:     localDynamic,
+		remoteDynamic:    remoteDynamic,
 		resync:           resync,
 		eventBroadcaster: eb,
 
@@ -77,9 +93,21 @@ func New(local, remote kubernetes.Interface, localLiqo, remoteLiqo liqoclient.In
 		localPodInformerFactory: informers.NewSharedInformerFactoryWithOptions(local, resync,
 			informers.WithTweakListOptions(localPodTweakListOptions)),
 
+		reflectionPolicyFactory: reflectionPolicyFactory,
+		reflectionPolicy:        policy.NewEvaluator(reflectionPolicies.Lister()),
+
 		started: false,
 		stop:    make(map[string]context.CancelFunc),
 	}
+
+	// Re-process the objects of the affected namespaces whenever a reflection policy changes.
+	reflectionPolicies.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { m.resyncReflectionPolicy(obj) },
+		UpdateFunc: func(oldObj, newObj interface{}) { m.resyncReflectionPolicy(oldObj, newObj) },
+		DeleteFunc: func(obj interface{}) { m.resyncReflectionPolicy(obj) },
+	})
+
+	return m
 }
 
 // With registers the given reflector to the manager.
@@ -117,7 +145,9 @@ func (m *manager) Start(ctx context.Context) {
 
 	// This is a no-op in case no informers/listers have been retrieved.
 	m.localPodInformerFactory.Start(ctx.Done())
+	m.reflectionPolicyFactory.Start(ctx.Done())
 	m.localPodInformerFactory.WaitForCacheSync(ctx.Done())
+	m.reflectionPolicyFactory.WaitForCacheSync(ctx.Done())
 
 	m.started = true
 
@@ -161,6 +191,7 @@ func (m *manager) StartNamespace(local, remote string) {
 	// The local informer factories, which select all resources in the given namespace.
 	localFactory := informers.NewSharedInformerFactoryWithOptions(m.local, m.resync, informers.WithNamespace(local))
 	localLiqoFactory := liqoinformers.NewSharedInformerFactoryWithOptions(m.localLiqo, m.resync, liqoinformers.WithNamespace(local))
+	localDynamicFactory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(m.localDynamic, m.resync, local, nil)
 
 	// The remote informer factories, which select all resources in the given namespace and with the reflection labels.
 	remoteTweakListOptions := func(opts *metav1.ListOptions) { opts.LabelSelector = forge.ReflectedLabelSelector().String() }
@@ -168,13 +199,18 @@ func (m *manager) StartNamespace(local, remote string) {
 		informers.WithNamespace(remote), informers.WithTweakListOptions(remoteTweakListOptions))
 	remoteLiqoFactory := liqoinformers.NewSharedInformerFactoryWithOptions(m.remoteLiqo, m.resync,
 		liqoinformers.WithNamespace(remote), liqoinformers.WithTweakListOptions(remoteTweakListOptions))
+	// The remote dynamic informer factory does not filter by the reflection labels, as generic resources
+	// can be reflected also from the remote to the local cluster (the ownership is checked by the reflectors).
+	remoteDynamicFactory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(m.remoteDynamic, m.resync, remote, nil)
 
 	ready := false
 	for _, reflector := range m.reflectors {
 		opts := options.NewNamespaced().
 			WithLocal(local, m.local, localFactory).WithLiqoLocal(m.localLiqo, localLiqoFactory).
 			WithRemote(remote, m.remote, remoteFactory).WithLiqoRemote(m.remoteLiqo, remoteLiqoFactory).
-			WithReadinessFunc(func() bool { return ready }).WithEventBroadcaster(m.eventBroadcaster)
+			WithDynamicLocal(m.localDynamic, localDynamicFactory).WithDynamicRemote(m.remoteDynamic, remoteDynamicFactory).
+			WithReadinessFunc(func() bool { return ready }).WithEventBroadcaster(m.eventBroadcaster).
+			WithReflectionPolicy(m.reflectionPolicy)
 		reflector.StartNamespace(opts)
 	}
 
@@ -188,6 +224,12 @@ func (m *manager) StartNamespace(local, remote string) {
 		localLiqoFactory.Start(ctx.Done())
 		remoteFactory.Start(ctx.Done())
 		remoteLiqoFactory.Start(ctx.Done())
+		localDynamicFactory.Start(ctx.Done())
+		remoteDynamicFactory.Start(ctx.Done())
+
+		// The dynamic informers are synchronized separately (each custom resource reflector additionally waits for
+		// its own informers), so that a resource which cannot be listed does not prevent the others from being reflected.
+		go m.waitForDynamicCacheSync(ctx, local, remote, localDynamicFactory, remoteDynamicFactory)
 
 		localFactory.WaitForCacheSync(ctx.Done())
 		localLiqoFactory.WaitForCacheSync(ctx.Done())
@@ -208,6 +250,60 @@ func (m *manager) StartNamespace(local, remote string) {
 	}()
 }
 
+// waitForDynamicCacheSync waits for the caches of the given dynamic informer factories to sync.
+func (m *manager) waitForDynamicCacheSync(ctx context.Context, local, remote string, factories ...dynamicinformer.DynamicSharedInformerFactory) {
+	for _, factory := range factories {
+		for gvr, synced := range factory.WaitForCacheSync(ctx.Done()) {
+			if !synced {
+				klog.Warningf("Failed to sync the cache of %v between local namespace %q and remote namespace %q", gvr, local, remote)
+				return
+			}
+		}
+	}
+	klog.V(4).Infof("Custom resources reflection between local namespace %q and remote namespace %q correctly started", local, remote)
+}
+
+// resyncReflectionPolicy re-enqueues the objects of the namespaces affected by the given reflection policies.
+func (m *manager) resyncReflectionPolicy(objs ...interface{}) {
+	m.Lock()
+	defer m.Unlock()
+
+	namespaces := make(map[string]struct{})
+	for _, obj := range objs {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		reflectionPolicy, ok := obj.(*vkv1alpha1.ReflectionPolicy)
+		if !ok {
+			continue
+		}
+
+		affected, all := policy.AffectedNamespaces(reflectionPolicy, forge.RemoteClusterID)
+		if all {
+			affected = make([]string, 0, len(m.stop))
+			for local := range m.stop {
+				affected = append(affected, local)
+			}
+		}
+		for _, local := range affected {
+			namespaces[local] = struct{}{}
+		}
+	}
+
+	for local := range namespaces {
+		if _, found := m.stop[local]; !found {
+			continue
+		}
+
+		klog.V(4).Infof("Re-enqueuing the objects of local namespace %q, as the reflection policies changed", local)
+		for _, reflector := range m.reflectors {
+			if resyncable, ok := reflector.(ResyncableReflector); ok {
+				resyncable.Resync(local)
+			}
+		}
+	}
+}
+
 // StopNamespace stops the reflection for a given namespace.
 func (m *manager) StopNamespace(local, remote string) {
 	m.Lock()
@@ -20,6 +20,9 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/tools/record"
@@ -41,6 +44,8 @@ var _ = Describe("Manager tests", func() {
 		remoteClient     kubernetes.Interface
 		localLiqoClient  liqoclient.Interface
 		remoteLiqoClient liqoclient.Interface
+		localDynClient   dynamic.Interface
+		remoteDynClient  dynamic.Interface
 		broadcaster      record.EventBroadcaster
 
 		ctx    context.Context
@@ -53,12 +58,14 @@ var _ = Describe("Manager tests", func() {
 		remoteClient = fake.NewSimpleClientset()
 		localLiqoClient = liqoclientfake.NewSimpleClientset()
 		remoteLiqoClient = liqoclientfake.NewSimpleClientset()
+		localDynClient = dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+		remoteDynClient = dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
 		broadcaster = record.NewBroadcaster()
 	})
 	AfterEach(func() { cancel() })
 
 	JustBeforeEach(func() {
-		mgr = New(localClient, remoteClient, localLiqoClient, remoteLiqoClient, 1*time.Hour, broadcaster)
+		mgr = New(localClient, remoteClient, localLiqoClient, remoteLiqoClient, localDynClient, remoteDynClient, 1*time.Hour, broadcaster)
 	})
 
 	Context("a new manager is created", func() {
@@ -68,6 +75,8 @@ var _ = Describe("Manager tests", func() {
 			Expect(mgr.(*manager).remote).To(Equal(remoteClient))
 			Expect(mgr.(*manager).localLiqo).To(Equal(localLiqoClient))
 			Expect(mgr.(*manager).remoteLiqo).To(Equal(remoteLiqoClient))
+			Expect(mgr.(*manager).localDynamic).To(Equal(localDynClient))
+			Expect(mgr.(*manager).remoteDynamic).To(Equal(remoteDynClient))
 			Expect(mgr.(*manager).resync).To(Equal(1 * time.Hour))
 			Expect(mgr.(*manager).eventBroadcaster).To(Equal(broadcaster))
 
@@ -19,6 +19,10 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/informers"
 	corev1informers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
@@ -30,6 +34,7 @@ import (
 	liqoclientfake "github.com/liqotech/liqo/pkg/client/clientset/versioned/fake"
 	liqoinformers "github.com/liqotech/liqo/pkg/client/informers/externalversions"
 	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/options"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/policy"
 )
 
 var _ = Describe("Options", func() {
@@ -117,14 +122,21 @@ var _ = Describe("Options", func() {
 			factory     informers.SharedInformerFactory
 			liqoFactory liqoinformers.SharedInformerFactory
 			broadcaster record.EventBroadcaster
+			evaluator   *policy.Evaluator
+
+			dynClient  dynamic.Interface
+			dynFactory dynamicinformer.DynamicSharedInformerFactory
 		)
 
 		BeforeEach(func() {
+			dynClient = dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+			dynFactory = dynamicinformer.NewDynamicSharedInformerFactory(dynClient, 10*time.Hour)
 			client = fake.NewSimpleClientset()
 			liqoClient = liqoclientfake.NewSimpleClientset()
 			factory = informers.NewSharedInformerFactory(client, 10*time.Hour)
 			liqoFactory = liqoinformers.NewSharedInformerFactory(liqoClient, 10*time.Hour)
 			broadcaster = record.NewBroadcaster()
+			evaluator = policy.NewEvaluator(liqoFactory.Virtualkubelet().V1alpha1().ReflectionPolicies().Lister())
 		})
 
 		JustBeforeEach(func() { original = options.NewNamespaced() })
@@ -217,6 +229,50 @@ var _ = Describe("Options", func() {
 			})
 		})
 
+		Describe("The WithDynamicLocal function", func() {
+			JustBeforeEach(func() { opts = original.WithDynamicLocal(dynClient, dynFactory) })
+
+			It("should return a non-nil pointer", func() { Expect(opts).ToNot(BeNil()) })
+			It("should return the same pointer of the receiver", func() { Expect(opts).To(BeIdenticalTo(original)) })
+			It("should correctly set the local dynamic client value", func() { Expect(opts.LocalDynamicClient).To(BeIdenticalTo(dynClient)) })
+			It("should correctly set the local dynamic factory value", func() { Expect(opts.LocalDynamicFactory).To(BeIdenticalTo(dynFactory)) })
+			It("should leave the other fields unset", func() {
+				Expect(opts.LocalNamespace).To(BeEmpty())
+				Expect(opts.RemoteNamespace).To(BeEmpty())
+				Expect(opts.LocalClient).To(BeNil())
+				Expect(opts.LocalFactory).To(BeNil())
+				Expect(opts.RemoteClient).To(BeNil())
+				Expect(opts.RemoteFactory).To(BeNil())
+				Expect(opts.RemoteDynamicClient).To(BeNil())
+				Expect(opts.RemoteDynamicFactory).To(BeNil())
+				Expect(opts.EventBroadcaster).To(BeNil())
+				Expect(opts.HandlerFactory).To(BeNil())
+				Expect(opts.Ready).To(BeNil())
+			})
+		})
+
+		Describe("The WithDynamicRemote function", func() {
+			JustBeforeEach(func() { opts = original.WithDynamicRemote(dynClient, dynFactory) })
+
+			It("should return a non-nil pointer", func() { Expect(opts).ToNot(BeNil()) })
+			It("should return the same pointer of the receiver", func() { Expect(opts).To(BeIdenticalTo(original)) })
+			It("should correctly set the remote dynamic client value", func() { Expect(opts.RemoteDynamicClient).To(BeIdenticalTo(dynClient)) })
+			It("should correctly set the remote dynamic factory value", func() { Expect(opts.RemoteDynamicFactory).To(BeIdenticalTo(dynFactory)) })
+			It("should leave the other fields unset", func() {
+				Expect(opts.LocalNamespace).To(BeEmpty())
+				Expect(opts.RemoteNamespace).To(BeEmpty())
+				Expect(opts.LocalClient).To(BeNil())
+				Expect(opts.LocalFactory).To(BeNil())
+				Expect(opts.LocalDynamicClient).To(BeNil())
+				Expect(opts.LocalDynamicFactory).To(BeNil())
+				Expect(opts.RemoteClient).To(BeNil())
+				Expect(opts.RemoteFactory).To(BeNil())
+				Expect(opts.EventBroadcaster).To(BeNil())
+				Expect(opts.HandlerFactory).To(BeNil())
+				Expect(opts.Ready).To(BeNil())
+			})
+		})
+
 		Describe("The WithHandlerFactory function", func() {
 			JustBeforeEach(func() { opts = original.WithHandlerFactory(hf) })
 
@@ -287,5 +343,27 @@ var _ = Describe("Options", func() {
 				Expect(opts.Ready).To(BeNil())
 			})
 		})
+
+		Describe("The WithReflectionPolicy function", func() {
+			JustBeforeEach(func() { opts = original.WithReflectionPolicy(evaluator) })
+
+			It("should return a non-nil pointer", func() { Expect(opts).ToNot(BeNil()) })
+			It("should return the same pointer of the receiver", func() { Expect(opts).To(BeIdenticalTo(original)) })
+			It("should correctly set the reflection policy value", func() { Expect(opts.ReflectionPolicy).To(BeIdenticalTo(evaluator)) })
+			It("should leave the other fields unset", func() {
+				Expect(opts.LocalNamespace).To(BeEmpty())
+				Expect(opts.RemoteNamespace).To(BeEmpty())
+				Expect(opts.LocalClient).To(BeNil())
+				Expect(opts.LocalLiqoClient).To(BeNil())
+				Expect(opts.LocalFactory).To(BeNil())
+				Expect(opts.RemoteClient).To(BeNil())
+				Expect(opts.RemoteLiqoClient).To(BeNil())
+				Expect(opts.RemoteFactory).To(BeNil())
+				Expect(opts.RemoteLiqoFactory).To(BeNil())
+				Expect(opts.EventBroadcaster).To(BeNil())
+				Expect(opts.HandlerFactory).To(BeNil())
+				Expect(opts.Ready).To(BeNil())
+			})
+		})
 	})
 })
@@ -0,0 +1,230 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	metav1apply "k8s.io/client-go/applyconfigurations/meta/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
+	vkv1alpha1listers "github.com/liqotech/liqo/pkg/client/listers/virtualkubelet/v1alpha1"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/utils/slice"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+)
+
+// Evaluator evaluates the reflection policies to determine whether and how a given object shall be reflected.
+// A nil Evaluator honors only the skip reflection annotation, and reflects all the other objects unmodified.
+type Evaluator struct {
+	policies vkv1alpha1listers.ReflectionPolicyLister
+}
+
+// NewEvaluator returns a new Evaluator, retrieving the reflection policies from the given lister.
+func NewEvaluator(policies vkv1alpha1listers.ReflectionPolicyLister) *Evaluator {
+	return &Evaluator{policies: policies}
+}
+
+// Decision is the outcome of the evaluation of the reflection policies for a given object.
+type Decision struct {
+	// Skip is true if the object shall not be reflected.
+	Skip bool
+	// Reason is a human-readable description of the source of the decision.
+	Reason string
+
+	rule *vkv1alpha1.ReflectionRule
+}
+
+// Evaluate returns the reflection decision for the given object. The skip reflection annotation can only
+// restrict the reflection: if set to true, the object is not reflected, while otherwise the reflection policies
+// are considered in alphabetical order. The first rule matching the object determines the outcome, while
+// objects matching no rule are reflected unmodified.
+func (e *Evaluator) Evaluate(obj client.Object) *Decision {
+	if value, found := obj.GetAnnotations()[consts.SkipReflectionAnnotationKey]; found {
+		skip, err := strconv.ParseBool(value)
+		switch {
+		case err != nil:
+			klog.Warningf("Invalid value %q for annotation %v of %q, ignoring", value, consts.SkipReflectionAnnotationKey, klog.KObj(obj))
+		case skip:
+			return &Decision{Skip: true, Reason: fmt.Sprintf("annotation %v=%v", consts.SkipReflectionAnnotationKey, value)}
+		}
+	}
+
+	if e == nil || e.policies == nil {
+		return &Decision{}
+	}
+
+	policies, err := e.policies.List(labels.Everything())
+	if err != nil {
+		// Listing from the informer cache cannot fail, hence we just log the error.
+		klog.Errorf("Failed to retrieve the reflection policies: %v", err)
+		return &Decision{}
+	}
+
+	sort.Slice(policies, func(i, j int) bool { return policies[i].GetName() < policies[j].GetName() })
+	for _, policy := range policies {
+		if !appliesToCluster(policy, forge.RemoteClusterID) {
+			continue
+		}
+
+		for i := range policy.Spec.Rules {
+			rule := &policy.Spec.Rules[i]
+			if !matches(rule, obj) {
+				continue
+			}
+
+			return &Decision{
+				Skip:   rule.Action == vkv1alpha1.ReflectionActionSkip,
+				Reason: fmt.Sprintf("ReflectionPolicy %q (rule #%d)", policy.GetName(), i),
+				rule:   rule,
+			}
+		}
+	}
+
+	return &Decision{}
+}
+
+// MutateObjectMeta strips and sets the labels and annotations of the given apply configuration,
+// according to the decision. The labels identifying the reflected objects are always preserved.
+func (d *Decision) MutateObjectMeta(meta *metav1apply.ObjectMetaApplyConfiguration) {
+	if d.rule == nil || meta == nil {
+		return
+	}
+
+	reserved := forge.ReflectionLabels()
+	for _, key := range d.rule.StripLabels {
+		if !reserved.Has(key) {
+			delete(meta.Labels, key)
+		}
+	}
+	for _, key := range d.rule.StripAnnotations {
+		delete(meta.Annotations, key)
+	}
+
+	for key, value := range d.rule.SetLabels {
+		if !reserved.Has(key) {
+			meta.WithLabels(map[string]string{key: value})
+		}
+	}
+	meta.WithAnnotations(d.rule.SetAnnotations)
+}
+
+// StripStringData removes the data keys to be stripped, according to the decision, from the given map.
+func (d *Decision) StripStringData(data map[string]string) {
+	if d.rule == nil {
+		return
+	}
+
+	for _, key := range d.rule.StripDataKeys {
+		delete(data, key)
+	}
+}
+
+// StripBinaryData removes the data keys to be stripped, according to the decision, from the given map.
+func (d *Decision) StripBinaryData(data map[string][]byte) {
+	if d.rule == nil {
+		return
+	}
+
+	for _, key := range d.rule.StripDataKeys {
+		delete(data, key)
+	}
+}
+
+// AffectedNamespaces returns the namespaces possibly affected by the given policy, as far as the given remote
+// cluster is concerned. The all return value is true in case the policy possibly affects every namespace.
+func AffectedNamespaces(policy *vkv1alpha1.ReflectionPolicy, clusterID string) (namespaces []string, all bool) {
+	if !appliesToCluster(policy, clusterID) {
+		return nil, false
+	}
+
+	for i := range policy.Spec.Rules {
+		if len(policy.Spec.Rules[i].Namespaces) == 0 {
+			return nil, true
+		}
+		namespaces = append(namespaces, policy.Spec.Rules[i].Namespaces...)
+	}
+	return namespaces, false
+}
+
+// appliesToCluster returns whether the given policy applies to the given remote cluster.
+func appliesToCluster(policy *vkv1alpha1.ReflectionPolicy, clusterID string) bool {
+	return len(policy.Spec.ClusterIDs) == 0 || slice.ContainsString(policy.Spec.ClusterIDs, clusterID)
+}
+
+// matches returns whether the given rule matches the given object.
+func matches(rule *vkv1alpha1.ReflectionRule, obj client.Object) bool {
+	if len(rule.Resources) > 0 && !containsResource(rule.Resources, resourceOf(obj)) {
+		return false
+	}
+
+	if len(rule.Namespaces) > 0 && !slice.ContainsString(rule.Namespaces, obj.GetNamespace()) {
+		return false
+	}
+
+	if rule.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(rule.Selector)
+		if err != nil {
+			klog.Warningf("Invalid label selector in reflection rule, ignoring: %v", err)
+			return false
+		}
+		if !selector.Matches(labels.Set(obj.GetLabels())) {
+			return false
+		}
+	}
+
+	if secret, ok := obj.(*corev1.Secret); ok && len(rule.SecretTypes) > 0 {
+		found := false
+		for _, secretType := range rule.SecretTypes {
+			found = found || secretType == secret.Type
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// resourceOf returns the ReflectedResource corresponding to the given object.
+func resourceOf(obj client.Object) vkv1alpha1.ReflectedResource {
+	switch obj.(type) {
+	case *corev1.ConfigMap:
+		return vkv1alpha1.ReflectedResourceConfigMap
+	case *corev1.Secret:
+		return vkv1alpha1.ReflectedResourceSecret
+	case *corev1.Service:
+		return vkv1alpha1.ReflectedResourceService
+	default:
+		return ""
+	}
+}
+
+// containsResource returns whether the given resource is included in the list.
+func containsResource(resources []vkv1alpha1.ReflectedResource, resource vkv1alpha1.ReflectedResource) bool {
+	for _, r := range resources {
+		if r == resource {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+)
+
+const (
+	LocalClusterID  = "local-cluster"
+	RemoteClusterID = "remote-cluster"
+)
+
+func TestPolicy(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Reflection Policy Suite")
+}
+
+var _ = BeforeSuite(func() {
+	forge.Init(LocalClusterID, RemoteClusterID, "local-node", "1.1.1.1")
+})
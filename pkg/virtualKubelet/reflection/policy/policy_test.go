@@ -0,0 +1,205 @@
+// Copyright 2019-2022 The Liqo Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package policy_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1apply "k8s.io/client-go/applyconfigurations/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	vkv1alpha1 "github.com/liqotech/liqo/apis/virtualkubelet/v1alpha1"
+	vkv1alpha1listers "github.com/liqotech/liqo/pkg/client/listers/virtualkubelet/v1alpha1"
+	"github.com/liqotech/liqo/pkg/consts"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/forge"
+	"github.com/liqotech/liqo/pkg/virtualKubelet/reflection/policy"
+)
+
+var _ = Describe("Reflection policies evaluation", func() {
+	var (
+		evaluator *policy.Evaluator
+		policies  []*vkv1alpha1.ReflectionPolicy
+		obj       *corev1.Secret
+		decision  *policy.Decision
+	)
+
+	BeforeEach(func() {
+		policies = nil
+		obj = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "name", Namespace: "namespace", Labels: map[string]string{"foo": "bar"}},
+			Type:       corev1.SecretTypeDockerConfigJson,
+		}
+	})
+
+	JustBeforeEach(func() {
+		indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+		for _, p := range policies {
+			Expect(indexer.Add(p)).To(Succeed())
+		}
+		evaluator = policy.NewEvaluator(vkv1alpha1listers.NewReflectionPolicyLister(indexer))
+		decision = evaluator.Evaluate(obj)
+	})
+
+	ReflectionPolicy := func(name string, clusterIDs []string, rules ...vkv1alpha1.ReflectionRule) *vkv1alpha1.ReflectionPolicy {
+		return &vkv1alpha1.ReflectionPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       vkv1alpha1.ReflectionPolicySpec{ClusterIDs: clusterIDs, Rules: rules},
+		}
+	}
+
+	When("no policy is present", func() {
+		It("should reflect the object unmodified", func() {
+			Expect(decision.Skip).To(BeFalse())
+			meta := &metav1apply.ObjectMetaApplyConfiguration{}
+			meta.WithLabels(map[string]string{"foo": "bar"})
+			decision.MutateObjectMeta(meta)
+			Expect(meta.Labels).To(Equal(map[string]string{"foo": "bar"}))
+			Expect(meta.Annotations).To(BeEmpty())
+		})
+	})
+
+	When("a rule matches the secret type", func() {
+		BeforeEach(func() {
+			policies = append(policies, ReflectionPolicy("policy", nil, vkv1alpha1.ReflectionRule{
+				Resources:   []vkv1alpha1.ReflectedResource{vkv1alpha1.ReflectedResourceSecret},
+				SecretTypes: []corev1.SecretType{corev1.SecretTypeDockerConfigJson},
+				Action:      vkv1alpha1.ReflectionActionSkip,
+			}))
+		})
+
+		It("should skip the object", func() {
+			Expect(decision.Skip).To(BeTrue())
+			Expect(decision.Reason).To(ContainSubstring("policy"))
+		})
+
+		When("the skip reflection annotation is set to false", func() {
+			BeforeEach(func() { obj.Annotations = map[string]string{consts.SkipReflectionAnnotationKey: "false"} })
+			It("should not override the policy", func() {
+				Expect(decision.Skip).To(BeTrue())
+				Expect(decision.Reason).To(ContainSubstring("policy"))
+			})
+		})
+	})
+
+	When("a rule does not match the secret type", func() {
+		BeforeEach(func() {
+			policies = append(policies, ReflectionPolicy("policy", nil, vkv1alpha1.ReflectionRule{
+				SecretTypes: []corev1.SecretType{corev1.SecretTypeTLS},
+				Action:      vkv1alpha1.ReflectionActionSkip,
+			}))
+		})
+
+		It("should reflect the object", func() { Expect(decision.Skip).To(BeFalse()) })
+	})
+
+	When("the policy targets a different remote cluster", func() {
+		BeforeEach(func() {
+			policies = append(policies, ReflectionPolicy("policy", []string{"other-cluster"},
+				vkv1alpha1.ReflectionRule{Action: vkv1alpha1.ReflectionActionSkip}))
+		})
+
+		It("should reflect the object", func() { Expect(decision.Skip).To(BeFalse()) })
+	})
+
+	When("multiple rules match", func() {
+		BeforeEach(func() {
+			policies = append(policies,
+				ReflectionPolicy("second", nil, vkv1alpha1.ReflectionRule{Action: vkv1alpha1.ReflectionActionSkip}),
+				ReflectionPolicy("first", []string{RemoteClusterID}, vkv1alpha1.ReflectionRule{
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"foo": "bar"}},
+					Action:   vkv1alpha1.ReflectionActionReflect, StripDataKeys: []string{"key"},
+					StripLabels: []string{"foo", forge.LiqoOriginClusterIDKey}, SetAnnotations: map[string]string{"new": "value"},
+				}))
+		})
+
+		It("should consider the first matching one", func() {
+			Expect(decision.Skip).To(BeFalse())
+			Expect(decision.Reason).To(ContainSubstring("first"))
+		})
+
+		It("should correctly mutate the object metadata", func() {
+			meta := &metav1apply.ObjectMetaApplyConfiguration{}
+			meta.WithLabels(map[string]string{"foo": "bar", "other": "value"}).WithLabels(forge.ReflectionLabels())
+			decision.MutateObjectMeta(meta)
+			Expect(meta.Labels).ToNot(HaveKey("foo"))
+			Expect(meta.Labels).To(HaveKeyWithValue("other", "value"))
+			Expect(meta.Labels).To(HaveKeyWithValue(forge.LiqoOriginClusterIDKey, LocalClusterID))
+			Expect(meta.Annotations).To(HaveKeyWithValue("new", "value"))
+		})
+
+		It("should correctly strip the data keys", func() {
+			data := map[string][]byte{"key": []byte("foo"), "other": []byte("bar")}
+			decision.StripBinaryData(data)
+			Expect(data).To(HaveLen(1))
+			Expect(data).To(HaveKey("other"))
+		})
+	})
+
+	When("the skip reflection annotation is set to true", func() {
+		BeforeEach(func() { obj.Annotations = map[string]string{consts.SkipReflectionAnnotationKey: "true"} })
+		It("should skip the object", func() { Expect(decision.Skip).To(BeTrue()) })
+	})
+
+	When("the skip reflection annotation is set to false and no policy matches", func() {
+		BeforeEach(func() { obj.Annotations = map[string]string{consts.SkipReflectionAnnotationKey: "false"} })
+		It("should reflect the object", func() { Expect(decision.Skip).To(BeFalse()) })
+	})
+
+	When("the evaluator is nil", func() {
+		It("should honor the skip reflection annotation", func() {
+			obj.Annotations = map[string]string{consts.SkipReflectionAnnotationKey: "true"}
+			Expect((*policy.Evaluator)(nil).Evaluate(obj).Skip).To(BeTrue())
+		})
+	})
+
+	Describe("The AffectedNamespaces function", func() {
+		var (
+			namespaces []string
+			all        bool
+		)
+
+		When("the policy targets a different remote cluster", func() {
+			BeforeEach(func() {
+				namespaces, all = policy.AffectedNamespaces(ReflectionPolicy("policy", []string{"other-cluster"},
+					vkv1alpha1.ReflectionRule{Namespaces: []string{"foo"}}), RemoteClusterID)
+			})
+			It("should return no namespaces", func() {
+				Expect(namespaces).To(BeEmpty())
+				Expect(all).To(BeFalse())
+			})
+		})
+
+		When("all rules select specific namespaces", func() {
+			BeforeEach(func() {
+				namespaces, all = policy.AffectedNamespaces(ReflectionPolicy("policy", nil,
+					vkv1alpha1.ReflectionRule{Namespaces: []string{"foo"}}, vkv1alpha1.ReflectionRule{Namespaces: []string{"bar"}}), RemoteClusterID)
+			})
+			It("should return the selected namespaces", func() {
+				Expect(namespaces).To(ConsistOf("foo", "bar"))
+				Expect(all).To(BeFalse())
+			})
+		})
+
+		When("a rule does not select specific namespaces", func() {
+			BeforeEach(func() {
+				_, all = policy.AffectedNamespaces(ReflectionPolicy("policy", nil,
+					vkv1alpha1.ReflectionRule{Namespaces: []string{"foo"}}, vkv1alpha1.ReflectionRule{}), RemoteClusterID)
+			})
+			It("should return that all namespaces are affected", func() { Expect(all).To(BeTrue()) })
+		})
+	})
+})
@@ -129,6 +129,18 @@ func (npr *NamespacedPodReflector) Handle(ctx context.Context, name string) erro
 	// The local pod does no longer exist. Ensure the shadowpod is absent from the remote cluster.
 	if !localExists {
 		defer tracer.Step("Ensured the absence of the remote object")
+		if shadowExists {
+			sibling, err := npr.isManagedBySibling(ctx, name)
+			if err != nil {
+				klog.Errorf("Failed to check whether local pod %q is scheduled on a sibling virtual node: %v", npr.LocalRef(name), err)
+				return err
+			}
+			if sibling {
+				klog.V(4).Infof("Skipping reflection of local pod %q, as scheduled on a sibling virtual node", npr.LocalRef(name))
+				return nil
+			}
+		}
+
 		if !kerrors.IsNotFound(serr) {
 			klog.V(4).Infof("Deleting remote shadowpod %q, since local pod %q does no longer exist", npr.RemoteRef(name), npr.LocalRef(name))
 			return npr.DeleteRemote(ctx, npr.remoteShadowPodsClient, "ShadowPod", name, shadow.GetUID())
@@ -169,7 +181,8 @@ func (npr *NamespacedPodReflector) Handle(ctx context.Context, name string) erro
 	}
 
 	// Do not offload the pod if it was previously rejected, as new copies should have already been re-created.
-	if local.Status.Phase == corev1.PodFailed && local.Status.Reason == forge.PodOffloadingAbortedReason {
+	if local.Status.Phase == corev1.PodFailed &&
+		(local.Status.Reason == forge.PodOffloadingAbortedReason || local.Status.Reason == forge.PodOffloadingForbiddenReason) {
 		// Ensure the corresponding remote shadowpod is not still present due to transients.
 		if shadowExists && shadow.DeletionTimestamp.IsZero() {
 			defer tracer.Step("Ensured the absence of the remote object")
@@ -205,6 +218,10 @@ func (npr *NamespacedPodReflector) Handle(ctx context.Context, name string) erro
 				klog.Infof("Remote shadowpod %q already exists (local pod: %q)", npr.RemoteRef(name), npr.LocalRef(name))
 				return nil
 			}
+			if kerrors.ReasonForError(err) == forge.PodOffloadingForbiddenReason {
+				klog.Warningf("Remote shadowpod %q rejected by the remote cluster (local pod: %q): %v", npr.RemoteRef(name), npr.LocalRef(name), err)
+				return npr.HandleForbidden(ctx, local, err)
+			}
 			klog.Errorf("Failed to create remote shadowpod %q (local pod: %q): %v", npr.RemoteRef(name), npr.LocalRef(name), err)
 			return err
 		}
@@ -239,6 +256,25 @@ func (npr *NamespacedPodReflector) Handle(ctx context.Context, name string) erro
 	return npr.HandleStatus(ctx, local, remote, info)
 }
 
+// HandleForbidden marks the local pod as rejected, since the remote cluster forbids its offloading (e.g., as violating
+// its pod security constraints). The pod is marked as Failed if it was already running, and Pending otherwise.
+func (npr *NamespacedPodReflector) HandleForbidden(ctx context.Context, local *corev1.Pod, reason error) error {
+	phase := corev1.PodPending
+	if local.Status.Phase != corev1.PodPending || len(local.Status.ContainerStatuses) > 0 {
+		phase = corev1.PodFailed
+	}
+
+	pod := forge.LocalRejectedPod(local, phase, forge.PodOffloadingForbiddenReason)
+	pod.Status.Message = reason.Error()
+	if _, err := npr.localPodsClient.UpdateStatus(ctx, pod, metav1.UpdateOptions{FieldManager: forge.ReflectionFieldManager}); err != nil {
+		klog.Errorf("Failed to mark local pod %q as %v (%v): %v", npr.LocalRef(local.GetName()), phase, forge.PodOffloadingForbiddenReason, err)
+		return err
+	}
+
+	klog.Infof("Pod %q successfully marked as %v (%v)", npr.LocalRef(local.GetName()), phase, forge.PodOffloadingForbiddenReason)
+	return nil
+}
+
 // HandleLabels mutates the local object labels, to mark the pod as offloaded and allow filtering at the informer level.
 func (npr *NamespacedPodReflector) HandleLabels(ctx context.Context, local *corev1.Pod) error {
 	// Forge the mutation to be applied to the local pod.
@@ -534,3 +570,17 @@ func (npr *NamespacedPodReflector) InferAdditionalRestarts(local, remote *corev1
 	// This should never occur, since the containers should match
 	return 0
 }
+
+// isManagedBySibling returns whether the given local pod is scheduled on a sibling virtual node,
+// associated with the same remote cluster, hence it shall not be handled by the current reflector.
+func (npr *NamespacedPodReflector) isManagedBySibling(ctx context.Context, name string) (bool, error) {
+	if len(forge.LiqoSiblingNodeNames) == 0 {
+		return false, nil
+	}
+
+	local, err := npr.localPodsClient.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	return local.Spec.NodeName != forge.LiqoNodeName && forge.IsLiqoNodeForRemoteCluster(local.Spec.NodeName), nil
+}
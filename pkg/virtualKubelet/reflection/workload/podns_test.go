@@ -17,12 +17,14 @@ package workload_test
 import (
 	"errors"
 	"fmt"
+	"net/http"
 	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -247,6 +249,29 @@ var _ = Describe("Namespaced Pod Reflection Tests", func() {
 					})
 				})
 
+				When("the remote object creation is forbidden by the remote cluster", func() {
+					BeforeEach(func() {
+						local.Status.Phase = corev1.PodPending
+						UpdatePod(client, &local)
+
+						liqoClient.(*liqoclientfake.Clientset).PrependReactor("create", "shadowpods",
+							func(action testing.Action) (handled bool, ret runtime.Object, err error) {
+								return true, nil, &kerrors.StatusError{ErrStatus: metav1.Status{
+									Status: metav1.StatusFailure, Code: http.StatusForbidden, Message: "host network namespace shared",
+									Reason: metav1.StatusReason(forge.PodOffloadingForbiddenReason),
+								}}
+							})
+					})
+
+					It("should succeed", func() { Expect(err).ToNot(HaveOccurred()) })
+					It("should mark the local pod as rejected", func() {
+						localAfter := GetPod(client, LocalNamespace, PodName)
+						Expect(localAfter.Status.Phase).To(Equal(corev1.PodPending))
+						Expect(localAfter.Status.Reason).To(Equal(forge.PodOffloadingForbiddenReason))
+						Expect(localAfter.Status.Message).To(ContainSubstring("host network namespace shared"))
+					})
+				})
+
 				When("the local object has already the appropriate offloading label", func() {
 					BeforeEach(func() {
 						shouldDenyPodPatches = true
@@ -15,6 +15,10 @@
 package forge
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -23,19 +27,98 @@ import (
 
 	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
 	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
+	"github.com/liqotech/liqo/pkg/consts"
 	"github.com/liqotech/liqo/pkg/discovery"
 	foreignclusterutils "github.com/liqotech/liqo/pkg/utils/foreignCluster"
+	"github.com/liqotech/liqo/pkg/virtualKubelet"
 	"github.com/liqotech/liqo/pkg/vkMachinery"
 )
 
-// VirtualKubeletDeployment forges the deployment for a virtual-kubelet.
-func VirtualKubeletDeployment(homeCluster, remoteCluster discoveryv1alpha1.ClusterIdentity, vkNamespace, liqoNamespace string,
-	opts *VirtualKubeletOpts, resourceOffer *sharingv1alpha1.ResourceOffer) (*appsv1.Deployment, error) {
-	vkLabels := VirtualKubeletLabels(remoteCluster.ClusterID, opts)
+// PrimaryNodePool returns the node pool whose virtual kubelet is in charge of reflecting all resources, given the currently
+// pinned one (possibly empty). The pinned pool is preserved as long as it is offered, to prevent reflection ownership from
+// moving between virtual kubelets as the other pools appear or disappear. Otherwise, the first pool in sorted order is chosen.
+// An empty string is returned if the ResourceOffer does not specify any node pool.
+func PrimaryNodePool(resourceOffer *sharingv1alpha1.ResourceOffer, pinned string) string {
+	primary := ""
+	for i := range resourceOffer.Spec.NodePools {
+		pool := resourceOffer.Spec.NodePools[i].Name
+		if pool == pinned {
+			return pinned
+		}
+		if primary == "" || virtualKubelet.NodePoolSuffix(pool) < virtualKubelet.NodePoolSuffix(primary) {
+			primary = pool
+		}
+	}
+	return primary
+}
+
+// VirtualKubeletDeployments forges the deployments of the virtual-kubelets associated with a ResourceOffer:
+// one for each node pool, if any, or a single one otherwise. The virtual kubelet of the primary node pool
+// (as returned by PrimaryNodePool) is in charge of reflecting all resources.
+func VirtualKubeletDeployments(homeCluster, remoteCluster discoveryv1alpha1.ClusterIdentity, vkNamespace, liqoNamespace string,
+	opts *VirtualKubeletOpts, resourceOffer *sharingv1alpha1.ResourceOffer, primaryPool string) ([]*appsv1.Deployment, error) {
+	if len(resourceOffer.Spec.NodePools) == 0 {
+		nodeName := virtualKubelet.VirtualNodeName(remoteCluster)
+		return []*appsv1.Deployment{virtualKubeletDeployment(homeCluster, remoteCluster, vkNamespace, liqoNamespace,
+			opts, resourceOffer, vkMachinery.DeploymentName, nodeName, nil, nil)}, nil
+	}
+
+	suffixes := make([]string, 0, len(resourceOffer.Spec.NodePools))
+	pools := make(map[string]string, len(resourceOffer.Spec.NodePools))
+	for i := range resourceOffer.Spec.NodePools {
+		pool := resourceOffer.Spec.NodePools[i].Name
+		suffix := virtualKubelet.NodePoolSuffix(pool)
+		if suffix == "" {
+			return nil, fmt.Errorf("invalid node pool name %q", pool)
+		}
+		if _, found := pools[suffix]; found {
+			return nil, fmt.Errorf("node pool %q conflicts with node pool %q", pool, pools[suffix])
+		}
+		pools[suffix] = pool
+		suffixes = append(suffixes, suffix)
+	}
+	sort.Strings(suffixes)
+
+	if primaryPool == "" || pools[virtualKubelet.NodePoolSuffix(primaryPool)] != primaryPool {
+		return nil, fmt.Errorf("primary node pool %q not found", primaryPool)
+	}
+
+	deployments := make([]*appsv1.Deployment, 0, len(suffixes))
+	for _, suffix := range suffixes {
+		pool := pools[suffix]
+		args := []string{stringifyArgument("--node-pool", pool)}
+		var siblings []string
+		for _, sibling := range suffixes {
+			if sibling != suffix {
+				siblings = append(siblings, virtualKubelet.VirtualNodePoolName(remoteCluster, pools[sibling]))
+			}
+		}
+		if len(siblings) > 0 {
+			args = append(args, stringifyArgument("--sibling-nodes", strings.Join(siblings, ",")))
+		}
+
+		// The virtual kubelet associated with the primary node pool is in charge of reflecting
+		// all resources, while the other ones reflect only the pods scheduled on their node.
+		if pool != primaryPool {
+			args = append(args, "--pod-reflection-only")
+		}
+
+		deployments = append(deployments, virtualKubeletDeployment(homeCluster, remoteCluster, vkNamespace, liqoNamespace,
+			opts, resourceOffer, vkMachinery.DeploymentName+"-"+suffix, virtualKubelet.VirtualNodePoolName(remoteCluster, pool),
+			map[string]string{consts.NodePoolLabel: suffix}, args))
+	}
+	return deployments, nil
+}
+
+// virtualKubeletDeployment forges the deployment for a virtual-kubelet.
+func virtualKubeletDeployment(homeCluster, remoteCluster discoveryv1alpha1.ClusterIdentity, vkNamespace, liqoNamespace string,
+	opts *VirtualKubeletOpts, resourceOffer *sharingv1alpha1.ResourceOffer, name, nodeName string,
+	extraLabels map[string]string, extraArgs []string) *appsv1.Deployment {
+	vkLabels := labels.Merge(VirtualKubeletLabels(remoteCluster.ClusterID, opts), extraLabels)
 	annotations := opts.ExtraAnnotations
 	return &appsv1.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        vkMachinery.DeploymentName,
+			Name:        name,
 			Namespace:   vkNamespace,
 			Labels:      vkLabels,
 			Annotations: annotations,
@@ -49,10 +132,10 @@ func VirtualKubeletDeployment(homeCluster, remoteCluster discoveryv1alpha1.Clust
 					Labels:      vkLabels,
 					Annotations: annotations,
 				},
-				Spec: forgeVKPodSpec(vkNamespace, liqoNamespace, homeCluster, remoteCluster, opts, resourceOffer),
+				Spec: forgeVKPodSpec(vkNamespace, liqoNamespace, homeCluster, remoteCluster, nodeName, opts, resourceOffer, extraArgs),
 			},
 		},
-	}, nil
+	}
 }
 
 // VirtualKubeletLabels forges the labels for a virtual-kubelet.
@@ -22,7 +22,6 @@ import (
 	discoveryv1alpha1 "github.com/liqotech/liqo/apis/discovery/v1alpha1"
 	sharingv1alpha1 "github.com/liqotech/liqo/apis/sharing/v1alpha1"
 	liqoconst "github.com/liqotech/liqo/pkg/consts"
-	"github.com/liqotech/liqo/pkg/virtualKubelet"
 	vk "github.com/liqotech/liqo/pkg/vkMachinery"
 )
 
@@ -111,7 +110,7 @@ func getDeafultStorageClass(storageClasses []sharingv1alpha1.StorageType) sharin
 func forgeVKContainers(
 	vkImage string, homeCluster, remoteCluster discoveryv1alpha1.ClusterIdentity,
 	nodeName, vkNamespace, liqoNamespace string, opts *VirtualKubeletOpts,
-	resourceOffer *sharingv1alpha1.ResourceOffer) []v1.Container {
+	resourceOffer *sharingv1alpha1.ResourceOffer, extraArgs []string) []v1.Container {
 	command := []string{
 		"/usr/bin/virtual-kubelet",
 	}
@@ -139,6 +138,7 @@ func forgeVKContainers(
 	if extraLabels := opts.NodeExtraLabels.StringMap; len(extraLabels) != 0 {
 		args = append(args, stringifyArgument("--node-extra-labels", opts.NodeExtraLabels.String()))
 	}
+	args = append(args, extraArgs...)
 	args = append(args, opts.ExtraArgs...)
 
 	volumeMounts := []v1.VolumeMount{
@@ -176,14 +176,13 @@ func forgeVKContainers(
 
 func forgeVKPodSpec(
 	vkNamespace, liqoNamespace string,
-	homeCluster, remoteCluster discoveryv1alpha1.ClusterIdentity, opts *VirtualKubeletOpts,
-	resourceOffer *sharingv1alpha1.ResourceOffer) v1.PodSpec {
-	nodeName := virtualKubelet.VirtualNodeName(remoteCluster)
+	homeCluster, remoteCluster discoveryv1alpha1.ClusterIdentity, nodeName string, opts *VirtualKubeletOpts,
+	resourceOffer *sharingv1alpha1.ResourceOffer, extraArgs []string) v1.PodSpec {
 	return v1.PodSpec{
 		Volumes:        forgeVKVolumes(),
 		InitContainers: forgeVKInitContainers(nodeName, opts),
 		Containers: forgeVKContainers(opts.ContainerImage, homeCluster, remoteCluster,
-			nodeName, vkNamespace, liqoNamespace, opts, resourceOffer),
+			nodeName, vkNamespace, liqoNamespace, opts, resourceOffer, extraArgs),
 		ServiceAccountName: vk.ServiceAccountName,
 		Affinity:           forgeVKAffinity(),
 	}
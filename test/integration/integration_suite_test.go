@@ -146,7 +146,7 @@ func initNatMappingController() error {
 		MetricsBindAddress: "0",
 	})
 
-	controller, err = tunneloperator.NewNatMappingController(mgr.GetClient(), &readyClustersMutex, readyClusters, iptNetns)
+	controller, err = tunneloperator.NewNatMappingController(mgr.GetClient(), &readyClustersMutex, readyClusters, iptNetns, false)
 	if err != nil {
 		return err
 	}